@@ -23,6 +23,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/sirupsen/logrus"
@@ -38,14 +39,20 @@ const (
 // Collect the fields, to make the aggregation proof
 func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 
+	lastFinalizedL1RollingHash, err := canonicalHash(req.ParentAggregationLastL1RollingHash)
+	if err != nil {
+		return nil, fmt.Errorf("fields collection, parent aggregation last L1 rolling hash: %w", err)
+	}
+
 	var (
 		allL2MessageHashes []string
 		l2MsgBlockOffsets  []bool
 		cf                 = &CollectedFields{
 			L2MsgTreeDepth:                          l2MsgMerkleTreeDepth,
 			ParentAggregationLastBlockTimestamp:     uint(req.ParentAggregationLastBlockTimestamp),
-			LastFinalizedL1RollingHash:              req.ParentAggregationLastL1RollingHash,
+			LastFinalizedL1RollingHash:              lastFinalizedL1RollingHash,
 			LastFinalizedL1RollingHashMessageNumber: uint(req.ParentAggregationLastL1RollingHashMessageNumber),
+			DeferEmulation:                          req.DeferEmulation,
 		}
 	)
 
@@ -67,7 +74,11 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 
 		if i == 0 {
 			cf.LastFinalizedBlockNumber = uint(po.FirstBlockNumber) - 1
-			cf.ParentStateRootHash = po.ParentStateRootHash
+			parentStateRootHash, err := canonicalHash(po.ParentStateRootHash)
+			if err != nil {
+				return nil, fmt.Errorf("fields collection, parent state root hash of %s: %w", execReqFPath, err)
+			}
+			cf.ParentStateRootHash = parentStateRootHash
 		}
 
 		if po.ProverMode == config.ProverModeProofless {
@@ -104,7 +115,7 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 		// Append the proof claim to the list of collected proofs
 		if !cf.IsProoflessJob { // TODO @Tabaie @alexandre.belling proofless jobs will no longer be accepted post PI interconnection
 			cf.InnerCircuitTypes = append(cf.InnerCircuitTypes, pi_interconnection.Execution)
-			pClaim, err := parseProofClaim(po.Proof, po.PublicInput.Hex(), po.VerifyingKeyShaSum)
+			pClaim, err := parseProofClaim(po.Proof, field.Element(po.PublicInput), po.VerifyingKeyShaSum)
 			if err != nil {
 				return nil, fmt.Errorf("could not parse the proof claim for `%v` : %w", fpath, err)
 			}
@@ -118,6 +129,7 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 				FinalBlockTimestamp:    finalBlock.TimeStamp,
 				FinalRollingHash:       cf.L1RollingHash,
 				FinalRollingHashNumber: uint64(cf.L1RollingHashMessageNumber),
+				TracesLimitsDigest:     po.TracesLimitsDigest,
 			}.Decode()
 			if err != nil {
 				return nil, err
@@ -139,6 +151,20 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 			return nil, fmt.Errorf("fields collection, decoding %s, %w", fpath, err)
 		}
 
+		// The hashes are passed by the blob submission and may not be in the
+		// canonical form.
+		var (
+			hashes = [4]*string{&dp.DataParentHash, &dp.PrevShnarf, &dp.ExpectedShnarf, &dp.DataHash}
+			names  = [4]string{"data parent hash", "previous shnarf", "expected shnarf", "data hash"}
+		)
+		for k := range hashes {
+			canonical, err := canonicalHash(*hashes[k])
+			if err != nil {
+				return nil, fmt.Errorf("fields collection, %v of %s: %w", names[k], fpath, err)
+			}
+			*hashes[k] = canonical
+		}
+
 		if i == 0 {
 			cf.DataParentHash = dp.DataParentHash
 			cf.ParentAggregationFinalShnarf = dp.PrevShnarf
@@ -152,7 +178,7 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 		// Append the proof claim to the list of collected proofs
 		if !cf.IsProoflessJob {
 			cf.InnerCircuitTypes = append(cf.InnerCircuitTypes, pi_interconnection.Decompression)
-			pClaim, err := parseProofClaim(dp.DecompressionProof, dp.Debug.PublicInput, dp.VerifyingKeyShaSum)
+			pClaim, err := parseProofClaim(dp.DecompressionProof, field.Element(dp.Debug.PublicInput), dp.VerifyingKeyShaSum)
 			if err != nil {
 				return nil, fmt.Errorf("could not parse the proof claim for `%v` : %w", fpath, err)
 			}
@@ -164,7 +190,7 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 	// If we did not collect the rolling hash, we instead pass the last
 	// finalized one in the collected fields
 	if len(cf.L1RollingHash) == 0 {
-		cf.L1RollingHash = req.ParentAggregationLastL1RollingHash
+		cf.L1RollingHash = cf.LastFinalizedL1RollingHash
 		cf.L1RollingHashMessageNumber = uint(req.ParentAggregationLastL1RollingHashMessageNumber)
 	}
 
@@ -233,9 +259,18 @@ func CraftResponse(cfg *config.Config, cf *CollectedFields) (resp *Response, err
 		pubInputParts,
 	)
 
-	resp.AggregatedVerifierIndex = cfg.Aggregation.VerifierID
 	resp.AggregatedProverVersion = cfg.Version
 
+	if cf.DeferEmulation {
+		resp.Bw6Proof, resp.Bw6CircuitID, err = makeDeferredProof(cfg, cf, resp.AggregatedProofPublicInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prove the aggregation: %w", err)
+		}
+		return resp, nil
+	}
+
+	resp.AggregatedVerifierIndex = cfg.Aggregation.VerifierID
+
 	resp.AggregatedProof, err = makeProof(cfg, cf, resp.AggregatedProofPublicInput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prove the aggregation: %w", err)
@@ -323,9 +358,19 @@ func PackInMiniTrees(l2MsgHashes []string) []string {
 	return res
 }
 
+// canonicalHash returns the canonical form of a 32 bytes hash, see
+// [utils.HexEncodeToString].
+func canonicalHash(s string) (string, error) {
+	b, err := utils.HexDecodeFixed(s, 32)
+	if err != nil {
+		return "", err
+	}
+	return utils.HexEncodeToString(b), nil
+}
+
 func parseProofClaim(
 	proofHexString string,
-	publicInputHexString string,
+	publicInput field.Element,
 	verifyinKeyShasum string,
 ) (*aggregation.ProofClaimAssignment, error) {
 
@@ -341,12 +386,7 @@ func parseProofClaim(
 	// This can potentially panic if the checksum is not a valid one.
 	res := &aggregation.ProofClaimAssignment{
 		VerifyingKeyShasum: types.FullBytes32FromHex(verifyinKeyShasum),
-	}
-
-	// This should be fine because, this field is set by the execution/compression
-	// prover.
-	if _, err := res.PublicInput.SetString(publicInputHexString); err != nil {
-		return nil, fmt.Errorf("the public input could not be parsed from bytes: %x because: %w", publicInputHexString, err)
+		PublicInput:        publicInput,
 	}
 
 	// @alex: the proof need to be (pre)-allocated before being read
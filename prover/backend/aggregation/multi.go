@@ -0,0 +1,190 @@
+package aggregation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	frBn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/emulation"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// MultiRequest asks the prover to wrap the BW6 proofs of several aggregations
+// into a single BN254 proof, so that they can be finalized in a single
+// transaction on L1. The aggregations must have been proven with
+// [Request.DeferEmulation] and cover consecutive block ranges.
+type MultiRequest struct {
+
+	// List of the responses of the aggregation prover to wrap, in the order of
+	// their block ranges.
+	AggregationProofs []string `json:"aggregationProofs"`
+}
+
+// MultiResponse contains the BN254 proof wrapping several aggregations along
+// with the finalization data of each of them.
+type MultiResponse struct {
+
+	// Aggregation proof in hexstring format. Its public inputs are the public
+	// inputs of the wrapped aggregations, in order.
+	AggregatedProof         string `json:"aggregatedProof"`
+	AggregatedProverVersion string `json:"aggregatedProverVersion"`
+	AggregatedVerifierIndex int    `json:"aggregatedVerifierIndex"`
+
+	// The responses of the wrapped aggregations, in order. Their BW6 proofs
+	// are omitted.
+	Aggregations []Response `json:"aggregations"`
+}
+
+// ProveMulti runs the prover wrapping several aggregation proofs into one
+func ProveMulti(cfg *config.Config, req *MultiRequest) (*MultiResponse, error) {
+
+	aggregations := make([]Response, len(req.AggregationProofs))
+	for i, aggFPath := range req.AggregationProofs {
+		fpath := path.Join(cfg.Aggregation.DirTo(), aggFPath)
+		f := files.MustRead(fpath)
+
+		if err := json.NewDecoder(f).Decode(&aggregations[i]); err != nil {
+			return nil, fmt.Errorf("fields collection, decoding %s, %w", fpath, err)
+		}
+	}
+
+	if err := validateMulti(cfg, aggregations); err != nil {
+		return nil, err
+	}
+
+	// The validation guarantees that the number of proofs is supported
+	pos := slices.Index(cfg.Aggregation.EmulationNumProofs, len(aggregations))
+
+	resp := &MultiResponse{
+		AggregatedProverVersion: cfg.Version,
+		AggregatedVerifierIndex: cfg.Aggregation.EmulationVerifierIDs[pos],
+		Aggregations:            aggregations,
+	}
+
+	var err error
+	if resp.AggregatedProof, err = makeMultiProof(cfg, aggregations); err != nil {
+		return nil, fmt.Errorf("failed to prove the multi-aggregation: %w", err)
+	}
+
+	for i := range resp.Aggregations {
+		resp.Aggregations[i].Bw6Proof = ""
+	}
+
+	return resp, nil
+}
+
+// validateMulti checks that the aggregations can be wrapped together. They
+// must all carry a BW6 proof of the current prover version, their number must
+// be supported by one of the multi-emulation circuits and they must cover
+// consecutive block ranges.
+func validateMulti(cfg *config.Config, aggregations []Response) error {
+
+	if cfg.Aggregation.ProverMode == config.ProverModeDev {
+		return errors.New("the multi-aggregation is not supported in dev mode")
+	}
+
+	if !slices.Contains(cfg.Aggregation.EmulationNumProofs, len(aggregations)) {
+		return fmt.Errorf(
+			"no emulation circuit wraps %v aggregations, the supported numbers are %v",
+			len(aggregations), cfg.Aggregation.EmulationNumProofs,
+		)
+	}
+
+	for i := range aggregations {
+
+		agg := &aggregations[i]
+
+		if agg.Bw6Proof == "" {
+			return fmt.Errorf("aggregation #%v (blocks %v-%v) has no BW6 proof, its emulation was not deferred", i, agg.LastFinalizedBlockNumber+1, agg.FinalBlockNumber)
+		}
+
+		if agg.AggregatedProverVersion != cfg.Version {
+			return fmt.Errorf("aggregation #%v was proven by version %v, expected %v", i, agg.AggregatedProverVersion, cfg.Version)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := &aggregations[i-1]
+
+		if agg.LastFinalizedBlockNumber != prev.FinalBlockNumber {
+			return fmt.Errorf(
+				"aggregation #%v starts after block %v but aggregation #%v ends at block %v",
+				i, agg.LastFinalizedBlockNumber, i-1, prev.FinalBlockNumber,
+			)
+		}
+
+		if agg.ParentAggregationFinalShnarf != prev.FinalShnarf {
+			return fmt.Errorf(
+				"the parent shnarf of aggregation #%v is %v but the final shnarf of aggregation #%v is %v",
+				i, agg.ParentAggregationFinalShnarf, i-1, prev.FinalShnarf,
+			)
+		}
+
+		if agg.ParentAggregationLastBlockTimestamp != prev.FinalTimestamp {
+			return fmt.Errorf(
+				"the parent timestamp of aggregation #%v is %v but the final timestamp of aggregation #%v is %v",
+				i, agg.ParentAggregationLastBlockTimestamp, i-1, prev.FinalTimestamp,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Run the multi-emulation prover over the BW6 proofs of the aggregations
+func makeMultiProof(cfg *config.Config, aggregations []Response) (proof string, err error) {
+
+	var (
+		circuitIDs   = make([]int, len(aggregations))
+		proofsBW6    = make([]plonk.Proof, len(aggregations))
+		publicInputs = make([]frBn254.Element, len(aggregations))
+	)
+
+	for i := range aggregations {
+
+		circuitIDs[i] = aggregations[i].Bw6CircuitID
+
+		proofBytes, err := utils.HexDecodeString(aggregations[i].Bw6Proof)
+		if err != nil {
+			return "", fmt.Errorf("could not parse the BW6 proof #%v as an hex string: %w", i, err)
+		}
+
+		proofsBW6[i] = plonk.NewProof(ecc.BW6_761)
+		if _, err := proofsBW6[i].ReadFrom(bytes.NewReader(proofBytes)); err != nil {
+			return "", fmt.Errorf("could not parse the BW6 proof #%v from bytes: %w", i, err)
+		}
+
+		if _, err := publicInputs[i].SetString(aggregations[i].AggregatedProofPublicInput); err != nil {
+			return "", fmt.Errorf("could not parse the public input #%v: %w", i, err)
+		}
+	}
+
+	c := circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.EmulationCircuitID), len(aggregations)))
+	logrus.Infof("reading the %v setup from disk...", c)
+
+	setup, err := circuits.LoadSetup(cfg, c)
+	if err != nil {
+		return "", fmt.Errorf("could not read the setup of %v: %w", c, err)
+	}
+
+	logrus.Infof("running the prover for %v", c)
+
+	proofBn254, err := emulation.MakeMultiProof(&setup, circuitIDs, proofsBW6, publicInputs)
+	if err != nil {
+		return "", fmt.Errorf("(for Bn254) gnark's plonk Prover failed with error: %w", err)
+	}
+
+	return circuits.SerializeProofSolidityBn254(proofBn254), nil
+}
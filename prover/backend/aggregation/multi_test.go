@@ -0,0 +1,95 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMulti(t *testing.T) {
+
+	cfg := &config.Config{}
+	cfg.Version = "1.2.3"
+	cfg.Aggregation.ProverMode = config.ProverModeFull
+	cfg.Aggregation.EmulationNumProofs = []int{2, 4}
+	cfg.Aggregation.EmulationVerifierIDs = []int{2, 3}
+
+	// consecutive returns n aggregations covering consecutive block ranges
+	consecutive := func(n int) []Response {
+		res := make([]Response, n)
+		for i := range res {
+			res[i] = Response{
+				Bw6Proof:                            "0x01",
+				AggregatedProverVersion:             cfg.Version,
+				LastFinalizedBlockNumber:            uint(10 * i),
+				FinalBlockNumber:                    uint(10 * (i + 1)),
+				ParentAggregationLastBlockTimestamp: uint(100 * i),
+				FinalTimestamp:                      uint(100 * (i + 1)),
+				ParentAggregationFinalShnarf:        string(rune('a' + i)),
+				FinalShnarf:                         string(rune('a' + i + 1)),
+			}
+		}
+		return res
+	}
+
+	assert.NoError(t, validateMulti(cfg, consecutive(2)))
+	assert.NoError(t, validateMulti(cfg, consecutive(4)))
+
+	testCases := []struct {
+		Explainer    string
+		Aggregations []Response
+		Mutate       func(aggs []Response)
+	}{
+		{
+			Explainer:    "no circuit for a single aggregation",
+			Aggregations: consecutive(1),
+		},
+		{
+			Explainer:    "no circuit for 3 aggregations",
+			Aggregations: consecutive(3),
+		},
+		{
+			Explainer:    "the emulation was not deferred",
+			Aggregations: consecutive(2),
+			Mutate:       func(aggs []Response) { aggs[1].Bw6Proof = "" },
+		},
+		{
+			Explainer:    "proven by another version",
+			Aggregations: consecutive(2),
+			Mutate:       func(aggs []Response) { aggs[0].AggregatedProverVersion = "1.2.2" },
+		},
+		{
+			Explainer:    "gap in the block ranges",
+			Aggregations: consecutive(2),
+			Mutate:       func(aggs []Response) { aggs[1].LastFinalizedBlockNumber++ },
+		},
+		{
+			Explainer:    "shnarf mismatch",
+			Aggregations: consecutive(4),
+			Mutate:       func(aggs []Response) { aggs[3].ParentAggregationFinalShnarf = "z" },
+		},
+		{
+			Explainer:    "timestamp mismatch",
+			Aggregations: consecutive(2),
+			Mutate:       func(aggs []Response) { aggs[0].FinalTimestamp-- },
+		},
+		{
+			Explainer:    "out of order",
+			Aggregations: consecutive(2),
+			Mutate:       func(aggs []Response) { aggs[0], aggs[1] = aggs[1], aggs[0] },
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Explainer, func(t *testing.T) {
+			if c.Mutate != nil {
+				c.Mutate(c.Aggregations)
+			}
+			assert.Error(t, validateMulti(cfg, c.Aggregations))
+		})
+	}
+
+	cfg.Aggregation.ProverMode = config.ProverModeDev
+	assert.Error(t, validateMulti(cfg, consecutive(2)))
+}
@@ -1,9 +1,11 @@
 package aggregation
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"path/filepath"
+	"slices"
 
 	frBls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark/backend/witness"
@@ -19,7 +21,6 @@ import (
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/sirupsen/logrus"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -28,6 +29,16 @@ import (
 	frBn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 )
 
+// proofClaimCacheSize bounds the number of emulated inner proofs kept by
+// [proofClaimCache]. It covers several retries of the largest aggregations.
+const proofClaimCacheSize = 512
+
+// proofClaimCache holds the emulated inner proofs of the aggregation jobs that
+// ran in the current process. When the same inner proof is part of several
+// jobs, for instance when the coordinator retries an aggregation with
+// overlapping inner proofs, it is only emulated once.
+var proofClaimCache = aggregation.NewClaimCache(proofClaimCacheSize)
+
 func Prove(cfg *config.Config, req *Request) (*Response, error) {
 	cf, err := collectFields(cfg, req)
 	if err != nil {
@@ -67,9 +78,37 @@ func makeProof(
 	return circuits.SerializeProofSolidityBn254(proofBn254), nil
 }
 
+// Run the prover for the aggregation up to the BW6 proof, without wrapping it
+// into a BN254 proof. Returns the BW6 proof in hexstring format and the
+// position of its verifying key among the ones accepted by the emulation
+// circuits.
+func makeDeferredProof(
+	cfg *config.Config,
+	cf *CollectedFields,
+	publicInput string,
+) (proof string, circuitID int, err error) {
+
+	if cfg.Aggregation.ProverMode == config.ProverModeDev {
+		// The dev mode does not generate BW6 proofs
+		return "", 0, errors.New("the emulation cannot be deferred in dev mode")
+	}
+
+	piProof, piPublicWitness, err := makePiProof(cfg, cf)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not create the public input proof: %w", err)
+	}
+
+	proofBW6, circuitID, err := makeBw6Proof(cfg, cf, piProof, piPublicWitness, publicInput)
+	if err != nil {
+		return "", 0, fmt.Errorf("error when running the BW6 proof: %w", err)
+	}
+
+	return circuits.SerializeProofRaw(proofBW6), circuitID, nil
+}
+
 func makePiProof(cfg *config.Config, cf *CollectedFields) (plonk.Proof, witness.Witness, error) {
 
-	c, err := pi_interconnection.Compile(cfg.PublicInputInterconnection, pi_interconnection.WizardCompilationParameters()...)
+	c, err := pi_interconnection.Compile(pi_interconnection.PublicInputConfig(cfg), pi_interconnection.WizardCompilationParameters()...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not create the public-input circuit: %w", err)
 	}
@@ -107,7 +146,11 @@ func makePiProof(cfg *config.Config, cf *CollectedFields) (plonk.Proof, witness.
 		return nil, nil, fmt.Errorf("could not extract interconnection circuit public witness: %w", err)
 	}
 
-	proof, err := circuits.ProveCheck(&setup, &assignment)
+	proof, err := circuits.ProveCheck(
+		&setup,
+		&assignment,
+		circuits.SolverOptions(cfg, circuits.PublicInputInterconnectionCircuitID)...,
+	)
 
 	return proof, w, err
 }
@@ -133,7 +176,7 @@ func makeDummyProof(cfg *config.Config, input string, circID circuits.MockCircui
 
 	// We encode the input ourselves so we can trust that the decoding will be
 	// successful and the error can safely be ignored.
-	xBytes, _ = hexutil.Decode(input)
+	xBytes, _ = utils.HexDecodeString(input)
 	x.SetBytes(xBytes)
 
 	return dummy.MakeProof(&setup, x, circID)
@@ -155,11 +198,11 @@ func makeBw6Proof(
 		biggestAvailable            = 0
 		bestSize                    = math.MaxInt
 		bestSetupPos                = -1
-		bestAllowedVkForAggregation []string
+		bestAllowedVkForAggregation allowedVkForAggregation
 	)
 
 	// first we discover available setups
-	for _, maxNbProofs := range cfg.Aggregation.NumProofs {
+	for setupPos, maxNbProofs := range cfg.Aggregation.NumProofs {
 		biggestAvailable = max(biggestAvailable, maxNbProofs)
 
 		// That's the quickest reject condition we have
@@ -175,20 +218,21 @@ func makeBw6Proof(
 		if err != nil {
 			return nil, 0, fmt.Errorf("could not read the manifest for circuit %v: %w", circuitID, err)
 		}
-		allowedVkForAggregation, err := manifest.GetStringArray("allowedVkForAggregationDigests")
+		allowedVks, err := readAllowedVkForAggregation(manifest)
 		if err != nil {
-			return nil, 0, fmt.Errorf("could not read the allowedVkForAggregationDigests: %w", err)
+			return nil, 0, fmt.Errorf("could not read the allowed verifying keys of circuit %v: %w", circuitID, err)
 		}
 
 		// This reject condition may take longer
-		if !doesBw6CircuitSupportVKeys(allowedVkForAggregation, cf.ProofClaims) {
+		if !doesBw6CircuitSupportVKeys(allowedVks, cf.ProofClaims) {
 			logrus.Infof("skipping setup with %v proofs because it does not support the required verifying keys", maxNbProofs)
 			continue
 		}
 
 		if maxNbProofs <= bestSize {
 			bestSize = maxNbProofs
-			bestAllowedVkForAggregation = allowedVkForAggregation
+			bestSetupPos = setupPos
+			bestAllowedVkForAggregation = allowedVks
 		}
 	}
 
@@ -235,11 +279,31 @@ func makeBw6Proof(
 		ActualIndexes: pi_interconnection.InnerCircuitTypesToIndexes(&cfg.PublicInputInterconnection, cf.InnerCircuitTypes),
 	}
 
+	innerVks, err := readInnerVerifyingKeys(cfg)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read the verifying keys of the inner circuits: %w", err)
+	}
+
 	logrus.Infof("running the BW6 prover")
-	proofBW6, err := aggregation.MakeProof(&setup, bestSize, cf.ProofClaims, piInfo, piBW6)
+	proofBW6, err := aggregation.MakeProof(
+		&setup,
+		bestSize,
+		cf.ProofClaims,
+		piInfo,
+		piBW6,
+		aggregation.ClaimPreparation{
+			Cache:         proofClaimCache,
+			VerifyingKeys: innerVks,
+			NbWorkers:     cfg.Aggregation.InnerProofWorkers,
+		},
+		circuits.SolverOptions(cfg, c)...,
+	)
 	if err != nil {
 		return nil, 0, fmt.Errorf("could not create BW6 proof: %w", err)
 	}
+
+	hits, misses := proofClaimCache.Stats()
+	logrus.Infof("proof claim cache: %v hits, %v misses, %v entries", hits, misses, proofClaimCache.Len())
 	return proofBW6, bestSetupPos, nil
 }
 
@@ -275,67 +339,150 @@ func makeBn254Proof(
 
 }
 
-// This function is used to detect if a a BW6 circuit is compatible with a list
-// proof's verifier keys. Namely, it takes the list of supported keys, parse them
-// as hex-bytes-32 and check that all the proof claims verifier keys are included
-// in the list of supported verifier keys.
-func doesBw6CircuitSupportVKeys(supportedVkeys []string, proofClaims []aggregation.ProofClaimAssignment) bool {
-	// Parse the required verifying keys into a list of bytes32
-	suppBytes32 := make([]types.FullBytes32, len(supportedVkeys))
-	for i := range suppBytes32 {
-		suppBytes32[i] = types.FullBytes32FromHex(supportedVkeys[i])
+// allowedVkForAggregation collects the digests of the verifying keys that an
+// aggregation circuit accepts, for each of the prover versions it supports.
+// The position of a digest in its list is the circuit ID to assign to the
+// proof claims using the corresponding verifying key.
+type allowedVkForAggregation struct {
+	Current  []types.FullBytes32
+	Previous []types.FullBytes32
+}
+
+// readAllowedVkForAggregation reads the digests of the allowed verifying keys
+// from the manifest of an aggregation circuit. The digests of the previous
+// prover version are only present in the manifest if the circuit was set up
+// during a prover upgrade window and are treated as optional.
+func readAllowedVkForAggregation(manifest *circuits.SetupManifest) (allowedVkForAggregation, error) {
+
+	var res allowedVkForAggregation
+
+	current, err := manifest.GetStringArray("allowedVkForAggregationDigests")
+	if err != nil {
+		return res, fmt.Errorf("could not read the allowedVkForAggregationDigests: %w", err)
 	}
+	res.Current = parseVkDigests(current)
 
-	// The list are not expected to be very big therefore, we anticipate that
-	// relying on a hashmap is likely not worth the effort.
-	for k := range proofClaims {
-		found := false
-		requiredVKey := proofClaims[k].VerifyingKeyShasum
-		for i := range suppBytes32 {
-			found = found || (suppBytes32[i] == requiredVKey)
-		}
-		if !found {
-			return false
+	if _, ok := manifest.ExtraFlags["previousAllowedVkForAggregationDigests"]; ok {
+		previous, err := manifest.GetStringArray("previousAllowedVkForAggregationDigests")
+		if err != nil {
+			return res, fmt.Errorf("could not read the previousAllowedVkForAggregationDigests: %w", err)
 		}
+		res.Previous = parseVkDigests(previous)
 	}
 
-	return true
+	return res, nil
 }
 
-// This function assigns circuits ID to the input proof claims based on the list
-// of verifying keys that are supported by the circuit. This function mutates the
-// proofClaims parameter. It will panic if one of the claim's verifying key is
-// missing from the supportedVkeys. Therefore, this function should only be
-// called after `doesBW6CircuitSupportsVKeys` has been called and has returned
-// true.
-func assignCircuitIDToProofClaims(supportedVkeys []string, proofClaims []aggregation.ProofClaimAssignment) {
-
-	// Parse the required verifying keys into a list of bytes32
-	suppBytes32 := make([]types.FullBytes32, len(supportedVkeys))
-	for i := range suppBytes32 {
-		suppBytes32[i] = types.FullBytes32FromHex(supportedVkeys[i])
+// parseVkDigests parses a list of hex-encoded verifying key digests
+func parseVkDigests(digests []string) []types.FullBytes32 {
+	res := make([]types.FullBytes32, len(digests))
+	for i := range res {
+		res[i] = types.FullBytes32FromHex(digests[i])
 	}
+	return res
+}
 
+// lookup returns the prover version and the circuit ID corresponding to a
+// verifying key digest. The keys of the current version take precedence over
+// the ones of the previous version. The last returned value is false if the
+// verifying key is not supported.
+func (a *allowedVkForAggregation) lookup(vkDigest types.FullBytes32) (proverVersion, circuitID int, found bool) {
 	// The list are not expected to be very big therefore, we anticipate that
 	// relying on a hashmap is likely not worth the effort.
-	for k := range proofClaims {
-		found := false
-		for i := range suppBytes32 {
-			isThisOne := suppBytes32[i] == proofClaims[k].VerifyingKeyShasum
-			if isThisOne {
-				proofClaims[k].CircuitID = i
-				found = true
-				break
+	if i := slices.Index(a.Current, vkDigest); i >= 0 {
+		return aggregation.CurrentProverVersion, i, true
+	}
+	if i := slices.Index(a.Previous, vkDigest); i >= 0 {
+		return aggregation.PreviousProverVersion, i, true
+	}
+	return 0, 0, false
+}
+
+// readInnerVerifyingKeys reads the verifying keys of the inner circuits that
+// the aggregation accepts, for the current and the previous prover versions,
+// and indexes them by digest. They are used to natively verify the inner
+// proofs before emulating them. The setups of the dummy circuits are not
+// stored, so their proofs are only verified by the aggregation circuit.
+func readInnerVerifyingKeys(cfg *config.Config) (map[types.FullBytes32]plonk.VerifyingKey, error) {
+
+	res := make(map[types.FullBytes32]plonk.VerifyingKey)
+
+	read := func(version string, allowedInputs []string) error {
+		for _, allowedInput := range allowedInputs {
+
+			info, registered := circuits.Lookup(circuits.CircuitID(allowedInput))
+			if registered && info.Dummy {
+				continue
 			}
+
+			curve := ecc.BLS12_377
+			if registered {
+				curve = info.Curve
+			}
+
+			vkPath := filepath.Join(cfg.PathForSetupOfVersion(version, allowedInput), config.VerifyingKeyFileName)
+			vk := plonk.NewVerifyingKey(curve)
+			if err := circuits.ReadVerifyingKey(vkPath, vk); err != nil {
+				return fmt.Errorf("could not read the verifying key of circuit %v (version %v): %w", allowedInput, version, err)
+			}
+
+			digest, err := circuits.VerifyingKeyChecksum(vk)
+			if err != nil {
+				return fmt.Errorf("could not compute the digest of the verifying key of circuit %v (version %v): %w", allowedInput, version, err)
+			}
+			res[types.FullBytes32FromHex(digest)] = vk
 		}
+		return nil
+	}
+
+	if err := read(cfg.Version, cfg.Aggregation.AllowedInputs); err != nil {
+		return nil, err
+	}
+	if cfg.Aggregation.PreviousVersion != "" {
+		if err := read(cfg.Aggregation.PreviousVersion, cfg.Aggregation.PreviousAllowedInputs); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// This function is used to detect if a a BW6 circuit is compatible with a list
+// proof's verifier keys. Namely, it checks that all the proof claims verifier
+// keys are included in the list of supported verifier keys of either the
+// current or the previous prover version.
+func doesBw6CircuitSupportVKeys(supportedVkeys allowedVkForAggregation, proofClaims []aggregation.ProofClaimAssignment) bool {
+	for k := range proofClaims {
+		if _, _, found := supportedVkeys.lookup(proofClaims[k].VerifyingKeyShasum); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// This function assigns circuits ID and prover versions to the input proof
+// claims based on the lists of verifying keys that are supported by the
+// circuit. This function mutates the proofClaims parameter. It will panic if
+// one of the claim's verifying key is missing from the supportedVkeys.
+// Therefore, this function should only be called after
+// `doesBW6CircuitSupportsVKeys` has been called and has returned true.
+func assignCircuitIDToProofClaims(supportedVkeys allowedVkForAggregation, proofClaims []aggregation.ProofClaimAssignment) {
+	for k := range proofClaims {
+		version, circuitID, found := supportedVkeys.lookup(proofClaims[k].VerifyingKeyShasum)
 		if !found {
 			// Here, we panic because we were supposed to have run the above
 			// `doesBw6CircuitSupportVKeys` before calling the current function.
 			utils.Panic(
-				"proof %v requires vkey %v, which was not found in the list %v",
-				k, proofClaims[k].VerifyingKeyShasum.Hex(), supportedVkeys,
+				"proof %v requires vkey %v, which was not found in the lists %v (current) and %v (previous)",
+				k, proofClaims[k].VerifyingKeyShasum.Hex(), supportedVkeys.Current, supportedVkeys.Previous,
 			)
 		}
 
+		if version == aggregation.PreviousProverVersion {
+			logrus.Infof("proof %v was generated by the previous prover version (circuit ID %v)", k, circuitID)
+		}
+
+		proofClaims[k].ProverVersion = version
+		proofClaims[k].CircuitID = circuitID
 	}
 }
@@ -0,0 +1,43 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/circuits/aggregation"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignCircuitIDToProofClaimsVersions(t *testing.T) {
+
+	var (
+		vkA, vkB, vkC, vkD types.FullBytes32
+	)
+	vkA[0], vkB[0], vkC[0], vkD[0] = 0xa, 0xb, 0xc, 0xd
+
+	allowed := allowedVkForAggregation{
+		Current:  []types.FullBytes32{vkA, vkB},
+		Previous: []types.FullBytes32{vkC, vkA},
+	}
+
+	claims := []aggregation.ProofClaimAssignment{
+		{VerifyingKeyShasum: vkB},
+		{VerifyingKeyShasum: vkC},
+		{VerifyingKeyShasum: vkA},
+	}
+
+	assert.True(t, doesBw6CircuitSupportVKeys(allowed, claims))
+	assignCircuitIDToProofClaims(allowed, claims)
+
+	assert.Equal(t, aggregation.CurrentProverVersion, claims[0].ProverVersion)
+	assert.Equal(t, 1, claims[0].CircuitID)
+	assert.Equal(t, aggregation.PreviousProverVersion, claims[1].ProverVersion)
+	assert.Equal(t, 0, claims[1].CircuitID)
+	// the current version takes precedence when a key belongs to both sets
+	assert.Equal(t, aggregation.CurrentProverVersion, claims[2].ProverVersion)
+	assert.Equal(t, 0, claims[2].CircuitID)
+
+	claims = append(claims, aggregation.ProofClaimAssignment{VerifyingKeyShasum: vkD})
+	assert.False(t, doesBw6CircuitSupportVKeys(allowed, claims))
+	assert.False(t, doesBw6CircuitSupportVKeys(allowedVkForAggregation{Current: allowed.Current}, claims[1:2]))
+}
@@ -33,6 +33,12 @@ type Request struct {
 	// this field.
 	ParentAggregationLastL1RollingHash              string `json:"parentAggregationLastL1RollingHash"`
 	ParentAggregationLastL1RollingHashMessageNumber int    `json:"parentAggregationLastL1RollingHashMessageNumber"`
+
+	// DeferEmulation asks the prover to stop after the BW6 aggregation proof
+	// instead of wrapping it into a BN254 proof. The BW6 proof is returned in
+	// the response so that it can later be wrapped along with the proofs of
+	// the following aggregations, see [MultiRequest].
+	DeferEmulation bool `json:"deferEmulation,omitempty"`
 }
 
 // This struct contains a collection of fields that are to be extracted from the
@@ -97,6 +103,10 @@ type CollectedFields struct {
 	// response is to be written in a dedicated folder.
 	IsProoflessJob bool
 
+	// DeferEmulation marks that the BW6 proof is not to be wrapped into a
+	// BN254 proof. See [Request.DeferEmulation].
+	DeferEmulation bool
+
 	// The proof claims for the execution prover
 	ProofClaims []aggregation.ProofClaimAssignment
 
@@ -33,6 +33,13 @@ type Response struct {
 	// Modulo reduced public input to be used to verify the proof.
 	AggregatedProofPublicInput string `json:"aggregatedProofPublicInput"`
 
+	// Bw6Proof is the BW6 aggregation proof, in hexstring format. It is only
+	// set if the emulation was deferred, in which case AggregatedProof is
+	// empty. Bw6CircuitID is the position of the verifying key of the proof in
+	// the list of the keys accepted by the emulation circuits.
+	Bw6Proof     string `json:"bw6Proof,omitempty"`
+	Bw6CircuitID int    `json:"bw6CircuitID,omitempty"`
+
 	// Parent data hash and the list of data hashes to be finalized
 	DataHashes     []string `json:"dataHashes"`
 	DataParentHash string   `json:"dataParentHash"`
@@ -0,0 +1,344 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testChunkSize = 1 << 10
+	testToken     = "test-token"
+)
+
+// flakyServer serves the volumes and fails the requests matching failOn with
+// a 503 status as long as failures is positive.
+type flakyServer struct {
+	handler  http.Handler
+	failOn   string
+	failures atomic.Int32
+	// counts the requests by method
+	gets, puts atomic.Int32
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.failOn != "" && strings.HasSuffix(r.URL.Path, f.failOn) && f.failures.Add(-1) >= 0 {
+		http.Error(w, "flaky network", http.StatusServiceUnavailable)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		f.gets.Add(1)
+	case http.MethodPut:
+		f.puts.Add(1)
+	}
+	f.handler.ServeHTTP(w, r)
+}
+
+// newTestServer returns a server whose volumes "execution/requests" and
+// "execution/responses", the only one accepting uploads, share the returned
+// directory.
+func newTestServer(t *testing.T, failOn string, failures int32) (*flakyServer, *Client, string) {
+	dir := t.TempDir()
+	volumes := map[string]Volume{
+		"execution/requests":  {Dir: dir},
+		"execution/responses": {Dir: dir, Uploads: true},
+	}
+	f := &flakyServer{
+		handler: NewServer(volumes, ServerOptions{ChunkSize: testChunkSize, Token: testToken}).Handler(),
+		failOn:  failOn,
+	}
+	f.failures.Store(failures)
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, &Client{BaseURL: srv.URL, Token: testToken, ChunkSize: testChunkSize}, dir
+}
+
+func randomFile(t *testing.T, dir, name string, size int) []byte {
+	b := make([]byte, size)
+	rng := rand.New(rand.NewPCG(0, uint64(size)))
+	for i := range b {
+		b[i] = byte(rng.Uint32())
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), b, 0600))
+	return b
+}
+
+func TestManifest(t *testing.T) {
+
+	dir := t.TempDir()
+	randomFile(t, dir, "a.json", 5*testChunkSize+3)
+
+	m, err := ManifestOf(filepath.Join(dir, "a.json"), testChunkSize)
+	require.NoError(t, err)
+	require.NoError(t, m.Validate())
+	assert.Len(t, m.Chunks, 6)
+	assert.Equal(t, int64(3), m.ChunkLen(5))
+
+	m.Chunks = m.Chunks[1:]
+	assert.Error(t, m.Validate())
+
+	for _, name := range []string{"", ".hidden", "../a.json", "a/b.json"} {
+		assert.Error(t, ValidateName(name), name)
+	}
+}
+
+func TestDownloadResumes(t *testing.T) {
+
+	// The chunk 3 fails twice and the client does not retry: the first two
+	// attempts fail and the third one completes the download.
+	f, client, dir := newTestServer(t, "/chunks/3", 2)
+	content := randomFile(t, dir, "1-2-getZkProof.json", 10*testChunkSize+17)
+
+	var (
+		ctx = context.Background()
+		dst = filepath.Join(t.TempDir(), "out.json")
+	)
+
+	require.Error(t, client.Download(ctx, "execution", "requests", "1-2-getZkProof.json", dst))
+	assert.NoFileExists(t, dst)
+	nbGets := f.gets.Load()
+
+	require.Error(t, client.Download(ctx, "execution", "requests", "1-2-getZkProof.json", dst))
+	// only the manifest was fetched again, the chunks 0 to 2 were kept
+	assert.Equal(t, nbGets+1, f.gets.Load())
+
+	require.NoError(t, client.Download(ctx, "execution", "requests", "1-2-getZkProof.json", dst))
+	got, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+	assert.NoDirExists(t, filepath.Join(filepath.Dir(dst), ".out.json.parts"))
+
+	t.Run("retries", func(t *testing.T) {
+		f.failures.Store(2)
+		client := *client
+		client.Retries, client.RetryDelay = 2, 1
+		require.NoError(t, client.Download(ctx, "execution", "requests", "1-2-getZkProof.json", dst))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var se *StatusError
+		err := client.Download(ctx, "execution", "requests", "missing.json", dst)
+		require.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusNotFound, se.Code)
+
+		err = client.Download(ctx, "aggregation", "requests", "1-2-getZkProof.json", dst)
+		require.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusNotFound, se.Code)
+	})
+}
+
+func TestUploadResumes(t *testing.T) {
+
+	f, client, dir := newTestServer(t, "/4", 1)
+
+	var (
+		ctx     = context.Background()
+		src     = t.TempDir()
+		content = randomFile(t, src, "local.json", 8*testChunkSize)
+		name    = "3-4-getZkProof.json"
+	)
+
+	require.Error(t, client.Upload(ctx, filepath.Join(src, "local.json"), "execution", "responses", name))
+	assert.NoFileExists(t, filepath.Join(dir, name))
+	assert.Equal(t, int32(4), f.puts.Load())
+
+	// The chunks 0 to 3 are not sent again
+	require.NoError(t, client.Upload(ctx, filepath.Join(src, "local.json"), "execution", "responses", name))
+	assert.Equal(t, int32(8), f.puts.Load())
+
+	got, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	entries, err := os.ReadDir(filepath.Join(dir, uploadsDir))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the staged chunks must be removed")
+}
+
+func TestUploadRejectsCorruptedChunks(t *testing.T) {
+
+	_, client, dir := newTestServer(t, "", 0)
+
+	var (
+		ctx = context.Background()
+		src = t.TempDir()
+	)
+
+	randomFile(t, src, "local.json", 2*testChunkSize)
+	m, err := ManifestOf(filepath.Join(src, "local.json"), testChunkSize)
+	require.NoError(t, err)
+	m.Name = "5-6-getZkProof.json"
+
+	body, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	var status UploadStatus
+	require.NoError(t, client.do(ctx, http.MethodPost, client.url("execution", "responses", m.Name, "uploads"), body, &status))
+	assert.Equal(t, []int{0, 1}, status.Missing)
+
+	var se *StatusError
+	err = client.do(ctx, http.MethodPut, client.url("execution", "responses", m.Name, "uploads", m.Sha256, "0"), bytes.Repeat([]byte{1}, testChunkSize), nil)
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusUnprocessableEntity, se.Code)
+
+	err = client.do(ctx, http.MethodPost, client.url("execution", "responses", m.Name, "uploads", m.Sha256, "commit"), nil, nil)
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusConflict, se.Code)
+	assert.NoFileExists(t, filepath.Join(dir, m.Name))
+}
+
+func TestServerRequiresToken(t *testing.T) {
+
+	_, client, dir := newTestServer(t, "", 0)
+	randomFile(t, dir, "1-2-getZkProof.json", testChunkSize)
+
+	var (
+		ctx = context.Background()
+		dst = filepath.Join(t.TempDir(), "out.json")
+		se  *StatusError
+	)
+
+	for _, token := range []string{"", "wrong-token", testToken + "x"} {
+		client := *client
+		client.Token = token
+		err := client.Download(ctx, "execution", "requests", "1-2-getZkProof.json", dst)
+		require.ErrorAs(t, err, &se, token)
+		assert.Equal(t, http.StatusUnauthorized, se.Code, token)
+	}
+
+	// A server without a token rejects every request
+	srv := httptest.NewServer(NewServer(map[string]Volume{"execution/requests": {Dir: dir}}, ServerOptions{}).Handler())
+	t.Cleanup(srv.Close)
+	noToken := &Client{BaseURL: srv.URL}
+	err := noToken.Download(ctx, "execution", "requests", "1-2-getZkProof.json", dst)
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusUnauthorized, se.Code)
+}
+
+func TestUploadRestrictions(t *testing.T) {
+
+	_, client, dir := newTestServer(t, "", 0)
+
+	var (
+		ctx = context.Background()
+		src = t.TempDir()
+		se  *StatusError
+	)
+
+	randomFile(t, src, "local.json", 2*testChunkSize)
+
+	// The requests may not be uploaded
+	err := client.Upload(ctx, filepath.Join(src, "local.json"), "execution", "requests", "1-2-getZkProof.json")
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusForbidden, se.Code)
+	assert.NoFileExists(t, filepath.Join(dir, "1-2-getZkProof.json"))
+
+	// The responses may not be replaced
+	existing := randomFile(t, dir, "1-2-getZkProof.json", 3*testChunkSize)
+	err = client.Upload(ctx, filepath.Join(src, "local.json"), "execution", "responses", "1-2-getZkProof.json")
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusConflict, se.Code)
+
+	// even when the file appears after the upload started
+	m, err := ManifestOf(filepath.Join(src, "local.json"), testChunkSize)
+	require.NoError(t, err)
+	m.Name = "3-4-getZkProof.json"
+	body, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(src, "local.json"))
+	require.NoError(t, err)
+
+	var status UploadStatus
+	require.NoError(t, client.do(ctx, http.MethodPost, client.url("execution", "responses", m.Name, "uploads"), body, &status))
+	for _, i := range status.Missing {
+		chunk := content[int64(i)*m.ChunkSize : int64(i)*m.ChunkSize+m.ChunkLen(i)]
+		require.NoError(t, client.do(ctx, http.MethodPut, client.url("execution", "responses", m.Name, "uploads", m.Sha256, fmt.Sprint(i)), chunk, nil))
+	}
+
+	written := randomFile(t, dir, m.Name, testChunkSize)
+	err = client.do(ctx, http.MethodPost, client.url("execution", "responses", m.Name, "uploads", m.Sha256, "commit"), nil, nil)
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusConflict, se.Code)
+
+	for name, want := range map[string][]byte{"1-2-getZkProof.json": existing, m.Name: written} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestExpireUploads(t *testing.T) {
+
+	dir := t.TempDir()
+	server := NewServer(map[string]Volume{"execution/responses": {Dir: dir, Uploads: true}}, ServerOptions{
+		ChunkSize: testChunkSize,
+		Token:     testToken,
+		UploadTTL: time.Hour,
+	})
+	srv := httptest.NewServer(server.Handler())
+	t.Cleanup(srv.Close)
+	client := &Client{BaseURL: srv.URL, Token: testToken, ChunkSize: testChunkSize}
+
+	src := t.TempDir()
+	randomFile(t, src, "local.json", 2*testChunkSize)
+	m, err := ManifestOf(filepath.Join(src, "local.json"), testChunkSize)
+	require.NoError(t, err)
+	m.Name = "1-2-getZkProof.json"
+	body, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, client.do(ctx, http.MethodPost, client.url("execution", "responses", m.Name, "uploads"), body, nil))
+
+	staging := stagingDir(dir, m.Sha256)
+	require.DirExists(t, staging)
+
+	require.NoError(t, server.ExpireUploads(time.Now().Add(59*time.Minute)))
+	assert.DirExists(t, staging)
+
+	require.NoError(t, server.ExpireUploads(time.Now().Add(61*time.Minute)))
+	assert.NoDirExists(t, staging)
+
+	// The upload is restarted from scratch
+	var status UploadStatus
+	require.NoError(t, client.do(ctx, http.MethodPost, client.url("execution", "responses", m.Name, "uploads"), body, &status))
+	assert.Equal(t, []int{0, 1}, status.Missing)
+}
+
+func TestManifestCacheIsBounded(t *testing.T) {
+
+	c := newManifestCache(2)
+	keys := []manifestKey{{path: "a"}, {path: "b"}, {path: "c"}}
+
+	c.put(keys[0], &Manifest{Name: "a"})
+	c.put(keys[1], &Manifest{Name: "b"})
+
+	// "a" is used, so "b" is the one evicted
+	_, ok := c.get(keys[0])
+	require.True(t, ok)
+	c.put(keys[2], &Manifest{Name: "c"})
+
+	assert.Equal(t, 2, c.len())
+	_, ok = c.get(keys[1])
+	assert.False(t, ok)
+	for _, k := range []manifestKey{keys[0], keys[2]} {
+		m, ok := c.get(k)
+		require.True(t, ok)
+		assert.Equal(t, k.path, m.Name)
+	}
+}
@@ -0,0 +1,81 @@
+package artifacts
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultMaxManifests is the number of manifests cached by a [Server] when
+// none is specified.
+const DefaultMaxManifests = 1024
+
+// manifestKey identifies a version of a file to cache its manifest
+type manifestKey struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// manifestCache is a least-recently-used cache of the manifests of the served
+// files. It is bounded as every version of every served file has its own key:
+// the cache would otherwise grow for as long as the server runs.
+type manifestCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[manifestKey]*list.Element
+	// order lists the entries, the most recently used first
+	order *list.List
+}
+
+type manifestEntry struct {
+	key manifestKey
+	m   *Manifest
+}
+
+func newManifestCache(max int) *manifestCache {
+	if max <= 0 {
+		max = DefaultMaxManifests
+	}
+	return &manifestCache{max: max, entries: map[manifestKey]*list.Element{}, order: list.New()}
+}
+
+// get returns the cached manifest for key, if any
+func (c *manifestCache) get(key manifestKey) (*Manifest, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*manifestEntry).m, true
+}
+
+// put caches m for key and evicts the least recently used entry if the cache
+// is full.
+func (c *manifestCache) put(key manifestKey, m *Manifest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*manifestEntry).m = m
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&manifestEntry{key: key, m: m})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*manifestEntry).key)
+	}
+}
+
+// len returns the number of cached manifests
+func (c *manifestCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
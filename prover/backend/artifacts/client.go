@@ -0,0 +1,252 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/sirupsen/logrus"
+)
+
+// Client transfers files to and from a [Server]. The transfers are resumable:
+// a download keeps the chunks it received next to its destination, and an
+// upload is resumed by the server, so that calling the same method again
+// after a failure only transfers the missing chunks.
+type Client struct {
+	// BaseURL is the URL of the server, e.g. "http://prover-1:9091"
+	BaseURL string
+	// Token is the bearer token sent to the server
+	Token string
+	// HTTP is the client used for the requests, [http.DefaultClient] if nil
+	HTTP *http.Client
+	// ChunkSize is the size of the uploaded chunks, [DefaultChunkSize] if not
+	// positive. It may not exceed the one of the server.
+	ChunkSize int64
+	// Retries is the number of times the transfer of a chunk is retried
+	// before giving up.
+	Retries int
+	// RetryDelay is the delay before the first retry, it doubles after every
+	// retry. Defaults to 1 second.
+	RetryDelay time.Duration
+}
+
+func (c *Client) http() *http.Client {
+	if c.HTTP == nil {
+		return http.DefaultClient
+	}
+	return c.HTTP
+}
+
+func (c *Client) url(job, dir, name string, elems ...string) string {
+	u, err := url.JoinPath(c.BaseURL, append([]string{"v1", job, dir, name}, elems...)...)
+	if err != nil {
+		// the only error is a parsing error of the base URL, which is a
+		// configuration error.
+		panic(fmt.Sprintf("invalid base URL %q: %v", c.BaseURL, err))
+	}
+	return u
+}
+
+// do sends a request and decodes the JSON response into res, if not nil. The
+// status codes other than 200 are returned as an [*StatusError].
+func (c *Client) do(ctx context.Context, method, u string, body []byte, res any) error {
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.http().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		return &StatusError{Code: resp.StatusCode, Message: string(bytes.TrimSpace(b))}
+	}
+
+	if res == nil {
+		return nil
+	}
+
+	if raw, ok := res.(*[]byte); ok {
+		*raw, err = io.ReadAll(resp.Body)
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// StatusError is returned by the [Client] when the server answers with an
+// unexpected status code.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %v: %v", e.Code, e.Message)
+}
+
+// retry runs f until it succeeds, the retries are exhausted or the error is
+// not transient, i.e. a status error other than a server error.
+func (c *Client) retry(ctx context.Context, what string, f func() error) error {
+
+	delay := c.RetryDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		var se *StatusError
+		if attempt >= c.Retries || ctx.Err() != nil || (errors.As(err, &se) && se.Code < 500) {
+			return fmt.Errorf("%v: %w", what, err)
+		}
+
+		logrus.Warnf("artifacts: %v failed (attempt %v/%v), retrying in %v: %v", what, attempt+1, c.Retries+1, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v: %w", what, ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// Download downloads the file name of the volume job/dir to dst. The chunks
+// are kept in a hidden directory next to dst until the download completes,
+// so a failed download is resumed by calling Download again. The file is
+// written atomically, once its digest is checked.
+func (c *Client) Download(ctx context.Context, job, dir, name, dst string) error {
+
+	m := &Manifest{}
+	err := c.retry(ctx, "fetching the manifest", func() error {
+		return c.do(ctx, http.MethodGet, c.url(job, dir, name, "manifest"), nil, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := m.Validate(); err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	parts := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".parts", m.Sha256)
+	if err := os.MkdirAll(parts, 0770); err != nil {
+		return err
+	}
+
+	nbFetched := 0
+	for i, chunk := range m.Chunks {
+
+		if _, err := os.Stat(filepath.Join(parts, chunk)); err == nil {
+			continue
+		}
+
+		err := c.retry(ctx, fmt.Sprintf("downloading the chunk %v of %v", i, name), func() error {
+			var b []byte
+			if err := c.do(ctx, http.MethodGet, c.url(job, dir, name, "chunks", fmt.Sprint(i)), nil, &b); err != nil {
+				return err
+			}
+			if digest(b) != chunk {
+				// the file may have changed on the server, or the chunk was
+				// corrupted in transit. The latter is worth a retry.
+				return fmt.Errorf("chunk %v: %w", i, errDigestMismatch)
+			}
+			return files.WriteFileAtomic(filepath.Join(parts, chunk), b)
+		})
+		if err != nil {
+			return err
+		}
+		nbFetched++
+	}
+
+	err = files.WriteAtomic(dst, func(w io.Writer) error {
+		return concatChunks(w, parts, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("artifacts: downloaded %v (%v bytes, %v/%v chunks fetched)", name, m.Size, nbFetched, len(m.Chunks))
+	return os.RemoveAll(filepath.Dir(parts))
+}
+
+// Upload uploads the file at src as name in the volume job/dir. Only the
+// chunks missing on the server are sent, so a failed upload is resumed by
+// calling Upload again.
+func (c *Client) Upload(ctx context.Context, src, job, dir, name string) error {
+
+	m, err := ManifestOf(src, c.ChunkSize)
+	if err != nil {
+		return err
+	}
+	m.Name = name
+
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	var status UploadStatus
+	err = c.retry(ctx, "starting the upload", func() error {
+		return c.do(ctx, http.MethodPost, c.url(job, dir, name, "uploads"), body, &status)
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, i := range status.Missing {
+
+		if i < 0 || i >= len(m.Chunks) {
+			return fmt.Errorf("the server requested the chunk %v out of %v", i, len(m.Chunks))
+		}
+
+		chunk := make([]byte, m.ChunkLen(i))
+		if _, err := f.ReadAt(chunk, int64(i)*m.ChunkSize); err != nil {
+			return fmt.Errorf("could not read the chunk %v of %v: %w", i, src, err)
+		}
+
+		err := c.retry(ctx, fmt.Sprintf("uploading the chunk %v of %v", i, name), func() error {
+			return c.do(ctx, http.MethodPut, c.url(job, dir, name, "uploads", m.Sha256, fmt.Sprint(i)), chunk, nil)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.retry(ctx, "committing the upload", func() error {
+		return c.do(ctx, http.MethodPost, c.url(job, dir, name, "uploads", m.Sha256, "commit"), nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("artifacts: uploaded %v (%v bytes, %v/%v chunks sent)", name, m.Size, len(status.Missing), len(m.Chunks))
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package artifacts implements a chunked and resumable transfer protocol over
+// HTTP for the large files exchanged between the coordinator and the provers:
+// the requests and the proofs.
+//
+// A file is described by a [Manifest] listing the SHA-256 digests of its
+// fixed-size chunks and of the whole file. The chunks are transferred
+// independently and checked against the manifest on reception, so that a
+// transfer interrupted by a network failure resumes with the chunks that are
+// missing instead of starting over. The uploads are staged next to their
+// destination and only moved in place, atomically, once all the chunks are
+// received and the digest of the assembled file matches the manifest.
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultChunkSize is the size of the chunks used when none is specified
+const DefaultChunkSize int64 = 8 << 20
+
+// Manifest describes a file as a list of content-addressed chunks. All the
+// chunks have ChunkSize bytes, except the last one which may be shorter.
+type Manifest struct {
+	// Name is the base name of the file
+	Name string `json:"name"`
+	// Size is the size of the file in bytes
+	Size int64 `json:"size"`
+	// Sha256 is the hex-encoded digest of the whole file
+	Sha256 string `json:"sha256"`
+	// ChunkSize is the size of the chunks in bytes
+	ChunkSize int64 `json:"chunkSize"`
+	// Chunks lists the hex-encoded digests of the chunks, in order
+	Chunks []string `json:"chunks"`
+}
+
+// ManifestOf reads the file at path and returns its manifest
+func ManifestOf(path string, chunkSize int64) (*Manifest, error) {
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %v: %w", path, err)
+	}
+	defer f.Close()
+
+	var (
+		res = &Manifest{Name: filepath.Base(path), ChunkSize: chunkSize}
+		all = sha256.New()
+		buf = make([]byte, chunkSize)
+	)
+
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			all.Write(buf[:n])
+			res.Chunks = append(res.Chunks, digest(buf[:n]))
+			res.Size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read %v: %w", path, err)
+		}
+	}
+
+	res.Sha256 = hex.EncodeToString(all.Sum(nil))
+	return res, nil
+}
+
+// Validate checks that the manifest is well-formed: the name is a plain file
+// name and the number of chunks and their digests are consistent with the
+// size.
+func (m *Manifest) Validate() error {
+
+	if err := ValidateName(m.Name); err != nil {
+		return err
+	}
+
+	if m.Size < 0 || m.ChunkSize <= 0 {
+		return fmt.Errorf("invalid size %v or chunk size %v", m.Size, m.ChunkSize)
+	}
+
+	if expected := (m.Size + m.ChunkSize - 1) / m.ChunkSize; int64(len(m.Chunks)) != expected {
+		return fmt.Errorf("expected %v chunks for %v bytes, got %v", expected, m.Size, len(m.Chunks))
+	}
+
+	for _, d := range append([]string{m.Sha256}, m.Chunks...) {
+		if !isDigest(d) {
+			return fmt.Errorf("invalid digest %q", d)
+		}
+	}
+
+	return nil
+}
+
+// ChunkLen returns the size of the chunk at position i
+func (m *Manifest) ChunkLen(i int) int64 {
+	return min(m.ChunkSize, m.Size-int64(i)*m.ChunkSize)
+}
+
+// ValidateName checks that name is a plain file name that can be transferred:
+// it must not be empty, contain a path separator or start with a dot, the
+// latter being reserved for the in-progress files.
+func ValidateName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || filepath.Base(name) != name || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid file name %q", name)
+	}
+	return nil
+}
+
+// errDigestMismatch is returned when a chunk or a file does not match the
+// digest of the manifest.
+var errDigestMismatch = errors.New("digest mismatch")
+
+func digest(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func isDigest(s string) bool {
+	b, err := hex.DecodeString(s)
+	return err == nil && len(b) == sha256.Size
+}
+
+// digestWriter forwards the writes to an underlying writer while hashing them
+type digestWriter struct {
+	w io.Writer
+	h hash.Hash
+}
+
+func newDigestWriter(w io.Writer) *digestWriter {
+	return &digestWriter{w: w, h: sha256.New()}
+}
+
+func (d *digestWriter) Write(p []byte) (int, error) {
+	d.h.Write(p)
+	return d.w.Write(p)
+}
+
+// digest returns the hex-encoded digest of what has been written so far
+func (d *digestWriter) digest() string {
+	return hex.EncodeToString(d.h.Sum(nil))
+}
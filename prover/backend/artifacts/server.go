@@ -0,0 +1,563 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/sirupsen/logrus"
+)
+
+// uploadsDir is the name of the directory, in each volume, where the chunks
+// of the in-progress uploads are staged. It starts with a dot so that it is
+// ignored by the file watcher of the controller.
+const uploadsDir = ".uploads"
+
+// Server serves the files of a set of directories, the volumes, following the
+// transfer protocol of the package. The routes are:
+//
+//	GET  /v1/{job}/{dir}/{name}/manifest                  the manifest of a file
+//	GET  /v1/{job}/{dir}/{name}/chunks/{index}            a chunk of a file
+//	POST /v1/{job}/{dir}/{name}/uploads                   starts or resumes an upload, the body is the manifest
+//	PUT  /v1/{job}/{dir}/{name}/uploads/{sha256}/{index}  uploads a chunk
+//	POST /v1/{job}/{dir}/{name}/uploads/{sha256}/commit   assembles the file once all the chunks are uploaded
+//
+// where "{job}/{dir}" identifies the volume. Starting or resuming an upload
+// returns the indices of the chunks that the server is missing, as an
+// [UploadStatus]. An upload is identified by the digest of the file so that
+// an interrupted upload of the same file is resumed, with the chunks that were
+// already received.
+//
+// Every request must carry the token of the server as a bearer token. The
+// files may only be uploaded to the volumes accepting uploads, and an upload
+// may not replace an existing file.
+type Server struct {
+	volumes   map[string]Volume
+	chunkSize int64
+	token     []byte
+	uploadTTL time.Duration
+
+	// manifests caches the manifests of the served files as computing them
+	// requires reading the whole file.
+	manifests *manifestCache
+	// commits serializes the commits as two concurrent commits of the same
+	// upload would race on the staged chunks.
+	commits sync.Mutex
+}
+
+// Volume is a directory served by a [Server]
+type Volume struct {
+	// Dir is the served directory
+	Dir string
+	// Uploads indicates whether files may be uploaded to the volume
+	Uploads bool
+}
+
+// DefaultUploadTTL is the time after which an upload that is not progressing
+// is expired, when none is specified.
+const DefaultUploadTTL = 24 * time.Hour
+
+// ServerOptions are the options of a [Server]
+type ServerOptions struct {
+	// ChunkSize is the size of the chunks in which the files are served and
+	// the maximal size of the uploaded chunks, [DefaultChunkSize] if not
+	// positive.
+	ChunkSize int64
+	// Token is the bearer token the requests must carry. The server rejects
+	// every request if it is empty.
+	Token string
+	// UploadTTL is the time after which the chunks of an upload which did not
+	// receive any chunk are removed, [DefaultUploadTTL] if not positive.
+	UploadTTL time.Duration
+	// MaxManifests bounds the number of cached manifests,
+	// [DefaultMaxManifests] if not positive.
+	MaxManifests int
+}
+
+// UploadStatus is returned when starting, resuming or committing an upload
+type UploadStatus struct {
+	// Missing lists the indices of the chunks the server has not received
+	Missing []int `json:"missing"`
+}
+
+// NewServer returns a server for the volumes, that maps the names of the
+// volumes, of the form "{job}/{dir}", to directories.
+func NewServer(volumes map[string]Volume, opts ServerOptions) *Server {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	if opts.UploadTTL <= 0 {
+		opts.UploadTTL = DefaultUploadTTL
+	}
+	return &Server{
+		volumes:   volumes,
+		chunkSize: opts.ChunkSize,
+		token:     []byte(opts.Token),
+		uploadTTL: opts.UploadTTL,
+		manifests: newManifestCache(opts.MaxManifests),
+	}
+}
+
+// Handler returns the HTTP handler of the server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/{job}/{dir}/{name}/manifest", s.getManifest)
+	mux.HandleFunc("GET /v1/{job}/{dir}/{name}/chunks/{index}", s.getChunk)
+	mux.HandleFunc("POST /v1/{job}/{dir}/{name}/uploads", s.startUpload)
+	mux.HandleFunc("PUT /v1/{job}/{dir}/{name}/uploads/{sha256}/{index}", s.putChunk)
+	mux.HandleFunc("POST /v1/{job}/{dir}/{name}/uploads/{sha256}/commit", s.commitUpload)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects the requests which do not carry the token of the
+// server as a bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || len(s.token) == 0 || subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// httpError is an error along with the HTTP status to report it with
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+
+func errorf(status int, format string, args ...any) error {
+	return &httpError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// reply writes the error, if any, or res in JSON
+func reply(w http.ResponseWriter, r *http.Request, res any, err error) {
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		var he *httpError
+		if errors.As(err, &he) {
+			status = he.status
+		}
+		if status == http.StatusInternalServerError {
+			logrus.Errorf("artifacts: %v %v: %v", r.Method, r.URL.Path, err)
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logrus.Errorf("artifacts: could not write the response of %v %v: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// resolve returns the directory of the volume and the name of the file
+// targeted by the request. upload indicates whether the request is part of an
+// upload, which the volume must accept.
+func (s *Server) resolve(r *http.Request, upload bool) (dir, name string, err error) {
+
+	vol, ok := s.volumes[r.PathValue("job")+"/"+r.PathValue("dir")]
+	if !ok {
+		return "", "", errorf(http.StatusNotFound, "unknown volume %v/%v", r.PathValue("job"), r.PathValue("dir"))
+	}
+
+	if upload && !vol.Uploads {
+		return "", "", errorf(http.StatusForbidden, "the volume %v/%v does not accept uploads", r.PathValue("job"), r.PathValue("dir"))
+	}
+	dir = vol.Dir
+
+	name = r.PathValue("name")
+	if err := ValidateName(name); err != nil {
+		return "", "", &httpError{status: http.StatusBadRequest, err: err}
+	}
+
+	return dir, name, nil
+}
+
+// manifest returns the manifest of the file at path, from the cache if the
+// file did not change.
+func (s *Server) manifest(path string) (*Manifest, error) {
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errorf(http.StatusNotFound, "%v does not exist", filepath.Base(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := manifestKey{path: path, size: info.Size(), modTime: info.ModTime()}
+	if m, ok := s.manifests.get(key); ok {
+		return m, nil
+	}
+
+	m, err := ManifestOf(path, s.chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	s.manifests.put(key, m)
+	return m, nil
+}
+
+func (s *Server) getManifest(w http.ResponseWriter, r *http.Request) {
+
+	dir, name, err := s.resolve(r, false)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	m, err := s.manifest(filepath.Join(dir, name))
+	reply(w, r, m, err)
+}
+
+func (s *Server) getChunk(w http.ResponseWriter, r *http.Request) {
+
+	dir, name, err := s.resolve(r, false)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	path := filepath.Join(dir, name)
+	m, err := s.manifest(path)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 || index >= len(m.Chunks) {
+		reply(w, r, nil, errorf(http.StatusBadRequest, "invalid chunk index %q", r.PathValue("index")))
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+	defer f.Close()
+
+	// The client checks the chunk against the manifest, if the file changed
+	// in the meantime, it fetches the manifest again.
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(m.ChunkLen(index), 10))
+	if _, err := io.Copy(w, io.NewSectionReader(f, int64(index)*m.ChunkSize, m.ChunkLen(index))); err != nil {
+		logrus.Warnf("artifacts: could not send the chunk %v of %v: %v", index, path, err)
+	}
+}
+
+// stagingDir returns the directory where the chunks of the upload of a file
+// with the given digest are staged.
+func stagingDir(dir, sha string) string {
+	return filepath.Join(dir, uploadsDir, sha)
+}
+
+// readUpload returns the manifest of an upload started with [startUpload]
+func (s *Server) readUpload(dir, name, sha string) (*Manifest, error) {
+
+	if !isDigest(sha) {
+		return nil, errorf(http.StatusBadRequest, "invalid digest %q", sha)
+	}
+
+	b, err := os.ReadFile(filepath.Join(stagingDir(dir, sha), "manifest.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errorf(http.StatusNotFound, "no upload in progress for %v", sha)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+
+	if m.Name != name {
+		return nil, errorf(http.StatusBadRequest, "the upload %v is for %v, not %v", sha, m.Name, name)
+	}
+
+	return m, nil
+}
+
+// missingChunks returns the indices of the chunks of the upload which have
+// not been received.
+func missingChunks(staging string, m *Manifest) []int {
+	res := []int{}
+	for i, c := range m.Chunks {
+		if _, err := os.Stat(filepath.Join(staging, c)); err != nil {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func (s *Server) startUpload(w http.ResponseWriter, r *http.Request) {
+
+	dir, name, err := s.resolve(r, true)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	m := &Manifest{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<20)).Decode(m); err != nil {
+		reply(w, r, nil, errorf(http.StatusBadRequest, "could not decode the manifest: %v", err))
+		return
+	}
+
+	if err := m.Validate(); err != nil {
+		reply(w, r, nil, &httpError{status: http.StatusBadRequest, err: err})
+		return
+	}
+
+	if m.Name != name {
+		reply(w, r, nil, errorf(http.StatusBadRequest, "the manifest is for %v, not %v", m.Name, name))
+		return
+	}
+
+	if m.ChunkSize > s.chunkSize {
+		reply(w, r, nil, errorf(http.StatusBadRequest, "the chunks may not be larger than %v bytes", s.chunkSize))
+		return
+	}
+
+	// Checked again when committing, this spares the transfer of the chunks
+	if err := checkNotExist(filepath.Join(dir, name)); err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	staging := stagingDir(dir, m.Sha256)
+	if err := os.MkdirAll(staging, 0770); err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	b, err := json.Marshal(m)
+	if err == nil {
+		err = files.WriteFileAtomic(filepath.Join(staging, "manifest.json"), b)
+	}
+
+	reply(w, r, UploadStatus{Missing: missingChunks(staging, m)}, err)
+}
+
+func (s *Server) putChunk(w http.ResponseWriter, r *http.Request) {
+
+	dir, name, err := s.resolve(r, true)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	sha := r.PathValue("sha256")
+	m, err := s.readUpload(dir, name, sha)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 || index >= len(m.Chunks) {
+		reply(w, r, nil, errorf(http.StatusBadRequest, "invalid chunk index %q", r.PathValue("index")))
+		return
+	}
+
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, m.ChunkLen(index)))
+	if err != nil {
+		reply(w, r, nil, errorf(http.StatusBadRequest, "could not read the chunk: %v", err))
+		return
+	}
+
+	if int64(len(b)) != m.ChunkLen(index) || digest(b) != m.Chunks[index] {
+		reply(w, r, nil, errorf(http.StatusUnprocessableEntity, "chunk %v: %w", index, errDigestMismatch))
+		return
+	}
+
+	err = files.WriteFileAtomic(filepath.Join(stagingDir(dir, sha), m.Chunks[index]), b)
+	reply(w, r, struct{}{}, err)
+}
+
+func (s *Server) commitUpload(w http.ResponseWriter, r *http.Request) {
+
+	dir, name, err := s.resolve(r, true)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	s.commits.Lock()
+	defer s.commits.Unlock()
+
+	sha := r.PathValue("sha256")
+	m, err := s.readUpload(dir, name, sha)
+	if err != nil {
+		reply(w, r, nil, err)
+		return
+	}
+
+	staging := stagingDir(dir, sha)
+	if missing := missingChunks(staging, m); len(missing) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(UploadStatus{Missing: missing})
+		return
+	}
+
+	err = assemble(filepath.Join(dir, name), staging, m)
+	if err == nil {
+		err = os.RemoveAll(staging)
+		logrus.Infof("artifacts: received %v (%v bytes) in %v", name, m.Size, dir)
+	}
+
+	reply(w, r, UploadStatus{Missing: []int{}}, err)
+}
+
+// checkNotExist returns a conflict error if the file at path exists
+func checkNotExist(path string) error {
+	_, err := os.Lstat(path)
+	if err == nil {
+		return errorf(http.StatusConflict, "%v already exists", filepath.Base(path))
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// assemble concatenates the chunks staged in staging into the file at path,
+// only if its digest matches the manifest. The file is assembled in staging
+// and hard-linked to path, so that it appears complete and does not replace
+// a file written in the meantime, by the server or by a local process.
+func assemble(path, staging string, m *Manifest) error {
+
+	if err := checkNotExist(path); err != nil {
+		return err
+	}
+
+	assembled := filepath.Join(staging, "assembled")
+	err := files.WriteAtomic(assembled, func(w io.Writer) error {
+		return concatChunks(w, staging, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.Link(assembled, path); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return errorf(http.StatusConflict, "%v already exists", filepath.Base(path))
+		}
+		return err
+	}
+
+	return files.SyncDir(filepath.Dir(path))
+}
+
+// ExpireUploads removes the uploads, of all the volumes, which did not
+// progress for longer than the upload TTL of the server: their staged chunks
+// would otherwise remain on the disk forever if the client gave up.
+func (s *Server) ExpireUploads(now time.Time) error {
+
+	// A commit removes its staging directory once done, the lock spares
+	// removing an upload being committed.
+	s.commits.Lock()
+	defer s.commits.Unlock()
+
+	var errs []error
+	for _, vol := range s.volumes {
+
+		if !vol.Uploads {
+			continue
+		}
+
+		root := filepath.Join(vol.Dir, uploadsDir)
+		entries, err := os.ReadDir(root)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, e := range entries {
+			// The modification time of the staging directory changes
+			// whenever a chunk or the manifest is written in it.
+			info, err := e.Info()
+			if err != nil || now.Sub(info.ModTime()) < s.uploadTTL {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(root, e.Name())); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			logrus.Infof("artifacts: expired the upload %v in %v", e.Name(), vol.Dir)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RunExpiry calls [Server.ExpireUploads] periodically until ctx is done
+func (s *Server) RunExpiry(ctx context.Context) {
+
+	// The uploads are expired at most a tenth of the TTL late
+	ticker := time.NewTicker(s.uploadTTL / 10)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.ExpireUploads(now); err != nil {
+				logrus.Warnf("artifacts: could not expire the uploads: %v", err)
+			}
+		}
+	}
+}
+
+// concatChunks writes the chunks of the manifest, read from dir, into w and
+// checks the digest of the whole file.
+func concatChunks(w io.Writer, dir string, m *Manifest) error {
+
+	var (
+		h   = newDigestWriter(w)
+		buf []byte
+		err error
+	)
+
+	for i, c := range m.Chunks {
+		if buf, err = os.ReadFile(filepath.Join(dir, c)); err != nil {
+			return fmt.Errorf("could not read the chunk %v: %w", i, err)
+		}
+		if _, err := h.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	if h.digest() != m.Sha256 {
+		return &httpError{status: http.StatusUnprocessableEntity, err: fmt.Errorf("file %v: %w", m.Name, errDigestMismatch)}
+	}
+
+	return nil
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 
 	blob_v0 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v0"
 	blob_v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
@@ -19,6 +17,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/sirupsen/logrus"
 
@@ -50,13 +49,13 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 		y      fr381.Element
 	)
 
-	if b, err := utils.HexDecodeString(req.ExpectedX); err != nil {
+	if b, err := utils.HexDecodeFixed(req.ExpectedX, 32); err != nil {
 		return nil, fmt.Errorf("could not parse the bytes of the expected x: %w", err)
 	} else {
 		copy(xBytes[:], b)
 	}
 
-	yBytes, err := utils.HexDecodeString(req.ExpectedY)
+	yBytes, err := utils.HexDecodeFixed(req.ExpectedY, 32)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse the bytes of the expected y: %w", err)
 	}
@@ -82,11 +81,16 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 		return nil, fmt.Errorf("unsupported blob version: %v", version)
 	}
 
-	dictPath := filepath.Join(cfg.PathForSetup(string(circuitID)), config.DictionaryFileName)
+	// The setup may provide several dictionaries, the blob is decompressed
+	// with the one recorded in its header.
+	dictChecksum, err := blob.GetDictChecksum(blobBytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the dictionary checksum of the blob: %w", err)
+	}
 
-	logrus.Infof("reading the dictionary at %v", dictPath)
+	logrus.Infof("reading the dictionary with checksum %v", utils.HexEncodeToString(dictChecksum))
 
-	dict, err := os.ReadFile(dictPath)
+	dict, err := blobdecompression.ReadDictionary(cfg.PathForSetup(string(circuitID)), version, dictChecksum)
 	if err != nil {
 		return nil, fmt.Errorf("error reading the dictionary: %w", err)
 	}
@@ -95,12 +99,14 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
 	logrus.Infof("computing the circuit's assignment")
 
-	snarkHash, err := utils.HexDecodeString(req.SnarkHash)
+	snarkHash, err := utils.HexDecodeFixed(req.SnarkHash, 32)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse the snark hash: %w", err)
 	}
 
-	assignment, pubInput, _snarkHash, err := blobdecompression.Assign(
+	// The witness is streamed from the blob bytes rather than going through
+	// the assignment of the circuit, so as to limit the peak memory.
+	witness, pubInput, _snarkHash, err := blobdecompression.AssignWitness(
 		utils.RightPad(blobBytes, expectedMaxUsableBytes),
 		dict,
 		req.Eip4844Enabled,
@@ -116,6 +122,18 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 		return nil, fmt.Errorf("blob checksum does not match the one computed by the assigner")
 	}
 
+	// In check-only mode, the job stops once the assignment is computed. This
+	// is used to replay requests and check the public input without paying
+	// for loading the setup and proving.
+	if cfg.BlobDecompression.ProverMode == config.ProverModeCheckOnly {
+		resp := &Response{
+			Request:       *req,
+			ProverVersion: cfg.Version,
+		}
+		resp.Debug.PublicInput = field.HexElement(pubInput)
+		return resp, nil
+	}
+
 	setup, err := circuits.LoadSetup(cfg, circuitID)
 	if err != nil {
 		return nil, fmt.Errorf("could not load the setup: %w", err)
@@ -151,9 +169,9 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
 	logrus.Infof("running the decompression prover")
 
-	proof, err := circuits.ProveCheck(
+	proof, err := circuits.ProveCheckWitness(
 		&setup,
-		assignment,
+		witness,
 		opts...,
 	)
 
@@ -170,7 +188,7 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 		VerifyingKeyShaSum: setup.VerifyingKeyDigest(),
 	}
 
-	resp.Debug.PublicInput = "0x" + pubInput.Text(16)
+	resp.Debug.PublicInput = field.HexElement(pubInput)
 
 	return resp, nil
 
@@ -216,8 +234,7 @@ func dummyProve(cfg *config.Config, req *Request) (*Response, error) {
 		VerifyingKeyShaSum: setup.VerifyingKeyDigest(),
 	}
 
-	inputString := utils.HexEncodeToString(input)
-	resp.Debug.PublicInput = utils.ApplyModulusBls12377(inputString)
+	resp.Debug.PublicInput = field.HexElement(*inputF)
 
 	return resp, nil
 }
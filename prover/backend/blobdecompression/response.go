@@ -1,5 +1,7 @@
 package blobdecompression
 
+import "github.com/consensys/linea-monorepo/prover/maths/field"
+
 // The decompression proof response contains all the fields of the requests
 // plus some prover related fields. We keep all the fields from the request so
 // that we can be sure that the prover will have all the relevant fields.
@@ -22,6 +24,6 @@ type Response struct {
 	// values corresponding to the generated proof.
 	Debug struct {
 		// Expected public input of the proof
-		PublicInput string `json:"publicInput"`
+		PublicInput field.HexElement `json:"publicInput"`
 	} `json:"debug"`
 }
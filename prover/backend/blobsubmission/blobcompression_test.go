@@ -346,3 +346,45 @@ func randBlob() kzg4844.Blob {
 	}
 	return blob
 }
+
+// The hashes of the request are accepted in any casing and with or without
+// prefix, and are re-encoded in the canonical form in the response.
+func TestBlobSubmissionNonCanonicalHashes(t *testing.T) {
+
+	f, err := os.Open(_inFile)
+	if err != nil {
+		t.Fatalf("could not open %s: %v", _inFile, err)
+	}
+	defer f.Close()
+
+	var inp Request
+	if err = json.NewDecoder(f).Decode(&inp); err != nil {
+		t.Fatalf("could not decode %++v: %v", inp, err)
+	}
+
+	expected, err := CraftResponse(&inp)
+	assert.NoError(t, err)
+
+	mixed := inp
+	mixed.ParentStateRootHash = strings.ToUpper(inp.ParentStateRootHash)
+	mixed.FinalStateRootHash = strings.TrimPrefix(inp.FinalStateRootHash, "0x")
+	mixed.DataParentHash = strings.ToUpper(strings.TrimPrefix(inp.DataParentHash, "0x"))
+
+	out, err := CraftResponse(&mixed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, *expected, *out)
+	}
+
+	short := inp
+	short.PrevShnarf = inp.PrevShnarf[:len(inp.PrevShnarf)-2]
+	_, err = CraftResponse(&short)
+	assert.ErrorContains(t, err, "bad previous shnarf")
+
+	// the empty hashes are the zero hash
+	empty := inp
+	empty.DataParentHash = ""
+	out, err = CraftResponse(&empty)
+	if assert.NoError(t, err) {
+		assert.Equal(t, utils.HexEncodeToString(make([]byte, 32)), out.DataParentHash)
+	}
+}
@@ -24,18 +24,20 @@ func CraftResponseCalldata(req *Request) (*Response, error) {
 
 	// Flat pass the request parameters to the response
 	var (
-		errs             [4]error
+		errs             [5]error
 		parentZkRootHash []byte
 		newZkRootHash    []byte
 		prevShnarf       []byte
 		compressedStream []byte
+		dataParentHash   []byte
 	)
 
 	// Validate the request parameters
-	parentZkRootHash, errs[0] = utils.HexDecodeString(req.ParentStateRootHash)
-	newZkRootHash, errs[1] = utils.HexDecodeString(req.FinalStateRootHash)
-	prevShnarf, errs[2] = utils.HexDecodeString(req.PrevShnarf)
+	parentZkRootHash, errs[0] = decodeHash(req.ParentStateRootHash)
+	newZkRootHash, errs[1] = decodeHash(req.FinalStateRootHash)
+	prevShnarf, errs[2] = decodeHash(req.PrevShnarf)
 	compressedStream, errs[3] = b64.DecodeString(req.CompressedData)
+	dataParentHash, errs[4] = decodeHash(req.DataParentHash)
 
 	// Collect and wrap the errors if any, so that we get a friendly error message
 	if errors.Join(errs[:]...) != nil {
@@ -52,16 +54,19 @@ func CraftResponseCalldata(req *Request) (*Response, error) {
 		if errs[3] != nil {
 			errsFiltered = append(errsFiltered, fmt.Errorf("bad compressed data: %w", errs[3]))
 		}
+		if errs[4] != nil {
+			errsFiltered = append(errsFiltered, fmt.Errorf("bad data parent hash: %w", errs[4]))
+		}
 		return nil, fmt.Errorf("crafting response:\n%w", errors.Join(errsFiltered...))
 	}
 
 	resp := &Response{
 		ConflationOrder: req.ConflationOrder,
-		// Reencode all the parameters to ensure that they are in 0x prefixed format
+		// Reencode all the parameters to ensure that they are in the canonical format
 		CompressedData:      b64.EncodeToString(compressedStream),
 		ParentStateRootHash: utils.HexEncodeToString(parentZkRootHash),
 		FinalStateRootHash:  utils.HexEncodeToString(newZkRootHash),
-		DataParentHash:      req.DataParentHash,
+		DataParentHash:      utils.HexEncodeToString(dataParentHash),
 		PrevShnarf:          utils.HexEncodeToString(prevShnarf),
 		Eip4844Enabled:      req.Eip4844Enabled, // this is guaranteed to be false
 		// Pass an the hex for an empty commitments and proofs instead of passing
@@ -178,6 +183,16 @@ func EvalStream(stream []byte, x_ []byte) (fr381.Element, error) {
 	return y, nil
 }
 
+// decodeHash decodes a 32 bytes hash of a request. The empty string is
+// decoded as the zero hash as the requests may leave the hashes they do not
+// rely on empty, e.g. the tests only interested in the blob.
+func decodeHash(s string) ([]byte, error) {
+	if len(s) == 0 {
+		return make([]byte, 32), nil
+	}
+	return utils.HexDecodeFixed(s, 32)
+}
+
 // schnarfParts wrap the arguments needed to create a new Shnarf by calling
 // the NewSchnarf() function.
 type Shnarf struct {
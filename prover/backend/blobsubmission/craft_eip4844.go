@@ -26,18 +26,20 @@ func CraftResponse(req *Request) (*Response, error) {
 
 	// Flat pass the request parameters to the response
 	var (
-		errs             [4]error
+		errs             [5]error
 		parentZkRootHash []byte
 		newZkRootHash    []byte
 		prevShnarf       []byte
 		compressedStream []byte
+		dataParentHash   []byte
 	)
 
 	// Validate the request parameters
-	parentZkRootHash, errs[0] = utils.HexDecodeString(req.ParentStateRootHash)
-	newZkRootHash, errs[1] = utils.HexDecodeString(req.FinalStateRootHash)
-	prevShnarf, errs[2] = utils.HexDecodeString(req.PrevShnarf)
+	parentZkRootHash, errs[0] = decodeHash(req.ParentStateRootHash)
+	newZkRootHash, errs[1] = decodeHash(req.FinalStateRootHash)
+	prevShnarf, errs[2] = decodeHash(req.PrevShnarf)
 	compressedStream, errs[3] = b64.DecodeString(req.CompressedData)
+	dataParentHash, errs[4] = decodeHash(req.DataParentHash)
 
 	// Collect and wrap the errors if any, so that we get a friendly error message
 	if errors.Join(errs[:]...) != nil {
@@ -54,16 +56,19 @@ func CraftResponse(req *Request) (*Response, error) {
 		if errs[3] != nil {
 			errsFiltered = append(errsFiltered, fmt.Errorf("bad compressed data: %w", errs[3]))
 		}
+		if errs[4] != nil {
+			errsFiltered = append(errsFiltered, fmt.Errorf("bad data parent hash: %w", errs[4]))
+		}
 		return nil, fmt.Errorf("crafting response:\n%w", errors.Join(errsFiltered...))
 
 	}
 
 	resp := &Response{
 		ConflationOrder: req.ConflationOrder,
-		// Reencode all the parameters to ensure that they are in 0x prefixed format
+		// Reencode all the parameters to ensure that they are in the canonical format
 		ParentStateRootHash: utils.HexEncodeToString(parentZkRootHash),
 		FinalStateRootHash:  utils.HexEncodeToString(newZkRootHash),
-		DataParentHash:      req.DataParentHash,
+		DataParentHash:      utils.HexEncodeToString(dataParentHash),
 		PrevShnarf:          utils.HexEncodeToString(prevShnarf),
 		Eip4844Enabled:      req.Eip4844Enabled, // this is guaranteed to be true
 	}
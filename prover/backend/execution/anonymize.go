@@ -0,0 +1,221 @@
+package execution
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+)
+
+// AnonymizeOptions tunes [Request.Anonymize]
+type AnonymizeOptions struct {
+	// Salt keys the pseudo-random substitutions. Two requests anonymized with
+	// the same salt are anonymized consistently: the same calldata or the same
+	// address are replaced by the same value in both. The salt must not be
+	// shared along with the anonymized requests.
+	Salt []byte
+	// Addresses also replaces the recipients of the transactions and the
+	// addresses of their access lists by pseudonyms. The addresses up to
+	// [anonymizeMaxReservedAddress], e.g. the precompiles, are kept as is.
+	Addresses bool
+}
+
+// anonymizeMaxReservedAddress is the largest address, seen as an integer, that
+// is never replaced by a pseudonym. This covers the precompiles and the system
+// contracts living at low addresses.
+const anonymizeMaxReservedAddress = 0xffff
+
+// Anonymize returns a redacted copy of the request, which can be shared with
+// external teams to describe the shape of a request that the prover failed
+// on. It is a redaction, not a re-derivation: the result is not a valid
+// request and does not reproduce the failure by itself.
+//
+// The calldata of the transactions, and optionally their addresses, are
+// replaced by pseudo-random values. The substitutions preserve the number of
+// blocks and of transactions, the type, the gas parameters and the value of
+// the transactions, the length of the calldata and the position of its zero
+// bytes. A given calldata or address is always replaced by the same value, so
+// the repetitions are preserved too.
+//
+// The state-manager traces and the bridge logs are dropped, whatever the
+// options: they hold the state of the accounts and the messages sent through
+// the bridge, and cannot be rewritten consistently with the state root of
+// the parent block. The block headers are kept, so their transaction roots and
+// the signatures of the transactions no longer match the anonymized content:
+// the senders are recovered as different, but consistent, accounts. The
+// conflated traces referenced by the request are not anonymized and must not
+// be shared along with it.
+func (req *Request) Anonymize(opts AnonymizeOptions) (*Request, error) {
+
+	if len(opts.Salt) == 0 {
+		return nil, errors.New("anonymizing a request requires a salt")
+	}
+
+	blocks, err := req.decodeBlocks()
+	if err != nil {
+		return nil, err
+	}
+
+	res := *req
+	res.ZkStateMerkleProof = nil
+	res.BlocksData = append(res.BlocksData[:0:0], req.BlocksData...)
+
+	for i := range blocks {
+
+		var (
+			block = &blocks[i]
+			txs   = block.Transactions()
+			anon  = make([]*ethtypes.Transaction, len(txs))
+		)
+
+		for j, tx := range txs {
+			if anon[j], err = anonymizeTx(tx, opts); err != nil {
+				return nil, fmt.Errorf("could not anonymize the transaction #%v of the block #%v: %w", j, i, err)
+			}
+		}
+
+		newBlock := ethtypes.NewBlockWithHeader(block.Header()).WithBody(ethtypes.Body{
+			Transactions: anon,
+			Uncles:       block.Uncles(),
+			Withdrawals:  block.Withdrawals(),
+		})
+
+		b, err := rlp.EncodeToBytes(newBlock)
+		if err != nil {
+			return nil, fmt.Errorf("could not RLP encode the anonymized block #%v: %w", i, err)
+		}
+
+		res.BlocksData[i].Rlp = hexutil.Encode(b)
+		res.BlocksData[i].BridgeLogs = nil
+	}
+
+	return &res, nil
+}
+
+// anonymizeTx returns a copy of the transaction with its calldata, and
+// optionally its addresses, replaced by pseudonyms. The signature is kept.
+func anonymizeTx(tx *ethtypes.Transaction, opts AnonymizeOptions) (*ethtypes.Transaction, error) {
+
+	var (
+		v, r, s    = tx.RawSignatureValues()
+		data       = anonymizeCalldata(tx.Data(), opts.Salt)
+		to         = tx.To()
+		accessList = tx.AccessList()
+	)
+
+	if opts.Addresses {
+		if to != nil {
+			a := anonymizeAddress(*to, opts.Salt)
+			to = &a
+		}
+		accessList = make(ethtypes.AccessList, len(tx.AccessList()))
+		for i, tuple := range tx.AccessList() {
+			accessList[i] = ethtypes.AccessTuple{
+				Address:     anonymizeAddress(tuple.Address, opts.Salt),
+				StorageKeys: tuple.StorageKeys,
+			}
+		}
+	}
+
+	switch tx.Type() {
+	case ethtypes.LegacyTxType:
+		return ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce: tx.Nonce(), GasPrice: tx.GasPrice(), Gas: tx.Gas(),
+			To: to, Value: tx.Value(), Data: data,
+			V: v, R: r, S: s,
+		}), nil
+	case ethtypes.AccessListTxType:
+		return ethtypes.NewTx(&ethtypes.AccessListTx{
+			ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasPrice: tx.GasPrice(), Gas: tx.Gas(),
+			To: to, Value: tx.Value(), Data: data, AccessList: accessList,
+			V: v, R: r, S: s,
+		}), nil
+	case ethtypes.DynamicFeeTxType:
+		return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasTipCap: tx.GasTipCap(), GasFeeCap: tx.GasFeeCap(), Gas: tx.Gas(),
+			To: to, Value: tx.Value(), Data: data, AccessList: accessList,
+			V: v, R: r, S: s,
+		}), nil
+	case ethtypes.BlobTxType:
+		// The blob transactions may not create a contract, so to is set
+		if to == nil {
+			return nil, errors.New("blob transaction without recipient")
+		}
+		return ethtypes.NewTx(&ethtypes.BlobTx{
+			ChainID: uint256.MustFromBig(tx.ChainId()), Nonce: tx.Nonce(),
+			GasTipCap: uint256.MustFromBig(tx.GasTipCap()), GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()), Gas: tx.Gas(),
+			To: *to, Value: uint256.MustFromBig(tx.Value()), Data: data, AccessList: accessList,
+			BlobFeeCap: uint256.MustFromBig(tx.BlobGasFeeCap()), BlobHashes: tx.BlobHashes(),
+			V: uint256.MustFromBig(v), R: uint256.MustFromBig(r), S: uint256.MustFromBig(s),
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %v", tx.Type())
+	}
+}
+
+// anonymizeCalldata replaces every non-zero byte of data by a pseudo-random
+// non-zero byte. The pseudo-random stream is derived from the salt and from
+// data itself so that identical calldata are replaced identically.
+func anonymizeCalldata(data, salt []byte) []byte {
+
+	if len(data) == 0 {
+		return data
+	}
+
+	var (
+		res    = make([]byte, len(data))
+		seed   = saltedHash(salt, []byte("calldata"), data)
+		stream []byte
+	)
+
+	for i, b := range data {
+		if b == 0 {
+			continue
+		}
+		if len(stream) == 0 {
+			var ctr [8]byte
+			binary.BigEndian.PutUint64(ctr[:], uint64(i)) // #nosec G115 -- i is non-negative
+			block := saltedHash(seed[:], ctr[:])
+			stream = block[:]
+		}
+		// maps to [1, 255], the bias is irrelevant here
+		res[i] = 1 + stream[0]%255
+		stream = stream[1:]
+	}
+
+	return res
+}
+
+// anonymizeAddress returns the pseudonym of an address. The reserved addresses
+// are returned as is.
+func anonymizeAddress(a common.Address, salt []byte) common.Address {
+
+	if a.Big().Cmp(big.NewInt(anonymizeMaxReservedAddress)) <= 0 {
+		return a
+	}
+
+	h := saltedHash(salt, []byte("address"), a[:])
+	return common.BytesToAddress(h[12:])
+}
+
+// saltedHash hashes the salt and the chunks, each prefixed by its length so
+// that the encoding is unambiguous.
+func saltedHash(salt []byte, chunks ...[]byte) [sha256.Size]byte {
+	h := sha256.New()
+	for _, c := range append([][]byte{salt}, chunks...) {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(c)))
+		h.Write(l[:])
+		h.Write(c)
+	}
+	var res [sha256.Size]byte
+	h.Sum(res[:0])
+	return res
+}
@@ -0,0 +1,119 @@
+package execution
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizeRequest(t *testing.T) {
+
+	var (
+		recipient  = common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678")
+		precompile = common.BytesToAddress([]byte{0x01})
+		calldata   = []byte{0xa9, 0x05, 0x9c, 0xbb, 0, 0, 0, 0x12, 0x34, 0, 0xff}
+	)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := ethtypes.LatestSignerForChainID(big.NewInt(59144))
+
+	txs := []*ethtypes.Transaction{
+		ethtypes.MustSignNewTx(key, signer, &ethtypes.DynamicFeeTx{
+			ChainID: big.NewInt(59144), Nonce: 1, Gas: 100_000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2),
+			To: &recipient, Value: big.NewInt(3), Data: calldata,
+			AccessList: ethtypes.AccessList{{Address: recipient, StorageKeys: []common.Hash{{1}}}},
+		}),
+		ethtypes.MustSignNewTx(key, signer, &ethtypes.LegacyTx{
+			Nonce: 2, Gas: 100_000, GasPrice: big.NewInt(1), To: &precompile, Data: calldata,
+		}),
+		ethtypes.MustSignNewTx(key, signer, &ethtypes.BlobTx{
+			ChainID: uint256.NewInt(59144), Nonce: 3, Gas: 100_000, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(2),
+			To: recipient, Value: uint256.NewInt(4), Data: calldata,
+			BlobFeeCap: uint256.NewInt(5), BlobHashes: []common.Hash{{2}},
+		}),
+	}
+
+	header := &ethtypes.Header{Number: big.NewInt(7), Difficulty: big.NewInt(0)}
+	block := ethtypes.NewBlockWithHeader(header).WithBody(ethtypes.Body{Transactions: txs})
+	b, err := rlp.EncodeToBytes(block)
+	require.NoError(t, err)
+
+	req := &Request{
+		ConflatedExecutionTracesFile: "7-7.conflated.lt",
+		ZkStateMerkleProof:           [][]statemanager.DecodedTrace{{{Location: "0x"}}},
+	}
+	req.BlocksData = make([]struct {
+		Rlp        string         `json:"rlp"`
+		BridgeLogs []ethtypes.Log `json:"bridgeLogs"`
+	}, 1)
+	req.BlocksData[0].Rlp = hexutil.Encode(b)
+	req.BlocksData[0].BridgeLogs = []ethtypes.Log{{Address: recipient, Data: calldata}}
+
+	anonTxs := func(opts AnonymizeOptions) []*ethtypes.Transaction {
+		anon, err := req.Anonymize(opts)
+		require.NoError(t, err)
+		assert.Equal(t, req.ConflatedExecutionTracesFile, anon.ConflatedExecutionTracesFile)
+		// dropped in every mode, and not in the original request
+		assert.Nil(t, anon.ZkStateMerkleProof)
+		assert.Nil(t, anon.BlocksData[0].BridgeLogs)
+		assert.Len(t, req.ZkStateMerkleProof, 1)
+		assert.Len(t, req.BlocksData[0].BridgeLogs, 1)
+		blocks := anon.Blocks()
+		require.Len(t, blocks, 1)
+		assert.Equal(t, block.Hash(), blocks[0].Hash(), "the header must be kept")
+		return blocks[0].Transactions()
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+
+		res := anonTxs(AnonymizeOptions{Salt: []byte("salt")})
+		require.Len(t, res, 3)
+
+		for i, tx := range res {
+			assert.Equal(t, txs[i].Type(), tx.Type())
+			assert.Equal(t, txs[i].Nonce(), tx.Nonce())
+			assert.Equal(t, txs[i].To(), tx.To())
+			assert.Equal(t, txs[i].AccessList(), tx.AccessList())
+			assert.NotEqual(t, calldata, tx.Data())
+			require.Len(t, tx.Data(), len(calldata))
+			for j := range calldata {
+				assert.Equal(t, calldata[j] == 0, tx.Data()[j] == 0, "zero byte pattern at %v", j)
+			}
+		}
+
+		// identical calldata are anonymized identically and the process is
+		// deterministic for a given salt.
+		assert.Equal(t, res[0].Data(), res[1].Data())
+		assert.Equal(t, res[0].Data(), res[2].Data())
+		assert.Equal(t, txs[2].BlobHashes(), res[2].BlobHashes())
+		assert.Equal(t, txs[2].BlobGasFeeCap(), res[2].BlobGasFeeCap())
+		assert.Equal(t, res[0].Data(), anonTxs(AnonymizeOptions{Salt: []byte("salt")})[0].Data())
+		assert.NotEqual(t, res[0].Data(), anonTxs(AnonymizeOptions{Salt: []byte("other")})[0].Data())
+	})
+
+	t.Run("addresses", func(t *testing.T) {
+
+		res := anonTxs(AnonymizeOptions{Salt: []byte("salt"), Addresses: true})
+
+		assert.NotEqual(t, recipient, *res[0].To())
+		assert.Equal(t, *res[0].To(), res[0].AccessList()[0].Address)
+		assert.Equal(t, txs[0].AccessList()[0].StorageKeys, res[0].AccessList()[0].StorageKeys)
+		assert.Equal(t, precompile, *res[1].To(), "reserved addresses are kept")
+		assert.Equal(t, *res[0].To(), *res[2].To())
+	})
+
+	t.Run("no-salt", func(t *testing.T) {
+		_, err := req.Anonymize(AnonymizeOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -1,7 +1,11 @@
 package bridge
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/consensys/linea-monorepo/prover/utils"
+	utypes "github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -54,3 +58,37 @@ func MsgConfirmSelector() []byte {
 	// Encode in utf8, hash it, keep the 4 leftmost bytes
 	return crypto.Keccak256([]byte(signature))[:4]
 }
+
+// ParseL1L2MessageHashesAddedToInbox returns the message hashes listed in an
+// `L1L2MessageHashesAddedToInbox` event. The hashes are ABI-encoded in the data
+// of the log as a dynamic bytes32[].
+func ParseL1L2MessageHashesAddedToInbox(log types.Log) ([]utypes.FullBytes32, error) {
+
+	const word = 32
+
+	if len(log.Data) < 2*word {
+		return nil, fmt.Errorf("the data of the log is too short: %v bytes", len(log.Data))
+	}
+
+	offset := new(big.Int).SetBytes(log.Data[:word])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(log.Data)-word) {
+		return nil, fmt.Errorf("invalid offset for the array: %v", offset)
+	}
+
+	var (
+		start  = int(offset.Uint64())
+		length = new(big.Int).SetBytes(log.Data[start : start+word])
+		body   = log.Data[start+word:]
+	)
+
+	if !length.IsUint64() || length.Uint64() > uint64(len(body)/word) {
+		return nil, fmt.Errorf("invalid length for the array: %v", length)
+	}
+
+	res := make([]utypes.FullBytes32, length.Uint64())
+	for i := range res {
+		copy(res[i][:], body[i*word:(i+1)*word])
+	}
+
+	return res, nil
+}
@@ -0,0 +1,139 @@
+package bridge
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The L2 blocks are sealed by the sequencer with the clique consensus. As a
+// result, their headers differ from the ones of Ethereum:
+//   - the extra-data is a 32 bytes vanity followed by the 65 bytes signature
+//     of the sequencer, see [L2BlockSigner];
+//   - the coinbase, the nonce and the mix digest are zero and there are no
+//     uncles;
+//   - the difficulty is [L2BlockDifficulty] as the sequencer is the only
+//     signer and is always in turn;
+//   - the forks after London are not activated: the headers have none of the
+//     withdrawals, blob gas and beacon root fields.
+const (
+	// L2BlockDifficulty is the difficulty of the sealed L2 blocks
+	L2BlockDifficulty = 2
+	l2ExtraVanity     = 32
+	l2ExtraSeal       = crypto.SignatureLength
+)
+
+// SealedL2Header returns the header of a block of a request as it was sealed
+// by the sequencer. The header of the RLP of a block in a request is not the
+// sealed header: its difficulty is zero and its gas used is set to the gas
+// limit. The function restores them, the gas used of the block is not part
+// of the request and has to be fetched from the node by the caller.
+func SealedL2Header(header *ethtypes.Header, gasUsed uint64) *ethtypes.Header {
+	res := ethtypes.CopyHeader(header)
+	res.Difficulty = big.NewInt(L2BlockDifficulty)
+	res.GasUsed = gasUsed
+	return res
+}
+
+// CheckL2Header returns an error if the header is not shaped as a sealed L2
+// header.
+func CheckL2Header(header *ethtypes.Header) error {
+	switch {
+	case len(header.Extra) != l2ExtraVanity+l2ExtraSeal:
+		return fmt.Errorf("the extra-data has %v bytes, expected %v", len(header.Extra), l2ExtraVanity+l2ExtraSeal)
+	case header.Difficulty == nil || header.Difficulty.Cmp(big.NewInt(L2BlockDifficulty)) != 0:
+		return fmt.Errorf("the difficulty is %v, expected %v", header.Difficulty, L2BlockDifficulty)
+	case header.Coinbase != (common.Address{}):
+		return fmt.Errorf("the coinbase %v is not zero", header.Coinbase.Hex())
+	case header.Nonce != (ethtypes.BlockNonce{}) || header.MixDigest != (common.Hash{}):
+		return fmt.Errorf("the nonce and the mix digest are not zero")
+	case header.UncleHash != ethtypes.EmptyUncleHash:
+		return fmt.Errorf("the block has uncles")
+	case header.BaseFee == nil:
+		return fmt.Errorf("the header has no base fee")
+	case header.WithdrawalsHash != nil || header.BlobGasUsed != nil || header.ExcessBlobGas != nil || header.ParentBeaconRoot != nil:
+		return fmt.Errorf("the header has fields of forks after London")
+	case header.GasUsed > header.GasLimit:
+		return fmt.Errorf("the gas used %v exceeds the gas limit %v", header.GasUsed, header.GasLimit)
+	}
+	return nil
+}
+
+// L2BlockHash returns the hash of a sealed L2 header. It is the hash the next
+// block refers to as its parent and the one carried by the logs of the block.
+// The function returns an error if the header is not shaped as a sealed L2
+// header, see [SealedL2Header] for the headers of the requests.
+func L2BlockHash(header *ethtypes.Header) (common.Hash, error) {
+	if err := CheckL2Header(header); err != nil {
+		return common.Hash{}, fmt.Errorf("block %v: %w", header.Number, err)
+	}
+	return header.Hash(), nil
+}
+
+// L2BlockSigner returns the address of the sequencer that sealed the header.
+// The signature does not cover itself, but covers all the other fields of the
+// header. A signer that is not the expected sequencer thus indicates a header
+// that was not restored correctly.
+func L2BlockSigner(header *ethtypes.Header) (common.Address, error) {
+
+	if len(header.Extra) < l2ExtraSeal {
+		return common.Address{}, fmt.Errorf("block %v: the extra-data has no seal", header.Number)
+	}
+
+	// The signed message is the hash of the header without the signature. As
+	// the header has none of the fields of the forks after London, this is
+	// the seal hash of clique.
+	unsealed := ethtypes.CopyHeader(header)
+	unsealed.Extra = unsealed.Extra[:len(unsealed.Extra)-l2ExtraSeal]
+
+	sig := header.Extra[len(header.Extra)-l2ExtraSeal:]
+	pub, err := crypto.Ecrecover(unsealed.Hash().Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("block %v: could not recover the signer: %w", header.Number, err)
+	}
+
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pub[1:])[12:])
+	return signer, nil
+}
+
+// CheckSealedHeaders checks the sealed headers of the blocks of the segment
+// against the canonical hashes: the first header refers to the parent of the
+// segment and the hash of each header is the canonical hash of its block. It
+// is meant for the coordinator to pre-validate a request before proving it.
+// The headers pin the hashes of the segment that are not known yet, in
+// particular the one of the last block.
+func (s *CanonicalSegment) CheckSealedHeaders(headers []*ethtypes.Header) error {
+
+	if len(headers) != len(s.Hashes) {
+		return fmt.Errorf("there are %v headers for a segment of %v blocks", len(headers), len(s.Hashes))
+	}
+
+	if headers[0].ParentHash != s.ParentHash {
+		return fmt.Errorf("the parent hash of the first block is %v, expected %v", headers[0].ParentHash.Hex(), s.ParentHash.Hex())
+	}
+
+	hashes := make([]common.Hash, len(headers))
+	for i := range headers {
+
+		if n := headers[i].Number; n == nil || !n.IsUint64() || n.Uint64() != s.FirstNumber+uint64(i) {
+			return fmt.Errorf("header #%v has number %v, expected %v", i, n, s.FirstNumber+uint64(i))
+		}
+
+		h, err := L2BlockHash(headers[i])
+		if err != nil {
+			return err
+		}
+
+		if s.Hashes[i] != (common.Hash{}) && s.Hashes[i] != h {
+			return fmt.Errorf("block #%v has hash %v, expected %v", i, h.Hex(), s.Hashes[i].Hex())
+		}
+		hashes[i] = h
+	}
+
+	copy(s.Hashes, hashes)
+	return nil
+}
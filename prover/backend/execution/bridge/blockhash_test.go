@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// l2BlockHashFixture is a header of a block of a real request along with the
+// gas used and the hash of the block on the chain. The hash is the parent hash
+// of the next block, or the block hash of its logs.
+type l2BlockHashFixture struct {
+	Source    string         `json:"source"`
+	HeaderRlp hexutil.Bytes  `json:"headerRlp"`
+	GasUsed   uint64         `json:"gasUsed"`
+	Hash      common.Hash    `json:"hash"`
+	Signer    common.Address `json:"signer"`
+}
+
+func readL2BlockHashFixtures(t *testing.T) []l2BlockHashFixture {
+	b, err := os.ReadFile("testdata/l2-block-hashes.json")
+	require.NoError(t, err)
+	var res []l2BlockHashFixture
+	require.NoError(t, json.Unmarshal(b, &res))
+	return res
+}
+
+func TestL2BlockHash(t *testing.T) {
+
+	for _, f := range readL2BlockHashFixtures(t) {
+
+		var header ethtypes.Header
+		require.NoError(t, rlp.DecodeBytes(f.HeaderRlp, &header), f.Source)
+
+		// the header of the request is not sealed
+		assert.NotEqual(t, f.Hash, header.Hash(), f.Source)
+		_, err := L2BlockHash(&header)
+		assert.Error(t, err, f.Source)
+
+		sealed := SealedL2Header(&header, f.GasUsed)
+		hash, err := L2BlockHash(sealed)
+		require.NoError(t, err, f.Source)
+		assert.Equal(t, f.Hash, hash, f.Source)
+
+		signer, err := L2BlockSigner(sealed)
+		require.NoError(t, err, f.Source)
+		assert.Equal(t, f.Signer, signer, f.Source)
+
+		// a wrong gas used changes the signer
+		signer, err = L2BlockSigner(SealedL2Header(&header, f.GasUsed+1))
+		require.NoError(t, err, f.Source)
+		assert.NotEqual(t, f.Signer, signer, f.Source)
+
+		// the input header is not modified
+		assert.Equal(t, header.GasLimit, header.GasUsed, f.Source)
+	}
+}
+
+func TestCheckSealedHeaders(t *testing.T) {
+
+	var (
+		fixtures = readL2BlockHashFixtures(t)
+		// blocks 40, 41 and 42 are consecutive
+		headers = make([]*ethtypes.Header, 3)
+		blocks  = make([]ethtypes.Block, 3)
+	)
+
+	for i := range headers {
+		var header ethtypes.Header
+		f := fixtures[i+1]
+		require.NoError(t, rlp.DecodeBytes(f.HeaderRlp, &header))
+		blocks[i] = *ethtypes.NewBlockWithHeader(&header)
+		headers[i] = SealedL2Header(&header, f.GasUsed)
+	}
+
+	segment, err := NewCanonicalSegment(blocks)
+	require.NoError(t, err)
+
+	require.NoError(t, segment.CheckSealedHeaders(headers))
+	assert.Equal(t, []common.Hash{fixtures[1].Hash, fixtures[2].Hash, fixtures[3].Hash}, segment.Hashes)
+
+	// a wrong gas used is detected on all blocks, including the last one as
+	// its hash was pinned by the first check
+	wrong := append([]*ethtypes.Header{}, headers...)
+	wrong[1] = SealedL2Header(headers[1], headers[1].GasUsed+1)
+	assert.Error(t, segment.CheckSealedHeaders(wrong))
+
+	wrong = append([]*ethtypes.Header{}, headers...)
+	wrong[2] = SealedL2Header(headers[2], headers[2].GasUsed+1)
+	assert.Error(t, segment.CheckSealedHeaders(wrong))
+
+	// the headers of another segment are rejected
+	assert.Error(t, segment.CheckSealedHeaders(headers[1:]))
+	shifted := append([]*ethtypes.Header{}, headers...)
+	shifted[0] = ethtypes.CopyHeader(headers[0])
+	shifted[0].Number = big.NewInt(39)
+	assert.Error(t, segment.CheckSealedHeaders(shifted))
+}
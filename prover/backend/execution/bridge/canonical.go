@@ -0,0 +1,149 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// CanonicalSegment is the sequence of consecutive blocks of a request,
+// identified by their hashes. It pins the bridge logs to the blocks they are
+// attached to: a tracer following a chain that was reorged may supply logs
+// emitted by a block of the abandoned branch. Such logs carry the number of a
+// block of the segment but the hash of another block, and the events parsed
+// from them must not end up in the prover's output.
+//
+// The header decoded from the RLP of a block of the request is not the sealed
+// header of the chain, so its hash is not the canonical hash of the block, see
+// [SealedL2Header]. Instead, the canonical hash of a block is the parent hash
+// of its child in the segment. The hash of the last block is unknown until a
+// log pins it, see [CanonicalSegment.CheckLog], or until the sealed headers
+// are checked, see [CanonicalSegment.CheckSealedHeaders].
+type CanonicalSegment struct {
+	// ParentHash is the hash of the parent of the first block
+	ParentHash  common.Hash
+	FirstNumber uint64
+	// Hashes are the canonical hashes of the blocks, the zero hash stands for
+	// a hash that is not known yet.
+	Hashes []common.Hash
+}
+
+// ReorgedLogError is returned when a bridge log does not come from the
+// canonical block it is attached to.
+type ReorgedLogError struct {
+	// Block is the position of the block in the segment
+	Block    int
+	LogIndex uint
+	TxHash   common.Hash
+	Reason   string
+}
+
+func (e *ReorgedLogError) Error() string {
+	return fmt.Sprintf(
+		"log #%v of tx %v attached to block #%v of the segment is not canonical: %v",
+		e.LogIndex, e.TxHash.Hex(), e.Block, e.Reason,
+	)
+}
+
+// CanonicalHashes returns the hashes of the blocks as given by the parent
+// hashes of their children. The hash of the last block is left to zero.
+func CanonicalHashes(blocks []ethtypes.Block) []common.Hash {
+	res := make([]common.Hash, len(blocks))
+	for i := 1; i < len(blocks); i++ {
+		res[i-1] = blocks[i].ParentHash()
+	}
+	return res
+}
+
+// NewCanonicalSegment returns the segment formed by the blocks. It returns an
+// error if the blocks are not numbered consecutively.
+func NewCanonicalSegment(blocks []ethtypes.Block) (*CanonicalSegment, error) {
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("the segment has no blocks")
+	}
+
+	s := &CanonicalSegment{
+		ParentHash:  blocks[0].ParentHash(),
+		FirstNumber: blocks[0].NumberU64(),
+		Hashes:      CanonicalHashes(blocks),
+	}
+
+	for i := range blocks {
+		if blocks[i].NumberU64() != s.FirstNumber+uint64(i) {
+			return nil, fmt.Errorf(
+				"block #%v has number %v, expected %v",
+				i, blocks[i].NumberU64(), s.FirstNumber+uint64(i),
+			)
+		}
+	}
+
+	return s, nil
+}
+
+// CheckLog returns a [ReorgedLogError] if the log was removed from the chain
+// or if it was emitted by another block than the i-th block of the segment.
+// As in the requests, the block hash of the log is optional; the block
+// number is not. When the hash of the block is not known, the first log
+// carrying one pins it and the next logs of the block must agree with it.
+func (s *CanonicalSegment) CheckLog(i int, log ethtypes.Log) error {
+
+	if i < 0 || i >= len(s.Hashes) {
+		return fmt.Errorf("block #%v is out of the segment of %v blocks", i, len(s.Hashes))
+	}
+
+	fail := func(format string, args ...any) error {
+		return &ReorgedLogError{
+			Block:    i,
+			LogIndex: log.Index,
+			TxHash:   log.TxHash,
+			Reason:   fmt.Sprintf(format, args...),
+		}
+	}
+
+	switch {
+	case log.Removed:
+		return fail("the log was removed by a reorg")
+	case log.BlockNumber != s.FirstNumber+uint64(i):
+		return fail("block number %v, expected %v", log.BlockNumber, s.FirstNumber+uint64(i))
+	case log.BlockHash == (common.Hash{}):
+	case s.Hashes[i] == (common.Hash{}):
+		s.Hashes[i] = log.BlockHash
+	case log.BlockHash != s.Hashes[i]:
+		return fail("block hash %v, expected %v", log.BlockHash.Hex(), s.Hashes[i].Hex())
+	}
+
+	return nil
+}
+
+// L2L1MessageHashes is as [L2L1MessageHashes] for the logs of the i-th block
+// of the segment. It fails on the first L2 -> L1 log that is not pinned to
+// the block.
+func (s *CanonicalSegment) L2L1MessageHashes(i int, logs []ethtypes.Log, l2BridgeAddress common.Address) ([]types.FullBytes32, error) {
+	for _, log := range logs {
+		if !isL2L1Log(log, l2BridgeAddress) {
+			continue
+		}
+		if err := s.CheckLog(i, log); err != nil {
+			return nil, err
+		}
+	}
+	return L2L1MessageHashes(logs, l2BridgeAddress), nil
+}
+
+// ExtractRollingHashUpdated is as [ExtractRollingHashUpdated] for the logs of
+// the i-th block of the segment. It fails on the first `RollingHashUpdated`
+// log that is not pinned to the block.
+func (s *CanonicalSegment) ExtractRollingHashUpdated(i int, logs []ethtypes.Log, l2BridgeAddress common.Address) ([]RollingHashUpdated, error) {
+	for _, log := range logs {
+		if !IsRollingHashUpdated(log, l2BridgeAddress) {
+			continue
+		}
+		if err := s.CheckLog(i, log); err != nil {
+			return nil, err
+		}
+	}
+	return ExtractRollingHashUpdated(logs, l2BridgeAddress), nil
+}
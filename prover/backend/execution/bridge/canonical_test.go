@@ -0,0 +1,99 @@
+package bridge
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/chainspec"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSegment(t *testing.T) {
+
+	var (
+		l2BridgeAddress = chainspec.Mainnet.L2MessageService
+		headers         = make([]*ethtypes.Header, 3)
+		parent          = common.Hash{0xaa}
+	)
+
+	for i := range headers {
+		headers[i] = &ethtypes.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(100 + i)),
+			Difficulty: big.NewInt(0),
+		}
+		parent = headers[i].Hash()
+	}
+
+	// the blocks are not copied as they embed an atomic
+	chain := func(order ...int) []ethtypes.Block {
+		blocks := make([]ethtypes.Block, len(order))
+		for i, o := range order {
+			b, err := rlp.EncodeToBytes(ethtypes.NewBlockWithHeader(headers[o]))
+			require.NoError(t, err)
+			require.NoError(t, rlp.DecodeBytes(b, &blocks[i]))
+		}
+		return blocks
+	}
+
+	blocks := chain(0, 1, 2)
+
+	segment, err := NewCanonicalSegment(blocks)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{0xaa}, segment.ParentHash)
+
+	// the logs are pinned to the second block
+	pinned := func() []ethtypes.Log {
+		logs := []ethtypes.Log{
+			(&RollingHashUpdated{MessageNumber: 3, RollingHash: types.FullBytes32{3}}).AsTypesLog(l2BridgeAddress),
+			(&RollingHashUpdated{MessageNumber: 4, RollingHash: types.FullBytes32{4}}).AsTypesLog(l2BridgeAddress),
+		}
+		for i := range logs {
+			logs[i].BlockNumber = 101
+			logs[i].BlockHash = blocks[1].Hash()
+		}
+		return logs
+	}
+
+	events, err := segment.ExtractRollingHashUpdated(1, pinned(), l2BridgeAddress)
+	require.NoError(t, err)
+	assert.Equal(t, ExtractRollingHashUpdated(pinned(), l2BridgeAddress), events)
+
+	// the block hash is optional
+	logs := pinned()
+	logs[0].BlockHash = common.Hash{}
+	_, err = segment.ExtractRollingHashUpdated(1, logs, l2BridgeAddress)
+	require.NoError(t, err)
+
+	// the logs of the other blocks are rejected
+	_, err = segment.ExtractRollingHashUpdated(2, pinned(), l2BridgeAddress)
+	var reorged *ReorgedLogError
+	require.True(t, errors.As(err, &reorged))
+	assert.Equal(t, 2, reorged.Block)
+
+	// a log of a reorged block with the same number
+	logs = pinned()
+	logs[1].BlockHash = common.Hash{0xbb}
+	_, err = segment.ExtractRollingHashUpdated(1, logs, l2BridgeAddress)
+	assert.ErrorContains(t, err, "block hash")
+
+	logs = pinned()
+	logs[1].Removed = true
+	_, err = segment.ExtractRollingHashUpdated(1, logs, l2BridgeAddress)
+	assert.ErrorContains(t, err, "removed")
+
+	// the logs that are not parsed are not checked
+	hashes, err := segment.L2L1MessageHashes(1, logs, l2BridgeAddress)
+	require.NoError(t, err)
+	assert.Empty(t, hashes)
+
+	// the blocks must be consecutive
+	_, err = NewCanonicalSegment(chain(0, 2, 1))
+	assert.ErrorContains(t, err, "has number")
+}
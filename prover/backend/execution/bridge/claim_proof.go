@@ -0,0 +1,190 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// L1L2MessageClaimProof links an L1 -> L2 message to the rolling hashes
+// reported on L2 by the `RollingHashUpdated` events. It contains everything
+// that is needed to recompute the rolling hash from an anchor (a rolling hash
+// reported by an event before the message or the genesis rolling hash) up to a
+// checkpoint (the first rolling hash reported by an event including the
+// message). Wallets and bridge UIs can use it to show that a message is
+// claimable on L2.
+type L1L2MessageClaimProof struct {
+	// MessageNumber is the number of the message whose inclusion is proven
+	MessageNumber int64 `json:"messageNumber"`
+	// MessageHash is the hash of the message whose inclusion is proven
+	MessageHash types.FullBytes32 `json:"messageHash"`
+	// Anchor is the rolling hash from which the rolling hash is recomputed. It
+	// is either reported by an event or the genesis rolling hash (zero, for
+	// message number 0).
+	Anchor RollingHashUpdated `json:"anchor"`
+	// MessageHashes lists the hashes of all the messages numbered from
+	// Anchor.MessageNumber+1 to Checkpoint.MessageNumber included.
+	MessageHashes []types.FullBytes32 `json:"messageHashes"`
+	// Checkpoint is the rolling hash reported by an event and that the
+	// recomputed rolling hash must match.
+	Checkpoint RollingHashUpdated `json:"checkpoint"`
+}
+
+// RollingHash returns the rolling hash obtained by appending the message hash
+// msgHash to the rolling hash prev. This mirrors the computation done by the L1
+// message service: keccak256(prev || msgHash).
+func RollingHash(prev, msgHash types.FullBytes32) types.FullBytes32 {
+	return types.FullBytes32(crypto.Keccak256Hash(prev[:], msgHash[:]))
+}
+
+// NewL1L2MessageClaimProof constructs the claim proof of the message numbered
+// messageNumber. events is the sequence of `RollingHashUpdated` events observed
+// on L2, sorted by message number. messageHashes is a contiguous range of L1 ->
+// L2 message hashes (as emitted by the `MessageSent` events on L1) starting at
+// message number firstMessageNumber. It must cover all the messages between the
+// anchor and the checkpoint selected for the message.
+//
+// The function returns an error if the inputs do not allow constructing the
+// proof or if the rolling hash recomputed from the message hashes does not
+// match the one reported by the checkpoint event.
+func NewL1L2MessageClaimProof(
+	events []RollingHashUpdated,
+	firstMessageNumber int64,
+	messageHashes []types.FullBytes32,
+	messageNumber int64,
+) (*L1L2MessageClaimProof, error) {
+
+	if messageNumber <= 0 {
+		return nil, fmt.Errorf("invalid message number %v, message numbers start at 1", messageNumber)
+	}
+
+	for i := 1; i < len(events); i++ {
+		if events[i].MessageNumber <= events[i-1].MessageNumber {
+			return nil, fmt.Errorf(
+				"the events are not sorted by message number: event #%v has number %v and event #%v has number %v",
+				i-1, events[i-1].MessageNumber, i, events[i].MessageNumber,
+			)
+		}
+	}
+
+	var (
+		anchor        = RollingHashUpdated{} // genesis
+		checkpointPos = -1
+	)
+
+	for i := range events {
+		if events[i].MessageNumber < messageNumber {
+			anchor = events[i]
+			continue
+		}
+		checkpointPos = i
+		break
+	}
+
+	if checkpointPos < 0 {
+		return nil, fmt.Errorf("message %v is not yet covered by any RollingHashUpdated event", messageNumber)
+	}
+
+	var (
+		checkpoint        = events[checkpointPos]
+		lastMessageNumber = firstMessageNumber + int64(len(messageHashes)) - 1
+	)
+
+	if firstMessageNumber > anchor.MessageNumber+1 || lastMessageNumber < checkpoint.MessageNumber {
+		return nil, fmt.Errorf(
+			"the provided message hashes cover the messages [%v, %v] but the messages [%v, %v] are required",
+			firstMessageNumber, lastMessageNumber, anchor.MessageNumber+1, checkpoint.MessageNumber,
+		)
+	}
+
+	start := anchor.MessageNumber + 1 - firstMessageNumber
+	stop := checkpoint.MessageNumber + 1 - firstMessageNumber
+
+	proof := &L1L2MessageClaimProof{
+		MessageNumber: messageNumber,
+		MessageHash:   messageHashes[messageNumber-firstMessageNumber],
+		Anchor:        anchor,
+		MessageHashes: append([]types.FullBytes32{}, messageHashes[start:stop]...),
+		Checkpoint:    checkpoint,
+	}
+
+	if err := proof.Check(); err != nil {
+		return nil, fmt.Errorf("inconsistent message hashes and events: %w", err)
+	}
+
+	return proof, nil
+}
+
+// Check verifies that the proof is self-consistent: the message is located
+// between the anchor and the checkpoint at the claimed position and the
+// rolling hash recomputed from the anchor matches the checkpoint. It does not
+// check that the anchor and the checkpoint were actually reported on L2, see
+// [L1L2MessageClaimProof.Verify] for that.
+func (p *L1L2MessageClaimProof) Check() error {
+
+	if p.Anchor.MessageNumber < 0 || p.Anchor.MessageNumber >= p.MessageNumber {
+		return fmt.Errorf("the anchor (message number %v) must precede the message %v", p.Anchor.MessageNumber, p.MessageNumber)
+	}
+
+	if p.Checkpoint.MessageNumber < p.MessageNumber {
+		return fmt.Errorf("the checkpoint (message number %v) must not precede the message %v", p.Checkpoint.MessageNumber, p.MessageNumber)
+	}
+
+	if p.Anchor.MessageNumber == 0 && p.Anchor.RollingHash != (types.FullBytes32{}) {
+		return errors.New("the genesis rolling hash must be zero")
+	}
+
+	if int64(len(p.MessageHashes)) != p.Checkpoint.MessageNumber-p.Anchor.MessageNumber {
+		return fmt.Errorf(
+			"expected %v message hashes between the anchor and the checkpoint, got %v",
+			p.Checkpoint.MessageNumber-p.Anchor.MessageNumber, len(p.MessageHashes),
+		)
+	}
+
+	if p.MessageHashes[p.MessageNumber-p.Anchor.MessageNumber-1] != p.MessageHash {
+		return fmt.Errorf("message %v has hash %v in the proof, expected %v",
+			p.MessageNumber, p.MessageHashes[p.MessageNumber-p.Anchor.MessageNumber-1].Hex(), p.MessageHash.Hex(),
+		)
+	}
+
+	rollingHash := p.Anchor.RollingHash
+	for i := range p.MessageHashes {
+		rollingHash = RollingHash(rollingHash, p.MessageHashes[i])
+	}
+
+	if rollingHash != p.Checkpoint.RollingHash {
+		return fmt.Errorf(
+			"recomputed rolling hash %v does not match the rolling hash %v of the checkpoint (message number %v)",
+			rollingHash.Hex(), p.Checkpoint.RollingHash.Hex(), p.Checkpoint.MessageNumber,
+		)
+	}
+
+	return nil
+}
+
+// Verify verifies the proof against the `RollingHashUpdated` events observed
+// on L2. On top of the checks of [L1L2MessageClaimProof.Check], it ensures that
+// the checkpoint and, unless it is the genesis, the anchor are among the
+// events.
+func (p *L1L2MessageClaimProof) Verify(events []RollingHashUpdated) error {
+
+	var anchorFound, checkpointFound bool
+	anchorFound = p.Anchor.MessageNumber == 0
+
+	for i := range events {
+		anchorFound = anchorFound || events[i] == p.Anchor
+		checkpointFound = checkpointFound || events[i] == p.Checkpoint
+	}
+
+	if !anchorFound {
+		return fmt.Errorf("the anchor (message number %v) is not among the events", p.Anchor.MessageNumber)
+	}
+
+	if !checkpointFound {
+		return fmt.Errorf("the checkpoint (message number %v) is not among the events", p.Checkpoint.MessageNumber)
+	}
+
+	return p.Check()
+}
@@ -0,0 +1,61 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestL1L2MessageClaimProof(t *testing.T) {
+
+	// messages numbered from 1 to 10 with events reported after the
+	// messages 3, 4 and 8.
+	var (
+		msgHashes    = make([]types.FullBytes32, 10)
+		rollingHashs = make([]types.FullBytes32, 11)
+		events       []RollingHashUpdated
+	)
+
+	for i := range msgHashes {
+		msgHashes[i][31] = byte(i + 1)
+		rollingHashs[i+1] = RollingHash(rollingHashs[i], msgHashes[i])
+	}
+
+	for _, n := range []int64{3, 4, 8} {
+		events = append(events, RollingHashUpdated{MessageNumber: n, RollingHash: rollingHashs[n]})
+	}
+
+	for msgNum := int64(1); msgNum <= 8; msgNum++ {
+		proof, err := NewL1L2MessageClaimProof(events, 1, msgHashes, msgNum)
+		require.NoError(t, err, "message %v", msgNum)
+		assert.NoError(t, proof.Verify(events), "message %v", msgNum)
+		assert.Equal(t, msgHashes[msgNum-1], proof.MessageHash)
+	}
+
+	// message 9 is not covered by any event yet
+	_, err := NewL1L2MessageClaimProof(events, 1, msgHashes, 9)
+	assert.Error(t, err)
+
+	// the message hashes must cover the range between anchor and checkpoint
+	_, err = NewL1L2MessageClaimProof(events, 6, msgHashes[5:], 6)
+	assert.Error(t, err)
+	proof, err := NewL1L2MessageClaimProof(events, 5, msgHashes[4:], 6)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), proof.Anchor.MessageNumber)
+	assert.Equal(t, int64(8), proof.Checkpoint.MessageNumber)
+
+	// tampering with the proof must be detected
+	tampered := *proof
+	tampered.MessageHash = msgHashes[0]
+	assert.Error(t, tampered.Verify(events))
+
+	tampered = *proof
+	tampered.MessageHashes = append([]types.FullBytes32{}, proof.MessageHashes...)
+	tampered.MessageHashes[3][0] ^= 1
+	assert.Error(t, tampered.Verify(events))
+
+	// the checkpoint must be among the events
+	assert.Error(t, proof.Verify(events[:2]))
+}
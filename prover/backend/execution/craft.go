@@ -2,20 +2,24 @@ package execution
 
 import (
 	"bytes"
+	"fmt"
 	"path"
 
 	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
 	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
 	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
-	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/batchhash"
 	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/config"
 	blob "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 // Craft prover's functional inputs
@@ -25,17 +29,25 @@ func CraftProverOutput(
 ) Response {
 
 	var (
-		l2BridgeAddress = cfg.Layer2.MsgSvcContract
+		chainSpec       = cfg.ChainSpec()
+		l2BridgeAddress = chainSpec.L2MessageService
 		blocks          = req.Blocks()
 		execDataBuf     = &bytes.Buffer{}
 		rsp             = Response{
 			BlocksData:           make([]BlockData, len(blocks)),
-			ChainID:              cfg.Layer2.ChainID,
-			L2BridgeAddress:      types.EthAddress(cfg.Layer2.MsgSvcContract),
+			ChainID:              chainSpec.ChainID,
+			L2BridgeAddress:      types.EthAddress(l2BridgeAddress),
 			MaxNbL2MessageHashes: cfg.TracesLimits.BlockL2L1Logs,
 		}
 	)
 
+	// The bridge events are only extracted from the logs pinned to the blocks
+	// of the request, the logs of a reorged branch abort the crafting.
+	segment, err := bridge.NewCanonicalSegment(blocks)
+	if err != nil {
+		utils.Panic("the blocks of the request do not form a chain segment: %v", err)
+	}
+
 	// Extract the data from the block
 	for i := range blocks {
 
@@ -50,9 +62,13 @@ func CraftProverOutput(
 
 			// Filter the logs L2 to L1, and hash them before sending them
 			// back to the coordinator.
-			l2l1MessageHashes = bridge.L2L1MessageHashes(logs, l2BridgeAddress)
+			l2l1MessageHashes, err = segment.L2L1MessageHashes(i, logs, l2BridgeAddress)
 		)
 
+		if err != nil {
+			utils.Panic("could not extract the L2 messages of block #%v: %v", i, err)
+		}
+
 		// This encodes the block as it will be by the compressor before running
 		// the compression algorithm.
 		blob.EncodeBlockForCompression(block, execDataBuf)
@@ -62,10 +78,12 @@ func CraftProverOutput(
 		rsp.BlocksData[i].FromAddresses = FromAddresses(block)
 		rsp.BlocksData[i].TimeStamp = block.Time()
 		rsp.BlocksData[i].L2ToL1MsgHashes = l2l1MessageHashes
-		rsp.BlocksData[i].BlockHash = types.FullBytes32(block.Hash())
 
 		// Also filters the RollingHashUpdated logs
-		events := bridge.ExtractRollingHashUpdated(logs, l2BridgeAddress)
+		events, err := segment.ExtractRollingHashUpdated(i, logs, l2BridgeAddress)
+		if err != nil {
+			utils.Panic("could not extract the rolling hash updates of block #%v: %v", i, err)
+		}
 		if len(events) > 0 {
 			rsp.BlocksData[i].LastRollingHashUpdatedEvent = events[len(events)-1]
 		}
@@ -78,20 +96,27 @@ func CraftProverOutput(
 		)
 	}
 
-	rsp.ExecDataChecksum = mimcHashLooselyPacked(execDataBuf.Bytes())
+	// The hash recomputed from the RLP of a block is not its hash on the
+	// chain. The canonical hashes are only known once all the logs pinned the
+	// hash of the last block, if any of them carries one.
+	for i := range rsp.BlocksData {
+		rsp.BlocksData[i].BlockHash = types.FullBytes32(segment.Hashes[i])
+	}
+
+	rsp.ExecDataChecksum = types.AsBytes32(batchhash.Sum(execDataBuf.Bytes()))
 
 	// Add into that the data of the state-manager
 	// Run the inspector and pass the parsed traces back to the caller.
 	// These traces may be used by the state-manager module depending on
 	// if the flag `PROVER_WITH_STATE_MANAGER`
-	inspectStateManagerTraces(req, &rsp)
+	inspectStateManagerTraces(req, blocks, cfg.Execution.StateLeafLayouts, &rsp)
 
 	// Value of the first blocks
 	rsp.FirstBlockNumber = utils.ToInt(blocks[0].NumberU64())
 
 	// Set the public input as part of the response immediately so that we can
 	// easily debug issues during the proving.
-	rsp.PublicInput = types.Bytes32(rsp.FuncInput().Sum())
+	rsp.PublicInput = field.HexElement(rsp.FuncInput(cfg).SumAsField())
 
 	return rsp
 }
@@ -101,9 +126,12 @@ func CraftProverOutput(
 // the parentStateRootHash. This behaviour can be altered by setting the field
 // `tolerate_state_root_hash_mismatch`, see its documentation. In case of
 // success, the function returns the decoded state-manager traces. Otherwise, it
-// panics.
+// panics. The tries of each block are checked against the leaf layout
+// scheduled for the block.
 func inspectStateManagerTraces(
 	req *Request,
+	blocks []ethtypes.Block,
+	layouts config.StateLeafLayouts,
 	resp *Response,
 ) {
 
@@ -117,8 +145,13 @@ func inspectStateManagerTraces(
 	for i := range traces {
 
 		if len(traces[i]) > 0 {
+			conf, err := stateTrieConfig(blocks, layouts, i)
+			if err != nil {
+				utils.Panic("%v", err)
+			}
+
 			// Run the trace inspection routine
-			old, new, err := statemanager.CheckTraces(traces[i])
+			old, new, err := statemanager.CheckTracesWithConfig(conf, traces[i])
 			// The trace must have been validated
 			if err != nil {
 				utils.Panic("error parsing the state manager traces : %v", err)
@@ -143,6 +176,23 @@ func inspectStateManagerTraces(
 	resp.ParentStateRootHash = firstParent.Hex()
 }
 
+// stateTrieConfig returns the config of the state tries of the i-th block of
+// the request, following the leaf layout scheduled for the block. The blocks
+// missing from the request use the original layout.
+func stateTrieConfig(blocks []ethtypes.Block, layouts config.StateLeafLayouts, i int) (*smt.Config, error) {
+
+	if i >= len(blocks) {
+		return statemanager.MIMC_CONFIG, nil
+	}
+
+	layout := layouts.At(blocks[i].NumberU64())
+	if err := accumulator.ValidateLeafLayout(layout); err != nil {
+		return nil, fmt.Errorf("block %v: %w", blocks[i].NumberU64(), err)
+	}
+
+	return statemanager.ConfigForLeafLayout(layout), nil
+}
+
 func (req *Request) collectSignatures() ([]ethereum.Signature, [][32]byte) {
 
 	var (
@@ -173,8 +223,10 @@ func (req *Request) collectSignatures() ([]ethereum.Signature, [][32]byte) {
 // FuncInput are all the relevant fields parsed by the prover that
 // are functionally useful to contextualize what the proof is proving. This
 // is used by the aggregation circuit to ensure that the execution proofs
-// relate to consecutive Linea block execution.
-func (rsp *Response) FuncInput() *execution.FunctionalPublicInput {
+// relate to consecutive Linea block execution. The digest of the prover
+// metadata is included if they are enabled in the config, and the one of the
+// traces limits if it is set in the response.
+func (rsp *Response) FuncInput(cfg *config.Config) *execution.FunctionalPublicInput {
 
 	var (
 		firstBlock = &rsp.BlocksData[0]
@@ -206,28 +258,33 @@ func (rsp *Response) FuncInput() *execution.FunctionalPublicInput {
 		fi.FinalRollingHashNumber = uint64(lastRHEvent.MessageNumber)
 	}
 
+	if cfg.PublicInputInterconnection.ProverMetadata {
+		fi.ProverMetadataDigest = execution.ProverMetadataDigest(cfg)
+	}
+
+	if rsp.TracesLimitsDigest != "" {
+		digest, err := utils.HexDecodeString(rsp.TracesLimitsDigest)
+		if err != nil {
+			utils.Panic("invalid traces limits digest %q: %v", rsp.TracesLimitsDigest, err)
+		}
+		fi.TracesLimitsDigest = digest
+	}
+
 	return fi
 }
 
 func NewWitness(cfg *config.Config, req *Request, rsp *Response) *Witness {
 	txSignatures, txHashes := req.collectSignatures()
+	chainSpec := cfg.ChainSpec()
 	return &Witness{
 		ZkEVM: &zkevm.Witness{
 			ExecTracesFPath: path.Join(cfg.Execution.ConflatedTracesDir, req.ConflatedExecutionTracesFile),
 			SMTraces:        req.StateManagerTraces(),
 			TxSignatures:    txSignatures,
 			TxHashes:        txHashes,
-			L2BridgeAddress: cfg.Layer2.MsgSvcContract,
-			ChainID:         cfg.Layer2.ChainID,
+			L2BridgeAddress: chainSpec.L2MessageService,
+			ChainID:         chainSpec.ChainID,
 		},
-		FuncInp: rsp.FuncInput(),
+		FuncInp: rsp.FuncInput(cfg),
 	}
 }
-
-// mimcHashLooselyPacked hashes the input stream b using the MiMC hash function
-// encoding each slice of 31 bytes into a field element separately.
-func mimcHashLooselyPacked(b []byte) types.Bytes32 {
-	var buf [32]byte
-	gnarkutil.ChecksumLooselyPackedBytes(b, buf[:], mimc.NewMiMC())
-	return types.AsBytes32(buf[:])
-}
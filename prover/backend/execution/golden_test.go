@@ -0,0 +1,144 @@
+package execution
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goldenDir is the directory of the golden fixtures. They are the requests of
+// small real blocks and the responses that the prover returned for them.
+const goldenDir = "../../../testdata/prover-v2/prover-execution"
+
+// goldenBridgeAddress is the address of the L2 message service of the network
+// on which the golden blocks were produced.
+var goldenBridgeAddress = common.HexToAddress("0xe537D669CA013d86EBeF1D64e40fC74CADC91987")
+
+// goldenFixture is a request paired with its expected response
+type goldenFixture struct {
+	Name     string
+	Request  *Request
+	Expected *goldenResponse
+}
+
+// goldenResponse is the subset of the [Response] checked by the golden tests.
+// The responses of the fixtures were generated by an earlier version of the
+// prover which returned the from addresses as a single hex string.
+type goldenResponse struct {
+	ParentStateRootHash string `json:"parentStateRootHash"`
+	FirstBlockNumber    int    `json:"firstBlockNumber"`
+	BlocksData          []struct {
+		RlpEncodedTransactions []string      `json:"rlpEncodedTransactions"`
+		TimeStamp              uint64        `json:"timestamp"`
+		RootHash               types.Bytes32 `json:"rootHash"`
+		FromAddresses          string        `json:"fromAddresses"`
+	} `json:"blocksData"`
+}
+
+// goldenFixtures loads all the golden fixtures. The response of a request
+// "<start>-<end>-etv..-getZkProof.json" is "<start>-<end>-getZkProof.json".
+func goldenFixtures(t *testing.T) []goldenFixture {
+
+	reqFiles, err := filepath.Glob(filepath.Join(goldenDir, "requests", "*-getZkProof.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, reqFiles, "no golden fixtures found in %v", goldenDir)
+
+	res := make([]goldenFixture, 0, len(reqFiles))
+	for _, reqFile := range reqFiles {
+
+		var (
+			base    = filepath.Base(reqFile)
+			parts   = strings.SplitN(base, "-", 3)
+			name    = parts[0] + "-" + parts[1]
+			rspFile = filepath.Join(goldenDir, "responses", name+"-getZkProof.json")
+			fix     = goldenFixture{Name: name, Request: &Request{}, Expected: &goldenResponse{}}
+		)
+
+		readJSON(t, reqFile, fix.Request)
+		readJSON(t, rspFile, fix.Expected)
+		res = append(res, fix)
+	}
+
+	return res
+}
+
+func readJSON(t *testing.T, file string, v any) {
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, json.NewDecoder(f).Decode(v), "could not decode %v", file)
+}
+
+// goldenConfig returns the minimal config needed to craft the prover outputs
+// of the golden fixtures.
+func goldenConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Layer2.ChainID = 59139
+	cfg.Layer2.MsgSvcContract = goldenBridgeAddress
+	cfg.TracesLimits.BlockL2L1Logs = 16
+	cfg.Execution.ConflatedTracesDir = filepath.Join(goldenDir, "..", "conflated")
+	return cfg
+}
+
+// TestGolden checks that the functional outputs crafted from the requests of
+// the golden fixtures match the ones that the prover returned for them: the
+// state root hashes, the timestamps, the transactions and their senders.
+//
+// The L2 to L1 messages are not compared: the responses were generated with
+// another message service in the config and report none although some of the
+// blocks emit MessageSent events.
+func TestGolden(t *testing.T) {
+
+	cfg := goldenConfig()
+
+	for _, fix := range goldenFixtures(t) {
+		t.Run(fix.Name, func(t *testing.T) {
+
+			var (
+				out = CraftProverOutput(cfg, fix.Request)
+				exp = fix.Expected
+			)
+
+			assert.Equal(t, exp.ParentStateRootHash, out.ParentStateRootHash)
+			assert.Equal(t, exp.FirstBlockNumber, out.FirstBlockNumber)
+			require.Len(t, out.BlocksData, len(exp.BlocksData))
+
+			for i := range exp.BlocksData {
+				var (
+					expBlock = exp.BlocksData[i]
+					outBlock = out.BlocksData[i]
+				)
+
+				for _, log := range fix.Request.LogsForBlock(i) {
+					assert.Equalf(t, types.FullBytes32(log.BlockHash), outBlock.BlockHash, "hash of block %v", i)
+				}
+				assert.Equalf(t, expBlock.RootHash, outBlock.RootHash, "root hash of block %v", i)
+				assert.Equalf(t, expBlock.TimeStamp, outBlock.TimeStamp, "timestamp of block %v", i)
+				assert.Equalf(t, expBlock.RlpEncodedTransactions, outBlock.RlpEncodedTransactions, "transactions of block %v", i)
+				assert.Equalf(t, expBlock.FromAddresses, concatAddresses(outBlock.FromAddresses), "from addresses of block %v", i)
+			}
+
+			// The public input must be reproducible
+			assert.Equal(t, out.PublicInput, CraftProverOutput(cfg, fix.Request).PublicInput)
+		})
+	}
+}
+
+// concatAddresses returns the addresses concatenated in a single 0x-prefixed
+// hex string.
+func concatAddresses(addrs []types.EthAddress) string {
+	res := "0x"
+	for i := range addrs {
+		res += hex.EncodeToString(addrs[i][:])
+	}
+	return res
+}
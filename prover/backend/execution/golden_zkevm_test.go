@@ -0,0 +1,117 @@
+//go:build golden_zkevm
+
+package execution
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests of this file run the zkEVM prover on the golden fixtures. They
+// are behind the `golden_zkevm` build tag as they compile the whole zkEVM,
+// which takes several minutes even with the dummy compiler:
+//
+//	GOLDEN_CONFIG=path/to/config.toml go test -tags golden_zkevm -run TestGoldenZkEvm ./backend/execution/
+//
+// The config provides the traces limits and the SIS parameters. Setting
+// GOLDEN_FULL=1 additionally runs the full compilation suite.
+//
+// The conflated traces shipped with the fixtures are JSON traces of a former
+// arithmetization, which the zkEVM no longer reads: the ".lt" traces of the
+// fixtures must be generated by the tracer, for the arithmetization embedded
+// in the prover, into the directory given by GOLDEN_TRACES_DIR (the
+// conflated traces directory of the fixtures by default). The fixtures
+// without ".lt" traces are skipped, and the test fails if none has them so
+// that it cannot pass without proving anything.
+
+// goldenZkEvmConfig loads the config given by GOLDEN_CONFIG
+func goldenZkEvmConfig(t *testing.T) *config.Config {
+
+	file := os.Getenv("GOLDEN_CONFIG")
+	if file == "" {
+		t.Skip("GOLDEN_CONFIG is not set")
+	}
+
+	cfg, err := config.NewConfigFromFile(file)
+	require.NoError(t, err)
+
+	golden := goldenConfig()
+	cfg.Execution.ConflatedTracesDir = golden.Execution.ConflatedTracesDir
+	if dir := os.Getenv("GOLDEN_TRACES_DIR"); dir != "" {
+		cfg.Execution.ConflatedTracesDir = dir
+	}
+	cfg.Layer2.MsgSvcContract = golden.Layer2.MsgSvcContract
+	return cfg
+}
+
+// goldenLtTraces returns the ".lt" traces file of the fixture or the empty
+// string if there is none.
+func goldenLtTraces(t *testing.T, cfg *config.Config, fix goldenFixture) string {
+	pattern := strings.SplitN(fix.Request.ConflatedExecutionTracesFile, ".", 2)[0] + ".conflated.*.lt"
+	matches, err := filepath.Glob(filepath.Join(cfg.Execution.ConflatedTracesDir, pattern))
+	require.NoError(t, err)
+	if len(matches) == 0 {
+		return ""
+	}
+	return filepath.Base(matches[0])
+}
+
+// runGoldenZkEvm proves and verifies the golden fixtures with the zkEVM
+// returned by getZkEvm. The zkEVM is only compiled if at least one fixture
+// has its traces.
+func runGoldenZkEvm(t *testing.T, getZkEvm func(cfg *config.Config) (*zkevm.ZkEvm, error)) {
+
+	var (
+		cfg      = goldenZkEvmConfig(t)
+		nbProven = 0
+	)
+
+	for _, fix := range goldenFixtures(t) {
+		t.Run(fix.Name, func(t *testing.T) {
+
+			traces := goldenLtTraces(t, cfg, fix)
+			if traces == "" {
+				t.Skipf("no .lt traces for %v in %v", fix.Name, cfg.Execution.ConflatedTracesDir)
+			}
+			nbProven++
+
+			fix.Request.ConflatedExecutionTracesFile = traces
+
+			z, err := getZkEvm(cfg)
+			require.NoError(t, err)
+
+			var (
+				out   = CraftProverOutput(cfg, fix.Request)
+				w     = NewWitness(cfg, fix.Request, &out)
+				proof = z.ProveInner(w.ZkEVM)
+			)
+
+			require.NoError(t, z.VerifyInner(proof))
+		})
+	}
+
+	if nbProven == 0 {
+		t.Fatalf("none of the fixtures has .lt traces in %v, see GOLDEN_TRACES_DIR", cfg.Execution.ConflatedTracesDir)
+	}
+}
+
+func TestGoldenZkEvm(t *testing.T) {
+	runGoldenZkEvm(t, func(cfg *config.Config) (*zkevm.ZkEvm, error) {
+		return zkevm.FullZkEVMCheckOnly(&cfg.TracesLimits)
+	})
+}
+
+func TestGoldenZkEvmFull(t *testing.T) {
+	if os.Getenv("GOLDEN_FULL") != "1" {
+		t.Skip("GOLDEN_FULL is not set")
+	}
+	runGoldenZkEvm(t, func(cfg *config.Config) (*zkevm.ZkEvm, error) {
+		return zkevm.FullZkEvm(&cfg.TracesLimits, cfg.Execution.SIS.Params())
+	})
+}
@@ -0,0 +1,246 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// WitnessMismatch reports a value claimed in the request that does not match
+// the one recomputed from the raw witness.
+type WitnessMismatch struct {
+	// Field locates the claimed value in the request, e.g.
+	// "blocksData[2].bridgeLogs[0].blockHash".
+	Field      string
+	Claimed    string
+	Recomputed string
+}
+
+func (m *WitnessMismatch) Error() string {
+	return fmt.Sprintf("%v: claimed %v, recomputed %v", m.Field, m.Claimed, m.Recomputed)
+}
+
+// CheckWitness is a pre-flight check run before proving. It recomputes the
+// state root hashes, the block hashes and the L1 -> L2 message rolling hashes
+// from the raw witness and compares them with the values claimed in the
+// request (by the tracer or by the state-manager). Such inconsistencies would
+// otherwise only surface at the end of the proving as unsatisfied
+// constraints.
+//
+// The state-manager traces of each block are checked against the leaf layout
+// scheduled for the block in layouts.
+//
+// The returned error joins all the [WitnessMismatch] found, so that they can be
+// reported at once.
+func CheckWitness(l2BridgeAddress common.Address, layouts config.StateLeafLayouts, req *Request) error {
+
+	blocks, err := req.decodeBlocks()
+	if err != nil {
+		return err
+	}
+
+	if len(blocks) == 0 {
+		return errors.New("the request does not have any block")
+	}
+
+	errs := checkStateRootHashes(req, blocks, layouts)
+	errs = append(errs, checkBlockHashes(req, blocks)...)
+	errs = append(errs, checkRollingHashes(req, l2BridgeAddress)...)
+
+	return errors.Join(errs...)
+}
+
+// checkStateRootHashes verifies the state-manager traces of every block and
+// checks that they form a chain starting from the claimed parent state root
+// hash.
+func checkStateRootHashes(req *Request, blocks []ethtypes.Block, layouts config.StateLeafLayouts) []error {
+
+	var (
+		errs   []error
+		traces = req.StateManagerTraces()
+		parent = req.ZkParentStateRootHash
+		field  = "zkParentStateRootHash"
+	)
+
+	if len(traces) > len(blocks) {
+		errs = append(errs, &WitnessMismatch{
+			Field:      "len(zkStateMerkleProof)",
+			Claimed:    fmt.Sprint(len(traces)),
+			Recomputed: fmt.Sprint(len(blocks)),
+		})
+	}
+
+	for i := range traces {
+
+		if len(traces[i]) == 0 {
+			continue
+		}
+
+		conf, err := stateTrieConfig(blocks, layouts, i)
+		if err != nil {
+			return append(errs, fmt.Errorf("zkStateMerkleProof[%v]: %w", i, err))
+		}
+
+		old, new, err := statemanager.CheckTracesWithConfig(conf, traces[i])
+		if err != nil {
+			// The chain of root hashes cannot be recomputed past this block
+			return append(errs, fmt.Errorf("zkStateMerkleProof[%v]: invalid traces: %w", i, err))
+		}
+
+		if old != parent {
+			errs = append(errs, &WitnessMismatch{
+				Field:      field,
+				Claimed:    parent.Hex(),
+				Recomputed: old.Hex(),
+			})
+		}
+
+		parent = new
+		field = fmt.Sprintf("zkStateMerkleProof[%v].newStateRootHash", i)
+	}
+
+	logrus.Debugf("pre-flight: recomputed the final state root hash %v", parent.Hex())
+	return errs
+}
+
+// checkBlockHashes checks that the blocks are consecutive and that the bridge
+// logs are attached to the canonical blocks. The hash recomputed from the RLP
+// of a block is not its canonical hash, see [bridge.CanonicalSegment], so the
+// logs are checked against the parent hash of the next block. The logs of the
+// last block must agree on a single hash.
+func checkBlockHashes(req *Request, blocks []ethtypes.Block) []error {
+
+	var (
+		errs   []error
+		hashes = bridge.CanonicalHashes(blocks)
+	)
+
+	for i := range blocks {
+
+		block := &blocks[i]
+
+		if i > 0 {
+			prev := &blocks[i-1]
+
+			if block.NumberU64() != prev.NumberU64()+1 {
+				errs = append(errs, &WitnessMismatch{
+					Field:      fmt.Sprintf("blocksData[%v].number", i),
+					Claimed:    fmt.Sprint(block.NumberU64()),
+					Recomputed: fmt.Sprint(prev.NumberU64() + 1),
+				})
+			}
+		}
+
+		for j, log := range req.LogsForBlock(i) {
+
+			// The tracer reports the logs of a reorged branch as removed
+			if log.Removed {
+				errs = append(errs, &WitnessMismatch{
+					Field:      fmt.Sprintf("blocksData[%v].bridgeLogs[%v].removed", i, j),
+					Claimed:    "true",
+					Recomputed: "false",
+				})
+			}
+
+			switch {
+			// The block hash is optional in the logs
+			case log.BlockHash == (common.Hash{}):
+			case hashes[i] == (common.Hash{}):
+				hashes[i] = log.BlockHash
+			case log.BlockHash != hashes[i]:
+				errs = append(errs, &WitnessMismatch{
+					Field:      fmt.Sprintf("blocksData[%v].bridgeLogs[%v].blockHash", i, j),
+					Claimed:    log.BlockHash.Hex(),
+					Recomputed: hashes[i].Hex(),
+				})
+			}
+
+			if log.BlockNumber != block.NumberU64() {
+				errs = append(errs, &WitnessMismatch{
+					Field:      fmt.Sprintf("blocksData[%v].bridgeLogs[%v].blockNumber", i, j),
+					Claimed:    fmt.Sprint(log.BlockNumber),
+					Recomputed: fmt.Sprint(block.NumberU64()),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// checkRollingHashes recomputes the rolling hash reported by every
+// `RollingHashUpdated` event from the one reported by the previous event and
+// from the message hashes anchored in between. The first event of the request
+// cannot be recomputed as its anchor is not part of the witness. The check is
+// also skipped when the number of anchored hashes does not match the
+// increment of the message number as the contract silently ignores the
+// hashes that were already anchored.
+func checkRollingHashes(req *Request, l2BridgeAddress common.Address) []error {
+
+	var (
+		errs    []error
+		prev    *bridge.RollingHashUpdated
+		pending []types.FullBytes32
+	)
+
+	for i := range req.BlocksData {
+		for j, log := range req.LogsForBlock(i) {
+
+			if bridge.IsL1L2MessageHashesAddedToInbox(log, l2BridgeAddress) {
+				hashes, err := bridge.ParseL1L2MessageHashesAddedToInbox(log)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("blocksData[%v].bridgeLogs[%v]: %w", i, j, err))
+					continue
+				}
+				pending = append(pending, hashes...)
+				continue
+			}
+
+			if !bridge.IsRollingHashUpdated(log, l2BridgeAddress) {
+				continue
+			}
+
+			event := bridge.ExtractRollingHashUpdated([]ethtypes.Log{log}, l2BridgeAddress)[0]
+			field := fmt.Sprintf("blocksData[%v].bridgeLogs[%v]", i, j)
+
+			switch {
+			case prev == nil:
+			case event.MessageNumber <= prev.MessageNumber:
+				errs = append(errs, &WitnessMismatch{
+					Field:      field + ".messageNumber",
+					Claimed:    fmt.Sprint(event.MessageNumber),
+					Recomputed: fmt.Sprintf("> %v", prev.MessageNumber),
+				})
+			case event.MessageNumber-prev.MessageNumber != int64(len(pending)):
+				logrus.Debugf(
+					"pre-flight: skipping the rolling hash check of %v, %v hashes were anchored for %v messages",
+					field, len(pending), event.MessageNumber-prev.MessageNumber,
+				)
+			default:
+				rollingHash := prev.RollingHash
+				for _, h := range pending {
+					rollingHash = bridge.RollingHash(rollingHash, h)
+				}
+
+				if rollingHash != event.RollingHash {
+					errs = append(errs, &WitnessMismatch{
+						Field:      field + ".rollingHash",
+						Claimed:    event.RollingHash.Hex(),
+						Recomputed: rollingHash.Hex(),
+					})
+				}
+			}
+
+			prev, pending = &event, nil
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,229 @@
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/chainspec"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var preflightBridgeAddress = chainspec.Mainnet.L2MessageService
+
+// preflightRequest returns a consistent request of 3 blocks whose first block
+// anchors 2 L1 -> L2 messages.
+func preflightRequest(t *testing.T) *Request {
+
+	f, err := os.Open("statemanager/testdata/insert-2-accounts.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	var shomei statemanager.ShomeiOutput
+	require.NoError(t, json.NewDecoder(f).Decode(&shomei))
+
+	var (
+		req = &Request{
+			ZkParentStateRootHash: shomei.Result.ZkParentStateRootHash,
+			ZkStateMerkleProof:    shomei.Result.ZkStateMerkleProof,
+		}
+		parent  common.Hash
+		anchor  = types.FullBytes32{1}
+		msgs    = []types.FullBytes32{{2}, {3}}
+		rolling = bridge.RollingHash(bridge.RollingHash(anchor, msgs[0]), msgs[1])
+	)
+
+	req.BlocksData = make([]struct {
+		Rlp        string         `json:"rlp"`
+		BridgeLogs []ethtypes.Log `json:"bridgeLogs"`
+	}, 3)
+
+	for i := range req.BlocksData {
+		block := ethtypes.NewBlockWithHeader(&ethtypes.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(100 + i)),
+			Difficulty: big.NewInt(0),
+		})
+		b, err := rlp.EncodeToBytes(block)
+		require.NoError(t, err)
+		req.BlocksData[i].Rlp = hexutil.Encode(b)
+		parent = block.Hash()
+	}
+
+	hashes := make([]byte, 64, 128)
+	hashes[31], hashes[63] = 32, 2
+	hashes = append(hashes, msgs[0][:]...)
+	hashes = append(hashes, msgs[1][:]...)
+
+	req.BlocksData[0].BridgeLogs = []ethtypes.Log{
+		(&bridge.RollingHashUpdated{MessageNumber: 10, RollingHash: anchor}).AsTypesLog(preflightBridgeAddress),
+		{
+			Address: preflightBridgeAddress,
+			Topics:  []common.Hash{bridge.L1L2MessageHashesAddedToInboxTopic0()},
+			Data:    hashes,
+		},
+		(&bridge.RollingHashUpdated{MessageNumber: 12, RollingHash: rolling}).AsTypesLog(preflightBridgeAddress),
+	}
+
+	blocks, err := req.decodeBlocks()
+	require.NoError(t, err)
+	for i := range req.BlocksData[0].BridgeLogs {
+		req.BlocksData[0].BridgeLogs[i].BlockHash = blocks[0].Hash()
+		req.BlocksData[0].BridgeLogs[i].BlockNumber = blocks[0].NumberU64()
+	}
+
+	return req
+}
+
+// mismatchedFields returns the fields of the [WitnessMismatch] joined in err
+func mismatchedFields(t *testing.T, err error) []string {
+	require.Error(t, err)
+	res := []string{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var m *WitnessMismatch
+		require.True(t, errors.As(e, &m), "unexpected error: %v", e)
+		res = append(res, m.Field)
+	}
+	return res
+}
+
+func TestCheckWitness(t *testing.T) {
+
+	require.NoError(t, CheckWitness(preflightBridgeAddress, nil, preflightRequest(t)))
+
+	t.Run("state-root-hash", func(t *testing.T) {
+		req := preflightRequest(t)
+		req.ZkParentStateRootHash[31] ^= 1
+		err := CheckWitness(preflightBridgeAddress, nil, req)
+		assert.Equal(t, []string{"zkParentStateRootHash"}, mismatchedFields(t, err))
+		assert.ErrorContains(t, err, "claimed "+req.ZkParentStateRootHash.Hex())
+	})
+
+	t.Run("block-hashes", func(t *testing.T) {
+		req := preflightRequest(t)
+		req.BlocksData[0].BridgeLogs[1].BlockHash[0] ^= 1
+		req.BlocksData[0].BridgeLogs[2].BlockNumber = 101
+		req.BlocksData[0].BridgeLogs[2].Removed = true
+		assert.Equal(t, []string{
+			"blocksData[0].bridgeLogs[1].blockHash",
+			"blocksData[0].bridgeLogs[2].removed",
+			"blocksData[0].bridgeLogs[2].blockNumber",
+		}, mismatchedFields(t, CheckWitness(preflightBridgeAddress, nil, req)))
+	})
+
+	t.Run("reorg", func(t *testing.T) {
+		// the next block is not the child of the block of the logs
+		req := preflightRequest(t)
+		req.BlocksData[1], req.BlocksData[2] = req.BlocksData[2], req.BlocksData[1]
+		assert.Equal(t, []string{
+			"blocksData[0].bridgeLogs[0].blockHash",
+			"blocksData[0].bridgeLogs[1].blockHash",
+			"blocksData[0].bridgeLogs[2].blockHash",
+			"blocksData[1].number",
+			"blocksData[2].number",
+		}, mismatchedFields(t, CheckWitness(preflightBridgeAddress, nil, req)))
+
+		// the logs of the last block must agree on its hash
+		req = preflightRequest(t)
+		req.BlocksData[0].BridgeLogs, req.BlocksData[2].BridgeLogs = nil, req.BlocksData[0].BridgeLogs
+		for i := range req.BlocksData[2].BridgeLogs {
+			req.BlocksData[2].BridgeLogs[i].BlockNumber = 102
+		}
+		require.NoError(t, CheckWitness(preflightBridgeAddress, nil, req))
+		req.BlocksData[2].BridgeLogs[2].BlockHash[0] ^= 1
+		assert.Equal(t,
+			[]string{"blocksData[2].bridgeLogs[2].blockHash"},
+			mismatchedFields(t, CheckWitness(preflightBridgeAddress, nil, req)),
+		)
+	})
+
+	t.Run("rolling-hash", func(t *testing.T) {
+		req := preflightRequest(t)
+		req.BlocksData[0].BridgeLogs[1].Data[127] ^= 1
+		assert.Equal(t,
+			[]string{"blocksData[0].bridgeLogs[2].rollingHash"},
+			mismatchedFields(t, CheckWitness(preflightBridgeAddress, nil, req)),
+		)
+
+		// Already anchored messages are not counted by the contract so the
+		// rolling hash cannot be recomputed.
+		req = preflightRequest(t)
+		req.BlocksData[0].BridgeLogs[1].Data[63] = 1
+		require.NoError(t, CheckWitness(preflightBridgeAddress, nil, req))
+	})
+
+	t.Run("invalid-rlp", func(t *testing.T) {
+		req := preflightRequest(t)
+		req.BlocksData[1].Rlp = "0x1234"
+		assert.ErrorContains(t, CheckWitness(preflightBridgeAddress, nil, req), "block #1")
+	})
+}
+
+// TestCheckBlockHashesOfTheChain checks the block hashes of the logs against
+// the headers of consecutive blocks of a real request. The hash recomputed
+// from the RLP of a block is not the hash of the block on the chain, the one
+// carried by its logs.
+func TestCheckBlockHashesOfTheChain(t *testing.T) {
+
+	b, err := os.ReadFile("bridge/testdata/l2-block-hashes.json")
+	require.NoError(t, err)
+
+	var fixtures []struct {
+		HeaderRlp hexutil.Bytes `json:"headerRlp"`
+		Hash      common.Hash   `json:"hash"`
+	}
+	require.NoError(t, json.Unmarshal(b, &fixtures))
+
+	// blocks 40, 41 and 42 are consecutive
+	fixtures = fixtures[1:4]
+
+	var (
+		req    = &Request{}
+		blocks = make([]ethtypes.Block, len(fixtures))
+	)
+
+	req.BlocksData = make([]struct {
+		Rlp        string         `json:"rlp"`
+		BridgeLogs []ethtypes.Log `json:"bridgeLogs"`
+	}, len(fixtures))
+
+	for i := range fixtures {
+		var header ethtypes.Header
+		require.NoError(t, rlp.DecodeBytes(fixtures[i].HeaderRlp, &header))
+		blocks[i] = *ethtypes.NewBlockWithHeader(&header)
+		require.NotEqual(t, fixtures[i].Hash, blocks[i].Hash(), "the header of the request is not sealed")
+	}
+
+	withLogs := func(hash func(i int) common.Hash) *Request {
+		for i := range req.BlocksData {
+			req.BlocksData[i].BridgeLogs = []ethtypes.Log{{
+				Address:     preflightBridgeAddress,
+				BlockNumber: blocks[i].NumberU64(),
+				BlockHash:   hash(i),
+			}}
+		}
+		return req
+	}
+
+	// the logs carrying the hashes of the chain are accepted, including the
+	// ones of the last block
+	assert.Empty(t, checkBlockHashes(withLogs(func(i int) common.Hash { return fixtures[i].Hash }), blocks))
+
+	// the logs carrying the recomputed hashes are rejected, except the ones of
+	// the last block as its hash is not known
+	errs := checkBlockHashes(withLogs(func(i int) common.Hash { return blocks[i].Hash() }), blocks)
+	assert.Equal(t,
+		[]string{"blocksData[0].bridgeLogs[0].blockHash", "blocksData[1].bridgeLogs[0].blockHash"},
+		mismatchedFields(t, errors.Join(errs...)),
+	)
+}
@@ -1,14 +1,25 @@
 package execution
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
 	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/profiling"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,12 +28,24 @@ type Witness struct {
 	ZkEVM   *zkevm.Witness
 }
 
-func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
+func Prove(cfg *config.Config, req *Request, large bool) (_ *Response, err error) {
+
+	// The failures of the zkEVM prover are returned as errors carrying the
+	// round, the step and the module that panicked. The other panics are
+	// propagated.
+	defer wizard.RecoverProverError(&err)
+
 	traces := &cfg.TracesLimits
 	if large {
 		traces = &cfg.TracesLimitsLarge
 	}
 
+	// Catch the inconsistencies of the witness before spending hours in the
+	// prover.
+	if err := CheckWitness(cfg.ChainSpec().L2MessageService, cfg.Execution.StateLeafLayouts, req); err != nil {
+		return nil, fmt.Errorf("the witness does not pass the pre-flight checks: %w", err)
+	}
+
 	var resp Response
 
 	// TODO @gbotrel wrap profiling in the caller; so that we can properly return errors
@@ -34,6 +57,13 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 			// WARN: CraftProverOutput calls functions that can panic.
 			out := CraftProverOutput(cfg, req)
 
+			// The digest of the limits is only known here, the public input
+			// is updated accordingly.
+			if cfg.PublicInputInterconnection.TracesLimitsBinding {
+				out.TracesLimitsDigest = utils.HexEncodeToString(execution.TracesLimitsDigest(traces))
+				out.PublicInput = field.HexElement(out.FuncInput(cfg).SumAsField())
+			}
+
 			if cfg.Execution.ProverMode != config.ProverModeProofless {
 				// Development, Partial, Full or Full-large Mode
 
@@ -41,7 +71,7 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 				// - MustProveAndPass can panic
 				// - Execution prover calls function that can panic
 				// - NewFromString can panic
-				out.Proof, out.VerifyingKeyShaSum = mustProveAndPass(
+				out.Proof, out.VerifyingKeyShaSum, out.ModuleUsage = mustProveAndPass(
 					cfg,
 					traces,
 					NewWitness(cfg, req, &out),
@@ -67,12 +97,13 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 // mustProveAndPass the prover (in the void). Does not takes a
 // prover-step function performing the assignment but a function
 // returning such a function. This is important to avoid side-effects
-// when calling it twice.
+// when calling it twice. It also returns the usage of the modules of the
+// arithmetization when the zkEVM prover is run.
 func mustProveAndPass(
 	cfg *config.Config,
 	traces *config.TracesLimits,
 	w *Witness,
-) (proofHexString string, vkeyShaSum string) {
+) (proofHexString string, vkeyShaSum string, usage []arithmetization.ModuleUsage) {
 
 	switch cfg.Execution.ProverMode {
 	case config.ProverModeDev, config.ProverModePartial:
@@ -83,11 +114,15 @@ func mustProveAndPass(
 			// And run the partial-prover with only the main steps. The generated
 			// proof is sanity-checked to ensure that the prover never outputs
 			// invalid proofs.
-			partial := zkevm.FullZkEVMCheckOnly(traces)
+			partial, err := zkevm.FullZkEVMCheckOnly(traces)
+			if err != nil {
+				utils.Panic("could not compile the zkEVM: %v", err)
+			}
 			proof := partial.ProveInner(w.ZkEVM)
 			if err := partial.VerifyInner(proof); err != nil {
 				utils.Panic("The prover did not pass: %v", err)
 			}
+			usage = partial.ModuleUsage()
 		}
 
 		srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
@@ -100,14 +135,17 @@ func mustProveAndPass(
 			utils.Panic(err.Error())
 		}
 
-		return dummy.MakeProof(&setup, w.FuncInp.SumAsField(), circuits.MockCircuitIDExecution), setup.VerifyingKeyDigest()
+		return dummy.MakeProof(&setup, w.FuncInp.SumAsField(), circuits.MockCircuitIDExecution), setup.VerifyingKeyDigest(), usage
 
 	case config.ProverModeFull:
 		logrus.Info("Running the FULL prover")
 
 		// Run the full prover to obtain the intermediate proof
 		logrus.Info("Get Full IOP")
-		fullZkEvm := zkevm.FullZkEvm(traces)
+		fullZkEvm, err := compileFullZkEvm(cfg, traces)
+		if err != nil {
+			utils.Panic("could not compile the zkEVM: %v", err)
+		}
 
 		var (
 			setup       circuits.Setup
@@ -121,7 +159,7 @@ func mustProveAndPass(
 
 		// Generates the inner-proof and sanity-check it so that we ensure that
 		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
+		proof := proveInner(cfg, fullZkEvm, w)
 
 		logrus.Info("Sanity-checking the inner-proof")
 		if err := fullZkEvm.VerifyInner(proof); err != nil {
@@ -134,45 +172,172 @@ func mustProveAndPass(
 			utils.Panic("could not load setup: %v", errSetup)
 		}
 
-		// ensure the checksum for the traces in the setup matches the one in the config
+		// ensure the setup was generated for the traces limits and the SIS
+		// parameters of the config
 		setupCfgChecksum, err := setup.Manifest.GetString("cfg_checksum")
 		if err != nil {
 			utils.Panic("could not get the traces checksum from the setup manifest: %v", err)
 		}
-		if setupCfgChecksum != traces.Checksum() {
-			utils.Panic("traces checksum in the setup manifest does not match the one in the config")
+		if setupCfgChecksum != cfg.Execution.SetupChecksum(traces) {
+			utils.Panic("the config checksum in the setup manifest does not match the traces limits and the SIS parameters of the config")
+		}
+		// the older setups do not record the canonical checksum
+		if canonical, err := setup.Manifest.GetString("cfg_checksum_canonical"); err == nil {
+			if canonical != cfg.Execution.VersionedSetupChecksum(traces, config.ChecksumCanonical) {
+				utils.Panic("the canonical config checksum in the setup manifest does not match the traces limits and the SIS parameters of the config")
+			}
+		}
+
+		// the circuit only accepts the prover metadata it was compiled with
+		if w.FuncInp.ProverMetadataDigest != nil {
+			setupProverMetadata, err := setup.Manifest.GetString("prover_metadata")
+			if err != nil {
+				utils.Panic("the prover metadata are enabled but the setup was not compiled with them: %v", err)
+			}
+			if setupProverMetadata != utils.HexEncodeToString(w.FuncInp.ProverMetadataDigest) {
+				utils.Panic("the prover metadata digest in the setup manifest does not match the version and the SIS parameters of the config")
+			}
+		}
+
+		// and the traces limits it was compiled for
+		if w.FuncInp.TracesLimitsDigest != nil {
+			setupTracesLimits, err := setup.Manifest.GetString("traces_limits_digest")
+			if err != nil {
+				utils.Panic("the traces limits are bound but the setup was not compiled with them: %v", err)
+			}
+			if setupTracesLimits != utils.HexEncodeToString(w.FuncInp.TracesLimitsDigest) {
+				utils.Panic("the traces limits digest in the setup manifest does not match the traces limits of the config")
+			}
 		}
 
 		// TODO: implements the collection of the functional inputs from the prover response
-		return execution.MakeProof(setup, fullZkEvm.WizardIOP, proof, *w.FuncInp), setup.VerifyingKeyDigest()
+		outerProof := execution.MakeProof(
+			setup,
+			fullZkEvm.WizardIOP,
+			proof,
+			*w.FuncInp,
+			circuits.SolverOptions(cfg, circuits.ExecutionCircuitID)...,
+		)
+		return outerProof, setup.VerifyingKeyDigest(), fullZkEvm.ModuleUsage()
 
 	case config.ProverModeBench:
 
 		// Run the full prover to obtain the intermediate proof
 		logrus.Info("Get Full IOP")
-		fullZkEvm := zkevm.FullZkEvm(traces)
+		fullZkEvm, err := compileFullZkEvm(cfg, traces)
+		if err != nil {
+			utils.Panic("could not compile the zkEVM: %v", err)
+		}
 
 		// Generates the inner-proof and sanity-check it so that we ensure that
 		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
+		proof := proveInner(cfg, fullZkEvm, w)
 
 		logrus.Info("Sanity-checking the inner-proof")
 		if err := fullZkEvm.VerifyInner(proof); err != nil {
 			utils.Panic("The prover did not pass: %v", err)
 		}
-		return "", ""
+		return "", "", fullZkEvm.ModuleUsage()
 
 	case config.ProverModeCheckOnly:
 
-		fullZkEvm := zkevm.FullZkEVMCheckOnly(traces)
+		fullZkEvm, err := zkevm.FullZkEVMCheckOnly(traces)
+		if err != nil {
+			utils.Panic("could not compile the zkEVM: %v", err)
+		}
 		// this will panic to alert errors, so there is no need to handle or
 		// sanity-check anything.
 		logrus.Infof("Prover starting the prover")
 		_ = fullZkEvm.ProveInner(w.ZkEVM)
 		logrus.Infof("Prover checks passed")
-		return "", ""
+		return "", "", fullZkEvm.ModuleUsage()
 
 	default:
 		panic("not implemented")
 	}
 }
+
+// proveInner runs the inner-prover of the zkEVM. When the profiling is
+// enabled, the memory used by every round of the prover is measured and
+// written in the profiling report. When the checkpoints are enabled, the
+// state of the prover is saved at the end of every round and the proof
+// resumes from the last saved round of the job.
+//
+// A gnark sub-circuit instance that fails is not re-run in place: its witness
+// and its solution are deterministic functions of the runtime state, so it
+// would fail again. The job fails and is resumed from its last checkpoint.
+func proveInner(cfg *config.Config, z *zkevm.ZkEvm, w *Witness) wizard.Proof {
+
+	var (
+		opts  []wizard.ProveOption
+		store = CheckpointStore{Dir: cfg.Execution.CheckpointDir}
+		job   string
+	)
+	if store.Dir != "" {
+		job = checkpointJob(w)
+		storeOpts, err := store.ProveOptions(z.WizardIOP, job)
+		if err != nil {
+			utils.Panic("could not resume the job from its checkpoints: %v", err)
+		}
+		opts = append(opts, storeOpts...)
+	}
+
+	var report *wizard.MemoryReport
+	if cfg.Debug.Profiling {
+		report = &wizard.MemoryReport{}
+		opts = append(opts, wizard.WithMemoryReport(report))
+	}
+
+	if cfg.Debug.DebugOpeningsDir != "" {
+		cols := make([]ifaces.ColID, len(cfg.Debug.DebugOpeningsColumns))
+		for i, col := range cfg.Debug.DebugOpeningsColumns {
+			cols[i] = ifaces.ColID(col)
+		}
+		opts = append(opts, wizard.WithDebugOpenings(cols...))
+	}
+
+	proof := z.ProveInner(w.ZkEVM, opts...)
+
+	if cfg.Debug.DebugOpeningsDir != "" {
+		if err := writeDebugOpenings(cfg.Debug.DebugOpeningsDir, checkpointJob(w), proof); err != nil {
+			logrus.Errorf("could not write the debug openings of the inner-proof: %v", err)
+		}
+	}
+
+	// The checkpoints are only removed once the proof is complete, the ones of
+	// a failed proof are left for the prover taking over the job.
+	if store.Dir != "" {
+		if err := store.Remove(job); err != nil {
+			logrus.Errorf("could not remove the checkpoint of the job: %v", err)
+		}
+	}
+
+	if report != nil {
+		if err := profiling.WriteReport("execution", "wizard-memory.txt", report); err != nil {
+			logrus.Errorf("could not write the memory report of the prover: %v", err)
+		}
+	}
+
+	return proof
+}
+
+// compileFullZkEvm returns the full zkEVM for the traces limits, compiled
+// with the options selected by the config. See [zkevm.FullZkEvm].
+func compileFullZkEvm(cfg *config.Config, traces *config.TracesLimits) (*zkevm.ZkEvm, error) {
+	opts, err := zkevm.DebugOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return zkevm.FullZkEvm(traces, cfg.Execution.SIS.Params(), opts...)
+}
+
+// writeDebugOpenings writes the columns retained in the inner-proof in the
+// file of the job in dir. See [accessors.ReadDebugOpenings] to read them.
+func writeDebugOpenings(dir, job string, proof wizard.Proof) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return files.WriteAtomic(filepath.Join(dir, job+".openings"), func(w io.Writer) error {
+		return accessors.WriteDebugOpenings(w, proof)
+	})
+}
@@ -2,13 +2,13 @@ package execution
 
 import (
 	"bytes"
+	"fmt"
 	"path"
 
 	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
 	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/sirupsen/logrus"
@@ -33,6 +33,15 @@ func (req *Request) StateManagerTraces() [][]statemanager.DecodedTrace {
 
 // Returns the parsed block data
 func (req *Request) Blocks() []ethtypes.Block {
+	res, err := req.decodeBlocks()
+	if err != nil {
+		utils.Panic("%v", err)
+	}
+	return res
+}
+
+// decodeBlocks parses the RLP of the blocks of the request
+func (req *Request) decodeBlocks() ([]ethtypes.Block, error) {
 	// Allocate the result
 	res := make([]ethtypes.Block, len(req.BlocksData))
 
@@ -40,19 +49,19 @@ func (req *Request) Blocks() []ethtypes.Block {
 		// Attempt to parse the block as an hexstring
 		blockRLPBytes, err := utils.HexDecodeString(blockdata.Rlp)
 		if err != nil {
-			utils.Panic("error while parsing the block RLP #%v : %v", i, err)
+			return nil, fmt.Errorf("error while parsing the block RLP #%v : %w", i, err)
 		}
 		buffer := bytes.NewReader(blockRLPBytes)
 
 		// Attempt to parse the RLP
 		err = rlp.Decode(buffer, &res[i])
 		if err != nil {
-			utils.Panic("Could not RLP decode the blockRLP 0x%x (block #%v)", blockRLPBytes, i)
+			return nil, fmt.Errorf("could not RLP decode the blockRLP 0x%x (block #%v): %w", blockRLPBytes, i, err)
 		}
 
 	}
 
-	return res
+	return res, nil
 }
 
 // Returns the transactions RLP encoded
@@ -60,7 +69,7 @@ func RlpTransactions(block *ethtypes.Block) []string {
 	res := []string{}
 	for _, tx := range block.Transactions() {
 		txRlp := ethereum.EncodeTxForSigning(tx)
-		res = append(res, hexutil.Encode(txRlp))
+		res = append(res, utils.HexEncodeToString(txRlp))
 	}
 	logrus.Tracef("computed the RLP of #%v transactions", len(block.Transactions()))
 	return res
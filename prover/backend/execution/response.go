@@ -3,7 +3,9 @@ package execution
 import (
 	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 )
 
 // JSON schema of the message to return as an output of the prover
@@ -66,12 +68,24 @@ type Response struct {
 	// PublicInput is the final value public input of the current proof. This
 	// field is used for debugging in case one of the proofs don't pass at the
 	// aggregation level.
-	PublicInput types.Bytes32 `json:"publicInput"`
+	PublicInput field.HexElement `json:"publicInput"`
+	// TracesLimitsDigest is the hex-encoded digest of the traces limits the
+	// proof was generated with. It is only set when the traces limits are
+	// bound, see [config.PublicInput.TracesLimitsBinding], and is forwarded
+	// to the aggregation.
+	TracesLimitsDigest string `json:"tracesLimitsDigest,omitempty"`
+	// ModuleUsage lists, for every module of the arithmetization, the number
+	// of rows used by the conflation against the limit of the module. It is
+	// metadata for monitoring the headroom on the limits and is only set when
+	// the zkEVM prover is run.
+	ModuleUsage []arithmetization.ModuleUsage `json:"moduleUsage,omitempty"`
 }
 
 type BlockData struct {
 
-	// BlockHash is the Eths block hash
+	// BlockHash is the hash of the block on the chain, see
+	// [bridge.CanonicalSegment]. It is zero for the last block of the request
+	// if none of its logs carries its hash.
 	BlockHash types.FullBytes32 `json:"blockHash"`
 
 	// T Transaction in 0x-prefixed hex format
@@ -0,0 +1,222 @@
+package execution
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/fft"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckpointStore stores the checkpoints of the inner proof of every job in a
+// directory shared by the active and the standby provers. A job is identified
+// by the digest of its functional public input. As every checkpoint only
+// holds the changes since the previous one, see [wizard.Checkpoint], the store
+// keeps the whole chain of checkpoints of the job, one file per round.
+type CheckpointStore struct {
+	Dir string
+}
+
+// path returns the path of the checkpoint of the job for the round
+func (s CheckpointStore) path(job string, round int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%v.%v.checkpoint", job, round))
+}
+
+// rounds returns the rounds of the checkpoints stored for the job, in
+// increasing order.
+func (s CheckpointStore) rounds(job string) ([]int, error) {
+
+	paths, err := filepath.Glob(filepath.Join(s.Dir, job+".*.checkpoint"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int, 0, len(paths))
+	for _, path := range paths {
+		var round int
+		name := strings.TrimPrefix(filepath.Base(path), job+".")
+		if _, err := fmt.Sscanf(name, "%d.checkpoint", &round); err != nil {
+			return nil, fmt.Errorf("unexpected checkpoint file %v", path)
+		}
+		res = append(res, round)
+	}
+
+	slices.Sort(res)
+	return res, nil
+}
+
+// Save adds the checkpoint to the chain of the job. The first checkpoint of a
+// chain replaces the ones stored for the job. The file is written atomically
+// so that a standby prover never reads a partial checkpoint.
+func (s CheckpointStore) Save(job string, cp *wizard.Checkpoint) error {
+	if cp.Previous < 0 {
+		if err := s.Remove(job); err != nil {
+			return err
+		}
+	}
+	return files.WriteAtomic(s.path(job, cp.Round), cp.Encode)
+}
+
+// Load returns the chain of checkpoints of the job or nil if there is none.
+// The checkpoints are decoded with the codecs registered in comp.
+func (s CheckpointStore) Load(comp *wizard.CompiledIOP, job string) ([]*wizard.Checkpoint, error) {
+
+	rounds, err := s.rounds(job)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the checkpoints of %v: %w", job, err)
+	}
+
+	var (
+		chain []*wizard.Checkpoint
+		prev  = -1
+	)
+
+	for _, round := range rounds {
+
+		cp, err := s.load(comp, job, round)
+		if err != nil {
+			return nil, err
+		}
+
+		if cp.Previous != prev {
+			return nil, fmt.Errorf("the checkpoint of round %v of %v follows round %v, expected %v", round, job, cp.Previous, prev)
+		}
+
+		chain = append(chain, cp)
+		prev = cp.Round
+	}
+
+	return chain, nil
+}
+
+func (s CheckpointStore) load(comp *wizard.CompiledIOP, job string, round int) (*wizard.Checkpoint, error) {
+
+	f, err := os.Open(s.path(job, round))
+	if err != nil {
+		return nil, fmt.Errorf("could not open the checkpoint of round %v of %v: %w", round, job, err)
+	}
+	defer f.Close()
+
+	cp, err := wizard.DecodeCheckpoint(comp, f)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the checkpoint of round %v of %v: %w", round, job, err)
+	}
+	return cp, nil
+}
+
+// Remove deletes the checkpoints of the job, if any
+func (s CheckpointStore) Remove(job string) error {
+
+	rounds, err := s.rounds(job)
+	if err != nil {
+		return err
+	}
+
+	for _, round := range rounds {
+		if err := os.Remove(s.path(job, round)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProveOptions returns the options of the inner prover saving the checkpoints
+// of the job and resuming it from its last checkpoint if there is one, i.e.
+// if another prover failed while proving it. It returns an error if the
+// checkpoints of the job cannot be read; the proof is aborted if they cannot
+// be saved.
+func (s CheckpointStore) ProveOptions(comp *wizard.CompiledIOP, job string) ([]wizard.ProveOption, error) {
+
+	opts := []wizard.ProveOption{
+		wizard.WithCheckpoints(func(cp *wizard.Checkpoint) error {
+			return s.Save(job, cp)
+		}),
+	}
+
+	chain, err := s.Load(comp, job)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(chain) > 0 {
+		logrus.Infof("resuming the inner proof from the checkpoint of round %v", chain[len(chain)-1].Round)
+		opts = append(opts, wizard.ResumeFrom(chain))
+	}
+
+	return opts, nil
+}
+
+// Warmup does the work of the prover that does not depend on the request,
+// so that a standby prover taking over a job starts proving without delay:
+// it compiles the zkEVM, precomputes the FFT domains of its columns and, in
+// full mode, loads the setup of the circuit in memory.
+func Warmup(cfg *config.Config, large bool) error {
+
+	traces := &cfg.TracesLimits
+	if large {
+		traces = &cfg.TracesLimitsLarge
+	}
+
+	switch cfg.Execution.ProverMode {
+	case config.ProverModeFull, config.ProverModeBench:
+
+		logrus.Info("Compiling the zkEVM")
+		z, err := compileFullZkEvm(cfg, traces)
+		if err != nil {
+			return err
+		}
+		precomputeDomains(z.WizardIOP)
+
+		if cfg.Execution.ProverMode == config.ProverModeFull {
+			logrus.Info("Loading the setup")
+			if err := circuits.PreloadSetup(cfg, circuits.ExecutionCircuitID); err != nil {
+				return err
+			}
+		}
+
+	case config.ProverModeCheckOnly, config.ProverModePartial:
+		logrus.Info("Compiling the zkEVM")
+		if _, err := zkevm.FullZkEVMCheckOnly(traces); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// precomputeDomains computes the twiddles of the FFT domains up to twice the
+// size of the largest column, as needed by the Reed-Solomon encoding of the
+// columns.
+func precomputeDomains(comp *wizard.CompiledIOP) {
+
+	maxSize := 0
+	for round := 0; round < comp.NumRounds(); round++ {
+		for _, col := range comp.Columns.AllHandlesAtRound(round) {
+			maxSize = max(maxSize, col.Size())
+		}
+	}
+
+	if maxSize == 0 {
+		return
+	}
+
+	logrus.Infof("Precomputing the FFT domains up to size %v", 2*maxSize)
+	fft.GetTwiddleForDomainOfSize(2 * utils.NextPowerOfTwo(maxSize))
+}
+
+// checkpointJob returns the key identifying the job in the checkpoint store
+func checkpointJob(w *Witness) string {
+	return hex.EncodeToString(w.FuncInp.Sum())
+}
@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"os"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointStore(t *testing.T) {
+
+	var (
+		store = CheckpointStore{Dir: t.TempDir()}
+		comp  = wizard.Compile(func(b *wizard.Builder) {})
+		coinV = field.NewElement(7)
+		first = &wizard.Checkpoint{
+			Round:    0,
+			Previous: -1,
+			Columns:  map[ifaces.ColID]ifaces.ColAssignment{"A": smartvectors.ForTest(1, 2, 3, 4)},
+		}
+		second = &wizard.Checkpoint{
+			Round:    2,
+			Previous: 0,
+			Coins:    map[coin.Name]interface{}{"C": coinV},
+		}
+	)
+
+	chain, err := store.Load(comp, "job")
+	require.NoError(t, err)
+	require.Nil(t, chain, "no checkpoint was saved yet")
+
+	require.NoError(t, store.Save("job", first))
+	require.NoError(t, store.Save("job", second))
+
+	chain, err = store.Load(comp, "job")
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	require.Equal(t, 2, chain[1].Round, "the chain is ordered by round")
+	require.Equal(t, first.Columns["A"].Pretty(), chain[0].Columns["A"].Pretty())
+	require.Equal(t, coinV, chain[1].Coins["C"])
+
+	// A new chain replaces the previous one
+	require.NoError(t, store.Save("job", first))
+	chain, err = store.Load(comp, "job")
+	require.NoError(t, err)
+	require.Len(t, chain, 1)
+
+	// A broken chain is an error, not a silent restart
+	require.NoError(t, store.Save("job", second))
+	require.NoError(t, os.Remove(store.path("job", 0)))
+	_, err = store.Load(comp, "job")
+	require.Error(t, err)
+
+	require.NoError(t, store.Save("job", first))
+	require.NoError(t, os.WriteFile(store.path("job", 1), []byte("garbage"), 0o600))
+	_, err = store.Load(comp, "job")
+	require.Error(t, err)
+
+	require.NoError(t, store.Remove("job"))
+	require.NoError(t, store.Remove("job"), "removing a missing checkpoint is not an error")
+
+	chain, err = store.Load(comp, "job")
+	require.NoError(t, err)
+	require.Nil(t, chain)
+}
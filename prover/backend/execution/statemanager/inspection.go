@@ -3,12 +3,20 @@ package statemanager
 import (
 	"fmt"
 
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 )
 
 // Inspect the traces and check if they are consistent with what the spec allows
 func CheckTraces(traces []DecodedTrace) (oldStateRootHash Digest, newStateRootHash Digest, err error) {
+	return CheckTracesWithConfig(MIMC_CONFIG, traces)
+}
+
+// CheckTracesWithConfig inspects the traces as [CheckTraces] for tries using
+// the given config, e.g. a leaf layout other than the original one, see
+// [ConfigForLeafLayout].
+func CheckTracesWithConfig(conf *smt.Config, traces []DecodedTrace) (oldStateRootHash Digest, newStateRootHash Digest, err error) {
 
 	var prevAddress Address
 
@@ -60,13 +68,13 @@ func CheckTraces(traces []DecodedTrace) (oldStateRootHash Digest, newStateRootHa
 			return digestErr, digestErr, err
 		}
 		// run the proof verification on the account
-		if err := checkProofsForAccount(traces); err != nil {
+		if err := checkProofsForAccount(conf, traces); err != nil {
 			return digestErr, digestErr, err
 		}
 	}
 
 	// Finally check the proof for the world state
-	return checkProofsWorldState(traceWs)
+	return checkProofsWorldState(conf, traceWs)
 }
 
 // return the account of a trace. location for storage trie updates) and key for
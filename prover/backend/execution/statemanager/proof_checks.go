@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/sirupsen/logrus"
@@ -13,7 +14,7 @@ import (
 // consist in several sequences of ST access delimited by WS accesses on
 // the right. The exact pattern is inspected prior to calling this function
 // so any irregularity in the pattern yields a panic.
-func checkProofsForAccount(traces []DecodedTrace) error {
+func checkProofsForAccount(conf *smt.Config, traces []DecodedTrace) error {
 
 	curr := []DecodedTrace{}
 
@@ -28,7 +29,7 @@ func checkProofsForAccount(traces []DecodedTrace) error {
 			if len(curr) > 0 {
 
 				logrus.Tracef("checking proof for segment of length %v for account %v", len(curr), trace.Location)
-				old, new, err := checkSpliceST(curr)
+				old, new, err := checkSpliceST(conf, curr)
 				if err != nil {
 					return err
 				}
@@ -53,8 +54,8 @@ func checkProofsForAccount(traces []DecodedTrace) error {
 					panic("read zero but there are ST accesses")
 				case InsertionTraceWS:
 					// The initial value should be the empty tree
-					if old != MIMC_EMPTY_STORAGE {
-						return fmt.Errorf("sequence of storage access followed by an insertion, but the old (%v) was not the empty storage root (%v)", old.Hex(), MIMC_EMPTY_STORAGE.Hex())
+					if emptyStorage := EmptyStorageTrieHash(conf); old != emptyStorage {
+						return fmt.Errorf("sequence of storage access followed by an insertion, but the old (%v) was not the empty storage root (%v)", old.Hex(), emptyStorage.Hex())
 					}
 					// The recovered new root hash should be consistent with the one
 					// inserted
@@ -104,14 +105,14 @@ func checkProofsForAccount(traces []DecodedTrace) error {
 	return nil
 }
 
-func checkProofsWorldState(traces []DecodedTrace) (oldRootHash, newRootHash Digest, err error) {
+func checkProofsWorldState(conf *smt.Config, traces []DecodedTrace) (oldRootHash, newRootHash Digest, err error) {
 
 	if len(traces) == 0 {
 		panic("unexpected empty slice")
 	}
 
 	// uses the first trace to bootstrap the verifier
-	vs := bootstrapVerifierStateFromWS(traces[0])
+	vs := bootstrapVerifierStateFromWS(conf, traces[0])
 	oldRootHash = vs.TopRoot()
 
 	for i, trace := range traces {
@@ -170,7 +171,7 @@ func checkProofsWorldState(traces []DecodedTrace) (oldRootHash, newRootHash Dige
 
 // plays the trace verification and in case of success. Return a recovered initial
 // and final root hash.
-func checkSpliceST(traces []DecodedTrace) (oldRootHash, newRootHash Digest, err error) {
+func checkSpliceST(conf *smt.Config, traces []DecodedTrace) (oldRootHash, newRootHash Digest, err error) {
 
 	if len(traces) == 0 {
 		panic("unexpected empty slice")
@@ -180,7 +181,7 @@ func checkSpliceST(traces []DecodedTrace) (oldRootHash, newRootHash Digest, err
 	digestErr := Digest{}
 
 	// uses the first trace to bootstrap the verifier
-	vs := bootstrapVerifierStateFromST(traces[0])
+	vs := bootstrapVerifierStateFromST(conf, traces[0])
 	oldRootHash = vs.TopRoot()
 
 	for _, trace := range traces {
@@ -217,11 +218,11 @@ func checkSpliceST(traces []DecodedTrace) (oldRootHash, newRootHash Digest, err
 	return oldRootHash, newRootHash, nil
 }
 
-func bootstrapVerifierStateFromST(trace DecodedTrace) (vs StorageVerifier) {
+func bootstrapVerifierStateFromST(conf *smt.Config, trace DecodedTrace) (vs StorageVerifier) {
 
 	vs = StorageVerifier{
 		Location: trace.Location,
-		Config:   MIMC_CONFIG,
+		Config:   conf,
 	}
 
 	switch t := trace.Underlying.(type) {
@@ -248,11 +249,11 @@ func bootstrapVerifierStateFromST(trace DecodedTrace) (vs StorageVerifier) {
 	return vs
 }
 
-func bootstrapVerifierStateFromWS(trace DecodedTrace) (vs AccountVerifier) {
+func bootstrapVerifierStateFromWS(conf *smt.Config, trace DecodedTrace) (vs AccountVerifier) {
 
 	vs = AccountVerifier{
 		Location: trace.Location,
-		Config:   MIMC_CONFIG,
+		Config:   conf,
 	}
 
 	switch t := trace.Underlying.(type) {
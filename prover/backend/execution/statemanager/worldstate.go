@@ -18,6 +18,16 @@ var MIMC_CONFIG = &smt.Config{
 	Depth:    40,
 }
 
+// ConfigForLeafLayout returns the config of the tries whose leaves follow the
+// given layout, see [accumulator.LeafLayoutV0]. It returns [MIMC_CONFIG] for
+// the original layout and panics if the layout is not supported.
+func ConfigForLeafLayout(layout uint8) *smt.Config {
+	if layout == accumulator.LeafLayoutV0 {
+		return MIMC_CONFIG
+	}
+	return accumulator.WithLeafLayout(MIMC_CONFIG, layout)
+}
+
 // Legacy keccak code hash of an empty account
 // 0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470
 var LEGACY_KECCAK_EMPTY_CODEHASH = common.FromHex("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
@@ -0,0 +1,133 @@
+package files
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPartialFile is returned when a file was only partially written, which
+// happens when the process writing it crashed without the guarantees of
+// [WriteAtomic].
+var ErrPartialFile = errors.New("partial file")
+
+// WriteAtomic writes a file through write so that readers either see the
+// previous version of the file, if any, or the complete new one. The content
+// is written to a temporary file of the same directory, flushed to the disk
+// and renamed to p, then the directory itself is flushed so that the rename
+// survives a crash of the machine. The parent directory is created if needed.
+// The temporary file is removed if any step fails.
+func WriteAtomic(p string, write func(w io.Writer) error) (err error) {
+
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0770); err != nil {
+		return fmt.Errorf("could not create directory %v: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not create a temporary file for %v: %w", p, err)
+	}
+
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	buf := bufio.NewWriter(tmp)
+	if err := write(buf); err != nil {
+		return fmt.Errorf("could not write %v: %w", p, err)
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("could not write %v: %w", p, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("could not sync %v: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not close %v: %w", tmp.Name(), err)
+	}
+
+	// os.CreateTemp creates the file with the permissions 0600, the file is
+	// made readable by the consumers of the responses.
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("could not set the permissions of %v: %w", tmp.Name(), err)
+	}
+
+	return RenameDurable(tmp.Name(), p)
+}
+
+// WriteFileAtomic is as [os.WriteFile] with the guarantees of [WriteAtomic]
+func WriteFileAtomic(p string, b []byte) error {
+	return WriteAtomic(p, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
+
+// RenameDurable renames oldpath to newpath and flushes the directory of
+// newpath to the disk so that the rename is not lost if the machine crashes.
+func RenameDurable(oldpath, newpath string) error {
+	if err := os.Rename(oldpath, newpath); err != nil {
+		return err
+	}
+	if err := SyncDir(filepath.Dir(newpath)); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(oldpath); dir != filepath.Dir(newpath) {
+		return SyncDir(dir)
+	}
+	return nil
+}
+
+// SyncDir flushes the entries of the directory to the disk
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("could not open directory %v: %w", dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("could not sync directory %v: %w", dir, err)
+	}
+	return nil
+}
+
+// CheckComplete returns an error wrapping [ErrPartialFile] if the file at p is
+// empty or if, for a JSON file, it does not hold a complete JSON document. It
+// is meant to detect the files truncated by a crash before they are handed
+// over, as the formats of the files do not carry their length.
+func CheckComplete(p string) error {
+
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+
+	if len(b) == 0 {
+		return fmt.Errorf("%v: %w, the file is empty", p, ErrPartialFile)
+	}
+
+	if strings.HasSuffix(p, ".json") && !json.Valid(b) {
+		return fmt.Errorf("%v: %w, the file does not hold a complete JSON document", p, ErrPartialFile)
+	}
+
+	return nil
+}
+
+// WrapTruncated wraps the error returned when decoding a file with
+// [ErrPartialFile] if the decoder reached the end of the file before the end
+// of the document.
+func WrapTruncated(err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %w", ErrPartialFile, err)
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAtomic(t *testing.T) {
+
+	var (
+		dir = t.TempDir()
+		p   = filepath.Join(dir, "responses", "response.json")
+	)
+
+	require.NoError(t, WriteFileAtomic(p, []byte(`{"a":1}`)))
+	require.NoError(t, CheckComplete(p))
+
+	// a failed write leaves the previous version and no temporary file
+	err := WriteAtomic(p, func(w io.Writer) error {
+		w.Write([]byte(`{"a":`))
+		return errors.New("crash")
+	})
+	require.Error(t, err)
+
+	b, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(b))
+
+	entries, err := os.ReadDir(filepath.Dir(p))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "response.json", entries[0].Name())
+}
+
+func TestCheckComplete(t *testing.T) {
+
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		name, content string
+		partial       bool
+	}{
+		{"complete.json", `{"a":[1,2]}`, false},
+		{"truncated.json", `{"a":[1,`, true},
+		{"empty.bin", ``, true},
+		{"binary.bin", `{"a":[1,`, false},
+	} {
+		p := filepath.Join(dir, tc.name)
+		require.NoError(t, os.WriteFile(p, []byte(tc.content), 0600))
+		err := CheckComplete(p)
+		assert.Equal(t, tc.partial, errors.Is(err, ErrPartialFile), tc.name)
+	}
+
+	// the decoders of the truncated files
+	var v any
+	err := json.NewDecoder(strings.NewReader(`{"a":[1,`)).Decode(&v)
+	assert.ErrorIs(t, WrapTruncated(err), ErrPartialFile)
+	err = json.NewDecoder(strings.NewReader(`{"a":]`)).Decode(&v)
+	assert.NotErrorIs(t, WrapTruncated(err), ErrPartialFile)
+}
@@ -0,0 +1,66 @@
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/sirupsen/logrus"
+)
+
+// Anonymize writes a redacted copy of the execution requests of the corpus in
+// src into dst, at the same relative paths, so that the shape of failing cases
+// can be shared with external teams. The copies cannot be replayed. See [execution.Request.Anonymize] for what is
+// anonymized and what is preserved. It returns the number of requests written.
+//
+// The blob decompression requests are skipped as their payload cannot be
+// anonymized without changing the blob commitments. The expectations are not
+// copied either since they do not hold for the anonymized requests.
+func Anonymize(src, dst string, opts execution.AnonymizeOptions) (int, error) {
+
+	requests, err := listRequests(src)
+	if err != nil {
+		return 0, err
+	}
+
+	nbWritten := 0
+
+	for _, path := range requests {
+
+		if jobOf(path) != JobExecution {
+			logrus.Warnf("anonymize: skipping %v, only the execution requests are anonymized", path)
+			continue
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return nbWritten, fmt.Errorf("could not locate %v in %v: %w", path, src, err)
+		}
+
+		req := &execution.Request{}
+		if err := readJSON(path, req); err != nil {
+			return nbWritten, err
+		}
+
+		anon, err := req.Anonymize(opts)
+		if err != nil {
+			return nbWritten, fmt.Errorf("could not anonymize %v: %w", path, err)
+		}
+
+		out := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+			return nbWritten, fmt.Errorf("could not create the directory of %v: %w", out, err)
+		}
+
+		if err := writeJSON(out, anon); err != nil {
+			return nbWritten, err
+		}
+
+		nbWritten++
+	}
+
+	logrus.Infof("anonymize: wrote %v anonymized requests in %v", nbWritten, dst)
+
+	return nbWritten, nil
+}
@@ -0,0 +1,442 @@
+// Package replay runs a corpus of archived prover requests through the
+// current build and compares the outputs with the ones recorded for previous
+// builds. It is meant to detect regressions before a new prover version is
+// deployed: a change of public input or of the number of rows of a module on
+// a historical request is almost always a bug.
+//
+// The corpus is a directory holding execution and blob decompression requests,
+// recognized by their file names as in the prove command. The expectations of
+// a request are stored next to it, in a file with the same name followed by
+// [ExpectationSuffix].
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+// ExpectationSuffix is appended to the name of a request file to obtain the
+// name of the file holding its expectations.
+const ExpectationSuffix = ".expected.json"
+
+// Job is the kind of request being replayed
+type Job string
+
+const (
+	JobExecution         Job = "execution"
+	JobBlobDecompression Job = "blob-decompression"
+)
+
+// Status is the outcome of the replay of a request
+type Status string
+
+const (
+	// StatusPass indicates that the outputs match the expectations
+	StatusPass Status = "pass"
+	// StatusRegression indicates that at least one output differs from the
+	// expectations.
+	StatusRegression Status = "regression"
+	// StatusError indicates that the request could not be replayed
+	StatusError Status = "error"
+	// StatusUnchecked indicates that the request has no expectations
+	StatusUnchecked Status = "unchecked"
+	// StatusRecorded indicates that the outputs have been recorded as the
+	// new expectations of the request.
+	StatusRecorded Status = "recorded"
+)
+
+// Expectation holds the outputs recorded for a request
+type Expectation struct {
+	// PublicInput is the public input of the proof, as a 0x-prefixed hex
+	// string.
+	PublicInput string `json:"publicInput"`
+	// RowCounts maps the modules of the arithmetization to their number of
+	// rows. It is only relevant for the execution requests.
+	RowCounts map[string]int `json:"rowCounts,omitempty"`
+}
+
+// Result is the outcome of the replay of a single request
+type Result struct {
+	Request    string      `json:"request"`
+	Job        Job         `json:"job"`
+	Status     Status      `json:"status"`
+	Output     Expectation `json:"output"`
+	Mismatches []string    `json:"mismatches,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Duration   string      `json:"duration"`
+}
+
+// Summary is the outcome of the replay of a corpus
+type Summary struct {
+	ProverVersion string   `json:"proverVersion"`
+	CheckOnly     bool     `json:"checkOnly"`
+	NbPass        int      `json:"nbPass"`
+	NbRegression  int      `json:"nbRegression"`
+	NbError       int      `json:"nbError"`
+	NbUnchecked   int      `json:"nbUnchecked"`
+	NbRecorded    int      `json:"nbRecorded"`
+	Results       []Result `json:"results"`
+}
+
+// Failed returns true if a request could not be replayed or did not match its
+// expectations.
+func (s *Summary) Failed() bool {
+	return s.NbRegression > 0 || s.NbError > 0
+}
+
+// WriteTo writes the summary in JSON in the file at path
+func (s *Summary) WriteTo(path string) error {
+	return writeJSON(path, s)
+}
+
+// Options tunes the replay of a corpus
+type Options struct {
+	// CheckOnly stops the jobs once the witness has been checked. The
+	// execution requests are run in [config.ProverModeCheckOnly] and the blob
+	// decompression requests stop after the assignment is computed.
+	CheckOnly bool
+	// Record overwrites the expectations of the requests with the outputs of
+	// the current build instead of comparing them.
+	Record bool
+	// TracesDir overrides the directory where the conflated execution traces
+	// are read. The one of the config is used if empty.
+	TracesDir string
+}
+
+// replayer runs the requests of a corpus. The job functions are fields so
+// that the tests can replace them.
+type replayer struct {
+	cfg              *config.Config
+	opts             Options
+	execution        func(cfg *config.Config, req *execution.Request) (Expectation, error)
+	blobDecompressor func(cfg *config.Config, req *blobdecompression.Request) (Expectation, error)
+}
+
+// Run replays all the requests found in dir and its sub-directories, in
+// lexicographic order. The returned error is only about the corpus itself:
+// the failure of a request is reported in its [Result]. Aggregation requests
+// are ignored.
+//
+// The jobs run in the current process, with the prover mode of the config
+// unless opts.CheckOnly is set. Note that an execution request overflowing
+// the traces limits terminates the process, as in the prove command.
+func Run(cfg *config.Config, dir string, opts Options) (*Summary, error) {
+	r := &replayer{
+		cfg:              cfg,
+		opts:             opts,
+		execution:        replayExecution,
+		blobDecompressor: replayBlobDecompression,
+	}
+	return r.run(dir)
+}
+
+func (r *replayer) run(dir string) (*Summary, error) {
+
+	requests, err := listRequests(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		ProverVersion: r.cfg.Version,
+		CheckOnly:     r.opts.CheckOnly,
+		Results:       make([]Result, 0, len(requests)),
+	}
+
+	for i, path := range requests {
+		logrus.Infof("replay: running request %v/%v: %v", i+1, len(requests), path)
+
+		res := r.replay(path)
+
+		switch res.Status {
+		case StatusPass:
+			summary.NbPass++
+		case StatusRegression:
+			summary.NbRegression++
+			logrus.Errorf("replay: regression on %v: %v", path, strings.Join(res.Mismatches, "; "))
+		case StatusError:
+			summary.NbError++
+			logrus.Errorf("replay: could not replay %v: %v", path, res.Error)
+		case StatusUnchecked:
+			summary.NbUnchecked++
+			logrus.Warnf("replay: no expectations for %v", path)
+		case StatusRecorded:
+			summary.NbRecorded++
+		}
+
+		summary.Results = append(summary.Results, res)
+	}
+
+	logrus.Infof(
+		"replay: done, pass=%v regression=%v error=%v unchecked=%v recorded=%v",
+		summary.NbPass, summary.NbRegression, summary.NbError, summary.NbUnchecked, summary.NbRecorded,
+	)
+
+	return summary, nil
+}
+
+// replay runs a single request and compares its outputs with the recorded
+// expectations.
+func (r *replayer) replay(path string) (res Result) {
+
+	var (
+		start = time.Now()
+		err   error
+	)
+
+	res = Result{Request: path, Job: jobOf(path)}
+	defer func() { res.Duration = time.Since(start).String() }()
+
+	cfg := *r.cfg
+	if r.opts.TracesDir != "" {
+		cfg.Execution.ConflatedTracesDir = r.opts.TracesDir
+	}
+
+	switch res.Job {
+	case JobExecution:
+		if r.opts.CheckOnly {
+			cfg.Execution.ProverMode = config.ProverModeCheckOnly
+		}
+		req := &execution.Request{}
+		if err = readJSON(path, req); err == nil {
+			res.Output, err = catchPanic(func() (Expectation, error) { return r.execution(&cfg, req) })
+		}
+	case JobBlobDecompression:
+		if r.opts.CheckOnly {
+			cfg.BlobDecompression.ProverMode = config.ProverModeCheckOnly
+		}
+		req := &blobdecompression.Request{}
+		if err = readJSON(path, req); err == nil {
+			res.Output, err = catchPanic(func() (Expectation, error) { return r.blobDecompressor(&cfg, req) })
+		}
+	}
+
+	if err != nil {
+		res.Status, res.Error = StatusError, err.Error()
+		return res
+	}
+
+	expPath := path + ExpectationSuffix
+
+	if r.opts.Record {
+		if err := writeJSON(expPath, res.Output); err != nil {
+			res.Status, res.Error = StatusError, err.Error()
+			return res
+		}
+		res.Status = StatusRecorded
+		return res
+	}
+
+	if _, err := os.Stat(expPath); os.IsNotExist(err) {
+		res.Status = StatusUnchecked
+		return res
+	}
+
+	var expected Expectation
+	if err := readJSON(expPath, &expected); err != nil {
+		res.Status, res.Error = StatusError, fmt.Sprintf("could not read the expectations: %v", err)
+		return res
+	}
+
+	res.Mismatches = Compare(expected, res.Output)
+	res.Status = StatusPass
+	if len(res.Mismatches) > 0 {
+		res.Status = StatusRegression
+	}
+
+	return res
+}
+
+// Compare returns a description of every difference between the expected and
+// the actual outputs. The public inputs are compared case-insensitively. The
+// row counts are only compared if some were recorded, a module that appears on
+// only one side is reported as a mismatch.
+func Compare(expected, actual Expectation) []string {
+
+	var res []string
+
+	if !strings.EqualFold(expected.PublicInput, actual.PublicInput) {
+		res = append(res, fmt.Sprintf("public input: expected %v, got %v", expected.PublicInput, actual.PublicInput))
+	}
+
+	if len(expected.RowCounts) == 0 {
+		return res
+	}
+
+	modules := make([]string, 0, len(expected.RowCounts))
+	for m := range expected.RowCounts {
+		modules = append(modules, m)
+	}
+	for m := range actual.RowCounts {
+		if _, ok := expected.RowCounts[m]; !ok {
+			modules = append(modules, m)
+		}
+	}
+	sort.Strings(modules)
+
+	for _, m := range modules {
+		exp, okExp := expected.RowCounts[m]
+		act, okAct := actual.RowCounts[m]
+		switch {
+		case !okExp:
+			res = append(res, fmt.Sprintf("row count of %v: unexpected module with %v rows", m, act))
+		case !okAct:
+			res = append(res, fmt.Sprintf("row count of %v: expected %v rows, the module is missing", m, exp))
+		case exp != act:
+			res = append(res, fmt.Sprintf("row count of %v: expected %v, got %v", m, exp, act))
+		}
+	}
+
+	return res
+}
+
+// replayExecution runs an execution request and collects the number of rows
+// of the modules from its traces.
+func replayExecution(cfg *config.Config, req *execution.Request) (Expectation, error) {
+
+	rowCounts, err := readRowCounts(req.ConflatedExecTraceFilepath(cfg.Execution.ConflatedTracesDir))
+	if err != nil {
+		return Expectation{}, err
+	}
+
+	resp, err := execution.Prove(cfg, req, false)
+	if err != nil {
+		return Expectation{}, err
+	}
+
+	return Expectation{
+		PublicInput: resp.PublicInput.String(),
+		RowCounts:   rowCounts,
+	}, nil
+}
+
+// replayBlobDecompression runs a blob decompression request
+func replayBlobDecompression(cfg *config.Config, req *blobdecompression.Request) (Expectation, error) {
+
+	resp, err := blobdecompression.Prove(cfg, req)
+	if err != nil {
+		return Expectation{}, err
+	}
+
+	return Expectation{PublicInput: resp.Debug.PublicInput.String()}, nil
+}
+
+// readRowCounts expands the traces at path and returns the height of every
+// module.
+func readRowCounts(path string) (map[string]int, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the traces: %w", err)
+	}
+
+	schema, err := arithmetization.ReadZkevmBin()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// ReadLtTraces closes the file
+	traces, err := arithmetization.ReadLtTraces(f, schema)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the traces at %v: %w", path, err)
+	}
+
+	res := map[string]int{}
+	for _, module := range traces.Modules().Collect() {
+		res[module.Name()] = int(module.Height()) // #nosec G115 -- the height fits in an int
+	}
+
+	return res, nil
+}
+
+// listRequests returns the execution and blob decompression requests of the
+// corpus, sorted.
+func listRequests(dir string) ([]string, error) {
+
+	var res []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ExpectationSuffix) {
+			return nil
+		}
+		if jobOf(d.Name()) != "" {
+			res = append(res, path)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not list the requests in %v: %w", dir, err)
+	}
+
+	return res, nil
+}
+
+// jobOf returns the job of a request from its file name, or an empty string
+// if it is not replayed.
+func jobOf(path string) Job {
+	name := filepath.Base(path)
+	switch {
+	case strings.Contains(name, "getZkProof"):
+		return JobExecution
+	case strings.Contains(name, "getZkBlobCompressionProof"):
+		return JobBlobDecompression
+	default:
+		return ""
+	}
+}
+
+// catchPanic runs f and converts a panic into an error. The backends report
+// many failures by panicking and a single request should not stop the replay
+// of the corpus.
+func catchPanic(f func() (Expectation, error)) (res Expectation, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return f()
+}
+
+func readJSON(path string, into any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open file: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(into); err != nil {
+		return fmt.Errorf("could not decode %v: %w", path, err)
+	}
+
+	return nil
+}
+
+func writeJSON(path string, from any) error {
+	return files.WriteAtomic(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(from); err != nil {
+			return fmt.Errorf("could not encode %v: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+package replay
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+
+	expected := Expectation{
+		PublicInput: "0xABCD",
+		RowCounts:   map[string]int{"hub": 10, "mmu": 20, "rom": 30},
+	}
+
+	assert.Empty(t, Compare(expected, Expectation{
+		PublicInput: "0xabcd",
+		RowCounts:   map[string]int{"hub": 10, "mmu": 20, "rom": 30},
+	}))
+
+	// The row counts are not checked if none were recorded
+	assert.Empty(t, Compare(Expectation{PublicInput: "0xabcd"}, expected))
+
+	assert.Equal(t, []string{
+		"public input: expected 0xABCD, got 0x1234",
+		"row count of ext: unexpected module with 5 rows",
+		"row count of mmu: expected 20, got 21",
+		"row count of rom: expected 30 rows, the module is missing",
+	}, Compare(expected, Expectation{
+		PublicInput: "0x1234",
+		RowCounts:   map[string]int{"hub": 10, "mmu": 21, "ext": 5},
+	}))
+}
+
+func TestReplay(t *testing.T) {
+
+	var (
+		dir   = t.TempDir()
+		write = func(name, content string) {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+		}
+		outputs = map[string]Expectation{
+			"exec-a": {PublicInput: "0x01", RowCounts: map[string]int{"hub": 10}},
+			"exec-b": {PublicInput: "0x02", RowCounts: map[string]int{"hub": 20}},
+		}
+	)
+
+	write("1-2-getZkProof.json", `{"conflatedExecutionTracesFile": "exec-a"}`)
+	write("3-4-getZkProof.json", `{"conflatedExecutionTracesFile": "exec-b"}`)
+	write("5-6-getZkBlobCompressionProof.json", `{"compressedData": "blob"}`)
+	write("7-8-getZkBlobCompressionProof.json", `{"compressedData": "panic"}`)
+	write("9-10-getZkAggregatedProof.json", `{}`)
+	write("not-a-request.json", `{}`)
+
+	r := &replayer{
+		cfg:  &config.Config{Version: "test"},
+		opts: Options{Record: true},
+		execution: func(cfg *config.Config, req *execution.Request) (Expectation, error) {
+			if out, ok := outputs[req.ConflatedExecutionTracesFile]; ok {
+				return out, nil
+			}
+			return Expectation{}, errors.New("unknown traces")
+		},
+		blobDecompressor: func(cfg *config.Config, req *blobdecompression.Request) (Expectation, error) {
+			if req.CompressedData == "panic" {
+				panic("invalid blob")
+			}
+			return Expectation{PublicInput: "0x03"}, nil
+		},
+	}
+
+	summary, err := r.run(dir)
+	require.NoError(t, err)
+	require.Len(t, summary.Results, 4)
+	assert.Equal(t, 3, summary.NbRecorded)
+	assert.Equal(t, 1, summary.NbError)
+	assert.Contains(t, summary.Results[3].Error, "invalid blob")
+	assert.FileExists(t, filepath.Join(dir, "1-2-getZkProof.json"+ExpectationSuffix))
+
+	// The recorded expectations are not part of the corpus and match the
+	// outputs of the same build.
+	require.NoError(t, os.Remove(filepath.Join(dir, "5-6-getZkBlobCompressionProof.json"+ExpectationSuffix)))
+	r.opts.Record = false
+	summary, err = r.run(dir)
+	require.NoError(t, err)
+	require.Len(t, summary.Results, 4)
+	assert.Equal(t, 2, summary.NbPass)
+	assert.Equal(t, 1, summary.NbUnchecked)
+	assert.Equal(t, 1, summary.NbError)
+
+	// A change of row count in the new build is a regression
+	outputs["exec-b"] = Expectation{PublicInput: "0x02", RowCounts: map[string]int{"hub": 21}}
+	summary, err = r.run(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.NbRegression)
+	assert.True(t, summary.Failed())
+
+	res := summary.Results[1]
+	assert.Equal(t, JobExecution, res.Job)
+	assert.Equal(t, StatusRegression, res.Status)
+	assert.Equal(t, []string{"row count of hub: expected 20, got 21"}, res.Mismatches)
+
+	summaryPath := filepath.Join(t.TempDir(), "summary.json")
+	require.NoError(t, summary.WriteTo(summaryPath))
+	var decoded Summary
+	require.NoError(t, readJSON(summaryPath, &decoded))
+	assert.Equal(t, *summary, decoded)
+}
+
+func TestReplayCheckOnlyConfig(t *testing.T) {
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1-2-getZkProof.json"), []byte(`{}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "3-4-getZkBlobCompressionProof.json"), []byte(`{}`), 0600))
+
+	cfg := &config.Config{}
+	cfg.Execution.ProverMode = config.ProverModeFull
+	cfg.Execution.ConflatedTracesDir = "/traces"
+	cfg.BlobDecompression.ProverMode = config.ProverModeFull
+
+	r := &replayer{
+		cfg:  cfg,
+		opts: Options{CheckOnly: true, TracesDir: "/archive/traces"},
+		execution: func(cfg *config.Config, req *execution.Request) (Expectation, error) {
+			assert.Equal(t, config.ProverModeCheckOnly, cfg.Execution.ProverMode)
+			assert.Equal(t, "/archive/traces", cfg.Execution.ConflatedTracesDir)
+			return Expectation{}, nil
+		},
+		blobDecompressor: func(cfg *config.Config, req *blobdecompression.Request) (Expectation, error) {
+			assert.Equal(t, config.ProverModeCheckOnly, cfg.BlobDecompression.ProverMode)
+			return Expectation{}, nil
+		},
+	}
+
+	summary, err := r.run(dir)
+	require.NoError(t, err)
+	assert.True(t, summary.CheckOnly)
+	assert.Equal(t, 2, summary.NbUnchecked)
+
+	// The config of the caller is left untouched
+	assert.Equal(t, config.ProverModeFull, cfg.Execution.ProverMode)
+	assert.Equal(t, "/traces", cfg.Execution.ConflatedTracesDir)
+}
+
+func TestAnonymizeCorpus(t *testing.T) {
+
+	var (
+		src   = t.TempDir()
+		dst   = t.TempDir()
+		write = func(name, content string) {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(src, name)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(src, name), []byte(content), 0600))
+		}
+	)
+
+	write("sub/1-2-getZkProof.json", `{"conflatedExecutionTracesFile": "exec-a", "blocksData": []}`)
+	write("sub/1-2-getZkProof.json"+ExpectationSuffix, `{"publicInput": "0x01"}`)
+	write("5-6-getZkBlobCompressionProof.json", `{"compressedData": "blob"}`)
+
+	n, err := Anonymize(src, dst, execution.AnonymizeOptions{Salt: []byte("salt")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	req := &execution.Request{}
+	require.NoError(t, readJSON(filepath.Join(dst, "sub/1-2-getZkProof.json"), req))
+	assert.Equal(t, "exec-a", req.ConflatedExecutionTracesFile)
+
+	assert.NoFileExists(t, filepath.Join(dst, "sub/1-2-getZkProof.json"+ExpectationSuffix))
+	assert.NoFileExists(t, filepath.Join(dst, "5-6-getZkBlobCompressionProof.json"))
+}
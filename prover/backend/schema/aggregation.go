@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+)
+
+// MarshalAggregationRequest encodes the request as an AggregationRequest
+// message. The fields of the request that are not serialized in JSON are not
+// serialized either.
+func MarshalAggregationRequest(req *aggregation.Request) ([]byte, error) {
+
+	var e encoder
+
+	for i := range req.ExecutionProofs {
+		e.repeatedString(1, req.ExecutionProofs[i])
+	}
+	for i := range req.DecompressionProofs {
+		e.repeatedString(2, req.DecompressionProofs[i])
+	}
+	e.uint64(3, req.ParentAggregationLastBlockTimestamp)
+	e.string(4, req.ParentAggregationLastL1RollingHash)
+	e.int64(5, int64(req.ParentAggregationLastL1RollingHashMessageNumber))
+	e.bool(6, req.DeferEmulation)
+
+	return e.b, nil
+}
+
+// UnmarshalAggregationRequest decodes an AggregationRequest message into req
+func UnmarshalAggregationRequest(b []byte, req *aggregation.Request) error {
+
+	*req = aggregation.Request{}
+
+	return decodeFields(b, func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.appendString(&req.ExecutionProofs)
+		case 2:
+			return fd.appendString(&req.DecompressionProofs)
+		case 3:
+			return fd.uint64(&req.ParentAggregationLastBlockTimestamp)
+		case 4:
+			return fd.string(&req.ParentAggregationLastL1RollingHash)
+		case 5:
+			return fd.int(&req.ParentAggregationLastL1RollingHashMessageNumber)
+		case 6:
+			return fd.bool(&req.DeferEmulation)
+		}
+		return nil
+	})
+}
+
+// MarshalAggregationResponse encodes the response as an AggregationResponse
+// message.
+func MarshalAggregationResponse(resp *aggregation.Response) ([]byte, error) {
+
+	var e encoder
+
+	e.string(1, resp.FinalShnarf)
+	e.string(2, resp.ParentAggregationFinalShnarf)
+	e.string(3, resp.AggregatedProof)
+	e.string(4, resp.AggregatedProverVersion)
+	e.int64(5, int64(resp.AggregatedVerifierIndex))
+	e.string(6, resp.AggregatedProofPublicInput)
+	for i := range resp.DataHashes {
+		e.repeatedString(7, resp.DataHashes[i])
+	}
+	e.string(8, resp.DataParentHash)
+	e.string(9, resp.ParentStateRootHash)
+	e.uint64(10, uint64(resp.ParentAggregationLastBlockTimestamp))
+	e.uint64(11, uint64(resp.LastFinalizedBlockNumber))
+	e.uint64(12, uint64(resp.FinalTimestamp))
+	e.uint64(13, uint64(resp.FinalBlockNumber))
+	e.string(14, resp.L1RollingHash)
+	e.uint64(15, uint64(resp.L1RollingHashMessageNumber))
+	for i := range resp.L2MerkleRoots {
+		e.repeatedString(16, resp.L2MerkleRoots[i])
+	}
+	e.uint64(17, uint64(resp.L2MsgTreesDepth))
+	e.string(18, resp.L2MessagingBlocksOffsets)
+	e.string(19, resp.Bw6Proof)
+	e.int64(20, int64(resp.Bw6CircuitID))
+
+	return e.b, nil
+}
+
+// UnmarshalAggregationResponse decodes an AggregationResponse message into resp
+func UnmarshalAggregationResponse(b []byte, resp *aggregation.Response) error {
+
+	*resp = aggregation.Response{}
+
+	return decodeFields(b, func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.string(&resp.FinalShnarf)
+		case 2:
+			return fd.string(&resp.ParentAggregationFinalShnarf)
+		case 3:
+			return fd.string(&resp.AggregatedProof)
+		case 4:
+			return fd.string(&resp.AggregatedProverVersion)
+		case 5:
+			return fd.int(&resp.AggregatedVerifierIndex)
+		case 6:
+			return fd.string(&resp.AggregatedProofPublicInput)
+		case 7:
+			return fd.appendString(&resp.DataHashes)
+		case 8:
+			return fd.string(&resp.DataParentHash)
+		case 9:
+			return fd.string(&resp.ParentStateRootHash)
+		case 10:
+			return fd.uint(&resp.ParentAggregationLastBlockTimestamp)
+		case 11:
+			return fd.uint(&resp.LastFinalizedBlockNumber)
+		case 12:
+			return fd.uint(&resp.FinalTimestamp)
+		case 13:
+			return fd.uint(&resp.FinalBlockNumber)
+		case 14:
+			return fd.string(&resp.L1RollingHash)
+		case 15:
+			return fd.uint(&resp.L1RollingHashMessageNumber)
+		case 16:
+			return fd.appendString(&resp.L2MerkleRoots)
+		case 17:
+			return fd.uint(&resp.L2MsgTreesDepth)
+		case 18:
+			return fd.string(&resp.L2MessagingBlocksOffsets)
+		case 19:
+			return fd.string(&resp.Bw6Proof)
+		case 20:
+			return fd.int(&resp.Bw6CircuitID)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+)
+
+// MarshalBlobDecompressionRequest encodes the request as a
+// BlobDecompressionRequest message. It returns an error if the compressed data
+// is not a base64 string.
+func MarshalBlobDecompressionRequest(req *blobdecompression.Request) ([]byte, error) {
+	var e encoder
+	if err := encodeBlobDecompressionRequest(&e, req); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+// UnmarshalBlobDecompressionRequest decodes a BlobDecompressionRequest message
+// into req. The compressed data is converted back into a base64 string.
+func UnmarshalBlobDecompressionRequest(b []byte, req *blobdecompression.Request) error {
+	*req = blobdecompression.Request{}
+	return decodeFields(b, func(fd field) error { return decodeBlobDecompressionRequestField(fd, req) })
+}
+
+// MarshalBlobDecompressionResponse encodes the response as a
+// BlobDecompressionResponse message.
+func MarshalBlobDecompressionResponse(resp *blobdecompression.Response) ([]byte, error) {
+
+	var (
+		e   encoder
+		err error
+	)
+
+	e.message(1, func(e *encoder) { err = encodeBlobDecompressionRequest(e, &resp.Request) })
+	e.string(2, resp.ProverVersion)
+	e.string(3, resp.VerifyingKeyShaSum)
+	e.string(4, resp.DecompressionProof)
+	e.string(5, resp.Debug.PublicInput.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+
+	return e.b, nil
+}
+
+// UnmarshalBlobDecompressionResponse decodes a BlobDecompressionResponse
+// message into resp.
+func UnmarshalBlobDecompressionResponse(b []byte, resp *blobdecompression.Response) error {
+
+	*resp = blobdecompression.Response{}
+
+	return decodeFields(b, func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.message(func(fd field) error { return decodeBlobDecompressionRequestField(fd, &resp.Request) })
+		case 2:
+			return fd.string(&resp.ProverVersion)
+		case 3:
+			return fd.string(&resp.VerifyingKeyShaSum)
+		case 4:
+			return fd.string(&resp.DecompressionProof)
+		case 5:
+			return fd.text(&resp.Debug.PublicInput)
+		}
+		return nil
+	})
+}
+
+func encodeBlobDecompressionRequest(e *encoder, req *blobdecompression.Request) error {
+
+	data, err := base64.StdEncoding.DecodeString(req.CompressedData)
+	if err != nil {
+		return fmt.Errorf("compressedData: %w", err)
+	}
+
+	e.bool(1, req.Eip4844Enabled)
+	e.string(2, req.DataHash)
+	e.bytes(3, data)
+	e.string(4, req.Commitment)
+	e.string(5, req.KzgProofContract)
+	e.string(6, req.KzgProofSidecar)
+	e.string(7, req.ExpectedX)
+	e.string(8, req.ExpectedY)
+	e.string(9, req.SnarkHash)
+	e.message(10, func(e *encoder) {
+		e.int64(1, int64(req.ConflationOrder.StartingBlockNumber))
+		boundaries := make([]uint64, len(req.ConflationOrder.UpperBoundaries))
+		for i := range boundaries {
+			boundaries[i] = uint64(req.ConflationOrder.UpperBoundaries[i])
+		}
+		e.packed(2, boundaries)
+	})
+	e.string(11, req.ParentStateRootHash)
+	e.string(12, req.FinalStateRootHash)
+	e.string(13, req.DataParentHash)
+	e.string(14, req.ExpectedShnarf)
+	e.string(15, req.PrevShnarf)
+
+	return nil
+}
+
+func decodeBlobDecompressionRequestField(fd field, req *blobdecompression.Request) error {
+	switch fd.num {
+	case 1:
+		return fd.bool(&req.Eip4844Enabled)
+	case 2:
+		return fd.string(&req.DataHash)
+	case 3:
+		var data []byte
+		err := fd.bytes(&data)
+		req.CompressedData = base64.StdEncoding.EncodeToString(data)
+		return err
+	case 4:
+		return fd.string(&req.Commitment)
+	case 5:
+		return fd.string(&req.KzgProofContract)
+	case 6:
+		return fd.string(&req.KzgProofSidecar)
+	case 7:
+		return fd.string(&req.ExpectedX)
+	case 8:
+		return fd.string(&req.ExpectedY)
+	case 9:
+		return fd.string(&req.SnarkHash)
+	case 10:
+		return fd.message(func(fd field) error {
+			switch fd.num {
+			case 1:
+				return fd.int(&req.ConflationOrder.StartingBlockNumber)
+			case 2:
+				var boundaries []uint64
+				err := fd.appendVarints(&boundaries)
+				for _, v := range boundaries {
+					req.ConflationOrder.UpperBoundaries = append(req.ConflationOrder.UpperBoundaries, int(int64(v)))
+				}
+				return err
+			}
+			return nil
+		})
+	case 11:
+		return fd.string(&req.ParentStateRootHash)
+	case 12:
+		return fd.string(&req.FinalStateRootHash)
+	case 13:
+		return fd.string(&req.DataParentHash)
+	case 14:
+		return fd.string(&req.ExpectedShnarf)
+	case 15:
+		return fd.string(&req.PrevShnarf)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+// Package schema implements the protobuf encoding of the requests and the
+// responses of the prover, as specified in prover.proto. The protobuf payloads
+// are an alternative to the JSON ones: they carry the same information but the
+// hashes and the binary payloads (in particular, the RLP of the blocks of the
+// execution requests) are encoded as raw bytes instead of hexadecimal strings.
+// This makes the large execution requests significantly smaller and cheaper to
+// parse.
+//
+// The messages are encoded and decoded with [protowire] directly to avoid
+// depending on protoc in the build. The decoders skip the fields they do not
+// know, which is what allows the schema to evolve in a backward-compatible way
+// (see the rules in prover.proto).
+package schema
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Version is the protobuf package of the schema. It changes only on breaking
+// changes of the schema.
+const Version = "linea.prover.v1"
+
+// FileExtension is the extension of the files holding a protobuf payload
+const FileExtension = ".pb"
+
+// Marshal encodes a request or a response of any of the provers. It returns an
+// error if the type of v is not part of the schema.
+func Marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case *execution.Request:
+		return MarshalExecutionRequest(v)
+	case *execution.Response:
+		return MarshalExecutionResponse(v)
+	case *blobdecompression.Request:
+		return MarshalBlobDecompressionRequest(v)
+	case *blobdecompression.Response:
+		return MarshalBlobDecompressionResponse(v)
+	case *aggregation.Request:
+		return MarshalAggregationRequest(v)
+	case *aggregation.Response:
+		return MarshalAggregationResponse(v)
+	}
+	return nil, fmt.Errorf("type %T is not part of the schema", v)
+}
+
+// Unmarshal decodes b into a request or a response of any of the provers. It
+// returns an error if the type of v is not part of the schema.
+func Unmarshal(b []byte, v any) error {
+	switch v := v.(type) {
+	case *execution.Request:
+		return UnmarshalExecutionRequest(b, v)
+	case *execution.Response:
+		return UnmarshalExecutionResponse(b, v)
+	case *blobdecompression.Request:
+		return UnmarshalBlobDecompressionRequest(b, v)
+	case *blobdecompression.Response:
+		return UnmarshalBlobDecompressionResponse(b, v)
+	case *aggregation.Request:
+		return UnmarshalAggregationRequest(b, v)
+	case *aggregation.Response:
+		return UnmarshalAggregationResponse(b, v)
+	}
+	return fmt.Errorf("type %T is not part of the schema", v)
+}
+
+// encoder appends the fields of a message. Following the proto3 semantic, the
+// scalar fields holding a zero value are not written.
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) tag(num protowire.Number, typ protowire.Type) {
+	e.b = protowire.AppendTag(e.b, num, typ)
+}
+
+func (e *encoder) uint64(num protowire.Number, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(num, protowire.VarintType)
+	e.b = protowire.AppendVarint(e.b, v)
+}
+
+func (e *encoder) int64(num protowire.Number, v int64) {
+	e.uint64(num, uint64(v))
+}
+
+func (e *encoder) bool(num protowire.Number, v bool) {
+	if v {
+		e.uint64(num, 1)
+	}
+}
+
+func (e *encoder) bytes(num protowire.Number, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	e.repeatedBytes(num, v)
+}
+
+// fixed writes a fixed-size array (a hash or an address). As for the other
+// scalar fields, the zero value is not written.
+func (e *encoder) fixed(num protowire.Number, v []byte) {
+	for i := range v {
+		if v[i] != 0 {
+			e.repeatedBytes(num, v)
+			return
+		}
+	}
+}
+
+// repeatedBytes writes v even if it is empty, as required for the elements
+// of a repeated field.
+func (e *encoder) repeatedBytes(num protowire.Number, v []byte) {
+	e.tag(num, protowire.BytesType)
+	e.b = protowire.AppendBytes(e.b, v)
+}
+
+func (e *encoder) string(num protowire.Number, v string) {
+	if len(v) == 0 {
+		return
+	}
+	e.repeatedString(num, v)
+}
+
+func (e *encoder) repeatedString(num protowire.Number, v string) {
+	e.tag(num, protowire.BytesType)
+	e.b = protowire.AppendString(e.b, v)
+}
+
+// packed writes a packed repeated varint field
+func (e *encoder) packed(num protowire.Number, v []uint64) {
+	if len(v) == 0 {
+		return
+	}
+	var inner []byte
+	for i := range v {
+		inner = protowire.AppendVarint(inner, v[i])
+	}
+	e.bytes(num, inner)
+}
+
+// message writes a sub-message. The sub-message is always written, even if
+// empty, so that the elements of the repeated fields and the optional
+// sub-messages are preserved.
+func (e *encoder) message(num protowire.Number, encode func(e *encoder)) {
+	var sub encoder
+	encode(&sub)
+	e.repeatedBytes(num, sub.b)
+}
+
+// field is a field of a message read by [decodeFields]
+type field struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	raw    []byte
+}
+
+// decodeFields calls f on every field of the message b in order. The fields
+// whose wire type is neither varint nor length-delimited are skipped as they
+// are not used by the schema.
+func decodeFields(b []byte, f func(fd field) error) error {
+
+	for len(b) > 0 {
+
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		fd := field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			fd.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			fd.raw, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if n < 0 {
+			return fmt.Errorf("field %v: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if typ != protowire.VarintType && typ != protowire.BytesType {
+			continue
+		}
+
+		if err := f(fd); err != nil {
+			return fmt.Errorf("field %v: %w", num, err)
+		}
+	}
+
+	return nil
+}
+
+func (fd field) expect(typ protowire.Type) error {
+	if fd.typ != typ {
+		return fmt.Errorf("unexpected wire type %v, expected %v", fd.typ, typ)
+	}
+	return nil
+}
+
+func (fd field) uint64(dst *uint64) error {
+	*dst = fd.varint
+	return fd.expect(protowire.VarintType)
+}
+
+func (fd field) int64(dst *int64) error {
+	*dst = int64(fd.varint)
+	return fd.expect(protowire.VarintType)
+}
+
+func (fd field) uint(dst *uint) error {
+	*dst = uint(fd.varint)
+	return fd.expect(protowire.VarintType)
+}
+
+func (fd field) int(dst *int) error {
+	*dst = int(int64(fd.varint))
+	return fd.expect(protowire.VarintType)
+}
+
+func (fd field) bool(dst *bool) error {
+	*dst = fd.varint != 0
+	return fd.expect(protowire.VarintType)
+}
+
+func (fd field) string(dst *string) error {
+	*dst = string(fd.raw)
+	return fd.expect(protowire.BytesType)
+}
+
+// text decodes a string field with the [encoding.TextUnmarshaler] of dst
+func (fd field) text(dst encoding.TextUnmarshaler) error {
+	if err := fd.expect(protowire.BytesType); err != nil {
+		return err
+	}
+	return dst.UnmarshalText(fd.raw)
+}
+
+// appendString appends the field to a repeated string field
+func (fd field) appendString(dst *[]string) error {
+	*dst = append(*dst, string(fd.raw))
+	return fd.expect(protowire.BytesType)
+}
+
+// bytes copies the field as it would otherwise alias the payload
+func (fd field) bytes(dst *[]byte) error {
+	*dst = append([]byte{}, fd.raw...)
+	return fd.expect(protowire.BytesType)
+}
+
+// fixed copies the field in dst, which is a fixed-size array (a hash or an
+// address).
+func (fd field) fixed(dst []byte) error {
+	if err := fd.expect(protowire.BytesType); err != nil {
+		return err
+	}
+	if len(fd.raw) != len(dst) {
+		return fmt.Errorf("expected %v bytes, got %v", len(dst), len(fd.raw))
+	}
+	copy(dst, fd.raw)
+	return nil
+}
+
+// message decodes the field as a sub-message, see [decodeFields]
+func (fd field) message(f func(fd field) error) error {
+	if err := fd.expect(protowire.BytesType); err != nil {
+		return err
+	}
+	return decodeFields(fd.raw, f)
+}
+
+// appendVarints appends the field to a repeated varint field. Per the
+// protobuf specification, it accepts both the packed and the unpacked
+// encodings.
+func (fd field) appendVarints(dst *[]uint64) error {
+
+	if fd.typ == protowire.VarintType {
+		*dst = append(*dst, fd.varint)
+		return nil
+	}
+
+	if err := fd.expect(protowire.BytesType); err != nil {
+		return err
+	}
+
+	for b := fd.raw; len(b) > 0; {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		*dst = append(*dst, v)
+		b = b[n:]
+	}
+
+	return nil
+}
@@ -0,0 +1,374 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"math"
+	"slices"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// MarshalExecutionRequest encodes the request as an ExecutionRequest message.
+// It returns an error if the RLP of a block is not an hexstring.
+func MarshalExecutionRequest(req *execution.Request) ([]byte, error) {
+
+	var (
+		e    encoder
+		errs error
+	)
+
+	e.fixed(1, req.ZkParentStateRootHash[:])
+	e.string(2, req.ConflatedExecutionTracesFile)
+	e.string(3, req.TracesEngineVersion)
+	e.string(4, req.Type2StateManagerVersion)
+
+	for i := range req.ZkStateMerkleProof {
+		e.message(5, func(e *encoder) {
+			for j := range req.ZkStateMerkleProof[i] {
+				b, err := json.Marshal(req.ZkStateMerkleProof[i][j])
+				if err != nil {
+					errs = fmt.Errorf("zkStateMerkleProof[%v][%v]: %w", i, j, err)
+				}
+				e.repeatedBytes(1, b)
+			}
+		})
+	}
+
+	for i := range req.BlocksData {
+		blk := &req.BlocksData[i]
+		e.message(6, func(e *encoder) {
+			rlp, err := utils.HexDecodeString(blk.Rlp)
+			if err != nil {
+				errs = fmt.Errorf("blocksData[%v].rlp: %w", i, err)
+			}
+			e.bytes(1, rlp)
+			for j := range blk.BridgeLogs {
+				e.message(2, func(e *encoder) { encodeLog(e, &blk.BridgeLogs[j]) })
+			}
+		})
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return e.b, nil
+}
+
+// UnmarshalExecutionRequest decodes an ExecutionRequest message into req. The
+// RLP of the blocks is converted back into a 0x-prefixed hexstring.
+func UnmarshalExecutionRequest(b []byte, req *execution.Request) error {
+
+	*req = execution.Request{}
+
+	return decodeFields(b, func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.fixed(req.ZkParentStateRootHash[:])
+		case 2:
+			return fd.string(&req.ConflatedExecutionTracesFile)
+		case 3:
+			return fd.string(&req.TracesEngineVersion)
+		case 4:
+			return fd.string(&req.Type2StateManagerVersion)
+		case 5:
+			traces := []statemanager.DecodedTrace{}
+			err := fd.message(func(fd field) error {
+				if fd.num != 1 {
+					return nil
+				}
+				var trace statemanager.DecodedTrace
+				if err := fd.expect(protowire.BytesType); err != nil {
+					return err
+				}
+				if err := json.Unmarshal(fd.raw, &trace); err != nil {
+					return err
+				}
+				traces = append(traces, trace)
+				return nil
+			})
+			req.ZkStateMerkleProof = append(req.ZkStateMerkleProof, traces)
+			return err
+		case 6:
+			req.BlocksData = slices.Grow(req.BlocksData, 1)[:len(req.BlocksData)+1]
+			blk := &req.BlocksData[len(req.BlocksData)-1]
+			return fd.message(func(fd field) error {
+				switch fd.num {
+				case 1:
+					var rlp []byte
+					err := fd.bytes(&rlp)
+					blk.Rlp = utils.HexEncodeToString(rlp)
+					return err
+				case 2:
+					var log ethtypes.Log
+					err := decodeLog(fd, &log)
+					blk.BridgeLogs = append(blk.BridgeLogs, log)
+					return err
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+func encodeLog(e *encoder, log *ethtypes.Log) {
+	e.fixed(1, log.Address[:])
+	for i := range log.Topics {
+		e.repeatedBytes(2, log.Topics[i][:])
+	}
+	e.bytes(3, log.Data)
+	e.uint64(4, log.BlockNumber)
+	e.fixed(5, log.TxHash[:])
+	e.uint64(6, uint64(log.TxIndex))
+	e.fixed(7, log.BlockHash[:])
+	e.uint64(8, uint64(log.Index))
+	e.bool(9, log.Removed)
+}
+
+// decodeLog decodes a Log message. Like the JSON decoder of go-ethereum, it
+// leaves the topics and the data non-nil.
+func decodeLog(fd field, log *ethtypes.Log) error {
+
+	log.Topics = []common.Hash{}
+	log.Data = []byte{}
+
+	return fd.message(func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.fixed(log.Address[:])
+		case 2:
+			var topic common.Hash
+			err := fd.fixed(topic[:])
+			log.Topics = append(log.Topics, topic)
+			return err
+		case 3:
+			return fd.bytes(&log.Data)
+		case 4:
+			return fd.uint64(&log.BlockNumber)
+		case 5:
+			return fd.fixed(log.TxHash[:])
+		case 6:
+			return fd.uint(&log.TxIndex)
+		case 7:
+			return fd.fixed(log.BlockHash[:])
+		case 8:
+			return fd.uint(&log.Index)
+		case 9:
+			return fd.bool(&log.Removed)
+		}
+		return nil
+	})
+}
+
+// MarshalExecutionResponse encodes the response as an ExecutionResponse
+// message.
+func MarshalExecutionResponse(resp *execution.Response) ([]byte, error) {
+
+	var e encoder
+
+	e.string(1, resp.Proof)
+	e.string(2, string(resp.ProverMode))
+	e.uint64(3, uint64(resp.VerifierIndex))
+	e.string(4, resp.VerifyingKeyShaSum)
+	for i := range resp.BlocksData {
+		blk := &resp.BlocksData[i]
+		e.message(5, func(e *encoder) { encodeExecutionBlockData(e, blk) })
+	}
+	e.string(6, resp.ParentStateRootHash)
+	e.bool(7, resp.HasParentStateRootHashMismatch)
+	e.string(8, resp.Version)
+	e.int64(9, int64(resp.FirstBlockNumber))
+	e.fixed(10, resp.ExecDataChecksum[:])
+	e.uint64(11, uint64(resp.ChainID))
+	e.fixed(12, resp.L2BridgeAddress[:])
+	e.int64(13, int64(resp.MaxNbL2MessageHashes))
+	for i := range resp.AllRollingHashEvent {
+		ev := &resp.AllRollingHashEvent[i]
+		e.message(14, func(e *encoder) { encodeRollingHashUpdated(e, ev) })
+	}
+	for i := range resp.AllL2L1MessageHashes {
+		e.repeatedBytes(15, resp.AllL2L1MessageHashes[i][:])
+	}
+	pi := (*fr.Element)(&resp.PublicInput).Bytes()
+	e.fixed(16, pi[:])
+	for i := range resp.ModuleUsage {
+		u := &resp.ModuleUsage[i]
+		e.message(17, func(e *encoder) {
+			e.string(1, u.Module)
+			e.uint64(2, uint64(u.Rows))
+			e.uint64(3, uint64(u.Limit))
+		})
+	}
+
+	return e.b, nil
+}
+
+// UnmarshalExecutionResponse decodes an ExecutionResponse message into resp
+func UnmarshalExecutionResponse(b []byte, resp *execution.Response) error {
+
+	*resp = execution.Response{}
+
+	return decodeFields(b, func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.string(&resp.Proof)
+		case 2:
+			var mode string
+			err := fd.string(&mode)
+			resp.ProverMode = config.ProverMode(mode)
+			return err
+		case 3:
+			return fd.uint(&resp.VerifierIndex)
+		case 4:
+			return fd.string(&resp.VerifyingKeyShaSum)
+		case 5:
+			var blk execution.BlockData
+			err := decodeExecutionBlockData(fd, &blk)
+			resp.BlocksData = append(resp.BlocksData, blk)
+			return err
+		case 6:
+			return fd.string(&resp.ParentStateRootHash)
+		case 7:
+			return fd.bool(&resp.HasParentStateRootHashMismatch)
+		case 8:
+			return fd.string(&resp.Version)
+		case 9:
+			return fd.int(&resp.FirstBlockNumber)
+		case 10:
+			return fd.fixed(resp.ExecDataChecksum[:])
+		case 11:
+			return fd.uint(&resp.ChainID)
+		case 12:
+			return fd.fixed(resp.L2BridgeAddress[:])
+		case 13:
+			return fd.int(&resp.MaxNbL2MessageHashes)
+		case 14:
+			var ev bridge.RollingHashUpdated
+			err := decodeRollingHashUpdated(fd, &ev)
+			resp.AllRollingHashEvent = append(resp.AllRollingHashEvent, ev)
+			return err
+		case 15:
+			var h types.FullBytes32
+			err := fd.fixed(h[:])
+			resp.AllL2L1MessageHashes = append(resp.AllL2L1MessageHashes, h)
+			return err
+		case 16:
+			var pi [fr.Bytes]byte
+			if err := fd.fixed(pi[:]); err != nil {
+				return err
+			}
+			return (*fr.Element)(&resp.PublicInput).SetBytesCanonical(pi[:])
+		case 17:
+			var u arithmetization.ModuleUsage
+			err := fd.message(func(fd field) error {
+				switch fd.num {
+				case 1:
+					return fd.string(&u.Module)
+				case 2:
+					return fd.int(&u.Rows)
+				case 3:
+					return fd.int(&u.Limit)
+				}
+				return nil
+			})
+			resp.ModuleUsage = append(resp.ModuleUsage, u)
+			return err
+		}
+		return nil
+	})
+}
+
+func encodeExecutionBlockData(e *encoder, blk *execution.BlockData) {
+
+	e.fixed(1, blk.BlockHash[:])
+	for i := range blk.RlpEncodedTransactions {
+		e.repeatedString(2, blk.RlpEncodedTransactions[i])
+	}
+	for i := range blk.L2ToL1MsgHashes {
+		e.repeatedBytes(3, blk.L2ToL1MsgHashes[i][:])
+	}
+	e.uint64(4, blk.TimeStamp)
+	e.fixed(5, blk.RootHash[:])
+	for i := range blk.FromAddresses {
+		e.repeatedBytes(6, blk.FromAddresses[i][:])
+	}
+
+	indices := make([]uint64, len(blk.BatchReceptionIndices))
+	for i := range indices {
+		indices[i] = uint64(blk.BatchReceptionIndices[i])
+	}
+	e.packed(7, indices)
+
+	e.message(8, func(e *encoder) { encodeRollingHashUpdated(e, &blk.LastRollingHashUpdatedEvent) })
+}
+
+func decodeExecutionBlockData(fd field, blk *execution.BlockData) error {
+
+	return fd.message(func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.fixed(blk.BlockHash[:])
+		case 2:
+			return fd.appendString(&blk.RlpEncodedTransactions)
+		case 3:
+			var h types.FullBytes32
+			err := fd.fixed(h[:])
+			blk.L2ToL1MsgHashes = append(blk.L2ToL1MsgHashes, h)
+			return err
+		case 4:
+			return fd.uint64(&blk.TimeStamp)
+		case 5:
+			return fd.fixed(blk.RootHash[:])
+		case 6:
+			var a types.EthAddress
+			err := fd.fixed(a[:])
+			blk.FromAddresses = append(blk.FromAddresses, a)
+			return err
+		case 7:
+			var indices []uint64
+			if err := fd.appendVarints(&indices); err != nil {
+				return err
+			}
+			for _, v := range indices {
+				if v > math.MaxUint16 {
+					return fmt.Errorf("batch reception index %v overflows an uint16", v)
+				}
+				blk.BatchReceptionIndices = append(blk.BatchReceptionIndices, uint16(v))
+			}
+			return nil
+		case 8:
+			return decodeRollingHashUpdated(fd, &blk.LastRollingHashUpdatedEvent)
+		}
+		return nil
+	})
+}
+
+func encodeRollingHashUpdated(e *encoder, ev *bridge.RollingHashUpdated) {
+	e.int64(1, ev.MessageNumber)
+	e.fixed(2, ev.RollingHash[:])
+}
+
+func decodeRollingHashUpdated(fd field, ev *bridge.RollingHashUpdated) error {
+
+	return fd.message(func(fd field) error {
+		switch fd.num {
+		case 1:
+			return fd.int64(&ev.MessageNumber)
+		case 2:
+			return fd.fixed(ev.RollingHash[:])
+		}
+		return nil
+	})
+}
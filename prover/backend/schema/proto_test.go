@@ -0,0 +1,280 @@
+package schema
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TestCodecMatchesProto checks the hand-written codec against prover.proto.
+// The payloads it writes are decoded by the protobuf runtime using the
+// descriptors of prover.proto, then re-encoded by the runtime and decoded by
+// the codec. The test fails if the codec writes a field with a number or a
+// wire type that does not match the .proto, if it does not write some of the
+// fields of the .proto or if it does not read back what the runtime writes.
+func TestCodecMatchesProto(t *testing.T) {
+
+	file := parseProto(t, "prover.proto")
+
+	// The random requests do not hold state-manager traces, some are taken
+	// from the test vectors of the state-manager.
+	f, err := os.Open("../execution/statemanager/testdata/read-account.json")
+	require.NoError(t, err)
+	var shomei statemanager.ShomeiOutput
+	require.NoError(t, json.NewDecoder(f).Decode(&shomei))
+	require.NoError(t, f.Close())
+
+	type testCase struct {
+		message   protoreflect.Name
+		rand      func(rng *rand.Rand) any
+		marshal   func(v any) ([]byte, error)
+		unmarshal func(b []byte) (any, error)
+	}
+
+	testCases := []testCase{
+		{
+			message: "ExecutionRequest",
+			rand: func(rng *rand.Rand) any {
+				req := randExecutionRequest(rng)
+				for i := range req.ZkStateMerkleProof {
+					req.ZkStateMerkleProof[i] = shomei.Result.ZkStateMerkleProof[0]
+				}
+				return req
+			},
+			marshal:   func(v any) ([]byte, error) { return MarshalExecutionRequest(v.(*execution.Request)) },
+			unmarshal: func(b []byte) (any, error) { v := &execution.Request{}; return v, UnmarshalExecutionRequest(b, v) },
+		},
+		{
+			message:   "ExecutionResponse",
+			rand:      func(rng *rand.Rand) any { return randExecutionResponse(rng) },
+			marshal:   func(v any) ([]byte, error) { return MarshalExecutionResponse(v.(*execution.Response)) },
+			unmarshal: func(b []byte) (any, error) { v := &execution.Response{}; return v, UnmarshalExecutionResponse(b, v) },
+		},
+		{
+			message: "BlobDecompressionRequest",
+			rand:    func(rng *rand.Rand) any { return &randBlobDecompressionResponse(rng).Request },
+			marshal: func(v any) ([]byte, error) {
+				return MarshalBlobDecompressionRequest(v.(*blobdecompression.Request))
+			},
+			unmarshal: func(b []byte) (any, error) {
+				v := &blobdecompression.Request{}
+				return v, UnmarshalBlobDecompressionRequest(b, v)
+			},
+		},
+		{
+			message: "BlobDecompressionResponse",
+			rand:    func(rng *rand.Rand) any { return randBlobDecompressionResponse(rng) },
+			marshal: func(v any) ([]byte, error) {
+				return MarshalBlobDecompressionResponse(v.(*blobdecompression.Response))
+			},
+			unmarshal: func(b []byte) (any, error) {
+				v := &blobdecompression.Response{}
+				return v, UnmarshalBlobDecompressionResponse(b, v)
+			},
+		},
+		{
+			message:   "AggregationRequest",
+			rand:      func(rng *rand.Rand) any { return randAggregationRequest(rng) },
+			marshal:   func(v any) ([]byte, error) { return MarshalAggregationRequest(v.(*aggregation.Request)) },
+			unmarshal: func(b []byte) (any, error) { v := &aggregation.Request{}; return v, UnmarshalAggregationRequest(b, v) },
+		},
+		{
+			message: "AggregationResponse",
+			rand:    func(rng *rand.Rand) any { return randAggregationResponse(rng) },
+			marshal: func(v any) ([]byte, error) { return MarshalAggregationResponse(v.(*aggregation.Response)) },
+			unmarshal: func(b []byte) (any, error) {
+				v := &aggregation.Response{}
+				return v, UnmarshalAggregationResponse(b, v)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.message), func(t *testing.T) {
+
+			desc := file.Messages().ByName(tc.message)
+			require.NotNil(t, desc, "message not found in prover.proto")
+
+			// The fields written at least once over all the seeds
+			written := map[protoreflect.FullName]struct{}{}
+
+			for seed := int64(0); seed < 16; seed++ {
+
+				v := tc.rand(rand.New(rand.NewSource(seed)))
+
+				b, err := tc.marshal(v)
+				require.NoError(t, err)
+
+				msg := dynamicpb.NewMessage(desc)
+				require.NoError(t, proto.Unmarshal(b, msg))
+				checkNoUnknownFields(t, msg, written)
+
+				b, err = proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+				require.NoError(t, err)
+
+				decoded, err := tc.unmarshal(b)
+				require.NoError(t, err)
+				require.Equal(t, v, decoded)
+			}
+
+			checkAllFieldsWritten(t, desc, written, map[protoreflect.FullName]bool{})
+		})
+	}
+}
+
+// checkNoUnknownFields fails if the runtime did not recognize some of the
+// fields of msg, which happens when the codec writes a field with a number or
+// a wire type not matching the .proto. The fields set in msg are recorded in
+// written.
+func checkNoUnknownFields(t *testing.T, msg protoreflect.Message, written map[protoreflect.FullName]struct{}) {
+
+	require.Empty(t, msg.GetUnknown(), "%v has unknown fields", msg.Descriptor().FullName())
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		written[fd.FullName()] = struct{}{}
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if fd.IsList() {
+			for i := 0; i < v.List().Len(); i++ {
+				checkNoUnknownFields(t, v.List().Get(i).Message(), written)
+			}
+			return true
+		}
+		checkNoUnknownFields(t, v.Message(), written)
+		return true
+	})
+}
+
+// checkAllFieldsWritten fails if one of the fields of desc, or of the messages
+// it contains, is missing from written. The deprecated fields are not
+// expected to be written.
+func checkAllFieldsWritten(t *testing.T, desc protoreflect.MessageDescriptor, written map[protoreflect.FullName]struct{}, visited map[protoreflect.FullName]bool) {
+
+	if visited[desc.FullName()] {
+		return
+	}
+	visited[desc.FullName()] = true
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDeprecated() {
+			continue
+		}
+		_, ok := written[fd.FullName()]
+		require.True(t, ok, "%v is never written by the codec", fd.FullName())
+		if fd.Kind() == protoreflect.MessageKind {
+			checkAllFieldsWritten(t, fd.Message(), written, visited)
+		}
+	}
+}
+
+var (
+	protoPackageRe = regexp.MustCompile(`^package\s+([\w.]+)\s*;$`)
+	protoMessageRe = regexp.MustCompile(`^message\s+(\w+)\s*\{$`)
+	protoFieldRe   = regexp.MustCompile(`^(repeated\s+)?(\w+)\s+(\w+)\s*=\s*(\d+)\s*(\[\s*deprecated\s*=\s*true\s*\])?\s*;$`)
+)
+
+// protoScalarTypes maps the scalar types used in prover.proto to their
+// descriptor type
+var protoScalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"bytes":  descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bool":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"uint32": descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"uint64": descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"int32":  descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"int64":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+}
+
+// parseProto builds the descriptor of a .proto file. It only supports the
+// subset of the language used by prover.proto: top-level messages whose
+// fields are scalars or messages, optionally repeated or deprecated. It fails
+// on any other statement so that the test cannot silently ignore a part of
+// the schema.
+func parseProto(t *testing.T, path string) protoreflect.FileDescriptor {
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var (
+		fdp = &descriptorpb.FileDescriptorProto{
+			Name:   proto.String(path),
+			Syntax: proto.String("proto3"),
+		}
+		curr    *descriptorpb.DescriptorProto
+		scanner = bufio.NewScanner(f)
+		lineNum = 0
+	)
+
+	for scanner.Scan() {
+
+		lineNum++
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "" || line == `syntax = "proto3";`:
+		case protoPackageRe.MatchString(line):
+			fdp.Package = proto.String(protoPackageRe.FindStringSubmatch(line)[1])
+		case curr == nil && protoMessageRe.MatchString(line):
+			curr = &descriptorpb.DescriptorProto{Name: proto.String(protoMessageRe.FindStringSubmatch(line)[1])}
+		case curr != nil && line == "}":
+			fdp.MessageType = append(fdp.MessageType, curr)
+			curr = nil
+		case curr != nil && protoFieldRe.MatchString(line):
+			m := protoFieldRe.FindStringSubmatch(line)
+			num, err := strconv.Atoi(m[4])
+			require.NoError(t, err)
+
+			field := &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(m[3]),
+				JsonName: proto.String(m[3]),
+				Number:   proto.Int32(int32(num)),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}
+			if m[1] != "" {
+				field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			}
+			if typ, ok := protoScalarTypes[m[2]]; ok {
+				field.Type = typ.Enum()
+			} else {
+				field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+				field.TypeName = proto.String("." + fdp.GetPackage() + "." + m[2])
+			}
+			if m[5] != "" {
+				field.Options = &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)}
+			}
+			curr.Field = append(curr.Field, field)
+		default:
+			t.Fatalf("%v:%v: unsupported statement %q", path, lineNum, line)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.Nil(t, curr, "unterminated message")
+
+	file, err := protodesc.NewFile(fdp, nil)
+	require.NoError(t, err)
+	return file
+}
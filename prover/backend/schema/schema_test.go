@@ -0,0 +1,454 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func FuzzExecutionRequest(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		rng := rand.New(rand.NewSource(seed))
+		req := randExecutionRequest(rng)
+
+		b, err := MarshalExecutionRequest(req)
+		require.NoError(t, err)
+
+		var decoded execution.Request
+		require.NoError(t, UnmarshalExecutionRequest(b, &decoded))
+		require.Equal(t, req, &decoded)
+	})
+}
+
+func FuzzExecutionResponse(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		rng := rand.New(rand.NewSource(seed))
+		resp := randExecutionResponse(rng)
+
+		b, err := MarshalExecutionResponse(resp)
+		require.NoError(t, err)
+
+		var decoded execution.Response
+		require.NoError(t, UnmarshalExecutionResponse(b, &decoded))
+		require.Equal(t, resp, &decoded)
+	})
+}
+
+func FuzzBlobDecompression(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		rng := rand.New(rand.NewSource(seed))
+		resp := randBlobDecompressionResponse(rng)
+
+		b, err := MarshalBlobDecompressionRequest(&resp.Request)
+		require.NoError(t, err)
+
+		var decodedReq blobdecompression.Request
+		require.NoError(t, UnmarshalBlobDecompressionRequest(b, &decodedReq))
+		require.Equal(t, resp.Request, decodedReq)
+
+		b, err = MarshalBlobDecompressionResponse(resp)
+		require.NoError(t, err)
+
+		var decodedResp blobdecompression.Response
+		require.NoError(t, UnmarshalBlobDecompressionResponse(b, &decodedResp))
+		require.Equal(t, resp, &decodedResp)
+	})
+}
+
+func FuzzAggregation(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		rng := rand.New(rand.NewSource(seed))
+		req, resp := randAggregationRequest(rng), randAggregationResponse(rng)
+
+		b, err := MarshalAggregationRequest(req)
+		require.NoError(t, err)
+
+		var decodedReq aggregation.Request
+		require.NoError(t, UnmarshalAggregationRequest(b, &decodedReq))
+		require.Equal(t, req, &decodedReq)
+
+		b, err = MarshalAggregationResponse(resp)
+		require.NoError(t, err)
+
+		var decodedResp aggregation.Response
+		require.NoError(t, UnmarshalAggregationResponse(b, &decodedResp))
+		require.Equal(t, resp, &decodedResp)
+	})
+}
+
+// FuzzUnmarshal checks that the decoders return an error instead of
+// panicking on arbitrary payloads.
+func FuzzUnmarshal(f *testing.F) {
+
+	for seed := int64(0); seed < 4; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		b, err := MarshalExecutionResponse(randExecutionResponse(rng))
+		require.NoError(f, err)
+		f.Add(b)
+		b, err = MarshalBlobDecompressionResponse(randBlobDecompressionResponse(rng))
+		require.NoError(f, err)
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		_ = UnmarshalExecutionRequest(b, &execution.Request{})
+		_ = UnmarshalExecutionResponse(b, &execution.Response{})
+		_ = UnmarshalBlobDecompressionRequest(b, &blobdecompression.Request{})
+		_ = UnmarshalBlobDecompressionResponse(b, &blobdecompression.Response{})
+		_ = UnmarshalAggregationRequest(b, &aggregation.Request{})
+		_ = UnmarshalAggregationResponse(b, &aggregation.Response{})
+	})
+}
+
+// TestUnknownFields checks that a payload written by a newer version of the
+// schema is still readable.
+func TestUnknownFields(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(0))
+	resp := randExecutionResponse(rng)
+
+	b, err := MarshalExecutionResponse(resp)
+	require.NoError(t, err)
+
+	// A varint, a length-delimited, a fixed32 and a fixed64 field unknown to
+	// the decoder.
+	b = protowire.AppendTag(b, 100, protowire.VarintType)
+	b = protowire.AppendVarint(b, 42)
+	b = protowire.AppendTag(b, 101, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte("from the future"))
+	b = protowire.AppendTag(b, 102, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 42)
+	b = protowire.AppendTag(b, 103, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, 42)
+
+	var decoded execution.Response
+	require.NoError(t, UnmarshalExecutionResponse(b, &decoded))
+	require.Equal(t, resp, &decoded)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+
+	// proof (field 1) sent as a varint
+	b := protowire.AppendTag(nil, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	require.Error(t, UnmarshalExecutionResponse(b, &execution.Response{}))
+
+	// exec_data_checksum (field 10) with a wrong length
+	b = protowire.AppendTag(nil, 10, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte{1, 2, 3})
+	require.Error(t, UnmarshalExecutionResponse(b, &execution.Response{}))
+
+	// truncated payload
+	b, err := MarshalExecutionResponse(randExecutionResponse(rand.New(rand.NewSource(0))))
+	require.NoError(t, err)
+	require.Error(t, UnmarshalExecutionResponse(b[:len(b)-1], &execution.Response{}))
+
+	// invalid base64 in the request
+	req := &blobdecompression.Request{CompressedData: "not base64!"}
+	_, err = MarshalBlobDecompressionRequest(req)
+	require.Error(t, err)
+}
+
+func TestExecutionRequestIsSmallerThanJSON(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(0))
+	req := randExecutionRequest(rng)
+
+	pb, err := MarshalExecutionRequest(req)
+	require.NoError(t, err)
+	js, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	require.Less(t, len(pb), len(js))
+}
+
+func addSeeds(f *testing.F) {
+	for seed := int64(0); seed < 8; seed++ {
+		f.Add(seed)
+	}
+}
+
+func randExecutionRequest(rng *rand.Rand) *execution.Request {
+
+	req := &execution.Request{
+		ConflatedExecutionTracesFile: randString(rng),
+		TracesEngineVersion:          randString(rng),
+		Type2StateManagerVersion:     randString(rng),
+	}
+	rng.Read(req.ZkParentStateRootHash[:])
+
+	if n := rng.Intn(4); n > 0 {
+		req.ZkStateMerkleProof = make([][]statemanager.DecodedTrace, n)
+		req.BlocksData = make([]struct {
+			Rlp        string         `json:"rlp"`
+			BridgeLogs []ethtypes.Log `json:"bridgeLogs"`
+		}, n)
+	}
+
+	for i := range req.ZkStateMerkleProof {
+		req.ZkStateMerkleProof[i] = []statemanager.DecodedTrace{}
+	}
+
+	for i := range req.BlocksData {
+		req.BlocksData[i].Rlp = hexutil.Encode(randBytes(rng, 1+rng.Intn(512)))
+		if n := rng.Intn(3); n > 0 {
+			req.BlocksData[i].BridgeLogs = make([]ethtypes.Log, n)
+		}
+		for j := range req.BlocksData[i].BridgeLogs {
+			req.BlocksData[i].BridgeLogs[j] = randLog(rng)
+		}
+	}
+
+	return req
+}
+
+func randLog(rng *rand.Rand) ethtypes.Log {
+	log := ethtypes.Log{
+		Topics:      make([]common.Hash, rng.Intn(4)),
+		Data:        randBytes(rng, rng.Intn(64)),
+		BlockNumber: rng.Uint64(),
+		TxIndex:     uint(rng.Uint32()),
+		Index:       uint(rng.Uint32()),
+		Removed:     rng.Intn(2) == 1,
+	}
+	rng.Read(log.Address[:])
+	rng.Read(log.TxHash[:])
+	rng.Read(log.BlockHash[:])
+	for i := range log.Topics {
+		rng.Read(log.Topics[i][:])
+	}
+	return log
+}
+
+func randExecutionResponse(rng *rand.Rand) *execution.Response {
+
+	resp := &execution.Response{
+		Proof:                          randString(rng),
+		ProverMode:                     config.ProverMode(randString(rng)),
+		VerifierIndex:                  uint(rng.Intn(4)),
+		VerifyingKeyShaSum:             randString(rng),
+		ParentStateRootHash:            randString(rng),
+		HasParentStateRootHashMismatch: rng.Intn(2) == 1,
+		Version:                        randString(rng),
+		FirstBlockNumber:               rng.Int(),
+		ChainID:                        uint(rng.Uint32()),
+		MaxNbL2MessageHashes:           rng.Intn(1 << 10),
+	}
+	rng.Read(resp.ExecDataChecksum[:])
+	rng.Read(resp.L2BridgeAddress[:])
+	var pi [fr.Bytes]byte
+	rng.Read(pi[:])
+	(*fr.Element)(&resp.PublicInput).SetBytes(pi[:])
+
+	if n := rng.Intn(4); n > 0 {
+		resp.BlocksData = make([]execution.BlockData, n)
+	}
+	for i := range resp.BlocksData {
+		resp.BlocksData[i] = randBlockData(rng)
+	}
+
+	if n := rng.Intn(4); n > 0 {
+		resp.AllRollingHashEvent = make([]bridge.RollingHashUpdated, n)
+	}
+	for i := range resp.AllRollingHashEvent {
+		resp.AllRollingHashEvent[i] = randRollingHashUpdated(rng)
+	}
+
+	resp.AllL2L1MessageHashes = randBytes32s(rng)
+
+	if n := rng.Intn(4); n > 0 {
+		resp.ModuleUsage = make([]arithmetization.ModuleUsage, n)
+	}
+	for i := range resp.ModuleUsage {
+		resp.ModuleUsage[i] = arithmetization.ModuleUsage{
+			Module: randString(rng),
+			Rows:   rng.Intn(1 << 24),
+			Limit:  rng.Intn(1 << 24),
+		}
+	}
+
+	return resp
+}
+
+func randBlockData(rng *rand.Rand) execution.BlockData {
+
+	blk := execution.BlockData{
+		TimeStamp:                   rng.Uint64(),
+		L2ToL1MsgHashes:             randBytes32s(rng),
+		LastRollingHashUpdatedEvent: randRollingHashUpdated(rng),
+	}
+	rng.Read(blk.BlockHash[:])
+	rng.Read(blk.RootHash[:])
+
+	if n := rng.Intn(4); n > 0 {
+		blk.RlpEncodedTransactions = make([]string, n)
+		blk.BatchReceptionIndices = make([]uint16, n)
+		blk.FromAddresses = make([]types.EthAddress, n)
+	}
+	for i := range blk.RlpEncodedTransactions {
+		blk.RlpEncodedTransactions[i] = hexutil.Encode(randBytes(rng, rng.Intn(128)))
+		blk.BatchReceptionIndices[i] = uint16(rng.Intn(1 << 16))
+		rng.Read(blk.FromAddresses[i][:])
+	}
+
+	return blk
+}
+
+func randRollingHashUpdated(rng *rand.Rand) bridge.RollingHashUpdated {
+	ev := bridge.RollingHashUpdated{MessageNumber: rng.Int63()}
+	rng.Read(ev.RollingHash[:])
+	return ev
+}
+
+func randBlobDecompressionResponse(rng *rand.Rand) *blobdecompression.Response {
+
+	req := blobdecompression.Request{
+		Eip4844Enabled:      rng.Intn(2) == 1,
+		DataHash:            randString(rng),
+		CompressedData:      base64.StdEncoding.EncodeToString(randBytes(rng, rng.Intn(256))),
+		Commitment:          randString(rng),
+		KzgProofContract:    randString(rng),
+		KzgProofSidecar:     randString(rng),
+		ExpectedX:           randString(rng),
+		ExpectedY:           randString(rng),
+		SnarkHash:           randString(rng),
+		ParentStateRootHash: randString(rng),
+		FinalStateRootHash:  randString(rng),
+		DataParentHash:      randString(rng),
+		ExpectedShnarf:      randString(rng),
+		PrevShnarf:          randString(rng),
+	}
+
+	req.ConflationOrder.StartingBlockNumber = rng.Int()
+	if n := rng.Intn(4); n > 0 {
+		req.ConflationOrder.UpperBoundaries = make([]int, n)
+	}
+	for i := range req.ConflationOrder.UpperBoundaries {
+		req.ConflationOrder.UpperBoundaries[i] = rng.Int()
+	}
+
+	resp := &blobdecompression.Response{
+		Request:            req,
+		ProverVersion:      randString(rng),
+		VerifyingKeyShaSum: randString(rng),
+		DecompressionProof: randString(rng),
+	}
+	var pi [fr.Bytes]byte
+	rng.Read(pi[:])
+	(*fr.Element)(&resp.Debug.PublicInput).SetBytes(pi[:])
+
+	return resp
+}
+
+func randAggregationRequest(rng *rand.Rand) *aggregation.Request {
+	return &aggregation.Request{
+		ExecutionProofs:                                 randStrings(rng),
+		DecompressionProofs:                             randStrings(rng),
+		ParentAggregationLastBlockTimestamp:             rng.Uint64(),
+		ParentAggregationLastL1RollingHash:              randString(rng),
+		ParentAggregationLastL1RollingHashMessageNumber: rng.Int(),
+		DeferEmulation:                                  rng.Intn(2) == 0,
+	}
+}
+
+func randAggregationResponse(rng *rand.Rand) *aggregation.Response {
+	return &aggregation.Response{
+		FinalShnarf:                         randString(rng),
+		ParentAggregationFinalShnarf:        randString(rng),
+		AggregatedProof:                     randString(rng),
+		AggregatedProverVersion:             randString(rng),
+		AggregatedVerifierIndex:             rng.Intn(4),
+		AggregatedProofPublicInput:          randString(rng),
+		DataHashes:                          randStrings(rng),
+		DataParentHash:                      randString(rng),
+		ParentStateRootHash:                 randString(rng),
+		ParentAggregationLastBlockTimestamp: uint(rng.Uint64()),
+		LastFinalizedBlockNumber:            uint(rng.Uint64()),
+		FinalTimestamp:                      uint(rng.Uint64()),
+		FinalBlockNumber:                    uint(rng.Uint64()),
+		L1RollingHash:                       randString(rng),
+		L1RollingHashMessageNumber:          uint(rng.Uint64()),
+		L2MerkleRoots:                       randStrings(rng),
+		L2MsgTreesDepth:                     uint(rng.Intn(64)),
+		L2MessagingBlocksOffsets:            randString(rng),
+		Bw6Proof:                            randString(rng),
+		Bw6CircuitID:                        rng.Intn(4),
+	}
+}
+
+// randString returns a random hexstring, possibly empty
+func randString(rng *rand.Rand) string {
+	if rng.Intn(4) == 0 {
+		return ""
+	}
+	return hexutil.Encode(randBytes(rng, rng.Intn(64)))
+}
+
+// randStrings returns nil or a non-empty list of strings as the decoder
+// never returns an empty non-nil slice.
+func randStrings(rng *rand.Rand) []string {
+	n := rng.Intn(4)
+	if n == 0 {
+		return nil
+	}
+	res := make([]string, n)
+	for i := range res {
+		res[i] = randString(rng)
+	}
+	return res
+}
+
+func randBytes32s(rng *rand.Rand) []types.FullBytes32 {
+	n := rng.Intn(4)
+	if n == 0 {
+		return nil
+	}
+	res := make([]types.FullBytes32, n)
+	for i := range res {
+		rng.Read(res[i][:])
+	}
+	return res
+}
+
+func randBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rng.Read(b)
+	return b
+}
+
+func TestMarshalDispatch(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(0))
+	resp := randAggregationResponse(rng)
+
+	b, err := Marshal(resp)
+	require.NoError(t, err)
+
+	var decoded aggregation.Response
+	require.NoError(t, Unmarshal(b, &decoded))
+	require.Equal(t, resp, &decoded)
+
+	_, err = Marshal(&struct{}{})
+	require.Error(t, err)
+	require.Error(t, Unmarshal(b, &struct{}{}))
+}
@@ -0,0 +1,143 @@
+// Package chainspec holds the parameters of the Linea networks the prover
+// knows of: chain ID, address of the L2 message service (the bridge) and
+// activation blocks of the forks. The built-in networks are selected by name
+// in the config and their parameters may be overridden, e.g. for a devnet.
+package chainspec
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Names of the built-in networks
+const (
+	MainnetName = "mainnet"
+	SepoliaName = "sepolia"
+)
+
+// Names of the forks the prover depends on
+const (
+	// ForkStateLeafLayoutV1 upgrades the leaves of the state tries to the
+	// versioned layout, see [github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator.LeafLayoutV1].
+	ForkStateLeafLayoutV1 = "state_leaf_layout_v1"
+)
+
+// Spec are the parameters of a network
+type Spec struct {
+	// Name of the network
+	Name string
+	// ChainID is the ID of the Linea L2 network
+	ChainID uint
+	// L2MessageService is the address of the message service contract on L2.
+	// The L2 to L1 messages and the rolling hash updates are extracted from
+	// its logs.
+	L2MessageService common.Address
+	// Forks lists the forks scheduled on the network
+	Forks Forks
+}
+
+// Fork is the activation of a fork at a block
+type Fork struct {
+	Name  string
+	Block uint64
+}
+
+// Forks is a list of forks
+type Forks []Fork
+
+// Block returns the activation block of the fork and false if the fork is
+// not scheduled.
+func (f Forks) Block(name string) (uint64, bool) {
+	for _, fork := range f {
+		if fork.Name == name {
+			return fork.Block, true
+		}
+	}
+	return 0, false
+}
+
+// IsActive returns true if the fork is activated at or before the block
+func (f Forks) IsActive(name string, block uint64) bool {
+	activation, ok := f.Block(name)
+	return ok && activation <= block
+}
+
+var (
+	// Mainnet is the Linea mainnet
+	Mainnet = Spec{
+		Name:             MainnetName,
+		ChainID:          59144,
+		L2MessageService: common.HexToAddress("0x508Ca82Df566dCD1B0DE8296e70a96332cD644ec"),
+	}
+
+	// Sepolia is the Linea testnet settling on Sepolia
+	Sepolia = Spec{
+		Name:             SepoliaName,
+		ChainID:          59141,
+		L2MessageService: common.HexToAddress("0x971e727e956690b9957be6d51Ec16E73AcAC83A7"),
+	}
+)
+
+// builtins maps the names of the built-in networks to their parameters
+var builtins = map[string]Spec{
+	MainnetName: Mainnet,
+	SepoliaName: Sepolia,
+}
+
+// Lookup returns the parameters of a built-in network
+func Lookup(name string) (Spec, error) {
+	spec, ok := builtins[name]
+	if !ok {
+		return Spec{}, fmt.Errorf("unknown network %q, the built-in networks are %v", name, Names())
+	}
+	spec.Forks = slices.Clone(spec.Forks)
+	return spec, nil
+}
+
+// Names returns the sorted names of the built-in networks
+func Names() []string {
+	res := make([]string, 0, len(builtins))
+	for name := range builtins {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Overrides are the parameters of a network replaced by the config. The zero
+// values leave the parameters unchanged.
+type Overrides struct {
+	ChainID          uint
+	L2MessageService common.Address
+	Forks            Forks
+}
+
+// WithOverrides returns a copy of the spec with the overrides applied. A fork
+// of the overrides replaces the fork of the spec with the same name.
+func (s Spec) WithOverrides(o Overrides) Spec {
+
+	res := s
+	res.Forks = slices.Clone(s.Forks)
+
+	if o.ChainID != 0 {
+		res.ChainID = o.ChainID
+	}
+
+	if o.L2MessageService != (common.Address{}) {
+		res.L2MessageService = o.L2MessageService
+	}
+
+	for _, fork := range o.Forks {
+		i := slices.IndexFunc(res.Forks, func(f Fork) bool { return f.Name == fork.Name })
+		if i < 0 {
+			res.Forks = append(res.Forks, fork)
+			continue
+		}
+		res.Forks[i] = fork
+	}
+
+	return res
+}
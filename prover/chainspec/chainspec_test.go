@@ -0,0 +1,61 @@
+package chainspec_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/chainspec"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+
+	for _, name := range chainspec.Names() {
+		spec, err := chainspec.Lookup(name)
+		require.NoError(t, err)
+		require.Equal(t, name, spec.Name)
+		require.NotZero(t, spec.ChainID)
+		require.NotEqual(t, common.Address{}, spec.L2MessageService)
+	}
+
+	mainnet, err := chainspec.Lookup(chainspec.MainnetName)
+	require.NoError(t, err)
+	require.Equal(t, uint(59144), mainnet.ChainID)
+
+	_, err = chainspec.Lookup("devnet")
+	require.Error(t, err)
+}
+
+func TestWithOverrides(t *testing.T) {
+
+	var (
+		bridge = common.HexToAddress("0xe537D669CA013d86EBeF1D64e40fC74CADC91987")
+		base   = chainspec.Spec{
+			Name:             "base",
+			ChainID:          1,
+			L2MessageService: common.Address{1},
+			Forks:            chainspec.Forks{{Name: "a", Block: 10}},
+		}
+	)
+
+	// The zero overrides leave the spec unchanged
+	require.Equal(t, base, base.WithOverrides(chainspec.Overrides{}))
+
+	spec := base.WithOverrides(chainspec.Overrides{
+		ChainID:          1337,
+		L2MessageService: bridge,
+		Forks:            chainspec.Forks{{Name: "a", Block: 20}, {Name: "b", Block: 30}},
+	})
+
+	require.Equal(t, "base", spec.Name)
+	require.Equal(t, uint(1337), spec.ChainID)
+	require.Equal(t, bridge, spec.L2MessageService)
+	require.Equal(t, chainspec.Forks{{Name: "a", Block: 20}, {Name: "b", Block: 30}}, spec.Forks)
+
+	// The base spec is not modified
+	require.Equal(t, chainspec.Forks{{Name: "a", Block: 10}}, base.Forks)
+
+	require.False(t, spec.Forks.IsActive("a", 19))
+	require.True(t, spec.Forks.IsActive("a", 20))
+	require.False(t, spec.Forks.IsActive("c", 100))
+}
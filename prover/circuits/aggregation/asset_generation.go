@@ -11,29 +11,47 @@ import (
 	"github.com/consensys/linea-monorepo/prover/circuits"
 )
 
+func init() {
+	// the builder needs the verifying keys of the inner circuits, hence it is
+	// left to the setup command which also sets up one circuit per number of
+	// proofs.
+	circuits.Register(circuits.CircuitInfo{
+		ID:           circuits.AggregationCircuitID,
+		Curve:        ecc.BW6_761,
+		Dependencies: []circuits.Dependency{circuits.DependencyInnerVerifyingKeys},
+		DefaultSetup: true,
+	})
+}
+
 type builder struct {
 	maxNbProofs   int
 	vKeys         []plonk.VerifyingKey
+	prevVKeys     []plonk.VerifyingKey
 	allowedInputs []string
 	pi            circuits.Setup
 }
 
+// NewBuilder returns a builder for the aggregation circuit. prevVKeys lists the
+// verifying keys of the previous prover version and can be left empty outside
+// of prover upgrade windows.
 func NewBuilder(
 	maxNbProofs int,
 	allowedInputs []string,
 	pi circuits.Setup,
 	vKeys []plonk.VerifyingKey,
+	prevVKeys []plonk.VerifyingKey,
 ) *builder {
 	return &builder{
 		pi:            pi,
 		allowedInputs: allowedInputs,
 		maxNbProofs:   maxNbProofs,
 		vKeys:         vKeys,
+		prevVKeys:     prevVKeys,
 	}
 }
 
 func (b *builder) Compile() (constraint.ConstraintSystem, error) {
-	return MakeCS(b.maxNbProofs, b.pi, b.vKeys)
+	return MakeCS(b.maxNbProofs, b.pi, b.vKeys, b.prevVKeys)
 }
 
 // Initializes the bw6 aggregation circuit and returns a compiled constraint
@@ -42,12 +60,14 @@ func MakeCS(
 	maxNbProofs int,
 	piSetup circuits.Setup,
 	vKeys []plonk.VerifyingKey,
+	prevVKeys []plonk.VerifyingKey,
 ) (constraint.ConstraintSystem, error) {
 
 	aggCircuit, err := AllocateCircuit(
 		maxNbProofs,
 		piSetup,
 		vKeys,
+		prevVKeys,
 	)
 
 	if err != nil {
@@ -41,6 +41,11 @@ type Circuit struct {
 	// List of available verifying keys that are available to the circuit. This
 	// is treated as a constant by the circuit.
 	verifyingKeys []emVkey `gnark:"-"`
+	// List of verifying keys of the inner circuits of the previous prover
+	// version. It is empty unless the circuit is meant to be used during a
+	// prover upgrade window, in which case the claims whose ProverVersion is
+	// [PreviousProverVersion] are verified against these keys instead.
+	previousVerifyingKeys []emVkey `gnark:"-"`
 
 	publicInputVerifyingKey        emVkey              `gnark:"-"`
 	PublicInputProof               emProof             `gnark:",secret"`
@@ -64,11 +69,16 @@ func (c *Circuit) Define(api frontend.API) error {
 	assertSlicesEqualZEXT(api, piBits[:16*8], field.ToBitsCanonical(&c.PublicInputWitness.Public[1]))
 	assertSlicesEqualZEXT(api, piBits[16*8:], field.ToBitsCanonical(&c.PublicInputWitness.Public[0]))
 
-	vks := append(slices.Clone(c.verifyingKeys), c.publicInputVerifyingKey)
+	vks := slices.Concat(c.verifyingKeys, c.previousVerifyingKeys, []emVkey{c.publicInputVerifyingKey})
 	piVkIndex := len(vks) - 1
 
+	// The claims refer to a verifying key through a (version, circuit ID)
+	// pair. We flatten it into an index of vks.
+	claims := make([]proofClaim, len(c.ProofClaims), len(c.ProofClaims)+1)
 	for i := range c.ProofClaims {
-		api.AssertIsDifferent(c.ProofClaims[i].CircuitID, piVkIndex) // TODO @Tabaie is this necessary? can't think of an attack if this is removed
+		claims[i] = c.ProofClaims[i]
+		claims[i].CircuitID = c.versionedCircuitID(api, &c.ProofClaims[i])
+		api.AssertIsDifferent(claims[i].CircuitID, piVkIndex) // TODO @Tabaie is this necessary? can't think of an attack if this is removed
 	}
 
 	// create a lookup table of actual public inputs
@@ -99,7 +109,7 @@ func (c *Circuit) Define(api frontend.API) error {
 		}
 	}
 
-	claims := append(slices.Clone(c.ProofClaims), proofClaim{
+	claims = append(claims, proofClaim{
 		CircuitID:   piVkIndex,
 		Proof:       c.PublicInputProof,
 		PublicInput: c.PublicInputWitness,
@@ -113,23 +123,51 @@ func (c *Circuit) Define(api frontend.API) error {
 	return nil
 }
 
+// versionedCircuitID returns the position in the flattened list of verifying
+// keys of the key the claim refers to. It also constrains the prover version of
+// the claim to be one supported by the circuit and the circuit ID to be within
+// the bounds of the set of verifying keys of that version.
+func (c *Circuit) versionedCircuitID(api frontend.API, claim *proofClaim) frontend.Variable {
+
+	nbCurrent := len(c.verifyingKeys)
+
+	if len(c.previousVerifyingKeys) == 0 {
+		api.AssertIsEqual(claim.ProverVersion, CurrentProverVersion)
+		return claim.CircuitID
+	}
+
+	api.AssertIsBoolean(claim.ProverVersion)
+	maxCircuitID := api.Select(claim.ProverVersion, len(c.previousVerifyingKeys)-1, nbCurrent-1)
+	api.AssertIsLessOrEqual(claim.CircuitID, maxCircuitID)
+
+	return api.Add(claim.CircuitID, api.Mul(claim.ProverVersion, nbCurrent))
+}
+
 // Instantiate a new Circuit from a list of verification keys and
 // a maximal number of proofs. The function should only be called with the
 // purpose of running `frontend.Compile` over it.
-func AllocateCircuit(nbProofs int, pi circuits.Setup, verifyingKeys []plonk.VerifyingKey) (*Circuit, error) {
+//
+// previousVerifyingKeys optionally lists the verifying keys of the inner
+// circuits of the previous prover version. When it is non-empty, the circuit
+// can aggregate proofs of both versions in the same batch. The keys of both
+// versions must be derived from the same SRS. The keys are constants of the
+// circuit: adding, removing or replacing previous keys changes the constraint
+// system and requires a new setup of the aggregation circuit.
+func AllocateCircuit(nbProofs int, pi circuits.Setup, verifyingKeys, previousVerifyingKeys []plonk.VerifyingKey) (*Circuit, error) {
 
 	var (
-		err           error
-		emVKeys       = make([]emVkey, len(verifyingKeys))
 		csPlaceHolder = getPlaceHolderCS()
 		proofClaims   = make([]proofClaim, nbProofs)
 	)
 
-	for i := range verifyingKeys {
-		emVKeys[i], err = emPlonk.ValueOfVerifyingKey[emFr, emG1, emG2](verifyingKeys[i])
-		if err != nil {
-			return nil, fmt.Errorf("while converting the verifying key #%v (execution) into its emulated gnark version: %w", i, err)
-		}
+	emVKeys, err := emulateVerifyingKeys(verifyingKeys)
+	if err != nil {
+		return nil, fmt.Errorf("while converting the verifying keys (current version): %w", err)
+	}
+
+	emPrevVKeys, err := emulateVerifyingKeys(previousVerifyingKeys)
+	if err != nil {
+		return nil, fmt.Errorf("while converting the verifying keys (previous version): %w", err)
 	}
 
 	for i := range proofClaims {
@@ -144,6 +182,7 @@ func AllocateCircuit(nbProofs int, pi circuits.Setup, verifyingKeys []plonk.Veri
 	return &Circuit{
 		ProofClaims:                    proofClaims,
 		verifyingKeys:                  emVKeys,
+		previousVerifyingKeys:          emPrevVKeys,
 		publicInputVerifyingKey:        piVkEm,
 		PublicInputProof:               emPlonk.PlaceholderProof[emFr, emG1, emG2](pi.Circuit),
 		PublicInputWitness:             emPlonk.PlaceholderWitness[emFr](pi.Circuit),
@@ -152,6 +191,20 @@ func AllocateCircuit(nbProofs int, pi circuits.Setup, verifyingKeys []plonk.Veri
 
 }
 
+// emulateVerifyingKeys converts a list of verifying keys into their emulated
+// gnark version.
+func emulateVerifyingKeys(verifyingKeys []plonk.VerifyingKey) ([]emVkey, error) {
+	res := make([]emVkey, len(verifyingKeys))
+	for i := range verifyingKeys {
+		var err error
+		res[i], err = emPlonk.ValueOfVerifyingKey[emFr, emG1, emG2](verifyingKeys[i])
+		if err != nil {
+			return nil, fmt.Errorf("while converting the verifying key #%v into its emulated gnark version: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
 func verifyClaimBatch(api frontend.API, vks []emVkey, claims []proofClaim) error {
 	verifier, err := emPlonk.NewVerifier[emFr, emG1, emG2, emGT](api)
 	if err != nil {
@@ -73,33 +73,56 @@ func TestPublicInput(t *testing.T) {
 }
 
 func TestAggregationOneInner(t *testing.T) {
-	testAggregation(t, 2, 1)
+	testAggregation(t, 2, 0, 1)
 }
 
 func TestAggregationFewDifferentInners(t *testing.T) {
 	t.Skipf("skipped as this fails on the CI for non-understood reasons")
-	testAggregation(t, 1, 5)
-	testAggregation(t, 2, 5)
-	testAggregation(t, 3, 2, 6, 10)
+	testAggregation(t, 1, 0, 5)
+	testAggregation(t, 2, 0, 5)
+	testAggregation(t, 3, 0, 2, 6, 10)
 }
 
-func testAggregation(t *testing.T, nCircuits int, ncs ...int) {
+// TestAggregationPreviousVersion aggregates, in the same batch, proofs verified
+// against the verifying keys of the current prover version and proofs verified
+// against the verifying keys of the previous one.
+func TestAggregationPreviousVersion(t *testing.T) {
+	testAggregation(t, 1, 1, 5)
+}
+
+// testAggregation checks the aggregation circuit on batches of proofs of
+// nCircuits inner circuits. When nPrevCircuits is non-zero, the circuit also
+// accepts the proofs of nPrevCircuits other inner circuits standing for the
+// previous prover version and the claims alternate between both versions.
+func testAggregation(t *testing.T, nCircuits, nPrevCircuits int, ncs ...int) {
 
-	// Mock circuits to aggregate
-	var innerSetups []circuits.Setup
+	// Mock circuits to aggregate. Their IDs start at 1: the addition of the ID
+	// is optimized out of the dummy circuit of ID 0 whose layout then differs
+	// from the others and whose key cannot be switched with theirs.
+	var innerSetups, prevInnerSetups []circuits.Setup
 	logrus.Infof("Initializing many inner-circuits of %v\n", nCircuits)
 	srsProvider := circuits.NewUnsafeSRSProvider() // This is a dummy SRS provider, not to use in prod.
 	for i := 0; i < nCircuits; i++ {
 		logrus.Infof("\t%d/%d\n", i+1, nCircuits)
-		pp, _ := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitID(i), ecc.BLS12_377.ScalarField())
+		pp, _ := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitID(i+1), ecc.BLS12_377.ScalarField())
 		innerSetups = append(innerSetups, pp)
 	}
 
+	// The circuits of the previous version are mocked by dummy circuits with
+	// other IDs so that their verifying keys differ from the current ones.
+	for i := 0; i < nPrevCircuits; i++ {
+		pp, _ := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitID(nCircuits+i+1), ecc.BLS12_377.ScalarField())
+		prevInnerSetups = append(prevInnerSetups, pp)
+	}
+
 	// This collects the verifying keys from the public parameters
-	var vkeys []plonk.VerifyingKey
+	var vkeys, prevVkeys []plonk.VerifyingKey
 	for _, setup := range innerSetups {
 		vkeys = append(vkeys, setup.VerifyingKey)
 	}
+	for _, setup := range prevInnerSetups {
+		prevVkeys = append(prevVkeys, setup.VerifyingKey)
+	}
 
 	aggregationPIBytes := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}
 	var aggregationPI frBw6.Element
@@ -130,7 +153,7 @@ func testAggregation(t *testing.T, nCircuits int, ncs ...int) {
 
 		// Building aggregation circuit for max `nc` proofs
 		logrus.Infof("Building aggregation circuit for size of %v\n", nc)
-		aggrCircuit, err := aggregation.AllocateCircuit(nc, piSetup, vkeys)
+		aggrCircuit, err := aggregation.AllocateCircuit(nc, piSetup, vkeys, prevVkeys)
 		require.NoError(t, err)
 
 		// Generate proofs claims to aggregate
@@ -141,23 +164,28 @@ func testAggregation(t *testing.T, nCircuits int, ncs ...int) {
 		for i := range innerProofClaims {
 
 			// Assign the dummy circuit for a random value
-			circID := i % nCircuits
+			version, setups, mockOffset := aggregation.CurrentProverVersion, innerSetups, 0
+			if nPrevCircuits > 0 && i%2 == 1 {
+				version, setups, mockOffset = aggregation.PreviousProverVersion, prevInnerSetups, nCircuits
+			}
+			circID := (i / utils.Ite(nPrevCircuits > 0, 2, 1)) % len(setups)
 			_, err = innerPI[i].SetRandom()
 			assert.NoError(t, err)
-			a := dummy.Assign(circuits.MockCircuitID(circID), innerPI[i])
+			a := dummy.Assign(circuits.MockCircuitID(mockOffset+circID+1), innerPI[i])
 
 			// Stores the inner-proofs for later
 			proof, err := circuits.ProveCheck(
-				&innerSetups[circID], a,
+				&setups[circID], a,
 				emPlonk.GetNativeProverOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
 				emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
 			)
 			assert.NoError(t, err)
 
 			innerProofClaims[i] = aggregation.ProofClaimAssignment{
-				CircuitID:   circID,
-				Proof:       proof,
-				PublicInput: innerPI[i],
+				CircuitID:     circID,
+				ProverVersion: version,
+				Proof:         proof,
+				PublicInput:   innerPI[i],
 			}
 		}
 
@@ -202,10 +230,22 @@ func testAggregation(t *testing.T, nCircuits int, ncs ...int) {
 		// Assigning the BW6 circuit
 		logrus.Infof("Generating the aggregation proof for arity %v", nc)
 
-		aggrAssignment, err := aggregation.AssignAggregationCircuit(nc, innerProofClaims, piInfo, aggregationPI)
+		aggrAssignment, err := aggregation.AssignAggregationCircuit(nc, innerProofClaims, piInfo, aggregationPI, aggregation.ClaimPreparation{})
 		assert.NoError(t, err)
 
 		assert.NoError(t, test.IsSolved(aggrCircuit, aggrAssignment, ecc.BW6_761.ScalarField()))
+
+		if nPrevCircuits == 0 || nProofs < 2 {
+			continue
+		}
+
+		// A proof of the previous version must not pass as a proof of the
+		// current version.
+		innerProofClaims[1].ProverVersion = aggregation.CurrentProverVersion
+		aggrAssignment, err = aggregation.AssignAggregationCircuit(nc, innerProofClaims, piInfo, aggregationPI, aggregation.ClaimPreparation{})
+		assert.NoError(t, err)
+
+		assert.Error(t, test.IsSolved(aggrCircuit, aggrAssignment, ecc.BW6_761.ScalarField()))
 	}
 
 }
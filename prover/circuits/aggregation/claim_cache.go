@@ -0,0 +1,152 @@
+package aggregation
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+)
+
+// ClaimCache is a content-addressed cache of the emulated assignments of the
+// proof claims. Emulating an inner proof over the BW6 field is one of the
+// costly steps of the assignment of the aggregation circuit and when the
+// coordinator retries an aggregation, most of the inner proofs are the same as
+// in the previous attempt. The entries are keyed by the digest of the proof,
+// of its public input and of its verifying key so that a cache hit is always
+// a valid assignment for the claim. The cache is safe for concurrent use and
+// evicts the least recently used entries past its capacity.
+//
+// The circuit ID and the prover version are not part of the cached value as
+// they depend on the aggregation circuit that is picked for the job. They are
+// set from the claim on every lookup.
+type ClaimCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[ClaimKey]*list.Element
+	lru      *list.List // of *claimCacheEntry, most recently used first
+	hits     int
+	misses   int
+}
+
+// ClaimKey is the content address of a proof claim in a [ClaimCache]
+type ClaimKey [sha256.Size]byte
+
+type claimCacheEntry struct {
+	key   ClaimKey
+	claim proofClaim
+}
+
+// NewClaimCache returns an empty cache holding at most capacity claims. It
+// panics if the capacity is not positive.
+func NewClaimCache(capacity int) *ClaimCache {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("the capacity of the claim cache must be positive, got %v", capacity))
+	}
+	return &ClaimCache{
+		capacity: capacity,
+		entries:  make(map[ClaimKey]*list.Element, capacity),
+		lru:      list.New(),
+	}
+}
+
+// Key returns the content address of the claim. It hashes the raw
+// serialization of the proof, the public input and the digest of the
+// verifying key.
+func (a *ProofClaimAssignment) Key() (ClaimKey, error) {
+
+	var (
+		key ClaimKey
+		h   = sha256.New()
+		pi  = a.PublicInput.Bytes()
+	)
+
+	if _, err := a.Proof.WriteRawTo(h); err != nil {
+		return key, fmt.Errorf("could not serialize the proof: %w", err)
+	}
+
+	h.Write(pi[:])
+	h.Write(a.VerifyingKeyShasum[:])
+	copy(key[:], h.Sum(nil))
+	return key, nil
+}
+
+// Len returns the number of claims held by the cache
+func (c *ClaimCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Stats returns the number of lookups that were served from the cache and the
+// number of lookups that required emulating the claim.
+func (c *ClaimCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// assign returns the gnark assignment of the claim, from the cache if
+// possible. A nil cache is valid and always emulates the claim. The emulation
+// runs outside of the lock, hence two concurrent lookups of the same missing
+// claim may both emulate it.
+func (c *ClaimCache) assign(a *ProofClaimAssignment) (proofClaim, error) {
+
+	if c == nil {
+		return assignProofClaim(a)
+	}
+
+	key, err := a.Key()
+	if err != nil {
+		return proofClaim{}, err
+	}
+
+	if claim, found := c.get(key); found {
+		claim.CircuitID = a.CircuitID
+		claim.ProverVersion = a.ProverVersion
+		return claim, nil
+	}
+
+	claim, err := assignProofClaim(a)
+	if err != nil {
+		return proofClaim{}, err
+	}
+
+	cached := claim
+	cached.CircuitID, cached.ProverVersion = nil, nil
+	c.put(key, cached)
+
+	return claim, nil
+}
+
+func (c *ClaimCache) get(key ClaimKey) (proofClaim, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.entries[key]
+	if !found {
+		c.misses++
+		return proofClaim{}, false
+	}
+
+	c.hits++
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*claimCacheEntry).claim, true
+}
+
+func (c *ClaimCache) put(key ClaimKey, claim proofClaim) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.entries[key]; found {
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&claimCacheEntry{key: key, claim: claim})
+
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*claimCacheEntry).key)
+	}
+}
@@ -0,0 +1,131 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dummyClaims generates a proof of the dummy circuit for every public input
+func dummyClaims(t *testing.T, pis ...uint64) []ProofClaimAssignment {
+	setup := dummySetup(t)
+	return proveDummyClaims(t, &setup, pis...)
+}
+
+// dummySetup generates an unsafe setup of the dummy circuit over BLS12-377
+func dummySetup(t *testing.T) circuits.Setup {
+	srsProvider := circuits.NewUnsafeSRSProvider() // This is a dummy SRS provider, not to use in prod.
+	setup, err := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitID(0), ecc.BLS12_377.ScalarField())
+	require.NoError(t, err)
+	return setup
+}
+
+// proveDummyClaims generates a proof of the dummy circuit for every public
+// input, with the provided setup.
+func proveDummyClaims(t *testing.T, setup *circuits.Setup, pis ...uint64) []ProofClaimAssignment {
+
+	var err error
+	res := make([]ProofClaimAssignment, len(pis))
+	for i := range pis {
+		res[i].PublicInput.SetUint64(pis[i])
+		res[i].Proof, err = circuits.ProveCheck(
+			setup, dummy.Assign(circuits.MockCircuitID(0), res[i].PublicInput),
+			emPlonk.GetNativeProverOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
+			emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
+		)
+		require.NoError(t, err)
+	}
+
+	return res
+}
+
+func TestClaimKey(t *testing.T) {
+
+	claims := dummyClaims(t, 1, 2)
+
+	key, err := claims[0].Key()
+	require.NoError(t, err)
+
+	// The circuit ID and the prover version are not part of the content
+	sameContent := claims[0]
+	sameContent.CircuitID, sameContent.ProverVersion = 3, PreviousProverVersion
+	sameKey, err := sameContent.Key()
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	otherPi := claims[0]
+	otherPi.PublicInput = fr.NewElement(2)
+	otherPiKey, err := otherPi.Key()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherPiKey)
+
+	otherVk := claims[0]
+	otherVk.VerifyingKeyShasum[0] ^= 1
+	otherVkKey, err := otherVk.Key()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherVkKey)
+
+	otherProof, err := claims[1].Key()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherProof)
+}
+
+func TestClaimCache(t *testing.T) {
+
+	var (
+		claims = dummyClaims(t, 1, 2)
+		cache  = NewClaimCache(1)
+	)
+
+	expected, err := assignProofClaim(&claims[0])
+	require.NoError(t, err)
+
+	first, err := cache.assign(&claims[0])
+	require.NoError(t, err)
+	assert.Equal(t, expected, first)
+
+	// A retry of the same claim, tagged for another circuit, is served from
+	// the cache.
+	retry := claims[0]
+	retry.CircuitID, retry.ProverVersion = 1, PreviousProverVersion
+	second, err := cache.assign(&retry)
+	require.NoError(t, err)
+
+	hits, misses := cache.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 1, misses)
+	assert.Equal(t, expected.Proof, second.Proof)
+	assert.Equal(t, expected.PublicInput, second.PublicInput)
+	assert.Equal(t, 1, second.CircuitID)
+	assert.Equal(t, PreviousProverVersion, second.ProverVersion)
+
+	// The second claim evicts the first one
+	_, err = cache.assign(&claims[1])
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+
+	_, err = cache.assign(&claims[0])
+	require.NoError(t, err)
+	hits, misses = cache.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 3, misses)
+}
+
+func TestNilClaimCache(t *testing.T) {
+
+	var cache *ClaimCache
+	claims := dummyClaims(t, 1)
+
+	expected, err := assignProofClaim(&claims[0])
+	require.NoError(t, err)
+
+	claim, err := cache.assign(&claims[0])
+	require.NoError(t, err)
+	assert.Equal(t, expected, claim)
+}
@@ -0,0 +1,121 @@
+package aggregation
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// ClaimPreparation configures how the inner proof claims are turned into the
+// assignment of the aggregation circuit. Each claim is natively verified and
+// emulated independently, by a bounded pool of workers. The zero value
+// prepares the claims on GOMAXPROCS workers, without cache and without native
+// verification.
+type ClaimPreparation struct {
+	// Cache holds the emulated claims of the previous jobs. It may be nil, see
+	// [ClaimCache].
+	Cache *ClaimCache
+	// VerifyingKeys are the verifying keys of the inner circuits, indexed by
+	// their digest. When set, the claims are natively verified before being
+	// emulated, so that an invalid inner proof is reported with its position
+	// instead of failing the solver of the aggregation circuit. The claims
+	// whose verifying key is missing are not verified.
+	VerifyingKeys map[types.FullBytes32]plonk.VerifyingKey
+	// NbWorkers bounds the number of claims prepared in parallel. The
+	// default is GOMAXPROCS.
+	NbWorkers int
+}
+
+// prepare verifies and emulates the claims in parallel. The returned claims
+// are in the same order as the input ones.
+func (p ClaimPreparation) prepare(proofClaims []ProofClaimAssignment) ([]proofClaim, error) {
+
+	var (
+		res       = make([]proofClaim, len(proofClaims))
+		nbWorkers = p.NbWorkers
+		eg        errgroup.Group
+		// counters of the claims, by outcome of the native verification
+		nbVerified, nbWithoutVk atomic.Int64
+	)
+
+	if nbWorkers <= 0 {
+		nbWorkers = runtime.GOMAXPROCS(0)
+	}
+	eg.SetLimit(nbWorkers)
+
+	for i := range proofClaims {
+		eg.Go(func() error {
+
+			a := &proofClaims[i]
+
+			switch outcome, err := p.verify(a); {
+			case err != nil:
+				return fmt.Errorf("while verifying the proof claim #%v (circ ID %v): %w", i, a.CircuitID, err)
+			case outcome == claimVerified:
+				nbVerified.Add(1)
+			default:
+				nbWithoutVk.Add(1)
+			}
+
+			claim, err := p.Cache.assign(a)
+			if err != nil {
+				return fmt.Errorf("while emulating the proof claim #%v (circ ID %v): %w", i, a.CircuitID, err)
+			}
+			res[i] = claim
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	if p.VerifyingKeys != nil {
+		logrus.Infof(
+			"inner proofs: %v verified natively, %v without verifying key",
+			nbVerified.Load(), nbWithoutVk.Load(),
+		)
+	}
+
+	return res, nil
+}
+
+// claimVerification is the outcome of the native verification of a claim
+type claimVerification int
+
+const (
+	claimNotVerified claimVerification = iota
+	claimVerified
+)
+
+// verify natively verifies the proof of the claim against its verifying key
+// unless the key is unknown.
+func (p ClaimPreparation) verify(a *ProofClaimAssignment) (claimVerification, error) {
+
+	vk, found := p.VerifyingKeys[a.VerifyingKeyShasum]
+	if !found {
+		return claimNotVerified, nil
+	}
+
+	wit, err := claimPublicWitness(a)
+	if err != nil {
+		return claimNotVerified, err
+	}
+
+	err = plonk.Verify(
+		a.Proof, vk, wit,
+		emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
+	)
+	if err != nil {
+		return claimNotVerified, fmt.Errorf("the proof does not pass against the verifying key %v: %w", a.VerifyingKeyShasum.Hex(), err)
+	}
+
+	return claimVerified, nil
+}
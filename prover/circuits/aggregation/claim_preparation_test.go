@@ -0,0 +1,60 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimPreparation(t *testing.T) {
+
+	var (
+		setup    = dummySetup(t)
+		vkDigest = types.FullBytes32FromHex(setup.VerifyingKeyDigest())
+		claims   = proveDummyClaims(t, &setup, 1, 2, 3)
+	)
+
+	for i := range claims {
+		claims[i].VerifyingKeyShasum = vkDigest
+	}
+
+	prep := ClaimPreparation{
+		VerifyingKeys: map[types.FullBytes32]plonk.VerifyingKey{vkDigest: setup.VerifyingKey},
+		NbWorkers:     2,
+	}
+
+	for i := range claims {
+		outcome, err := prep.verify(&claims[i])
+		require.NoError(t, err)
+		assert.Equal(t, claimVerified, outcome, "claim %v", i)
+	}
+
+	prepared, err := prep.prepare(claims)
+	require.NoError(t, err)
+	for i := range claims {
+		expected, err := assignProofClaim(&claims[i])
+		require.NoError(t, err)
+		assert.Equal(t, expected, prepared[i], "claim %v", i)
+	}
+
+	// The claims whose verifying key is unknown are left to the circuit
+	unknown := claims[2]
+	unknown.VerifyingKeyShasum[0] ^= 1
+	outcome, err := prep.verify(&unknown)
+	require.NoError(t, err)
+	assert.Equal(t, claimNotVerified, outcome)
+
+	// A claim on the wrong public input is rejected
+	invalid := claims[1]
+	invalid.PublicInput = fr.NewElement(5)
+	_, err = prep.prepare([]ProofClaimAssignment{claims[0], invalid})
+	require.ErrorContains(t, err, "proof claim #1")
+
+	// Without verifying keys, the claims are only emulated
+	_, err = ClaimPreparation{}.prepare([]ProofClaimAssignment{invalid})
+	require.NoError(t, err)
+}
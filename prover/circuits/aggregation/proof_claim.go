@@ -14,12 +14,30 @@ import (
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 )
 
+// The aggregation circuit can accept proofs generated by two different versions
+// of the inner circuits. This allows combining the proofs of the old and of the
+// new execution circuits in the same batch during a prover upgrade window.
+const (
+	// CurrentProverVersion tags the proof claims whose verifying key belongs to
+	// the set of allowed verifying keys of the current prover version.
+	CurrentProverVersion = 0
+	// PreviousProverVersion tags the proof claims whose verifying key belongs
+	// to the set of allowed verifying keys of the previous prover version.
+	PreviousProverVersion = 1
+)
+
 // Assignment collects all the arguments that are necessary to produce a circuit
 // assignment for the BW6 aggregation circuit. As the number of required
 // arguments is large, it is more convenient to pack them in a struct instead of
 // passing them flat.
 type ProofClaimAssignment struct {
-	CircuitID          int
+	// CircuitID is the position of the verifying key of the proof in the list
+	// of allowed verifying keys of its prover version.
+	CircuitID int
+	// ProverVersion indicates whether the proof was generated by the current
+	// prover version or by the previous one. See [CurrentProverVersion] and
+	// [PreviousProverVersion].
+	ProverVersion      int
 	Proof              plonk.Proof
 	PublicInput        fr.Element
 	VerifyingKeyShasum types.FullBytes32
@@ -31,6 +49,10 @@ type proofClaim struct {
 	// to be used for the verification of the circuit. Not that the value 0 is
 	// reserved for the placeholder circuit.
 	CircuitID frontend.Variable `gnark:",secret"`
+	// ProverVersion selects the set of allowed verifying keys in which the
+	// circuit ID is looked up. It is either [CurrentProverVersion] or
+	// [PreviousProverVersion].
+	ProverVersion frontend.Variable `gnark:",secret"`
 	// The proof to verify
 	Proof emProof `gnark:",secret"`
 	// The public input to be provided to the proof.
@@ -65,13 +87,9 @@ func assignProofClaim(a *ProofClaimAssignment) (proofClaim, error) {
 		return emptyProofClaim, fmt.Errorf("while emulating the proof over BLS: %w", err)
 	}
 
-	// We use the dummy circuit as a placeholder circuit to generate the witness.
-	// It works because all of our circuit have a single public input
-	aPlace := dummy.Assign(0, a.PublicInput)
-
-	wit, err := frontend.NewWitness(aPlace, ecc.BLS12_377.ScalarField(), frontend.PublicOnly())
+	wit, err := claimPublicWitness(a)
 	if err != nil {
-		return emptyProofClaim, fmt.Errorf("while initializing the gnark witness: %w", err)
+		return emptyProofClaim, err
 	}
 
 	emWit, err := emPlonk.ValueOfWitness[emFr](wit)
@@ -80,12 +98,27 @@ func assignProofClaim(a *ProofClaimAssignment) (proofClaim, error) {
 	}
 
 	return proofClaim{
-		PublicInput: emWit,
-		Proof:       emPi,
-		CircuitID:   a.CircuitID,
+		PublicInput:   emWit,
+		Proof:         emPi,
+		CircuitID:     a.CircuitID,
+		ProverVersion: a.ProverVersion,
 	}, nil
 }
 
+// claimPublicWitness returns the public witness of the inner proof of the claim
+func claimPublicWitness(a *ProofClaimAssignment) (witness.Witness, error) {
+
+	// We use the dummy circuit as a placeholder circuit to generate the witness.
+	// It works because all of our circuit have a single public input
+	aPlace := dummy.Assign(0, a.PublicInput)
+
+	wit, err := frontend.NewWitness(aPlace, ecc.BLS12_377.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return nil, fmt.Errorf("while initializing the gnark witness: %w", err)
+	}
+	return wit, nil
+}
+
 type PiInfo struct {
 	Proof         plonk.Proof
 	PublicWitness witness.Witness
@@ -13,13 +13,18 @@ import (
 )
 
 // Make proof runs the prover of the aggregation circuit and returns the
-// corresponding proof.
+// corresponding proof. The proof claims are verified and emulated as
+// configured by prep, see [ClaimPreparation]. The options are passed to
+// [circuits.ProveCheck] on top of the ones of the recursion, e.g. the solver
+// options.
 func MakeProof(
 	setup *circuits.Setup,
 	maxNbProof int,
 	proofClaims []ProofClaimAssignment,
 	piInfo PiInfo,
 	publicInput fr.Element,
+	prep ClaimPreparation,
+	opts ...any,
 ) (
 	plonk.Proof,
 	error,
@@ -31,6 +36,7 @@ func MakeProof(
 		proofClaims,
 		piInfo,
 		publicInput,
+		prep,
 	)
 
 	if err != nil {
@@ -38,16 +44,17 @@ func MakeProof(
 	}
 
 	logrus.Infof("Running the prove-check")
-	return circuits.ProveCheck(
-		setup,
-		assignment,
+	opts = append([]any{
 		emPlonk.GetNativeProverOptions(ecc.BN254.ScalarField(), setup.Circuit.Field()),
 		emPlonk.GetNativeVerifierOptions(ecc.BN254.ScalarField(), setup.Circuit.Field()),
-	)
+	}, opts...)
+
+	return circuits.ProveCheck(setup, assignment, opts...)
 }
 
-// Assigns the proof using placeholders
-func AssignAggregationCircuit(maxNbProof int, proofClaims []ProofClaimAssignment, piInfo PiInfo, publicInput fr.Element) (c *Circuit, err error) {
+// Assigns the proof using placeholders. The claims are verified and emulated
+// as configured by prep, see [ClaimPreparation].
+func AssignAggregationCircuit(maxNbProof int, proofClaims []ProofClaimAssignment, piInfo PiInfo, publicInput fr.Element, prep ClaimPreparation) (c *Circuit, err error) {
 
 	c = &Circuit{
 		ProofClaims:                    make([]proofClaim, maxNbProof),
@@ -59,15 +66,14 @@ func AssignAggregationCircuit(maxNbProof int, proofClaims []ProofClaimAssignment
 		return nil, fmt.Errorf("while emulating the PI proof claim: %w", err)
 	}
 
+	prepared, err := prep.prepare(proofClaims)
+	if err != nil {
+		return nil, err
+	}
+
 	for i := range c.ProofClaims {
-		if i < len(proofClaims) {
-			c.ProofClaims[i], err = assignProofClaim(&proofClaims[i])
-			if err != nil {
-				return nil, fmt.Errorf(
-					"while emulating the proof claim #%v (circ ID %v): %w",
-					i, proofClaims[i].CircuitID, err,
-				)
-			}
+		if i < len(prepared) {
+			c.ProofClaims[i] = prepared[i]
 		} else {
 			// If we go over capacity, we should use the
 			c.ProofClaims[i] = c.ProofClaims[len(proofClaims)-1]
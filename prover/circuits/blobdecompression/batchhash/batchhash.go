@@ -0,0 +1,96 @@
+// Package batchhash implements the hash of the batches (i.e. the conflations)
+// stored in a blob. It binds the data-availability side to the execution side:
+// the execution prover commits to the data of its batch (see the
+// ExecDataChecksum field of the execution response) and the decompression
+// circuit recomputes the hash of every batch from the decompressed payload of
+// the blob, using [Check]. The Go implementation can also be used on the
+// sequencer side to pre-validate a blob before sending it to the prover, see
+// [CheckBlob].
+//
+// The data of a batch is split into chunks of 31 bytes, the last one being
+// right-padded with zeroes, and the hash of a batch b is
+//
+//	MiMC(len(b), MiMC(... MiMC(MiMC(chunk_0, chunk_1), chunk_2) ..., chunk_n))
+//
+// with the inner hash being chunk_0 for a batch of at most 31 bytes. See
+// [gnarkutil.ChecksumLooselyPackedBytes].
+package batchhash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/hash"
+	blob "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
+)
+
+// MinBatchLen is the minimal size in bytes of a batch accepted by [Check].
+// In practice, a batch is always longer as a block alone stores a 32 bytes
+// hash.
+const MinBatchLen = 31
+
+// Sum returns the hash of a batch
+func Sum(batch []byte) []byte {
+	var buf [32]byte
+	gnarkutil.ChecksumLooselyPackedBytes(batch, buf[:], hash.MIMC_BLS12_377.New())
+	return bytes.Clone(buf[:])
+}
+
+// Sums returns the hashes of the batches whose data are concatenated at the
+// beginning of payload. It returns an error if a batch is shorter than
+// [MinBatchLen] or if the batches do not fit in the payload.
+func Sums(payload []byte, batchLengths []int) ([][]byte, error) {
+
+	res := make([][]byte, len(batchLengths))
+	start := 0
+
+	for i, l := range batchLengths {
+		if l < MinBatchLen {
+			return nil, fmt.Errorf("batch #%d is %d bytes long, less than the minimum of %d", i, l, MinBatchLen)
+		}
+		if start+l > len(payload) {
+			return nil, fmt.Errorf("batch #%d ends at byte %d, beyond the payload of %d bytes", i, start+l, len(payload))
+		}
+		res[i] = Sum(payload[start : start+l])
+		start += l
+	}
+
+	return res, nil
+}
+
+// FromBlob decompresses a blob and returns the hashes of its batches
+func FromBlob(blobBytes, dict []byte) ([][]byte, error) {
+
+	header, payload, _, err := blob.DecompressBlob(blobBytes, dict)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress the blob: %w", err)
+	}
+
+	return Sums(payload, header.BatchSizes)
+}
+
+// CheckBlob returns an error if the hashes of the batches of a blob are not
+// the expected ones, e.g. the ExecDataChecksum of the execution proofs of the
+// batches. This is the check performed by the decompression circuit.
+func CheckBlob(blobBytes, dict []byte, expected [][]byte) error {
+
+	sums, err := FromBlob(blobBytes, dict)
+	if err != nil {
+		return err
+	}
+
+	if len(sums) != len(expected) {
+		return fmt.Errorf("the blob has %d batches, expected %d", len(sums), len(expected))
+	}
+
+	var errs []error
+	for i := range sums {
+		if !bytes.Equal(sums[i], expected[i]) {
+			errs = append(errs, fmt.Errorf("batch #%d: hash is 0x%x, expected 0x%x", i, sums[i], expected[i]))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package batchhash_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/batchhash"
+	blob "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	blobtesting "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/test_utils"
+	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSum(t *testing.T) {
+
+	// A batch of a single chunk is hashed along with its length only
+	batch := _range(20)
+	inner := make([]byte, 32)
+	copy(inner[1:], batch)
+
+	var length [32]byte
+	length[31] = byte(len(batch))
+
+	h := mimc.NewMiMC()
+	h.Write(length[:])
+	h.Write(inner)
+	require.Equal(t, h.Sum(nil), batchhash.Sum(batch))
+
+	// and it matches the partial checksum used in the hint of the circuit
+	buf := make([]byte, 32)
+	gnarkutil.PartialChecksumLooselyPackedBytes(batch, buf, mimc.NewMiMC())
+	require.Equal(t, inner, buf)
+}
+
+func TestSums(t *testing.T) {
+
+	payload := _range(100)
+
+	sums, err := batchhash.Sums(payload, []int{40, 60})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{batchhash.Sum(payload[:40]), batchhash.Sum(payload[40:])}, sums)
+
+	_, err = batchhash.Sums(payload, []int{30, 70})
+	require.Error(t, err, "batches shorter than 31 bytes are rejected by the circuit")
+
+	_, err = batchhash.Sums(payload, []int{40, 61})
+	require.Error(t, err, "the batches must fit in the payload")
+}
+
+func TestCheckBlob(t *testing.T) {
+
+	var (
+		blobBytes = blobtesting.TinyTwoBatchBlob(t)
+		dict      = blobtesting.GetDict(t)
+	)
+
+	header, payload, _, err := blob.DecompressBlob(blobBytes, dict)
+	require.NoError(t, err)
+	require.Equal(t, 2, header.NbBatches())
+
+	expected := [][]byte{
+		batchhash.Sum(payload[:header.BatchSizes[0]]),
+		batchhash.Sum(payload[header.BatchSizes[0] : header.BatchSizes[0]+header.BatchSizes[1]]),
+	}
+
+	sums, err := batchhash.FromBlob(blobBytes, dict)
+	require.NoError(t, err)
+	require.Equal(t, expected, sums)
+
+	require.NoError(t, batchhash.CheckBlob(blobBytes, dict, expected))
+	require.Error(t, batchhash.CheckBlob(blobBytes, dict, expected[:1]))
+
+	expected[1] = batchhash.Sum(payload[:header.BatchSizes[0]])
+	require.Error(t, batchhash.CheckBlob(blobBytes, dict, expected))
+}
@@ -0,0 +1,139 @@
+package batchhash_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/batchhash"
+	blobtesting "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/test_utils"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// maxNbBatches is the number of batches supported by the test circuit
+const maxNbBatches = 100
+
+func TestChecksumBatches(t *testing.T) {
+	const (
+		nbAssignments = 200
+	)
+	var blobData [2000 / 32 * 32]byte // just make sure it's a multiple of the packing size
+	blobLen := 0
+
+	var batchEndss [nbAssignments][]int
+	for i := range batchEndss {
+		batchEndss[i] = make([]int, blobtesting.RandIntn(maxNbBatches)+1)
+		for j := range batchEndss[i] {
+			batchEndss[i][j] = 31 + blobtesting.RandIntn(62)
+			if j > 0 {
+				batchEndss[i][j] += batchEndss[i][j-1]
+			}
+			if batchEndss[i][j] > len(blobData) {
+				if j == 0 || batchEndss[i][j-1]+31 < len(blobData) {
+					batchEndss[i][j] = len(blobData)
+					batchEndss[i] = batchEndss[i][:j+1]
+				} else {
+					batchEndss[i] = batchEndss[i][:j]
+				}
+				break
+			}
+		}
+		if v := batchEndss[i][len(batchEndss[i])-1]; v > blobLen {
+			blobLen = v
+		}
+	}
+
+	blobLen = (blobLen + 31) / 32 * 32
+	_, err := rand.Read(blobData[:blobLen])
+	assert.NoError(t, err)
+
+	testChecksumBatches(t, blobData[:blobLen], batchEndss[:]...)
+
+}
+
+func TestChecksumBatchesTrickyCases(t *testing.T) { // this consists of cases that have at some point failed
+	// TODO Test scenario where nbBatches = maxNbBatches
+
+	testChecksumBatches(t, _range(128), []int{31})
+	testChecksumBatches(t, _range(128), []int{32, 93})
+
+	testChecksumBatches(t, _range(93), []int{33, 64}) // a batch of length 31 but not word aligned
+	testChecksumBatches(t, _range(124), []int{32, 85, 124})
+	testChecksumBatches(t, _range(180), []int{50, 110, 148})
+}
+
+func TestChecksumBatchesSimple(t *testing.T) {
+	blobData := _range(31 * 4)
+	batchEnds := []int{32, 63, 100}
+	testChecksumBatches(t, blobData, batchEnds)
+}
+
+// testChecksumBatches checks that the hashes computed by [batchhash.Sum] are
+// accepted by [batchhash.Check] and that a wrong hash is rejected.
+func testChecksumBatches(t *testing.T, blob []byte, batchEndss ...[]int) {
+	circuit := testChecksumCircuit{
+		Blob: make([]frontend.Variable, len(blob)),
+	}
+	for _, batchEnds := range batchEndss {
+		var sums, lengths [maxNbBatches]frontend.Variable
+		batchStart := 0
+
+		for j := range sums {
+			if j < len(batchEnds) {
+				lengths[j] = batchEnds[j] - batchStart
+				sums[j] = batchhash.Sum(blob[batchStart:batchEnds[j]])
+				batchStart = batchEnds[j]
+			} else {
+				sums[j], lengths[j] = 0, 0
+			}
+		}
+
+		assignment := testChecksumCircuit{
+			Blob:      utils.ToVariableSlice(blob),
+			Lengths:   lengths,
+			Sums:      sums,
+			NbBatches: len(batchEnds),
+		}
+		assignment.Sums[blobtesting.RandIntn(len(batchEnds))] = 3
+
+		assert.Error(t, test.IsSolved(&circuit, &assignment, ecc.BLS12_377.ScalarField()))
+
+		assignment.Sums = sums
+		assert.NoError(t, test.IsSolved(&circuit, &assignment, ecc.BLS12_377.ScalarField()))
+	}
+}
+
+type testChecksumCircuit struct {
+	Blob          []frontend.Variable
+	Lengths, Sums [maxNbBatches]frontend.Variable
+	NbBatches     frontend.Variable
+}
+
+func (c *testChecksumCircuit) Define(api frontend.API) error {
+	api.AssertIsLessOrEqual(c.NbBatches, maxNbBatches-1)
+	api.AssertIsEqual(len(c.Lengths), maxNbBatches)
+	api.AssertIsEqual(len(c.Sums), maxNbBatches)
+
+	hsh, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+
+	if err = batchhash.Check(api, &hsh, c.NbBatches, c.Blob, c.Lengths[:], c.Sums[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// python style
+func _range(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
@@ -3,8 +3,10 @@ package blobdecompression
 import (
 	"errors"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	v0 "github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v0"
@@ -29,3 +31,22 @@ func Assign(blobData []byte, dict []byte, eip4844Enabled bool, x [32]byte, y fr3
 	err = errors.New("decompression circuit assignment : unsupported blob version")
 	return
 }
+
+// AssignWitness returns the full witness of the circuit for the blob and the
+// public input computed during the assignment. The witness of the v1 blobs is
+// streamed from the blob bytes, see [v1.AssignWitness].
+func AssignWitness(blobData []byte, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.Element) (w witness.Witness, publicInput fr.Element, snarkHash []byte, err error) {
+	switch blob.GetVersion(blobData) {
+	case 1:
+		return v1.AssignWitness(blobData, dict, eip4844Enabled, x, y)
+	case 0:
+		var assignment frontend.Circuit
+		if assignment, publicInput, snarkHash, err = v0.Assign(blobData, dict, eip4844Enabled, x, y); err != nil {
+			return
+		}
+		w, err = frontend.NewWitness(assignment, ecc.BLS12_377.ScalarField())
+		return
+	}
+	err = errors.New("decompression circuit assignment : unsupported blob version")
+	return
+}
@@ -0,0 +1,97 @@
+package blobdecompression
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Version returns the version of the blobs decompressed by the circuit
+func Version(id circuits.CircuitID) (uint16, error) {
+	switch id {
+	case circuits.BlobDecompressionV0CircuitID:
+		return 0, nil
+	case circuits.BlobDecompressionV1CircuitID:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("%v is not a blob decompression circuit", id)
+}
+
+// WriteDictionaries saves the dictionaries accepted by a circuit along its
+// setup. Each dictionary is stored in the [config.DictionariesDirName]
+// directory, under its checksum. The first one is also stored as
+// [config.DictionaryFileName] so that the setup remains readable by the
+// provers that only support a single dictionary.
+func WriteDictionaries(setupDir string, version uint16, dicts [][]byte) error {
+
+	dir := filepath.Join(setupDir, config.DictionariesDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create the dictionaries directory: %w", err)
+	}
+
+	for i, dict := range dicts {
+		checksum, err := blob.DictionaryChecksum(dict, version)
+		if err != nil {
+			return fmt.Errorf("could not compute the checksum of dictionary #%v: %w", i, err)
+		}
+		if err := files.WriteFileAtomic(dictionaryPath(setupDir, checksum), dict); err != nil {
+			return fmt.Errorf("could not write dictionary #%v: %w", i, err)
+		}
+	}
+
+	if len(dicts) > 0 {
+		if err := files.WriteFileAtomic(filepath.Join(setupDir, config.DictionaryFileName), dicts[0]); err != nil {
+			return fmt.Errorf("could not write the default dictionary: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadDictionary returns the dictionary of the setup with the given checksum,
+// typically the one recorded in the header of the blob to decompress. The
+// setups predating the support of several dictionaries only have the
+// [config.DictionaryFileName] file, which is used if its checksum matches.
+func ReadDictionary(setupDir string, version uint16, checksum []byte) ([]byte, error) {
+
+	candidates := []string{
+		dictionaryPath(setupDir, checksum),
+		filepath.Join(setupDir, config.DictionaryFileName),
+	}
+
+	for _, path := range candidates {
+
+		dict, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read the dictionary %v: %w", path, err)
+		}
+
+		// The checksum is recomputed to catch the corrupted or the misnamed
+		// files. A mismatch for the default dictionary only means that the
+		// requested one is another dictionary.
+		actual, err := blob.DictionaryChecksum(dict, version)
+		if err != nil {
+			return nil, fmt.Errorf("could not compute the checksum of the dictionary %v: %w", path, err)
+		}
+		if bytes.Equal(actual, checksum) {
+			return dict, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the setup at %v has no dictionary with checksum %v", setupDir, utils.HexEncodeToString(checksum))
+}
+
+// dictionaryPath returns the path of the dictionary with the given checksum
+func dictionaryPath(setupDir string, checksum []byte) string {
+	return filepath.Join(setupDir, config.DictionariesDirName, utils.HexEncodeToString(checksum)+".bin")
+}
@@ -0,0 +1,50 @@
+package blobdecompression
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDictionaries(t *testing.T) {
+
+	dict := make([]byte, 1024)
+	for i := range dict {
+		dict[i] = byte(i * 7)
+	}
+	other := append([]byte{}, dict...)
+	other[0] ^= 1
+
+	var (
+		setupDir = t.TempDir()
+		checksum = func(d []byte) []byte {
+			c, err := blob.DictionaryChecksum(d, 1)
+			require.NoError(t, err)
+			return c
+		}
+	)
+
+	require.NoError(t, WriteDictionaries(setupDir, 1, [][]byte{dict, other}))
+
+	for _, d := range [][]byte{dict, other} {
+		read, err := ReadDictionary(setupDir, 1, checksum(d))
+		require.NoError(t, err)
+		assert.Equal(t, d, read)
+	}
+
+	_, err := ReadDictionary(setupDir, 1, make([]byte, 32))
+	assert.ErrorContains(t, err, "no dictionary")
+
+	// the setups with a single dictionary only have the default file
+	require.NoError(t, os.RemoveAll(filepath.Join(setupDir, config.DictionariesDirName)))
+	read, err := ReadDictionary(setupDir, 1, checksum(dict))
+	require.NoError(t, err)
+	assert.Equal(t, dict, read)
+	_, err = ReadDictionary(setupDir, 1, checksum(other))
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/consensys/gnark/std/compress"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 )
 
 func interpolateLagrangeBls12381(field *emulated.Field[emulated.BLS12381Fr], unitCircleEvaluations []*emulated.Element[emulated.BLS12381Fr], evaluationPoint *emulated.Element[emulated.BLS12381Fr]) (evaluation *emulated.Element[emulated.BLS12381Fr], err error) {
@@ -92,7 +93,7 @@ func VerifyBlobConsistency(api frontend.API, blobCrumbs []frontend.Variable, eva
 
 	blobEmulatedBitReversed := make([]*emulated.Element[emulated.BLS12381Fr], len(blobEmulated))
 	copy(blobEmulatedBitReversed, blobEmulated)
-	bitReverseSlice(blobEmulatedBitReversed)
+	bitutil.ReverseSlice(blobEmulatedBitReversed)
 	lagrangeEval, err := interpolateLagrangeBls12381(field, blobEmulatedBitReversed, evaluationChallengeEmulated)
 	if err != nil {
 		return
@@ -143,27 +144,6 @@ func newElementFromVars(api frontend.API, x [2]frontend.Variable) *emulated.Elem
 	return field.FromBits(append(lBin, hBin...)...)
 }
 
-func bitReverse(n, logN int) int {
-	return utils.ToInt(bits.Reverse64(uint64(n)) >> (64 - logN))
-}
-
-func bitReverseSlice[K interface{}](list []K) {
-	n := uint64(len(list))
-
-	// The standard library's bits.Reverse64 inverts its input as a 64-bit unsigned integer.
-	// However, we need to invert it as a log2(len(list))-bit integer, so we need to correct this by
-	// shifting appropriately.
-	shiftCorrection := uint64(64 - bits.TrailingZeros64(n))
-
-	for i := uint64(0); i < n; i++ {
-		// Find index irev, such that i and irev get swapped
-		irev := bits.Reverse64(i) >> shiftCorrection
-		if irev > i {
-			list[i], list[irev] = list[irev], list[i]
-		}
-	}
-}
-
 func packCrumbsEmulated(api frontend.API, words []frontend.Variable) []*emulated.Element[emulated.BLS12381Fr] {
 	var fieldParams emulated.BLS12381Fr
 	field, err := emulated.NewField[emulated.BLS12381Fr](api)
@@ -17,6 +17,7 @@ import (
 
 	"github.com/consensys/linea-monorepo/prover/circuits/internal"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 
@@ -274,7 +275,7 @@ func TestVectorIopCompatibility(t *testing.T) {
 	blob = append(blob, make([]byte, 4096*32-len(blob))...) // pad if necessary
 	blobElems := make([]fr381.Element, 4096)
 	for i := range blobElems {
-		assert.NoError(t, blobElems[bitReverse(i, 12)].SetBytesCanonical(blob[i*32:(i+1)*32]), i)
+		assert.NoError(t, blobElems[bitutil.Reverse(i, 12)].SetBytesCanonical(blob[i*32:(i+1)*32]), i)
 	}
 	poly := iop.NewPolynomial(&blobElems, iop.Form{Basis: iop.Lagrange, Layout: iop.Regular})
 	poly.ToCanonical(domain)
@@ -6,18 +6,42 @@ import (
 
 	"github.com/consensys/linea-monorepo/prover/circuits/internal"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v0/compress/lzss"
+	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
 	blob "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v0"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/sirupsen/logrus"
 )
 
+func init() {
+	circuits.Register(circuits.CircuitInfo{
+		ID:           circuits.BlobDecompressionV0CircuitID,
+		Curve:        ecc.BLS12_377,
+		Dependencies: []circuits.Dependency{circuits.DependencyDictionary},
+		DefaultSetup: true,
+		NewBuilder: func(_ *config.Config, inputs circuits.SetupInputs) (circuits.Builder, map[string]any, error) {
+			extraFlags := map[string]any{
+				"maxUsableBytes":       blob.MaxUsableBytes,
+				"maxUncompressedBytes": blob.MaxUncompressedBytes,
+			}
+			// The dictionary is a constant of the v0 circuit, so it only
+			// accepts the default one.
+			if len(inputs.Dicts) == 0 {
+				return nil, nil, errors.New("no dictionary provided")
+			}
+			return NewBuilder(inputs.Dicts[0]), extraFlags, nil
+		},
+	})
+}
+
 type builder struct {
 	dict []byte
 }
@@ -30,31 +30,14 @@ func prepare(t require.TestingT, blobBytes []byte) (c *v1.Circuit, a frontend.Ci
 	_, payload, _, err := blobcompressorv1.DecompressBlob(blobBytes, blobtestutils.GetDict(t))
 	assert.NoError(t, err)
 
-	resp, err := blobsubmission.CraftResponse(&blobsubmission.Request{
-		Eip4844Enabled: true,
-		CompressedData: base64.StdEncoding.EncodeToString(blobBytes),
-	})
-	assert.NoError(t, err)
-
-	b, err := hex.DecodeString(resp.ExpectedX[2:])
-	assert.NoError(t, err)
-	var x [32]byte
-	copy(x[:], b)
-
-	b, err = hex.DecodeString(resp.ExpectedY[2:])
-	assert.NoError(t, err)
-	var y fr381.Element
-	y.SetBytes(b)
-
-	blobBytes = append(blobBytes, make([]byte, blobcompressorv1.MaxUsableBytes-len(blobBytes))...)
-	dict := blobtestutils.GetDict(t)
+	blobBytes, dict, x, y, expectedSnarkHash := prepareInputs(t, blobBytes)
 	a, _, snarkHash, err := blobdecompression.Assign(blobBytes, dict, true, x, y)
 	assert.NoError(t, err)
 
 	_, ok := a.(*v1.Circuit)
 	assert.True(t, ok)
 
-	assert.Equal(t, resp.SnarkHash[2:], hex.EncodeToString(snarkHash))
+	assert.Equal(t, expectedSnarkHash, hex.EncodeToString(snarkHash))
 
 	return &v1.Circuit{
 		Dict:                  make([]frontend.Variable, len(dict)),
@@ -63,6 +46,28 @@ func prepare(t require.TestingT, blobBytes []byte) (c *v1.Circuit, a frontend.Ci
 	}, a
 }
 
+// prepareInputs returns the padded blob, the dictionary, the evaluation point
+// and value of the blob and its expected snark hash in hex
+func prepareInputs(t require.TestingT, blobBytes []byte) (paddedBlob, dict []byte, x [32]byte, y fr381.Element, snarkHash string) {
+
+	resp, err := blobsubmission.CraftResponse(&blobsubmission.Request{
+		Eip4844Enabled: true,
+		CompressedData: base64.StdEncoding.EncodeToString(blobBytes),
+	})
+	require.NoError(t, err)
+
+	b, err := hex.DecodeString(resp.ExpectedX[2:])
+	require.NoError(t, err)
+	copy(x[:], b)
+
+	b, err = hex.DecodeString(resp.ExpectedY[2:])
+	require.NoError(t, err)
+	y.SetBytes(b)
+
+	paddedBlob = append(blobBytes, make([]byte, blobcompressorv1.MaxUsableBytes-len(blobBytes))...)
+	return paddedBlob, blobtestutils.GetDict(t), x, y, resp.SnarkHash[2:]
+}
+
 func TestSmallBlob(t *testing.T) {
 	c, a := prepareTestBlob(t)
 	assert.NoError(t, test.IsSolved(c, a, ecc.BLS12_377.ScalarField()))
@@ -90,3 +95,30 @@ func TestSingleBlockBlobNoEngine(t *testing.T) {
 
 	assert.NoError(t, cs.IsSolved(w))
 }
+
+func TestAssignWitness(t *testing.T) {
+
+	for _, blobBytes := range [][]byte{
+		blobtestutils.TinyTwoBatchBlob(t),
+		blobtestutils.SingleBlockBlob(t),
+	} {
+		blobBytes, dict, x, y, _ := prepareInputs(t, blobBytes)
+
+		a, expectedPI, expectedSnarkHash, err := v1.Assign(blobBytes, dict, true, x, y)
+		require.NoError(t, err)
+		expected, err := frontend.NewWitness(a, ecc.BLS12_377.ScalarField())
+		require.NoError(t, err)
+
+		// The streamed witness must be the one gnark derives from the assignment
+		w, pi, snarkHash, err := v1.AssignWitness(blobBytes, dict, true, x, y)
+		require.NoError(t, err)
+		require.Equal(t, expectedPI, pi)
+		require.Equal(t, expectedSnarkHash, snarkHash)
+
+		expectedBin, err := expected.MarshalBinary()
+		require.NoError(t, err)
+		wBin, err := w.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, expectedBin, wBin)
+	}
+}
@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -17,10 +16,12 @@ import (
 	snarkHash "github.com/consensys/gnark/std/hash"
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/batchhash"
 	"github.com/consensys/linea-monorepo/prover/circuits/internal"
+	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc/gkrmimc"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
 
 	blob "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 )
@@ -45,6 +46,12 @@ type Circuit struct {
 	// The dictionary used in the compression algorithm
 	Dict []frontend.Variable
 
+	// DictChecksums are the checksums of the dictionaries the circuit accepts,
+	// as computed by [blob.MiMCChecksumPackedData]. Provisioning several of
+	// them allows switching to a new dictionary without a new setup. They
+	// must all have the length of Dict. If empty, any dictionary is accepted.
+	DictChecksums [][]byte `gnark:"-"`
+
 	// The uncompressed and compressed data corresponds to the data that is
 	// made available on L1 when we submit a blob of transactions. The circuit
 	// proves that these two correspond to the same data. The uncompressed
@@ -189,7 +196,7 @@ func (c Circuit) Define(api frontend.API) error {
 		Length: c.FuncPI.NbBatches,
 	}
 
-	blobSum, y, err := ProcessBlob(api, hsh, c.MaxBlobPayloadNbBytes, c.BlobBytes, c.FuncPI.X, c.FuncPI.Eip4844Enabled, batchSums, c.Dict)
+	blobSum, y, err := ProcessBlob(api, hsh, c.MaxBlobPayloadNbBytes, c.BlobBytes, c.FuncPI.X, c.FuncPI.Eip4844Enabled, batchSums, c.Dict, c.DictChecksums)
 	if err != nil {
 		return err
 	}
@@ -203,22 +210,76 @@ func (c Circuit) Define(api frontend.API) error {
 
 type builder struct {
 	dictionaryLength int
+	dictChecksums    [][]byte
+}
+
+func init() {
+	circuits.Register(circuits.CircuitInfo{
+		ID:           circuits.BlobDecompressionV1CircuitID,
+		Curve:        ecc.BLS12_377,
+		Dependencies: []circuits.Dependency{circuits.DependencyDictionary},
+		DefaultSetup: true,
+		NewBuilder: func(_ *config.Config, inputs circuits.SetupInputs) (circuits.Builder, map[string]any, error) {
+			checksums, err := DictChecksums(inputs.Dicts)
+			if err != nil {
+				return nil, nil, err
+			}
+			hexChecksums := make([]string, len(checksums))
+			for i := range checksums {
+				hexChecksums[i] = utils.HexEncodeToString(checksums[i])
+			}
+			extraFlags := map[string]any{
+				"maxUsableBytes":       blob.MaxUsableBytes,
+				"maxUncompressedBytes": blob.MaxUncompressedBytes,
+				"dictChecksums":        hexChecksums,
+			}
+			return NewBuilder(len(inputs.Dicts[0]), checksums...), extraFlags, nil
+		},
+	})
 }
 
-func NewBuilder(dictionaryLength int) *builder {
-	return &builder{dictionaryLength: dictionaryLength}
+// DictChecksums returns the checksums of the dictionaries provisioned for the
+// circuit. It returns an error if there are none or if their lengths differ,
+// as the length of the dictionary is fixed by the circuit.
+func DictChecksums(dicts [][]byte) ([][]byte, error) {
+
+	if len(dicts) == 0 {
+		return nil, errors.New("no dictionary provided")
+	}
+
+	res := make([][]byte, len(dicts))
+	for i := range dicts {
+		if len(dicts[i]) != len(dicts[0]) {
+			return nil, fmt.Errorf("dictionary #%v has %v bytes but dictionary #0 has %v, the dictionaries of a circuit must have the same length", i, len(dicts[i]), len(dicts[0]))
+		}
+		checksum, err := blob.MiMCChecksumPackedData(dicts[i], 8)
+		if err != nil {
+			return nil, fmt.Errorf("could not compute the checksum of dictionary #%v: %w", i, err)
+		}
+		res[i] = checksum
+	}
+
+	return res, nil
+}
+
+// NewBuilder returns the builder of the circuit for dictionaries of the given
+// length. If checksums are given, the circuit only accepts the dictionaries
+// with these checksums.
+func NewBuilder(dictionaryLength int, dictChecksums ...[]byte) *builder {
+	return &builder{dictionaryLength: dictionaryLength, dictChecksums: dictChecksums}
 }
 
 // Compile the decompression circuit
 // Make sure to add the gkrmimc solver options in proving time
 func (b *builder) Compile() (constraint.ConstraintSystem, error) {
-	return Compile(b.dictionaryLength), nil
+	return Compile(b.dictionaryLength, b.dictChecksums...), nil
 }
 
-func Compile(dictionaryLength int) constraint.ConstraintSystem {
+func Compile(dictionaryLength int, dictChecksums ...[]byte) constraint.ConstraintSystem {
 	// TODO @gbotrel make signature return error...
 	if cs, err := frontend.Compile(ecc.BLS12_377.ScalarField(), scs.NewBuilder, &Circuit{
 		Dict:                  make([]frontend.Variable, dictionaryLength),
+		DictChecksums:         dictChecksums,
 		BlobBytes:             make([]frontend.Variable, blob.MaxUsableBytes),
 		MaxBlobPayloadNbBytes: blob.MaxUncompressedBytes,
 		UseGkrMiMC:            true,
@@ -244,15 +305,10 @@ func AssignFPI(blobBytes, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.
 		err = fmt.Errorf("decompression circuit assignment : too many batches in the header : %d. max %d", header.NbBatches(), MaxNbBatches)
 		return
 	}
-	batchEnds := make([]int, header.NbBatches())
-	if header.NbBatches() > 0 {
-		batchEnds[0] = header.BatchSizes[0]
-	}
-	for i := 1; i < len(header.BatchSizes); i++ {
-		batchEnds[i] = batchEnds[i-1] + header.BatchSizes[i]
-	}
 
-	fpi.BatchSums = BatchesChecksumAssign(batchEnds, payload)
+	if fpi.BatchSums, err = batchhash.Sums(payload, header.BatchSizes); err != nil {
+		return
+	}
 
 	fpi.X = x
 
@@ -273,21 +329,7 @@ func AssignFPI(blobBytes, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.
 
 func Assign(blobBytes, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.Element) (assignment frontend.Circuit, publicInput fr377.Element, snarkHash []byte, err error) {
 
-	fpi, err := AssignFPI(blobBytes, dict, eip4844Enabled, x, y)
-	if err != nil {
-		return
-	}
-	snarkHash = fpi.SnarkHash
-
-	pi, err := fpi.Sum()
-	if err != nil {
-		return
-	}
-	if err = publicInput.SetBytesCanonical(pi); err != nil {
-		return
-	}
-
-	sfpi, err := fpi.ToSnarkType()
+	sfpi, publicInput, snarkHash, err := assignPublicInput(blobBytes, dict, eip4844Enabled, x, y)
 	if err != nil {
 		return
 	}
@@ -304,23 +346,24 @@ func Assign(blobBytes, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.Ele
 	return
 }
 
-// the result for each batch is <data (31 bytes)> ... <data (31 bytes)>
-// for soundness some kind of length indicator must later be incorporated.
-func BatchesChecksumAssign(ends []int, payload []byte) [][]byte {
-	res := make([][]byte, len(ends))
+// assignPublicInput returns the functional public input of the blob and the
+// resulting public input of the circuit
+func assignPublicInput(blobBytes, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.Element) (sfpi FunctionalPublicInputSnark, publicInput fr377.Element, snarkHash []byte, err error) {
 
-	in := make([]byte, len(payload)+31)
-	copy(in, payload) // pad with 31 bytes to avoid out of range panic
-
-	hsh := hash.MIMC_BLS12_377.New()
-	var buf [32]byte
+	fpi, err := AssignFPI(blobBytes, dict, eip4844Enabled, x, y)
+	if err != nil {
+		return
+	}
+	snarkHash = fpi.SnarkHash
 
-	batchStart := 0
-	for i := range res {
-		gnarkutil.ChecksumLooselyPackedBytes(payload[batchStart:ends[i]], buf[:], hsh)
-		res[i] = bytes.Clone(buf[:])
-		batchStart = ends[i]
+	pi, err := fpi.Sum()
+	if err != nil {
+		return
+	}
+	if err = publicInput.SetBytesCanonical(pi); err != nil {
+		return
 	}
 
-	return res
+	sfpi, err = fpi.ToSnarkType()
+	return
 }
@@ -10,11 +10,9 @@ import (
 
 	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v1/test_utils"
 	"github.com/consensys/linea-monorepo/prover/circuits/internal"
-	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
-	"github.com/consensys/gnark-crypto/hash"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/hash/mimc"
@@ -79,97 +77,6 @@ func TestParseHeader(t *testing.T) {
 	test.NewAssert(t).CheckCircuit(&circuit, options...)
 }
 
-func TestChecksumBatches(t *testing.T) {
-	const (
-		nbAssignments = 200
-	)
-	var blobData [2000 / 32 * 32]byte // just make sure it's a multiple of the packing size
-	blobLen := 0
-
-	var batchEndss [nbAssignments][]int
-	for i := range batchEndss {
-		batchEndss[i] = make([]int, blobtesting.RandIntn(MaxNbBatches)+1)
-		for j := range batchEndss[i] {
-			batchEndss[i][j] = 31 + blobtesting.RandIntn(62)
-			if j > 0 {
-				batchEndss[i][j] += batchEndss[i][j-1]
-			}
-			if batchEndss[i][j] > len(blobData) {
-				if j == 0 || batchEndss[i][j-1]+31 < len(blobData) {
-					batchEndss[i][j] = len(blobData)
-					batchEndss[i] = batchEndss[i][:j+1]
-				} else {
-					batchEndss[i] = batchEndss[i][:j]
-				}
-				break
-			}
-		}
-		if v := batchEndss[i][len(batchEndss[i])-1]; v > blobLen {
-			blobLen = v
-		}
-	}
-
-	blobLen = (blobLen + 31) / 32 * 32
-	_, err := rand.Read(blobData[:blobLen])
-	assert.NoError(t, err)
-
-	testChecksumBatches(t, blobData[:blobLen], batchEndss[:]...)
-
-}
-
-func TestChecksumBatchesTrickyCases(t *testing.T) { // this consists of cases that have at some point failed
-	// TODO Test scenario where nbBatches = MaxNbBatches
-
-	testChecksumBatches(t, _range(128), []int{31})
-	testChecksumBatches(t, _range(128), []int{32, 93})
-
-	testChecksumBatches(t, _range(93), []int{33, 64}) // a batch of length 31 but not word aligned
-	testChecksumBatches(t, _range(124), []int{32, 85, 124})
-	testChecksumBatches(t, _range(180), []int{50, 110, 148})
-}
-
-func TestChecksumBatchesSimple(t *testing.T) {
-	blobData := _range(31 * 4)
-	batchEnds := []int{32, 63, 100}
-	testChecksumBatches(t, blobData, batchEnds)
-}
-
-func testChecksumBatches(t *testing.T, blob []byte, batchEndss ...[]int) {
-	hsh := hash.MIMC_BLS12_377.New()
-	circuit := testChecksumCircuit{
-		Blob: make([]frontend.Variable, len(blob)),
-	}
-	for _, batchEnds := range batchEndss {
-		var sums, lengths [MaxNbBatches]frontend.Variable
-		batchStart := 0
-		buf := make([]byte, 32)
-
-		for j := range sums {
-			if j < len(batchEnds) {
-				gnarkutil.ChecksumLooselyPackedBytes(blob[batchStart:batchEnds[j]], buf, hsh)
-				lengths[j] = batchEnds[j] - batchStart
-				sums[j] = bytes.Clone(buf)
-				batchStart = batchEnds[j]
-			} else {
-				sums[j], lengths[j] = 0, 0
-			}
-		}
-
-		assignment := testChecksumCircuit{
-			Blob:      utils.ToVariableSlice(blob),
-			Lengths:   lengths,
-			Sums:      sums,
-			NbBatches: len(batchEnds),
-		}
-		assignment.Sums[blobtesting.RandIntn(len(batchEnds))] = 3
-
-		assert.Error(t, test.IsSolved(&circuit, &assignment, ecc.BLS12_377.ScalarField()))
-
-		assignment.Sums = sums
-		assert.NoError(t, test.IsSolved(&circuit, &assignment, ecc.BLS12_377.ScalarField()))
-	}
-}
-
 type testParseHeaderCircuit struct {
 	Blob                          []frontend.Variable
 	BlocksPerBatch                [MaxNbBatches]frontend.Variable
@@ -189,37 +96,6 @@ func (c *testParseHeaderCircuit) Define(api frontend.API) error {
 	return nil
 }
 
-type testChecksumCircuit struct {
-	Blob          []frontend.Variable
-	Lengths, Sums [MaxNbBatches]frontend.Variable
-	NbBatches     frontend.Variable
-}
-
-func (c *testChecksumCircuit) Define(api frontend.API) error {
-	api.AssertIsLessOrEqual(c.NbBatches, MaxNbBatches-1)
-	api.AssertIsEqual(len(c.Lengths), MaxNbBatches)
-	api.AssertIsEqual(len(c.Sums), MaxNbBatches)
-
-	hsh, err := mimc.NewMiMC(api)
-	if err != nil {
-		return err
-	}
-
-	if err = CheckBatchesSums(api, &hsh, c.NbBatches, c.Blob, c.Lengths[:], c.Sums[:]); err != nil {
-		return err
-	}
-	return nil
-}
-
-// python style
-func _range(n int) []byte {
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = byte(i)
-	}
-	return b
-}
-
 func TestUnpackCircuit(t *testing.T) {
 
 	runTest := func(b []byte) {
@@ -362,3 +238,34 @@ type testDataDictHashCircuit struct {
 func (c *testDataDictHashCircuit) Define(api frontend.API) error {
 	return CheckDictChecksum(api, c.Checksum, c.DictBytes)
 }
+
+func TestDictChecksumAllowed(t *testing.T) {
+	dict := make([]byte, 1024)
+	_, err := rand.Read(dict)
+	require.NoError(t, err)
+	other := append([]byte{}, dict...)
+	other[0] ^= 1
+
+	checksums, err := DictChecksums([][]byte{other, dict})
+	require.NoError(t, err)
+
+	circuit := testDictChecksumAllowedCircuit{Allowed: checksums}
+	assert.NoError(t, test.IsSolved(&circuit, &testDictChecksumAllowedCircuit{Checksum: checksums[1]}, ecc.BLS12_377.ScalarField()))
+
+	circuit.Allowed = checksums[:1]
+	assert.Error(t, test.IsSolved(&circuit, &testDictChecksumAllowedCircuit{Checksum: checksums[1]}, ecc.BLS12_377.ScalarField()))
+
+	// the dictionaries of a circuit must have the same length
+	_, err = DictChecksums([][]byte{dict, dict[1:]})
+	assert.Error(t, err)
+}
+
+type testDictChecksumAllowedCircuit struct {
+	Checksum frontend.Variable
+	Allowed  [][]byte `gnark:"-"`
+}
+
+func (c *testDictChecksumAllowedCircuit) Define(api frontend.API) error {
+	AssertDictChecksumAllowed(api, c.Checksum, c.Allowed)
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build !fuzzlight
+
+package v1_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/linea-monorepo/prover/backend/blobsubmission"
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression"
+	v1 "github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v1"
+	blobcompressorv1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/testvectors"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVectors checks that the circuit decompresses the blobs of the canonical
+// test vectors of the compressor. Only the smallest vectors are solved, to
+// keep the circuit small.
+func TestVectors(t *testing.T) {
+
+	f, err := testvectors.Read("../../../lib/compressor/blob/v1/testvectors/" + testvectors.FixturesPath)
+	require.NoError(t, err)
+
+	for _, v := range f.Vectors {
+		if v.Name != "single-block" && v.Name != "two-batches" {
+			continue
+		}
+		t.Run(v.Name, func(t *testing.T) {
+
+			resp, err := blobsubmission.CraftResponse(&blobsubmission.Request{
+				Eip4844Enabled:      true,
+				CompressedData:      base64.StdEncoding.EncodeToString(v.Blob),
+				ParentStateRootHash: utils.FmtIntHex32Bytes(1),
+				FinalStateRootHash:  utils.FmtIntHex32Bytes(2),
+				PrevShnarf:          utils.FmtIntHex32Bytes(3),
+				DataParentHash:      utils.FmtIntHex32Bytes(4),
+			})
+			require.NoError(t, err)
+
+			var (
+				x [32]byte
+				y fr381.Element
+			)
+			b, err := hex.DecodeString(resp.ExpectedX[2:])
+			require.NoError(t, err)
+			copy(x[:], b)
+			b, err = hex.DecodeString(resp.ExpectedY[2:])
+			require.NoError(t, err)
+			y.SetBytes(b)
+
+			paddedBlob := append(v.Blob, make([]byte, blobcompressorv1.MaxUsableBytes-len(v.Blob))...)
+			a, _, snarkHash, err := blobdecompression.Assign(paddedBlob, f.Dict, true, x, y)
+			require.NoError(t, err)
+			require.Equal(t, resp.SnarkHash[2:], hex.EncodeToString(snarkHash))
+
+			c := &v1.Circuit{
+				Dict:                  make([]frontend.Variable, len(f.Dict)),
+				BlobBytes:             make([]frontend.Variable, blobcompressorv1.MaxUsableBytes),
+				MaxBlobPayloadNbBytes: len(v.Decompressed) * 3 / 2,
+			}
+			require.NoError(t, test.IsSolved(c, a, ecc.BLS12_377.ScalarField()))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// witnessChunkSize is the number of values buffered between the goroutine
+// streaming the witness values and the one filling the witness.
+const witnessChunkSize = 1 << 12
+
+// AssignWitness returns the full witness of the circuit for the blob, along
+// with the public input and the snark hash, as [Assign] does. Unlike
+// [frontend.NewWitness] on the assignment returned by [Assign], it does not
+// convert the dictionary and the blob into slices of variables: their bytes
+// are streamed chunk by chunk into the witness vector. This cuts the peak
+// memory of the decompression jobs.
+//
+// The values are streamed in the order in which gnark walks [Circuit]: the
+// public input first and then the secret fields in order of declaration.
+// Any change of the layout of [Circuit] must be reflected here, which the
+// tests check against [Assign].
+func AssignWitness(blobBytes, dict []byte, eip4844Enabled bool, x [32]byte, y fr381.Element) (w witness.Witness, publicInput fr377.Element, snarkHash []byte, err error) {
+
+	sfpi, publicInput, snarkHash, err := assignPublicInput(blobBytes, dict, eip4844Enabled, x, y)
+	if err != nil {
+		return
+	}
+
+	registerHints()
+
+	const nbPublic = 1
+	nbSecret := len(dict) + len(blobBytes) +
+		len(sfpi.Y) + 3 + len(sfpi.X) + // SnarkHash, Eip4844Enabled and NbBatches
+		len(sfpi.BatchSums)
+
+	if w, err = witness.New(ecc.BLS12_377.ScalarField()); err != nil {
+		return
+	}
+
+	values := make(chan any, witnessChunkSize)
+	go func() {
+		defer close(values)
+
+		// public
+		values <- publicInput
+
+		// secret: Dict, BlobBytes and FuncPI
+		for _, b := range dict {
+			values <- b
+		}
+		for _, b := range blobBytes {
+			values <- b
+		}
+		for _, v := range sfpi.Y {
+			values <- v
+		}
+		values <- sfpi.SnarkHash
+		values <- sfpi.Eip4844Enabled
+		values <- sfpi.NbBatches
+		for _, v := range sfpi.X {
+			values <- v
+		}
+		for _, v := range sfpi.BatchSums {
+			values <- v
+		}
+	}()
+
+	if err = w.Fill(nbPublic, nbSecret, values); err != nil {
+		// drain the channel so that the streaming goroutine terminates
+		for range values {
+		}
+		err = fmt.Errorf("filling the decompression witness: %w", err)
+	}
+
+	return
+}
@@ -4,12 +4,37 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
 )
 
+func init() {
+	for _, c := range []struct {
+		id    circuits.CircuitID
+		mock  circuits.MockCircuitID
+		curve ecc.ID
+	}{
+		{circuits.ExecutionDummyCircuitID, circuits.MockCircuitIDExecution, ecc.BLS12_377},
+		{circuits.BlobDecompressionDummyCircuitID, circuits.MockCircuitIDDecompression, ecc.BLS12_377},
+		{circuits.EmulationDummyCircuitID, circuits.MockCircuitIDEmulation, ecc.BN254},
+	} {
+		circuits.Register(circuits.CircuitInfo{
+			ID:    c.id,
+			Curve: c.curve,
+			Dummy: true,
+			// we want to generate Verifier.sol for the emulation dummy circuit
+			DefaultSetup: c.id == circuits.EmulationDummyCircuitID,
+			NewBuilder: func(*config.Config, circuits.SetupInputs) (circuits.Builder, map[string]any, error) {
+				return NewBuilder(c.mock, c.curve.ScalarField()), nil, nil
+			},
+		})
+	}
+}
+
 type builder struct {
 	circID      circuits.MockCircuitID
 	scalarField *big.Int
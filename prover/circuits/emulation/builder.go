@@ -8,8 +8,20 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover/circuits"
 )
 
+func init() {
+	// the builder needs the verifying keys of the aggregation circuits,
+	// hence it is left to the setup command.
+	circuits.Register(circuits.CircuitInfo{
+		ID:           circuits.EmulationCircuitID,
+		Curve:        ecc.BN254,
+		Dependencies: []circuits.Dependency{circuits.DependencyInnerVerifyingKeys},
+		DefaultSetup: true,
+	})
+}
+
 type builder struct {
 	innerVkeys []plonk.VerifyingKey
 }
@@ -50,3 +62,51 @@ func MakeCS(
 
 	return ccs, nil
 }
+
+type multiBuilder struct {
+	innerVkeys []plonk.VerifyingKey
+	nbProofs   int
+}
+
+// NewMultiBuilder returns the builder of the multi-emulation circuit wrapping
+// nbProofs proofs of the circuits of innerVkeys.
+func NewMultiBuilder(
+	innerVkeys []plonk.VerifyingKey,
+	nbProofs int,
+) *multiBuilder {
+	return &multiBuilder{
+		innerVkeys: innerVkeys,
+		nbProofs:   nbProofs,
+	}
+}
+
+func (b *multiBuilder) Compile() (constraint.ConstraintSystem, error) {
+	return MakeMultiCS(b.innerVkeys, b.nbProofs)
+}
+
+// Generate the constraint system of the multi-emulation circuit, see
+// [CircuitMultiEmulation].
+func MakeMultiCS(
+	innerVkeys []plonk.VerifyingKey,
+	nbProofs int,
+) (constraint.ConstraintSystem, error) {
+
+	outerCircuit, err := allocateMultiCircuit(innerVkeys, nbProofs)
+
+	if err != nil {
+		return nil, fmt.Errorf("while allocating the multi-emulation circuit: %w", err)
+	}
+
+	ccs, err := frontend.Compile(
+		ecc.BN254.ScalarField(),
+		scs.NewBuilder,
+		outerCircuit,
+		frontend.WithCapacity(nbProofs<<25),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("while compiling the multi-emulation circuit: %w", err)
+	}
+
+	return ccs, nil
+}
@@ -63,15 +63,24 @@ func (c *CircuitEmulation) Define(api frontend.API) error {
 		return fmt.Errorf("while asserting the proof are correct: %w", err)
 	}
 
-	// @alex: the intent here is to show that public input and witness
-	// represent an equal number but not in the same representation.
 	f, err := emulated.NewField[emFr](api)
 	if err != nil {
 		return err
 	}
 
+	assertWitnessEncodes(api, f, &c.Witness, c.PublicInput)
+
+	return nil
+}
+
+// assertWitnessEncodes asserts that the public input of the emulated witness
+// and the native public input represent the same number.
+func assertWitnessEncodes(api frontend.API, f *emulated.Field[emFr], witness *emWitness, publicInput frontend.Variable) {
+
+	// @alex: the intent here is to show that public input and witness
+	// represent an equal number but not in the same representation.
 	var (
-		piBits   = f.ToBits(&c.Witness.Public[0])
+		piBits   = f.ToBits(&witness.Public[0])
 		piNative = api.FromBinary(piBits[:fr.Bits]...)
 	)
 
@@ -79,9 +88,7 @@ func (c *CircuitEmulation) Define(api frontend.API) error {
 		api.AssertIsEqual(piBits[i], 0)
 	}
 
-	api.AssertIsEqual(piNative, c.PublicInput)
-
-	return nil
+	api.AssertIsEqual(piNative, publicInput)
 }
 
 // Produces a proof for the outer-proof outside on the BN field
@@ -117,29 +124,37 @@ func allocateOuterCircuit(
 
 	singlePiCs := singleInputCS()
 
+	_innerBaseVKey, _innerCircVKeys, err := emulateVerifyingKeys(innerVkeys)
+	if err != nil {
+		return nil, fmt.Errorf("while converting the verifying-key in emulated representation: %w", err)
+	}
+
+	return &CircuitEmulation{
+		Proof:        emPlonk.PlaceholderProof[emFr, emG1, emG2](singlePiCs),
+		BaseVKey:     _innerBaseVKey,
+		CircuitVkeys: _innerCircVKeys,
+		Witness:      emPlonk.PlaceholderWitness[emFr](singlePiCs),
+	}, nil
+}
+
+// Converts the verifying keys of the BW6 circuits in their emulated
+// representation. The base verifying key is taken from the first one.
+func emulateVerifyingKeys(innerVkeys []plonk.VerifyingKey) (emBaseVKey, []emCircVkey, error) {
+
 	_innerBaseVKey, err := emPlonk.ValueOfBaseVerifyingKey[emFr, emG1, emG2](innerVkeys[0])
 	if err != nil {
-		return nil, fmt.Errorf("while emulating the Base VK: %w", err)
+		return emBaseVKey{}, nil, fmt.Errorf("while emulating the Base VK: %w", err)
 	}
 
 	_innerCircVKeys := make([]emCircVkey, len(innerVkeys))
 	for i := range innerVkeys {
 		_innerCircVKeys[i], err = emPlonk.ValueOfCircuitVerifyingKey[emFr, emG1](innerVkeys[i])
 		if err != nil {
-			return nil, fmt.Errorf("while emulating the Circuit VK: %w", err)
+			return emBaseVKey{}, nil, fmt.Errorf("while emulating the Circuit VK: %w", err)
 		}
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("while converting the verifying-key in emulated representation: %w", err)
-	}
-
-	return &CircuitEmulation{
-		Proof:        emPlonk.PlaceholderProof[emFr, emG1, emG2](singlePiCs),
-		BaseVKey:     _innerBaseVKey,
-		CircuitVkeys: _innerCircVKeys,
-		Witness:      emPlonk.PlaceholderWitness[emFr](singlePiCs),
-	}, nil
+	return _innerBaseVKey, _innerCircVKeys, nil
 }
 
 // Produces an assignment for the outer-circuit toward proving satisfaction of
@@ -150,6 +165,23 @@ func assignOuterCircuit(
 	publicInput fr.Element,
 ) (*CircuitEmulation, error) {
 
+	emulatedProof, emulatedWitness, err := emulateInnerProof(innerProof, publicInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CircuitEmulation{
+		Proof:       emulatedProof,
+		Witness:     emulatedWitness,
+		PublicInput: publicInput,
+		CircuitID:   circuitID,
+	}, nil
+}
+
+// Converts a BW6 proof and its public input into their emulated
+// representations.
+func emulateInnerProof(innerProof plonk.Proof, publicInput fr.Element) (emProof, emWitness, error) {
+
 	// Convert x into a witness object
 
 	var (
@@ -164,20 +196,15 @@ func assignOuterCircuit(
 
 	emulatedProof, err := emPlonk.ValueOfProof[emFr, emG1, emG2](innerProof)
 	if err != nil {
-		return nil, fmt.Errorf("while emulating the inner proof in the outer-circuit: %w", err)
+		return emProof{}, emWitness{}, fmt.Errorf("while emulating the inner proof in the outer-circuit: %w", err)
 	}
 
 	emulatedWitness, err := emPlonk.ValueOfWitness[emFr](innerWitness)
 	if err != nil {
-		return nil, fmt.Errorf("while emulating the witness of the inner proof in the outer-circuit: %w", err)
+		return emProof{}, emWitness{}, fmt.Errorf("while emulating the witness of the inner proof in the outer-circuit: %w", err)
 	}
 
-	return &CircuitEmulation{
-		Proof:       emulatedProof,
-		Witness:     emulatedWitness,
-		PublicInput: publicInput,
-		CircuitID:   circuitID,
-	}, nil
+	return emulatedProof, emulatedWitness, nil
 }
 
 // This is just a dummy placeholder circuit with a single public input which is
@@ -0,0 +1,145 @@
+package emulation
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+)
+
+// CircuitMultiEmulation converts several proofs over the BW6-761 field into a
+// single proof over the BN254 field. It is used to submit several
+// aggregations, covering consecutive block ranges, in a single transaction on
+// L1. Unlike [CircuitEmulation], the circuit has one public input per wrapped
+// proof, in the order of the proofs.
+type CircuitMultiEmulation struct {
+	CircuitVkeys []emCircVkey        `gnark:"-"`
+	BaseVKey     emBaseVKey          `gnark:"-"`
+	Proofs       []emProof           `gnark:",secret:"`
+	Witnesses    []emWitness         `gnark:",secret:"`
+	CircuitIDs   []frontend.Variable `gnark:",secret:"`
+	PublicInputs []frontend.Variable `gnark:",public"`
+}
+
+func (c *CircuitMultiEmulation) Define(api frontend.API) error {
+
+	verifier, err := emPlonk.NewVerifier[emFr, emG1, emG2, emGT](api)
+	if err != nil {
+		return fmt.Errorf("while instantiating the verifier: %w", err)
+	}
+
+	err = verifier.AssertDifferentProofs(c.BaseVKey, c.CircuitVkeys, c.CircuitIDs, c.Proofs, c.Witnesses, emPlonk.WithCompleteArithmetic())
+	if err != nil {
+		return fmt.Errorf("while asserting the proof are correct: %w", err)
+	}
+
+	f, err := emulated.NewField[emFr](api)
+	if err != nil {
+		return err
+	}
+
+	for i := range c.Witnesses {
+		assertWitnessEncodes(api, f, &c.Witnesses[i], c.PublicInputs[i])
+	}
+
+	return nil
+}
+
+// Produces a proof wrapping all the inner proofs on the BN field. The circuit
+// IDs and the public inputs are given in the order of the inner proofs and
+// their number must match the one of the setup.
+func MakeMultiProof(
+	setup *circuits.Setup,
+	circuitIDs []int,
+	innerProofs []plonk.Proof,
+	publicInputs []fr.Element,
+) (
+	proof plonk.Proof,
+	err error,
+) {
+
+	assignment, err := assignMultiCircuit(
+		circuitIDs,
+		innerProofs,
+		publicInputs,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("while generating the multi-emulation circuit assignment: %w", err)
+	}
+
+	return circuits.ProveCheck(setup, assignment)
+}
+
+// Allocates a new multi-emulation circuit wrapping nbProofs proofs. See
+// [allocateOuterCircuit].
+func allocateMultiCircuit(
+	innerVkeys []plonk.VerifyingKey,
+	nbProofs int,
+) (*CircuitMultiEmulation, error) {
+
+	if nbProofs < 2 {
+		return nil, fmt.Errorf("the multi-emulation circuit wraps at least 2 proofs, got %v", nbProofs)
+	}
+
+	singlePiCs := singleInputCS()
+
+	_innerBaseVKey, _innerCircVKeys, err := emulateVerifyingKeys(innerVkeys)
+	if err != nil {
+		return nil, fmt.Errorf("while converting the verifying-key in emulated representation: %w", err)
+	}
+
+	res := &CircuitMultiEmulation{
+		BaseVKey:     _innerBaseVKey,
+		CircuitVkeys: _innerCircVKeys,
+		Proofs:       make([]emProof, nbProofs),
+		Witnesses:    make([]emWitness, nbProofs),
+		CircuitIDs:   make([]frontend.Variable, nbProofs),
+		PublicInputs: make([]frontend.Variable, nbProofs),
+	}
+
+	for i := range res.Proofs {
+		res.Proofs[i] = emPlonk.PlaceholderProof[emFr, emG1, emG2](singlePiCs)
+		res.Witnesses[i] = emPlonk.PlaceholderWitness[emFr](singlePiCs)
+	}
+
+	return res, nil
+}
+
+// Produces an assignment for the multi-emulation circuit
+func assignMultiCircuit(
+	circuitIDs []int,
+	innerProofs []plonk.Proof,
+	publicInputs []fr.Element,
+) (*CircuitMultiEmulation, error) {
+
+	if len(circuitIDs) != len(innerProofs) || len(publicInputs) != len(innerProofs) {
+		return nil, fmt.Errorf(
+			"got %v circuit IDs and %v public inputs for %v proofs",
+			len(circuitIDs), len(publicInputs), len(innerProofs),
+		)
+	}
+
+	res := &CircuitMultiEmulation{
+		Proofs:       make([]emProof, len(innerProofs)),
+		Witnesses:    make([]emWitness, len(innerProofs)),
+		CircuitIDs:   make([]frontend.Variable, len(innerProofs)),
+		PublicInputs: make([]frontend.Variable, len(innerProofs)),
+	}
+
+	for i := range innerProofs {
+		var err error
+		res.Proofs[i], res.Witnesses[i], err = emulateInnerProof(innerProofs[i], publicInputs[i])
+		if err != nil {
+			return nil, fmt.Errorf("inner proof #%v: %w", i, err)
+		}
+		res.CircuitIDs[i] = circuitIDs[i]
+		res.PublicInputs[i] = publicInputs[i]
+	}
+
+	return res, nil
+}
@@ -1,16 +1,62 @@
 package execution
 
 import (
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/profile"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
 )
 
+func init() {
+	for _, id := range []circuits.CircuitID{circuits.ExecutionCircuitID, circuits.ExecutionLargeCircuitID} {
+		large := id == circuits.ExecutionLargeCircuitID
+		circuits.Register(circuits.CircuitInfo{
+			ID:           id,
+			Curve:        ecc.BLS12_377,
+			Dependencies: []circuits.Dependency{circuits.DependencyTracesLimits},
+			DefaultSetup: true,
+			NewBuilder: func(cfg *config.Config, _ circuits.SetupInputs) (circuits.Builder, map[string]any, error) {
+				limits := cfg.TracesLimits
+				if large {
+					limits = cfg.TracesLimitsLarge
+				}
+				extraFlags := map[string]any{
+					"cfg_checksum":           cfg.Execution.SetupChecksum(&limits),
+					"cfg_checksum_canonical": cfg.Execution.VersionedSetupChecksum(&limits, config.ChecksumCanonical),
+				}
+				zkEvm, err := zkevm.FullZkEvm(&limits, cfg.Execution.SIS.Params())
+				if err != nil {
+					return nil, nil, err
+				}
+				b := NewBuilder(zkEvm)
+				if cfg.PublicInputInterconnection.ProverMetadata {
+					b.proverMetadata = ProverMetadataDigest(cfg)
+					extraFlags["prover_metadata"] = utils.HexEncodeToString(b.proverMetadata)
+				}
+				if cfg.PublicInputInterconnection.TracesLimitsBinding {
+					b.tracesLimits = TracesLimitsDigest(&limits)
+					extraFlags["traces_limits_digest"] = utils.HexEncodeToString(b.tracesLimits)
+				}
+				return b, extraFlags, nil
+			},
+		})
+	}
+}
+
 type builder struct {
 	zkevm *zkevm.ZkEvm
+	// proverMetadata is the digest of the prover metadata, nil if they are
+	// not part of the functional public input.
+	proverMetadata []byte
+	// tracesLimits is the digest of the traces limits, nil if they are not
+	// part of the functional public input.
+	tracesLimits []byte
 }
 
 func NewBuilder(z *zkevm.ZkEvm) *builder {
@@ -18,12 +64,18 @@ func NewBuilder(z *zkevm.ZkEvm) *builder {
 }
 
 func (b *builder) Compile() (constraint.ConstraintSystem, error) {
-	return makeCS(b.zkevm), nil
+	return makeCS(b.zkevm, b.proverMetadata, b.tracesLimits), nil
 }
 
 // builds the circuit
-func makeCS(z *zkevm.ZkEvm) constraint.ConstraintSystem {
+func makeCS(z *zkevm.ZkEvm, proverMetadata, tracesLimits []byte) constraint.ConstraintSystem {
 	circuit := Allocate(z)
+	if proverMetadata != nil {
+		circuit.EnableProverMetadata(proverMetadata)
+	}
+	if tracesLimits != nil {
+		circuit.EnableTracesLimits(tracesLimits)
+	}
 
 	pro := profile.Start(profile.WithPath("./profiling-execution.pprof"))
 	defer pro.Stop()
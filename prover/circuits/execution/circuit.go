@@ -4,7 +4,6 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark/backend/plonk"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
@@ -25,6 +24,16 @@ type CircuitExecution struct {
 	// The extractor only needs to be provided during the definition of the
 	// circuit and is omitted during the assignment of the circuit.
 	extractor publicInput.FunctionalInputExtractor `gnark:"-"`
+	// proverMetadata is the digest that the functional public inputs must
+	// carry when the prover metadata are enabled, see [ProverMetadataDigest].
+	// As the extractor, it is only needed during the definition of the
+	// circuit.
+	proverMetadata []byte `gnark:"-"`
+	// tracesLimits is the digest of the traces limits that the functional
+	// public inputs must carry when the limits are bound, see
+	// [TracesLimitsDigest]. It is only needed during the definition of the
+	// circuit.
+	tracesLimits []byte `gnark:"-"`
 	// The functional public inputs are the "actual" statement made by the
 	// circuit. They are not part of the public input of the circuit for
 	// a number of reasons involving efficiency and simplicity in the aggregation
@@ -54,6 +63,24 @@ func Allocate(zkevm *zkevm.ZkEvm) CircuitExecution {
 	}
 }
 
+// EnableProverMetadata switches the circuit to the mode where the functional
+// public input additionally includes the digest of the prover version and of
+// the compilation suite. The circuit checks that it equals digest, so that the
+// digest is pinned by the verifying key and the aggregation can check it.
+func (c *CircuitExecution) EnableProverMetadata(digest []byte) {
+	c.proverMetadata = digest
+	c.FuncInputs.WithProverMetadata = true
+}
+
+// EnableTracesLimits switches the circuit to the mode where the functional
+// public input additionally includes the digest of the traces limits the
+// circuit is compiled for. The circuit checks that it equals digest so that
+// the aggregation can restrict the limits it accepts.
+func (c *CircuitExecution) EnableTracesLimits(digest []byte) {
+	c.tracesLimits = digest
+	c.FuncInputs.WithTracesLimits = true
+}
+
 // assign the wizard proof to the outer circuit
 func assign(
 	comp *wizard.CompiledIOP,
@@ -82,54 +109,45 @@ func (c *CircuitExecution) Define(api frontend.API) error {
 		c.extractor,
 	)
 
+	if c.FuncInputs.WithProverMetadata {
+		api.AssertIsEqual(c.FuncInputs.ProverMetadataDigest, new(big.Int).SetBytes(c.proverMetadata))
+	}
+
+	if c.FuncInputs.WithTracesLimits {
+		api.AssertIsEqual(c.FuncInputs.TracesLimitsDigest, new(big.Int).SetBytes(c.tracesLimits))
+	}
+
 	// Add missing public input check
 	mimcHasher, _ := mimc.NewMiMC(api)
 	api.AssertIsEqual(c.PublicInput, c.FuncInputs.Sum(api, &mimcHasher))
 	return nil
 }
 
+// MakeProof generates the outer-proof of the execution and returns it
+// serialized. The options are passed to [circuits.ProveCheck] on top of the
+// ones of the recursion, e.g. the solver options.
 func MakeProof(
 	setup circuits.Setup,
 	comp *wizard.CompiledIOP,
 	wproof wizard.Proof,
 	funcInputs FunctionalPublicInput,
+	opts ...any,
 ) string {
 
 	assignment := assign(comp, wproof, funcInputs)
-	witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
-	if err != nil {
-		panic(err)
-	}
 
-	proof, err := plonk.Prove(
-		setup.Circuit,
-		setup.ProvingKey,
-		witness,
+	opts = append([]any{
 		emPlonk.GetNativeProverOptions(ecc.BW6_761.ScalarField(), setup.Circuit.Field()),
-	)
+		emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), setup.Circuit.Field()),
+	}, opts...)
+
+	proof, err := circuits.ProveCheck(&setup, &assignment, opts...)
 	if err != nil {
 		panic(err)
 	}
 
 	logrus.Infof("generated outer-circuit proof `%++v` for input `%v`", proof, assignment.PublicInput.(*big.Int).String())
 
-	// Sanity-check : the proof must pass
-	{
-		pubwitness, err := frontend.NewWitness(
-			&assignment,
-			ecc.BLS12_377.ScalarField(),
-			frontend.PublicOnly(),
-		)
-		if err != nil {
-			panic(err)
-		}
-
-		err = plonk.Verify(proof, setup.VerifyingKey, pubwitness, emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), setup.Circuit.Field()))
-		if err != nil {
-			panic(err)
-		}
-	}
-
 	// Write the serialized proof
 	return circuits.SerializeProofRaw(proof)
 }
@@ -0,0 +1,62 @@
+package execution
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+)
+
+// ProverMetadataDigest returns the digest of the prover version and of the
+// compilation suite of the full prover, i.e. [zkevm.FullMetadata] and the SIS
+// instance of the config. It is part of the functional public input of the
+// execution circuit when the prover metadata are enabled, and the aggregation
+// circuit checks it against the value of its own config so that the on-chain
+// verification pins the prover builds that produced the batch. The first byte
+// is zeroed so that the digest fits in a field element.
+//
+// The digest changes with the version of the config, so the proofs of a
+// version are rejected by the aggregation of the next one, see
+// [config.PublicInput.ProverMetadata].
+func ProverMetadataDigest(cfg *config.Config) []byte {
+
+	var (
+		hsh = sha256.New()
+		sis = cfg.Execution.SIS.Params()
+	)
+
+	writeString(hsh, cfg.Version)
+	writeString(hsh, zkevm.FullMetadata.Title)
+	writeString(hsh, zkevm.FullMetadata.Version)
+	writeNum(hsh, uint64(sis.LogTwoBound))
+	writeNum(hsh, uint64(sis.LogTwoDegree))
+
+	res := hsh.Sum(nil)
+	res[0] = 0
+	return res
+}
+
+// TracesLimitsDigest returns the digest of the traces limits, i.e. of
+// [config.TracesLimits.Checksum]. It is part of the functional public input of
+// the execution circuit when the traces limits are bound, and the aggregation
+// circuit checks it against the limit profiles of its own config so that the
+// on-chain verification pins the limits the batch was proven with. As for
+// [ProverMetadataDigest], the first byte is zeroed.
+func TracesLimitsDigest(limits *config.TracesLimits) []byte {
+	hsh := sha256.New()
+	writeString(hsh, limits.Checksum())
+	res := hsh.Sum(nil)
+	res[0] = 0
+	return res
+}
+
+// writeString writes a length-prefixed string so that the concatenation of
+// several strings is unambiguous.
+func writeString(hsh hash.Hash, s string) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(len(s)))
+	hsh.Write(b[:])
+	hsh.Write([]byte(s))
+}
@@ -26,6 +26,16 @@ type FunctionalPublicInputQSnark struct {
 	FinalBlockTimestamp    frontend.Variable
 	FinalRollingHash       [32]frontend.Variable
 	FinalRollingHashNumber frontend.Variable
+	// ProverMetadataDigest is the digest of the prover version and of the
+	// compilation suite, see [ProverMetadataDigest]. It is only part of the
+	// public input when WithProverMetadata is set.
+	ProverMetadataDigest frontend.Variable
+	WithProverMetadata   bool `gnark:"-"`
+	// TracesLimitsDigest is the digest of the traces limits the execution was
+	// proven with, see [TracesLimitsDigest]. It is only part of the public
+	// input when WithTracesLimits is set.
+	TracesLimitsDigest frontend.Variable
+	WithTracesLimits   bool `gnark:"-"`
 }
 
 // L2MessageHashes is a wrapper for [Var32Slice] it is use to instantiate the
@@ -115,6 +125,8 @@ type FunctionalPublicInput struct {
 	InitialRollingHashNumber uint64
 	ChainID                  uint64
 	L2MessageServiceAddr     types.EthAddress
+	ProverMetadataDigest     []byte // nil unless the prover metadata are enabled
+	TracesLimitsDigest       []byte // nil unless the traces limits are bound
 }
 
 // RangeCheck checks that values are within range
@@ -144,6 +156,12 @@ func (pi *FunctionalPublicInputSnark) Sum(api frontend.API, hsh gnarkHash.FieldH
 		pi.FinalStateRootHash, pi.FinalBlockNumber, pi.FinalBlockTimestamp, finalRollingHash[0], finalRollingHash[1], pi.FinalRollingHashNumber,
 		pi.InitialStateRootHash, pi.InitialBlockNumber, pi.InitialBlockTimestamp, initialRollingHash[0], initialRollingHash[1], pi.InitialRollingHashNumber,
 		pi.ChainID, pi.L2MessageServiceAddr)
+	if pi.WithProverMetadata {
+		hsh.Write(pi.ProverMetadataDigest)
+	}
+	if pi.WithTracesLimits {
+		hsh.Write(pi.TracesLimitsDigest)
+	}
 
 	return hsh.Sum()
 }
@@ -157,6 +175,10 @@ func (pi *FunctionalPublicInput) ToSnarkType() (FunctionalPublicInputSnark, erro
 			FinalBlockNumber:       pi.FinalBlockNumber,
 			FinalBlockTimestamp:    pi.FinalBlockTimestamp,
 			FinalRollingHashNumber: pi.FinalRollingHashNumber,
+			ProverMetadataDigest:   0,
+			WithProverMetadata:     pi.ProverMetadataDigest != nil,
+			TracesLimitsDigest:     0,
+			WithTracesLimits:       pi.TracesLimitsDigest != nil,
 		},
 		InitialStateRootHash:     slices.Clone(pi.InitialStateRootHash[:]),
 		InitialBlockNumber:       pi.InitialBlockNumber,
@@ -167,6 +189,12 @@ func (pi *FunctionalPublicInput) ToSnarkType() (FunctionalPublicInputSnark, erro
 	}
 	utils.Copy(res.FinalRollingHash[:], pi.FinalRollingHash[:])
 	utils.Copy(res.InitialRollingHash[:], pi.InitialRollingHash[:])
+	if pi.ProverMetadataDigest != nil {
+		res.ProverMetadataDigest = slices.Clone(pi.ProverMetadataDigest)
+	}
+	if pi.TracesLimitsDigest != nil {
+		res.TracesLimitsDigest = slices.Clone(pi.TracesLimitsDigest)
+	}
 
 	var err error
 	if nbMsg := len(pi.L2MessageHashes); nbMsg > pi.MaxNbL2MessageHashes {
@@ -204,6 +232,12 @@ func (pi *FunctionalPublicInput) Sum() []byte { // all mimc; no need to provide
 	writeNum(hsh, pi.InitialRollingHashNumber)
 	writeNum(hsh, pi.ChainID)
 	hsh.Write(pi.L2MessageServiceAddr[:])
+	if pi.ProverMetadataDigest != nil {
+		hsh.Write(pi.ProverMetadataDigest)
+	}
+	if pi.TracesLimitsDigest != nil {
+		hsh.Write(pi.TracesLimitsDigest)
+	}
 
 	return hsh.Sum(nil)
 
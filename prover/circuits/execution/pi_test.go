@@ -7,6 +7,7 @@ import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/hash/mimc"
 	snarkTestUtils "github.com/consensys/linea-monorepo/prover/circuits/internal/test_utils"
+	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
 )
 
@@ -47,4 +48,69 @@ func TestPIConsistency(t *testing.T) {
 		}
 		return []frontend.Variable{snarkPi.Sum(api, &hsh)}
 	}, piSum)(t)
+
+	// with the prover metadata
+	pi.ProverMetadataDigest = ProverMetadataDigest(&config.Config{Version: "v1.0.0"})
+
+	snarkPiMetadata, err := pi.ToSnarkType()
+	require.NoError(t, err)
+	require.True(t, snarkPiMetadata.WithProverMetadata)
+	piSumMetadata := pi.Sum()
+	require.NotEqual(t, piSum, piSumMetadata)
+
+	snarkTestUtils.SnarkFunctionTest(func(api frontend.API) []frontend.Variable {
+		hsh, err := mimc.NewMiMC(api)
+		if err != nil {
+			panic(err)
+		}
+		return []frontend.Variable{snarkPiMetadata.Sum(api, &hsh)}
+	}, piSumMetadata)(t)
+
+	// with the traces limits on top
+	pi.TracesLimitsDigest = TracesLimitsDigest(&config.TracesLimits{Add: 1 << 10})
+
+	snarkPiLimits, err := pi.ToSnarkType()
+	require.NoError(t, err)
+	require.True(t, snarkPiLimits.WithTracesLimits)
+	piSumLimits := pi.Sum()
+	require.NotEqual(t, piSumMetadata, piSumLimits)
+
+	snarkTestUtils.SnarkFunctionTest(func(api frontend.API) []frontend.Variable {
+		hsh, err := mimc.NewMiMC(api)
+		if err != nil {
+			panic(err)
+		}
+		return []frontend.Variable{snarkPiLimits.Sum(api, &hsh)}
+	}, piSumLimits)(t)
+}
+
+func TestProverMetadataDigest(t *testing.T) {
+	cfg := config.Config{Version: "v1.0.0"}
+	cfg.Execution.SIS = config.SIS{LogTwoBound: 16, LogTwoDegree: 6}
+
+	digest := ProverMetadataDigest(&cfg)
+	require.Len(t, digest, 32)
+	require.Zero(t, digest[0], "the digest must fit in a field element")
+	require.Equal(t, digest, ProverMetadataDigest(&cfg))
+
+	other := cfg
+	other.Version = "v1.0.1"
+	require.NotEqual(t, digest, ProverMetadataDigest(&other), "the digest must depend on the prover version")
+
+	other = cfg
+	other.Execution.SIS.LogTwoBound = 8
+	require.NotEqual(t, digest, ProverMetadataDigest(&other), "the digest must depend on the compilation suite")
+}
+
+func TestTracesLimitsDigest(t *testing.T) {
+	limits := config.TracesLimits{Add: 1 << 10, Hub: 1 << 20}
+
+	digest := TracesLimitsDigest(&limits)
+	require.Len(t, digest, 32)
+	require.Zero(t, digest[0], "the digest must fit in a field element")
+	require.Equal(t, digest, TracesLimitsDigest(&limits))
+
+	other := limits
+	other.Hub <<= 1
+	require.NotEqual(t, digest, TracesLimitsDigest(&other), "the digest must depend on the limits")
 }
@@ -192,6 +192,13 @@ func (c *Compiled) Assign(r Request) (a Circuit, err error) {
 		ChainID:                aggregationFPI.ChainID,
 		MaxNbL2MessageHashes:   config.ExecutionMaxNbMsg,
 	}
+	if config.ProverMetadata {
+		executionFPI.ProverMetadataDigest = config.ProverMetadataDigest
+	}
+	if config.TracesLimitsBinding {
+		// the padding executions carry any of the accepted digests
+		executionFPI.TracesLimitsDigest = config.TracesLimitsDigests[0]
+	}
 	for i := range a.ExecutionFPIQ {
 		executionFPI.InitialRollingHash = executionFPI.FinalRollingHash
 		executionFPI.InitialBlockNumber = executionFPI.FinalBlockNumber
@@ -212,6 +219,13 @@ func (c *Compiled) Assign(r Request) (a Circuit, err error) {
 			copy(executionFPI.DataChecksum[:], execDataChecksums[i])
 			executionFPI.L2MessageHashes = r.Executions[i].L2MsgHashes
 
+			if config.TracesLimitsBinding {
+				if executionFPI.TracesLimitsDigest, err = checkTracesLimitsDigest(config.TracesLimitsDigests, r.Executions[i].TracesLimitsDigest); err != nil {
+					err = fmt.Errorf("execution #%d: %w", i, err)
+					return
+				}
+			}
+
 			l2MessageHashes = append(l2MessageHashes, r.Executions[i].L2MsgHashes...)
 
 			if r.Executions[i].FinalRollingHashNumber != 0 { // if the rolling hash is being updated, record the change
@@ -340,3 +354,18 @@ func MerkleRoot(hsh hash.Hash, treeNbLeaves int, data [][32]byte) [32]byte {
 
 	return b[0]
 }
+
+// checkTracesLimitsDigest returns an error if the traces limits digest of an
+// execution is not one of the accepted ones. The circuit would not be
+// satisfied otherwise.
+func checkTracesLimitsDigest(accepted [][]byte, digest []byte) ([]byte, error) {
+	if digest == nil {
+		return nil, errors.New("the traces limits are bound but the execution carries no traces limits digest")
+	}
+	for _, d := range accepted {
+		if bytes.Equal(d, digest) {
+			return digest, nil
+		}
+	}
+	return nil, fmt.Errorf("the traces limits digest %x is not one of the accepted ones", digest)
+}
@@ -48,6 +48,15 @@ type Circuit struct {
 	MaxNbCircuits    int // possibly useless TODO consider removing
 	UseGkrMimc       bool
 	MockKeccakWizard bool // for testing purposes, bypass expensive keccak verification
+	// ProverMetadataDigest is the prover metadata digest that the execution
+	// functional public inputs must carry, see [execution.ProverMetadataDigest].
+	// It is nil if they carry none.
+	ProverMetadataDigest []byte `gnark:"-"`
+	// TracesLimitsDigests are the digests of the traces limits that the
+	// execution functional public inputs may carry, see
+	// [execution.TracesLimitsDigest]. They are nil if the traces limits are
+	// not bound.
+	TracesLimitsDigests [][]byte `gnark:"-"`
 }
 
 func (c *Circuit) Define(api frontend.API) error {
@@ -96,6 +105,33 @@ func (c *Circuit) Define(api frontend.API) error {
 
 	blobBatchHashes := internal.ChecksumSubSlices(api, hshM, batchHashes, internal.VarSlice{Values: nbBatchesSums, Length: c.NbDecompression})
 
+	// the prover metadata digest is a constant of the circuit, so that the
+	// verifying key pins the prover builds that produced the execution proofs
+	if c.ProverMetadataDigest != nil {
+		proverMetadataDigest := new(big.Int).SetBytes(c.ProverMetadataDigest)
+		for _, pi := range c.ExecutionFPIQ {
+			if !pi.WithProverMetadata {
+				return errors.New("the execution functional public inputs do not include the prover metadata")
+			}
+			api.AssertIsEqual(pi.ProverMetadataDigest, proverMetadataDigest)
+		}
+	}
+
+	// the accepted traces limits digests are constants of the circuit as
+	// well; every execution must carry one of them.
+	if c.TracesLimitsDigests != nil {
+		for _, pi := range c.ExecutionFPIQ {
+			if !pi.WithTracesLimits {
+				return errors.New("the execution functional public inputs do not include the traces limits")
+			}
+			prod := frontend.Variable(1)
+			for _, digest := range c.TracesLimitsDigests {
+				prod = api.Mul(prod, api.Sub(pi.TracesLimitsDigest, new(big.Int).SetBytes(digest)))
+			}
+			api.AssertIsEqual(prod, 0)
+		}
+	}
+
 	shnarfParams := make([]ShnarfIteration, len(c.DecompressionPublicInput))
 	for i, piq := range c.DecompressionFPIQ {
 		piq.RangeCheck(api)
@@ -240,6 +276,14 @@ func Compile(c config.PublicInput, wizardCompilationOpts ...func(iop *wizard.Com
 		c.L2MsgMaxNbMerkle = (c.MaxNbExecution*c.ExecutionMaxNbMsg + merkleNbLeaves - 1) / merkleNbLeaves
 	}
 
+	if c.ProverMetadata && c.ProverMetadataDigest == nil {
+		return nil, errors.New("the prover metadata are enabled but their digest is not set, see PublicInputConfig")
+	}
+
+	if c.TracesLimitsBinding && len(c.TracesLimitsDigests) == 0 {
+		return nil, errors.New("the traces limits are bound but their digests are not set, see PublicInputConfig")
+	}
+
 	sh := newKeccakCompiler(c).Compile(wizardCompilationOpts...)
 	shc, err := sh.GetCircuit()
 	if err != nil {
@@ -270,17 +314,21 @@ func (c *Compiled) getConfig() (config.PublicInput, error) {
 		}
 	}
 	return config.PublicInput{
-		MaxNbDecompression: len(c.Circuit.DecompressionFPIQ),
-		MaxNbExecution:     len(c.Circuit.ExecutionFPIQ),
-		ExecutionMaxNbMsg:  executionNbMsg,
-		L2MsgMerkleDepth:   c.Circuit.L2MessageMerkleDepth,
-		L2MsgMaxNbMerkle:   c.Circuit.L2MessageMaxNbMerkle,
-		MaxNbCircuits:      c.Circuit.MaxNbCircuits,
+		MaxNbDecompression:   len(c.Circuit.DecompressionFPIQ),
+		MaxNbExecution:       len(c.Circuit.ExecutionFPIQ),
+		ExecutionMaxNbMsg:    executionNbMsg,
+		L2MsgMerkleDepth:     c.Circuit.L2MessageMerkleDepth,
+		L2MsgMaxNbMerkle:     c.Circuit.L2MessageMaxNbMerkle,
+		MaxNbCircuits:        c.Circuit.MaxNbCircuits,
+		ProverMetadata:       c.Circuit.ProverMetadataDigest != nil,
+		ProverMetadataDigest: c.Circuit.ProverMetadataDigest,
+		TracesLimitsBinding:  c.Circuit.TracesLimitsDigests != nil,
+		TracesLimitsDigests:  c.Circuit.TracesLimitsDigests,
 	}, nil
 }
 
 func allocateCircuit(c config.PublicInput) Circuit {
-	return Circuit{
+	res := Circuit{
 		DecompressionPublicInput: make([]frontend.Variable, c.MaxNbDecompression),
 		ExecutionPublicInput:     make([]frontend.Variable, c.MaxNbExecution),
 		DecompressionFPIQ:        make([]decompression.FunctionalPublicInputQSnark, c.MaxNbDecompression),
@@ -291,6 +339,17 @@ func allocateCircuit(c config.PublicInput) Circuit {
 		MockKeccakWizard:         c.MockKeccakWizard,
 		UseGkrMimc:               true,
 	}
+	if c.ProverMetadata {
+		res.ProverMetadataDigest = c.ProverMetadataDigest
+	}
+	if c.TracesLimitsBinding {
+		res.TracesLimitsDigests = c.TracesLimitsDigests
+	}
+	for i := range res.ExecutionFPIQ {
+		res.ExecutionFPIQ[i].WithProverMetadata = c.ProverMetadata
+		res.ExecutionFPIQ[i].WithTracesLimits = c.TracesLimitsBinding
+	}
+	return res
 }
 
 func newKeccakCompiler(c config.PublicInput) *keccak.StrictHasherCompiler {
@@ -316,6 +375,35 @@ type builder struct {
 	*config.PublicInput
 }
 
+func init() {
+	circuits.Register(circuits.CircuitInfo{
+		ID:           circuits.PublicInputInterconnectionCircuitID,
+		Curve:        ecc.BLS12_377,
+		DefaultSetup: true,
+		NewBuilder: func(cfg *config.Config, _ circuits.SetupInputs) (circuits.Builder, map[string]any, error) {
+			return NewBuilder(PublicInputConfig(cfg)), nil, nil
+		},
+	})
+}
+
+// PublicInputConfig returns the config of the circuit. It is the one of the
+// public_input_interconnection section, along with the digest of the prover
+// metadata if they are enabled and the digests of the normal and large traces
+// limits if they are bound.
+func PublicInputConfig(cfg *config.Config) config.PublicInput {
+	res := cfg.PublicInputInterconnection
+	if res.ProverMetadata {
+		res.ProverMetadataDigest = execution.ProverMetadataDigest(cfg)
+	}
+	if res.TracesLimitsBinding {
+		res.TracesLimitsDigests = [][]byte{
+			execution.TracesLimitsDigest(&cfg.TracesLimits),
+			execution.TracesLimitsDigest(&cfg.TracesLimitsLarge),
+		}
+	}
+	return res
+}
+
 func NewBuilder(c config.PublicInput) circuits.Builder {
 	return builder{&c}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/consensys/gnark/test"
 	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
 	"github.com/consensys/linea-monorepo/prover/backend/blobsubmission"
+	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/circuits/internal"
 	circuittesting "github.com/consensys/linea-monorepo/prover/circuits/internal/test_utils"
 	pi_interconnection "github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection"
@@ -24,6 +25,7 @@ import (
 	public_input "github.com/consensys/linea-monorepo/prover/public-input"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TODO test with random values instead of small ones
@@ -67,6 +69,101 @@ func TestSingleBlockBlobE2E(t *testing.T) {
 	}
 }
 
+func TestSingleBlockBlobProverMetadata(t *testing.T) {
+	req := pitesting.AssignSingleBlockBlob(t)
+	cfg := config.Config{
+		Version: "v1.0.0",
+		PublicInputInterconnection: config.PublicInput{
+			MaxNbDecompression: len(req.Decompressions),
+			MaxNbExecution:     len(req.Executions),
+			ExecutionMaxNbMsg:  1,
+			L2MsgMerkleDepth:   5,
+			L2MsgMaxNbMerkle:   1,
+			ProverMetadata:     true,
+		},
+	}
+	_, err := pi_interconnection.Compile(cfg.PublicInputInterconnection, dummy.Compile)
+	assert.Error(t, err, "the digest must be derived from the config")
+
+	compiled, err := pi_interconnection.Compile(pi_interconnection.PublicInputConfig(&cfg), dummy.Compile)
+	assert.NoError(t, err)
+
+	a, err := compiled.Assign(req)
+	assert.NoError(t, err)
+	assert.True(t, a.ExecutionFPIQ[0].WithProverMetadata)
+
+	cs, err := frontend.Compile(ecc.BLS12_377.ScalarField(), scs.NewBuilder, compiled.Circuit, frontend.WithCapacity(3_000_000))
+	assert.NoError(t, err)
+
+	w, err := frontend.NewWitness(&a, ecc.BLS12_377.ScalarField())
+	assert.NoError(t, err)
+	assert.NoError(t, cs.IsSolved(w))
+
+	// the execution proofs of another prover version are rejected
+	otherCfg := cfg
+	otherCfg.Version = "v1.0.1"
+	other, err := pi_interconnection.Compile(pi_interconnection.PublicInputConfig(&otherCfg), dummy.Compile)
+	assert.NoError(t, err)
+
+	a, err = other.Assign(req)
+	assert.NoError(t, err)
+
+	w, err = frontend.NewWitness(&a, ecc.BLS12_377.ScalarField())
+	assert.NoError(t, err)
+	assert.Error(t, cs.IsSolved(w))
+}
+
+func TestSingleBlockBlobTracesLimits(t *testing.T) {
+	req := pitesting.AssignSingleBlockBlob(t)
+	cfg := config.Config{
+		PublicInputInterconnection: config.PublicInput{
+			MaxNbDecompression:  len(req.Decompressions),
+			MaxNbExecution:      len(req.Executions),
+			ExecutionMaxNbMsg:   1,
+			L2MsgMerkleDepth:    5,
+			L2MsgMaxNbMerkle:    1,
+			TracesLimitsBinding: true,
+			MockKeccakWizard:    true,
+		},
+		TracesLimits:      config.TracesLimits{Add: 1 << 10},
+		TracesLimitsLarge: config.TracesLimits{Add: 1 << 11},
+	}
+	_, err := pi_interconnection.Compile(cfg.PublicInputInterconnection, dummy.Compile)
+	assert.Error(t, err, "the digests must be derived from the config")
+
+	compiled, err := pi_interconnection.Compile(pi_interconnection.PublicInputConfig(&cfg), dummy.Compile)
+	require.NoError(t, err)
+
+	// both the normal and the large limits are accepted
+	for _, limits := range []*config.TracesLimits{&cfg.TracesLimits, &cfg.TracesLimitsLarge} {
+		req.Executions[0].TracesLimitsDigest = execution.TracesLimitsDigest(limits)
+		a, err := compiled.Assign(req)
+		require.NoError(t, err)
+		assert.True(t, a.ExecutionFPIQ[0].WithTracesLimits)
+		assert.NoError(t, test.IsSolved(compiled.Circuit, &a, ecc.BLS12_377.ScalarField()))
+	}
+
+	// the executions proven with other limits are rejected
+	unknown := config.TracesLimits{Add: 1 << 12}
+	req.Executions[0].TracesLimitsDigest = execution.TracesLimitsDigest(&unknown)
+	_, err = compiled.Assign(req)
+	assert.Error(t, err)
+
+	req.Executions[0].TracesLimitsDigest = nil
+	_, err = compiled.Assign(req)
+	assert.Error(t, err)
+
+	otherCfg := cfg
+	otherCfg.TracesLimits, otherCfg.TracesLimitsLarge = unknown, unknown
+	other, err := pi_interconnection.Compile(pi_interconnection.PublicInputConfig(&otherCfg), dummy.Compile)
+	require.NoError(t, err)
+
+	req.Executions[0].TracesLimitsDigest = execution.TracesLimitsDigest(&unknown)
+	a, err := other.Assign(req)
+	require.NoError(t, err)
+	assert.Error(t, test.IsSolved(compiled.Circuit, &a, ecc.BLS12_377.ScalarField()))
+}
+
 // some of the execution data are faked
 func TestTinyTwoBatchBlob(t *testing.T) {
 
@@ -12,7 +12,6 @@ import (
 
 	public_input "github.com/consensys/linea-monorepo/prover/public-input"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,7 +29,7 @@ func AssignSingleBlockBlob(t require.TestingT) pi_interconnection.Request {
 	}
 
 	blobResp, err := blobsubmission.CraftResponse(&blobReq)
-	assert.NoError(t, err)
+	require.NoError(t, err)
 
 	execReq := public_input.Execution{
 		L2MsgHashes:            [][32]byte{internal.Uint64To32Bytes(4)},
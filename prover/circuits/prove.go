@@ -6,9 +6,11 @@ import (
 
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/test"
+	"github.com/consensys/linea-monorepo/prover/utils/watchdog"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/sirupsen/logrus"
 
@@ -16,13 +18,35 @@ import (
 )
 
 // Generates a PlonkProof and sanity-checks it against the verifying key. Can
-// take a list of options which can of either backend.ProverOption of backend.
-// VerifierOption.
+// take a list of options which can of either backend.ProverOption, backend.
+// VerifierOption, solver.Option or *[HintProfiler].
 func ProveCheck(setup *Setup, assignment frontend.Circuit, opts ...any) (plonk.Proof, error) {
 
+	logrus.Infof("Creating the witness")
+	witness, err := frontend.NewWitness(assignment, setup.Circuit.Field())
+	if err != nil {
+		return nil, fmt.Errorf("while generating the gnark witness: %w", err)
+	}
+
+	return proveCheck(setup, witness, assignment, opts...)
+}
+
+// ProveCheckWitness is as [ProveCheck] but takes the full witness instead of
+// the assignment, for the circuits whose witness is built without going
+// through an assignment. As a consequence, the errors of the solver are not
+// detailed.
+func ProveCheckWitness(setup *Setup, witness witness.Witness, opts ...any) (plonk.Proof, error) {
+	return proveCheck(setup, witness, nil, opts...)
+}
+
+// proveCheck runs the prover on the witness. The assignment, if given, is
+// only used to detail the errors of the solver.
+func proveCheck(setup *Setup, witness witness.Witness, assignment frontend.Circuit, opts ...any) (plonk.Proof, error) {
+
 	proverOpts := []backend.ProverOption{}
 	verifierOpts := []backend.VerifierOption{}
 	solverOpts := []solver.Option{}
+	profilers := []*HintProfiler{}
 
 	// @alex: we cannot incrementally pass the solver options to the prover
 	// options (they are overriden at every call). That's why we need to collect
@@ -36,23 +60,32 @@ func ProveCheck(setup *Setup, assignment frontend.Circuit, opts ...any) (plonk.P
 			proverOpts = append(proverOpts, o)
 		case backend.VerifierOption:
 			verifierOpts = append(verifierOpts, o)
+		case *HintProfiler:
+			profilers = append(profilers, o)
 		default:
 			return nil, fmt.Errorf("unknown option type to prove-check: %++v", o)
 		}
 	}
 
-	proverOpts = append(proverOpts, backend.WithSolverOptions(solverOpts...))
-
-	logrus.Infof("Creating the witness")
-	witness, err := frontend.NewWitness(assignment, setup.Circuit.Field())
-	if err != nil {
-		return nil, fmt.Errorf("while generating the gnark witness: %w", err)
+	// The profilers instrument the hints registered by the other options, so
+	// they must come last.
+	for _, p := range profilers {
+		solverOpts = append(solverOpts, p.SolverOption())
 	}
 
+	proverOpts = append(proverOpts, backend.WithSolverOptions(solverOpts...))
+
 	logrus.Infof("Generating the proof")
-	var proof plonk.Proof
 
-	proof, err = plonk.Prove(setup.Circuit, setup.ProvingKey, witness, proverOpts...)
+	watchdog.BeginLongPhase("gnark proving")
+	proof, err := plonk.Prove(setup.Circuit, setup.ProvingKey, witness, proverOpts...)
+	watchdog.Heartbeat("gnark proving done")
+	for _, p := range profilers {
+		p.Report()
+	}
+	if err != nil && assignment == nil {
+		return nil, fmt.Errorf("while running the plonk prover: %w", err)
+	}
 	if err != nil {
 		// The error returned by the Plonk prover is usually not helpful at
 		// all. So, in order to get more details, we run the "test" Solver.
@@ -1,5 +1,17 @@
 package circuits
 
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/ids"
+)
+
 // CircuitID is a type to represent the different circuits.
 // It is used to identify the circuit to be used in the prover.
 type CircuitID string
@@ -17,6 +29,20 @@ const (
 	PublicInputInterconnectionCircuitID CircuitID = "public-input-interconnection"
 )
 
+// circuitIDFormat is the format of the circuit IDs: lowercase kebab-case,
+// as they are used in the paths of the setups.
+var circuitIDFormat = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// NewCircuitID returns the circuit ID or an error if it is not in lowercase
+// kebab-case, e.g. "execution-large". It does not check that the circuit is
+// registered, see [Lookup].
+func NewCircuitID(s string) (CircuitID, error) {
+	if !circuitIDFormat.MatchString(s) {
+		return "", fmt.Errorf("invalid circuit ID %q: expected lowercase kebab-case", s)
+	}
+	return CircuitID(s), nil
+}
+
 // MockCircuitID is a type to represent the different mock circuits.
 type MockCircuitID int
 
@@ -25,3 +51,108 @@ const (
 	MockCircuitIDDecompression MockCircuitID = 6789
 	MockCircuitIDEmulation     MockCircuitID = 1
 )
+
+// Dependency is an input, other than the configuration, that the builder of a
+// circuit needs.
+type Dependency string
+
+const (
+	// DependencyDictionary is the dictionaries of the blob compressor. They
+	// are passed to the factory in [SetupInputs.Dicts] and saved along the
+	// setup.
+	DependencyDictionary Dependency = "dictionary"
+	// DependencyTracesLimits is the traces limits of the configuration
+	DependencyTracesLimits Dependency = "traces-limits"
+	// DependencyInnerVerifyingKeys is the verifying keys of the circuits whose
+	// proofs are verified by the circuit. They are only known once these
+	// circuits are set up.
+	DependencyInnerVerifyingKeys Dependency = "inner-verifying-keys"
+)
+
+// SetupInputs holds the dependencies passed to a [BuilderFactory]
+type SetupInputs struct {
+	// Dicts are the dictionaries accepted by the circuit. The active one is
+	// the one whose checksum is recorded in the header of the blob.
+	Dicts [][]byte
+}
+
+// BuilderFactory returns the builder of a circuit along with the extra flags
+// recorded in its setup manifest.
+type BuilderFactory func(cfg *config.Config, inputs SetupInputs) (b Builder, extraFlags map[string]any, err error)
+
+// CircuitInfo describes a circuit of the registry
+type CircuitInfo struct {
+	ID CircuitID
+	// Curve is the curve of the proofs of the circuit
+	Curve ecc.ID
+	// Dependencies lists the inputs of the builder, besides the config
+	Dependencies []Dependency
+	// Dummy circuits are only used for testing. Their setup is run on the fly
+	// when they are allowed as inputs of the aggregation.
+	Dummy bool
+	// DefaultSetup tells whether the setup command prepares the circuit when
+	// the list of circuits is not specified.
+	DefaultSetup bool
+	// NewBuilder returns the builder of the circuit. It is nil for the
+	// circuits whose builder needs the verifying keys of other circuits, that
+	// the setup command handles separately.
+	NewBuilder BuilderFactory
+}
+
+// Needs returns true if the circuit has the dependency
+func (c *CircuitInfo) Needs(d Dependency) bool {
+	return slices.Contains(c.Dependencies, d)
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[CircuitID]CircuitInfo{}
+)
+
+// Register adds a circuit to the registry. It is meant to be called from the
+// init function of the package of the circuit, and panics if the ID is
+// invalid or if the circuit is already registered. In the debug builds, the
+// panic gives the locations of both registrations.
+func Register(info CircuitInfo) {
+
+	if _, err := NewCircuitID(string(info.ID)); err != nil {
+		panic(err)
+	}
+
+	if ids.Debug {
+		ids.Global.MustClaim("circuit", string(info.ID))
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := registry[info.ID]; ok {
+		panic(fmt.Sprintf("circuit %v is registered twice", info.ID))
+	}
+	registry[info.ID] = info
+}
+
+// Lookup returns the registered circuit with the given ID
+func Lookup(id CircuitID) (CircuitInfo, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	info, ok := registry[id]
+	return info, ok
+}
+
+// Registered returns the registered circuits sorted by ID. Only the circuits
+// whose package is linked in the binary are registered.
+func Registered() []CircuitInfo {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	res := make([]CircuitInfo, 0, len(registry))
+	for _, info := range registry {
+		res = append(res, info)
+	}
+	slices.SortFunc(res, func(a, b CircuitInfo) int {
+		return strings.Compare(string(a.ID), string(b.ID))
+	})
+	return res
+}
@@ -0,0 +1,54 @@
+package circuits
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+
+	Register(CircuitInfo{ID: "test-registry-b", Curve: ecc.BN254})
+	Register(CircuitInfo{
+		ID:           "test-registry-a",
+		Curve:        ecc.BLS12_377,
+		Dependencies: []Dependency{DependencyDictionary},
+	})
+
+	info, ok := Lookup("test-registry-a")
+	require.True(t, ok)
+	assert.Equal(t, ecc.BLS12_377, info.Curve)
+	assert.True(t, info.Needs(DependencyDictionary))
+	assert.False(t, info.Needs(DependencyTracesLimits))
+
+	_, ok = Lookup("test-registry-c")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { Register(CircuitInfo{ID: "test-registry-b"}) }, "registered twice")
+
+	var ids []CircuitID
+	for _, info := range Registered() {
+		ids = append(ids, info.ID)
+	}
+	assert.IsIncreasing(t, ids)
+	assert.Contains(t, ids, CircuitID("test-registry-a"))
+	assert.Contains(t, ids, CircuitID("test-registry-b"))
+}
+
+func TestNewCircuitID(t *testing.T) {
+
+	for _, s := range []string{"execution", "execution-large", "aggregation-10", "blob-decompression-v1"} {
+		id, err := NewCircuitID(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, CircuitID(s), id)
+	}
+
+	for _, s := range []string{"", "Execution", "execution_large", "execution-", "-execution", "execution--large", "execution large"} {
+		_, err := NewCircuitID(s)
+		assert.Error(t, err, "%q", s)
+	}
+
+	assert.Panics(t, func() { Register(CircuitInfo{ID: "Test_Registry"}) }, "invalid ID")
+}
@@ -0,0 +1,165 @@
+package selftest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Compatibility is an entry of the compatibility matrix returned by [Matrix].
+// It tells whether the assets on disk allow proving with one of the circuits
+// the config refers to.
+type Compatibility struct {
+	// Job is the kind of job the circuit is used for: "execution",
+	// "blob-decompression" or "aggregation".
+	Job string
+	// CircuitID is the circuit whose setup is checked.
+	CircuitID circuits.CircuitID
+	// Curve is the curve over which the circuit is defined.
+	Curve ecc.ID
+	// Enabled indicates whether the config makes the prover run the circuit,
+	// i.e. the job is enabled in the controller and is run in full mode.
+	Enabled bool
+	// Err is nil if the setup is usable and describes the first failed
+	// check otherwise.
+	Err error
+}
+
+// Ready returns true if the setup of the circuit is usable.
+func (c *Compatibility) Ready() bool {
+	return c.Err == nil
+}
+
+// Matrix checks the setups of all the circuits that the config may use in
+// full mode, whether the corresponding jobs are enabled or not, and returns
+// one entry per circuit. On top of the checks of [Run], it checks that the
+// setups of the execution circuits were generated for the traces limits and
+// the SIS parameters of the config, and that the dictionary of the blob
+// decompression circuits is present. No proof is generated.
+//
+// The returned error is only non-nil if the SRS store cannot be opened.
+func Matrix(cfg *config.Config) ([]Compatibility, error) {
+
+	srsDir := cfg.PathForSRS()
+	srsStore, err := circuits.NewSRSStore(srsDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the SRS store at %v: %w", srsDir, err)
+	}
+
+	var (
+		ctrl = &cfg.Controller
+		res  = []Compatibility{}
+		add  = func(job string, id circuits.CircuitID, curve ecc.ID, enabled bool, extraCheck func() error) {
+			req := requirement{CircuitID: id, Curve: curve}
+			err := checkSetup(cfg, srsStore, req)
+			if err == nil && extraCheck != nil {
+				err = extraCheck()
+			}
+			res = append(res, Compatibility{Job: job, CircuitID: id, Curve: curve, Enabled: enabled, Err: err})
+		}
+	)
+
+	var (
+		execEnabled = ctrl.EnableExecution && cfg.Execution.ProverMode == config.ProverModeFull
+		blobEnabled = ctrl.EnableBlobDecompression && cfg.BlobDecompression.ProverMode == config.ProverModeFull
+		aggEnabled  = ctrl.EnableAggregation && cfg.Aggregation.ProverMode == config.ProverModeFull
+	)
+
+	add("execution", circuits.ExecutionCircuitID, ecc.BLS12_377, execEnabled, func() error {
+		return checkSetupChecksum(cfg, circuits.ExecutionCircuitID, &cfg.TracesLimits)
+	})
+	add("execution", circuits.ExecutionLargeCircuitID, ecc.BLS12_377, execEnabled && cfg.Execution.CanRunFullLarge, func() error {
+		return checkSetupChecksum(cfg, circuits.ExecutionLargeCircuitID, &cfg.TracesLimitsLarge)
+	})
+
+	for _, id := range []circuits.CircuitID{circuits.BlobDecompressionV0CircuitID, circuits.BlobDecompressionV1CircuitID} {
+		add("blob-decompression", id, ecc.BLS12_377, blobEnabled, func() error {
+			return checkDictionary(cfg, id)
+		})
+	}
+
+	add("aggregation", circuits.PublicInputInterconnectionCircuitID, ecc.BLS12_377, aggEnabled, nil)
+	for _, n := range cfg.Aggregation.NumProofs {
+		add("aggregation", circuits.CircuitID(fmt.Sprintf("%s-%d", circuits.AggregationCircuitID, n)), ecc.BW6_761, aggEnabled, nil)
+	}
+	add("aggregation", circuits.EmulationCircuitID, ecc.BN254, aggEnabled, nil)
+	for _, n := range cfg.Aggregation.EmulationNumProofs {
+		add("aggregation", circuits.CircuitID(fmt.Sprintf("%s-%d", circuits.EmulationCircuitID, n)), ecc.BN254, aggEnabled, nil)
+	}
+
+	return res, nil
+}
+
+// checkSetupChecksum checks that the setup of an execution circuit was
+// generated for the limits and the SIS parameters of the config. The prover
+// refuses to use it otherwise.
+func checkSetupChecksum(cfg *config.Config, id circuits.CircuitID, limits *config.TracesLimits) error {
+
+	manifest, err := circuits.ReadSetupManifest(filepath.Join(cfg.PathForSetup(string(id)), config.ManifestFileName))
+	if err != nil {
+		return fmt.Errorf("could not read the manifest: %w", err)
+	}
+
+	checksum, err := manifest.GetString("cfg_checksum")
+	if err != nil {
+		return fmt.Errorf("the manifest has no config checksum: %w", err)
+	}
+
+	if expected := cfg.Execution.SetupChecksum(limits); checksum != expected {
+		return fmt.Errorf("config mismatch, the setup was generated for the config checksum %v but the config has %v", checksum, expected)
+	}
+
+	// the older setups do not record the canonical checksum
+	if canonical, err := manifest.GetString("cfg_checksum_canonical"); err == nil {
+		if expected := cfg.Execution.VersionedSetupChecksum(limits, config.ChecksumCanonical); canonical != expected {
+			return fmt.Errorf("config mismatch, the setup was generated for the canonical config checksum %v but the config has %v", canonical, expected)
+		}
+	}
+
+	return nil
+}
+
+// checkDictionary checks that the setup of a blob decompression circuit comes
+// with its compression dictionaries: all the dictionaries listed in the
+// manifest or, for the setups predating the support of several dictionaries,
+// the default one.
+func checkDictionary(cfg *config.Config, id circuits.CircuitID) error {
+
+	setupDir := cfg.PathForSetup(string(id))
+
+	manifest, err := circuits.ReadSetupManifest(filepath.Join(setupDir, config.ManifestFileName))
+	if err != nil {
+		return fmt.Errorf("could not read the manifest: %w", err)
+	}
+
+	checksums, err := manifest.GetStringArray("dictChecksums")
+	if err != nil {
+		if _, err := os.Stat(filepath.Join(setupDir, config.DictionaryFileName)); err != nil {
+			return fmt.Errorf("missing dictionary file: %w", err)
+		}
+		return nil
+	}
+
+	version, err := blobdecompression.Version(id)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range checksums {
+		checksum, err := utils.HexDecodeString(c)
+		if err != nil {
+			return fmt.Errorf("the manifest has an invalid dictionary checksum %q: %w", c, err)
+		}
+		if _, err := blobdecompression.ReadDictionary(setupDir, version, checksum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
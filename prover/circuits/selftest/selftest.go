@@ -0,0 +1,252 @@
+// Package selftest checks, before any job is accepted, that the prover
+// assets are usable with the current configuration. It is meant to surface
+// a missing SRS, a corrupted key or a setup generated for the wrong curve at
+// boot time instead of hours later, at the end of the first proving job.
+package selftest
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	frbw6 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/crypto/vortex"
+	"github.com/sirupsen/logrus"
+)
+
+// requirement is a setup that the prover needs to run the jobs it is
+// configured for, along with the curve it must be defined over.
+type requirement struct {
+	CircuitID circuits.CircuitID
+	Curve     ecc.ID
+}
+
+// Run performs the self-test of the prover for the configuration. It returns
+// a nil error if all the checks pass, and otherwise an error describing the
+// first failed check. The checks are:
+//
+//   - the SRS directory can be listed;
+//   - for every setup needed by the enabled jobs, the manifest can be read,
+//     the verifying key matches the checksum of the manifest, the setup is
+//     defined over the expected curve and the SRS store holds a canonical
+//     SRS large enough for it;
+//   - for every curve in use, a dummy circuit can be setup with the SRS of
+//     the store and a proof can be generated and verified;
+//   - if the full execution prover is enabled, the Vortex commitments are
+//     the same on this machine as on the reference one, see
+//     [vortex.SelfTest].
+//
+// The circuits themselves are not loaded as they can weigh several GB: the
+// check of the SRS size is based on the number of constraints recorded in the
+// manifest.
+func Run(ctx context.Context, cfg *config.Config) error {
+
+	srsDir := cfg.PathForSRS()
+	srsStore, err := circuits.NewSRSStore(srsDir)
+	if err != nil {
+		return fmt.Errorf("self-test: could not open the SRS store at %v: %w", srsDir, err)
+	}
+
+	reqs, curves := requirements(cfg)
+
+	for _, req := range reqs {
+		logrus.Infof("self-test: checking the setup of %v", req.CircuitID)
+		if err := checkSetup(cfg, srsStore, req); err != nil {
+			return fmt.Errorf("self-test: setup %v: %w", req.CircuitID, err)
+		}
+	}
+
+	for _, curve := range curves {
+		logrus.Infof("self-test: proving a dummy circuit over %v", curve)
+		if err := proveDummy(ctx, srsStore, curve); err != nil {
+			return fmt.Errorf("self-test: dummy proof over %v: %w", curve, err)
+		}
+	}
+
+	if cfg.Controller.EnableExecution && cfg.Execution.ProverMode == config.ProverModeFull {
+		logrus.Infof("self-test: checking the reproducibility of the Vortex commitments")
+		if err := vortex.SelfTest(); err != nil {
+			return fmt.Errorf("self-test: %w", err)
+		}
+	}
+
+	logrus.Infof("self-test: passed, checked %v setups and %v curves", len(reqs), len(curves))
+	return nil
+}
+
+// requirements returns the setups and the curves used by the jobs enabled in
+// the config. The curves are returned without duplicates, in order of
+// appearance.
+func requirements(cfg *config.Config) (reqs []requirement, curves []ecc.ID) {
+
+	var (
+		ctrl     = &cfg.Controller
+		seen     = map[ecc.ID]struct{}{}
+		addCurve = func(c ecc.ID) {
+			if _, ok := seen[c]; !ok {
+				seen[c] = struct{}{}
+				curves = append(curves, c)
+			}
+		}
+		addSetup = func(id circuits.CircuitID, c ecc.ID) {
+			reqs = append(reqs, requirement{CircuitID: id, Curve: c})
+			addCurve(c)
+		}
+	)
+
+	if ctrl.EnableExecution {
+		switch cfg.Execution.ProverMode {
+		case config.ProverModeFull:
+			addSetup(circuits.ExecutionCircuitID, ecc.BLS12_377)
+			if cfg.Execution.CanRunFullLarge {
+				addSetup(circuits.ExecutionLargeCircuitID, ecc.BLS12_377)
+			}
+		case config.ProverModeDev, config.ProverModePartial:
+			addCurve(ecc.BLS12_377)
+		}
+	}
+
+	if ctrl.EnableBlobDecompression {
+		switch cfg.BlobDecompression.ProverMode {
+		case config.ProverModeFull:
+			addSetup(circuits.BlobDecompressionV1CircuitID, ecc.BLS12_377)
+			// The v0 circuit is only needed for the blobs submitted before
+			// the v1 upgrade, it is checked only if it is deployed.
+			if _, err := os.Stat(cfg.PathForSetup(string(circuits.BlobDecompressionV0CircuitID))); err == nil {
+				addSetup(circuits.BlobDecompressionV0CircuitID, ecc.BLS12_377)
+			}
+		case config.ProverModeDev:
+			addCurve(ecc.BLS12_377)
+		}
+	}
+
+	if ctrl.EnableAggregation {
+		switch cfg.Aggregation.ProverMode {
+		case config.ProverModeFull:
+			addSetup(circuits.PublicInputInterconnectionCircuitID, ecc.BLS12_377)
+			for _, n := range cfg.Aggregation.NumProofs {
+				addSetup(circuits.CircuitID(fmt.Sprintf("%s-%d", circuits.AggregationCircuitID, n)), ecc.BW6_761)
+			}
+			addSetup(circuits.EmulationCircuitID, ecc.BN254)
+			for _, n := range cfg.Aggregation.EmulationNumProofs {
+				addSetup(circuits.CircuitID(fmt.Sprintf("%s-%d", circuits.EmulationCircuitID, n)), ecc.BN254)
+			}
+		case config.ProverModeDev:
+			addCurve(ecc.BN254)
+		}
+	}
+
+	return reqs, curves
+}
+
+// checkSetup checks the assets of a setup without loading its circuit
+func checkSetup(cfg *config.Config, srsStore *circuits.SRSStore, req requirement) error {
+
+	rootDir := cfg.PathForSetup(string(req.CircuitID))
+
+	manifest, err := circuits.ReadSetupManifest(filepath.Join(rootDir, config.ManifestFileName))
+	if err != nil {
+		return fmt.Errorf("could not read the manifest: %w", err)
+	}
+
+	curve, err := ecc.IDFromString(manifest.CurveID)
+	if err != nil {
+		return fmt.Errorf("the manifest has an invalid curve %q: %w", manifest.CurveID, err)
+	}
+
+	if curve != req.Curve {
+		return fmt.Errorf("wrong curve, the setup is for %v but the circuit is expected to be over %v", curve, req.Curve)
+	}
+
+	if _, err := os.Stat(filepath.Join(rootDir, config.CircuitFileName)); err != nil {
+		return fmt.Errorf("missing circuit file: %w", err)
+	}
+
+	vk := plonk.NewVerifyingKey(curve)
+	if err := circuits.ReadVerifyingKey(filepath.Join(rootDir, config.VerifyingKeyFileName), vk); err != nil {
+		return fmt.Errorf("could not read the verifying key, it may be corrupted: %w", err)
+	}
+
+	if digest, err := circuits.VerifyingKeyChecksum(vk); err != nil {
+		return fmt.Errorf("could not hash the verifying key: %w", err)
+	} else if digest != manifest.Checksums.VerifyingKey {
+		return fmt.Errorf("corrupted verifying key, its checksum is %v but the manifest expects %v", digest, manifest.Checksums.VerifyingKey)
+	}
+
+	// The actual size also accounts for the public inputs of the circuit so
+	// this is only a lower bound. It catches the deployment of an SRS meant
+	// for smaller circuits.
+	var (
+		minSize   = int(ecc.NextPowerOfTwo(uint64(manifest.NbConstraints))) + 3 // #nosec G115 -- the number of constraints is positive
+		available = srsStore.MaxCanonicalSize(curve)
+	)
+
+	if available < minSize {
+		return fmt.Errorf("missing SRS: the circuit has %v constraints and needs a canonical SRS over %v of size at least %v but the largest available is %v", manifest.NbConstraints, curve, minSize, available)
+	}
+
+	return nil
+}
+
+// proveDummy sets up the dummy circuit with the SRS of the store, then proves
+// and verifies a random assignment.
+func proveDummy(ctx context.Context, srsStore *circuits.SRSStore, curve ecc.ID) error {
+
+	ccs, err := dummy.MakeCS(circuits.MockCircuitIDExecution, curve.ScalarField())
+	if err != nil {
+		return err
+	}
+
+	setup, err := circuits.MakeSetup(ctx, "self-test", ccs, srsStore, nil)
+	if err != nil {
+		return err
+	}
+
+	x, err := randomElement(curve)
+	if err != nil {
+		return err
+	}
+
+	assignment := dummy.Assign(circuits.MockCircuitIDExecution, x)
+	if _, err := circuits.ProveCheck(&setup, assignment); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// randomElement returns a random element of the scalar field of the curve,
+// typed as expected by [dummy.Assign].
+func randomElement(curve ecc.ID) (any, error) {
+
+	v, err := rand.Int(rand.Reader, curve.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("could not sample a random field element: %w", err)
+	}
+
+	switch curve {
+	case ecc.BLS12_377:
+		var x fr377.Element
+		x.SetBigInt(v)
+		return x, nil
+	case ecc.BN254:
+		var x fr254.Element
+		x.SetBigInt(v)
+		return x, nil
+	case ecc.BW6_761:
+		var x frbw6.Element
+		x.SetBigInt(v)
+		return x, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %v", curve)
+	}
+}
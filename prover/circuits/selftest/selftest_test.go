@@ -0,0 +1,239 @@
+package selftest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns a config whose assets are in a temporary directory
+// holding the listed SRS files of the repository.
+func testConfig(t *testing.T, srsFiles ...string) *config.Config {
+
+	cfg := &config.Config{
+		AssetsDir:   t.TempDir(),
+		Version:     "0.0.0",
+		Environment: "integration-development",
+	}
+
+	require.NoError(t, os.MkdirAll(cfg.PathForSRS(), 0755))
+	for _, f := range srsFiles {
+		b, err := os.ReadFile(filepath.Join("../../prover-assets/kzgsrs", f))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.PathForSRS(), f), b, 0600))
+	}
+
+	return cfg
+}
+
+const (
+	srsBn254    = "kzg_srs_canonical_259_bn254_aztec.memdump"
+	srsBls12377 = "kzg_srs_canonical_259_bls12377_aleo.memdump"
+)
+
+func TestRequirements(t *testing.T) {
+
+	cfg := testConfig(t)
+	cfg.Controller.EnableExecution = true
+	cfg.Controller.EnableBlobDecompression = true
+	cfg.Controller.EnableAggregation = true
+	cfg.Execution.ProverMode = config.ProverModeFull
+	cfg.BlobDecompression.ProverMode = config.ProverModeFull
+	cfg.Aggregation.ProverMode = config.ProverModeFull
+	cfg.Aggregation.NumProofs = []int{10, 20}
+	cfg.Aggregation.EmulationNumProofs = []int{2}
+
+	reqs, curves := requirements(cfg)
+
+	assert.Equal(t, []requirement{
+		{CircuitID: circuits.ExecutionCircuitID, Curve: ecc.BLS12_377},
+		{CircuitID: circuits.BlobDecompressionV1CircuitID, Curve: ecc.BLS12_377},
+		{CircuitID: circuits.PublicInputInterconnectionCircuitID, Curve: ecc.BLS12_377},
+		{CircuitID: "aggregation-10", Curve: ecc.BW6_761},
+		{CircuitID: "aggregation-20", Curve: ecc.BW6_761},
+		{CircuitID: circuits.EmulationCircuitID, Curve: ecc.BN254},
+		{CircuitID: "emulation-2", Curve: ecc.BN254},
+	}, reqs)
+	assert.Equal(t, []ecc.ID{ecc.BLS12_377, ecc.BW6_761, ecc.BN254}, curves)
+
+	cfg.Controller.EnableAggregation = false
+	cfg.Execution.ProverMode = config.ProverModeDev
+	cfg.BlobDecompression.ProverMode = config.ProverModeDev
+
+	reqs, curves = requirements(cfg)
+	assert.Empty(t, reqs)
+	assert.Equal(t, []ecc.ID{ecc.BLS12_377}, curves)
+}
+
+func TestRunDevMode(t *testing.T) {
+
+	cfg := testConfig(t, srsBn254)
+	cfg.Controller.EnableAggregation = true
+	cfg.Aggregation.ProverMode = config.ProverModeDev
+
+	require.NoError(t, Run(context.Background(), cfg))
+
+	// The execution needs an SRS over BLS12-377 that is not in the store
+	cfg.Controller.EnableExecution = true
+	cfg.Execution.ProverMode = config.ProverModeDev
+
+	err := Run(context.Background(), cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not find canonical SRS for curve bls12_377")
+
+	// A truncated SRS file is reported as well
+	cfg = testConfig(t, srsBls12377)
+	cfg.Controller.EnableExecution = true
+	cfg.Execution.ProverMode = config.ProverModeDev
+	srsPath := filepath.Join(cfg.PathForSRS(), srsBls12377)
+	b, err := os.ReadFile(srsPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(srsPath, b[:len(b)/2], 0600))
+
+	assert.Error(t, Run(context.Background(), cfg))
+}
+
+func TestCheckSetup(t *testing.T) {
+
+	cfg := testConfig(t, srsBn254)
+	req := requirement{CircuitID: circuits.EmulationCircuitID, Curve: ecc.BN254}
+
+	// There is no setup yet
+	err := checkSetup(cfg, nil, req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read the manifest")
+
+	srsStore, err := circuits.NewSRSStore(cfg.PathForSRS())
+	require.NoError(t, err)
+	setup, err := dummy.MakeUnsafeSetup(srsStore, circuits.MockCircuitIDEmulation, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+
+	rootDir := cfg.PathForSetup(string(req.CircuitID))
+	require.NoError(t, setup.WriteTo(rootDir))
+	require.NoError(t, checkSetup(cfg, srsStore, req))
+
+	t.Run("wrong-curve", func(t *testing.T) {
+		err := checkSetup(cfg, srsStore, requirement{CircuitID: req.CircuitID, Curve: ecc.BW6_761})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "wrong curve")
+	})
+
+	t.Run("missing-srs-size", func(t *testing.T) {
+		large := setup
+		large.Manifest.NbConstraints = 1 << 20
+		require.NoError(t, large.Manifest.WriteTo(filepath.Join(rootDir, config.ManifestFileName)))
+		defer func() {
+			require.NoError(t, setup.Manifest.WriteTo(filepath.Join(rootDir, config.ManifestFileName)))
+		}()
+
+		err := checkSetup(cfg, srsStore, req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing SRS")
+	})
+
+	t.Run("corrupted-key", func(t *testing.T) {
+		vkPath := filepath.Join(rootDir, config.VerifyingKeyFileName)
+		b, err := os.ReadFile(vkPath)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, os.WriteFile(vkPath, b, 0600)) }()
+
+		corrupted := append([]byte{}, b...)
+		corrupted[len(corrupted)-1] ^= 1
+		require.NoError(t, os.WriteFile(vkPath, corrupted, 0600))
+
+		assert.Error(t, checkSetup(cfg, srsStore, req))
+	})
+
+	require.NoError(t, checkSetup(cfg, srsStore, req))
+}
+
+func TestMatrix(t *testing.T) {
+
+	cfg := testConfig(t, srsBn254)
+	cfg.Controller.EnableAggregation = true
+	cfg.Aggregation.ProverMode = config.ProverModeFull
+	cfg.Aggregation.NumProofs = []int{10}
+
+	srsStore, err := circuits.NewSRSStore(cfg.PathForSRS())
+	require.NoError(t, err)
+	setup, err := dummy.MakeUnsafeSetup(srsStore, circuits.MockCircuitIDEmulation, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	require.NoError(t, setup.WriteTo(cfg.PathForSetup(string(circuits.EmulationCircuitID))))
+
+	matrix, err := Matrix(cfg)
+	require.NoError(t, err)
+
+	var circuitIDs []circuits.CircuitID
+	for _, c := range matrix {
+		circuitIDs = append(circuitIDs, c.CircuitID)
+
+		switch c.Job {
+		case "aggregation":
+			assert.True(t, c.Enabled, c.CircuitID)
+		default:
+			assert.False(t, c.Enabled, c.CircuitID)
+		}
+
+		if c.CircuitID == circuits.EmulationCircuitID {
+			assert.True(t, c.Ready(), "%v", c.Err)
+		} else {
+			assert.False(t, c.Ready(), c.CircuitID)
+		}
+	}
+
+	assert.Equal(t, []circuits.CircuitID{
+		circuits.ExecutionCircuitID,
+		circuits.ExecutionLargeCircuitID,
+		circuits.BlobDecompressionV0CircuitID,
+		circuits.BlobDecompressionV1CircuitID,
+		circuits.PublicInputInterconnectionCircuitID,
+		"aggregation-10",
+		circuits.EmulationCircuitID,
+	}, circuitIDs)
+}
+
+func TestCheckSetupChecksum(t *testing.T) {
+
+	cfg := testConfig(t)
+	id := circuits.ExecutionCircuitID
+	manifestPath := filepath.Join(cfg.PathForSetup(string(id)), config.ManifestFileName)
+
+	manifest := circuits.NewSetupManifest(string(id), 1, ecc.BLS12_377, map[string]any{
+		"cfg_checksum": cfg.Execution.SetupChecksum(&cfg.TracesLimits),
+	})
+	require.NoError(t, manifest.WriteTo(manifestPath))
+	require.NoError(t, checkSetupChecksum(cfg, id, &cfg.TracesLimits))
+
+	cfg.Execution.SIS = config.SIS{LogTwoBound: 16, LogTwoDegree: 6}
+	err := checkSetupChecksum(cfg, id, &cfg.TracesLimits)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config mismatch")
+}
+
+func TestCheckSetupChecksumCanonical(t *testing.T) {
+
+	cfg := testConfig(t)
+	id := circuits.ExecutionCircuitID
+	manifestPath := filepath.Join(cfg.PathForSetup(string(id)), config.ManifestFileName)
+
+	manifest := circuits.NewSetupManifest(string(id), 1, ecc.BLS12_377, map[string]any{
+		"cfg_checksum":           cfg.Execution.SetupChecksum(&cfg.TracesLimits),
+		"cfg_checksum_canonical": cfg.Execution.VersionedSetupChecksum(&cfg.TracesLimits, config.ChecksumCanonical),
+	})
+	require.NoError(t, manifest.WriteTo(manifestPath))
+	require.NoError(t, checkSetupChecksum(cfg, id, &cfg.TracesLimits))
+
+	manifest.ExtraFlags["cfg_checksum_canonical"] = "0x1234"
+	require.NoError(t, manifest.WriteTo(manifestPath))
+	err := checkSetupChecksum(cfg, id, &cfg.TracesLimits)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "canonical config checksum")
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/consensys/gnark/constraint"
 	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
 )
@@ -109,6 +111,12 @@ func CircuitDigest(circuit constraint.ConstraintSystem) (string, error) {
 	return objectChecksum(circuit)
 }
 
+// VerifyingKeyChecksum computes the checksum of a verifying key, as recorded
+// in the setup manifest.
+func VerifyingKeyChecksum(vk plonk.VerifyingKey) (string, error) {
+	return objectChecksum(vk)
+}
+
 // WriteTo writes the setup assets to specified root directory.
 func (s *Setup) WriteTo(rootDir string) error {
 	circuitPath := filepath.Join(rootDir, config.CircuitFileName)
@@ -133,12 +141,10 @@ func (s *Setup) WriteTo(rootDir string) error {
 
 	hasSolidity := s.Circuit.Field().String() == ecc.BN254.ScalarField().String()
 	if hasSolidity {
-		f, err := os.Create(solidityVerifierPath)
+		err := files.WriteAtomic(solidityVerifierPath, func(w io.Writer) error {
+			return s.VerifyingKey.ExportSolidity(w, solidity.WithPragmaVersion(solidityPragmaVersion))
+		})
 		if err != nil {
-			return fmt.Errorf("creating verifier contract file: %w", err)
-		}
-		defer f.Close()
-		if err = s.VerifyingKey.ExportSolidity(f, solidity.WithPragmaVersion(solidityPragmaVersion)); err != nil {
 			return fmt.Errorf("exporting verifier contract to file: %w", err)
 		}
 	}
@@ -146,7 +152,31 @@ func (s *Setup) WriteTo(rootDir string) error {
 	return nil
 }
 
+// preloadedSetups stores the setups loaded by [PreloadSetup], by root
+// directory.
+var preloadedSetups sync.Map
+
+// PreloadSetup loads the setup of the circuit and keeps it in memory, the
+// subsequent calls to [LoadSetup] for the same circuit return it without
+// reading the files again. It is meant for the standby provers, which load
+// the proving keys before they take over a job.
+func PreloadSetup(cfg *config.Config, circuitID CircuitID) error {
+	setup, err := loadSetup(cfg, circuitID)
+	if err != nil {
+		return fmt.Errorf("preloading the setup of %v: %w", circuitID, err)
+	}
+	preloadedSetups.Store(cfg.PathForSetup(string(circuitID)), setup)
+	return nil
+}
+
 func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
+	if setup, ok := preloadedSetups.Load(cfg.PathForSetup(string(circuitID))); ok {
+		return setup.(Setup), nil
+	}
+	return loadSetup(cfg, circuitID)
+}
+
+func loadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 	runtime.GC()
 
 	rootDir := cfg.PathForSetup(string(circuitID))
@@ -156,6 +186,21 @@ func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 		return Setup{}, fmt.Errorf("reading manifest from file: %w", err)
 	}
 
+	// When trusted keys are configured, the manifest must be signed by one of
+	// them and the circuit must be the one of the manifest. The verifying key
+	// is checked against the manifest below in any case and the proving key
+	// is derived from the circuit, the verifying key and the SRS.
+	verifyAssets := len(cfg.SetupSigning.TrustedKeys) > 0
+	if verifyAssets {
+		trusted, err := LoadTrustedKeys(cfg.SetupSigning)
+		if err != nil {
+			return Setup{}, fmt.Errorf("loading the trusted keys: %w", err)
+		}
+		if err := manifest.VerifySignature(trusted); err != nil {
+			return Setup{}, fmt.Errorf("verifying the signature of the manifest %q: %w", manifestPath, err)
+		}
+	}
+
 	curveID, err := ecc.IDFromString(manifest.CurveID)
 	if err != nil {
 		return Setup{}, fmt.Errorf("parsing curve ID: %w", err)
@@ -163,10 +208,15 @@ func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 
 	circuitPath := filepath.Join(rootDir, config.CircuitFileName)
 	circuit := plonk.NewCS(curveID)
-	if err := readFromFile(circuitPath, circuit); err != nil {
+	circuitChecksum, err := readFromFileImpl(circuitPath, circuit, verifyAssets)
+	if err != nil {
 		return Setup{}, fmt.Errorf("reading circuit from file: %w", err)
 	}
 
+	if verifyAssets && circuitChecksum != manifest.Checksums.Circuit {
+		return Setup{}, fmt.Errorf("circuit checksum mismatch: expected %q, got %q", manifest.Checksums.Circuit, circuitChecksum)
+	}
+
 	verifyingKeyPath := filepath.Join(rootDir, config.VerifyingKeyFileName)
 	vk := plonk.NewVerifyingKey(curveID)
 	if err := readFromFile(verifyingKeyPath, vk); err != nil {
@@ -217,17 +267,12 @@ func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 	}, nil
 }
 
+// writeToFile writes the object atomically, so that a crash during the setup
+// does not leave a truncated asset behind.
 func writeToFile(path string, object any) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("creating %q: %w", path, err)
-	}
-	defer f.Close()
-
-	if err := writeToWriter(f, object); err != nil {
+	if err := files.WriteAtomic(path, func(w io.Writer) error { return writeToWriter(w, object) }); err != nil {
 		return fmt.Errorf("writing %q: %w", path, err)
 	}
-
 	return nil
 }
 
@@ -259,6 +304,15 @@ func ReadVerifyingKey(path string, into plonk.VerifyingKey) error {
 
 // this function is used to read circuits and verifying keys from disk
 func readFromFile(path string, into any) error {
+	_, err := readFromFileImpl(path, into, false)
+	return err
+}
+
+// readFromFileImpl reads the object from the file and, if withChecksum is
+// set, returns the checksum of the file. As the objects are written with
+// [writeToWriter], it is the [objectChecksum] of the object, computed without
+// encoding the object again.
+func readFromFileImpl(path string, into any, withChecksum bool) (checksum string, err error) {
 	logrus.Debugf("reading %s", path)
 
 	// if any implements io.ReaderRawFrom, we use it, else we use io.ReaderFrom, else we panic.
@@ -274,16 +328,21 @@ func readFromFile(path string, into any) error {
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("opening %q: %w", path, err)
+		return "", fmt.Errorf("opening %q: %w", path, err)
+	}
+
+	if withChecksum {
+		h := sha256.Sum256(data)
+		checksum = "0x" + hex.EncodeToString(h[:])
 	}
 
 	if _, err = rFunc(bytes.NewReader(data)); err != nil {
-		return fmt.Errorf("reading %q from disk: %w", path, err)
+		return "", fmt.Errorf("reading %q from disk: %w", path, files.WrapTruncated(err))
 	}
 
 	logrus.Debugf("read %s", path)
 
-	return err
+	return checksum, nil
 }
 
 func objectChecksum(object any) (string, error) {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
 )
 
 // SetupManifest is the human readable manifest of the assets generated by the prover setup command
@@ -25,6 +26,10 @@ type SetupManifest struct {
 	NbConstraints int            `json:"nbConstraints"`
 	CurveID       string         `json:"curveID"`
 	ExtraFlags    map[string]any `json:"extraFlags"`
+
+	// Signature is set when the setup command is configured with a signing
+	// key, see [SetupManifest.Sign].
+	Signature *ManifestSignature `json:"signature,omitempty"`
 }
 
 // NewSetupManifest creates a new manifest.
@@ -136,14 +141,8 @@ func (m *SetupManifest) WriteTo(filePath string) error {
 		return fmt.Errorf("encoding manifest to JSON: %s", err)
 	}
 
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("creating output JSON file: %s", err)
-	}
-	defer f.Close()
-
-	if _, err = f.Write(b); err != nil {
-		return fmt.Errorf("writing manifest to file: %s", err)
+	if err := files.WriteFileAtomic(filePath, b); err != nil {
+		return fmt.Errorf("writing manifest to file: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,352 @@
+package circuits
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+const (
+	// SignatureAlgorithmEd25519 signs the payload with ed25519
+	SignatureAlgorithmEd25519 = "ed25519"
+	// SignatureAlgorithmECDSAP256 signs the SHA-256 digest of the payload
+	// with ECDSA over P-256. The signature is ASN.1 encoded.
+	SignatureAlgorithmECDSAP256 = "ecdsa-p256-sha256"
+)
+
+// ErrManifestNotSigned is returned when verifying the signature of a
+// manifest that has none.
+var ErrManifestNotSigned = errors.New("the manifest is not signed")
+
+// ManifestSignature is the signature of a [SetupManifest]
+type ManifestSignature struct {
+	// KeyID is the fingerprint of the public key of the signer, see
+	// [KeyFingerprint].
+	KeyID     string `json:"keyID"`
+	Algorithm string `json:"algorithm"`
+	// Value is the signature of [SetupManifest.SigningPayload] in hex
+	Value string `json:"value"`
+}
+
+// ManifestSigner signs the setup manifests
+type ManifestSigner interface {
+	// PublicKey returns the public key with which the signatures verify
+	PublicKey() crypto.PublicKey
+	// Sign returns the signature of the payload. For ECDSA keys, the payload
+	// is hashed with SHA-256 by the signer.
+	Sign(payload []byte) ([]byte, error)
+}
+
+// SigningPayload returns the bytes signed by the manifest signature: the
+// canonical JSON encoding of the manifest without its signature.
+func (m *SetupManifest) SigningPayload() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return utils.CanonicalJSON(unsigned)
+}
+
+// Sign signs the manifest with the signer, replacing its previous signature
+// if any.
+func (m *SetupManifest) Sign(signer ManifestSigner) error {
+
+	algorithm, err := signatureAlgorithm(signer.PublicKey())
+	if err != nil {
+		return err
+	}
+
+	keyID, err := KeyFingerprint(signer.PublicKey())
+	if err != nil {
+		return err
+	}
+
+	payload, err := m.SigningPayload()
+	if err != nil {
+		return fmt.Errorf("encoding the manifest: %w", err)
+	}
+
+	sig, err := signer.Sign(payload)
+	if err != nil {
+		return fmt.Errorf("signing the manifest: %w", err)
+	}
+
+	// A broken signer, e.g. a misconfigured KMS command, must not produce
+	// setups that the prover refuses later on.
+	if err := verifySignature(signer.PublicKey(), payload, sig); err != nil {
+		return fmt.Errorf("the signer produced an invalid signature: %w", err)
+	}
+
+	m.Signature = &ManifestSignature{
+		KeyID:     keyID,
+		Algorithm: algorithm,
+		Value:     utils.HexEncodeToString(sig),
+	}
+	return nil
+}
+
+// IsSignedBy returns true if the manifest bears a signature of key. The
+// signature itself is not verified, see [SetupManifest.VerifySignature].
+func (m *SetupManifest) IsSignedBy(key crypto.PublicKey) bool {
+	if m.Signature == nil {
+		return false
+	}
+	keyID, err := KeyFingerprint(key)
+	return err == nil && keyID == m.Signature.KeyID
+}
+
+// VerifySignature checks that the manifest is signed by one of the trusted
+// keys and that the signature is valid.
+func (m *SetupManifest) VerifySignature(trusted []crypto.PublicKey) error {
+
+	if m.Signature == nil {
+		return ErrManifestNotSigned
+	}
+
+	var key crypto.PublicKey
+	for _, k := range trusted {
+		if m.IsSignedBy(k) {
+			key = k
+			break
+		}
+	}
+
+	if key == nil {
+		return fmt.Errorf("the manifest is signed by the untrusted key %v", m.Signature.KeyID)
+	}
+
+	algorithm, err := signatureAlgorithm(key)
+	if err != nil {
+		return err
+	}
+
+	if algorithm != m.Signature.Algorithm {
+		return fmt.Errorf("the signature algorithm %q does not match the key %v", m.Signature.Algorithm, m.Signature.KeyID)
+	}
+
+	sig, err := utils.HexDecodeString(m.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("decoding the signature: %w", err)
+	}
+
+	payload, err := m.SigningPayload()
+	if err != nil {
+		return fmt.Errorf("encoding the manifest: %w", err)
+	}
+
+	return verifySignature(key, payload, sig)
+}
+
+// KeyFingerprint returns the hex-encoded SHA-256 digest of the PKIX encoding
+// of the public key. It identifies the signers in the manifests.
+func KeyFingerprint(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("encoding the public key: %w", err)
+	}
+	h := sha256.Sum256(der)
+	return utils.HexEncodeToString(h[:]), nil
+}
+
+// NewManifestSigner returns the signer configured for the setup command or
+// nil if the manifests are not to be signed.
+func NewManifestSigner(cfg config.SetupSigning) (ManifestSigner, error) {
+	switch {
+	case cfg.KeyFile != "":
+		signer, err := LoadKeySigner(cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return signer, nil
+	case len(cfg.KMSCommand) > 0:
+		pub, err := LoadPublicKey(cfg.KMSPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := NewCommandSigner(cfg.KMSCommand, pub)
+		if err != nil {
+			return nil, err
+		}
+		return signer, nil
+	default:
+		return nil, nil
+	}
+}
+
+// LoadTrustedKeys returns the public keys trusted by the prover to sign the
+// setup manifests.
+func LoadTrustedKeys(cfg config.SetupSigning) ([]crypto.PublicKey, error) {
+	res := make([]crypto.PublicKey, len(cfg.TrustedKeys))
+	for i, path := range cfg.TrustedKeys {
+		key, err := LoadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = key
+	}
+	return res, nil
+}
+
+// KeySigner signs the manifests with a private key held in memory
+type KeySigner struct {
+	key crypto.Signer
+}
+
+// NewKeySigner returns a signer using an ed25519 or ECDSA P-256 private key
+func NewKeySigner(key crypto.Signer) (*KeySigner, error) {
+	if _, err := signatureAlgorithm(key.Public()); err != nil {
+		return nil, err
+	}
+	return &KeySigner{key: key}, nil
+}
+
+// LoadKeySigner returns a signer using the PEM-encoded PKCS#8 private key
+// stored in the file.
+func LoadKeySigner(path string) (*KeySigner, error) {
+
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the private key in %q: %w", path, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("the key in %q cannot sign", path)
+	}
+
+	return NewKeySigner(signer)
+}
+
+func (s *KeySigner) PublicKey() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *KeySigner) Sign(payload []byte) ([]byte, error) {
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		return s.key.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(payload)
+	return s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// CommandSigner signs the manifests by running an external command, e.g. a
+// script calling a KMS. The command receives the payload on its stdin and
+// writes the raw signature on its stdout.
+type CommandSigner struct {
+	command []string
+	pub     crypto.PublicKey
+}
+
+// NewCommandSigner returns a signer running the command. pub is the public
+// key of the key used by the command.
+func NewCommandSigner(command []string, pub crypto.PublicKey) (*CommandSigner, error) {
+	if len(command) == 0 {
+		return nil, errors.New("empty signing command")
+	}
+	if _, err := signatureAlgorithm(pub); err != nil {
+		return nil, err
+	}
+	return &CommandSigner{command: command, pub: pub}, nil
+}
+
+func (s *CommandSigner) PublicKey() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *CommandSigner) Sign(payload []byte) ([]byte, error) {
+
+	var stdout, stderr bytes.Buffer
+
+	// #nosec G204 -- the command comes from the config of the operator
+	cmd := exec.CommandContext(context.Background(), s.command[0], s.command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running %q: %w: %s", s.command[0], err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// LoadPublicKey reads a PEM-encoded PKIX public key from the file
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the public key in %q: %w", path, err)
+	}
+
+	if _, err := signatureAlgorithm(key); err != nil {
+		return nil, fmt.Errorf("the key in %q: %w", path, err)
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %q", path)
+	}
+	return block, nil
+}
+
+// signatureAlgorithm returns the signature algorithm used with the key
+func signatureAlgorithm(key crypto.PublicKey) (string, error) {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return SignatureAlgorithmEd25519, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported ECDSA curve %v", key.Curve.Params().Name)
+		}
+		return SignatureAlgorithmECDSAP256, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func verifySignature(key crypto.PublicKey, payload, sig []byte) error {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, sig) {
+			return errors.New("invalid ed25519 signature")
+		}
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
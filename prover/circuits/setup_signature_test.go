@@ -0,0 +1,103 @@
+package circuits
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/require"
+)
+
+func testManifest() SetupManifest {
+	m := NewSetupManifest("execution", 1<<20, ecc.BLS12_377, map[string]any{
+		"cfg_checksum": "0x1234",
+		"nbPairs":      12,
+	})
+	m.Checksums.VerifyingKey = "0xaa"
+	m.Checksums.Circuit = "0xbb"
+	return m
+}
+
+func TestManifestSignature(t *testing.T) {
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	for _, key := range []crypto.Signer{edKey, ecKey} {
+
+		signer, err := NewKeySigner(key)
+		require.NoError(t, err)
+
+		m := testManifest()
+		require.ErrorIs(t, m.VerifySignature([]crypto.PublicKey{key.Public()}), ErrManifestNotSigned)
+
+		require.NoError(t, m.Sign(signer))
+		require.True(t, m.IsSignedBy(key.Public()))
+		require.NoError(t, m.VerifySignature([]crypto.PublicKey{key.Public()}))
+
+		// The signature survives writing and reading the manifest back
+		path := filepath.Join(t.TempDir(), config.ManifestFileName)
+		require.NoError(t, m.WriteTo(path))
+		read, err := ReadSetupManifest(path)
+		require.NoError(t, err)
+		require.NoError(t, read.VerifySignature([]crypto.PublicKey{key.Public()}))
+
+		// A tampered manifest is rejected
+		tampered := *read
+		tampered.Checksums.VerifyingKey = "0xcc"
+		require.Error(t, tampered.VerifySignature([]crypto.PublicKey{key.Public()}))
+
+		// So is a manifest signed by an untrusted key
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		require.Error(t, read.VerifySignature([]crypto.PublicKey{other.Public()}))
+	}
+}
+
+func TestManifestSignerFromConfig(t *testing.T) {
+
+	dir := t.TempDir()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(dir, "key.pem")
+	pubPath := filepath.Join(dir, "pub.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600))
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0600))
+
+	signer, err := NewManifestSigner(config.SetupSigning{})
+	require.NoError(t, err)
+	require.Nil(t, signer)
+
+	signer, err = NewManifestSigner(config.SetupSigning{KeyFile: keyPath})
+	require.NoError(t, err)
+
+	trusted, err := LoadTrustedKeys(config.SetupSigning{TrustedKeys: []string{pubPath}})
+	require.NoError(t, err)
+
+	m := testManifest()
+	require.NoError(t, m.Sign(signer))
+	require.NoError(t, m.VerifySignature(trusted))
+
+	// A signing command returning a wrong signature is caught at setup time
+	badSigner, err := NewCommandSigner([]string{"echo", "not-a-signature"}, pub)
+	require.NoError(t, err)
+	require.Error(t, m.Sign(badSigner))
+}
@@ -0,0 +1,147 @@
+package circuits
+
+import (
+	"cmp"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultTopHints is the number of hints reported by a [HintProfiler] when
+// the number is not specified.
+const defaultTopHints = 10
+
+// WithSolverNbTasks returns a solver option setting the number of goroutines
+// used by gnark to solve the witness. A non-positive value keeps the default
+// of gnark, that is, one goroutine per CPU.
+func WithSolverNbTasks(nbTasks int) solver.Option {
+	if nbTasks <= 0 {
+		return func(*solver.Config) error { return nil }
+	}
+	return solver.WithNbTasks(nbTasks)
+}
+
+// SolverOptions returns the options to pass to [ProveCheck] to apply the
+// solver settings of the config to the circuit circuitID.
+func SolverOptions(cfg *config.Config, circuitID CircuitID) []any {
+	opts := []any{WithSolverNbTasks(cfg.Solver.NbTasks)}
+	if cfg.Solver.ProfileHints {
+		opts = append(opts, NewHintProfiler(string(circuitID), cfg.Solver.TopHints))
+	}
+	return opts
+}
+
+// HintStat is the time spent by the solver in a hint
+type HintStat struct {
+	Name  string
+	Calls int
+	// Total is the cumulated time of the calls. As the solver runs the hints
+	// concurrently, it may exceed the wall-clock time of the solving.
+	Total time.Duration
+}
+
+// HintProfiler collects the time spent in every hint while solving the
+// witness of a circuit. It is passed as an option to [ProveCheck] which
+// reports the hints taking the most time once the proof is generated. A
+// profiler may be reused across several proofs, the stats are cumulated.
+type HintProfiler struct {
+	circuitName string
+	top         int
+
+	mu    sync.Mutex
+	stats map[string]*HintStat
+}
+
+// NewHintProfiler returns a profiler reporting the top slowest hints of the
+// circuit circuitName. A non-positive top reports the 10 slowest hints.
+func NewHintProfiler(circuitName string, top int) *HintProfiler {
+	if top <= 0 {
+		top = defaultTopHints
+	}
+	return &HintProfiler{
+		circuitName: circuitName,
+		top:         top,
+		stats:       map[string]*HintStat{},
+	}
+}
+
+// SolverOption returns a solver option instrumenting the hints registered
+// in the solver config. The option must come after the ones registering or
+// overriding hints, otherwise these hints are not profiled. [ProveCheck]
+// takes care of that.
+func (p *HintProfiler) SolverOption() solver.Option {
+	return func(cfg *solver.Config) error {
+		for id, hint := range cfg.HintFunctions {
+			cfg.HintFunctions[id] = p.instrument(solver.GetHintName(hint), hint)
+		}
+		return nil
+	}
+}
+
+// instrument wraps the hint so that its calls are timed
+func (p *HintProfiler) instrument(name string, hint solver.Hint) solver.Hint {
+	return func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		start := time.Now()
+		err := hint(field, inputs, outputs)
+		p.record(name, time.Since(start))
+		return err
+	}
+}
+
+func (p *HintProfiler) record(name string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stats[name]
+	if !ok {
+		s = &HintStat{Name: name}
+		p.stats[name] = s
+	}
+	s.Calls++
+	s.Total += d
+}
+
+// Top returns the stats of the n hints with the largest cumulated time,
+// sorted by decreasing time.
+func (p *HintProfiler) Top(n int) []HintStat {
+	p.mu.Lock()
+	res := make([]HintStat, 0, len(p.stats))
+	for _, s := range p.stats {
+		res = append(res, *s)
+	}
+	p.mu.Unlock()
+
+	slices.SortFunc(res, func(a, b HintStat) int {
+		if a.Total != b.Total {
+			return cmp.Compare(b.Total, a.Total)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	if n < len(res) {
+		res = res[:n]
+	}
+	return res
+}
+
+// Report logs the hints taking the most time
+func (p *HintProfiler) Report() {
+	top := p.Top(p.top)
+	if len(top) == 0 {
+		logrus.Infof("hint profile of %v: no hint was called", p.circuitName)
+		return
+	}
+
+	logrus.Infof("hint profile of %v: top %v hints by cumulated time", p.circuitName, len(top))
+	for i, s := range top {
+		logrus.Infof(
+			"\t#%v %v: %v calls, total=%v, avg=%v",
+			i, s.Name, s.Calls, s.Total, s.Total/time.Duration(s.Calls),
+		)
+	}
+}
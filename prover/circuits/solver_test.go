@@ -0,0 +1,59 @@
+package circuits
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/stretchr/testify/require"
+)
+
+func slowHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	time.Sleep(5 * time.Millisecond)
+	return nil
+}
+
+func fastHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return nil
+}
+
+func TestHintProfiler(t *testing.T) {
+	assert := require.New(t)
+
+	cfg := solver.Config{
+		HintFunctions: map[solver.HintID]solver.Hint{
+			solver.GetHintID(slowHint): slowHint,
+			solver.GetHintID(fastHint): fastHint,
+		},
+	}
+
+	p := NewHintProfiler("test", 0)
+	assert.NoError(p.SolverOption()(&cfg))
+	assert.Empty(p.Top(10))
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(cfg.HintFunctions[solver.GetHintID(fastHint)](nil, nil, nil))
+	}
+	assert.NoError(cfg.HintFunctions[solver.GetHintID(slowHint)](nil, nil, nil))
+
+	top := p.Top(10)
+	assert.Len(top, 2)
+	assert.Equal(solver.GetHintName(slowHint), top[0].Name)
+	assert.Equal(1, top[0].Calls)
+	assert.Equal(solver.GetHintName(fastHint), top[1].Name)
+	assert.Equal(3, top[1].Calls)
+
+	assert.Len(p.Top(1), 1)
+	p.Report()
+}
+
+func TestWithSolverNbTasks(t *testing.T) {
+	assert := require.New(t)
+
+	cfg := solver.Config{NbTasks: 7}
+	assert.NoError(WithSolverNbTasks(0)(&cfg))
+	assert.Equal(7, cfg.NbTasks)
+	assert.NoError(WithSolverNbTasks(3)(&cfg))
+	assert.Equal(3, cfg.NbTasks)
+}
@@ -173,3 +173,16 @@ func toLagrange(srs kzg.SRS, sizeLagrange int) (kzg.SRS, error) {
 		panic("unknown SRS type")
 	}
 }
+
+// MaxCanonicalSize returns the size of the largest canonical SRS available in
+// the store for the given curve, or 0 if there are none. The SRS files are not
+// read.
+func (store *SRSStore) MaxCanonicalSize(curveID ecc.ID) int {
+	res := 0
+	for _, entry := range store.entries[curveID] {
+		if entry.isCanonical {
+			res = max(res, entry.size)
+		}
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/artifacts"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// artifactServerShutdownTimeout bounds the time given to the in-flight
+// transfers to complete when the controller exits.
+const artifactServerShutdownTimeout = 5 * time.Second
+
+// artifactVolumes returns the directories served by the artifact server: the
+// requests and the responses directories of the enabled jobs, named after the
+// job and the directory, e.g. "execution/requests". Only the responses may be
+// uploaded, by the remote provers.
+func artifactVolumes(cfg *config.Config) map[string]artifacts.Volume {
+
+	res := map[string]artifacts.Volume{}
+	add := func(job string, dirs *config.WithRequestDir) {
+		res[job+"/"+config.RequestsFromSubDir] = artifacts.Volume{Dir: dirs.DirFrom()}
+		res[job+"/"+config.RequestsToSubDir] = artifacts.Volume{Dir: dirs.DirTo(), Uploads: true}
+	}
+
+	if cfg.Controller.EnableExecution {
+		add(jobNameExecution, &cfg.Execution.WithRequestDir)
+	}
+	if cfg.Controller.EnableBlobDecompression {
+		add(jobNameBlobDecompression, &cfg.BlobDecompression.WithRequestDir)
+	}
+	if cfg.Controller.EnableAggregation {
+		add(jobNameAggregation, &cfg.Aggregation.WithRequestDir)
+	}
+
+	return res
+}
+
+// readArtifactToken reads the bearer token of the artifact server
+func readArtifactToken(path string) (string, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read the token of the artifact server: %w", err)
+	}
+
+	token := string(bytes.TrimSpace(b))
+	if token == "" {
+		return "", fmt.Errorf("the token file of the artifact server %v is empty", path)
+	}
+	return token, nil
+}
+
+// startArtifactServer starts the server transferring the requests and the
+// responses of the enabled jobs in resumable chunks. It returns nil if the
+// server is disabled.
+func startArtifactServer(cfg *config.Config) (*http.Server, error) {
+
+	scfg := &cfg.Controller.ArtifactServer
+	if !scfg.Enabled {
+		return nil, nil
+	}
+
+	token, err := readArtifactToken(scfg.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addr    = net.JoinHostPort(scfg.BindAddress, strconv.Itoa(scfg.Port))
+		volumes = artifactVolumes(cfg)
+		handler = artifacts.NewServer(volumes, artifacts.ServerOptions{
+			ChunkSize:    scfg.ChunkSize,
+			Token:        token,
+			UploadTTL:    scfg.UploadTTL,
+			MaxManifests: scfg.MaxCachedManifests,
+		})
+	)
+
+	// The timeouts only bound the headers: a chunk may take a while to be
+	// transferred on the slow networks this server is meant for.
+	server := &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		Handler:           handler.Handler(),
+	}
+
+	// The abandoned uploads are expired until the server is shut down
+	expiryCtx, stopExpiry := context.WithCancel(context.Background())
+	server.RegisterOnShutdown(stopExpiry)
+	go handler.RunExpiry(expiryCtx)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("Artifact server closed with error %v", err)
+		}
+	}()
+
+	logrus.Infof("Started the artifact server on %v for %v volumes", addr, len(volumes))
+	return server, nil
+}
+
+// shutdownArtifactServer gracefully shuts down the artifact server, if any
+func shutdownArtifactServer(ctx context.Context, server *http.Server) {
+
+	if server == nil {
+		return
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), artifactServerShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logrus.Warnf("artifact server shutdown: %v", err)
+		return
+	}
+
+	logrus.Infof("artifact server successfully shutdown")
+}
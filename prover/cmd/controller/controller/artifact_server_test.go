@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/artifacts"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactVolumes(t *testing.T) {
+
+	cfg := &config.Config{
+		Controller: config.Controller{
+			EnableExecution:   true,
+			EnableAggregation: true,
+		},
+	}
+	cfg.Execution.RequestsRootDir = "/data/execution"
+	cfg.BlobDecompression.RequestsRootDir = "/data/compression"
+	cfg.Aggregation.RequestsRootDir = "/data/aggregation"
+
+	assert.Equal(t, map[string]artifacts.Volume{
+		"execution/requests":    {Dir: "/data/execution/requests"},
+		"execution/responses":   {Dir: "/data/execution/responses", Uploads: true},
+		"aggregation/requests":  {Dir: "/data/aggregation/requests"},
+		"aggregation/responses": {Dir: "/data/aggregation/responses", Uploads: true},
+	}, artifactVolumes(cfg))
+
+	// The server is not started unless enabled
+	server, err := startArtifactServer(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, server)
+
+	// nor without a token
+	cfg.Controller.ArtifactServer = config.ArtifactServer{
+		Enabled:     true,
+		BindAddress: "127.0.0.1",
+		TokenFile:   filepath.Join(t.TempDir(), "token"),
+	}
+	_, err = startArtifactServer(cfg)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(cfg.Controller.ArtifactServer.TokenFile, []byte(" \n"), 0600))
+	_, err = startArtifactServer(cfg)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(cfg.Controller.ArtifactServer.TokenFile, []byte("secret\n"), 0600))
+	server, err = startArtifactServer(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:0", server.Addr)
+	shutdownArtifactServer(context.Background(), server)
+}
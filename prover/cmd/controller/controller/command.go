@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 
+	"github.com/consensys/linea-monorepo/prover/circuits/selftest"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -64,6 +65,14 @@ func cobraControllerRunCmd(c *cobra.Command, args []string) {
 		}
 	}
 
+	// Check the assets before accepting any job, so that a broken deployment
+	// is reported right away.
+	if cfg.Controller.SelfTest {
+		if err := selftest.Run(context.Background(), cfg); err != nil {
+			logrus.Fatalf("the self-test failed : %v", err)
+		}
+	}
+
 	// Start the main loop
 	runController(context.Background(), cfg)
 }
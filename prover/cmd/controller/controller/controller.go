@@ -7,6 +7,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/consensys/linea-monorepo/prover/backend/files"
 	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
@@ -31,6 +32,12 @@ func runController(ctx context.Context, cfg *config.Config) {
 		)
 	}
 
+	// Start the artifact server, nil if disabled
+	artifactServer, err := startArtifactServer(cfg)
+	if err != nil {
+		logrus.Fatalf("could not start the artifact server: %v", err)
+	}
+
 	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM)
 	defer stop()
 
@@ -58,6 +65,7 @@ func runController(ctx context.Context, cfg *config.Config) {
 			// detected and handled.
 			cLog.Infoln("Context canceled by caller or SIGTERM. Exiting")
 			metrics.ShutdownServer(ctx)
+			shutdownArtifactServer(ctx, artifactServer)
 			return
 
 		// Processing a new job
@@ -83,6 +91,21 @@ func runController(ctx context.Context, cfg *config.Config) {
 			// Run the command (potentially retrying in large mode)
 			status := executor.Run(job)
 
+			// The response is only handed over to the coordinator if it was
+			// completely written, the job fails otherwise.
+			if status.ExitCode == CodeSuccess {
+				tmpRespFile := job.TmpResponseFile(cfg)
+				if err := files.CheckComplete(tmpRespFile); err != nil {
+					cLog.Errorf("The prover succeeded but its response is not usable: %v", err)
+					os.Remove(tmpRespFile)
+					status = Status{
+						ExitCode: CodePartialResponse,
+						Err:      err,
+						What:     "partial response file",
+					}
+				}
+			}
+
 			// createColumns the job according to the status we got
 			switch {
 
@@ -103,7 +126,7 @@ func runController(ctx context.Context, cfg *config.Config) {
 					tmpRespFile, respFile,
 				)
 
-				if err := os.Rename(tmpRespFile, respFile); err != nil {
+				if err := files.RenameDurable(tmpRespFile, respFile); err != nil {
 					// @Alex: it is unclear how the rename operation could fail
 					// here. If this happens, we prefer removing the tmp file.
 					// Note that the operation is an `mv -f`
@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 	"text/template"
 	"time"
@@ -275,6 +276,47 @@ func TestFileWatcherL(t *testing.T) {
 	assert.Nil(t, fw.GetBest(), "the queue should be empty now")
 }
 
+func TestFileWatcherAdmission(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.MaxConcurrentJobs = map[string]int{jobNameExecution: 1}
+	confM.Controller.MaxQueuedJobs = map[string]int{jobNameExecution: 2}
+
+	var (
+		eFrom    = confM.Execution.DirFrom()
+		cFrom    = confM.BlobDecompression.DirFrom()
+		exec01   = createTestInputFile(eFrom, 0, 1, execJob, 0)
+		exec12   = createTestInputFile(eFrom, 1, 2, execJob, 0)
+		exec45   = createTestInputFile(eFrom, 4, 5, execJob, 0)
+		compr01  = createTestInputFile(cFrom, 0, 1, compressionJob, 0)
+		fw       = NewFsWatcher(confM)
+		rejected = fmt.Sprintf("%v/%v.failure.%v_%v", confM.Execution.DirDone(), exec45, config.FailSuffix, CodeQueueFull)
+	)
+
+	// The queue holds one execution job too many: the one with the lowest
+	// priority is rejected.
+	found := fw.GetBest()
+	require.NotNil(t, found)
+	assert.Equal(t, exec01, found.OriginalFile)
+	assert.FileExists(t, rejected)
+
+	// Only one execution job may be in progress at once
+	found = fw.GetBest()
+	require.NotNil(t, found)
+	assert.Equal(t, compr01, found.OriginalFile)
+	assert.Nil(t, fw.GetBest(), "the execution jobs should wait")
+
+	// Once the first one is done, the next one can be picked
+	exec01Locked, err := filepath.Glob(path.Join(eFrom, exec01+".*"))
+	require.NoError(t, err)
+	require.Len(t, exec01Locked, 1)
+	require.NoError(t, os.Remove(exec01Locked[0]))
+
+	found = fw.GetBest()
+	require.NotNil(t, found)
+	assert.Equal(t, exec12, found.OriginalFile)
+}
+
 func setupFsTest(t *testing.T) (confM, confL *config.Config) {
 
 	// Testdir is going to contain the whole test directory
@@ -297,7 +339,7 @@ func setupFsTest(t *testing.T) (confM, confL *config.Config) {
 /bin/sh {{.InFile}}
 CODE=$?
 if [ $CODE -eq 0 ]; then
-	touch {{.OutFile}}
+	echo "{}" > {{.OutFile}}
 fi
 exit $CODE
 `
@@ -306,7 +348,7 @@ exit $CODE
 	CODE=$?
 	CODE=$(($CODE - 12))
 	if [ $CODE -eq 0 ]; then
-		touch {{.OutFile}}
+		echo "{}" > {{.OutFile}}
 	fi
 	exit $CODE
 	`
@@ -316,7 +358,7 @@ exit $CODE
 CODE=$?
 CODE=$(($CODE - 10))
 if [ $CODE -eq 0 ]; then
-	touch {{.OutFile}}
+	echo "{}" > {{.OutFile}}
 fi
 exit $CODE
 `
@@ -19,11 +19,16 @@ import (
 // these codes are generated by the executor and not the child process itself.
 // These includes, CodeFatal, CodeTooManyRetries and CodeCantRunCommand.
 const (
-	CodeSuccess        int = 0   // Success code
-	CodeTraceLimit     int = 77  // The traces are overflown
-	CodeOom            int = 137 // When the process exits on OOM
-	CodeFatal          int = 14  // When the process could not start
-	CodeCantRunCommand int = 15  // When the controller could not run the command
+	CodeSuccess         int = 0   // Success code
+	CodeTraceLimit      int = 77  // The traces are overflown
+	CodeOom             int = 137 // When the process exits on OOM
+	CodeFatal           int = 14  // When the process could not start
+	CodeCantRunCommand  int = 15  // When the controller could not run the command
+	CodeStalled         int = 16  // When the prover watchdog aborted a stalled job
+	CodeRequestTooLarge int = 17  // When the request file exceeds the size limit
+	CodePartialResponse int = 18  // When the prover succeeded but its response file is partial
+	CodeQueueFull       int = 19  // When the request was rejected because too many are queued
+	CodeProverPanic     int = 78  // When a step of the wizard prover panicked
 )
 
 // Status of a finished job
@@ -71,6 +76,16 @@ func (e *Executor) Run(job *Job) (status Status) {
 		}
 	}
 
+	// Refuse the requests exceeding the size limit before running anything
+	if err := e.admit(job); err != nil {
+		metrics.CollectRejected(job.Def.Name, "request_too_large")
+		return Status{
+			ExitCode: CodeRequestTooLarge,
+			Err:      err,
+			What:     "the request exceeds the size limit",
+		}
+	}
+
 	// if we are on a large instance and the job is execution with large suffix,
 	// we directly run with large.
 	// note: checking that locked job contains "large" is not super typesafe...
@@ -123,6 +138,27 @@ func (e *Executor) Run(job *Job) (status Status) {
 	return runCmd(cmd, job, true)
 }
 
+// admit returns an error if the request file of the job exceeds the maximal
+// request size of the config.
+func (e *Executor) admit(job *Job) error {
+
+	maxSize := e.Config.Controller.MaxRequestSize
+	if maxSize <= 0 {
+		return nil
+	}
+
+	finfo, err := os.Stat(job.InProgressPath())
+	if err != nil {
+		return fmt.Errorf("could not stat the request file: %w", err)
+	}
+
+	if finfo.Size() > maxSize {
+		return fmt.Errorf("the request file has %v bytes but the limit is %v bytes", finfo.Size(), maxSize)
+	}
+
+	return nil
+}
+
 // Builds a command from a template to run, returns a status if it failed
 func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
 
@@ -261,6 +297,8 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 		status.What = "out of memory error"
 	case CodeTraceLimit:
 		status.What = "trace limit overflow"
+	case CodeProverPanic:
+		status.What = "prover step panicked"
 	}
 
 	metrics.CollectPostProcess(job.Def.Name, status.ExitCode, processingTime, retry)
@@ -97,3 +97,38 @@ func TestRetryWithLarge(t *testing.T) {
 		assert.Equalf(t, jobs[i].ExpCode, status.ExitCode, "got status %++v", status)
 	}
 }
+
+func TestRejectTooLargeRequest(t *testing.T) {
+
+	var testDefinition = JobDefinition{
+		Name: jobNameExecution,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+	}
+
+	newExecutor := func(maxRequestSize int64) *Executor {
+		return NewExecutor(&config.Config{
+			Controller: config.Controller{
+				WorkerCmdTmpl: template.Must(
+					template.New("test-cmd").
+						Parse("/bin/sh {{.InFile}}"),
+				),
+				MaxRequestSize: maxRequestSize,
+			},
+		})
+	}
+
+	job := Job{
+		Def:        &testDefinition,
+		LockedFile: "exit-0.sh",
+	}
+
+	// The request file is a few bytes long, so it is accepted with a large
+	// enough limit or without limit and refused otherwise.
+	assert.Equal(t, CodeSuccess, newExecutor(0).Run(&job).ExitCode)
+	assert.Equal(t, CodeSuccess, newExecutor(1<<20).Run(&job).ExitCode)
+	assert.Equal(t, CodeRequestTooLarge, newExecutor(1).Run(&job).ExitCode)
+}
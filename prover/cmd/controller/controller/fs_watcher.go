@@ -26,13 +26,19 @@ type FsWatcher struct {
 	InProgress string
 	// Logger specific to the file watcher
 	Logger *logrus.Entry
+	// MaxConcurrent and MaxQueued are the admission limits by job type, see
+	// [config.Controller.MaxConcurrentJobs] and [config.Controller.MaxQueuedJobs].
+	MaxConcurrent map[string]int
+	MaxQueued     map[string]int
 }
 
 func NewFsWatcher(conf *config.Config) *FsWatcher {
 	fs := &FsWatcher{
-		LocalID:    conf.Controller.LocalID,
-		InProgress: config.InProgressSufix,
-		Logger:     conf.Logger().WithField("component", "filesystem-watcher"),
+		LocalID:       conf.Controller.LocalID,
+		InProgress:    config.InProgressSufix,
+		Logger:        conf.Logger().WithField("component", "filesystem-watcher"),
+		MaxConcurrent: conf.Controller.MaxConcurrentJobs,
+		MaxQueued:     conf.Controller.MaxQueuedJobs,
 	}
 
 	if conf.Controller.EnableExecution {
@@ -45,6 +51,9 @@ func NewFsWatcher(conf *config.Config) *FsWatcher {
 
 	if conf.Controller.EnableAggregation {
 		fs.JobToWatch = append(fs.JobToWatch, AggregatedDefinition(conf))
+		if len(conf.Aggregation.EmulationNumProofs) > 0 {
+			fs.JobToWatch = append(fs.JobToWatch, MultiAggregatedDefinition(conf))
+		}
 	}
 
 	return fs
@@ -72,12 +81,28 @@ func (fs *FsWatcher) GetBest() (job *Job) {
 		// of every jobs found so far and they will all be attributed to the
 		// last job definition.
 		jdef := &fs.JobToWatch[i]
-		if err := fs.appendJobFromDef(jdef, &jobs); err != nil {
+		jobsOfDef := []*Job{}
+		numInProgress, err := fs.appendJobFromDef(jdef, &jobsOfDef)
+		if err != nil {
 			fs.Logger.Errorf(
 				"Got an error trying to fetch job `%v` from dir %v: %v",
 				jdef.Name, jdef.dirFrom(), err,
 			)
 		}
+
+		jobsOfDef = fs.shedExcessJobs(jdef, jobsOfDef)
+
+		// The jobs are left in the queue as long as the workers are running
+		// as many jobs of the same type as allowed.
+		if maxConcurrent := fs.MaxConcurrent[jdef.Name]; maxConcurrent > 0 && numInProgress >= maxConcurrent {
+			fs.Logger.Debugf(
+				"%v `%v` jobs are in progress, the limit is %v: not picking any",
+				numInProgress, jdef.Name, maxConcurrent,
+			)
+			continue
+		}
+
+		jobs = append(jobs, jobsOfDef...)
 	}
 
 	if len(jobs) == 0 {
@@ -115,9 +140,54 @@ func (f *FsWatcher) lockBest(jobs []*Job) (pos int, success bool) {
 	return 0, false
 }
 
-// Try appending a list of jobs that are parsed from a given directory. An error
+// shedExcessJobs rejects the jobs of the definition exceeding the maximal
+// number of queued jobs, if any, and returns the remaining ones. The rejected
+// jobs are the ones with the lowest priority. They are moved to the done
+// directory with the exit code [CodeQueueFull], so that their sender can
+// submit them again later.
+func (fs *FsWatcher) shedExcessJobs(jdef *JobDefinition, jobs []*Job) []*Job {
+
+	maxQueued := fs.MaxQueued[jdef.Name]
+	if maxQueued <= 0 || len(jobs) <= maxQueued {
+		return jobs
+	}
+
+	slices.SortStableFunc(jobs, func(a, b *Job) int {
+		return a.Score() - b.Score()
+	})
+
+	status := Status{ExitCode: CodeQueueFull, What: "the queue is full"}
+
+	for _, job := range jobs[maxQueued:] {
+
+		// Another worker may have picked or rejected it in the meantime
+		if !fs.tryLockFile(job) {
+			continue
+		}
+
+		fs.Logger.Warnf(
+			"%v `%v` jobs are queued, the limit is %v: rejecting %v",
+			len(jobs), jdef.Name, maxQueued, job.OriginalFile,
+		)
+
+		jobRejected := job.DoneFile(status)
+		if err := os.Rename(job.InProgressPath(), jobRejected); err != nil {
+			fs.Logger.Errorf(
+				"Error renaming %v to %v: %v",
+				job.InProgressPath(), jobRejected, err,
+			)
+		}
+
+		metrics.CollectRejected(jdef.Name, "queue_full")
+	}
+
+	return jobs[:maxQueued]
+}
+
+// Try appending a list of jobs that are parsed from a given directory. It
+// returns the number of jobs of the directory that are in progress. An error
 // is returned if the function fails to read the directory.
-func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (err error) {
+func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (numInProgress int, err error) {
 
 	dirFrom := jdef.dirFrom()
 	fs.Logger.Tracef("Seeking jobs for %v in %v", jdef.Name, dirFrom)
@@ -125,7 +195,7 @@ func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (err er
 	// This will fail if the provided directory is not a directory
 	dirents, err := lsname(dirFrom)
 	if err != nil {
-		return fmt.Errorf("cannot ls `%s` : %v", dirFrom, err)
+		return 0, fmt.Errorf("cannot ls `%s` : %v", dirFrom, err)
 	}
 	numMatched := 0
 
@@ -140,6 +210,12 @@ func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (err er
 			continue
 		}
 
+		// The files locked by the workers
+		if strings.Contains(dirent.Name(), "."+fs.InProgress+".") {
+			numInProgress++
+			continue
+		}
+
 		// Attempt to construct a job from the filename. If the filename is
 		// not parseable to the target JobType, it will return an error.
 		job, err := NewJob(jdef, dirent.Name())
@@ -155,9 +231,9 @@ func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (err er
 	}
 
 	// Pass prometheus metrics
-	metrics.CollectFS(jdef.Name, len(dirents), numMatched)
+	metrics.CollectFS(jdef.Name, len(dirents), numMatched, numInProgress)
 
-	return nil
+	return numInProgress, nil
 }
 
 // Trylock attempts to rename a file by adding an IN_PROGRESS suffix. The lock
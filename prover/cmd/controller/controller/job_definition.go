@@ -14,6 +14,7 @@ const (
 	jobNameExecution         = "execution"
 	jobNameBlobDecompression = "compression"
 	jobNameAggregation       = "aggregation"
+	jobNameMultiAggregation  = "multi-aggregation"
 )
 
 // JobDefinition represents a collection of static parameters allowing to define
@@ -228,6 +229,59 @@ func AggregatedDefinition(conf *config.Config) JobDefinition {
 	}
 }
 
+// Definition of a multi-aggregation prover job, wrapping several aggregation
+// proofs into a single one. The requests are read from the aggregation
+// directory.
+func MultiAggregatedDefinition(conf *config.Config) JobDefinition {
+
+	return JobDefinition{
+		RequestsRootDir: conf.Aggregation.RequestsRootDir,
+
+		// Name of the job
+		Name: jobNameMultiAggregation,
+
+		// This will panic at startup if the regexp is invalid
+		InputFileRegexp: regexp2.MustCompile(
+			fmt.Sprintf(
+				`^[0-9]+-[0-9]+(-[a-fA-F0-9]+)?-getZkMultiAggregatedProof\.json(\.failure\.%v_[0-9]+)*$`,
+				config.FailSuffix,
+			),
+			regexp2.None,
+		),
+
+		// This will panic at startup if the template is invalid
+		OutputFileTmpl: tmplMustCompile(
+			"multi-agreg-output-file",
+			"{{.Start}}-{{.End}}-{{.ContentHash}}-getZkMultiAggregatedProof.json",
+		),
+
+		// Multi-aggregation jobs wrap the proofs of the aggregation jobs, they
+		// come last.
+		Priority: 2,
+
+		// Parameters of the regexp, they can loose in the sense that these
+		// regexp are only called if the `InputFileRegexp` is matched.
+		ParamsRegexp: struct {
+			Start       *regexp2.Regexp
+			End         *regexp2.Regexp
+			Stv         *regexp2.Regexp
+			Etv         *regexp2.Regexp
+			Cv          *regexp2.Regexp
+			ContentHash *regexp2.Regexp
+		}{
+			// Match a string of digit at the beginning of the line
+			Start: regexp2.MustCompile(`^[0-9]+`, regexp2.None),
+			// Match a string of digit coming after the first string of digits
+			// that initiate the line and followed by a "-"
+			End: regexp2.MustCompile(`(?<=^[0-9]+-)[0-9]+`, regexp2.None),
+			// Match the hexadecimal string that precedes `getZkMultiAggregatedProof`
+			ContentHash: regexp2.MustCompile(`(?<=^[0-9]+-[0-9]+-)[a-fA-F0-9]+(?=-getZk)`, regexp2.None),
+		},
+
+		FailureSuffix: matchFailureSuffix(config.FailSuffix),
+	}
+}
+
 // Version prefix template
 func matchVersionWithPrefix(pre string) *regexp2.Regexp {
 	return regexp2.MustCompile(
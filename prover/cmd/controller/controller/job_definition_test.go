@@ -249,6 +249,51 @@ func TestAggregatedInFileRegexp(t *testing.T) {
 	}
 }
 
+func TestMultiAggregatedInFileRegexp(t *testing.T) {
+
+	var (
+		correctM           = "102-203-abcdef0123-getZkMultiAggregatedProof.json"
+		correctWithFailM   = "102-203-abcdef0123-getZkMultiAggregatedProof.json.failure.code_77"
+		correctWith2FailsM = "102-203-abcdef0123-getZkMultiAggregatedProof.json.failure.code_77.failure.code_77"
+		missingContentHash = "102-203-getZkMultiAggregatedProof.json"
+		aggregation        = "102-203-abcdef0123-getZkAggregatedProof.json"
+		notAPoint          = "102-203-getZkMultiAggregatedProofAjson"
+	)
+
+	var (
+		// #nosec G101 -- Not a credential
+		respM = "responses/102-203-getZkMultiAggregatedProof.json"
+		// #nosec G101 -- Not a credential
+		respWithContentHash = "responses/102-203-abcdef0123-getZkMultiAggregatedProof.json"
+	)
+
+	testcase := []inpFileNamesCases{
+		{
+			Ext: "", Fail: "code", ShouldMatch: true,
+			Fnames:         []string{correctM, correctWithFailM, correctWith2FailsM, missingContentHash},
+			Explainer:      "happy path, case M",
+			ExpectedOutput: []string{respWithContentHash, respWithContentHash, respWithContentHash, respM},
+		},
+		{
+			Ext: "", Fail: "code", ShouldMatch: false,
+			Fnames:    []string{aggregation, notAPoint},
+			Explainer: "M does not pick the aggregation files",
+		},
+	}
+
+	for _, c := range testcase {
+
+		conf := config.Config{}
+		conf.Version = "0.1.2"
+
+		def := MultiAggregatedDefinition(&conf)
+
+		t.Run(c.Explainer, func(t *testing.T) {
+			runInpFileTestCase(t, &conf, &def, c)
+		})
+	}
+}
+
 func runInpFileTestCase(t *testing.T, conf *config.Config, def *JobDefinition, c inpFileNamesCases) {
 
 	for i, fname := range c.Fnames {
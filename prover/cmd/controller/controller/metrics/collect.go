@@ -10,7 +10,7 @@ import (
 )
 
 // Collect metrics relative to the queue
-func CollectFS(jobType string, numDirEntries, numMatched int) {
+func CollectFS(jobType string, numDirEntries, numMatched, numInProgress int) {
 
 	if globalRegistry == nil {
 		logrus.Tracef("No global registry found, not collecting")
@@ -24,6 +24,10 @@ func CollectFS(jobType string, numDirEntries, numMatched int) {
 	globalRegistry.NumFilesInQueue.
 		With(jobLab(jobType)).
 		Set(float64(numMatched))
+
+	globalRegistry.NumInProgress.
+		With(jobLab(jobType)).
+		Set(float64(numInProgress))
 }
 
 // Collect metrics relative to a job we are about to run. Retry means that
@@ -85,6 +89,20 @@ func CollectPostProcess(jobType string, code int, t time.Duration, retry bool) {
 		Observe(t.Seconds())
 }
 
+// Collect metrics from jobs that were rejected by the controller before being
+// run.
+func CollectRejected(jobType string, reason string) {
+
+	if globalRegistry == nil {
+		logrus.Tracef("No global registry found, not collecting")
+		return
+	}
+
+	globalRegistry.NumRejected.
+		With(prometheus.Labels{labelJobType: jobType, labelReason: reason}).
+		Inc()
+}
+
 // helper function that returns a label map for some job type
 func jobLab(jobType string) prometheus.Labels {
 	return prometheus.Labels{
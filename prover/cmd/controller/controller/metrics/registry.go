@@ -14,6 +14,7 @@ const (
 	labelExitCode   = "status"
 	labelJobType    = "job_type"
 	labelWorkerID   = "worker_id"
+	labelReason     = "reason"
 )
 
 // global registry of metrics
@@ -111,6 +112,29 @@ func initRegistry(worker_id string) {
 				},
 				[]string{labelJobType},
 			),
+
+			NumInProgress: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "num_jobs_in_progress",
+					Help:        "Number of jobs in progress on all the workers sharing the queue. Segmented by type of job",
+				},
+				[]string{labelJobType},
+			),
+
+			NumRejected: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "rejected_jobs_count",
+					Help: "Count the number of jobs refused by the controller" +
+						" without being run. Broken down by job types and reason",
+				},
+				[]string{labelJobType, labelReason},
+			),
 		}
 	})
 }
@@ -140,4 +164,11 @@ type Registry struct {
 	// The span of the job (i.e)
 	NumFilesInQueue       *prometheus.GaugeVec
 	NumEntriesInDirectory *prometheus.GaugeVec
+
+	// The number of jobs locked by the workers sharing the queue
+	NumInProgress *prometheus.GaugeVec
+
+	// Total number of jobs refused by the admission control of the
+	// controller. Labeled by type of jobs and reason of the rejection.
+	NumRejected *prometheus.CounterVec
 }
@@ -0,0 +1,36 @@
+// Command compressor-vectors generates the test vectors of the v1 blob
+// compressor, see the testvectors package.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/testvectors"
+)
+
+func main() {
+
+	var (
+		dictPath = flag.String("dict", "../../../../lib/compressor/compressor_dict.bin", "dictionary of the compressor")
+		out      = flag.String("out", "../../../../lib/compressor/blob/v1/testvectors/"+testvectors.FixturesPath, "output file")
+	)
+	flag.Parse()
+
+	dict, err := os.ReadFile(*dictPath)
+	if err != nil {
+		log.Fatalf("could not read the dictionary: %v", err)
+	}
+
+	fixtures, err := testvectors.Generate(dict)
+	if err != nil {
+		log.Fatalf("could not generate the vectors: %v", err)
+	}
+
+	if err := fixtures.Write(*out); err != nil {
+		log.Fatalf("could not write the vectors: %v", err)
+	}
+
+	log.Printf("wrote %v vectors to %v", len(fixtures.Vectors), *out)
+}
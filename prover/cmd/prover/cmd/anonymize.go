@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/replay"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fAnonymizeFrom      string
+	fAnonymizeTo        string
+	fAnonymizeSalt      string
+	fAnonymizeAddresses bool
+)
+
+// anonymizeCmd represents the anonymize command
+var anonymizeCmd = &cobra.Command{
+	Use:   "anonymize",
+	Short: "anonymize writes a redacted copy of archived execution requests, with their calldata, and optionally their addresses, replaced by pseudonyms",
+	Long: `anonymize writes a redacted copy of archived execution requests, with their
+calldata, and optionally their addresses, replaced by pseudonyms so that the
+shape of failing cases can be shared with external teams. The length of the
+calldata, the position of its zero bytes and the shape of the blocks are
+preserved. The state-manager traces and the bridge logs are dropped.
+
+The redacted requests are not valid requests: they cannot be proven and do not
+reproduce the failure by themselves. The conflated traces are not anonymized
+and must not be shared along with the requests.`,
+	RunE: cmdAnonymize,
+}
+
+func init() {
+	rootCmd.AddCommand(anonymizeCmd)
+
+	anonymizeCmd.Flags().StringVar(&fAnonymizeFrom, "from-dir", "", "directory holding the archived requests")
+	anonymizeCmd.Flags().StringVar(&fAnonymizeTo, "to-dir", "", "directory where to write the anonymized requests")
+	anonymizeCmd.Flags().StringVar(&fAnonymizeSalt, "salt", "", "secret salt of the pseudonyms, a random one is used if empty; reusing a salt keeps the pseudonyms consistent across runs")
+	anonymizeCmd.Flags().BoolVar(&fAnonymizeAddresses, "addresses", false, "also replace the recipients and the access-list addresses by pseudonyms")
+
+	_ = anonymizeCmd.MarkFlagRequired("from-dir")
+	_ = anonymizeCmd.MarkFlagRequired("to-dir")
+}
+
+func cmdAnonymize(cmd *cobra.Command, args []string) error {
+
+	salt := []byte(fAnonymizeSalt)
+	if len(salt) == 0 {
+		salt = make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("could not sample the salt: %w", err)
+		}
+	}
+
+	_, err := replay.Anonymize(fAnonymizeFrom, fAnonymizeTo, execution.AnonymizeOptions{
+		Salt:      salt,
+		Addresses: fAnonymizeAddresses,
+	})
+	return err
+}
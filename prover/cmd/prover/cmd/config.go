@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/circuits/selftest"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fConfigValidateFile string
+	fConfigMigrateFrom  string
+	fConfigMigrateTo    string
+	fConfigMigrateOut   string
+)
+
+// configCmd groups the commands operating on the prover config
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "operate on the prover config file",
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "validates a config file against the assets on disk",
+	Long: `validates a config file and checks it against the assets on disk: which
+circuits are set up, whether their verifying keys match the checksums of their
+manifest, whether the setups of the execution circuits were generated for the
+limits of the config and whether the SRS store holds large enough SRS. It then
+prints the matrix of the proof types this machine can currently serve. The
+command fails if a circuit used by an enabled job cannot be served.`,
+	RunE: cmdConfigValidate,
+}
+
+// configMigrateCmd represents the config migrate command
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [old.toml]",
+	Short: "rewrites a config file for a newer schema version",
+	Long: `rewrites a config file written for an older schema version: the deprecated
+keys are renamed, the keys introduced by the newer versions are filled with
+their default value and the schema_version field is set. The comments and the
+order of the keys are not preserved. The command fails without writing anything
+if some settings cannot be migrated, e.g. if a deprecated key and its
+replacement are both set to different values, and lists them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: cmdConfigMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configMigrateCmd)
+
+	configValidateCmd.Flags().StringVar(&fConfigValidateFile, "file", "", "config file to validate (defaults to --config)")
+
+	configMigrateCmd.Flags().StringVar(&fConfigMigrateFrom, "from", "", "schema version of the config file, e.g. v2")
+	configMigrateCmd.Flags().StringVar(&fConfigMigrateTo, "to", fmt.Sprintf("v%v", config.SchemaVersion), "schema version to migrate to")
+	configMigrateCmd.Flags().StringVar(&fConfigMigrateOut, "out", "", "file to write the migrated config to (defaults to stdout)")
+	configMigrateCmd.MarkFlagRequired("from")
+}
+
+func cmdConfigMigrate(cmd *cobra.Command, args []string) error {
+
+	from, err := config.ParseSchemaVersion(fConfigMigrateFrom)
+	if err != nil {
+		return fmt.Errorf("%s --from: %w", cmd.Name(), err)
+	}
+	to, err := config.ParseSchemaVersion(fConfigMigrateTo)
+	if err != nil {
+		return fmt.Errorf("%s --to: %w", cmd.Name(), err)
+	}
+
+	in, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("%s could not read the config file: %w", cmd.Name(), err)
+	}
+
+	out, report, err := config.Migrate(in, from, to)
+	if err != nil {
+		return fmt.Errorf("%s %w", cmd.Name(), err)
+	}
+
+	stderr := cmd.ErrOrStderr()
+	for _, r := range report.Renamed {
+		fmt.Fprintf(stderr, "renamed: %v\n", r)
+	}
+	for _, f := range report.Filled {
+		fmt.Fprintf(stderr, "filled with the default value: %v\n", f)
+	}
+	for _, i := range report.Irreconcilable {
+		fmt.Fprintf(stderr, "irreconcilable: %v\n", i)
+	}
+
+	if len(report.Irreconcilable) > 0 {
+		return fmt.Errorf("%s %v setting(s) cannot be migrated and must be fixed by hand", cmd.Name(), len(report.Irreconcilable))
+	}
+
+	if fConfigMigrateOut == "" {
+		_, err = cmd.OutOrStdout().Write(out)
+		return err
+	}
+
+	return files.WriteFileAtomic(fConfigMigrateOut, out)
+}
+
+func cmdConfigValidate(cmd *cobra.Command, args []string) error {
+
+	file := fConfigValidateFile
+	if file == "" {
+		file = fConfigFile
+	}
+
+	cfg, err := config.NewConfigFromFile(file)
+	if err != nil {
+		return fmt.Errorf("%s invalid config file: %w", cmd.Name(), err)
+	}
+
+	matrix, err := selftest.Matrix(cfg)
+	if err != nil {
+		return fmt.Errorf("%s %w", cmd.Name(), err)
+	}
+
+	printCompatibilityMatrix(cmd.OutOrStdout(), matrix)
+
+	numUnservable := 0
+	for i := range matrix {
+		if matrix[i].Enabled && !matrix[i].Ready() {
+			numUnservable++
+		}
+	}
+
+	if numUnservable > 0 {
+		return fmt.Errorf("%s %v circuit(s) used by the enabled jobs cannot be served", cmd.Name(), numUnservable)
+	}
+
+	return nil
+}
+
+// printCompatibilityMatrix prints one line per circuit of the matrix
+func printCompatibilityMatrix(out io.Writer, matrix []selftest.Compatibility) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tCIRCUIT\tCURVE\tENABLED\tSTATUS")
+	for _, c := range matrix {
+		status := "ready"
+		if !c.Ready() {
+			status = c.Err.Error()
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", c.Job, c.CircuitID, c.Curve, c.Enabled, status)
+	}
+	w.Flush()
+}
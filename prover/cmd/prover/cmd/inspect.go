@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fInspectCircuit   string
+	fInspectCheckOnly bool
+	fInspectExec      string
+)
+
+// inspectCmd groups the commands used to inspect the prover artefacts
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "inspect the structure of the prover artefacts",
+}
+
+// inspectIOPCmd represents the inspect iop command
+var inspectIOPCmd = &cobra.Command{
+	Use:   "iop",
+	Short: "compiles the wizard of a circuit and opens an interactive session to explore it",
+	Long: `compiles the wizard of a circuit and opens an interactive session to explore
+its columns, queries and rounds. Type "help" in the session to list the
+available commands. Commands can also be passed non-interactively using
+--exec with ";" as a separator.`,
+	RunE: cmdInspectIOP,
+}
+
+// inspectCircuitsCmd represents the inspect circuits command
+var inspectCircuitsCmd = &cobra.Command{
+	Use:   "circuits",
+	Short: "lists the circuits known to the prover along with their curve and dependencies",
+	RunE:  cmdInspectCircuits,
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.AddCommand(inspectIOPCmd)
+	inspectCmd.AddCommand(inspectCircuitsCmd)
+
+	inspectIOPCmd.Flags().StringVar(&fInspectCircuit, "circuit", string(circuits.ExecutionCircuitID), "circuit whose wizard is inspected (execution or execution-large)")
+	inspectIOPCmd.Flags().BoolVar(&fInspectCheckOnly, "check-only", false, "use the check-only compilation suite (faster, shows the protocol before the cryptographic compilation)")
+	inspectIOPCmd.Flags().StringVar(&fInspectExec, "exec", "", "semicolon-separated list of commands to run instead of starting an interactive session")
+}
+
+func cmdInspectIOP(cmd *cobra.Command, args []string) error {
+
+	cfg, err := config.NewConfigFromFile(fConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmd.Name(), err)
+	}
+
+	var limits config.TracesLimits
+	switch circuits.CircuitID(fInspectCircuit) {
+	case circuits.ExecutionCircuitID:
+		limits = cfg.TracesLimits
+	case circuits.ExecutionLargeCircuitID:
+		limits = cfg.TracesLimitsLarge
+	default:
+		return fmt.Errorf("%s unsupported circuit: %s", cmd.Name(), fInspectCircuit)
+	}
+
+	var zkEvm *zkevm.ZkEvm
+	if fInspectCheckOnly {
+		zkEvm, err = zkevm.FullZkEVMCheckOnly(&limits)
+	} else {
+		zkEvm, err = zkevm.FullZkEvm(&limits, cfg.Execution.SIS.Params())
+	}
+	if err != nil {
+		return fmt.Errorf("%s could not compile the zkEVM: %w", cmd.Name(), err)
+	}
+
+	session := &iopInspector{comp: zkEvm.WizardIOP, out: cmd.OutOrStdout()}
+
+	if fInspectExec != "" {
+		for _, line := range strings.Split(fInspectExec, ";") {
+			if !session.exec(line) {
+				break
+			}
+		}
+		return nil
+	}
+
+	return session.loop(os.Stdin)
+}
+
+func cmdInspectCircuits(cmd *cobra.Command, args []string) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CIRCUIT\tCURVE\tDEPENDENCIES\tDUMMY\tDEFAULT SETUP")
+	for _, info := range circuits.Registered() {
+		deps := make([]string, len(info.Dependencies))
+		for i, d := range info.Dependencies {
+			deps[i] = string(d)
+		}
+		if len(deps) == 0 {
+			deps = append(deps, "-")
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", info.ID, info.Curve, strings.Join(deps, ","), info.Dummy, info.DefaultSetup)
+	}
+	return w.Flush()
+}
+
+// iopInspector implements the commands of the interactive session opened by
+// `prover inspect iop`.
+type iopInspector struct {
+	comp *wizard.CompiledIOP
+	out  io.Writer
+}
+
+// loop reads the commands from r line by line and executes them until the
+// input is exhausted or the user exits the session.
+func (ins *iopInspector) loop(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	fmt.Fprint(ins.out, "iop> ")
+	for scanner.Scan() {
+		if !ins.exec(scanner.Text()) {
+			return nil
+		}
+		fmt.Fprint(ins.out, "iop> ")
+	}
+	return scanner.Err()
+}
+
+// exec runs a single command and returns false if the session should be
+// terminated.
+func (ins *iopInspector) exec(line string) bool {
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true
+	}
+
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+
+	switch fields[0] {
+	case "help":
+		fmt.Fprintln(ins.out, `commands:
+	modules              list the modules with their number of columns and cells
+	columns [module]     list the columns, optionally restricted to a module
+	queries [substring]  list the queries, optionally filtered by name
+	names [module]       report the near-duplicate and truncated names, optionally restricted to a module
+	query <id>           show the round, the type and the expression of a query
+	rounds               report the sizes of the protocol round by round
+	exit                 close the session`)
+	case "modules":
+		ins.modules()
+	case "columns":
+		ins.columns(arg)
+	case "queries":
+		ins.queries(arg)
+	case "query":
+		ins.query(ifaces.QueryID(arg))
+	case "names":
+		ins.names(arg)
+	case "rounds":
+		ins.rounds()
+	case "exit", "quit":
+		return false
+	default:
+		fmt.Fprintf(ins.out, "unknown command %q, type \"help\" for the list of commands\n", fields[0])
+	}
+
+	return true
+}
+
+// moduleOf returns the module a column belongs to, see [wizard.ModuleOf].
+func moduleOf(name ifaces.ColID) string {
+	return wizard.ModuleOf(string(name))
+}
+
+func (ins *iopInspector) modules() {
+
+	var (
+		numCols  = map[string]int{}
+		numCells = map[string]int{}
+		names    = []string{}
+	)
+
+	for _, name := range ins.comp.Columns.AllKeys() {
+		m := moduleOf(name)
+		if _, ok := numCols[m]; !ok {
+			names = append(names, m)
+		}
+		numCols[m]++
+		numCells[m] += ins.comp.Columns.GetSize(name)
+	}
+
+	sort.Strings(names)
+	w := tabwriter.NewWriter(ins.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tCOLUMNS\tCELLS")
+	for _, m := range names {
+		fmt.Fprintf(w, "%v\t%v\t%v\n", m, numCols[m], numCells[m])
+	}
+	w.Flush()
+}
+
+func (ins *iopInspector) columns(module string) {
+	w := tabwriter.NewWriter(ins.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tROUND\tSIZE\tSTATUS")
+	for _, name := range ins.comp.Columns.AllKeys() {
+		if module != "" && moduleOf(name) != module {
+			continue
+		}
+		col := ins.comp.Columns.GetHandle(name)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", name, col.Round(), col.Size(), ins.comp.Columns.Status(name))
+	}
+	w.Flush()
+}
+
+func (ins *iopInspector) queries(filter string) {
+	w := tabwriter.NewWriter(ins.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tROUND\tTYPE\tIGNORED")
+	for _, reg := range []*wizard.ByRoundRegister[ifaces.QueryID, ifaces.Query]{&ins.comp.QueriesNoParams, &ins.comp.QueriesParams} {
+		for _, id := range reg.AllKeys() {
+			if filter != "" && !strings.Contains(string(id), filter) {
+				continue
+			}
+			fmt.Fprintf(w, "%v\t%v\t%T\t%v\n", id, reg.Round(id), reg.Data(id), reg.IsIgnored(id))
+		}
+	}
+	w.Flush()
+}
+
+func (ins *iopInspector) query(id ifaces.QueryID) {
+
+	var reg *wizard.ByRoundRegister[ifaces.QueryID, ifaces.Query]
+	switch {
+	case ins.comp.QueriesNoParams.Exists(id):
+		reg = &ins.comp.QueriesNoParams
+	case ins.comp.QueriesParams.Exists(id):
+		reg = &ins.comp.QueriesParams
+	default:
+		fmt.Fprintf(ins.out, "no query named %q\n", id)
+		return
+	}
+
+	q := reg.Data(id)
+	fmt.Fprintf(ins.out, "name:    %v\nround:   %v\ntype:    %T\nignored: %v\n", id, reg.Round(id), q, reg.IsIgnored(id))
+
+	switch q := q.(type) {
+	case query.GlobalConstraint:
+		fmt.Fprintf(ins.out, "domain:  %v\nexpr:    %v\n", q.DomainSize, q.Expression.Pretty())
+	case query.LocalConstraint:
+		fmt.Fprintf(ins.out, "expr:    %v\n", q.Expression.Pretty())
+	}
+}
+
+// names reports the issues found by [wizard.AuditNames]. If a module is
+// given, only the issues involving one of its names are reported.
+func (ins *iopInspector) names(module string) {
+	w := tabwriter.NewWriter(ins.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND	NAMESPACE	CROSS-MODULE	NAMES")
+	for _, issue := range wizard.AuditNames(ins.comp) {
+		if module != "" && !slices.Contains(issue.Modules, module) {
+			continue
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", issue.Kind, issue.Namespace, issue.CrossModule, strings.Join(issue.Names, ", "))
+	}
+	w.Flush()
+}
+
+func (ins *iopInspector) rounds() {
+	w := tabwriter.NewWriter(ins.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROUND\tCOLUMNS\tCOMMITTED\tCOMMITTED CELLS\tPROOF CELLS\tCOINS\tQUERIES")
+	for round := 0; round < ins.comp.NumRounds(); round++ {
+
+		var (
+			committedCells, proofCells int
+			cols                       = ins.comp.Columns.AllKeysAt(round)
+			committed                  = ins.comp.Columns.AllKeysCommittedAt(round)
+			numQueries                 = len(ins.comp.QueriesNoParams.AllKeysAt(round)) + len(ins.comp.QueriesParams.AllKeysAt(round))
+		)
+
+		for _, name := range committed {
+			committedCells += ins.comp.Columns.GetSize(name)
+		}
+
+		for _, name := range cols {
+			if ins.comp.Columns.Status(name) == column.Proof {
+				proofCells += ins.comp.Columns.GetSize(name)
+			}
+		}
+
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			round, len(cols), len(committed), committedCells, proofCells,
+			len(ins.comp.Coins.AllKeysAt(round)), numQueries,
+		)
+	}
+	w.Flush()
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fOpeningsColumn string
+	fOpeningsRow    int
+)
+
+// inspectOpeningsCmd represents the inspect openings command
+var inspectOpeningsCmd = &cobra.Command{
+	Use:   "openings <openings file>",
+	Short: "opens the columns retained by the execution prover",
+	Long: `reads the columns written by the execution prover when debug.debug_openings_dir
+is set in the config. Without --column, the retained columns are listed with
+their size. With --column, the value of the column at --row is printed.
+Negative rows are counted from the end of the column.`,
+	Args: cobra.ExactArgs(1),
+	RunE: cmdInspectOpenings,
+}
+
+func init() {
+	inspectCmd.AddCommand(inspectOpeningsCmd)
+
+	inspectOpeningsCmd.Flags().StringVar(&fOpeningsColumn, "column", "", "name of the column to open")
+	inspectOpeningsCmd.Flags().IntVar(&fOpeningsRow, "row", 0, "row at which the column is opened")
+}
+
+func cmdInspectOpenings(cmd *cobra.Command, args []string) error {
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("%s could not open the openings: %w", cmd.Name(), err)
+	}
+	defer f.Close()
+
+	proof, err := accessors.ReadDebugOpenings(f)
+	if err != nil {
+		return fmt.Errorf("%s could not read the openings: %w", cmd.Name(), err)
+	}
+
+	out := cmd.OutOrStdout()
+
+	if fOpeningsColumn == "" {
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		for _, name := range accessors.DebugColumns(proof) {
+			fmt.Fprintf(tw, "%v\t%v\n", name, proof.DebugOpenings[name].Len())
+		}
+		return tw.Flush()
+	}
+
+	y, err := accessors.OpenDebugColumn(proof, ifaces.ColID(fOpeningsColumn), fOpeningsRow)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Name(), err)
+	}
+
+	fmt.Fprintln(out, y.String())
+	return nil
+}
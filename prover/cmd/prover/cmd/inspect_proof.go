@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	plonk_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761"
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/spf13/cobra"
+)
+
+// inspectProofCmd represents the inspect proof command
+var inspectProofCmd = &cobra.Command{
+	Use:   "proof <response file>",
+	Short: "pretty-prints an execution or aggregation proof response and checks its consistency",
+	Long: `parses an execution or aggregation proof response file and prints its public
+inputs, the curve of its proofs and the size of their components. The
+internal consistency of the response is checked: the hex fields are well
+formed, the proofs can be decoded and, for the execution, the public input
+matches the one recomputed from the other fields. The prover metadata are
+only accounted for in the public input if the config passed with --config
+enables them. The command fails if one of the checks fails.`,
+	Args: cobra.ExactArgs(1),
+	RunE: cmdInspectProof,
+}
+
+func init() {
+	inspectCmd.AddCommand(inspectProofCmd)
+}
+
+func cmdInspectProof(cmd *cobra.Command, args []string) error {
+
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("%s could not read the response: %w", cmd.Name(), err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return fmt.Errorf("%s could not parse the response: %w", cmd.Name(), err)
+	}
+
+	cfg := &config.Config{}
+	if fConfigFile != "" {
+		if cfg, err = config.NewConfigFromFile(fConfigFile); err != nil {
+			return fmt.Errorf("%s failed to read config file: %w", cmd.Name(), err)
+		}
+	}
+
+	rep := &proofReport{out: cmd.OutOrStdout()}
+
+	switch {
+	case fields["aggregatedProof"] != nil || fields["bw6Proof"] != nil:
+		var resp aggregation.Response
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return fmt.Errorf("%s could not parse the aggregation response: %w", cmd.Name(), err)
+		}
+		rep.aggregation(&resp)
+	case fields["blocksData"] != nil:
+		var resp execution.Response
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return fmt.Errorf("%s could not parse the execution response: %w", cmd.Name(), err)
+		}
+		rep.execution(cfg, &resp)
+	default:
+		return fmt.Errorf("%s %v is neither an execution nor an aggregation response", cmd.Name(), args[0])
+	}
+
+	if rep.failures > 0 {
+		return fmt.Errorf("%s %v of the checks failed", cmd.Name(), rep.failures)
+	}
+	return nil
+}
+
+// proofReport prints the content of a proof response along with the outcome
+// of the consistency checks.
+type proofReport struct {
+	out io.Writer
+	// failures counts the failed checks
+	failures int
+}
+
+func (r *proofReport) section(title string) {
+	fmt.Fprintf(r.out, "\n%v\n", title)
+}
+
+func (r *proofReport) field(name string, value any) {
+	fmt.Fprintf(r.out, "  %-40v %v\n", name, value)
+}
+
+func (r *proofReport) check(name string, err error) {
+	if err != nil {
+		r.failures++
+		fmt.Fprintf(r.out, "  %-40v FAILED: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(r.out, "  %-40v ok\n", name)
+}
+
+func (r *proofReport) execution(cfg *config.Config, resp *execution.Response) {
+
+	r.section("EXECUTION RESPONSE")
+	r.field("prover version", resp.Version)
+	r.field("prover mode", resp.ProverMode)
+	r.field("blocks", fmt.Sprintf("%v to %v", resp.FirstBlockNumber, resp.FirstBlockNumber+len(resp.BlocksData)-1))
+	r.field("verifying key shasum", resp.VerifyingKeyShaSum)
+
+	r.section("CHECKS")
+
+	var errHex error
+	utils.ValidateHexString(&errHex, resp.ParentStateRootHash, "parent state root hash: %w", 32)
+	if resp.ProverMode != config.ProverModeProofless {
+		utils.ValidateHexString(&errHex, resp.VerifyingKeyShaSum, "verifying key shasum: %w", 32)
+	}
+	r.check("hex fields", errHex)
+
+	if len(resp.BlocksData) == 0 {
+		r.check("blocks", errors.New("the response has no block"))
+		return
+	}
+	if errHex != nil {
+		// The functional inputs cannot be parsed
+		return
+	}
+
+	r.check("L2 message hashes", checkL2MessageHashes(resp))
+	r.check("rolling hash events", checkRollingHashEvents(resp))
+
+	fi := resp.FuncInput(cfg)
+
+	var errPI error
+	if recomputed := field.HexElement(fi.SumAsField()); recomputed != resp.PublicInput {
+		errPI = fmt.Errorf("recomputed %v, the response has %v", recomputed, resp.PublicInput)
+	}
+	r.check("public input", errPI)
+
+	r.section("PUBLIC INPUTS")
+	r.field("DataChecksum", types.Bytes32(fi.DataChecksum).Hex())
+	r.field("L2MessageHashes", fmt.Sprintf("%v hashes (max %v)", len(fi.L2MessageHashes), fi.MaxNbL2MessageHashes))
+	r.field("InitialStateRootHash", types.Bytes32(fi.InitialStateRootHash).Hex())
+	r.field("FinalStateRootHash", types.Bytes32(fi.FinalStateRootHash).Hex())
+	r.field("InitialBlockNumber", fi.InitialBlockNumber)
+	r.field("FinalBlockNumber", fi.FinalBlockNumber)
+	r.field("InitialBlockTimestamp", fi.InitialBlockTimestamp)
+	r.field("FinalBlockTimestamp", fi.FinalBlockTimestamp)
+	r.field("InitialRollingHash", types.Bytes32(fi.InitialRollingHash).Hex())
+	r.field("FinalRollingHash", types.Bytes32(fi.FinalRollingHash).Hex())
+	r.field("InitialRollingHashNumber", fi.InitialRollingHashNumber)
+	r.field("FinalRollingHashNumber", fi.FinalRollingHashNumber)
+	r.field("ChainID", fi.ChainID)
+	r.field("L2MessageServiceAddr", fi.L2MessageServiceAddr.Hex())
+	if fi.ProverMetadataDigest != nil {
+		r.field("ProverMetadataDigest", utils.HexEncodeToString(fi.ProverMetadataDigest))
+	}
+	r.field("public input", resp.PublicInput)
+
+	if resp.ProverMode != config.ProverModeProofless {
+		r.rawProof("PROOF", circuits.ExecutionCircuitID, resp.Proof)
+	}
+}
+
+func (r *proofReport) aggregation(resp *aggregation.Response) {
+
+	r.section("AGGREGATION RESPONSE")
+	r.field("prover version", resp.AggregatedProverVersion)
+	r.field("verifier index", resp.AggregatedVerifierIndex)
+	r.field("blocks", fmt.Sprintf("%v to %v", resp.LastFinalizedBlockNumber+1, resp.FinalBlockNumber))
+	r.field("data hashes", len(resp.DataHashes))
+
+	r.section("CHECKS")
+
+	var errHex error
+	utils.ValidateHexString(&errHex, resp.FinalShnarf, "final shnarf: %w", 32)
+	utils.ValidateHexString(&errHex, resp.ParentAggregationFinalShnarf, "parent aggregation final shnarf: %w", 32)
+	utils.ValidateHexString(&errHex, resp.ParentStateRootHash, "parent state root hash: %w", 32)
+	utils.ValidateHexString(&errHex, resp.L1RollingHash, "L1 rolling hash: %w", 32)
+	utils.ValidateHexString(&errHex, resp.DataParentHash, "data parent hash: %w", 32)
+	utils.ValidateHexString(&errHex, resp.L2MessagingBlocksOffsets, "L2 messaging blocks offsets: %w", -1)
+	for i := range resp.DataHashes {
+		utils.ValidateHexString(&errHex, resp.DataHashes[i], fmt.Sprintf("data hashes[%d]: ", i)+"%w", 32)
+	}
+	for i := range resp.L2MerkleRoots {
+		utils.ValidateHexString(&errHex, resp.L2MerkleRoots[i], fmt.Sprintf("L2 merkle roots[%d]: ", i)+"%w", 32)
+	}
+	r.check("hex fields", errHex)
+
+	// The public input is reduced modulo the scalar field of the proof
+	// verified on L1.
+	var errPI error
+	if pi, ok := new(big.Int).SetString(resp.AggregatedProofPublicInput, 0); !ok {
+		errPI = fmt.Errorf("could not parse %q as an integer", resp.AggregatedProofPublicInput)
+	} else if pi.Sign() < 0 || pi.Cmp(ecc.BN254.ScalarField()) >= 0 {
+		errPI = fmt.Errorf("%v is not reduced modulo the scalar field of %v", resp.AggregatedProofPublicInput, ecc.BN254)
+	}
+	r.check("public input", errPI)
+
+	var errProofs error
+	if (resp.AggregatedProof == "") == (resp.Bw6Proof == "") {
+		errProofs = errors.New("exactly one of the aggregated and the BW6 proof must be set")
+	}
+	r.check("proofs", errProofs)
+
+	r.section("PUBLIC INPUTS")
+	r.field("ParentAggregationFinalShnarf", resp.ParentAggregationFinalShnarf)
+	r.field("FinalShnarf", resp.FinalShnarf)
+	r.field("ParentStateRootHash", resp.ParentStateRootHash)
+	r.field("ParentAggregationLastBlockTimestamp", resp.ParentAggregationLastBlockTimestamp)
+	r.field("FinalTimestamp", resp.FinalTimestamp)
+	r.field("LastFinalizedBlockNumber", resp.LastFinalizedBlockNumber)
+	r.field("FinalBlockNumber", resp.FinalBlockNumber)
+	r.field("L1RollingHash", resp.L1RollingHash)
+	r.field("L1RollingHashMessageNumber", resp.L1RollingHashMessageNumber)
+	r.field("L2MsgRootHashes", fmt.Sprintf("%v roots", len(resp.L2MerkleRoots)))
+	r.field("L2MsgMerkleTreeDepth", resp.L2MsgTreesDepth)
+	r.field("public input", resp.AggregatedProofPublicInput)
+
+	if resp.AggregatedProof != "" {
+		r.solidityProof("AGGREGATED PROOF", circuits.EmulationCircuitID, resp.AggregatedProof)
+	}
+	if resp.Bw6Proof != "" {
+		r.rawProof("BW6 PROOF", circuits.AggregationCircuitID, resp.Bw6Proof)
+		r.field("circuit ID", resp.Bw6CircuitID)
+	}
+}
+
+// checkL2MessageHashes checks that the L2 message hashes of the response are
+// the ones of its blocks and do not exceed the maximum.
+func checkL2MessageHashes(resp *execution.Response) error {
+
+	var fromBlocks []types.FullBytes32
+	for _, block := range resp.BlocksData {
+		fromBlocks = append(fromBlocks, block.L2ToL1MsgHashes...)
+	}
+
+	if !slices.Equal(fromBlocks, resp.AllL2L1MessageHashes) {
+		return fmt.Errorf("the response lists %v hashes which are not the %v hashes of its blocks", len(resp.AllL2L1MessageHashes), len(fromBlocks))
+	}
+
+	if len(fromBlocks) > resp.MaxNbL2MessageHashes {
+		return fmt.Errorf("%v hashes, the maximum is %v", len(fromBlocks), resp.MaxNbL2MessageHashes)
+	}
+
+	return nil
+}
+
+// checkRollingHashEvents checks that the last rolling hash event of the
+// response is the last one of its blocks.
+func checkRollingHashEvents(resp *execution.Response) error {
+
+	var last bridge.RollingHashUpdated
+	for _, block := range resp.BlocksData {
+		if block.LastRollingHashUpdatedEvent != (bridge.RollingHashUpdated{}) {
+			last = block.LastRollingHashUpdatedEvent
+		}
+	}
+
+	var lastOfResp bridge.RollingHashUpdated
+	if n := len(resp.AllRollingHashEvent); n > 0 {
+		lastOfResp = resp.AllRollingHashEvent[n-1]
+	}
+
+	if last != lastOfResp {
+		return fmt.Errorf("the last event of the blocks is %+v, the one of the response is %+v", last, lastOfResp)
+	}
+
+	return nil
+}
+
+// rawProof prints the components of a PlonK proof serialized with
+// [circuits.SerializeProofRaw] for a proof of the circuit.
+func (r *proofReport) rawProof(title string, id circuits.CircuitID, proofHex string) {
+
+	r.section(title)
+
+	curve, err := circuitCurve(id)
+	if err != nil {
+		r.check("decoding", err)
+		return
+	}
+	r.field("curve", curve)
+
+	b, err := utils.HexDecodeString(proofHex)
+	if err != nil {
+		r.check("decoding", fmt.Errorf("not an hex string: %w", err))
+		return
+	}
+	r.field("size", fmt.Sprintf("%v bytes", len(b)))
+
+	proof := plonk.NewProof(curve)
+	n, err := proof.ReadFrom(bytes.NewReader(b))
+	if err == nil && n != int64(len(b)) {
+		err = fmt.Errorf("%v trailing bytes", int64(len(b))-n)
+	}
+	r.check("decoding", err)
+	if err != nil {
+		return
+	}
+
+	var nbBsb22, nbClaimed int
+	switch p := proof.(type) {
+	case *plonk_bls12377.Proof:
+		nbBsb22, nbClaimed = len(p.Bsb22Commitments), len(p.BatchedProof.ClaimedValues)
+	case *plonk_bw6761.Proof:
+		nbBsb22, nbClaimed = len(p.Bsb22Commitments), len(p.BatchedProof.ClaimedValues)
+	case *plonk_bn254.Proof:
+		nbBsb22, nbClaimed = len(p.Bsb22Commitments), len(p.BatchedProof.ClaimedValues)
+	default:
+		r.check("components", fmt.Errorf("unsupported proof type %T", proof))
+		return
+	}
+
+	r.components(curve, nbBsb22, nbClaimed)
+}
+
+// solidityProofFixedSize is the size of the part of a BN254 PlonK proof
+// serialized for the Solidity verifier which does not depend on the number
+// of BSB22 commitments. Each commitment adds a point and a claimed value.
+const solidityProofFixedSize = 6*64 + 5*32 + 64 + 32 + 2*64
+
+// solidityProof prints the components of a BN254 PlonK proof serialized with
+// [circuits.SerializeProofSolidityBn254] for a proof of the circuit.
+func (r *proofReport) solidityProof(title string, id circuits.CircuitID, proofHex string) {
+
+	r.section(title)
+
+	curve, err := circuitCurve(id)
+	if err != nil {
+		r.check("decoding", err)
+		return
+	}
+	r.field("curve", curve)
+
+	b, err := utils.HexDecodeString(proofHex)
+	if err != nil {
+		r.check("decoding", fmt.Errorf("not an hex string: %w", err))
+		return
+	}
+	r.field("size", fmt.Sprintf("%v bytes", len(b)))
+
+	nbBsb22, rem := (len(b)-solidityProofFixedSize)/(64+32), (len(b)-solidityProofFixedSize)%(64+32)
+	if len(b) < solidityProofFixedSize || rem != 0 {
+		r.check("decoding", fmt.Errorf("%v bytes is not the size of a proof for the solidity verifier", len(b)))
+		return
+	}
+	r.check("decoding", nil)
+
+	// The claimed value of the linearized polynomial is not serialized
+	r.components(curve, nbBsb22, 5+nbBsb22)
+}
+
+// components prints the number of points, of scalars and the size of the
+// components of a PlonK proof, with the points in uncompressed form.
+func (r *proofReport) components(curve ecc.ID, nbBsb22, nbClaimed int) {
+
+	var (
+		pointSize  = 2 * ((curve.BaseField().BitLen() + 7) / 8)
+		scalarSize = (curve.ScalarField().BitLen() + 7) / 8
+	)
+
+	for _, c := range []struct {
+		name            string
+		points, scalars int
+	}{
+		{"wire commitments (LRO)", 3, 0},
+		{"grand product commitment (Z)", 1, 0},
+		{"quotient commitments (H)", 3, 0},
+		{"bsb22 commitments", nbBsb22, 0},
+		{"batched opening at zeta", 1, nbClaimed},
+		{"opening at zeta.omega", 1, 1},
+	} {
+		r.field(c.name, fmt.Sprintf("%v points, %v scalars, %v bytes", c.points, c.scalars, c.points*pointSize+c.scalars*scalarSize))
+	}
+}
+
+// circuitCurve returns the curve of the proofs of the circuit.
+func circuitCurve(id circuits.CircuitID) (ecc.ID, error) {
+	info, ok := circuits.Lookup(id)
+	if !ok {
+		return ecc.UNKNOWN, fmt.Errorf("unknown circuit %v", id)
+	}
+	return info.Curve, nil
+}
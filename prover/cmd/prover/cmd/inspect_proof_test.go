@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectProof(t *testing.T) {
+
+	// The proofs are the ones of the dummy circuits, on the curves of the
+	// execution and of the emulation circuits.
+	srsProvider := circuits.NewUnsafeSRSProvider()
+
+	execSetup, err := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitIDExecution, ecc.BLS12_377.ScalarField())
+	require.NoError(t, err)
+	execProof := dummy.MakeProof(&execSetup, fr377.NewElement(7), circuits.MockCircuitIDExecution)
+
+	emulationSetup, err := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitIDEmulation, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	emulationProof := dummy.MakeProof(&emulationSetup, fr254.NewElement(7), circuits.MockCircuitIDEmulation)
+
+	var (
+		hash32 = "0x" + strings.Repeat("0a", 32)
+		msg    = types.FullBytes32{1}
+		event  = bridge.RollingHashUpdated{MessageNumber: 3, RollingHash: types.FullBytes32{2}}
+	)
+
+	newExecution := func() *execution.Response {
+		resp := &execution.Response{
+			Proof:                execProof,
+			ProverMode:           config.ProverModeFull,
+			VerifyingKeyShaSum:   hash32,
+			ParentStateRootHash:  hash32,
+			Version:              "test",
+			FirstBlockNumber:     10,
+			MaxNbL2MessageHashes: 4,
+			AllRollingHashEvent:  []bridge.RollingHashUpdated{event},
+			AllL2L1MessageHashes: []types.FullBytes32{msg},
+			BlocksData: []execution.BlockData{
+				{TimeStamp: 100, RootHash: types.Bytes32{3}},
+				{TimeStamp: 101, RootHash: types.Bytes32{4}, L2ToL1MsgHashes: []types.FullBytes32{msg}, LastRollingHashUpdatedEvent: event},
+			},
+		}
+		resp.PublicInput = field.HexElement(resp.FuncInput(&config.Config{}).SumAsField())
+		return resp
+	}
+
+	newAggregation := func() *aggregation.Response {
+		return &aggregation.Response{
+			FinalShnarf:                  hash32,
+			ParentAggregationFinalShnarf: hash32,
+			AggregatedProof:              emulationProof,
+			AggregatedProverVersion:      "test",
+			AggregatedProofPublicInput:   "0x1234",
+			DataHashes:                   []string{hash32},
+			DataParentHash:               hash32,
+			ParentStateRootHash:          hash32,
+			LastFinalizedBlockNumber:     9,
+			FinalBlockNumber:             11,
+			L1RollingHash:                hash32,
+			L2MerkleRoots:                []string{hash32},
+			L2MessagingBlocksOffsets:     "0x0001",
+		}
+	}
+
+	testCases := []struct {
+		name string
+		resp any
+		// fails lists the checks expected to fail, nil if the command is
+		// expected to succeed
+		fails []string
+		// contains lists substrings expected in the output
+		contains []string
+	}{
+		{
+			name:     "execution",
+			resp:     newExecution(),
+			contains: []string{"EXECUTION RESPONSE", "blocks", "10 to 11", ecc.BLS12_377.String(), "decoding", "ok"},
+		},
+		{
+			name: "execution-proofless",
+			resp: func() any {
+				resp := newExecution()
+				resp.ProverMode, resp.Proof, resp.VerifyingKeyShaSum = config.ProverModeProofless, "", ""
+				return resp
+			}(),
+			contains: []string{"EXECUTION RESPONSE"},
+		},
+		{
+			name: "execution-wrong-public-input",
+			resp: func() any {
+				resp := newExecution()
+				resp.PublicInput = field.HexElement(field.NewElement(1))
+				return resp
+			}(),
+			fails: []string{"public input"},
+		},
+		{
+			name: "execution-missing-message-hash",
+			resp: func() any {
+				resp := newExecution()
+				resp.BlocksData[1].L2ToL1MsgHashes = nil
+				return resp
+			}(),
+			fails: []string{"L2 message hashes"},
+		},
+		{
+			name: "execution-stale-rolling-hash",
+			resp: func() any {
+				resp := newExecution()
+				resp.BlocksData[1].LastRollingHashUpdatedEvent = bridge.RollingHashUpdated{}
+				return resp
+			}(),
+			fails: []string{"rolling hash events"},
+		},
+		{
+			name: "execution-bad-hex",
+			resp: func() any {
+				resp := newExecution()
+				resp.ParentStateRootHash = "0x1234"
+				return resp
+			}(),
+			fails: []string{"hex fields"},
+		},
+		{
+			name: "execution-truncated-proof",
+			resp: func() any {
+				resp := newExecution()
+				resp.Proof = resp.Proof[:len(resp.Proof)-2]
+				return resp
+			}(),
+			fails: []string{"decoding"},
+		},
+		{
+			name: "execution-no-block",
+			resp: func() any {
+				resp := newExecution()
+				resp.BlocksData = []execution.BlockData{}
+				return resp
+			}(),
+			fails: []string{"blocks"},
+		},
+		{
+			name:     "aggregation",
+			resp:     newAggregation(),
+			contains: []string{"AGGREGATION RESPONSE", "10 to 11", ecc.BN254.String(), "decoding", "ok"},
+		},
+		{
+			name: "aggregation-unreduced-public-input",
+			resp: func() any {
+				resp := newAggregation()
+				resp.AggregatedProofPublicInput = ecc.BN254.ScalarField().String()
+				return resp
+			}(),
+			fails: []string{"public input"},
+		},
+		{
+			name: "aggregation-two-proofs",
+			resp: func() any {
+				resp := newAggregation()
+				resp.Bw6Proof = "0x00"
+				return resp
+			}(),
+			fails: []string{"proofs", "decoding"},
+		},
+		{
+			name: "aggregation-bad-proof-size",
+			resp: func() any {
+				resp := newAggregation()
+				resp.AggregatedProof = resp.AggregatedProof[:len(resp.AggregatedProof)-2]
+				return resp
+			}(),
+			fails: []string{"decoding"},
+		},
+		{
+			name: "aggregation-bad-hex",
+			resp: func() any {
+				resp := newAggregation()
+				resp.DataHashes = append(resp.DataHashes, "0xzz")
+				return resp
+			}(),
+			fails: []string{"hex fields"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			out, err := runInspectProof(t, tc.resp)
+
+			if tc.fails == nil {
+				require.NoError(t, err, out)
+				require.NotContains(t, out, "FAILED")
+			} else {
+				require.Error(t, err)
+				require.Equal(t, len(tc.fails), strings.Count(out, "FAILED"), out)
+				for _, check := range tc.fails {
+					require.Regexp(t, `(?m)^  `+check+` +FAILED`, out)
+				}
+			}
+
+			for _, s := range tc.contains {
+				require.Contains(t, out, s)
+			}
+		})
+	}
+
+	t.Run("unknown-response", func(t *testing.T) {
+		_, err := runInspectProof(t, map[string]string{"foo": "bar"})
+		require.ErrorContains(t, err, "neither an execution nor an aggregation response")
+	})
+}
+
+// runInspectProof writes the response in a file and runs the inspect proof
+// command over it. It returns the output of the command.
+func runInspectProof(t *testing.T, resp any) (string, error) {
+
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "response.json")
+	require.NoError(t, os.WriteFile(path, b, 0600))
+
+	var out bytes.Buffer
+	inspectProofCmd.SetOut(&out)
+	defer inspectProofCmd.SetOut(nil)
+
+	err = cmdInspectProof(inspectProofCmd, []string{path})
+	return out.String(), err
+}
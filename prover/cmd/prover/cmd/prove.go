@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -11,7 +12,12 @@ import (
 	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
 	"github.com/consensys/linea-monorepo/prover/backend/execution"
 	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/backend/schema"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/numa"
+	"github.com/consensys/linea-monorepo/prover/utils/watchdog"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -47,10 +53,26 @@ func cmdProve(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s failed to read config file: %w", cmd.Name(), err)
 	}
 
+	if cfg.Watchdog.Enabled {
+		w := watchdog.New(watchdog.Config{
+			StallTimeout:     cfg.Watchdog.StallTimeout,
+			LongPhaseTimeout: cfg.Watchdog.LongPhaseTimeout,
+			DiagnosticsDir:   cfg.Watchdog.DiagnosticsDir,
+			Job:              fInput,
+		})
+		w.Start()
+		defer w.Stop()
+	}
+
+	if err := numa.Configure(numa.Policy(cfg.Numa.Policy)); err != nil {
+		return fmt.Errorf("%s failed to configure the NUMA placement: %w", cmd.Name(), err)
+	}
+
 	// discover the type of the job from the input file name
 	jobExecution := strings.Contains(fInput, "getZkProof")
 	jobBlobDecompression := strings.Contains(fInput, "getZkBlobCompressionProof")
 	jobAggregation := strings.Contains(fInput, "getZkAggregatedProof")
+	jobMultiAggregation := strings.Contains(fInput, "getZkMultiAggregatedProof")
 
 	if jobExecution {
 		req := &execution.Request{}
@@ -63,6 +85,9 @@ func cmdProve(cmd *cobra.Command, args []string) error {
 		large := fLarge || (strings.Contains(fInput, "large") && cfg.Execution.CanRunFullLarge)
 
 		resp, err := execution.Prove(cfg, req, large)
+		if perr, ok := wizard.IsProverError(err); ok {
+			logProverError(perr)
+		}
 		if err != nil {
 			return fmt.Errorf("could not prove the execution: %w", err)
 		}
@@ -98,10 +123,50 @@ func cmdProve(cmd *cobra.Command, args []string) error {
 		return writeResponse(fOutput, resp)
 	}
 
+	if jobMultiAggregation {
+		req := &aggregation.MultiRequest{}
+		if err := readRequest(fInput, req); err != nil {
+			return fmt.Errorf("could not read the input file (%v): %w", fInput, err)
+		}
+
+		resp, err := aggregation.ProveMulti(cfg, req)
+		if err != nil {
+			return fmt.Errorf("could not prove the multi-aggregation: %w", err)
+		}
+
+		return writeResponse(fOutput, resp)
+	}
+
 	return errors.New("unknown job type")
 }
 
+// logProverError logs the context of a panic of the wizard prover along with
+// the job that triggered it.
+func logProverError(perr *wizard.ProverError) {
+	logrus.WithFields(logrus.Fields{
+		"job":          fInput,
+		"round":        perr.Round,
+		"step":         perr.Step,
+		"module":       perr.Module,
+		"location":     perr.Location,
+		"lastAssigned": perr.LastAssigned,
+	}).Errorf("the wizard prover panicked: %v\n%s", perr.Cause, perr.Stack)
+}
+
+// readRequest reads a request in JSON or, if the path has the
+// [schema.FileExtension] extension, in protobuf.
 func readRequest(path string, into any) error {
+	if strings.HasSuffix(path, schema.FileExtension) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read file: %w", err)
+		}
+		if err := schema.Unmarshal(b, into); err != nil {
+			return fmt.Errorf("could not decode input file: %w", err)
+		}
+		return nil
+	}
+
 	f, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("could not open file: %w", err)
@@ -109,19 +174,33 @@ func readRequest(path string, into any) error {
 	defer f.Close()
 
 	if err := json.NewDecoder(f).Decode(into); err != nil {
-		return fmt.Errorf("could not decode input file: %w", err)
+		return fmt.Errorf("could not decode input file: %w", files.WrapTruncated(err))
 	}
 
 	return nil
 }
 
+// writeResponse writes a response in JSON or, if the path has the
+// [schema.FileExtension] extension, in protobuf. The response is written
+// atomically, see [files.WriteAtomic].
 func writeResponse(path string, from any) error {
-	f := files.MustOverwrite(path)
-	defer f.Close()
+	return files.WriteAtomic(path, func(w io.Writer) error {
+
+		if strings.HasSuffix(path, schema.FileExtension) {
+			b, err := schema.Marshal(from)
+			if err != nil {
+				return fmt.Errorf("could not encode output file: %w", err)
+			}
+			if _, err := w.Write(b); err != nil {
+				return fmt.Errorf("could not write output file: %w", err)
+			}
+			return nil
+		}
 
-	if err := json.NewEncoder(f).Encode(from); err != nil {
-		return fmt.Errorf("could not encode output file: %w", err)
-	}
+		if err := json.NewEncoder(w).Encode(from); err != nil {
+			return fmt.Errorf("could not encode output file: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/backend/replay"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fReplayDir       string
+	fReplaySummary   string
+	fReplayCheckOnly bool
+	fReplayRecord    bool
+	fReplayTracesDir string
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "replay re-runs a corpus of archived requests and compares the public inputs and row counts with the recorded expectations",
+	RunE:  cmdReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringVar(&fReplayDir, "from-dir", "", "directory holding the archived requests")
+	replayCmd.Flags().StringVar(&fReplaySummary, "summary", "", "file where to write the summary of the replay in JSON")
+	replayCmd.Flags().BoolVar(&fReplayCheckOnly, "check-only", false, "stop the jobs once the witness is checked instead of proving")
+	replayCmd.Flags().BoolVar(&fReplayRecord, "record", false, "record the outputs as the new expectations instead of comparing them")
+	replayCmd.Flags().StringVar(&fReplayTracesDir, "traces-dir", "", "directory holding the conflated traces, overrides the one of the config")
+
+	_ = replayCmd.MarkFlagRequired("from-dir")
+}
+
+func cmdReplay(cmd *cobra.Command, args []string) error {
+
+	cfg, err := config.NewConfigFromFile(fConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmd.Name(), err)
+	}
+
+	summary, err := replay.Run(cfg, fReplayDir, replay.Options{
+		CheckOnly: fReplayCheckOnly,
+		Record:    fReplayRecord,
+		TracesDir: fReplayTracesDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	if fReplaySummary != "" {
+		if err := summary.WriteTo(fReplaySummary); err != nil {
+			return fmt.Errorf("could not write the summary: %w", err)
+		}
+	}
+
+	if summary.Failed() {
+		return errors.New("the replay found regressions or failing requests")
+	}
+
+	return nil
+}
@@ -4,12 +4,17 @@ import (
 	"os"
 
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 )
 
 var fConfigFile string
 
+// ProverPanicExitCode is the exit code of the prover when a step of the wizard
+// prover panicked. The context of the failure is logged beforehand.
+const ProverPanicExitCode = 78
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "prover",
@@ -20,6 +25,9 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	if _, ok := wizard.IsProverError(err); ok {
+		os.Exit(ProverPanicExitCode)
+	}
 	if err != nil {
 		os.Exit(1)
 	}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/vortex"
+	"github.com/spf13/cobra"
+)
+
+// selftestCmd represents the selftest command
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "checks that the Vortex commitments computed on this machine match the reference ones",
+	Long: `commits to a fixed pseudo-random matrix with several Vortex parameterizations
+and compares the roots with the golden values embedded in the binary. A
+mismatch means that the arithmetic of this machine, e.g. its assembly or its
+FFTs, differs from the one of the reference machine and that the proofs it
+generates would be invalid.`,
+	RunE: cmdSelfTest,
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+}
+
+func cmdSelfTest(cmd *cobra.Command, args []string) error {
+	if err := vortex.SelfTest(); err != nil {
+		return fmt.Errorf("%s %w", cmd.Name(), err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "vortex self-test passed, %v cases checked\n", len(vortex.SelfTestCases))
+	return nil
+}
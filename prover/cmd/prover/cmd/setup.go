@@ -9,24 +9,23 @@ import (
 	"path/filepath"
 	"strings"
 
-	pi_interconnection "github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection"
-
-	blob_v0 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v0"
-	blob_v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 	"github.com/sirupsen/logrus"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/aggregation"
-	v0 "github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v0"
-	v1 "github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v1"
-	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
 	"github.com/consensys/linea-monorepo/prover/circuits/emulation"
-	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/consensys/linea-monorepo/prover/zkevm"
 	"github.com/spf13/cobra"
+
+	// the circuits register themselves in the circuits registry
+	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression"
+	_ "github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v0"
+	_ "github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v1"
+	_ "github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	_ "github.com/consensys/linea-monorepo/prover/circuits/execution"
+	_ "github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection"
 	"github.com/spf13/viper"
 
 	"github.com/consensys/gnark/backend/plonk"
@@ -46,22 +45,22 @@ var setupCmd = &cobra.Command{
 	RunE:  cmdSetup,
 }
 
-var allCircuits = []string{
-	string(circuits.ExecutionCircuitID),
-	string(circuits.ExecutionLargeCircuitID),
-	string(circuits.BlobDecompressionV0CircuitID),
-	string(circuits.BlobDecompressionV1CircuitID),
-	string(circuits.PublicInputInterconnectionCircuitID),
-	string(circuits.AggregationCircuitID),
-	string(circuits.EmulationCircuitID),
-	string(circuits.EmulationDummyCircuitID), // we want to generate Verifier.sol for this one
+// defaultCircuits returns the registered circuits set up by default
+func defaultCircuits() []string {
+	var res []string
+	for _, info := range circuits.Registered() {
+		if info.DefaultSetup {
+			res = append(res, string(info.ID))
+		}
+	}
+	return res
 }
 
 func init() {
 	rootCmd.AddCommand(setupCmd)
 	setupCmd.Flags().BoolVar(&fForce, "force", false, "overwrites existing files")
-	setupCmd.Flags().StringVar(&fCircuits, "circuits", strings.Join(allCircuits, ","), "comma separated list of circuits to setup")
-	setupCmd.Flags().StringVar(&fDictPath, "dict", "", "path to the dictionary file used in blob (de)compression")
+	setupCmd.Flags().StringVar(&fCircuits, "circuits", strings.Join(defaultCircuits(), ","), "comma separated list of circuits to setup")
+	setupCmd.Flags().StringVar(&fDictPath, "dict", "", "comma separated paths to the dictionary files accepted by the blob decompression circuits, the first one is the default")
 	setupCmd.Flags().StringVar(&fAssetsDir, "assets-dir", "", "path to the directory where the assets are stored (override conf)")
 
 	viper.BindPFlag("assets_dir", setupCmd.Flags().Lookup("assets-dir"))
@@ -74,29 +73,40 @@ func cmdSetup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s failed to read config file: %w", cmd.Name(), err)
 	}
 
+	var dictPaths []string
 	if fDictPath != "" {
-		// fail early if the dictionary file is not found but was specified.
-		if _, err := os.Stat(fDictPath); err != nil {
+		dictPaths = strings.Split(fDictPath, ",")
+	}
+
+	// fail early if a dictionary file is not found but was specified.
+	for _, path := range dictPaths {
+		if _, err := os.Stat(path); err != nil {
 			return fmt.Errorf("%s dictionary file not found: %w", cmd.Name(), err)
 		}
 	}
 
 	// parse inCircuits
 	inCircuits := make(map[circuits.CircuitID]bool)
-	for _, c := range allCircuits {
-		inCircuits[circuits.CircuitID(c)] = false
-	}
-	_inCircuits := strings.Split(fCircuits, ",")
-	for _, c := range _inCircuits {
-		if _, ok := inCircuits[circuits.CircuitID(c)]; !ok {
+	for _, c := range strings.Split(fCircuits, ",") {
+		id, err := circuits.NewCircuitID(c)
+		if err != nil {
+			return fmt.Errorf("%s %w", cmd.Name(), err)
+		}
+		if _, ok := circuits.Lookup(id); !ok {
 			return fmt.Errorf("%s unknown circuit: %s", cmd.Name(), c)
 		}
-		inCircuits[circuits.CircuitID(c)] = true
+		inCircuits[id] = true
 	}
 
 	// create assets dir if needed (example; efs://prover-assets/v0.1.0/)
 	os.MkdirAll(filepath.Join(cfg.AssetsDir, cfg.Version), 0755)
 
+	// the manifests are signed if a key is configured
+	signer, err := circuits.NewManifestSigner(cfg.SetupSigning)
+	if err != nil {
+		return fmt.Errorf("%s failed to create the manifest signer: %w", cmd.Name(), err)
+	}
+
 	// srs provider
 	var srsProvider circuits.SRSProvider
 	srsProvider, err = circuits.NewSRSStore(cfg.PathForSRS())
@@ -104,61 +114,58 @@ func cmdSetup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s failed to create SRS provider: %w", cmd.Name(), err)
 	}
 
+	// the dictionaries are read once, if a circuit needs them
+	var dicts [][]byte
+
 	// for each circuit, we start by compiling the circuit
 	// then we do a sha sum and compare against the one in the manifest.json
-	for c, setup := range inCircuits {
-		if !setup {
-			// we skip aggregation in this first loop since the setup is more complex
+	for _, info := range circuits.Registered() {
+		if !inCircuits[info.ID] || info.NewBuilder == nil {
+			// the circuits depending on other setups, aggregation and
+			// emulation, are handled later.
 			continue
 		}
+		c := info.ID
 		logrus.Infof("setting up %s", c)
 
-		var builder circuits.Builder
-		var dict []byte
-		extraFlags := make(map[string]any)
-
-		// let's compile the circuit.
-		switch c {
-		case circuits.ExecutionCircuitID, circuits.ExecutionLargeCircuitID:
-			limits := cfg.TracesLimits
-			if c == circuits.ExecutionLargeCircuitID {
-				limits = cfg.TracesLimitsLarge
-			}
-			extraFlags["cfg_checksum"] = limits.Checksum()
-			zkEvm := zkevm.FullZkEvm(&limits)
-			builder = execution.NewBuilder(zkEvm)
-		case circuits.BlobDecompressionV0CircuitID, circuits.BlobDecompressionV1CircuitID:
-			dict, err = os.ReadFile(fDictPath)
-			if err != nil {
-				return fmt.Errorf("%s failed to read dictionary file: %w", cmd.Name(), err)
+		var inputs circuits.SetupInputs
+		if info.Needs(circuits.DependencyDictionary) {
+			if dicts == nil {
+				if len(dictPaths) == 0 {
+					return fmt.Errorf("%s %s needs a dictionary, see --dict", cmd.Name(), c)
+				}
+				for _, path := range dictPaths {
+					dict, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("%s failed to read dictionary file: %w", cmd.Name(), err)
+					}
+					dicts = append(dicts, dict)
+				}
 			}
+			inputs.Dicts = dicts
+		}
 
-			if c == circuits.BlobDecompressionV0CircuitID {
-				extraFlags["maxUsableBytes"] = blob_v0.MaxUsableBytes
-				extraFlags["maxUncompressedBytes"] = blob_v0.MaxUncompressedBytes
-				builder = v0.NewBuilder(dict)
-			} else if c == circuits.BlobDecompressionV1CircuitID {
-				extraFlags["maxUsableBytes"] = blob_v1.MaxUsableBytes
-				extraFlags["maxUncompressedBytes"] = blob_v1.MaxUncompressedBytes
-				builder = v1.NewBuilder(len(dict))
-			}
-		case circuits.PublicInputInterconnectionCircuitID:
-			builder = pi_interconnection.NewBuilder(cfg.PublicInputInterconnection)
-		case circuits.EmulationDummyCircuitID:
-			// we can get the Verifier.sol from there.
-			builder = dummy.NewBuilder(circuits.MockCircuitIDEmulation, ecc.BN254.ScalarField())
-		default:
-			continue // dummy, aggregation, emulation or public input circuits are handled later
+		// let's compile the circuit.
+		builder, extraFlags, err := info.NewBuilder(cfg, inputs)
+		if err != nil {
+			return fmt.Errorf("%s failed to create the builder of %s: %w", cmd.Name(), c, err)
 		}
 
-		if err := updateSetup(cmd.Context(), cfg, srsProvider, c, builder, extraFlags); err != nil {
+		if err := updateSetup(cmd.Context(), cfg, srsProvider, signer, c, builder, extraFlags); err != nil {
 			return err
 		}
-		if dict != nil {
-			// we save the dictionary to disk
-			dictPath := filepath.Join(cfg.PathForSetup(string(c)), config.DictionaryFileName)
-			if err := os.WriteFile(dictPath, dict, 0600); err != nil {
-				return fmt.Errorf("%s failed to write dictionary file: %w", cmd.Name(), err)
+		if inputs.Dicts != nil {
+			// we save the dictionaries to disk
+			version, err := blobdecompression.Version(c)
+			if err != nil {
+				return fmt.Errorf("%s %w", cmd.Name(), err)
+			}
+			// the v0 circuit only accepts the default dictionary
+			if version == 0 {
+				inputs.Dicts = inputs.Dicts[:1]
+			}
+			if err := blobdecompression.WriteDictionaries(cfg.PathForSetup(string(c)), version, inputs.Dicts); err != nil {
+				return fmt.Errorf("%s failed to write the dictionaries of %s: %w", cmd.Name(), c, err)
 			}
 		}
 
@@ -176,43 +183,9 @@ func cmdSetup(cmd *cobra.Command, args []string) error {
 	}
 
 	// first, we need to collect the verifying keys
-	var allowedVkForAggregation []plonk.VerifyingKey
-	for _, allowedInput := range cfg.Aggregation.AllowedInputs {
-		// first if it's a dummy circuit, we just run the setup here, we don't need to persist it.
-		if isDummyCircuit(allowedInput) {
-			var curveID ecc.ID
-			var mockID circuits.MockCircuitID
-			switch allowedInput {
-			case string(circuits.ExecutionDummyCircuitID):
-				curveID = ecc.BLS12_377
-				mockID = circuits.MockCircuitIDExecution
-			case string(circuits.BlobDecompressionDummyCircuitID):
-				curveID = ecc.BLS12_377
-				mockID = circuits.MockCircuitIDDecompression
-			case string(circuits.EmulationDummyCircuitID):
-				curveID = ecc.BN254
-				mockID = circuits.MockCircuitIDEmulation
-			default:
-				return fmt.Errorf("unknown dummy circuit: %s", allowedInput)
-			}
-
-			vk, err := getDummyCircuitVK(cmd.Context(), cfg, srsProvider, circuits.CircuitID(allowedInput), dummy.NewBuilder(mockID, curveID.ScalarField()))
-			if err != nil {
-				return err
-			}
-			allowedVkForAggregation = append(allowedVkForAggregation, vk)
-			continue
-		}
-
-		// derive the asset paths
-		setupPath := cfg.PathForSetup(allowedInput)
-		vkPath := filepath.Join(setupPath, config.VerifyingKeyFileName)
-		vk := plonk.NewVerifyingKey(ecc.BLS12_377)
-		if err := circuits.ReadVerifyingKey(vkPath, vk); err != nil {
-			return fmt.Errorf("%s failed to read verifying key for circuit %s: %w", cmd.Name(), allowedInput, err)
-		}
-
-		allowedVkForAggregation = append(allowedVkForAggregation, vk)
+	allowedVkForAggregation, err := collectAllowedVkForAggregation(cmd.Context(), cfg, srsProvider, cfg.Version, cfg.Aggregation.AllowedInputs)
+	if err != nil {
+		return fmt.Errorf("%s %w", cmd.Name(), err)
 	}
 
 	// we need to compute the digest of the verifying keys & store them in the manifest
@@ -222,14 +195,26 @@ func cmdSetup(cmd *cobra.Command, args []string) error {
 		"allowedVkForAggregationDigests": allowedVkForAggregationDigests,
 	}
 
+	// during a prover upgrade window, the aggregation circuit also accepts the
+	// proofs of the inner circuits of the previous prover version.
+	var previousAllowedVkForAggregation []plonk.VerifyingKey
+	if cfg.Aggregation.PreviousVersion != "" {
+		logrus.Infof("collecting the verifying keys of the previous prover version %s", cfg.Aggregation.PreviousVersion)
+		previousAllowedVkForAggregation, err = collectAllowedVkForAggregation(cmd.Context(), cfg, srsProvider, cfg.Aggregation.PreviousVersion, cfg.Aggregation.PreviousAllowedInputs)
+		if err != nil {
+			return fmt.Errorf("%s %w", cmd.Name(), err)
+		}
+		extraFlagsForAggregationCircuit["previousAllowedVkForAggregationDigests"] = listOfCheckum(previousAllowedVkForAggregation)
+	}
+
 	// now for each aggregation circuit, we update the setup if needed, and collect the verifying keys
 	var allowedVkForEmulation []plonk.VerifyingKey
 	for _, numProofs := range cfg.Aggregation.NumProofs {
 		c := circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.AggregationCircuitID), numProofs))
 		logrus.Infof("setting up %s (numProofs=%d)", c, numProofs)
 
-		builder := aggregation.NewBuilder(numProofs, cfg.Aggregation.AllowedInputs, piSetup, allowedVkForAggregation)
-		if err := updateSetup(cmd.Context(), cfg, srsProvider, c, builder, extraFlagsForAggregationCircuit); err != nil {
+		builder := aggregation.NewBuilder(numProofs, cfg.Aggregation.AllowedInputs, piSetup, allowedVkForAggregation, previousAllowedVkForAggregation)
+		if err := updateSetup(cmd.Context(), cfg, srsProvider, signer, c, builder, extraFlagsForAggregationCircuit); err != nil {
 			return err
 		}
 
@@ -248,17 +233,63 @@ func cmdSetup(cmd *cobra.Command, args []string) error {
 	c := circuits.EmulationCircuitID
 	logrus.Infof("setting up %s", c)
 	builder := emulation.NewBuilder(allowedVkForEmulation)
-	return updateSetup(cmd.Context(), cfg, srsProvider, c, builder, nil)
+	if err := updateSetup(cmd.Context(), cfg, srsProvider, signer, c, builder, nil); err != nil {
+		return err
+	}
 
-}
+	// and the multi-proof emulation circuits, wrapping several aggregation
+	// proofs at once
+	for _, numProofs := range cfg.Aggregation.EmulationNumProofs {
+		c := circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.EmulationCircuitID), numProofs))
+		logrus.Infof("setting up %s (numProofs=%d)", c, numProofs)
 
-func isDummyCircuit(cID string) bool {
-	switch circuits.CircuitID(cID) {
-	case circuits.ExecutionDummyCircuitID, circuits.BlobDecompressionDummyCircuitID, circuits.EmulationDummyCircuitID:
-		return true
+		builder := emulation.NewMultiBuilder(allowedVkForEmulation, numProofs)
+		if err := updateSetup(cmd.Context(), cfg, srsProvider, signer, c, builder, nil); err != nil {
+			return err
+		}
 	}
-	return false
 
+	return nil
+}
+
+// collectAllowedVkForAggregation returns the verifying keys of the inner
+// circuits listed in allowedInputs, in the same order. The setups of the
+// non-dummy circuits are read from the assets of the given prover version while
+// the dummy circuits are set up on the fly.
+func collectAllowedVkForAggregation(ctx context.Context, cfg *config.Config, srsProvider circuits.SRSProvider, version string, allowedInputs []string) ([]plonk.VerifyingKey, error) {
+	var allowedVkForAggregation []plonk.VerifyingKey
+	for _, allowedInput := range allowedInputs {
+		// first if it's a dummy circuit, we just run the setup here, we don't need to persist it.
+		info, registered := circuits.Lookup(circuits.CircuitID(allowedInput))
+		if registered && info.Dummy {
+			builder, _, err := info.NewBuilder(cfg, circuits.SetupInputs{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create the builder of %s: %w", allowedInput, err)
+			}
+			vk, err := getDummyCircuitVK(ctx, cfg, srsProvider, info.ID, builder)
+			if err != nil {
+				return nil, err
+			}
+			allowedVkForAggregation = append(allowedVkForAggregation, vk)
+			continue
+		}
+
+		curve := ecc.BLS12_377
+		if registered {
+			curve = info.Curve
+		}
+
+		// derive the asset paths
+		setupPath := cfg.PathForSetupOfVersion(version, allowedInput)
+		vkPath := filepath.Join(setupPath, config.VerifyingKeyFileName)
+		vk := plonk.NewVerifyingKey(curve)
+		if err := circuits.ReadVerifyingKey(vkPath, vk); err != nil {
+			return nil, fmt.Errorf("failed to read verifying key for circuit %s (version %s): %w", allowedInput, version, err)
+		}
+
+		allowedVkForAggregation = append(allowedVkForAggregation, vk)
+	}
+	return allowedVkForAggregation, nil
 }
 
 func getDummyCircuitVK(ctx context.Context, cfg *config.Config, srsProvider circuits.SRSProvider, circuit circuits.CircuitID, builder circuits.Builder) (plonk.VerifyingKey, error) {
@@ -281,7 +312,9 @@ func getDummyCircuitVK(ctx context.Context, cfg *config.Config, srsProvider circ
 // and if so, if the checksums match.
 // if the files already exist and the checksums match, it skips the setup.
 // else it does the setup and writes the assets to disk.
-func updateSetup(ctx context.Context, cfg *config.Config, srsProvider circuits.SRSProvider, circuit circuits.CircuitID, builder circuits.Builder, extraFlags map[string]any) error {
+// if a signer is given, the manifest is signed, including the manifest of a
+// skipped setup if it was not signed by the signer yet.
+func updateSetup(ctx context.Context, cfg *config.Config, srsProvider circuits.SRSProvider, signer circuits.ManifestSigner, circuit circuits.CircuitID, builder circuits.Builder, extraFlags map[string]any) error {
 	if extraFlags == nil {
 		extraFlags = make(map[string]any)
 	}
@@ -309,7 +342,14 @@ func updateSetup(ctx context.Context, cfg *config.Config, srsProvider circuits.S
 
 			if manifest.Checksums.Circuit == circuitDigest {
 				logrus.Infof("skipping %s (already setup)", circuit)
-				return nil
+				if signer == nil || manifest.IsSignedBy(signer.PublicKey()) {
+					return nil
+				}
+				logrus.Infof("signing the manifest of %s", circuit)
+				if err := manifest.Sign(signer); err != nil {
+					return fmt.Errorf("failed to sign the manifest of circuit %s: %w", circuit, err)
+				}
+				return manifest.WriteTo(manifestPath)
 			}
 		}
 	}
@@ -321,6 +361,12 @@ func updateSetup(ctx context.Context, cfg *config.Config, srsProvider circuits.S
 		return fmt.Errorf("failed to setup circuit %s: %w", circuit, err)
 	}
 
+	if signer != nil {
+		if err := setup.Manifest.Sign(signer); err != nil {
+			return fmt.Errorf("failed to sign the manifest of circuit %s: %w", circuit, err)
+		}
+	}
+
 	logrus.Infof("writing assets for %s", circuit)
 	return setup.WriteTo(setupPath)
 }
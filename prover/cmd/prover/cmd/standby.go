@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fStandbyHandoff string
+	fStandbyPoll    time.Duration
+)
+
+// standbyCmd represents the standby command
+var standbyCmd = &cobra.Command{
+	Use:   "standby",
+	Short: "standby warms up an execution prover and waits for a handoff file to take over a job, resuming it from its last checkpoint",
+	RunE:  cmdStandby,
+}
+
+func init() {
+	rootCmd.AddCommand(standbyCmd)
+
+	standbyCmd.Flags().StringVar(&fStandbyHandoff, "handoff", "", "file whose creation hands a job over to the standby prover")
+	standbyCmd.Flags().DurationVar(&fStandbyPoll, "poll", time.Second, "interval at which the handoff file is polled")
+	standbyCmd.Flags().BoolVar(&fLarge, "large", false, "warm up the large execution circuit")
+
+	_ = standbyCmd.MarkFlagRequired("handoff")
+}
+
+// handoff is the content of the handoff file, it describes the job the
+// standby prover takes over. The file is expected to be written atomically.
+type handoff struct {
+	In  string `json:"in"`
+	Out string `json:"out"`
+}
+
+func cmdStandby(cmd *cobra.Command, args []string) error {
+
+	cfg, err := config.NewConfigFromFile(fConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmd.Name(), err)
+	}
+
+	if cfg.Execution.CheckpointDir == "" {
+		logrus.Warn("the checkpoints are disabled, the standby prover will restart the jobs from scratch")
+	}
+
+	start := time.Now()
+	if err := execution.Warmup(cfg, fLarge); err != nil {
+		return fmt.Errorf("%s failed to warm up: %w", cmd.Name(), err)
+	}
+	logrus.Infof("Warmed up in %v, waiting for the handoff file %v", time.Since(start), fStandbyHandoff)
+
+	job, err := waitForHandoff(fStandbyHandoff, fStandbyPoll)
+	if err != nil {
+		return fmt.Errorf("%s failed to read the handoff file: %w", cmd.Name(), err)
+	}
+
+	logrus.Infof("Taking over the job %v", job.In)
+	fInput, fOutput = job.In, job.Out
+	return cmdProve(cmd, args)
+}
+
+// waitForHandoff polls the handoff file until it exists and returns its
+// content.
+func waitForHandoff(path string, poll time.Duration) (handoff, error) {
+
+	for {
+		b, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			time.Sleep(poll)
+			continue
+		}
+		if err != nil {
+			return handoff{}, err
+		}
+
+		var job handoff
+		if err := json.Unmarshal(b, &job); err != nil {
+			return handoff{}, err
+		}
+		if job.In == "" || job.Out == "" {
+			return handoff{}, errors.New("the handoff file must specify the input and the output files")
+		}
+		return job, nil
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/consensys/linea-monorepo/prover/chainspec"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator"
+)
+
+// customNetworkName is the name of the network described by the config alone,
+// when no built-in network is selected.
+const customNetworkName = "custom"
+
+// ChainSpec returns the parameters of the network the prover runs for: the
+// ones of the built-in network selected by layer2.network, overridden by the
+// other settings of the layer2 section.
+func (cfg *Config) ChainSpec() chainspec.Spec {
+
+	base := chainspec.Spec{Name: customNetworkName}
+	if cfg.Layer2.Network != "" {
+		// The network is checked when loading the config
+		if spec, err := chainspec.Lookup(cfg.Layer2.Network); err == nil {
+			base = spec
+		}
+	}
+
+	return base.WithOverrides(chainspec.Overrides{
+		ChainID:          cfg.Layer2.ChainID,
+		L2MessageService: cfg.Layer2.MsgSvcContract,
+		Forks:            forkOverrides(cfg.Layer2.Forks),
+	})
+}
+
+// applyNetworkDefaults fills the settings of the layer2 section left empty
+// with the parameters of the selected built-in network and derives the
+// schedule of the state leaf layouts from its forks if none is configured.
+func applyNetworkDefaults(cfg *Config) error {
+
+	if cfg.Layer2.Network == "" {
+		return nil
+	}
+
+	spec, err := chainspec.Lookup(cfg.Layer2.Network)
+	if err != nil {
+		return fmt.Errorf("layer2.network: %w", err)
+	}
+
+	if cfg.Layer2.ChainID == 0 {
+		cfg.Layer2.ChainID = spec.ChainID
+	}
+
+	if cfg.Layer2.MsgSvcContractStr == "" {
+		cfg.Layer2.MsgSvcContractStr = spec.L2MessageService.Hex()
+	}
+
+	// The layout is derived even though the execution circuit does not prove
+	// it yet: the config is then rejected by [StateLeafLayouts.Validate]
+	// rather than proving the blocks with the wrong layout.
+	spec = spec.WithOverrides(chainspec.Overrides{Forks: forkOverrides(cfg.Layer2.Forks)})
+	if block, ok := spec.Forks.Block(chainspec.ForkStateLeafLayoutV1); ok && len(cfg.Execution.StateLeafLayouts) == 0 {
+		cfg.Execution.StateLeafLayouts = StateLeafLayouts{{FromBlock: block, Layout: accumulator.LeafLayoutV1}}
+	}
+
+	return nil
+}
+
+// forkOverrides converts the forks of the layer2 section, sorted by name so
+// that the result does not depend on the iteration order of the map.
+func forkOverrides(forks map[string]uint64) chainspec.Forks {
+	res := make(chainspec.Forks, 0, len(forks))
+	for name, block := range forks {
+		res = append(res, chainspec.Fork{Name: name, Block: block})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
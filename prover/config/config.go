@@ -1,11 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"text/template"
+	"time"
 
+	"github.com/consensys/linea-monorepo/prover/crypto/ringsis"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
@@ -23,17 +27,31 @@ func NewConfigFromFile(path string) (*Config, error) {
 		return nil, err
 	}
 
+	// Refuse the files written for another schema, their keys would be
+	// rejected or misinterpreted.
+	if err := checkSchemaVersion(viper.GetViper()); err != nil {
+		return nil, err
+	}
+
 	// Set the default values
-	setDefaultValues()
+	setDefaultValues(viper.GetViper())
 
 	// Unmarshal the config; note that UnmarshalExact will error if there are any fields in the config
 	// that are not present in the struct.
 	var cfg Config
 	err = viper.UnmarshalExact(&cfg)
+	if err != nil && !viper.IsSet("schema_version") {
+		return nil, fmt.Errorf("%w (if the file predates the schema version %v, see `prover config migrate`)", err, SchemaVersion)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	// Fill the parameters of the network left empty
+	if err := applyNetworkDefaults(&cfg); err != nil {
+		return nil, err
+	}
+
 	// Validate the config
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	if err = validate.RegisterValidation("power_of_2", validateIsPowerOfTwo); err != nil {
@@ -44,6 +62,28 @@ func NewConfigFromFile(path string) (*Config, error) {
 		return nil, err
 	}
 
+	sis := cfg.Execution.SIS.Params()
+	if err := sis.Validate(); err != nil {
+		return nil, fmt.Errorf("execution.sis: %w", err)
+	}
+
+	if err := cfg.Execution.StateLeafLayouts.Validate(); err != nil {
+		return nil, fmt.Errorf("execution.state_leaf_layouts: %w", err)
+	}
+
+	// The aggregation would accept the proofs of the previous version that
+	// the public-input circuit then rejects for their prover metadata.
+	if cfg.PublicInputInterconnection.ProverMetadata && cfg.Aggregation.PreviousVersion != "" {
+		return nil, errors.New("public_input_interconnection.prover_metadata cannot be set along with aggregation.previous_version")
+	}
+
+	if len(cfg.Aggregation.EmulationVerifierIDs) != len(cfg.Aggregation.EmulationNumProofs) {
+		return nil, fmt.Errorf(
+			"aggregation.emulation_verifier_ids has %v entries, but aggregation.emulation_num_proofs has %v",
+			len(cfg.Aggregation.EmulationVerifierIDs), len(cfg.Aggregation.EmulationNumProofs),
+		)
+	}
+
 	// Ensure cmdTmpl and cmdLargeTmpl are parsed
 	cfg.Controller.WorkerCmdTmpl, err = template.New("worker_cmd").Parse(cfg.Controller.WorkerCmd)
 	if err != nil {
@@ -85,6 +125,11 @@ func validateIsPowerOfTwo(f validator.FieldLevel) bool {
 // TODO @gbotrel add viper hook to decode custom types (instead of having duplicate string and custom type.)
 
 type Config struct {
+	// SchemaVersion is the version of the schema of the config file, see
+	// [SchemaVersion]. The files that do not set it are assumed to follow the
+	// current schema.
+	SchemaVersion int `mapstructure:"schema_version"`
+
 	// Environment stores the environment in which the application is running.
 	// It enables us have a clear domain separation for generated assets.
 	Environment string `validate:"required,oneof=mainnet sepolia devnet integration-development integration-full integration-benchmark"`
@@ -108,15 +153,61 @@ type Config struct {
 
 	Debug struct {
 		// Profiling indicates whether we want to generate profiles using the [runtime/pprof] pkg.
-		// Profiles can later be read using the `go tool pprof` command.
+		// Profiles can later be read using the `go tool pprof` command. The
+		// execution prover also reports the memory used by every round of
+		// the inner-prover.
 		Profiling bool `mapstructure:"profiling"`
 
 		// Tracing indicates whether we want to generate traces using the [runtime/trace] pkg.
 		// Traces can later be read using the `go tool trace` command.
 		Tracing bool `mapstructure:"tracing"`
+
+		// DebugOpeningsDir, if set, makes the execution prover retain the
+		// assignment of the columns of the inner-proof and write them in
+		// this directory, in one file per job. They are written before the
+		// inner-proof is checked, so that the columns of a rejected proof
+		// can be opened with `prover inspect openings`.
+		DebugOpeningsDir string `mapstructure:"debug_openings_dir"`
+
+		// DebugOpeningsColumns restricts the retained columns. If empty, all
+		// the columns are retained, which keeps the whole witness in memory.
+		DebugOpeningsColumns []string `mapstructure:"debug_openings_columns"`
+
+		// CleanUpLevel selects which of the columns no longer needed by the
+		// execution prover are freed during the proving: "none", "safe" to
+		// keep the precomputed columns, or "aggressive" (default) to free
+		// all of them. The columns listed in DebugOpeningsColumns are never
+		// freed. The level does not change the setup.
+		CleanUpLevel string `mapstructure:"clean_up_level" validate:"omitempty,oneof=none safe aggressive"`
 	}
 
+	// Watchdog configures the watchdog aborting the proving jobs that stop
+	// making progress. See the utils/watchdog package.
+	Watchdog Watchdog
+
+	// Numa configures the placement of the prover workers and of its large
+	// buffers on multi-socket machines. See the utils/numa package.
+	Numa Numa
+
+	// Solver configures the gnark witness solver of the outer circuits. See
+	// SolverOptions in the circuits package.
+	Solver Solver
+
+	// SetupSigning configures the signature of the setup manifests by the
+	// setup command and their verification when the prover loads a setup.
+	SetupSigning SetupSigning `mapstructure:"setup_signing"`
+
 	Layer2 struct {
+		// Network selects a built-in network whose parameters are used for the
+		// settings of this section left empty, see [Config.ChainSpec]. If it
+		// is empty, e.g. for a devnet, the chain ID and the message service
+		// contract must be set.
+		Network string `mapstructure:"network"`
+
+		// Forks overrides the activation blocks of the forks of the network,
+		// by name of fork, e.g. "state_leaf_layout_v1".
+		Forks map[string]uint64 `mapstructure:"forks"`
+
 		// ChainID stores the ID of the Linea L2 network to consider.
 		ChainID uint `mapstructure:"chain_id" validate:"required"`
 
@@ -142,7 +233,15 @@ func (cfg *Config) Logger() *logrus.Logger {
 // PathForSetup returns the path to the setup directory for the given circuitID.
 // e.g. .../prover-assets/0.1.0/mainnet/execution
 func (cfg *Config) PathForSetup(circuitID string) string {
-	return path.Join(cfg.AssetsDir, cfg.Version, cfg.Environment, circuitID)
+	return cfg.PathForSetupOfVersion(cfg.Version, circuitID)
+}
+
+// PathForSetupOfVersion returns the path to the setup directory for the given
+// circuitID and prover version. It is used to locate the assets of the previous
+// prover version during an upgrade window.
+// e.g. .../prover-assets/0.0.9/mainnet/execution
+func (cfg *Config) PathForSetupOfVersion(version, circuitID string) string {
+	return path.Join(cfg.AssetsDir, version, cfg.Environment, circuitID)
 }
 
 // PathForSRS returns the path to the SRS directory.
@@ -159,6 +258,11 @@ type Controller struct {
 	// Prometheus stores the configuration for the Prometheus metrics server.
 	Prometheus Prometheus
 
+	// ArtifactServer stores the configuration of the server transferring the
+	// requests and the responses in resumable chunks, see
+	// [github.com/consensys/linea-monorepo/prover/backend/artifacts].
+	ArtifactServer ArtifactServer `mapstructure:"artifact_server"`
+
 	// The delays at which we retry when we find no files in the queue. If this
 	// is set to [0, 1, 2, 3, 4, 5]. It will retry after 0 sec the first time it
 	// cannot find a file in the queue, 1 sec the second time and so on. Once it
@@ -176,6 +280,34 @@ type Controller struct {
 	EnableBlobDecompression bool `mapstructure:"enable_blob_decompression"`
 	EnableAggregation       bool `mapstructure:"enable_aggregation"`
 
+	// SelfTest indicates whether the controller should check the prover
+	// assets before accepting jobs: the setups of the enabled circuits must be
+	// readable and match the SRS, and a dummy proof is generated and verified
+	// on every curve in use. Defaults to false.
+	SelfTest bool `mapstructure:"self_test"`
+
+	// MaxRequestSize is the maximal size in bytes of the request files that
+	// the controller accepts to process. Larger requests are not proven and
+	// are moved to the done directory with the exit code 17. This protects a
+	// shared prover fleet from requests that are too large to be handled.
+	// Defaults to 0, meaning that there is no limit.
+	MaxRequestSize int64 `mapstructure:"max_request_size" validate:"gte=0"`
+
+	// MaxConcurrentJobs caps, by job type ("execution", "compression",
+	// "aggregation" or "multi-aggregation"), the number of jobs in progress
+	// on all the workers sharing the queue. The controller does not pick a
+	// job of a type that reached its limit. The jobs locked by a worker that
+	// crashed count until their file is moved back. A missing or zero entry
+	// means that there is no limit.
+	MaxConcurrentJobs map[string]int `mapstructure:"max_concurrent_jobs" validate:"dive,keys,oneof=execution compression aggregation multi-aggregation,endkeys,gte=0"`
+
+	// MaxQueuedJobs caps, by job type, the number of requests waiting in the
+	// queue. The requests beyond the limit, starting from the ones with the
+	// lowest priority, are rejected: they are moved to the done directory
+	// with the exit code 19 so that they can be submitted again later. A
+	// missing or zero entry means that there is no limit.
+	MaxQueuedJobs map[string]int `mapstructure:"max_queued_jobs" validate:"dive,keys,oneof=execution compression aggregation multi-aggregation,endkeys,gte=0"`
+
 	// TODO @gbotrel the only reason we keep these is for test purposes; default value is fine,
 	// we should remove them from here for readability.
 	WorkerCmd          string             `mapstructure:"worker_cmd_tmpl"`
@@ -184,6 +316,72 @@ type Controller struct {
 	WorkerCmdLargeTmpl *template.Template `mapstructure:"-"`
 }
 
+type Watchdog struct {
+	// Enabled indicates whether the prover should run a watchdog. Defaults to
+	// false.
+	Enabled bool
+
+	// StallTimeout is the maximal duration during which a proving phase may
+	// not report any progress before the job is aborted. Defaults to 30m.
+	StallTimeout time.Duration `mapstructure:"stall_timeout"`
+
+	// LongPhaseTimeout is the maximal duration of the phases which cannot
+	// report their progress, such as the gnark proof generation. Defaults to
+	// 3h.
+	LongPhaseTimeout time.Duration `mapstructure:"long_phase_timeout"`
+
+	// DiagnosticsDir is the directory in which the diagnostics (goroutine
+	// stacks, phase history) are dumped when a job is aborted. Defaults to
+	// /shared/prover-diagnostics.
+	DiagnosticsDir string `mapstructure:"diagnostics_dir"`
+}
+
+type Numa struct {
+	// Policy is either "none" (default), "local" to pin the workers on the
+	// NUMA nodes or "interleave" to only spread the large shared buffers
+	// across the nodes. Both "local" and "interleave" interleave the shared
+	// buffers. The setting is ignored on single-node machines.
+	Policy string `mapstructure:"policy" validate:"omitempty,oneof=none local interleave"`
+}
+
+type Solver struct {
+	// NbTasks is the number of goroutines used by gnark to solve the witness
+	// of the circuits. Defaults to 0, meaning one per CPU.
+	NbTasks int `mapstructure:"nb_tasks" validate:"gte=0,lte=512"`
+
+	// ProfileHints indicates whether the time spent in each hint is collected
+	// while solving the witness and the slowest hints reported in the logs.
+	// Defaults to false.
+	ProfileHints bool `mapstructure:"profile_hints"`
+
+	// TopHints is the number of hints reported when ProfileHints is set.
+	// Defaults to 10.
+	TopHints int `mapstructure:"top_hints" validate:"gte=0"`
+}
+
+type SetupSigning struct {
+	// KeyFile is the path to the PEM-encoded PKCS#8 private key, ed25519 or
+	// ECDSA P-256, with which the setup command signs the manifests.
+	KeyFile string `mapstructure:"key_file" validate:"excluded_with=KMSCommand"`
+
+	// KMSCommand is the command with which the setup command signs the
+	// manifests when the key is held by a KMS. The command receives the
+	// payload to sign on its stdin and writes the raw signature on its stdout:
+	// the ed25519 signature of the payload or the ASN.1 ECDSA signature of
+	// its SHA-256 digest.
+	KMSCommand []string `mapstructure:"kms_command"`
+
+	// KMSPublicKeyFile is the path to the PEM-encoded PKIX public key of the
+	// KMS key. It identifies the key in the manifests.
+	KMSPublicKeyFile string `mapstructure:"kms_public_key_file" validate:"required_with=KMSCommand"`
+
+	// TrustedKeys lists the paths to the PEM-encoded PKIX public keys trusted
+	// by the prover. When it is non-empty, the prover refuses to load a setup
+	// whose manifest is not signed by one of them or whose circuit does not
+	// match the manifest.
+	TrustedKeys []string `mapstructure:"trusted_keys"`
+}
+
 type Prometheus struct {
 	Enabled bool
 	// The underlying implementation defaults to :9090.
@@ -194,6 +392,30 @@ type Prometheus struct {
 	Route string
 }
 
+type ArtifactServer struct {
+	// Enabled indicates whether the controller serves the requests and the
+	// responses of the enabled jobs. Defaults to false.
+	Enabled bool
+	// BindAddress is the address of the interface the server listens on.
+	// Defaults to 127.0.0.1, so that exposing the server is a deliberate
+	// choice.
+	BindAddress string `mapstructure:"bind_address"`
+	// Port of the server. Defaults to 9091.
+	Port int `validate:"gte=0,lt=65536"`
+	// ChunkSize is the size in bytes of the chunks in which the files are
+	// transferred. Defaults to 8MiB.
+	ChunkSize int64 `mapstructure:"chunk_size" validate:"gte=0"`
+	// TokenFile is the path to the file holding the bearer token the clients
+	// must present. Required when the server is enabled.
+	TokenFile string `mapstructure:"token_file" validate:"required_if=Enabled true"`
+	// UploadTTL is the time after which an upload which does not progress is
+	// removed. Defaults to 24h.
+	UploadTTL time.Duration `mapstructure:"upload_ttl" validate:"gte=0"`
+	// MaxCachedManifests bounds the number of manifests of the served files
+	// kept in memory. Defaults to 1024.
+	MaxCachedManifests int `mapstructure:"max_cached_manifests" validate:"gte=0"`
+}
+
 type Execution struct {
 	WithRequestDir `mapstructure:",squash"`
 
@@ -205,6 +427,108 @@ type Execution struct {
 
 	// ConflatedTracesDir stores the directory where the conflation traces are stored.
 	ConflatedTracesDir string `mapstructure:"conflated_traces_dir" validate:"required"`
+
+	// SIS sets the ring-SIS instance used by the Vortex commitments of the
+	// full prover. It defaults to [ringsis.StdParams]. Changing it changes
+	// the setup of the execution circuits, see [Execution.SetupChecksum].
+	SIS SIS `mapstructure:"sis"`
+
+	// StateLeafLayouts schedules the upgrades of the layout of the leaves of
+	// the state tries. The blocks before the first upgrade use the original
+	// layout, which is the only one the execution circuit proves for now.
+	StateLeafLayouts StateLeafLayouts `mapstructure:"state_leaf_layouts"`
+
+	// CheckpointDir is a directory, shared with the standby provers, where
+	// the prover saves the state of the inner proof at the end of every
+	// round. A standby prover taking over a job resumes it from there. Empty
+	// disables the checkpoints.
+	CheckpointDir string `mapstructure:"checkpoint_dir"`
+}
+
+// StateLeafLayout activates a layout of the leaves of the state tries from a
+// block on, see [github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator.LeafLayoutV1].
+type StateLeafLayout struct {
+	FromBlock uint64 `mapstructure:"from_block"`
+	Layout    uint8  `mapstructure:"layout"`
+}
+
+// StateLeafLayouts is a schedule of upgrades of the leaf layout
+type StateLeafLayouts []StateLeafLayout
+
+// maxProvableStateLeafLayout is the latest leaf layout the state-manager
+// module of the execution circuit arithmetizes. The accumulator of the crypto
+// package supports the later ones, but the prover would not be able to prove
+// the blocks using them.
+const maxProvableStateLeafLayout = accumulator.LeafLayoutV0
+
+// Validate returns an error if the schedule activates a layout the execution
+// circuit cannot prove.
+func (s StateLeafLayouts) Validate() error {
+	for _, l := range s {
+		if l.Layout > maxProvableStateLeafLayout {
+			return fmt.Errorf(
+				"the leaf layout %v, activated at block %v, is not supported by the execution circuit (latest: %v)",
+				l.Layout, l.FromBlock, maxProvableStateLeafLayout,
+			)
+		}
+	}
+	return nil
+}
+
+// At returns the layout of the leaves of the state tries for the block: the
+// one of the latest upgrade activated at or before it. If two upgrades are
+// activated at the same block, the last one listed prevails.
+func (s StateLeafLayouts) At(block uint64) uint8 {
+	var (
+		res  uint8
+		from uint64
+	)
+	for _, l := range s {
+		if l.FromBlock <= block && l.FromBlock >= from {
+			res, from = l.Layout, l.FromBlock
+		}
+	}
+	return res
+}
+
+// SIS holds the parameters of a ring-SIS instance, see [ringsis.Params]
+type SIS struct {
+	LogTwoBound  int `mapstructure:"log_two_bound"`
+	LogTwoDegree int `mapstructure:"log_two_degree"`
+}
+
+// Params returns the ring-SIS parameters
+func (s SIS) Params() ringsis.Params {
+	return ringsis.Params{LogTwoBound: s.LogTwoBound, LogTwoDegree: s.LogTwoDegree}
+}
+
+// SetupChecksum returns the checksum identifying the setup of an execution
+// circuit compiled for the limits. It covers the SIS parameters when they
+// differ from [ringsis.StdParams] so that the assets cannot silently diverge
+// from the config. They are omitted otherwise so that the checksum remains
+// the one of [TracesLimits.Checksum] and the existing setups remain valid.
+func (e *Execution) SetupChecksum(limits *TracesLimits) string {
+	return e.VersionedSetupChecksum(limits, ChecksumLegacy)
+}
+
+// VersionedSetupChecksum returns the checksum of [Execution.SetupChecksum] in
+// the requested version.
+func (e *Execution) VersionedSetupChecksum(limits *TracesLimits, version ChecksumVersion) string {
+
+	var sis *SIS
+	if e.SIS.Params() != ringsis.StdParams {
+		sis = &e.SIS
+	}
+
+	// The precompiles disabled by a zero limit are listed along the ones
+	// disabled explicitly, as their modules are left out of the circuit.
+	normalized := *limits
+	normalized.DisabledPrecompiles = limits.EffectiveDisabledPrecompiles()
+
+	return checksumJSON(struct {
+		*TracesLimits
+		SIS *SIS `json:",omitempty"`
+	}{&normalized, sis}, version)
 }
 
 type BlobDecompression struct {
@@ -227,12 +551,43 @@ type Aggregation struct {
 	// Order matters.
 	AllowedInputs []string `mapstructure:"allowed_inputs" validate:"required,dive,oneof=execution-dummy execution execution-large blob-decompression-dummy blob-decompression-v0 blob-decompression-v1"`
 
+	// PreviousVersion optionally sets the version of the previous prover
+	// release. When set, the aggregation circuit also accepts the inner proofs
+	// generated by the circuits listed in PreviousAllowedInputs, whose setup is
+	// read from the assets of that version. This is meant to be used during a
+	// prover upgrade window, so that proofs from the old and the new circuits
+	// can be aggregated in the same batch.
+	PreviousVersion string `mapstructure:"previous_version" validate:"omitempty,semver"`
+
+	// PreviousAllowedInputs lists the inner circuits of PreviousVersion that the
+	// aggregation circuit can aggregate. It is ignored if PreviousVersion is not
+	// set. Order matters.
+	PreviousAllowedInputs []string `mapstructure:"previous_allowed_inputs" validate:"required_with=PreviousVersion,dive,oneof=execution-dummy execution execution-large blob-decompression-dummy blob-decompression-v0 blob-decompression-v1"`
+
+	// InnerProofWorkers bounds the number of inner proofs that are natively
+	// verified and emulated in parallel when assigning the aggregation
+	// circuit. Each worker holds an emulated proof, so this caps the memory
+	// used by the witness preparation. Defaults to the number of CPUs.
+	InnerProofWorkers int `mapstructure:"inner_proof_workers" validate:"gte=0"`
+
 	// note @gbotrel keeping that around in case we need to support two emulation contract
 	// during a migration.
 	// Verifier ID to assign to the proof once generated. It will be used
 	// by the L1 contracts to determine which solidity Plonk verifier
 	// contract should be used to verify the proof.
 	VerifierID int `mapstructure:"verifier_id" validate:"gte=0,number"`
+
+	// EmulationNumProofs lists the numbers of aggregation proofs, beyond one,
+	// that the final (emulation) circuit can wrap in a single BN254 proof. A
+	// setup is generated for each of them. Wrapping several aggregations,
+	// covering consecutive block ranges, reduces the number of finalization
+	// transactions on L1 during catch-up periods.
+	EmulationNumProofs []int `mapstructure:"emulation_num_proofs" validate:"dive,gt=1,number"`
+
+	// EmulationVerifierIDs are the verifier IDs to assign to the proofs of the
+	// multi-proof emulation circuits. They are given in the same order as
+	// EmulationNumProofs.
+	EmulationVerifierIDs []int `mapstructure:"emulation_verifier_ids" validate:"dive,gte=0,number"`
 }
 
 type WithRequestDir struct {
@@ -259,4 +614,30 @@ type PublicInput struct {
 	L2MsgMerkleDepth   int  `mapstructure:"l2_msg_merkle_depth" validate:"gte=0"`
 	L2MsgMaxNbMerkle   int  `mapstructure:"l2_msg_max_nb_merkle" validate:"gte=0"` // if not explicitly provided (i.e. non-positive) it will be set to maximum
 	MockKeccakWizard   bool // for testing purposes only
+	// ProverMetadata binds the execution proofs to the version of the prover
+	// and to the compilation suite that produced them, by checking the digest
+	// included in their functional public inputs. It requires the execution
+	// circuits to be compiled in the matching mode.
+	//
+	// Only the digest of the current version is accepted: every change of
+	// the version rejects the execution proofs of the previous one. They must
+	// be aggregated, or re-proven, before the aggregation is upgraded. For
+	// the same reason, it cannot be combined with
+	// [Aggregation.PreviousVersion].
+	ProverMetadata bool `mapstructure:"prover_metadata"`
+	// ProverMetadataDigest is the digest expected when ProverMetadata is set.
+	// It is derived from the rest of the config rather than read from the
+	// config file, see pi_interconnection.PublicInputConfig.
+	ProverMetadataDigest []byte `mapstructure:"-"`
+	// TracesLimitsBinding binds the execution proofs to the traces limits
+	// they were proven with, by checking the digest included in their
+	// functional public inputs against the ones of the traces_limits and
+	// traces_limits_large sections. The verifying key of the aggregation thus
+	// pins the limit profiles accepted on-chain. It requires the execution
+	// circuits to be compiled in the matching mode.
+	TracesLimitsBinding bool `mapstructure:"traces_limits_binding"`
+	// TracesLimitsDigests are the digests accepted when TracesLimitsBinding
+	// is set. They are derived from the rest of the config rather than read
+	// from the config file, see pi_interconnection.PublicInputConfig.
+	TracesLimitsDigests [][]byte `mapstructure:"-"`
 }
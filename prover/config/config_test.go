@@ -4,7 +4,10 @@ import (
 	"os"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/consensys/linea-monorepo/prover/chainspec"
+	"github.com/consensys/linea-monorepo/prover/crypto/ringsis"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
@@ -45,8 +48,134 @@ func TestEnvironment(t *testing.T) {
 
 			// check that the environment is set
 			assert.Equal(matches[1], config.Environment)
+
+			// check that the default durations are parsed
+			assert.Equal(30*time.Minute, config.Watchdog.StallTimeout)
 		})
 	}
 
 	assert.NotEqual(0, count, "no config file found")
 }
+
+func TestExecutionSIS(t *testing.T) {
+	assert := require.New(t)
+
+	viper.Set("assets_dir", "../prover-assets")
+	cfg, err := NewConfigFromFile("config-integration-full.toml")
+	assert.NoError(err)
+	assert.Equal(ringsis.StdParams, cfg.Execution.SIS.Params())
+
+	// The standard parameters do not alter the checksum of the limits
+	limits := &cfg.TracesLimits
+	assert.Equal(limits.Checksum(), cfg.Execution.SetupChecksum(limits))
+
+	exec := cfg.Execution
+	exec.SIS.LogTwoDegree = 7
+	assert.NotEqual(limits.Checksum(), exec.SetupChecksum(limits))
+
+	// A zero limit disables the module of the precompile: the setup checksum
+	// is the one of the limits disabling the precompile explicitly.
+	disabled := *limits
+	disabled.PrecompileSha2Blocks = 0
+	assert.NotEqual(limits.Checksum(), cfg.Execution.SetupChecksum(&disabled))
+	disabled.DisabledPrecompiles = []string{PrecompileSha2}
+	assert.Equal(disabled.Checksum(), cfg.Execution.SetupChecksum(&disabled))
+
+	// Unsafe parameters are rejected
+	viper.Set("execution.sis.log_two_bound", 32)
+	defer viper.Set("execution.sis.log_two_bound", ringsis.StdParams.LogTwoBound)
+	_, err = NewConfigFromFile("config-integration-full.toml")
+	assert.ErrorContains(err, "execution.sis")
+}
+
+func TestStateLeafLayouts(t *testing.T) {
+
+	var none StateLeafLayouts
+	require.Equal(t, uint8(0), none.At(1000))
+
+	layouts := StateLeafLayouts{
+		{FromBlock: 200, Layout: 2},
+		{FromBlock: 100, Layout: 1},
+	}
+
+	require.Equal(t, uint8(0), layouts.At(99))
+	require.Equal(t, uint8(1), layouts.At(100))
+	require.Equal(t, uint8(1), layouts.At(199))
+	require.Equal(t, uint8(2), layouts.At(200))
+	require.Equal(t, uint8(2), layouts.At(1<<40))
+
+	require.NoError(t, none.Validate())
+	require.NoError(t, StateLeafLayouts{{FromBlock: 100, Layout: 0}}.Validate())
+	require.Error(t, layouts.Validate())
+}
+
+func TestChainSpec(t *testing.T) {
+	assert := require.New(t)
+
+	viper.Set("assets_dir", "../prover-assets")
+	cfg, err := NewConfigFromFile("config-integration-full.toml")
+	assert.NoError(err)
+
+	// The parameters are the ones of the built-in network
+	assert.Equal(chainspec.Mainnet.ChainID, cfg.Layer2.ChainID)
+	assert.Equal(chainspec.Mainnet.L2MessageService, cfg.Layer2.MsgSvcContract)
+	assert.Equal(chainspec.Mainnet, cfg.ChainSpec())
+	assert.Empty(cfg.Execution.StateLeafLayouts)
+
+	// The settings of the section override them
+	viper.Set("layer2.chain_id", 1337)
+	viper.Set("layer2.forks", map[string]uint64{"some_fork": 100})
+	defer viper.Set("layer2.chain_id", nil)
+	defer viper.Set("layer2.forks", nil)
+
+	cfg, err = NewConfigFromFile("config-integration-full.toml")
+	assert.NoError(err)
+	assert.Equal(uint(1337), cfg.ChainSpec().ChainID)
+	assert.Equal(chainspec.Mainnet.L2MessageService, cfg.ChainSpec().L2MessageService)
+	assert.True(cfg.ChainSpec().Forks.IsActive("some_fork", 100))
+
+	// The execution circuit does not prove the versioned leaf layout yet, so
+	// scheduling its fork is rejected instead of proving with the wrong one.
+	viper.Set("layer2.forks", map[string]uint64{chainspec.ForkStateLeafLayoutV1: 100})
+	_, err = NewConfigFromFile("config-integration-full.toml")
+	assert.ErrorContains(err, "execution.state_leaf_layouts")
+
+	// Unknown networks are rejected
+	viper.Set("layer2.network", "devnet")
+	defer viper.Set("layer2.network", chainspec.MainnetName)
+	_, err = NewConfigFromFile("config-integration-full.toml")
+	assert.ErrorContains(err, "layer2.network")
+}
+
+func TestProverMetadataPreviousVersion(t *testing.T) {
+	assert := require.New(t)
+
+	viper.Set("assets_dir", "../prover-assets")
+	viper.Set("public_input_interconnection.prover_metadata", true)
+	defer viper.Set("public_input_interconnection.prover_metadata", nil)
+
+	_, err := NewConfigFromFile("config-integration-full.toml")
+	assert.NoError(err)
+
+	// The proofs of the previous version carry another prover metadata
+	// digest, the public-input circuit would reject them.
+	viper.Set("aggregation.previous_version", "0.0.1")
+	viper.Set("aggregation.previous_allowed_inputs", []string{"execution"})
+	defer viper.Set("aggregation.previous_version", nil)
+	defer viper.Set("aggregation.previous_allowed_inputs", nil)
+
+	_, err = NewConfigFromFile("config-integration-full.toml")
+	assert.ErrorContains(err, "aggregation.previous_version")
+}
+
+func TestSetupChecksumLocked(t *testing.T) {
+
+	// The setup checksums identify the setups, in both versions
+	var (
+		limits = TracesLimits{Add: 1 << 10, Bin: 1 << 12, BlockKeccak: 8192, BlockGasLimit: 2_000_000_000}
+		exec   = Execution{SIS: SIS{LogTwoBound: 8, LogTwoDegree: 7}}
+	)
+
+	require.Equal(t, "0x8b4190eb992da51579a4634081269444bef3d0465e4af182ef92ec0dcc197c92", exec.SetupChecksum(&limits))
+	require.Equal(t, "0x8b0a5b6c46f05f0b71ea288699787fe7b49d88231c07c3ae7e2f3cde8b031b87", exec.VersionedSetupChecksum(&limits, ChecksumCanonical))
+}
@@ -6,6 +6,7 @@ const (
 	VerifierContractFileName = "Verifier.sol"
 	ManifestFileName         = "manifest.json"
 	DictionaryFileName       = "compressor_dict.bin"
+	DictionariesDirName      = "dictionaries"
 
 	RequestsFromSubDir = "requests"
 	RequestsToSubDir   = "responses"
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
+)
+
+// SchemaVersion is the version of the schema of the config files supported by
+// this prover. It is bumped on every breaking change of the schema, along
+// with the migration rewriting the files of the previous version, see
+// [Migrate].
+const SchemaVersion = 3
+
+// schemaMigration rewrites a config file of the schema version from into the
+// version from+1.
+type schemaMigration struct {
+	from int
+	// renames maps the deprecated keys to the keys replacing them. The keys
+	// are dotted paths and are matched case-insensitively, as viper does.
+	renames map[string]string
+	// defaults lists the keys introduced by the new version. They are filled
+	// with their default value if they are missing, so that the migrated
+	// file is explicit about the settings in use.
+	defaults []string
+}
+
+// schemaMigrations lists the migrations, ordered by version
+var schemaMigrations = []schemaMigration{
+	{
+		// v3 aligns the names of the limits with the arithmetization and
+		// introduces the G2 membership calls of the pairing precompile.
+		from: 2,
+		renames: map[string]string{
+			"traces_limits.PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS":       "traces_limits.PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS",
+			"traces_limits.PRECOMPILE_ECPAIRING_WEIGHTED_CALLS":        "traces_limits.PRECOMPILE_ECPAIRING_MILLER_LOOPS",
+			"traces_limits.BLOCK_L2L1_LOGS":                            "traces_limits.BLOCK_L2_L1_LOGS",
+			"traces_limits_large.PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS": "traces_limits_large.PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS",
+			"traces_limits_large.PRECOMPILE_ECPAIRING_WEIGHTED_CALLS":  "traces_limits_large.PRECOMPILE_ECPAIRING_MILLER_LOOPS",
+			"traces_limits_large.BLOCK_L2L1_LOGS":                      "traces_limits_large.BLOCK_L2_L1_LOGS",
+		},
+		defaults: []string{
+			"traces_limits.PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS",
+			"traces_limits_large.PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS",
+		},
+	},
+}
+
+// MigrationReport describes the changes made by [Migrate]
+type MigrationReport struct {
+	// Renamed lists the deprecated keys that were renamed, as "old -> new"
+	Renamed []string
+	// Filled lists the keys that were filled with their default value
+	Filled []string
+	// Irreconcilable lists the settings that could not be migrated and must
+	// be fixed by hand: the deprecated keys set along with their replacement
+	// to a different value and the keys unknown to the target schema.
+	Irreconcilable []string
+}
+
+// ParseSchemaVersion parses a schema version written as "v3" or "3"
+func ParseSchemaVersion(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimPrefix(s, "v"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// Migrate rewrites a TOML config file of the schema version from into the
+// version to, which may only be [SchemaVersion] as the prover cannot check
+// the files of the other versions. The deprecated keys are renamed, the new
+// keys are filled with their default value and the schema_version field is
+// set. The comments and the order of the keys are not preserved. The
+// migrated file is returned even if the report lists irreconcilable
+// settings, it is then up to the caller to discard it.
+func Migrate(in []byte, from, to int) ([]byte, MigrationReport, error) {
+
+	var report MigrationReport
+
+	if to != SchemaVersion {
+		return nil, report, fmt.Errorf("can only migrate to the schema version %v of this prover, not to %v", SchemaVersion, to)
+	}
+
+	if first := schemaMigrations[0].from; from < first || from >= to {
+		return nil, report, fmt.Errorf("can only migrate from a schema version in [%v, %v), not from %v", first, to, from)
+	}
+
+	settings := map[string]any{}
+	if err := toml.Unmarshal(in, &settings); err != nil {
+		return nil, report, fmt.Errorf("could not parse the config file: %w", err)
+	}
+
+	if v, ok := lookupKey(settings, "schema_version"); ok && fmt.Sprint(v) != strconv.Itoa(from) {
+		return nil, report, fmt.Errorf("the config file has the schema version %v, not %v", v, from)
+	}
+
+	defaults := viper.New()
+	setDefaultValues(defaults)
+
+	for _, m := range schemaMigrations {
+		if m.from < from || m.from >= to {
+			continue
+		}
+
+		// sorted for the report to be deterministic
+		deprecated := make([]string, 0, len(m.renames))
+		for old := range m.renames {
+			deprecated = append(deprecated, old)
+		}
+		slices.Sort(deprecated)
+
+		for _, old := range deprecated {
+			oldVal, ok := lookupKey(settings, old)
+			if !ok {
+				continue
+			}
+			replacement := m.renames[old]
+			deleteKey(settings, old)
+			if newVal, ok := lookupKey(settings, replacement); ok {
+				if fmt.Sprint(newVal) != fmt.Sprint(oldVal) {
+					report.Irreconcilable = append(report.Irreconcilable,
+						fmt.Sprintf("%v = %v conflicts with %v = %v", old, oldVal, replacement, newVal))
+				}
+				continue
+			}
+			setKey(settings, replacement, oldVal)
+			report.Renamed = append(report.Renamed, old+" -> "+replacement)
+		}
+
+		for _, key := range m.defaults {
+			if _, ok := lookupKey(settings, key); ok {
+				continue
+			}
+			setKey(settings, key, defaults.Get(key))
+			report.Filled = append(report.Filled, key)
+		}
+	}
+
+	setKey(settings, "schema_version", to)
+
+	out, err := toml.Marshal(settings)
+	if err != nil {
+		return nil, report, fmt.Errorf("could not write the migrated config file: %w", err)
+	}
+
+	// The keys unknown to the schema would be rejected when loading the
+	// file. They are detected by decoding the file as the prover does.
+	checker := viper.New()
+	checker.SetConfigType("toml")
+	if err := checker.ReadConfig(bytes.NewReader(out)); err != nil {
+		return nil, report, fmt.Errorf("could not read the migrated config file: %w", err)
+	}
+	setDefaultValues(checker)
+	var cfg Config
+	if err := checker.UnmarshalExact(&cfg); err != nil {
+		report.Irreconcilable = append(report.Irreconcilable, err.Error())
+	}
+
+	return out, report, nil
+}
+
+// checkSchemaVersion returns an error if the config read by v declares
+// another schema version than [SchemaVersion].
+func checkSchemaVersion(v *viper.Viper) error {
+
+	if !v.IsSet("schema_version") {
+		return nil
+	}
+
+	version := v.GetInt("schema_version")
+	switch {
+	case version < SchemaVersion:
+		return fmt.Errorf(
+			"the config file has the schema version %v but the prover expects %v, run `prover config migrate --from v%v --to v%v`",
+			version, SchemaVersion, version, SchemaVersion,
+		)
+	case version > SchemaVersion:
+		return fmt.Errorf("the config file has the schema version %v, it was written for a newer prover expecting at most %v", version, SchemaVersion)
+	}
+
+	return nil
+}
+
+// lookupKey returns the value of a dotted key in nested settings, matching
+// the keys case-insensitively.
+func lookupKey(settings map[string]any, key string) (any, bool) {
+	parent, last, ok := parentOf(settings, key, false)
+	if !ok {
+		return nil, false
+	}
+	k, ok := findKey(parent, last)
+	if !ok {
+		return nil, false
+	}
+	return parent[k], true
+}
+
+// setKey sets a dotted key in nested settings, creating the tables as needed.
+// An existing key with a different case is overwritten.
+func setKey(settings map[string]any, key string, val any) {
+	parent, last, ok := parentOf(settings, key, true)
+	if !ok {
+		return
+	}
+	if k, ok := findKey(parent, last); ok {
+		last = k
+	}
+	parent[last] = val
+}
+
+// deleteKey removes a dotted key from nested settings
+func deleteKey(settings map[string]any, key string) {
+	if parent, last, ok := parentOf(settings, key, false); ok {
+		if k, ok := findKey(parent, last); ok {
+			delete(parent, k)
+		}
+	}
+}
+
+// parentOf returns the table holding a dotted key and the last component of
+// the key. If create is set, the missing tables are created.
+func parentOf(settings map[string]any, key string, create bool) (map[string]any, string, bool) {
+
+	var (
+		path   = strings.Split(key, ".")
+		parent = settings
+	)
+
+	for _, p := range path[:len(path)-1] {
+		k, ok := findKey(parent, p)
+		if !ok {
+			if !create {
+				return nil, "", false
+			}
+			k = p
+			parent[k] = map[string]any{}
+		}
+		child, ok := parent[k].(map[string]any)
+		if !ok {
+			return nil, "", false
+		}
+		parent = child
+	}
+
+	return parent, path[len(path)-1], true
+}
+
+// findKey returns the key of the table equal to key up to the case
+func findKey(table map[string]any, key string) (string, bool) {
+	if _, ok := table[key]; ok {
+		return key, true
+	}
+	for k := range table {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+const configV2 = `
+environment = "integration-full"
+version = "2.0.0"
+assets_dir = "./prover-assets"
+log_level = 4
+
+[layer2]
+chain_id = 59144
+message_service_contract = "0x508Ca82Df566dCD1B0DE8296e70a96332cD644ec"
+
+[traces_limits]
+ADD = 524288
+PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS = 16
+PRECOMPILE_ECPAIRING_WEIGHTED_CALLS = 64
+BLOCK_L2L1_LOGS = 16
+
+[traces_limits_large]
+ADD = 1048576
+PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS = 32
+`
+
+func TestMigrate(t *testing.T) {
+	assert := require.New(t)
+
+	out, report, err := Migrate([]byte(configV2), 2, 3)
+	assert.NoError(err)
+	assert.Empty(report.Irreconcilable)
+	assert.Equal([]string{
+		"traces_limits.BLOCK_L2L1_LOGS -> traces_limits.BLOCK_L2_L1_LOGS",
+		"traces_limits.PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS -> traces_limits.PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS",
+		"traces_limits.PRECOMPILE_ECPAIRING_WEIGHTED_CALLS -> traces_limits.PRECOMPILE_ECPAIRING_MILLER_LOOPS",
+		"traces_limits_large.PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS -> traces_limits_large.PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS",
+	}, report.Renamed)
+	assert.Len(report.Filled, 2)
+
+	var migrated struct {
+		SchemaVersion int              `toml:"schema_version"`
+		TracesLimits  map[string]int64 `toml:"traces_limits"`
+		Large         map[string]int64 `toml:"traces_limits_large"`
+	}
+	assert.NoError(toml.Unmarshal(out, &migrated))
+
+	assert.Equal(SchemaVersion, migrated.SchemaVersion)
+	assert.Equal(map[string]int64{
+		"ADD": 524288,
+		"PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS": 16,
+		"PRECOMPILE_ECPAIRING_MILLER_LOOPS":          64,
+		"BLOCK_L2_L1_LOGS":                           16,
+		"PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS":   64,
+	}, migrated.TracesLimits)
+	assert.Equal(int64(32), migrated.Large["PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS"])
+	assert.Equal(int64(128), migrated.Large["PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS"])
+
+	// The migrated file passes the schema checks
+	viper.Reset()
+	defer viper.Reset()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(os.WriteFile(path, out, 0600))
+	_, err = NewConfigFromFile(path)
+	assert.Error(err, "the file misses required sections")
+	assert.NotContains(err.Error(), "schema")
+}
+
+func TestMigrateIrreconcilable(t *testing.T) {
+	assert := require.New(t)
+
+	in := configV2 + `
+PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS = 64
+UNKNOWN_LIMIT = 1
+`
+
+	_, report, err := Migrate([]byte(in), 2, 3)
+	assert.NoError(err)
+	assert.Len(report.Irreconcilable, 2)
+	assert.Contains(report.Irreconcilable[0], "traces_limits_large.PRECOMPILE_ECPAIRING_EFFECTIVE_CALLS = 32 conflicts")
+	assert.Contains(report.Irreconcilable[1], "unknown_limit")
+
+	// unsupported versions
+	_, _, err = Migrate([]byte(configV2), 1, 3)
+	assert.Error(err)
+	_, _, err = Migrate([]byte(configV2), 2, 4)
+	assert.Error(err)
+	_, _, err = Migrate([]byte("schema_version = 3\n"+configV2), 2, 3)
+	assert.Error(err)
+}
+
+func TestSchemaVersionIsHonored(t *testing.T) {
+	assert := require.New(t)
+
+	viper.Reset()
+	defer viper.Reset()
+
+	dir := t.TempDir()
+	for version, expected := range map[int]string{
+		2: "prover config migrate --from v2 --to v3",
+		4: "newer prover",
+	} {
+		path := filepath.Join(dir, "config.toml")
+		content := []byte(fmt.Sprintf("schema_version = %v\n%v", version, configV2))
+		assert.NoError(os.WriteFile(path, content, 0600))
+		_, err := NewConfigFromFile(path)
+		assert.ErrorContains(err, expected)
+	}
+}
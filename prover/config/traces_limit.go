@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"slices"
 
 	"github.com/consensys/linea-monorepo/prover/utils"
 )
@@ -60,17 +61,156 @@ type TracesLimits struct {
 	PrecompileBlakeEffectiveCalls        int `mapstructure:"PRECOMPILE_BLAKE_EFFECTIVE_CALLS"`
 	PrecompileBlakeRounds                int `mapstructure:"PRECOMPILE_BLAKE_ROUNDS"`
 
+	// The BLS12-381 precompiles of EIP-2537 are not activated yet. The module
+	// proving them is only included in the zkEVM if one of these limits is set,
+	// see [TracesLimits.Eip2537Enabled]. The limits are omitted from the
+	// checksum when unset so that the existing setups remain valid.
+	PrecompileBlsG1AddEffectiveCalls int `mapstructure:"PRECOMPILE_BLS_G1_ADD_EFFECTIVE_CALLS" json:",omitempty"`
+	PrecompileBlsG1MulEffectiveCalls int `mapstructure:"PRECOMPILE_BLS_G1_MUL_EFFECTIVE_CALLS" json:",omitempty"`
+	PrecompileBlsPairingCheckCalls   int `mapstructure:"PRECOMPILE_BLS_PAIRING_CHECK_CALLS" json:",omitempty"`
+
+	// PrecompileModexpLargeEffectiveCalls is the number of modexp calls with
+	// operands larger than 256 bits that the zkEVM can prove. They are proven
+	// by a dedicated 4096-bits circuit, on top of the
+	// PrecompileModexpEffectiveCalls small calls. When unset, a single large
+	// call is supported, see [TracesLimits.ModexpLargeCalls]. The limit is
+	// omitted from the checksum when unset so that the existing setups remain
+	// valid.
+	PrecompileModexpLargeEffectiveCalls int `mapstructure:"PRECOMPILE_MODEXP_LARGE_EFFECTIVE_CALLS" json:",omitempty"`
+
+	// PrecompileEcpairingMalformedPairs is the number of pairs of the failing
+	// ecpairing calls that the zkEVM can prove to be malformed. The limit is
+	// omitted from the checksum when unset.
+	PrecompileEcpairingMalformedPairs int `mapstructure:"PRECOMPILE_ECPAIRING_MALFORMED_PAIRS" validate:"gte=1" json:",omitempty"`
+
 	BlockKeccak       int `mapstructure:"BLOCK_KECCAK"`
 	BlockL1Size       int `mapstructure:"BLOCK_L1_SIZE"`
 	BlockL2L1Logs     int `mapstructure:"BLOCK_L2_L1_LOGS"`
 	BlockTransactions int `mapstructure:"BLOCK_TRANSACTIONS"`
 
+	// BlockGasLimit is the largest gas limit accepted for a block. The zkEVM
+	// constrains the gas limits of the blocks to be within the bounds enforced
+	// by the sequencer. When unset, the gas limits are only bounded below and
+	// the limit is omitted from the checksum so that the existing setups
+	// remain valid.
+	BlockGasLimit uint64 `mapstructure:"BLOCK_GAS_LIMIT" json:",omitempty"`
+
 	ShomeiMerkleProofs int `mapstructure:"SHOMEI_MERKLE_PROOFS"`
+
+	// DisabledPrecompiles lists the precompiles whose proving module is left
+	// out of the zkEVM, e.g. to lighten the prover of a testnet. The traces
+	// invoking a disabled precompile are rejected by the prover and the
+	// arithmetization constrains them away. The precompiles whose limit is
+	// zero are disabled as well, see [TracesLimits.PrecompileEnabled]. The
+	// field is omitted from the checksum when empty so that the existing
+	// setups remain valid.
+	DisabledPrecompiles []string `mapstructure:"DISABLED_PRECOMPILES" validate:"dive,oneof=modexp ecmul ecpair sha2" json:",omitempty"`
+}
+
+// The names of the precompiles that can be listed in
+// [TracesLimits.DisabledPrecompiles].
+const (
+	PrecompileModexp = "modexp"
+	PrecompileEcmul  = "ecmul"
+	PrecompileEcpair = "ecpair"
+	PrecompileSha2   = "sha2"
+)
+
+// precompileLimits returns, for every precompile that can be disabled, the
+// limit bounding its calls. A zero limit disables the precompile.
+func (tl *TracesLimits) precompileLimits() map[string]int {
+	return map[string]int{
+		PrecompileModexp: tl.PrecompileModexpEffectiveCalls,
+		PrecompileEcmul:  tl.PrecompileEcmulEffectiveCalls,
+		PrecompileEcpair: tl.PrecompileEcpairingEffectiveCalls,
+		PrecompileSha2:   tl.PrecompileSha2Blocks,
+	}
+}
+
+// PrecompileEnabled returns false if the proving module of the precompile is
+// disabled in the limits: either explicitly, in DisabledPrecompiles, or
+// because the limit on its calls is zero.
+func (tl *TracesLimits) PrecompileEnabled(name string) bool {
+	if slices.Contains(tl.DisabledPrecompiles, name) {
+		return false
+	}
+	limit, ok := tl.precompileLimits()[name]
+	return !ok || limit > 0
+}
+
+// EffectiveDisabledPrecompiles returns the sorted list of the precompiles
+// disabled in the limits, see [TracesLimits.PrecompileEnabled].
+func (tl *TracesLimits) EffectiveDisabledPrecompiles() []string {
+	var res []string
+	for name := range tl.precompileLimits() {
+		if !tl.PrecompileEnabled(name) {
+			res = append(res, name)
+		}
+	}
+	slices.Sort(res)
+	return res
 }
 
+// Eip2537Enabled returns true if the zkEVM should prove the BLS12-381
+// precompiles of EIP-2537.
+func (tl *TracesLimits) Eip2537Enabled() bool {
+	return tl.PrecompileBlsG1AddEffectiveCalls > 0 ||
+		tl.PrecompileBlsG1MulEffectiveCalls > 0 ||
+		tl.PrecompileBlsPairingCheckCalls > 0
+}
+
+// ModexpLargeCalls returns the number of modexp calls with operands larger
+// than 256 bits that the zkEVM can prove.
+func (tl *TracesLimits) ModexpLargeCalls() int {
+	if tl.PrecompileModexpLargeEffectiveCalls == 0 {
+		return 1
+	}
+	return tl.PrecompileModexpLargeEffectiveCalls
+}
+
+// ChecksumVersion selects the encoding hashed by the checksums of the config.
+type ChecksumVersion int
+
+const (
+	// ChecksumLegacy hashes the JSON encoding of the value, which follows the
+	// order of the fields of its structs. The checksums of this version
+	// identify the existing setups and the prover metadata of the proofs.
+	ChecksumLegacy ChecksumVersion = iota
+	// ChecksumCanonical hashes the canonical JSON encoding of the value (see
+	// [utils.CanonicalJSON]): reordering the fields of the structs does not
+	// change the checksums of this version.
+	ChecksumCanonical
+)
+
+// Checksum returns the checksum of the limits in the [ChecksumLegacy]
+// version.
 func (tl *TracesLimits) Checksum() string {
-	// encode the struct to json, then hash it
-	encoded, err := json.Marshal(tl)
+	return tl.VersionedChecksum(ChecksumLegacy)
+}
+
+// VersionedChecksum returns the checksum of the limits in the requested version.
+func (tl *TracesLimits) VersionedChecksum(version ChecksumVersion) string {
+	return checksumJSON(tl, version)
+}
+
+// checksumJSON returns the digest of the JSON encoding of v in the requested
+// version.
+func checksumJSON(v any, version ChecksumVersion) string {
+
+	var (
+		encoded []byte
+		err     error
+	)
+
+	switch version {
+	case ChecksumLegacy:
+		encoded, err = json.Marshal(v)
+	case ChecksumCanonical:
+		encoded, err = utils.CanonicalJSON(v)
+	default:
+		utils.Panic("unknown checksum version %v", version)
+	}
+
 	if err != nil {
 		panic(err) // should never happen
 	}
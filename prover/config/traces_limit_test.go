@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracesLimitsEip2537(t *testing.T) {
+
+	tl := TracesLimits{Add: 1 << 10, BlockKeccak: 8192}
+	assert.False(t, tl.Eip2537Enabled())
+
+	// The unset BLS limits do not appear in the checksum so that it remains
+	// valid for the setups generated before they existed.
+	encoded, err := json.Marshal(tl)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "PrecompileBls")
+
+	before := tl.Checksum()
+	tl.PrecompileBlsPairingCheckCalls = 2
+	assert.True(t, tl.Eip2537Enabled())
+	assert.NotEqual(t, before, tl.Checksum())
+}
+
+func TestTracesLimitsZeroLimitDisablesPrecompile(t *testing.T) {
+
+	tl := TracesLimits{
+		PrecompileModexpEffectiveCalls:    4,
+		PrecompileEcpairingEffectiveCalls: 4,
+		DisabledPrecompiles:               []string{PrecompileEcpair},
+	}
+
+	// ecmul and sha2 have a zero limit
+	assert.True(t, tl.PrecompileEnabled(PrecompileModexp))
+	assert.False(t, tl.PrecompileEnabled(PrecompileEcmul))
+	assert.False(t, tl.PrecompileEnabled(PrecompileSha2))
+	assert.Equal(t, []string{PrecompileEcmul, PrecompileEcpair, PrecompileSha2}, tl.EffectiveDisabledPrecompiles())
+
+	// The precompiles without a governing limit are left untouched
+	assert.True(t, tl.PrecompileEnabled("ecrecover"))
+}
+
+func TestTracesLimitsDisabledPrecompiles(t *testing.T) {
+
+	tl := TracesLimits{
+		Add:                               1 << 10,
+		BlockKeccak:                       8192,
+		PrecompileModexpEffectiveCalls:    4,
+		PrecompileEcmulEffectiveCalls:     4,
+		PrecompileEcpairingEffectiveCalls: 4,
+		PrecompileSha2Blocks:              4,
+	}
+	assert.True(t, tl.PrecompileEnabled(PrecompileEcpair))
+	assert.Empty(t, tl.EffectiveDisabledPrecompiles())
+
+	encoded, err := json.Marshal(tl)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "DisabledPrecompiles")
+
+	before := tl.Checksum()
+	tl.DisabledPrecompiles = []string{PrecompileEcpair}
+	assert.False(t, tl.PrecompileEnabled(PrecompileEcpair))
+	assert.True(t, tl.PrecompileEnabled(PrecompileModexp))
+	assert.NotEqual(t, before, tl.Checksum())
+}
+
+func TestTracesLimitsChecksumLocked(t *testing.T) {
+
+	// The checksum identifies the setups: changing the encoding, the order of
+	// the fields or the json names of the limits requires regenerating them.
+	tl := TracesLimits{Add: 1 << 10, Bin: 1 << 12, BlockKeccak: 8192, BlockGasLimit: 2_000_000_000}
+	assert.Equal(t, "0x1d4a15d5565897c6ef2f8256e95360a5e7bf6d8966f54c9fc21e05069653ddb2", tl.Checksum())
+	assert.Equal(t, tl.Checksum(), tl.VersionedChecksum(ChecksumLegacy))
+
+	// The canonical checksum does not depend on the order of the fields but
+	// changing the json names or the values of the limits changes it.
+	assert.Equal(t, "0xfae74e2942997eca6451768bfbbd4544bdee73c5bca743075ac8a9c663ea2af4", tl.VersionedChecksum(ChecksumCanonical))
+}
@@ -0,0 +1,95 @@
+package fiatshamir
+
+import (
+	"errors"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// Snapshot is the serializable state of a [State]. It allows resuming the
+// Fiat-Shamir transcript in another process, see [Restore].
+type Snapshot struct {
+	// Digest is the MiMC state after absorbing the transcript so far
+	Digest           field.Element
+	TranscriptSize   int
+	NumCoinGenerated int
+}
+
+// Snapshot returns the current state. The MiMC hasher only buffers the
+// elements it receives until they are hashed by the next call to Sum, and
+// hashing them earlier does not change the subsequent outputs. Thus, taking a
+// snapshot does not alter the randomness generated by the state afterwards.
+func (fs *State) Snapshot() Snapshot {
+	var digest field.Element
+	digest.SetBytes(fs.hasher.Sum(nil))
+	return Snapshot{
+		Digest:           digest,
+		TranscriptSize:   fs.TranscriptSize,
+		NumCoinGenerated: fs.NumCoinGenerated,
+	}
+}
+
+// Restore returns a [State] resuming from the snapshot. It generates the same
+// randomness as the state the snapshot was taken from, given the same
+// updates.
+func Restore(s Snapshot) *State {
+	return &State{
+		hasher:           &resumedMiMC{state: s.Digest},
+		TranscriptSize:   s.TranscriptSize,
+		NumCoinGenerated: s.NumCoinGenerated,
+	}
+}
+
+// resumedMiMC is a MiMC hasher in Miyaguchi-Preneel mode, as the one of
+// gnark-crypto, but whose initial state can be set.
+type resumedMiMC struct {
+	state field.Element
+	data  []field.Element
+}
+
+// Write appends the field elements encoded in p, in big-endian on
+// [field.Bytes] bytes each. As the hasher of gnark-crypto, a single short
+// element is left-padded.
+func (h *resumedMiMC) Write(p []byte) (int, error) {
+
+	n := len(p)
+	if n > 0 && n < field.Bytes {
+		padded := make([]byte, field.Bytes)
+		copy(padded[field.Bytes-n:], p)
+		p = padded
+	}
+
+	if len(p)%field.Bytes != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements")
+	}
+
+	for start := 0; start < len(p); start += field.Bytes {
+		var e field.Element
+		if err := e.SetBytesCanonical(p[start : start+field.Bytes]); err != nil {
+			return 0, err
+		}
+		h.data = append(h.data, e)
+	}
+
+	return n, nil
+}
+
+// Sum hashes the buffered elements and appends the resulting state to b
+func (h *resumedMiMC) Sum(b []byte) []byte {
+	for i := range h.data {
+		h.state = mimc.BlockCompression(h.state, h.data[i])
+	}
+	h.data = nil
+	res := h.state.Bytes()
+	return append(b, res[:]...)
+}
+
+func (h *resumedMiMC) Reset() {
+	h.state.SetZero()
+	h.data = nil
+}
+
+func (h *resumedMiMC) Size() int { return field.Bytes }
+
+func (h *resumedMiMC) BlockSize() int { return field.Bytes }
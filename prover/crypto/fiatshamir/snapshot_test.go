@@ -0,0 +1,41 @@
+package fiatshamir
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+
+	var (
+		fs        = NewMiMCFiatShamir()
+		reference = NewMiMCFiatShamir()
+	)
+
+	for _, s := range []*State{fs, reference} {
+		s.Update(field.NewElement(1), field.NewElement(2))
+		_ = s.RandomField()
+		s.UpdateVec([]field.Element{field.NewElement(3), field.NewElement(4)})
+	}
+
+	// Taking the snapshot does not alter the state
+	resumed := Restore(fs.Snapshot())
+
+	for _, s := range []*State{fs, reference, resumed} {
+		s.Update(field.NewElement(5))
+	}
+
+	var (
+		expected = reference.RandomField()
+		ints     = reference.RandomManyIntegers(10, 1<<8)
+	)
+
+	for _, s := range []*State{fs, resumed} {
+		require.Equal(t, expected, s.RandomField())
+		require.Equal(t, ints, s.RandomManyIntegers(10, 1<<8))
+		require.Equal(t, reference.TranscriptSize, s.TranscriptSize)
+		require.Equal(t, reference.NumCoinGenerated, s.NumCoinGenerated)
+	}
+}
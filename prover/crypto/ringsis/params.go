@@ -1,6 +1,9 @@
 package ringsis
 
 import (
+	"fmt"
+	"math"
+
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 )
@@ -9,11 +12,83 @@ import (
 // more than the 128 level of security.
 var StdParams = Params{LogTwoBound: 16, LogTwoDegree: 6}
 
+// MinSecurityLevel is the number of bits of security below which [Params.Validate]
+// rejects a ring-SIS instance.
+const MinSecurityLevel = 128
+
 // Params encapsulates the parameters of a ring SIS instance
 type Params struct {
 	LogTwoBound, LogTwoDegree int
 }
 
+// SecurityLevel estimates the number of bits of security of the instance
+// against lattice reduction attacks. Finding a collision amounts to finding a
+// vector of norm 2^LogTwoBound in the SIS lattice of dimension 2^LogTwoDegree
+// over the scalar field, which requires a root-Hermite factor δ such that
+//
+//	log2(δ) = LogTwoBound^2 / (4 * 2^LogTwoDegree * log2(q))
+//
+// following Micciancio and Regev. The function returns the core-SVP cost,
+// 0.292 * b, of the smallest BKZ block size b achieving δ. The infinity norm
+// bound is used in place of the Euclidean one, as in the estimation from
+// which [StdParams] have been chosen.
+func (p *Params) SecurityLevel() float64 {
+
+	var (
+		n         = float64(p.modulusDegree())
+		logQ      = float64(field.Bits)
+		logBound  = float64(p.LogTwoBound)
+		logTarget = logBound * logBound / (4 * n * logQ)
+	)
+
+	// Past this block size, the estimation is far above any target
+	const maxBlockSize = 1 << 14
+
+	for b := 50; b < maxBlockSize; b++ {
+		if math.Log2(rootHermiteFactor(float64(b))) <= logTarget {
+			return 0.292 * float64(b)
+		}
+	}
+
+	return 0.292 * maxBlockSize
+}
+
+// rootHermiteFactor returns the root-Hermite factor achieved by BKZ with
+// block size b, following Chen's estimation.
+func rootHermiteFactor(b float64) float64 {
+	return math.Pow(math.Pow(math.Pi*b, 1/b)*b/(2*math.Pi*math.E), 1/(2*(b-1)))
+}
+
+// Validate returns an error if the instance cannot be used by the prover or
+// if its estimated security is below [MinSecurityLevel].
+func (p *Params) Validate() error {
+
+	if p.LogTwoBound <= 0 || p.LogTwoBound >= 64 {
+		return fmt.Errorf("ring-SIS: the log two bound %v must be in [1, 63]", p.LogTwoBound)
+	}
+
+	if p.LogTwoDegree <= 0 {
+		return fmt.Errorf("ring-SIS: the log two degree %v must be positive", p.LogTwoDegree)
+	}
+
+	// The self-recursion requires the number of limbs to be a power of two
+	if numLimbs := p.NumLimbs(); !utils.IsPowerOfTwo(numLimbs) {
+		return fmt.Errorf(
+			"ring-SIS: a log two bound of %v splits the field elements in %v limbs, which is not a power of two",
+			p.LogTwoBound, numLimbs,
+		)
+	}
+
+	if sec := p.SecurityLevel(); sec < MinSecurityLevel {
+		return fmt.Errorf(
+			"ring-SIS: the parameters (log two bound %v, log two degree %v) are estimated at %.0f bits of security, below the %v bits required",
+			p.LogTwoBound, p.LogTwoDegree, sec, MinSecurityLevel,
+		)
+	}
+
+	return nil
+}
+
 // NumLimbs number of limbs to represent a field element with the current
 // representation
 func (p *Params) NumLimbs() int {
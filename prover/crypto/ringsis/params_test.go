@@ -35,3 +35,23 @@ func TestStdParams(t *testing.T) {
 		})
 	}
 }
+
+func TestParamsValidate(t *testing.T) {
+
+	require.NoError(t, StdParams.Validate())
+	require.Greater(t, StdParams.SecurityLevel(), float64(MinSecurityLevel))
+
+	// Larger bounds lower the security, larger degrees increase it
+	require.Less(t, (&Params{LogTwoBound: 32, LogTwoDegree: 6}).SecurityLevel(), StdParams.SecurityLevel())
+	require.Greater(t, (&Params{LogTwoBound: 16, LogTwoDegree: 7}).SecurityLevel(), StdParams.SecurityLevel())
+
+	for _, p := range []Params{
+		{LogTwoBound: 16, LogTwoDegree: 5}, // not secure
+		{LogTwoBound: 32, LogTwoDegree: 6}, // not secure
+		{LogTwoBound: 10, LogTwoDegree: 6}, // 26 limbs
+		{LogTwoBound: 0, LogTwoDegree: 6},
+		{LogTwoBound: 16, LogTwoDegree: 0},
+	} {
+		require.Errorf(t, p.Validate(), "params %+v", p)
+	}
+}
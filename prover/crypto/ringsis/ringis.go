@@ -2,16 +2,13 @@ package ringsis
 
 import (
 	"bytes"
-	"encoding/binary"
-	"io"
-	"math"
 	"runtime"
 	"sync"
 
-	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/sis"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	wfft "github.com/consensys/linea-monorepo/prover/maths/fft"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/parallel"
@@ -29,8 +26,8 @@ const (
 // Key encapsulates the public parameters of an instance of the ring-SIS hash
 // instance.
 type Key struct {
-	// lock guards the access to the SIS key and prevents the user from hashing
-	// concurrently with the same SIS key.
+	// lock guards the access to the internal buffers of gnarkInternal.
+	// [Key.Hash] does not use them and can be called concurrently.
 	lock *sync.Mutex
 	// gnarkInternal stores the SIS key itself and some precomputed domain
 	// twiddles.
@@ -44,6 +41,10 @@ type Key struct {
 	// to the specially unrolled [sis.FFT64] function. They are thus optionally
 	// constructed when [GenerateKey] is called.
 	twiddleCosets []field.Element
+	// negacyclic is used to compute the products modulo X^d + 1. Its
+	// evaluations are in the same order as the ones of the [sis.RSis] domain
+	// so that they can be multiplied with the key in evaluation form.
+	negacyclic *wfft.NegacyclicDomain
 }
 
 // GenerateKey generates a ring-SIS key from a set of a [Params] and a max
@@ -65,6 +66,13 @@ func GenerateKey(params Params, maxNumFieldToHash int) Key {
 		lock:          &sync.Mutex{},
 		gnarkInternal: rsis,
 		Params:        params,
+		negacyclic:    wfft.NewNegacyclicDomain(1 << params.LogTwoDegree),
+	}
+
+	// Sanity-check : the evaluations of the key are only compatible with
+	// the negacyclic NTT if both use the same 2d-th root of unity.
+	if res.negacyclic.Psi != rsis.Domain.FrMultiplicativeGen {
+		utils.Panic("the shift of the SIS domain is not the root of unity of the negacyclic domain")
 	}
 
 	// Optimization for these specific parameters
@@ -102,37 +110,63 @@ func (s *Key) Ag() [][]field.Element {
 // to the sum sum_i A[i]*m Mod X^{d}+1
 //
 // It is equivalent to calling r.Write(element.Marshal()); outBytes = r.Sum(nil);
+//
+// The limbs are not converted in Montgommery form. As a result, they are
+// implicitly multiplied by RInv: this is the "Montgommery skip" that
+// [Key.FlattenedKey] and [Key.HashModXnMinus1] account for.
 func (s *Key) Hash(v []field.Element) []field.Element {
 
-	// since hashing writes into internal buffers
-	// we need to guard against races conditions.
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	if len(v) > s.MaxNumFieldHashable() {
+		utils.Panic("Attempted to hash %v field elements, but the limit is %v", len(v), s.MaxNumFieldHashable())
+	}
+
+	var (
+		degree = s.modulusDegree()
+		nbPoly = utils.DivCeil(len(v)*s.NumLimbs(), degree)
+		buf    = make([]byte, 0, len(v)*field.Bytes)
+		limbs  = make([]field.Element, nbPoly*degree)
+		res    = make([]field.Element, degree)
+		tmp    field.Element
+	)
 
-	// write the input as byte
-	s.gnarkInternal.Reset()
 	for i := range v {
-		_, err := s.gnarkInternal.Write(v[i].Marshal())
-		if err != nil {
-			panic(err)
-		}
+		b := v[i].Bytes() // big endian serialization
+		buf = append(buf, b[:]...)
 	}
-	sum := s.gnarkInternal.Sum(make([]byte, 0, field.Bytes*s.OutputSize()))
 
-	// unmarshal the result
-	var rlen [4]byte
-	if len(sum) > math.MaxUint32*fr.Bytes {
-		panic("slice too long")
-	}
-	binary.BigEndian.PutUint32(rlen[:], uint32(len(sum)/fr.Bytes)) // #nosec G115 -- Overflow checked
-	reader := io.MultiReader(bytes.NewReader(rlen[:]), bytes.NewReader(sum))
-	var result fr.Vector
-	_, err := result.ReadFrom(reader)
-	if err != nil {
-		panic(err)
+	sis.LimbDecomposeBytes(buf, limbs, s.LogTwoBound)
+
+	for i := 0; i < nbPoly; i++ {
+
+		k := limbs[i*degree : (i+1)*degree]
+
+		// NTT(0) = 0, the polynomial has no contribution
+		if isZero(k) {
+			continue
+		}
+
+		s.negacyclic.NTT(k)
+
+		ag := s.gnarkInternal.Ag[i]
+		for j := range res {
+			tmp.Mul(&k[j], &ag[j])
+			res[j].Add(&res[j], &tmp)
+		}
 	}
 
-	return result
+	// by linearity, we defer the inverse NTT at the end
+	s.negacyclic.NTTInverse(res)
+	return res
+}
+
+// isZero returns true if all the entries of v are zero
+func isZero(v []field.Element) bool {
+	for i := range v {
+		if !v[i].IsZero() {
+			return false
+		}
+	}
+	return true
 }
 
 // LimbSplit breaks down the entries of `v` into short limbs representing
@@ -301,7 +335,7 @@ func (s *Key) TransversalHash(v []smartvectors.SmartVector) []field.Element {
 			s.gnarkInternal.Ag,
 			v,
 			s.twiddleCosets,
-			s.gnarkInternal.Domain,
+			s.negacyclic,
 		)
 	}
 
@@ -310,7 +344,7 @@ func (s *Key) TransversalHash(v []smartvectors.SmartVector) []field.Element {
 			s.gnarkInternal.Ag,
 			v,
 			s.twiddleCosets,
-			s.gnarkInternal.Domain,
+			s.negacyclic,
 		)
 	}
 
@@ -319,7 +353,7 @@ func (s *Key) TransversalHash(v []smartvectors.SmartVector) []field.Element {
 			s.gnarkInternal.Ag,
 			v,
 			s.twiddleCosets,
-			s.gnarkInternal.Domain,
+			s.negacyclic,
 		)
 	}
 
@@ -362,5 +396,6 @@ func (s *Key) CopyWithFreshBuffer() *Key {
 		lock:          &sync.Mutex{},
 		gnarkInternal: &clonedRsis,
 		Params:        s.Params,
+		negacyclic:    s.negacyclic,
 	}
 }
@@ -5,9 +5,9 @@ package ringsis_32_8
 import (
 	"runtime"
 
-	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/fft"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/parallel"
@@ -21,8 +21,8 @@ func TransversalHash(
 	pols []smartvectors.SmartVector,
 	// The precomputed twiddle cosets for the forward FFT
 	twiddleCosets []field.Element,
-	// The domain for the final inverse-FFT
-	domain *fft.Domain,
+	// The domain for the final inverse NTT
+	domain *fft.NegacyclicDomain,
 ) []field.Element {
 
 	var (
@@ -143,8 +143,8 @@ func TransversalHash(
 		for col := start; col < stop; col++ {
 			// Accumulate the const
 			vector.Add(mainResults[col*32:(col+1)*32], mainResults[col*32:(col+1)*32], constResults)
-			// And run the reverse FFT
-			domain.FFTInverse(mainResults[col*32:(col+1)*32], fft.DIT, fft.OnCoset(), fft.WithNbTasks(1))
+			// And run the inverse NTT
+			domain.NTTInverse(mainResults[col*32 : (col+1)*32])
 		}
 	})
 
@@ -61,13 +61,14 @@ func regularRandomTestVector(rng *rand.Rand, numRow, numCols int) []smartvectors
 func TestSmartVectorTransversalSisHash(t *testing.T) {
 
 	var (
-		numReps   = 64
-		numCols   = 16
-		rng       = rand.New(rand.NewSource(786868))
-		domain    = fft.NewDomain(32, fft.WithShift(wfft.GetOmega(32*2)))
-		twiddles  = ringsis_32_8.PrecomputeTwiddlesCoset(domain.Generator, domain.FrMultiplicativeGen)
-		params    = ringsis.Params{LogTwoBound: 8, LogTwoDegree: 5}
-		testCases = [][]smartvectors.SmartVector{
+		numReps    = 64
+		numCols    = 16
+		rng        = rand.New(rand.NewSource(786868))
+		domain     = fft.NewDomain(32, fft.WithShift(wfft.GetOmega(32*2)))
+		twiddles   = ringsis_32_8.PrecomputeTwiddlesCoset(domain.Generator, domain.FrMultiplicativeGen)
+		negacyclic = wfft.NewNegacyclicDomain(32)
+		params     = ringsis.Params{LogTwoBound: 8, LogTwoDegree: 5}
+		testCases  = [][]smartvectors.SmartVector{
 			constantRandomTestVector(rng, 1, numCols),
 			regularRandomTestVector(rng, 1, numCols),
 		}
@@ -91,7 +92,7 @@ func TestSmartVectorTransversalSisHash(t *testing.T) {
 					key.Ag(),
 					c,
 					twiddles,
-					domain,
+					negacyclic,
 				)
 			)
 
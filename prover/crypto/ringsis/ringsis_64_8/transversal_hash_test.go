@@ -61,13 +61,14 @@ func regularRandomTestVector(rng *rand.Rand, numRow, numCols int) []smartvectors
 func TestSmartVectorTransversalSisHash(t *testing.T) {
 
 	var (
-		numReps   = 64
-		numCols   = 16
-		rng       = rand.New(rand.NewSource(786868))
-		domain    = fft.NewDomain(64, fft.WithShift(wfft.GetOmega(64*2)))
-		twiddles  = ringsis_64_8.PrecomputeTwiddlesCoset(domain.Generator, domain.FrMultiplicativeGen)
-		params    = ringsis.Params{LogTwoBound: 8, LogTwoDegree: 6}
-		testCases = [][]smartvectors.SmartVector{
+		numReps    = 64
+		numCols    = 16
+		rng        = rand.New(rand.NewSource(786868))
+		domain     = fft.NewDomain(64, fft.WithShift(wfft.GetOmega(64*2)))
+		twiddles   = ringsis_64_8.PrecomputeTwiddlesCoset(domain.Generator, domain.FrMultiplicativeGen)
+		negacyclic = wfft.NewNegacyclicDomain(64)
+		params     = ringsis.Params{LogTwoBound: 8, LogTwoDegree: 6}
+		testCases  = [][]smartvectors.SmartVector{
 			constantRandomTestVector(rng, 2, numCols),
 			regularRandomTestVector(rng, 2, numCols),
 		}
@@ -91,7 +92,7 @@ func TestSmartVectorTransversalSisHash(t *testing.T) {
 					key.Ag(),
 					c,
 					twiddles,
-					domain,
+					negacyclic,
 				)
 			)
 
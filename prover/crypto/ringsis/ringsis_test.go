@@ -212,6 +212,40 @@ func TestHashFromLimbs(t *testing.T) {
 	}
 }
 
+func TestHashMatchesGnark(t *testing.T) {
+
+	// gnarkHash is the hash of v computed by gnark-crypto
+	gnarkHash := func(key *Key, v []field.Element) []field.Element {
+		rsis := key.gnarkInternal.CopyWithFreshBuffer()
+		for i := range v {
+			rsis.Write(v[i].Marshal())
+		}
+		sum := rsis.Sum(nil)
+		res := make([]field.Element, len(sum)/field.Bytes)
+		for i := range res {
+			res[i].SetBytes(sum[i*field.Bytes : (i+1)*field.Bytes])
+		}
+		return res
+	}
+
+	for pId, tcParams := range testCasesKey {
+		key := GenerateKey(tcParams.Params, tcParams.Size)
+		testCaseVecs := [][]field.Element{
+			{field.One()},
+			vector.Rand(tcParams.Size),
+			// The last polynomials are not used
+			append(vector.Rand(1), make([]field.Element, tcParams.Size-1)...),
+			// The first polynomials are zero and are skipped
+			append(make([]field.Element, tcParams.Size-1), vector.Rand(1)...),
+		}
+		for vecId, tcVec := range testCaseVecs {
+			t.Run(fmt.Sprintf("params-#%v-vec-%v", pId, vecId), func(t *testing.T) {
+				require.Equal(t, vector.Prettify(gnarkHash(&key, tcVec)), vector.Prettify(key.Hash(tcVec)))
+			})
+		}
+	}
+}
+
 func TestTransveralHashFromLimbs(t *testing.T) {
 
 	testCaseDimensions := []struct {
@@ -4,12 +4,12 @@ import (
 	"flag"
 	"fmt"
 	"math/big"
-	"math/bits"
 	"os"
 	"text/template"
 
 	"github.com/consensys/bavard"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 )
 
 // Config stores the template generation parameters for the optimized ring-SIS
@@ -77,17 +77,8 @@ func log2(n int64) int64 {
 	return int64(utils.Log2Floor(int(n)))
 }
 
+// bitReverse returns the position of i after the bit-reversal permutation of
+// a slice of size n.
 func bitReverse(n, i int64) uint64 {
-	nn := uint64(64 - bits.TrailingZeros64(uint64(n)))
-	r := make([]uint64, n)
-	for i := 0; i < len(r); i++ {
-		r[i] = uint64(i)
-	}
-	for i := 0; i < len(r); i++ {
-		irev := bits.Reverse64(r[i]) >> nn
-		if irev > uint64(i) {
-			r[i], r[irev] = r[irev], r[i]
-		}
-	}
-	return r[i]
+	return uint64(bitutil.Reverse(int(i), utils.Log2Floor(int(n))))
 }
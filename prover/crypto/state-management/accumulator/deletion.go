@@ -103,6 +103,10 @@ func (v *VerifierState[K, V]) VerifyDeletion(trace DeletionTrace[K, V]) error {
 		return fmt.Errorf("the deleted value does not match the hVal of the opening")
 	}
 
+	if !extraEqual(trace.DeletedOpen.Extra, leafExtra(v.Config, trace.DeletedValue)) {
+		return fmt.Errorf("the deleted value does not match the extra fields of the opening")
+	}
+
 	iMinus := int64(trace.ProofMinus.Path)
 	iDeleted := int64(trace.ProofDeleted.Path)
 	iPlus := int64(trace.ProofPlus.Path)
@@ -139,7 +143,7 @@ func (v *VerifierState[K, V]) VerifyDeletion(trace DeletionTrace[K, V]) error {
 	}
 
 	// Audit the update of the deleted leaf
-	deletedLeaf := hash(v.Config, &trace.DeletedOpen)
+	deletedLeaf := trace.DeletedOpen.Hash(v.Config)
 	currentRoot, err = updateCheckRoot(v.Config, trace.ProofDeleted, currentRoot, deletedLeaf, smt.EmptyLeaf())
 	if err != nil {
 		return fmt.Errorf("audit of the update of the middle leaf failed %v", err)
@@ -183,7 +187,7 @@ func (trace DeletionTrace[K, V]) DeferMerkleChecks(
 	appendTo, currentRoot = deferCheckUpdateRoot(config, trace.ProofMinus, currentRoot, oldLeafMinus, newLeafMinus, appendTo)
 
 	// the proof verification for the deleted leaf
-	deletedLeaf := hash(config, &trace.DeletedOpen)
+	deletedLeaf := trace.DeletedOpen.Hash(config)
 	appendTo, currentRoot = deferCheckUpdateRoot(config, trace.ProofDeleted, currentRoot, deletedLeaf, smt.EmptyLeaf(), appendTo)
 
 	// Audit the update of the "plus"
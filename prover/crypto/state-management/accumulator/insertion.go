@@ -72,9 +72,9 @@ func (p *ProverState[K, V]) InsertAndProve(key K, val V) (trace InsertionTrace[K
 			Prev: int64(iMinus),
 			Next: int64(iPlus),
 			HKey: hash(p.Config(), key),
-			HVal: hash(p.Config(), val),
 		},
 	}
+	insertedTuple.LeafOpening.setValue(p.Config(), val)
 	trace.ProofNew = p.upsertTuple(iInserted, insertedTuple)
 
 	// 3/ The next
@@ -142,12 +142,13 @@ func (v *VerifierState[K, V]) VerifyInsertion(trace InsertionTrace[K, V]) error
 	}
 
 	// Audit the update of the inserted new leaf
-	newLeaf := LeafOpening{
+	newOpening := LeafOpening{
 		Prev: int64(trace.ProofMinus.Path),
 		Next: int64(trace.ProofPlus.Path),
 		HKey: hkey,
-		HVal: hash(v.Config, trace.Val),
-	}.Hash(v.Config)
+	}
+	newOpening.setValue(v.Config, trace.Val)
+	newLeaf := newOpening.Hash(v.Config)
 
 	currentRoot, err = updateCheckRoot(v.Config, trace.ProofNew, currentRoot, smt.EmptyLeaf(), newLeaf)
 	if err != nil {
@@ -198,12 +199,13 @@ func (trace InsertionTrace[K, V]) DeferMerkleChecks(
 	appendTo, currentRoot = deferCheckUpdateRoot(config, trace.ProofMinus, currentRoot, oldLeafMinus, newLeafMinus, appendTo)
 
 	// Audit the update of the inserted new leaf
-	newLeaf := LeafOpening{
+	newOpening := LeafOpening{
 		Prev: int64(trace.ProofMinus.Path),
 		Next: int64(trace.ProofPlus.Path),
 		HKey: hkey,
-		HVal: hash(config, trace.Val),
-	}.Hash(config)
+	}
+	newOpening.setValue(config, trace.Val)
+	newLeaf := newOpening.Hash(config)
 
 	appendTo, currentRoot = deferCheckUpdateRoot(config, trace.ProofNew, currentRoot, smt.EmptyLeaf(), newLeaf, appendTo)
 
@@ -0,0 +1,106 @@
+package accumulator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/consensys/linea-monorepo/prover/utils"
+
+	//lint:ignore ST1001 -- the package contains a list of standard types for this repo
+	. "github.com/consensys/linea-monorepo/prover/utils/types"
+)
+
+// The layouts of the leaves of the accumulator, selected by
+// [smt.Config.LeafLayout]. A tree uses a single layout, the upgrades of the
+// layout happen at a fork and are carried by migrating the trees.
+const (
+	// LeafLayoutV0 is the original layout: Prev || Next || HKey || HVal,
+	// each on 32 bytes, hashed without domain separation.
+	LeafLayoutV0 uint8 = iota
+	// LeafLayoutV1 prefixes the leaf with a domain separator tagging the
+	// version of the layout and appends the extra fields of the value, see
+	// [LeafExtraFields]. For the accounts, this is the keccak code hash.
+	LeafLayoutV1
+
+	// LatestLeafLayout is the most recent layout supported
+	LatestLeafLayout = LeafLayoutV1
+)
+
+// leafDomainSeparator is the tag ("leaf" in ASCII) prefixing the leaves of the
+// versioned layouts, followed by the version. The leaves of distinct layouts
+// and the inner nodes of the tree thus never hash the same inputs.
+const leafDomainSeparator int64 = 0x6c656166
+
+// LeafExtraFields is implemented by the values whose leaves carry extra fields
+// in the versioned layouts. The fields are hashed in the leaf after HVal.
+type LeafExtraFields interface {
+	LeafExtraFields() []Bytes32
+}
+
+// WithLeafLayout returns a copy of the config using the given leaf layout. It
+// panics if the layout is not supported.
+func WithLeafLayout(conf *smt.Config, layout uint8) *smt.Config {
+	if err := ValidateLeafLayout(layout); err != nil {
+		utils.Panic("%v", err)
+	}
+	res := *conf
+	res.LeafLayout = layout
+	return &res
+}
+
+// ValidateLeafLayout returns an error if the layout is not supported
+func ValidateLeafLayout(layout uint8) error {
+	if layout > LatestLeafLayout {
+		return fmt.Errorf("unsupported leaf layout %v, the latest one is %v", layout, LatestLeafLayout)
+	}
+	return nil
+}
+
+// leafExtra returns the extra fields of the leaf storing v, nil in the
+// original layout or if v has none.
+func leafExtra(conf *smt.Config, v io.WriterTo) []Bytes32 {
+	if conf.LeafLayout == LeafLayoutV0 {
+		return nil
+	}
+	if e, ok := v.(LeafExtraFields); ok {
+		return e.LeafExtraFields()
+	}
+	return nil
+}
+
+// hashLeaf hashes the leaf opening following the layout of the config
+func hashLeaf(conf *smt.Config, leaf *LeafOpening) Bytes32 {
+
+	switch conf.LeafLayout {
+	case LeafLayoutV0:
+		if len(leaf.Extra) > 0 {
+			utils.Panic("the original leaf layout has no extra field, got %v", len(leaf.Extra))
+		}
+		return hash(conf, leaf)
+	case LeafLayoutV1:
+		hasher := conf.HashFunc()
+		WriteInt64On32Bytes(hasher, leafDomainSeparator<<8|int64(LeafLayoutV1))
+		leaf.WriteTo(hasher)
+		for _, f := range leaf.Extra {
+			f.WriteTo(hasher)
+		}
+		return AsBytes32(hasher.Sum(nil))
+	default:
+		utils.Panic("unsupported leaf layout %v", conf.LeafLayout)
+		return Bytes32{}
+	}
+}
+
+// extraEqual returns true if the two lists of extra fields are the same
+func extraEqual(a, b []Bytes32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package accumulator_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+
+	. "github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccount(i int) Account {
+	return Account{
+		Nonce:          int64(i),
+		Balance:        big.NewInt(int64(1000 * i)),
+		StorageRoot:    DummyBytes32(i),
+		MimcCodeHash:   DummyBytes32(i + 1),
+		KeccakCodeHash: FullBytes32(DummyBytes32(i + 2)),
+		CodeSize:       int64(i),
+	}
+}
+
+func TestLeafLayoutV1(t *testing.T) {
+
+	var (
+		confV0 = &smt.Config{HashFunc: hashtypes.MiMC, Depth: 40}
+		confV1 = accumulator.WithLeafLayout(confV0, accumulator.LeafLayoutV1)
+		accV0  = accumulator.InitializeProverState[DummyKey, Account](confV0, locationTesting)
+		acc    = accumulator.InitializeProverState[DummyKey, Account](confV1, locationTesting)
+		ver    = acc.VerifierState()
+	)
+
+	// The head and the tail are domain-separated, so are the empty trees
+	require.NotEqual(t, accV0.SubTreeRoot(), acc.SubTreeRoot())
+	require.NotEqual(t, accumulator.Head().Hash(confV0), accumulator.Head().Hash(confV1))
+
+	for i := 0; i < 4; i++ {
+		trace := acc.InsertAndProve(dumkey(i), testAccount(i))
+		require.Equal(t, testAccount(i).LeafExtraFields(), acc.Data.MustGet(acc.NextFreeNode-1).LeafOpening.Extra)
+		require.NoError(t, ver.VerifyInsertion(trace))
+	}
+
+	update := acc.UpdateAndProve(dumkey(1), testAccount(10))
+	require.NoError(t, ver.UpdateVerify(update))
+
+	read := acc.ReadNonZeroAndProve(dumkey(1))
+	require.NoError(t, ver.ReadNonZeroVerify(read))
+
+	readZero := acc.ReadZeroAndProve(dumkey(9))
+	require.NoError(t, ver.ReadZeroVerify(readZero))
+
+	deletion := acc.DeleteAndProve(dumkey(2))
+	require.NoError(t, ver.VerifyDeletion(deletion))
+
+	require.Equal(t, acc.SubTreeRoot(), ver.SubTreeRoot)
+
+	// The extra fields are bound to the value
+	read = acc.ReadNonZeroAndProve(dumkey(1))
+	read.LeafOpening.Extra = testAccount(11).LeafExtraFields()
+	require.Error(t, ver.ReadNonZeroVerify(read))
+
+	// The original layout has no extra field
+	opening := acc.Data.MustGet(2).LeafOpening
+	require.NotEmpty(t, opening.Extra)
+	require.Panics(t, func() { opening.Hash(confV0) })
+}
+
+func TestLeafLayoutValidation(t *testing.T) {
+	require.NoError(t, accumulator.ValidateLeafLayout(accumulator.LeafLayoutV0))
+	require.NoError(t, accumulator.ValidateLeafLayout(accumulator.LatestLeafLayout))
+	require.Error(t, accumulator.ValidateLeafLayout(accumulator.LatestLeafLayout+1))
+	require.Panics(t, func() {
+		accumulator.WithLeafLayout(&smt.Config{}, accumulator.LatestLeafLayout+1)
+	})
+}
@@ -19,6 +19,9 @@ type LeafOpening struct {
 	Next int64   `json:"nextLeaf"`
 	HKey Bytes32 `json:"hkey"` //it is mimc hash of the adress
 	HVal Bytes32 `json:"hval"` // is it mimc of account
+	// Extra lists the extra fields of the leaf in the versioned layouts,
+	// see [LeafLayoutV1]. It is empty in the original layout.
+	Extra []Bytes32 `json:"extra,omitempty"`
 }
 
 // KVOpeningTuple is simple a tuple type of (key, value) adding the
@@ -29,8 +32,9 @@ type KVOpeningTuple[K, V io.WriterTo] struct {
 	Value       V
 }
 
-// WriteTo implements the [io.WriterTo] interface and is used to hash the leaf
-// opening into the leaves that we store in the tree.
+// WriteTo implements the [io.WriterTo] interface and writes the fields of the
+// leaf opening in the original layout. The leaves stored in the tree are
+// obtained with [LeafOpening.Hash], which follows the layout of the tree.
 func (leaf *LeafOpening) WriteTo(w io.Writer) (int64, error) {
 	n0, _ := WriteInt64On32Bytes(w, leaf.Prev)
 	n1, _ := WriteInt64On32Bytes(w, leaf.Next)
@@ -44,9 +48,16 @@ func (leaf *LeafOpening) WriteTo(w io.Writer) (int64, error) {
 	return total, nil
 }
 
-// Hash returns a hash of the leaf opening
+// Hash returns a hash of the leaf opening, following the leaf layout of the
+// config.
 func (leaf LeafOpening) Hash(conf *smt.Config) Bytes32 {
-	return hash(conf, &leaf)
+	return hashLeaf(conf, &leaf)
+}
+
+// setValue sets the fields of the leaf opening derived from the value
+func (leaf *LeafOpening) setValue(conf *smt.Config, val io.WriterTo) {
+	leaf.HVal = hash(conf, val)
+	leaf.Extra = leafExtra(conf, val)
 }
 
 // Head returns the "head" of the accumulator set
@@ -90,6 +101,10 @@ func (t KVOpeningTuple[K, V]) CheckAndLeaf(conf *smt.Config) (Bytes32, error) {
 		return Bytes32{}, fmt.Errorf("inconsistent val and leaf opening")
 	}
 
+	if !extraEqual(t.LeafOpening.Extra, leafExtra(conf, t.Value)) {
+		return Bytes32{}, fmt.Errorf("inconsistent extra fields and leaf opening")
+	}
+
 	return t.LeafOpening.Hash(conf), nil
 }
 
@@ -132,12 +147,18 @@ func (t KVOpeningTuple[K, V]) CopyWithNext(next int64) KVOpeningTuple[K, V] {
 // CopyWithVal copies the tuple and give it a new new value
 func (t KVOpeningTuple[K, V]) CopyWithVal(conf *smt.Config, val V) KVOpeningTuple[K, V] {
 	t.Value = val
-	t.LeafOpening.HVal = hash(conf, val)
+	t.LeafOpening.setValue(conf, val)
 	return t
 }
 
 // String pretty prints a leaf opening
 func (l LeafOpening) String() string {
+	if len(l.Extra) > 0 {
+		return fmt.Sprintf(
+			"LeafOpening{Prev: %d, Next: %d, HKey: %s, HVal: %s, Extra: %v}",
+			l.Prev, l.Next, l.HKey.Hex(), l.HVal.Hex(), l.Extra,
+		)
+	}
 	return fmt.Sprintf(
 		"LeafOpening{Prev: %d, Next: %d, HKey: %s, HVal: %s}",
 		l.Prev, l.Next, l.HKey.Hex(), l.HVal.Hex(),
@@ -87,13 +87,13 @@ func (v *VerifierState[K, V]) ReadZeroVerify(trace ReadZeroTrace[K, V]) error {
 	}
 
 	// Test membership of leaf minus
-	leafMinus := hash(v.Config, &trace.OpeningMinus)
+	leafMinus := trace.OpeningMinus.Hash(v.Config)
 	if !trace.ProofMinus.Verify(v.Config, leafMinus, trace.SubRoot) {
 		return fmt.Errorf("merkle proof verification failed : minus")
 	}
 
 	// Test membership of leaf plus
-	leafPlus := hash(v.Config, &trace.OpeningPlus)
+	leafPlus := trace.OpeningPlus.Hash(v.Config)
 	if !trace.ProofPlus.Verify(v.Config, leafPlus, trace.SubRoot) {
 		return fmt.Errorf("merkle proof verification failed : plus")
 	}
@@ -113,10 +113,10 @@ func (trace ReadZeroTrace[K, V]) DeferMerkleChecks(
 ) []smt.ProvedClaim {
 
 	// Test membership of leaf minus
-	leafMinus := hash(config, &trace.OpeningMinus)
+	leafMinus := trace.OpeningMinus.Hash(config)
 
 	// Test membership of leaf plus
-	leafPlus := hash(config, &trace.OpeningPlus)
+	leafPlus := trace.OpeningPlus.Hash(config)
 
 	appendTo = append(appendTo, smt.ProvedClaim{Proof: trace.ProofMinus, Root: trace.SubRoot, Leaf: leafMinus})
 	return append(appendTo, smt.ProvedClaim{Proof: trace.ProofPlus, Root: trace.SubRoot, Leaf: leafPlus})
@@ -0,0 +1,172 @@
+package accumulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/consensys/linea-monorepo/prover/utils/collection"
+
+	//lint:ignore ST1001 -- the package contains a list of standard types for this repo
+	. "github.com/consensys/linea-monorepo/prover/utils/types"
+)
+
+// Snapshot is a serializable image of a [ProverState]. It stores the tree
+// along with the tuples of its leaves so that the state can be restored
+// without replaying the operations that led to it. This allows successive
+// proof jobs over consecutive blocks to carry the accumulator over instead of
+// rebuilding it from the witness.
+//
+// The `json` format and the order of the fields is important
+type Snapshot[K, V io.WriterTo] struct {
+	Location     string `json:"location"`
+	NextFreeNode int64  `json:"nextFreeNode"`
+	Depth        int    `json:"depth"`
+	// SubTreeRoot and TopRoot are digests of the snapshot. They are checked
+	// against the tree and the tuples when restoring the state.
+	SubTreeRoot    Bytes32     `json:"subTreeRoot"`
+	TopRoot        Bytes32     `json:"topRoot"`
+	OccupiedLeaves []Bytes32   `json:"occupiedLeaves"`
+	OccupiedNodes  [][]Bytes32 `json:"occupiedNodes"`
+	// Tuples lists the tuples stored in the accumulator, by increasing
+	// position.
+	Tuples []SnapshotTuple[K, V] `json:"tuples"`
+}
+
+// SnapshotTuple is a [KVOpeningTuple] tagged with its position in the tree
+type SnapshotTuple[K, V io.WriterTo] struct {
+	Pos         int64       `json:"pos"`
+	LeafOpening LeafOpening `json:"leafOpening"`
+	Key         K           `json:"key"`
+	Value       V           `json:"value"`
+}
+
+// Snapshot returns a snapshot of the current state of the accumulator. The
+// returned snapshot shares memory with the state and must not be modified
+// while the state is in use.
+func (s *ProverState[K, V]) Snapshot() Snapshot[K, V] {
+
+	positions := s.Data.ListAllKeys()
+	slices.Sort(positions)
+
+	tuples := make([]SnapshotTuple[K, V], len(positions))
+	for i, pos := range positions {
+		tuple := s.Data.MustGet(pos)
+		tuples[i] = SnapshotTuple[K, V]{
+			Pos:         pos,
+			LeafOpening: tuple.LeafOpening,
+			Key:         tuple.Key,
+			Value:       tuple.Value,
+		}
+	}
+
+	return Snapshot[K, V]{
+		Location:       s.Location,
+		NextFreeNode:   s.NextFreeNode,
+		Depth:          s.Config().Depth,
+		SubTreeRoot:    s.SubTreeRoot(),
+		TopRoot:        s.TopRoot(),
+		OccupiedLeaves: s.Tree.OccupiedLeaves,
+		OccupiedNodes:  s.Tree.OccupiedNodes,
+		Tuples:         tuples,
+	}
+}
+
+// WriteSnapshot serializes a snapshot of the accumulator in w
+func (s *ProverState[K, V]) WriteSnapshot(w io.Writer) error {
+	snap := s.Snapshot()
+	if err := json.NewEncoder(w).Encode(&snap); err != nil {
+		return fmt.Errorf("could not serialize the snapshot of accumulator %v: %w", s.Location, err)
+	}
+	return nil
+}
+
+// ReadSnapshot deserializes a snapshot from r and restores the accumulator
+// state it describes. See [Snapshot.ProverState].
+func ReadSnapshot[K, V io.WriterTo](r io.Reader, conf *smt.Config) (*ProverState[K, V], error) {
+	var snap Snapshot[K, V]
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("could not deserialize the accumulator snapshot: %w", err)
+	}
+	return snap.ProverState(conf)
+}
+
+// ProverState restores the accumulator state described by the snapshot. It
+// returns an error if the snapshot is not self-consistent: the tree must
+// match its recorded root, every tuple must be stored in the tree at its
+// position and the recorded top root must match the restored state. The
+// snapshot must not be modified afterwards as the state shares its memory.
+func (snap *Snapshot[K, V]) ProverState(conf *smt.Config) (*ProverState[K, V], error) {
+
+	if snap.Depth != conf.Depth {
+		return nil, fmt.Errorf("the snapshot is for a tree of depth %v, the config expects %v", snap.Depth, conf.Depth)
+	}
+
+	tree := smt.NewEmptyTree(conf)
+	tree.Root = snap.SubTreeRoot
+	tree.OccupiedLeaves = snap.OccupiedLeaves
+	tree.OccupiedNodes = snap.OccupiedNodes
+
+	if err := tree.CheckConsistency(); err != nil {
+		return nil, fmt.Errorf("accumulator %v: inconsistent tree: %w", snap.Location, err)
+	}
+
+	var (
+		data     = collection.NewMapping[int64, KVOpeningTuple[K, V]]()
+		nbLeaves = 0
+	)
+
+	for _, leaf := range tree.OccupiedLeaves {
+		if leaf != smt.EmptyLeaf() {
+			nbLeaves++
+		}
+	}
+
+	if nbLeaves != len(snap.Tuples) {
+		return nil, fmt.Errorf("accumulator %v: the tree has %v non-empty leaves but the snapshot has %v tuples", snap.Location, nbLeaves, len(snap.Tuples))
+	}
+
+	for _, t := range snap.Tuples {
+
+		if t.Pos < 0 || t.Pos >= snap.NextFreeNode || data.Exists(t.Pos) {
+			return nil, fmt.Errorf("accumulator %v: invalid or duplicate tuple position %v", snap.Location, t.Pos)
+		}
+
+		tuple := KVOpeningTuple[K, V]{LeafOpening: t.LeafOpening, Key: t.Key, Value: t.Value}
+		leaf, err := tuple.CheckAndLeaf(conf)
+		if err != nil {
+			return nil, fmt.Errorf("accumulator %v: tuple at position %v: %w", snap.Location, t.Pos, err)
+		}
+
+		if stored := tree.MustGetLeaf(int(t.Pos)); stored != leaf {
+			return nil, fmt.Errorf("accumulator %v: tuple at position %v hashes to %x but the tree stores %x", snap.Location, t.Pos, leaf, stored)
+		}
+
+		data.InsertNew(t.Pos, tuple)
+	}
+
+	res := &ProverState[K, V]{
+		Location:     snap.Location,
+		NextFreeNode: snap.NextFreeNode,
+		Tree:         tree,
+		Data:         data,
+	}
+
+	if err := res.CheckTopRoot(snap.TopRoot); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CheckTopRoot returns an error if the top root of the accumulator is not the
+// expected one. It is meant to detect that a state restored from a snapshot
+// diverged from the root known on-chain before using it for a new job.
+func (s *ProverState[K, V]) CheckTopRoot(expected Bytes32) error {
+	if actual := s.TopRoot(); actual != expected {
+		return fmt.Errorf("accumulator %v: top root mismatch, expected %x but got %x", s.Location, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package accumulator_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/accumulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+
+	acc := newTestAccumulatorKeccak()
+
+	for i := 0; i < 16; i++ {
+		acc.InsertAndProve(dumkey(i), dumval(i))
+	}
+	acc.UpdateAndProve(dumkey(3), dumval(100))
+	acc.DeleteAndProve(dumkey(5))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, acc.WriteSnapshot(buf))
+
+	restored, err := accumulator.ReadSnapshot[DummyKey, DummyVal](buf, acc.Config())
+	require.NoError(t, err)
+
+	assert.Equal(t, acc.TopRoot(), restored.TopRoot())
+	assert.Equal(t, acc.NextFreeNode, restored.NextFreeNode)
+	require.NoError(t, restored.CheckTopRoot(acc.TopRoot()))
+
+	// Both states must keep evolving identically
+	ver := restored.VerifierState()
+	for i := 16; i < 24; i++ {
+		acc.InsertAndProve(dumkey(i), dumval(i))
+		trace := restored.InsertAndProve(dumkey(i), dumval(i))
+		require.NoError(t, ver.VerifyInsertion(trace))
+	}
+	acc.DeleteAndProve(dumkey(7))
+	require.NoError(t, ver.VerifyDeletion(restored.DeleteAndProve(dumkey(7))))
+
+	assert.Equal(t, acc.TopRoot(), restored.TopRoot())
+}
+
+func TestSnapshotTampered(t *testing.T) {
+
+	acc := newTestAccumulatorKeccak()
+	for i := 0; i < 8; i++ {
+		acc.InsertAndProve(dumkey(i), dumval(i))
+	}
+
+	t.Run("value", func(t *testing.T) {
+		snap := acc.Snapshot()
+		snap.Tuples = append([]accumulator.SnapshotTuple[DummyKey, DummyVal]{}, snap.Tuples...)
+		snap.Tuples[3].Value = dumval(42)
+		_, err := snap.ProverState(acc.Config())
+		assert.Error(t, err)
+	})
+
+	t.Run("leaf", func(t *testing.T) {
+		snap := acc.Snapshot()
+		snap.OccupiedLeaves = append(snap.OccupiedLeaves[:0:0], snap.OccupiedLeaves...)
+		snap.OccupiedLeaves[4] = dumval(42)
+		_, err := snap.ProverState(acc.Config())
+		assert.Error(t, err)
+	})
+
+	t.Run("missing-tuple", func(t *testing.T) {
+		snap := acc.Snapshot()
+		snap.Tuples = snap.Tuples[:len(snap.Tuples)-1]
+		_, err := snap.ProverState(acc.Config())
+		assert.Error(t, err)
+	})
+
+	t.Run("top-root", func(t *testing.T) {
+		snap := acc.Snapshot()
+		snap.NextFreeNode++
+		_, err := snap.ProverState(acc.Config())
+		assert.Error(t, err)
+	})
+
+	// The original state must be untouched by the above
+	restored, err := accumulator.ReadSnapshot[DummyKey, DummyVal](snapshotBuffer(t, acc), acc.Config())
+	require.NoError(t, err)
+	assert.Equal(t, acc.TopRoot(), restored.TopRoot())
+	assert.Error(t, restored.CheckTopRoot(dumval(0)))
+}
+
+func snapshotBuffer(t *testing.T, acc *accumulator.ProverState[DummyKey, DummyVal]) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	require.NoError(t, acc.WriteSnapshot(buf))
+	return buf
+}
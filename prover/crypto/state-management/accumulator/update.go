@@ -52,7 +52,7 @@ func (p *ProverState[K, V]) UpdateAndProve(key K, newVal V) UpdateTrace[K, V] {
 
 	// Compute the new value and update the tree
 	tuple.Value = newVal
-	tuple.LeafOpening.HVal = hash(p.Config(), tuple.Value)
+	tuple.LeafOpening.setValue(p.Config(), tuple.Value)
 	p.Data.Update(i, tuple)
 
 	newLeaf := tuple.LeafOpening.Hash(p.Config())
@@ -102,10 +102,10 @@ func (v *VerifierState[K, V]) UpdateVerify(trace UpdateTrace[K, V]) error {
 
 	newTuple := tuple
 	newTuple.Value = trace.NewValue
-	newTuple.LeafOpening.HVal = hash(v.Config, trace.NewValue)
+	newTuple.LeafOpening.setValue(v.Config, trace.NewValue)
 
 	// We panic because if the consistency check passed
-	newLeaf := hash(v.Config, &newTuple.LeafOpening)
+	newLeaf := newTuple.LeafOpening.Hash(v.Config)
 
 	newRoot, err := updateCheckRoot(v.Config, trace.Proof, trace.OldSubRoot, leaf, newLeaf)
 	if err != nil {
@@ -142,10 +142,10 @@ func (trace UpdateTrace[K, V]) DeferMerkleChecks(
 
 	newTuple := tuple
 	newTuple.Value = trace.NewValue
-	newTuple.LeafOpening.HVal = hash(config, trace.NewValue)
+	newTuple.LeafOpening.setValue(config, trace.NewValue)
 
 	// We panic because if the consistency check passed
-	newLeaf := hash(config, &newTuple.LeafOpening)
+	newLeaf := newTuple.LeafOpening.Hash(config)
 	appendTo, _ = deferCheckUpdateRoot(config, trace.Proof, trace.OldSubRoot, leaf, newLeaf, appendTo)
 	return appendTo
 }
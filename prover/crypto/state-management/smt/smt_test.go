@@ -136,3 +136,32 @@ func TestBuildFromScratch(t *testing.T) {
 	require.Equal(t, oneByoneTree.Root.Hex(), tree.Root.Hex())
 
 }
+
+// Checks that the parallel construction yields the same tree as the one
+// obtained by adding the leaves one by one, including for trees that have
+// fewer leaves than there are subtrees to build in parallel.
+func TestBuildCompleteSmallTrees(t *testing.T) {
+
+	for depth := 1; depth <= 6; depth++ {
+
+		config := &smt.Config{
+			HashFunc: hashtypes.Keccak,
+			Depth:    depth,
+		}
+
+		leaves := make([]Bytes32, 1<<depth)
+		for i := range leaves {
+			leaves[i] = RandBytes32(i)
+		}
+
+		tree := smt.BuildComplete(leaves, config.HashFunc)
+
+		oneByoneTree := smt.NewEmptyTree(config)
+		for i := range leaves {
+			oneByoneTree.Update(i, leaves[i])
+		}
+
+		require.Equal(t, oneByoneTree.Root.Hex(), tree.Root.Hex(), "depth=%v", depth)
+		require.Equal(t, oneByoneTree.OccupiedNodes, tree.OccupiedNodes, "depth=%v", depth)
+	}
+}
@@ -2,6 +2,7 @@ package smt
 
 import (
 	"fmt"
+	"runtime"
 
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
 	"github.com/consensys/linea-monorepo/prover/utils"
@@ -15,6 +16,10 @@ type Config struct {
 	HashFunc func() hashtypes.Hasher
 	// Depth is the depth of the tree
 	Depth int
+	// LeafLayout is the version of the encoding of the leaves hashed into
+	// the tree. It is interpreted by the accumulator package, zero standing
+	// for the original layout.
+	LeafLayout uint8
 }
 
 // Tree represents a binary sparse Merkle-tree (SMT).
@@ -262,6 +267,12 @@ func (t *Tree) reserveLevel(level, newSize int) {
 // BuildComplete builds from scratch a complete Merkle-tree. Requires that the
 // input leaves are powers of 2. The depth of the tree is deduced from the list.
 //
+// The tree is split into as many independent subtrees as there are available
+// cores (rounded to a power of two). Each subtree is built bottom-up by a
+// single goroutine reusing the same hasher, so that the workers do not need
+// to synchronize between the levels. The few remaining nodes on top of the
+// subtrees are then hashed sequentially.
+//
 // It panics if the number of leaves is a non-power of 2.
 func BuildComplete(leaves []types.Bytes32, hashFunc func() hashtypes.Hasher) *Tree {
 
@@ -279,26 +290,124 @@ func BuildComplete(leaves []types.Bytes32, hashFunc func() hashtypes.Hasher) *Tr
 	tree := NewEmptyTree(config)
 	tree.OccupiedLeaves = leaves
 
-	// Builds the tree bottom-up
-	currLevels := leaves
+	// levels[i] stores the nodes of the level i + 1 of the tree (the leaves
+	// being level 0). The last level contains only the root.
+	levels := make([][]types.Bytes32, depth)
+	for i := range levels {
+		levels[i] = make([]types.Bytes32, numLeaves>>(i+1))
+	}
+
+	var (
+		numSubTrees      = min(utils.NextPowerOfTwo(runtime.GOMAXPROCS(0)), numLeaves/2)
+		subTreeDepth     = depth - utils.Log2Floor(numSubTrees)
+		leavesPerSubTree = numLeaves / numSubTrees
+	)
 
-	for i := 0; i < depth-1; i++ {
-		nextLevel := make([]types.Bytes32, len(currLevels)/2)
-		parallel.Execute(len(nextLevel), func(start, stop int) {
-			for k := start; k < stop; k++ {
-				nextLevel[k] = hashLR(config, currLevels[2*k], currLevels[2*k+1])
+	parallel.Execute(numSubTrees, func(start, stop int) {
+		hasher := hashFunc()
+		for s := start; s < stop; s++ {
+			currLevel := leaves[s*leavesPerSubTree : (s+1)*leavesPerSubTree]
+			for i := 0; i < subTreeDepth; i++ {
+				nextLevel := levels[i][s*len(currLevel)/2 : (s+1)*len(currLevel)/2]
+				for k := range nextLevel {
+					nextLevel[k] = hashLRWith(hasher, currLevel[2*k], currLevel[2*k+1])
+				}
+				currLevel = nextLevel
 			}
-		})
-		tree.OccupiedNodes[i] = nextLevel
-		currLevels = nextLevel
+		}
+	})
+
+	// Hashes the nodes above the subtrees
+	hasher := hashFunc()
+	for i := subTreeDepth; i < depth; i++ {
+		for k := range levels[i] {
+			levels[i][k] = hashLRWith(hasher, levels[i-1][2*k], levels[i-1][2*k+1])
+		}
 	}
 
-	// sanity-check : len(currLevels) == 2 is an invariant
-	if len(currLevels) != 2 {
-		utils.Panic("broken invariant : len(currLevels) != 2, =%v", len(currLevels))
+	// sanity-check : the last level should only contain the root
+	if len(levels[depth-1]) != 1 {
+		utils.Panic("broken invariant : len(levels[depth-1]) != 1, =%v", len(levels[depth-1]))
 	}
 
-	// And overwrite the root
-	tree.Root = hashLR(config, currLevels[0], currLevels[1])
+	copy(tree.OccupiedNodes, levels[:depth-1])
+	tree.Root = levels[depth-1][0]
 	return tree
 }
+
+// hashLRWith is as [hashLR] but reuses the provided hasher instead of
+// instantiating a new one.
+func hashLRWith(hasher hashtypes.Hasher, nodeL, nodeR types.Bytes32) types.Bytes32 {
+	hasher.Reset()
+	nodeL.WriteTo(hasher)
+	nodeR.WriteTo(hasher)
+	return types.AsBytes32(hasher.Sum(nil))
+}
+
+// CheckConsistency recomputes every non-trivial node of the tree from its
+// children and returns an error if one of them, or the root, does not match
+// the stored value. It is meant to audit trees that were not built by this
+// package, e.g. deserialized ones. The cost is about one hash per stored node.
+func (t *Tree) CheckConsistency() error {
+
+	depth := t.Config.Depth
+	if len(t.OccupiedNodes) != depth-1 || len(t.EmptyNodes) != depth-1 {
+		return fmt.Errorf("expected %v levels of intermediate nodes, got %v occupied and %v empty", depth-1, len(t.OccupiedNodes), len(t.EmptyNodes))
+	}
+
+	if len(t.OccupiedLeaves) > 1<<depth {
+		return fmt.Errorf("too many leaves for a tree of depth %v: %v", depth, len(t.OccupiedLeaves))
+	}
+
+	var (
+		hasher   = t.Config.HashFunc()
+		children = t.OccupiedLeaves
+		empty    = EmptyLeaf()
+	)
+
+	for level := 1; level < depth; level++ {
+		var (
+			nodes     = t.OccupiedNodes[level-1]
+			emptyNode = t.EmptyNodes[level-1]
+			nbParents = max(len(nodes), (len(children)+1)/2)
+		)
+
+		if nbParents > 1<<(depth-level) {
+			return fmt.Errorf("level %v: too many nodes: %v", level, len(nodes))
+		}
+
+		childAt := func(pos int) types.Bytes32 {
+			if pos < len(children) {
+				return children[pos]
+			}
+			return empty
+		}
+
+		for pos := 0; pos < nbParents; pos++ {
+			expected := hashLRWith(hasher, childAt(2*pos), childAt(2*pos+1))
+			actual := emptyNode
+			if pos < len(nodes) {
+				actual = nodes[pos]
+			}
+			if actual != expected {
+				return fmt.Errorf("level %v, position %v: stored node %x does not match its children's hash %x", level, pos, actual, expected)
+			}
+		}
+
+		children, empty = nodes, emptyNode
+	}
+
+	var top [2]types.Bytes32
+	for pos := range top {
+		top[pos] = empty
+		if pos < len(children) {
+			top[pos] = children[pos]
+		}
+	}
+
+	if root := hashLRWith(hasher, top[0], top[1]); root != t.Root {
+		return fmt.Errorf("stored root %x does not match its children's hash %x", t.Root, root)
+	}
+
+	return nil
+}
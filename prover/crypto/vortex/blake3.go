@@ -0,0 +1,30 @@
+package vortex
+
+import (
+	"hash"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/zeebo/blake3"
+)
+
+// Blake3 returns a Blake3 hasher whose digests are reduced modulo the field
+// so that they can be represented as field elements. It can be passed to
+// [Params.WithMerkleTreeHash] to speed up the construction of the Merkle
+// trees. It has no efficient in-circuit counterpart so it should only be used
+// for commitments that are neither self-recursed nor verified in a circuit.
+func Blake3() hash.Hash {
+	return fieldReducedHash{Hash: blake3.New()}
+}
+
+// fieldReducedHash wraps a hasher and reduces its digests modulo the field.
+type fieldReducedHash struct {
+	hash.Hash
+}
+
+// Sum appends the reduced digest to b and returns the resulting slice.
+func (h fieldReducedHash) Sum(b []byte) []byte {
+	var f field.Element
+	f.SetBytes(h.Hash.Sum(nil))
+	d := f.Bytes()
+	return append(b, d[:]...)
+}
@@ -4,13 +4,13 @@ import (
 	"hash"
 	"runtime"
 
-	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/maths/common/mempool"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+	"github.com/consensys/linea-monorepo/prover/utils/profiling"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/sirupsen/logrus"
 )
@@ -43,7 +43,6 @@ func (p *Params) CommitMerkle(ps []smartvectors.SmartVector) (encodedMatrix Enco
 	colHashes = p.hashColumns(encodedMatrix)
 	logrus.Infof("Vortex compiler: SIS hashing DONE")
 
-	logrus.Infof("Vortex compiler: SIS merkle hashing START")
 	// Hash the digest by chunk and build the tree using the chunk hashes as leaves.
 	var leaves []types.Bytes32
 
@@ -56,13 +55,9 @@ func (p *Params) CommitMerkle(ps []smartvectors.SmartVector) (encodedMatrix Enco
 		}
 	}
 
-	tree = smt.BuildComplete(
-		leaves,
-		func() hashtypes.Hasher {
-			return hashtypes.Hasher{Hash: p.HashFunc()}
-		},
-	)
-	logrus.Infof("Vortex compiler: SIS merkle hashing DONE")
+	stopTimer := profiling.LogTimer("Vortex compiler: Merkle tree construction nleaves=%v", len(leaves))
+	tree = smt.BuildComplete(leaves, p.merkleTreeHasher())
+	stopTimer()
 
 	return encodedMatrix, tree, colHashes
 }
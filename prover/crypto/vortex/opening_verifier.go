@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/maths/common/poly"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
@@ -165,10 +164,8 @@ func (v *VerifierInputs) checkColumnInclusion() error {
 
 	var (
 		mTreeHashConfig = &smt.Config{
-			HashFunc: func() hashtypes.Hasher {
-				return hashtypes.Hasher{Hash: v.Params.HashFunc()}
-			},
-			Depth: utils.Log2Ceil(v.Params.NumEncodedCols()),
+			HashFunc: v.Params.merkleTreeHasher(),
+			Depth:    utils.Log2Ceil(v.Params.NumEncodedCols()),
 		}
 	)
 
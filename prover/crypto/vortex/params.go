@@ -4,8 +4,11 @@ import (
 	"hash"
 
 	"github.com/consensys/linea-monorepo/prover/crypto/ringsis"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/maths/fft"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
 )
 
 // Params collects the public parameters of the commitment scheme. The object
@@ -40,6 +43,10 @@ type Params struct {
 	// NoSisHashFunc is an optional hash function that is used in place of the
 	// SIS. If it is set,
 	NoSisHashFunc func() hash.Hash
+	// MerkleTreeHashFunc is an optional hash function that is used to hash the
+	// internal nodes of the Merkle trees. If it is not set, HashFunc is used.
+	// See [Params.WithMerkleTreeHash].
+	MerkleTreeHashFunc func() hash.Hash
 }
 
 // NewParams creates and returns a [Params]:
@@ -107,6 +114,38 @@ func (p *Params) RemoveSis(h func() hash.Hash) *Params {
 	return p
 }
 
+// WithMerkleTreeHash sets the Vortex parameters to use another hash function
+// than HashFunc to compute the internal nodes of the Merkle trees. The leaves
+// are still computed using HashFunc (or NoSisHashFunc). The digests of h must
+// be canonical field elements as the roots are sent as such; see [Blake3].
+func (p *Params) WithMerkleTreeHash(h func() hash.Hash) *Params {
+
+	if h == nil {
+		utils.Panic("provided a nil Merkle tree hash function")
+	}
+
+	p.MerkleTreeHashFunc = h
+	return p
+}
+
+// merkleTreeHasher returns a function instantiating the hasher used for the
+// internal nodes of the Merkle trees.
+func (p *Params) merkleTreeHasher() func() hashtypes.Hasher {
+	h := p.HashFunc
+	if p.MerkleTreeHashFunc != nil {
+		h = p.MerkleTreeHashFunc
+	}
+	return func() hashtypes.Hasher {
+		return hashtypes.Hasher{Hash: h()}
+	}
+}
+
+// RebuildMerkleTree returns the Merkle tree of the leaves, as built by
+// [Params.CommitMerkle]. It allows restoring a tree from its leaves.
+func (p *Params) RebuildMerkleTree(leaves []types.Bytes32) *smt.Tree {
+	return smt.BuildComplete(leaves, p.merkleTreeHasher())
+}
+
 // HasSisReplacement returns true if the parameters are set to not use SIS
 func (p *Params) HasSisReplacement() bool {
 	return p.NoSisHashFunc != nil
@@ -7,6 +7,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/fft"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils/numa"
 )
 
 // rsEncode encodes a vector `v` and returns the corresponding the Reed-Solomon
@@ -39,7 +40,15 @@ func (p *Params) rsEncode(v smartvectors.SmartVector, pool mempool.MemPool) smar
 	asCoeffs.WriteInSlice(expandedCoeffs[:asCoeffs.Len()])
 
 	// This is not memory that will be recycled easily
-	return smartvectors.FFT(smartvectors.NewRegular(expandedCoeffs), fft.DIT, true, 0, 0, nil)
+	res := smartvectors.FFT(smartvectors.NewRegular(expandedCoeffs), fft.DIT, true, 0, 0, nil)
+
+	// The encoded rows are then read column by column by all the workers
+	// hashing the columns.
+	if pooled, ok := res.(*smartvectors.Pooled); ok {
+		numa.Interleave(pooled.Regular)
+	}
+
+	return res
 }
 
 // IsCodeword returns nil iff the argument `v` is a correct codeword and an
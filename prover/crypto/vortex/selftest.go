@@ -0,0 +1,153 @@
+package vortex
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/rand"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
+	"github.com/consensys/linea-monorepo/prover/crypto/ringsis"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// SelfTestCase is a parameterization of Vortex along with the root of the
+// Merkle commitment to the self-test matrix that it must yield, see
+// [SelfTest].
+type SelfTestCase struct {
+	Name         string
+	BlowUpFactor int
+	NbColumns    int
+	NbRows       int
+	// SIS is the ring-SIS instance hashing the columns. If nil, the columns
+	// are hashed with MiMC instead.
+	SIS *ringsis.Params
+	// MerkleTreeHash is an optional hash function for the internal nodes of
+	// the Merkle tree, see [Params.WithMerkleTreeHash].
+	MerkleTreeHash func() hash.Hash
+	// Golden is the expected root, as returned by [field.Element.Text] in
+	// base 16.
+	Golden string
+}
+
+// selfTestSeed seeds the generation of the self-test matrix. Changing it
+// changes all the golden values.
+const selfTestSeed = 0x766f72746578
+
+// SelfTestCases lists the parameterizations checked by [SelfTest]. They cover
+// the code paths of the full prover: the SIS instances and the blow-up
+// factors of its Vortex compilation steps, the no-SIS mode and the Blake3
+// Merkle trees.
+var SelfTestCases = []SelfTestCase{
+	{
+		Name:         "sis-16-6/blowup-2",
+		BlowUpFactor: 2,
+		NbColumns:    1 << 8,
+		NbRows:       16,
+		SIS:          &ringsis.StdParams,
+		Golden:       "5ec892b9f2d8f00d930412014e9e8aa5a1a59607e7124b03132c4cae865dd21",
+	},
+	{
+		Name:         "sis-16-6/blowup-8",
+		BlowUpFactor: 8,
+		NbColumns:    1 << 6,
+		NbRows:       16,
+		SIS:          &ringsis.StdParams,
+		Golden:       "55303c4f1715e11f53c3e0445b5f3039a780749eeab403dbd15a16011efd397",
+	},
+	{
+		Name:         "sis-8-6/blowup-2",
+		BlowUpFactor: 2,
+		NbColumns:    1 << 8,
+		NbRows:       16,
+		SIS:          &ringsis.Params{LogTwoBound: 8, LogTwoDegree: 6},
+		Golden:       "d3629bc9b900f48052e7aa35cecb1a58dabcff5b8e48ba8a09406d3a6709696",
+	},
+	{
+		Name:         "no-sis/blowup-2",
+		BlowUpFactor: 2,
+		NbColumns:    1 << 8,
+		NbRows:       16,
+		Golden:       "2278472b382b1ef9c15cce792240c579c8385fc761f67450fd685281b10da1b",
+	},
+	{
+		Name:           "sis-16-6/blowup-2/blake3",
+		BlowUpFactor:   2,
+		NbColumns:      1 << 8,
+		NbRows:         16,
+		SIS:            &ringsis.StdParams,
+		MerkleTreeHash: Blake3,
+		Golden:         "5557e5b57941e3fc4eec7d6fa5de52ab026f25dbd3b09a05035788e26137abb",
+	},
+}
+
+// Params returns the Vortex parameters of the test case
+func (c *SelfTestCase) Params() *Params {
+
+	sis := ringsis.StdParams
+	if c.SIS != nil {
+		sis = *c.SIS
+	}
+
+	p := NewParams(c.BlowUpFactor, c.NbColumns, c.NbRows, sis, mimc.NewMiMC)
+	if c.SIS == nil {
+		p.RemoveSis(mimc.NewMiMC)
+	}
+	if c.MerkleTreeHash != nil {
+		p.WithMerkleTreeHash(c.MerkleTreeHash)
+	}
+	return p
+}
+
+// Root commits to the self-test matrix of the test case and returns the root
+// of the Merkle tree.
+func (c *SelfTestCase) Root() field.Element {
+	_, tree, _ := c.Params().CommitMerkle(selfTestMatrix(c.NbRows, c.NbColumns))
+	var root field.Element
+	root.SetBytes(tree.Root[:])
+	return root
+}
+
+// selfTestMatrix returns the matrix committed by the self-test. It is
+// generated from a fixed seed so that it is the same on every machine. The
+// first row is constant so that the specialized encoding of the constant
+// rows is covered as well.
+func selfTestMatrix(nbRows, nbColumns int) []smartvectors.SmartVector {
+
+	var (
+		rng  = rand.New(rand.NewSource(selfTestSeed))
+		rows = make([]smartvectors.SmartVector, nbRows)
+	)
+
+	rows[0] = smartvectors.NewConstant(field.NewElement(rng.Uint64()), nbColumns)
+
+	for i := 1; i < nbRows; i++ {
+		row := make([]field.Element, nbColumns)
+		for j := range row {
+			row[j] = field.NewElement(rng.Uint64())
+		}
+		rows[i] = smartvectors.NewRegular(row)
+	}
+
+	return rows
+}
+
+// SelfTest commits to a fixed pseudo-random matrix with each of the
+// [SelfTestCases] and compares the roots with the golden values. It is meant
+// to be run at startup: a mismatch means that the field arithmetic, the FFTs
+// or the hash functions behave differently on this machine than on the one
+// that generated the golden values, e.g. because of miscompiled assembly, and
+// that the proofs it generates would not be verifiable. The returned error
+// lists all the cases that failed.
+func SelfTest() error {
+	var errs []error
+	for i := range SelfTestCases {
+		c := &SelfTestCases[i]
+		root := c.Root()
+		if got := root.Text(16); got != c.Golden {
+			errs = append(errs, fmt.Errorf("vortex self-test %v: got root 0x%v, expected 0x%v", c.Name, got, c.Golden))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,26 @@
+package vortex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+
+	require.NoError(t, SelfTest())
+
+	// the matrix is the same from one call to the next
+	assert.Equal(t, selfTestMatrix(4, 8)[2], selfTestMatrix(4, 8)[2])
+
+	// a mismatch is reported with the name of the failing case
+	saved := SelfTestCases[1].Golden
+	SelfTestCases[1].Golden = "1"
+	defer func() { SelfTestCases[1].Golden = saved }()
+
+	err := SelfTest()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, SelfTestCases[1].Name)
+	assert.NotContains(t, err.Error(), SelfTestCases[0].Name+":")
+}
@@ -87,7 +87,8 @@ func main() {
 		ccs, err := aggregation.MakeCS(
 			nc,
 			piSetup,
-			vkeys)
+			vkeys,
+			nil)
 		assert.NoError(t, err)
 
 		// Generate the setup
@@ -164,7 +165,7 @@ func main() {
 		// Assigning the BW6 circuit
 		logrus.Infof("Generating the aggregation proof for arity %v", nc)
 
-		bw6Proof, err := aggregation.MakeProof(&ppBw6, nc, innerProofClaims, piInfo, aggregationPI)
+		bw6Proof, err := aggregation.MakeProof(&ppBw6, nc, innerProofClaims, piInfo, aggregationPI, aggregation.ClaimPreparation{})
 		assert.NoError(t, err)
 
 		bw6Proofs = append(bw6Proofs, bw6Proof)
@@ -188,6 +189,24 @@ func main() {
 		assert.NoError(t, err)
 	}
 
+	// And the BN254 circuit wrapping two BW6 proofs at once
+	logrus.Infof("Generating the multi-emulation circuit")
+	ccsMultiEmulation, err := emulation.MakeMultiCS(bw6Vkeys, 2)
+	assert.NoError(t, err)
+
+	logrus.Infof("Generating the setup for the multi-emulation circuit")
+	setupMultiEmulation, err := circuits.MakeSetup(context.Background(), circuits.EmulationCircuitID+"-2", ccsMultiEmulation, circuits.NewUnsafeSRSProvider(), nil)
+	assert.NoError(t, err)
+
+	logrus.Infof("Generating the proof for the multi-emulation circuit (BW6 Proofs #0 and #%v)", len(bw6Proofs)-1)
+	_, err = emulation.MakeMultiProof(
+		&setupMultiEmulation,
+		[]int{0, len(bw6Proofs) - 1},
+		[]plonk.Proof{bw6Proofs[0], bw6Proofs[len(bw6Proofs)-1]},
+		[]frBn254.Element{aggregationPiBn254, aggregationPiBn254},
+	)
+	assert.NoError(t, err)
+
 }
 
 const (
@@ -1,7 +1,9 @@
 package blob
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +11,7 @@ import (
 	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/hash"
 	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression/v0/compress"
+	v0 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v0"
 	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 )
 
@@ -34,6 +37,36 @@ func DictionaryChecksum(dict []byte, version uint16) ([]byte, error) {
 	return nil, errors.New("unsupported version")
 }
 
+// GetDictChecksum returns the checksum of the dictionary the blob was
+// compressed with, as recorded in its header. It does not decompress the
+// blob, so it can be used to select the dictionary to decompress it with.
+func GetDictChecksum(blob []byte) ([]byte, error) {
+	switch version := GetVersion(blob); version {
+	case 1:
+		b, err := v1.UnpackAlign(blob, fr381.Bits-1, false)
+		if err != nil {
+			return nil, err
+		}
+		var header v1.Header
+		if _, err := header.ReadFrom(bytes.NewReader(b)); err != nil {
+			return nil, fmt.Errorf("failed to read blob header: %w", err)
+		}
+		return header.DictChecksum[:], nil
+	case 0:
+		b, err := v0.UnpackAlign(blob)
+		if err != nil {
+			return nil, err
+		}
+		var header v0.Header
+		if _, err := header.ReadFrom(bytes.NewReader(b)); err != nil {
+			return nil, fmt.Errorf("failed to read blob header: %w", err)
+		}
+		return header.DictChecksum[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported blob version: %v", version)
+	}
+}
+
 // GetRepoRootPath assumes that current working directory is within the repo
 func GetRepoRootPath() (string, error) {
 	wd, err := os.Getwd()
@@ -40,6 +40,8 @@ type BlobMaker struct {
 	Limit      int              // maximum size of the compressed data
 	compressor *lzss.Compressor // compressor used to compress the blob body
 	dict       []byte           // dictionary used for compression
+	profile    Profile          // compression effort, see SetProfile
+	estimator  *sizeEstimator   // lazily built by EstimateSize
 
 	Header Header
 
@@ -54,18 +56,31 @@ type BlobMaker struct {
 	packBuffer bytes.Buffer
 }
 
-// NewBlobMaker returns a new bm.
+// NewBlobMaker returns a new bm using the [ProfileBalanced] profile. The data
+// limit may not exceed the capacity of the decompression circuit,
+// [MaxUsableBytes].
 func NewBlobMaker(dataLimit int, dictPath string) (*BlobMaker, error) {
+	dict, err := os.ReadFile(dictPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlobMakerFromDict(dataLimit, dict)
+}
+
+// NewBlobMakerFromDict is as [NewBlobMaker] but takes the content of the
+// dictionary instead of its path.
+func NewBlobMakerFromDict(dataLimit int, dict []byte) (*BlobMaker, error) {
+	if dataLimit <= 0 || dataLimit > MaxUsableBytes {
+		return nil, fmt.Errorf("the data limit must be positive and at most the %v bytes of the decompression circuit, got %v", MaxUsableBytes, dataLimit)
+	}
+
 	blobMaker := BlobMaker{
-		Limit: dataLimit,
+		Limit:   dataLimit,
+		profile: ProfileBalanced,
 	}
 	blobMaker.buf.Grow(1 << 17)
 
 	// initialize compressor
-	dict, err := os.ReadFile(dictPath)
-	if err != nil {
-		return nil, err
-	}
 	dict = lzss.AugmentDict(dict)
 	blobMaker.dict = dict
 
@@ -86,6 +101,22 @@ func NewBlobMaker(dataLimit int, dictPath string) (*BlobMaker, error) {
 	return &blobMaker, nil
 }
 
+// SetProfile changes the compression profile. It applies to the next writes
+// and can be called at any time, the blocks already written are kept. It
+// returns an error and keeps the current profile if the profile is invalid.
+func (bm *BlobMaker) SetProfile(p Profile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	bm.profile = p
+	return nil
+}
+
+// Profile returns the compression profile in use
+func (bm *BlobMaker) Profile() Profile {
+	return bm.profile
+}
+
 // StartNewBatch starts a new batch of blocks.
 func (bm *BlobMaker) StartNewBatch() {
 	bm.Header.sealBatch()
@@ -159,6 +190,22 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 		logrus.Warn("block size is larger than the blob Limit. This should be checked by the coordinator, keeping the log for sanity", "block size", blockLen, "Limit", bm.Limit)
 	}
 
+	// with the fast profiles, a nearly full blob is sealed without spending
+	// a compression on the block.
+	if bm.currentBlobLength > 0 && float64(bm.currentBlobLength) >= bm.profile.FillTarget*float64(bm.Limit) {
+		return false, nil
+	}
+
+	// the payload preceding the block, to restore it after a recompression
+	prevWritten := bm.compressor.Written()
+	recompressed := false
+	revert := func() error {
+		if recompressed {
+			return bm.rewrite(bm.compressor.WrittenBytes()[:prevWritten])
+		}
+		return bm.compressor.Revert()
+	}
+
 	// write the block to the bm
 	if _, err = bm.compressor.Write(bm.buf.Bytes()); err != nil {
 		// The 2 possibles errors are:
@@ -179,7 +226,7 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 	}
 
 	// check that the header + the uncompressed data is "decompressable" in the circuit
-	if uint64(bm.compressor.Written()+bm.buf.Len()) > MaxUncompressedBytes {
+	if bm.compressor.Written()+bm.buf.Len() > bm.profile.MaxUncompressedBytes {
 		// it means we are not exploiting the full blob capacity; our compression ratio is "too good"
 		// and our decompression circuit is not able to handle the uncompressed data.
 		// we should reset the state.
@@ -191,19 +238,30 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 	}
 
 	// check that the header + the compressed data fits in the blob
-	fitsInBlob := PackAlignSize(bm.buf.Len()+bm.compressor.Len(), fr381.Bits-1) <= bm.Limit
-	if !fitsInBlob {
+	if !bm.fits() {
 		// first thing to check is if we bypass compression, would that fit?
 		if bm.compressor.ConsiderBypassing() {
 			// we can bypass compression and get a better ratio.
 			// let's check if now we fit in the blob.
-			if PackAlignSize(bm.buf.Len()+bm.compressor.Len(), fr381.Bits-1) <= bm.Limit {
+			if bm.fits() {
+				goto bypass
+			}
+		}
+
+		// then, with the max profile, if compressing the payload in one go
+		// would save enough.
+		if bm.profile.RecompressOnOverflow {
+			recompressed = true
+			if err = bm.rewrite(bm.compressor.WrittenBytes()); err != nil {
+				return false, fmt.Errorf("when recompressing the blob: %w", err)
+			}
+			if bm.fits() {
 				goto bypass
 			}
 		}
 
 		// discard.
-		if err = bm.compressor.Revert(); err != nil {
+		if err = revert(); err != nil {
 			return false, fmt.Errorf("when reverting compressor because blob is full: %w", err)
 		}
 		bm.Header.removeLastBlock()
@@ -212,7 +270,7 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 bypass:
 	if forceReset {
 		// we don't want to append the data, but we could have.
-		if err = bm.compressor.Revert(); err != nil {
+		if err = revert(); err != nil {
 			return false, fmt.Errorf("when reverting compressor (blob is not full but forceReset == true): %w", err)
 		}
 		bm.Header.removeLastBlock()
@@ -223,7 +281,7 @@ bypass:
 	bm.packBuffer.Reset()
 	n2, err := PackAlign(&bm.packBuffer, bm.buf.Bytes(), fr381.Bits-1, WithAdditionalInput(bm.compressor.Bytes()))
 	if err != nil {
-		bm.compressor.Revert()
+		revert()
 		bm.Header.removeLastBlock()
 		return false, fmt.Errorf("when packing blob: %w", err)
 	}
@@ -233,6 +291,24 @@ bypass:
 	return true, nil
 }
 
+// fits returns true if the header, written in bm.buf, and the compressed
+// data fit in the blob.
+func (bm *BlobMaker) fits() bool {
+	return PackAlignSize(bm.buf.Len()+bm.compressor.Len(), fr381.Bits-1) <= bm.Limit
+}
+
+// rewrite compresses the payload from scratch in a single write. The payload
+// may alias the internal buffer of the compressor.
+func (bm *BlobMaker) rewrite(payload []byte) error {
+	payload = bytes.Clone(payload)
+	bm.compressor.Reset()
+	if _, err := bm.compressor.Write(payload); err != nil {
+		return err
+	}
+	bm.compressor.ConsiderBypassing()
+	return nil
+}
+
 // Clone returns a (almost) deep copy of the bm -- this is used for test purposes.
 func (bm *BlobMaker) Clone() *BlobMaker {
 	deepCopy := *bm
@@ -0,0 +1,328 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/compress/lzss"
+	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// estimateTolerance is the relative error of the size estimated by
+	// [BlobMaker.EstimateSize] with respect to the size of the compressed
+	// payload. On the blocks of the prover test data, the estimate is within
+	// 1% of the compressed size past a few kilobytes; the tolerance leaves a
+	// margin for less regular blocks and for the nearly empty blobs.
+	estimateTolerance = 0.1
+
+	// parameters of the greedy parser of the estimator. They mirror the
+	// backrefs of lzss: a backref costs 8+14+8 bits if its address fits in 14
+	// bits and 8+21+8 bits otherwise, and is at most 256 bytes long.
+	estimateMinMatch     = 4
+	estimateMaxMatch     = 1 << 8
+	estimateShortWindow  = 1 << 14
+	estimateShortBits    = 8 + 14 + 8
+	estimateDynamicBits  = 8 + 21 + 8
+	estimateHashLog2     = 20
+	estimateChainLen     = 16
+	estimateNoCandidates = -1
+)
+
+// SizeEstimate is an estimation of the length of the blob, header included,
+// after writing a batch of blocks. See [BlobMaker.EstimateSize].
+type SizeEstimate struct {
+	// Size is the estimated length of the blob
+	Size int
+	// Min is the lower bound of the estimation, up to its tolerance. A blob
+	// whose Min exceeds the limit is very unlikely to accept the blocks.
+	Min int
+	// Max is the length of the blob if the compression of the new blocks was
+	// bypassed. The blob maker falls back to it when the compressed payload
+	// does not fit, hence the blocks always fit in the limit if Max does.
+	Max int
+	// Uncompressed is the length of the header and of the uncompressed
+	// payload, that may not exceed the MaxUncompressedBytes of the profile.
+	Uncompressed int
+}
+
+// EstimateVerdict is the answer of [BlobMaker.EstimateCanWrite]
+type EstimateVerdict uint8
+
+const (
+	// EstimateFits means that writing the blocks is guaranteed to succeed
+	EstimateFits EstimateVerdict = iota
+	// EstimateDoesNotFit means that writing the blocks would most likely
+	// fail, or is guaranteed to fail if it exceeds the uncompressed capacity
+	EstimateDoesNotFit
+	// EstimateNearBoundary means that the estimation is not conclusive and
+	// that the exact answer requires a call to [BlobMaker.Write]
+	EstimateNearBoundary
+)
+
+func (v EstimateVerdict) String() string {
+	switch v {
+	case EstimateFits:
+		return "fits"
+	case EstimateDoesNotFit:
+		return "does-not-fit"
+	case EstimateNearBoundary:
+		return "near-boundary"
+	default:
+		return fmt.Sprintf("EstimateVerdict(%d)", uint8(v))
+	}
+}
+
+// sizeEstimator approximates lzss with a greedy parser that looks for the
+// backrefs in a hash table of the positions of the 4-byte sequences of the
+// dictionary and of the payload. Unlike the compressor, which builds a suffix
+// array over the whole payload at every write, the table is updated
+// incrementally as the blob grows.
+type sizeEstimator struct {
+	// history is the dictionary followed by the payload already indexed
+	history []byte
+	dictLen int
+	// table maps the hash of 4 bytes to the last position of history where
+	// they were seen, or -1, and chain maps a position of history to the
+	// previous one with the same hash.
+	table []int32
+	chain []int32
+}
+
+func newSizeEstimator(dict []byte) *sizeEstimator {
+	e := &sizeEstimator{
+		history: make([]byte, 0, len(dict)+MaxUncompressedBytes),
+		dictLen: len(dict),
+		table:   make([]int32, 1<<estimateHashLog2),
+		chain:   make([]int32, 0, len(dict)+MaxUncompressedBytes),
+	}
+	e.reset(dict)
+	return e
+}
+
+// reset drops the indexed payload and indexes the dictionary
+func (e *sizeEstimator) reset(dict []byte) {
+	for i := range e.table {
+		e.table[i] = estimateNoCandidates
+	}
+	e.history = append(e.history[:0], dict...)
+	e.chain = e.chain[:0]
+	e.index(0, len(e.history))
+}
+
+// sync indexes the payload written to the compressor since the last call. It
+// starts over if the payload shrank, which happens after a Reset.
+func (e *sizeEstimator) sync(dict, written []byte) {
+	indexed := len(e.history) - e.dictLen
+	if len(written) < indexed || !bytes.Equal(written[:indexed], e.history[e.dictLen:]) {
+		e.reset(dict)
+		indexed = 0
+	}
+	start := len(e.history)
+	e.history = append(e.history, written[indexed:]...)
+	e.index(start, len(e.history))
+}
+
+// index adds the positions [start, end) of history to the table. The
+// positions must be indexed in order.
+func (e *sizeEstimator) index(start, end int) {
+	for i := start; i < end; i++ {
+		if i+estimateMinMatch > len(e.history) {
+			e.chain = append(e.chain, estimateNoCandidates)
+			continue
+		}
+		h := hash4(e.history[i:])
+		e.chain = append(e.chain, e.table[h])
+		e.table[h] = int32(i)
+	}
+}
+
+// truncate drops the positions of history from n on
+func (e *sizeEstimator) truncate(n int) {
+	for i := len(e.chain) - 1; i >= n; i-- {
+		if i+estimateMinMatch > len(e.history) {
+			continue
+		}
+		if h := hash4(e.history[i:]); int(e.table[h]) == i {
+			e.table[h] = e.chain[i]
+		}
+	}
+	e.history = e.history[:n]
+	e.chain = e.chain[:n]
+}
+
+// longestMatch returns the longest match of the data at position i of
+// history among the positions preceding it with the same hash.
+func (e *sizeEstimator) longestMatch(i int) (length, address int) {
+	length, address = 0, estimateNoCandidates
+	if i+estimateMinMatch > len(e.history) {
+		return
+	}
+	c := int(e.table[hash4(e.history[i:])])
+	for n := 0; n < estimateChainLen && c != estimateNoCandidates; n++ {
+		if l := matchLen(e.history[c:i], e.history[i:]); l > length {
+			length, address = l, c
+		}
+		c = int(e.chain[c])
+	}
+	return
+}
+
+// compressedBits estimates the number of bits lzss outputs for data when
+// appended to the indexed history. The history is left unchanged.
+func (e *sizeEstimator) compressedBits(data []byte) int {
+	start := len(e.history)
+	e.history = append(e.history, data...)
+	defer e.truncate(start)
+
+	bits := 0
+	for i := start; i < len(e.history); {
+
+		length, address := e.longestMatch(i)
+
+		if length < estimateMinMatch {
+			if b := e.history[i]; b == lzss.SymbolShort || b == lzss.SymbolDynamic {
+				// the reserved symbols are backrefs of length 1 to the dictionary
+				bits += estimateDynamicBits
+			} else {
+				bits += 8
+			}
+			e.index(i, i+1)
+			i++
+			continue
+		}
+
+		if address >= e.dictLen && i-address <= estimateShortWindow {
+			bits += estimateShortBits
+		} else {
+			bits += estimateDynamicBits
+		}
+		e.index(i, i+length)
+		i += length
+	}
+
+	return bits
+}
+
+// hash4 is the multiplicative hash of the first 4 bytes of b
+func hash4(b []byte) uint32 {
+	return (binary.LittleEndian.Uint32(b) * 2654435761) >> (32 - estimateHashLog2)
+}
+
+// matchLen returns the length of the common prefix of a and b, capped to the
+// maximum length of a backref.
+func matchLen(a, b []byte) int {
+	n := min(len(a), len(b), estimateMaxMatch)
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// EstimateSize estimates the length of the blob after writing the RLP
+// encoded blocks in the current batch, without compressing them. It is meant
+// to make cheap packing decisions: the estimate is within a fixed tolerance of
+// the exact size, see [SizeEstimate], and only the blocks whose estimate is
+// close to the limit need to go through [BlobMaker.Write]. The estimation
+// does not modify the blob.
+//
+// The first call indexes the dictionary, and every call indexes the payload
+// written since the previous one. Hence, it is cheap when called between
+// successive writes.
+func (bm *BlobMaker) EstimateSize(rlpBlocks ...[]byte) (SizeEstimate, error) {
+
+	var (
+		payload   bytes.Buffer
+		header    bytes.Buffer
+		blockLens = make([]int, len(rlpBlocks))
+	)
+
+	for i, rlpBlock := range rlpBlocks {
+		var block types.Block
+		if err := rlp.Decode(bytes.NewReader(rlpBlock), &block); err != nil {
+			return SizeEstimate{}, fmt.Errorf("when decoding input RLP block: %w", err)
+		}
+		start := payload.Len()
+		if err := EncodeBlockForCompression(&block, &payload); err != nil {
+			return SizeEstimate{}, fmt.Errorf("when re-encoding block for compression: %w", err)
+		}
+		blockLens[i] = payload.Len() - start
+	}
+
+	// the header is written as if the blocks were in the batch
+	for _, l := range blockLens {
+		bm.Header.addBlock(l)
+	}
+	_, err := bm.Header.WriteTo(&header)
+	for range blockLens {
+		bm.Header.removeLastBlock()
+	}
+	if err != nil {
+		return SizeEstimate{}, fmt.Errorf("when writing header to buffer: %w", err)
+	}
+
+	if bm.estimator == nil {
+		bm.estimator = newSizeEstimator(bm.dict)
+	}
+	bm.estimator.sync(bm.dict, bm.compressor.WrittenBytes())
+
+	var (
+		written      = bm.compressor.Written()
+		bypassedSize = written + payload.Len() + lzss.HeaderSize
+		compressed   int
+	)
+
+	if written > 0 && bm.compressor.Len() == written+lzss.HeaderSize {
+		// the compression is bypassed, the blocks are appended as is
+		compressed = bypassedSize
+	} else {
+		compressed = bm.compressor.Len() + (bm.estimator.compressedBits(payload.Bytes())+7)/8
+		compressed = min(compressed, bypassedSize)
+	}
+
+	return SizeEstimate{
+		Size:         PackAlignSize(header.Len()+compressed, fr381.Bits-1),
+		Min:          PackAlignSize(header.Len()+int(float64(compressed)*(1-estimateTolerance)), fr381.Bits-1),
+		Max:          PackAlignSize(header.Len()+bypassedSize, fr381.Bits-1),
+		Uncompressed: header.Len() + written + payload.Len(),
+	}, nil
+}
+
+// EstimateCanWrite tells, based on [BlobMaker.EstimateSize], whether the RLP
+// encoded blocks can be written in the current batch. When the answer is
+// [EstimateNearBoundary], the caller must fall back to [BlobMaker.Write].
+func (bm *BlobMaker) EstimateCanWrite(rlpBlocks ...[]byte) (EstimateVerdict, error) {
+
+	// mirrors the early rejections of Write
+	if bm.currentBlobLength > 0 && float64(bm.currentBlobLength) >= bm.profile.FillTarget*float64(bm.Limit) {
+		return EstimateDoesNotFit, nil
+	}
+
+	e, err := bm.EstimateSize(rlpBlocks...)
+	if err != nil {
+		return EstimateNearBoundary, err
+	}
+
+	// past the first block, the writes are also rejected once the blob
+	// reaches the fill target of the profile.
+	fitLimit := bm.Limit
+	if len(rlpBlocks) > 1 {
+		fitLimit = min(fitLimit, int(bm.profile.FillTarget*float64(bm.Limit)))
+	}
+
+	switch {
+	case e.Uncompressed > bm.profile.MaxUncompressedBytes:
+		return EstimateDoesNotFit, nil
+	case e.Max <= fitLimit:
+		return EstimateFits, nil
+	case e.Min > bm.Limit:
+		return EstimateDoesNotFit, nil
+	default:
+		return EstimateNearBoundary, nil
+	}
+}
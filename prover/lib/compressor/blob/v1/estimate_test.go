@@ -0,0 +1,82 @@
+//go:build !fuzzlight
+
+package v1_test
+
+import (
+	"testing"
+
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateSize(t *testing.T) {
+
+	// a small limit so that the blob fills up several times
+	bm, err := v1.NewBlobMaker(24*1024, testDictPath)
+	require.NoError(t, err)
+
+	var nbFits, nbDoesNotFit int
+
+	for i := 0; i < 4*len(testBlocks); i++ {
+		block := testBlocks[(7*i)%len(testBlocks)]
+
+		c := bm.Clone()
+		e, err := bm.EstimateSize(block)
+		require.NoError(t, err)
+		verdict, err := bm.EstimateCanWrite(block)
+		require.NoError(t, err)
+		require.True(t, bm.Equals(c), "the estimation should not mutate the blob maker")
+		require.LessOrEqual(t, e.Min, e.Size)
+
+		ok, err := bm.Write(block, false)
+		require.NoError(t, err)
+
+		switch verdict {
+		case v1.EstimateFits:
+			nbFits++
+			require.True(t, ok, "block %d: the block should fit, estimate %+v", i, e)
+		case v1.EstimateDoesNotFit:
+			nbDoesNotFit++
+			require.False(t, ok, "block %d: the block should not fit, estimate %+v", i, e)
+		}
+
+		if !ok {
+			bm.Reset()
+			continue
+		}
+
+		assert.LessOrEqual(t, e.Min, bm.Len(), "block %d: the blob is smaller than the lower bound", i)
+		assert.InDelta(t, bm.Len(), e.Size, 0.1*float64(bm.Len())+32, "block %d: the estimate is off", i)
+	}
+
+	assert.NotZero(t, nbFits)
+	assert.NotZero(t, nbDoesNotFit)
+}
+
+func TestEstimateSizeMultipleBlocks(t *testing.T) {
+
+	bm, err := v1.NewBlobMaker(v1.MaxUsableBytes, testDictPath)
+	require.NoError(t, err)
+
+	blocks := testBlocks[:len(testBlocks)/2]
+	e, err := bm.EstimateSize(blocks...)
+	require.NoError(t, err)
+
+	verdict, err := bm.EstimateCanWrite(blocks...)
+	require.NoError(t, err)
+	require.Equal(t, v1.EstimateFits, verdict)
+
+	for _, block := range blocks {
+		ok, err := bm.Write(block, false)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	assert.LessOrEqual(t, e.Min, bm.Len())
+	assert.LessOrEqual(t, bm.Len(), e.Max)
+	assert.InDelta(t, bm.Len(), e.Size, 0.1*float64(bm.Len()))
+
+	_, err = bm.EstimateSize([]byte{0x01, 0x02})
+	assert.Error(t, err, "invalid RLP")
+}
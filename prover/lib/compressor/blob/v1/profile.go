@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Profile sets how much CPU the blob maker spends to fill the blobs. The
+// dictionary is not part of the profile as the decompression circuit only
+// accepts the dictionary it was set up with.
+type Profile struct {
+	Name string
+
+	// MaxUncompressedBytes bounds the size of the header and of the
+	// uncompressed payload of a blob. Every write compresses the block against
+	// the whole payload, hence a lower bound makes the writes cheaper but
+	// seals the highly compressible blobs earlier. It may not exceed the
+	// capacity of the decompression circuit, [MaxUncompressedBytes].
+	MaxUncompressedBytes int
+
+	// FillTarget is the fraction of the limit of the blob past which the
+	// blob is considered full: the writes are rejected without attempting the
+	// compression of the block. A value of 1 always attempts the compression.
+	FillTarget float64
+
+	// RecompressOnOverflow makes the blob maker recompress the whole payload
+	// in a single pass when a block does not fit. This saves the padding that
+	// the incremental compression inserts after every block, at the cost of a
+	// full compression per rejected block.
+	RecompressOnOverflow bool
+}
+
+var (
+	// ProfileFast stops compressing the blocks once the blob is nearly full
+	// and keeps the payload small so that the writes remain cheap under load.
+	ProfileFast = Profile{
+		Name:                 "fast",
+		MaxUncompressedBytes: 512 * 1024,
+		FillTarget:           0.98,
+	}
+
+	// ProfileBalanced is the default profile
+	ProfileBalanced = Profile{
+		Name:                 "balanced",
+		MaxUncompressedBytes: MaxUncompressedBytes,
+		FillTarget:           1,
+	}
+
+	// ProfileMax squeezes as many blocks as possible in the blobs
+	ProfileMax = Profile{
+		Name:                 "max",
+		MaxUncompressedBytes: MaxUncompressedBytes,
+		FillTarget:           1,
+		RecompressOnOverflow: true,
+	}
+)
+
+// ProfileByName returns the predefined profile with the given name
+func ProfileByName(name string) (Profile, error) {
+	for _, p := range []Profile{ProfileFast, ProfileBalanced, ProfileMax} {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("unknown compression profile %q, expected fast, balanced or max", name)
+}
+
+// Validate checks that the blobs made with the profile can be decompressed
+// by the decompression circuit.
+func (p *Profile) Validate() error {
+
+	if p.MaxUncompressedBytes <= 0 || p.MaxUncompressedBytes > MaxUncompressedBytes {
+		return fmt.Errorf(
+			"profile %v: the uncompressed payload is bounded by %v bytes, it must be positive and at most the %v bytes of the decompression circuit",
+			p.Name, p.MaxUncompressedBytes, MaxUncompressedBytes,
+		)
+	}
+
+	if p.FillTarget <= 0 || p.FillTarget > 1 {
+		return fmt.Errorf("profile %v: the fill target %v must be in (0, 1]", p.Name, p.FillTarget)
+	}
+
+	return nil
+}
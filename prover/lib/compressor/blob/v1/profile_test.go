@@ -0,0 +1,95 @@
+//go:build !fuzzlight
+
+package v1_test
+
+import (
+	"bytes"
+	"testing"
+
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileValidate(t *testing.T) {
+
+	for _, name := range []string{"fast", "balanced", "MAX"} {
+		p, err := v1.ProfileByName(name)
+		require.NoError(t, err)
+		assert.NoError(t, p.Validate())
+	}
+
+	_, err := v1.ProfileByName("ultra")
+	assert.Error(t, err)
+
+	tooLarge := v1.ProfileMax
+	tooLarge.MaxUncompressedBytes = v1.MaxUncompressedBytes + 1
+	assert.Error(t, tooLarge.Validate())
+
+	noTarget := v1.ProfileBalanced
+	noTarget.FillTarget = 0
+	assert.Error(t, noTarget.Validate())
+
+	bm, err := v1.NewBlobMaker(64*1024, testDictPath)
+	require.NoError(t, err)
+	assert.Equal(t, v1.ProfileBalanced, bm.Profile())
+	assert.Error(t, bm.SetProfile(tooLarge))
+	assert.Equal(t, v1.ProfileBalanced, bm.Profile())
+
+	_, err = v1.NewBlobMaker(v1.MaxUsableBytes+1, testDictPath)
+	assert.Error(t, err, "the decompression circuit cannot handle the blob")
+}
+
+func TestProfiles(t *testing.T) {
+
+	// fill fills a blob with the test blocks and returns the number of blocks
+	// appended
+	fill := func(p v1.Profile) int {
+
+		bm, err := v1.NewBlobMaker(4*1024, testDictPath)
+		require.NoError(t, err)
+		require.NoError(t, bm.SetProfile(p))
+
+		var appended [][]byte
+		for i, block := range testBlocks {
+
+			if i%3 == 0 {
+				bm.StartNewBatch()
+			}
+
+			// CanWrite must leave the blob untouched
+			before := bytes.Clone(bm.Bytes())
+			canWrite, err := bm.Write(block, true)
+			require.NoError(t, err)
+			require.Equal(t, before, bm.Bytes())
+
+			ok, err := bm.Write(block, false)
+			require.NoError(t, err)
+			require.Equal(t, canWrite, ok, "profile %v, block %v", p.Name, i)
+			if ok {
+				appended = append(appended, block)
+			}
+		}
+
+		batches, err := decompressBlob(bm.Bytes())
+		require.NoError(t, err)
+
+		var decompressed [][]byte
+		for _, batch := range batches {
+			decompressed = append(decompressed, batch...)
+		}
+		require.Equal(t, len(appended), len(decompressed), "profile %v", p.Name)
+
+		return len(appended)
+	}
+
+	var (
+		fast     = fill(v1.ProfileFast)
+		balanced = fill(v1.ProfileBalanced)
+		max      = fill(v1.ProfileMax)
+	)
+
+	assert.Positive(t, fast)
+	assert.LessOrEqual(t, fast, balanced)
+	assert.LessOrEqual(t, balanced, max)
+}
@@ -0,0 +1,419 @@
+// Package testvectors generates and checks the canonical test vectors of the
+// v1 blob compressor. The vectors are published as a JSON fixture, see
+// [FixturesPath], consumed by the tests of the compressor and of the
+// decompression circuit, as well as by the other implementations of the
+// compressor which must produce the same blobs byte for byte.
+//
+// The blocks of the vectors are synthetic and derived from fixed seeds, so
+// that running [Generate] again yields the same fixture. A change of the
+// fixture thus signals a change of the blob format or of the compressor.
+package testvectors
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// FixturesPath is the path of the fixture, relative to this package
+const FixturesPath = "testdata/vectors.json"
+
+// chainID is the chain ID of the transactions of the vectors, the one of the
+// Linea mainnet.
+const chainID = 59144
+
+// Fixtures is the content of the fixture
+type Fixtures struct {
+	// Version is the version of the blobs
+	Version uint16 `json:"version"`
+	// Dict is the dictionary of the compressor, before its augmentation
+	Dict hexutil.Bytes `json:"dict"`
+	// DictChecksum is the checksum of the dictionary, as found in the header
+	// of the blobs
+	DictChecksum hexutil.Bytes `json:"dictChecksum"`
+	Vectors      []Vector      `json:"vectors"`
+}
+
+// Vector is a test vector: a sequence of batches of blocks and the blob the
+// compressor produces out of them, with the default profile of the
+// compressor.
+type Vector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// DataLimit is the maximal size of the blob
+	DataLimit int `json:"dataLimit"`
+	// Batches are the RLP-encoded blocks, batch by batch
+	Batches [][]hexutil.Bytes `json:"batches"`
+	// Rejected is a block that the compressor must refuse to append after
+	// the batches as the blob is full, if any.
+	Rejected hexutil.Bytes `json:"rejected,omitempty"`
+	// Blob is the expected blob
+	Blob hexutil.Bytes `json:"blob"`
+	// Decompressed is the expected payload of the blob once decompressed,
+	// i.e. the blocks encoded for compression.
+	Decompressed hexutil.Bytes `json:"decompressed"`
+}
+
+// spec describes a vector, from which the blocks are generated
+type spec struct {
+	name, description string
+	dataLimit         int
+	// batches lists the number of transactions of each block, batch by
+	// batch
+	batches [][]int
+	// calldata generates the calldata of a transaction
+	calldata func(rng *rand.Rand) []byte
+	// fill appends blocks of one transaction until the blob is full, after
+	// the batches
+	fill bool
+}
+
+var specs = []spec{
+	{
+		name:        "single-block",
+		description: "a single block with a single transfer",
+		dataLimit:   v1.MaxUsableBytes,
+		batches:     [][]int{{1}},
+		calldata:    noCalldata,
+	},
+	{
+		name:        "two-batches",
+		description: "two batches of one block each",
+		dataLimit:   v1.MaxUsableBytes,
+		batches:     [][]int{{2}, {3}},
+		calldata:    tokenTransfer,
+	},
+	{
+		name:        "repetitive-calldata",
+		description: "token transfers, which compress well",
+		dataLimit:   v1.MaxUsableBytes,
+		batches:     [][]int{{4, 4, 4}},
+		calldata:    tokenTransfer,
+	},
+	{
+		name:        "random-calldata",
+		description: "random calldata, which does not compress",
+		dataLimit:   v1.MaxUsableBytes,
+		batches:     [][]int{{1, 1}},
+		calldata:    randomCalldata(2048),
+	},
+	{
+		name:        "multi-batch",
+		description: "several batches of several blocks",
+		dataLimit:   v1.MaxUsableBytes,
+		batches:     [][]int{{1, 2}, {3}, {2, 2, 1}},
+		calldata:    tokenTransfer,
+	},
+	{
+		name:        "full-blob",
+		description: "a small blob filled with blocks until the compressor rejects one",
+		dataLimit:   4096,
+		batches:     [][]int{{1}},
+		calldata:    randomCalldata(256),
+		fill:        true,
+	},
+}
+
+// Generate generates the vectors with the dictionary
+func Generate(dict []byte) (*Fixtures, error) {
+
+	bm, err := v1.NewBlobMakerFromDict(v1.MaxUsableBytes, dict)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Fixtures{
+		Dict:         dict,
+		DictChecksum: bytes.Clone(bm.Header.DictChecksum[:]),
+	}
+
+	for i, s := range specs {
+		v, err := s.generate(dict, int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("vector %v: %w", s.name, err)
+		}
+		f.Vectors = append(f.Vectors, *v)
+	}
+
+	f.Version = blob.GetVersion(f.Vectors[0].Blob)
+	return f, nil
+}
+
+// generate generates the blocks of the vector from the seed and compresses
+// them.
+func (s spec) generate(dict []byte, seed int64) (*Vector, error) {
+
+	var (
+		rng = rand.New(rand.NewSource(seed)) // #nosec G404 -- the vectors must be reproducible
+		gen = newBlockGenerator(rng, s.calldata)
+		v   = &Vector{Name: s.name, Description: s.description, DataLimit: s.dataLimit}
+	)
+
+	bm, err := v1.NewBlobMakerFromDict(s.dataLimit, dict)
+	if err != nil {
+		return nil, err
+	}
+
+	write := func(block []byte) (bool, error) {
+		ok, err := bm.Write(block, false)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			last := &v.Batches[len(v.Batches)-1]
+			*last = append(*last, block)
+		}
+		return ok, nil
+	}
+
+	for i, batch := range s.batches {
+		if i > 0 {
+			bm.StartNewBatch()
+		}
+		v.Batches = append(v.Batches, nil)
+		for _, nbTxs := range batch {
+			ok, err := write(gen.block(nbTxs))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, errors.New("the blob is full before the end of the batches")
+			}
+		}
+	}
+
+	for s.fill {
+		block := gen.block(1)
+		ok, err := write(block)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			v.Rejected = block
+			break
+		}
+	}
+
+	v.Blob = bytes.Clone(bm.Bytes())
+	if _, v.Decompressed, _, err = v1.DecompressBlob(v.Blob, dict); err != nil {
+		return nil, fmt.Errorf("could not decompress the blob: %w", err)
+	}
+
+	return v, nil
+}
+
+// Check replays the vector through the compressor and the decompressor and
+// returns an error if they do not produce the expected blob and payload.
+func (v *Vector) Check(dict []byte) error {
+
+	bm, err := v1.NewBlobMakerFromDict(v.DataLimit, dict)
+	if err != nil {
+		return err
+	}
+
+	for i, batch := range v.Batches {
+		if i > 0 {
+			bm.StartNewBatch()
+		}
+		for j, block := range batch {
+			ok, err := bm.Write(block, false)
+			if err != nil {
+				return fmt.Errorf("batch %v, block %v: %w", i, j, err)
+			}
+			if !ok {
+				return fmt.Errorf("batch %v, block %v: the block was rejected", i, j)
+			}
+		}
+	}
+
+	if len(v.Rejected) > 0 {
+		ok, err := bm.Write(v.Rejected, false)
+		if err != nil {
+			return fmt.Errorf("rejected block: %w", err)
+		}
+		if ok {
+			return errors.New("the block expected to be rejected was appended")
+		}
+	}
+
+	if !bytes.Equal(bm.Bytes(), v.Blob) {
+		return errors.New("the blob does not match")
+	}
+
+	_, decompressed, _, err := v1.DecompressBlob(v.Blob, dict)
+	if err != nil {
+		return fmt.Errorf("could not decompress the blob: %w", err)
+	}
+	if !bytes.Equal(decompressed, v.Decompressed) {
+		return errors.New("the decompressed payload does not match")
+	}
+
+	return nil
+}
+
+// Read reads the fixture
+func Read(path string) (*Fixtures, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixtures
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("could not parse the fixture %v: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Write writes the fixture as indented JSON
+func (f *Fixtures) Write(path string) error {
+	b, err := json.MarshalIndent(f, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// blockGenerator generates blocks of signed transactions from a rng
+type blockGenerator struct {
+	rng      *rand.Rand
+	calldata func(rng *rand.Rand) []byte
+	signer   types.Signer
+	keys     []*ecdsa.PrivateKey
+	nonces   []uint64
+	number   int64
+}
+
+func newBlockGenerator(rng *rand.Rand, calldata func(rng *rand.Rand) []byte) *blockGenerator {
+
+	g := &blockGenerator{
+		rng:      rng,
+		calldata: calldata,
+		signer:   types.NewLondonSigner(big.NewInt(chainID)),
+		number:   1_000_000,
+	}
+
+	// a few senders, so that the addresses repeat across the transactions
+	for i := 0; i < 4; i++ {
+		var seed [32]byte
+		rng.Read(seed[:])
+		seed[0] = 1 // ensures the key is non-zero and lower than the order
+		key, err := crypto.ToECDSA(seed[:])
+		if err != nil {
+			panic(err)
+		}
+		g.keys = append(g.keys, key)
+		g.nonces = append(g.nonces, 0)
+	}
+
+	return g
+}
+
+// block returns a RLP-encoded block with nbTxs transactions of various types
+func (g *blockGenerator) block(nbTxs int) []byte {
+
+	txs := make([]*types.Transaction, nbTxs)
+	for i := range txs {
+		txs[i] = g.tx(i)
+	}
+
+	g.number++
+	header := &types.Header{
+		ParentHash: g.hash(),
+		Number:     big.NewInt(g.number),
+		GasLimit:   61_000_000,
+		Time:       1_700_000_000 + uint64(g.number)*2,
+		BaseFee:    big.NewInt(7),
+		Coinbase:   g.address(),
+	}
+
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs})
+
+	var buf bytes.Buffer
+	if err := rlp.Encode(&buf, block); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// tx returns a signed transaction, its type depends on its position
+func (g *blockGenerator) tx(pos int) *types.Transaction {
+
+	var (
+		sender = g.rng.Intn(len(g.keys))
+		nonce  = g.nonces[sender]
+		to     = g.address()
+		value  = big.NewInt(g.rng.Int63n(1 << 50))
+		data   = g.calldata(g.rng)
+		gas    = 21_000 + 16*uint64(len(data))
+		inner  types.TxData
+	)
+
+	g.nonces[sender]++
+
+	switch pos % 3 {
+	case 0:
+		inner = &types.DynamicFeeTx{
+			ChainID: big.NewInt(chainID), Nonce: nonce, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(100),
+			Gas: gas, To: &to, Value: value, Data: data,
+		}
+	case 1:
+		inner = &types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(100), Gas: gas, To: &to, Value: value, Data: data}
+	case 2:
+		inner = &types.AccessListTx{
+			ChainID: big.NewInt(chainID), Nonce: nonce, GasPrice: big.NewInt(100), Gas: gas, To: &to, Value: value, Data: data,
+			AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{g.hash()}}},
+		}
+	}
+
+	tx, err := types.SignNewTx(g.keys[sender], g.signer, inner)
+	if err != nil {
+		panic(err)
+	}
+	return tx
+}
+
+func (g *blockGenerator) address() (a common.Address) {
+	g.rng.Read(a[:])
+	return a
+}
+
+func (g *blockGenerator) hash() (h common.Hash) {
+	g.rng.Read(h[:])
+	return h
+}
+
+func noCalldata(*rand.Rand) []byte {
+	return nil
+}
+
+// tokenTransfer returns the calldata of an ERC-20 transfer to a random
+// recipient
+func tokenTransfer(rng *rand.Rand) []byte {
+	data := make([]byte, 4+32+32)
+	copy(data, []byte{0xa9, 0x05, 0x9c, 0xbb})
+	rng.Read(data[4+12 : 4+32])
+	rng.Read(data[4+32+24:])
+	return data
+}
+
+// randomCalldata returns a generator of random calldata of size n
+func randomCalldata(n int) func(rng *rand.Rand) []byte {
+	return func(rng *rand.Rand) []byte {
+		data := make([]byte, n)
+		rng.Read(data)
+		return data
+	}
+}
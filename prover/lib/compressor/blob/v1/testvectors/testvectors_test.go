@@ -0,0 +1,42 @@
+package testvectors
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFixturesUpToDate ensures the generation is deterministic and that the
+// published fixture is the one generated with the dictionary of the repo.
+// When the blob format changes, the fixture must be regenerated.
+func TestFixturesUpToDate(t *testing.T) {
+
+	dict, err := os.ReadFile("../../../compressor_dict.bin")
+	require.NoError(t, err)
+
+	published, err := Read(FixturesPath)
+	require.NoError(t, err)
+
+	generated, err := Generate(dict)
+	require.NoError(t, err)
+
+	require.Equal(t, published, generated, "the fixture is outdated, regenerate it with cmd/dev-tools/testcase-gen/compressor-vectors")
+}
+
+func TestVectors(t *testing.T) {
+
+	f, err := Read(FixturesPath)
+	require.NoError(t, err)
+
+	for _, v := range f.Vectors {
+		assert.NoError(t, v.Check(f.Dict), v.Name)
+	}
+
+	// a vector that does not match is reported
+	v := f.Vectors[0]
+	v.Blob = append([]byte{}, v.Blob...)
+	v.Blob[len(v.Blob)-1] ^= 1
+	assert.Error(t, v.Check(f.Dict))
+}
@@ -0,0 +1,23 @@
+package v1_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/testvectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVectors checks the compressor against the canonical test vectors, that
+// the other implementations of the compressor are also tested against.
+func TestVectors(t *testing.T) {
+
+	f, err := testvectors.Read("testvectors/" + testvectors.FixturesPath)
+	require.NoError(t, err)
+
+	for _, v := range f.Vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			assert.NoError(t, v.Check(f.Dict))
+		})
+	}
+}
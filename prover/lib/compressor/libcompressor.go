@@ -38,6 +38,34 @@ func initGo(dataLimit int, dictPath string) bool {
 	return lastError == nil
 }
 
+// SetProfile selects the compression profile by name: "fast", "balanced" or
+// "max". The profile applies to the next writes and can be changed at any time,
+// e.g. to trade CPU for blob utilization under load. The compressor uses the
+// "balanced" profile after Init.
+// Returns true if the profile was selected, false otherwise.
+// If false is returned, the Error() method will return a string describing the error.
+//
+//export SetProfile
+func SetProfile(name *C.char) bool {
+	return setProfileGo(C.GoString(name))
+}
+
+func setProfileGo(name string) bool {
+	lock.Lock()
+	defer lock.Unlock()
+
+	profile, err := blob_v1.ProfileByName(name)
+	if err == nil {
+		err = compressor.SetProfile(profile)
+	}
+	if err != nil {
+		lastError = err
+		return false
+	}
+
+	return true
+}
+
 // Reset resets the compressor. Must be called between each Blob.
 //
 //export Reset
@@ -87,6 +115,29 @@ func CanWrite(input *C.char, inputLength C.int) (chunkAppended bool) {
 	return chunkAppended
 }
 
+// EstimateCanWrite estimates, without compressing the input, whether Write
+// would append it to the compressed data. It is much cheaper than CanWrite
+// and meant to pre-select the blocks.
+// Returns 0 if the chunk is guaranteed to fit, 1 if it most likely does not
+// fit, 2 if the estimation is not conclusive and CanWrite must be called, or
+// -1 if an error occurred.
+//
+// The input []byte is interpreted as a RLP encoded Block.
+//
+//export EstimateCanWrite
+func EstimateCanWrite(input *C.char, inputLength C.int) C.int {
+	lock.Lock()
+	defer lock.Unlock()
+	rlpBlock := unsafe.Slice((*byte)(unsafe.Pointer(input)), inputLength)
+	verdict, err := compressor.EstimateCanWrite(rlpBlock)
+	if err != nil {
+		lastError = err
+		return -1
+	}
+
+	return C.int(verdict)
+}
+
 // Error returns the last encountered error.
 // If no error was encountered, returns nil.
 //
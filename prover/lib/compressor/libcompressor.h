@@ -82,6 +82,15 @@ extern "C" {
 //
 extern GoUint8 Init(GoInt dataLimit, char* dictPath);
 
+// SetProfile selects the compression profile by name: "fast", "balanced" or
+// "max". The profile applies to the next writes and can be changed at any time,
+// e.g. to trade CPU for blob utilization under load. The compressor uses the
+// "balanced" profile after Init.
+// Returns true if the profile was selected, false otherwise.
+// If false is returned, the Error() method will return a string describing the error.
+//
+extern GoUint8 SetProfile(char* name);
+
 // Reset resets the compressor. Must be called between each Blob.
 //
 extern void Reset();
@@ -99,6 +108,17 @@ extern GoUint8 Write(char* input, int inputLength);
 //
 extern GoUint8 CanWrite(char* input, int inputLength);
 
+// EstimateCanWrite estimates, without compressing the input, whether Write
+// would append it to the compressed data. It is much cheaper than CanWrite
+// and meant to pre-select the blocks.
+// Returns 0 if the chunk is guaranteed to fit, 1 if it most likely does not
+// fit, 2 if the estimation is not conclusive and CanWrite must be called, or
+// -1 if an error occurred.
+//
+// The input []byte is interpreted as a RLP encoded Block.
+//
+extern int EstimateCanWrite(char* input, int inputLength);
+
 // Error returns the last encountered error.
 // If no error was encountered, returns nil.
 //
@@ -50,6 +50,21 @@ func InnerProduct(a, b SmartVector) field.Element {
 
 	var res field.Element
 
+	// Only the entries of the sparse vector contribute
+	if _, ok := b.(*Sparse); ok {
+		a, b = b, a
+	}
+
+	if s, ok := a.(*Sparse); ok {
+		for k, i := range s.indices {
+			var tmp field.Element
+			b_ := b.Get(i)
+			tmp.Mul(&s.values[k], &b_)
+			res.Add(&res, &tmp)
+		}
+		return res
+	}
+
 	for i := 0; i < a.Len(); i++ {
 		var tmp field.Element
 		a_, b_ := a.Get(i), b.Get(i)
@@ -92,7 +107,7 @@ func PolyEval(vecs []SmartVector, x field.Element, p ...mempool.MemPool) (result
 	xPow := field.One()
 
 	accumulateReg := func(acc, v []field.Element, x field.Element) {
-		for i := 0; i < length; i++ {
+		for i := range v {
 			tmpF.Mul(&v[i], &x)
 			acc[i].Add(&acc[i], &tmpF)
 		}
@@ -103,12 +118,7 @@ func PolyEval(vecs []SmartVector, x field.Element, p ...mempool.MemPool) (result
 	// The computation is done following horner's method.
 	for i := range vecs {
 
-		v := vecs[i]
-		if asRotated, ok := v.(*Rotated); ok {
-			v = rotatedAsRegular(asRotated)
-		}
-
-		switch casted := v.(type) {
+		switch casted := vecs[i].(type) {
 		case *Constant:
 			anyCon = true
 			tmpF.Mul(&casted.val, &xPow)
@@ -121,7 +131,18 @@ func PolyEval(vecs []SmartVector, x field.Element, p ...mempool.MemPool) (result
 			anyReg = true
 			v := casted.Regular
 			accumulateReg(resReg, v, xPow)
-		case *PaddedCircularWindow:
+		case *Rotated:
+			anyReg = true
+			head, tail := rotatedSegments(casted)
+			accumulateReg(resReg[:len(head)], head, xPow)
+			accumulateReg(resReg[len(head):], tail, xPow)
+		case *Sparse:
+			anyReg = true
+			for k, j := range casted.indices {
+				tmpF.Mul(&casted.values[k], &xPow)
+				resReg[j].Add(&resReg[j], &tmpF)
+			}
+		case *PaddedCircularWindow, *Lazy:
 			// treat it as a regular, reusing the buffer
 			anyReg = true
 			casted.WriteInSlice(tmpVec)
@@ -178,6 +199,14 @@ func BatchInvert(x SmartVector) SmartVector {
 		return NewRegular(field.BatchInvert(v.Regular))
 	case *Regular:
 		return NewRegular(field.BatchInvert(*v))
+	case *Sparse:
+		return &Sparse{
+			indices: v.indices,
+			values:  field.BatchInvert(v.values),
+			length:  v.length,
+		}
+	case *Lazy:
+		return BatchInvert(v.Materialize())
 	}
 
 	panic("unsupported type")
@@ -242,6 +271,20 @@ func IsZero(x SmartVector) SmartVector {
 			}
 		}
 		return NewRegular(res)
+
+	case *Sparse:
+		// The result is one almost everywhere
+		res := make([]field.Element, v.length)
+		vector.Fill(res, field.One())
+		for k, i := range v.indices {
+			if !v.values[k].IsZero() {
+				res[i] = field.Zero()
+			}
+		}
+		return NewRegular(res)
+
+	case *Lazy:
+		return IsZero(v.Materialize())
 	}
 
 	panic("unsupported type")
@@ -287,6 +330,16 @@ func Sum(a SmartVector) (res field.Element) {
 		}
 		return res
 
+	case *Sparse:
+		res := field.Zero()
+		for i := range v.values {
+			res.Add(&res, &v.values[i])
+		}
+		return res
+
+	case *Lazy:
+		return Sum(v.Materialize())
+
 	default:
 		utils.Panic("unsupported type: %T", v)
 	}
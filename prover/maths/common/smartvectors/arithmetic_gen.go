@@ -73,6 +73,15 @@ func processOperator(op operator, coeffs []int, svecs []SmartVector, p ...mempoo
 		return constRes
 	}
 
+	// The sparse smart-vectors are processed separately as their result is
+	// usually sparse as well.
+	if sparseRes, ok := processSparse(op, coeffs, svecs, p...); ok {
+		return sparseRes
+	}
+
+	// The lazy smart-vectors are needed in full from now on
+	svecs = materializeLazy(svecs)
+
 	// Accumulate the windowed smart-vectors
 	windowRes, matchedWindow := processWindowedOnly(op, svecs, coeffs)
 
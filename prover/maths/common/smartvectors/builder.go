@@ -0,0 +1,173 @@
+package smartvectors
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Builder assembles a smart-vector of a fixed length from ranges written by
+// several producers, typically the goroutines of a module assigner working
+// on disjoint parts of a column. The writes may happen concurrently as long
+// as they target disjoint ranges; overlapping writes are rejected. Once every
+// position has been written, [Builder.Build] returns the most compact
+// representation of the vector: a [Constant] if all the entries are equal, a
+// [PaddedCircularWindow] if the vector starts or ends with a run of equal
+// entries (e.g. zero-padding) and a [Regular] otherwise.
+//
+// The zero value is not usable, use [NewBuilder].
+type Builder struct {
+	buf []field.Element
+	// mu protects ranges, the ranges reserved by the writes. They are sorted
+	// and the adjacent ones are merged, so that the producers writing
+	// consecutive chunks keep the list short. The entries of buf are written
+	// outside of the lock since the ranges are disjoint.
+	mu     sync.Mutex
+	ranges []builderRange
+	// written counts the entries whose write is completed
+	written atomic.Int64
+}
+
+// NewBuilder returns a [Builder] for a vector of length n
+func NewBuilder(n int) *Builder {
+	assertStrictPositiveLen(n)
+	return &Builder{buf: make([]field.Element, n)}
+}
+
+// Len returns the length of the vector being built
+func (b *Builder) Len() int {
+	return len(b.buf)
+}
+
+// Write writes values at the positions [start, start+len(values)) of the
+// vector. It panics if the range is out of bounds or if it overlaps a range
+// that was already written. It is safe to call concurrently.
+func (b *Builder) Write(start int, values []field.Element) {
+	stop := start + len(values)
+	b.reserve(start, stop)
+	copy(b.buf[start:stop], values)
+	b.written.Add(int64(len(values)))
+}
+
+// WriteConstant writes val at the positions [start, stop) of the vector. It
+// follows the same rules as [Builder.Write].
+func (b *Builder) WriteConstant(start, stop int, val field.Element) {
+	b.reserve(start, stop)
+	for i := start; i < stop; i++ {
+		b.buf[i] = val
+	}
+	b.written.Add(int64(stop - start))
+}
+
+// IsComplete returns true if every position of the vector has been written
+func (b *Builder) IsComplete() bool {
+	return int(b.written.Load()) == len(b.buf)
+}
+
+// Build returns the vector in its most compact representation. It panics if
+// some positions have not been written, and the builder must not be used
+// afterwards.
+func (b *Builder) Build() SmartVector {
+
+	if !b.IsComplete() {
+		utils.Panic("the vector is incomplete: %v positions out of %v have been written", b.written.Load(), len(b.buf))
+	}
+
+	var (
+		n = len(b.buf)
+		// prefix and suffix are the lengths of the runs of entries equal to
+		// the first and the last entries.
+		prefix = runLength(b.buf, b.buf[0])
+		suffix = 0
+	)
+
+	if prefix == n {
+		return NewConstant(b.buf[0], n)
+	}
+
+	for suffix < n && b.buf[n-1-suffix] == b.buf[n-1] {
+		suffix++
+	}
+
+	// If the first and the last entries are equal, the padding wraps around
+	// the end of the vector and the window sits in the middle.
+	switch {
+	case b.buf[0] == b.buf[n-1]:
+		return newCompactWindow(b.buf[prefix:n-suffix], b.buf[0], prefix, n)
+	case prefix > suffix:
+		return newCompactWindow(b.buf[prefix:], b.buf[0], prefix, n)
+	case suffix > 1:
+		return newCompactWindow(b.buf[:n-suffix], b.buf[n-1], 0, n)
+	default:
+		return NewRegular(b.buf)
+	}
+}
+
+// builderRange is the range of positions [start, stop) of a [Builder]
+type builderRange struct {
+	start, stop int
+}
+
+// reserve records the range [start, stop) as written and panics if it is out
+// of bounds or if it overlaps a range that was already recorded. The range is
+// located by a binary search and merged with its neighbours if they are
+// adjacent.
+func (b *Builder) reserve(start, stop int) {
+
+	if start < 0 || stop > len(b.buf) || start > stop {
+		utils.Panic("the range [%v, %v) is out of bounds for a vector of length %v", start, stop, len(b.buf))
+	}
+
+	if start == stop {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// i is the first range ending after start, the only one that may
+	// overlap [start, stop) as the ranges are sorted and disjoint.
+	i := sort.Search(len(b.ranges), func(i int) bool { return b.ranges[i].stop > start })
+	if i < len(b.ranges) && b.ranges[i].start < stop {
+		r := b.ranges[i]
+		utils.Panic("the range [%v, %v) overlaps the range [%v, %v) that was already written", start, stop, r.start, r.stop)
+	}
+
+	var (
+		mergePrev = i > 0 && b.ranges[i-1].stop == start
+		mergeNext = i < len(b.ranges) && b.ranges[i].start == stop
+	)
+
+	switch {
+	case mergePrev && mergeNext:
+		b.ranges[i-1].stop = b.ranges[i].stop
+		b.ranges = slices.Delete(b.ranges, i, i+1)
+	case mergePrev:
+		b.ranges[i-1].stop = stop
+	case mergeNext:
+		b.ranges[i].start = start
+	default:
+		b.ranges = slices.Insert(b.ranges, i, builderRange{start: start, stop: stop})
+	}
+}
+
+// newCompactWindow returns a [PaddedCircularWindow] holding a copy of window,
+// so that the memory of the rest of the buffer can be released.
+func newCompactWindow(window []field.Element, paddingVal field.Element, offset, totLen int) SmartVector {
+	return NewPaddedCircularWindow(slices.Clone(window), paddingVal, offset, totLen)
+}
+
+// runLength returns the length of the run of entries equal to val at the
+// beginning of v.
+func runLength(v []field.Element, val field.Element) int {
+	for i := range v {
+		if v[i] != val {
+			return i
+		}
+	}
+	return len(v)
+}
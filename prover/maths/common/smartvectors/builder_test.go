@@ -0,0 +1,138 @@
+package smartvectors
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderCompactRepresentation(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		values   []int
+		expected SmartVector
+	}{
+		{
+			name:     "constant",
+			values:   []int{3, 3, 3, 3},
+			expected: NewConstant(field.NewElement(3), 4),
+		},
+		{
+			name:     "zero-padded",
+			values:   []int{1, 2, 0, 0, 0},
+			expected: NewPaddedCircularWindow(vector.ForTest(1, 2), field.Zero(), 0, 5),
+		},
+		{
+			name:     "left-padded",
+			values:   []int{7, 7, 7, 1, 2},
+			expected: NewPaddedCircularWindow(vector.ForTest(1, 2), field.NewElement(7), 3, 5),
+		},
+		{
+			name:     "padded-on-both-sides",
+			values:   []int{0, 1, 2, 0, 0},
+			expected: NewPaddedCircularWindow(vector.ForTest(1, 2), field.Zero(), 1, 5),
+		},
+		{
+			name:     "regular",
+			values:   []int{1, 2, 3, 4},
+			expected: ForTest(1, 2, 3, 4),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewBuilder(len(tc.values))
+			b.Write(0, vector.ForTest(tc.values...))
+			res := b.Build()
+			assert.IsType(t, tc.expected, res)
+			assert.Equal(t, vector.ForTest(tc.values...), IntoRegVec(res))
+		})
+	}
+}
+
+func TestBuilderConcurrentWrites(t *testing.T) {
+
+	const (
+		n         = 1 << 10
+		chunkSize = 1 << 6
+	)
+
+	var (
+		b        = NewBuilder(n)
+		expected = vector.Rand(n)
+		wg       sync.WaitGroup
+	)
+
+	// The second half is zero-padding written at once
+	b.WriteConstant(n/2, n, field.Zero())
+	for i := n / 2; i < n; i++ {
+		expected[i].SetZero()
+	}
+
+	for start := 0; start < n/2; start += chunkSize {
+		wg.Add(1)
+		go func(start int) {
+			defer wg.Done()
+			b.Write(start, expected[start:start+chunkSize])
+		}(start)
+	}
+	wg.Wait()
+
+	require.True(t, b.IsComplete())
+	res := b.Build()
+	assert.IsType(t, &PaddedCircularWindow{}, res)
+	assert.Equal(t, expected, IntoRegVec(res))
+}
+
+func TestBuilderMisuse(t *testing.T) {
+
+	b := NewBuilder(8)
+	b.Write(2, vector.ForTest(1, 2, 3))
+
+	assert.Panics(t, func() { b.Write(4, vector.ForTest(1, 2)) }, "overlapping write")
+	assert.Panics(t, func() { b.Write(7, vector.ForTest(1, 2)) }, "out of bounds write")
+	assert.Panics(t, func() { b.WriteConstant(-1, 1, field.Zero()) }, "out of bounds write")
+
+	require.False(t, b.IsComplete())
+	assert.Panics(t, func() { b.Build() }, "incomplete vector")
+
+	b.WriteConstant(0, 2, field.Zero())
+	b.WriteConstant(5, 8, field.Zero())
+	assert.Panics(t, func() { b.WriteConstant(1, 3, field.Zero()) }, "write overlapping merged ranges")
+	require.True(t, b.IsComplete())
+	assert.Equal(t, vector.ForTest(0, 0, 1, 2, 3, 0, 0, 0), IntoRegVec(b.Build()))
+}
+
+func TestBuilderRanges(t *testing.T) {
+
+	b := NewBuilder(16)
+
+	// Out of order writes, the adjacent ranges are merged
+	b.Write(4, vector.ForTest(4, 5))
+	b.Write(10, vector.ForTest(10, 11))
+	b.Write(8, vector.ForTest(8, 9))
+	b.Write(6, vector.ForTest(6, 7))
+	require.Equal(t, []builderRange{{start: 4, stop: 12}}, b.ranges)
+
+	b.Write(0, vector.ForTest(0))
+	b.WriteConstant(14, 16, field.NewElement(14))
+	require.Equal(t, []builderRange{{start: 0, stop: 1}, {start: 4, stop: 12}, {start: 14, stop: 16}}, b.ranges)
+
+	// Every gap is still writable, every reserved position is not
+	for i := 0; i < 16; i++ {
+		free := i >= 1 && i < 4 || i >= 12 && i < 14
+		if !free {
+			assert.Panics(t, func() { b.Write(i, vector.ForTest(0)) }, "position %v", i)
+		}
+	}
+
+	b.Write(1, vector.ForTest(1, 2, 3))
+	b.Write(12, vector.ForTest(12, 13))
+	require.Equal(t, []builderRange{{start: 0, stop: 16}}, b.ranges)
+	require.True(t, b.IsComplete())
+}
@@ -0,0 +1,219 @@
+package smartvectors
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// segment is a contiguous part of a smart-vector: either a slice of values or
+// n repetitions of the same value.
+type segment struct {
+	values []field.Element
+	val    field.Element
+	n      int
+}
+
+func (s segment) isConst() bool {
+	return s.values == nil
+}
+
+func (s segment) len() int {
+	if s.isConst() {
+		return s.n
+	}
+	return len(s.values)
+}
+
+// segmentsOf returns the segments whose concatenation is v, in order. The
+// values of v are not copied and the padding of the constant and windowed
+// vectors is not expanded. The vector types that have no dedicated case are
+// converted into a single slice.
+func segmentsOf(v SmartVector) []segment {
+	switch w := v.(type) {
+	case *Constant:
+		return []segment{{val: w.val, n: w.length}}
+	case *PaddedCircularWindow:
+		var (
+			res = make([]segment, 0, 4)
+			// The window starts at the offset and may wrap around the end of
+			// the vector, in which case its end is at position 0.
+			firstLen = min(len(w.window), w.totLen-w.offset)
+			wrapped  = w.window[firstLen:]
+		)
+		if len(wrapped) > 0 {
+			res = append(res, segment{values: wrapped})
+		}
+		if gap := w.offset - len(wrapped); gap > 0 {
+			res = append(res, segment{val: w.paddingVal, n: gap})
+		}
+		res = append(res, segment{values: w.window[:firstLen]})
+		if tail := w.totLen - w.offset - firstLen; tail > 0 {
+			res = append(res, segment{val: w.paddingVal, n: tail})
+		}
+		return res
+	case *Regular:
+		return []segment{{values: *w}}
+	case *Pooled:
+		return []segment{{values: w.Regular}}
+	case *Rotated:
+		head, tail := rotatedSegments(w)
+		if len(tail) == 0 {
+			return []segment{{values: head}}
+		}
+		return []segment{{values: head}, {values: tail}}
+	case *Sparse:
+		// The runs of consecutive entries are segments of values and the
+		// gaps between them are segments of zeroes.
+		var (
+			res  = make([]segment, 0, 2*len(w.indices)+1)
+			next int
+		)
+		for k := 0; k < len(w.indices); {
+			start := k
+			k++
+			for k < len(w.indices) && w.indices[k] == w.indices[k-1]+1 {
+				k++
+			}
+			if gap := w.indices[start] - next; gap > 0 {
+				res = append(res, segment{n: gap})
+			}
+			res = append(res, segment{values: w.values[start:k]})
+			next = w.indices[k-1] + 1
+		}
+		if gap := w.length - next; gap > 0 {
+			res = append(res, segment{n: gap})
+		}
+		return res
+	default:
+		return []segment{{values: v.IntoRegVecSaveAlloc()}}
+	}
+}
+
+// Equal returns true if a and b have the same length and the same values,
+// regardless of their representations. The padding of the constant and
+// windowed vectors is compared without being expanded: two constant vectors
+// are compared in constant time and two windowed vectors in time linear in
+// the size of their windows.
+func Equal(a, b SmartVector) bool {
+
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	var (
+		sa, sb     = segmentsOf(a), segmentsOf(b)
+		i, j       int
+		posA, posB int
+	)
+
+	// The segments of a and b do not have the same boundaries: the vectors
+	// are compared on the intersections of their segments.
+	for i < len(sa) && j < len(sb) {
+
+		n := min(sa[i].len()-posA, sb[j].len()-posB)
+		if !segmentsEqual(sa[i], posA, sb[j], posB, n) {
+			return false
+		}
+
+		if posA += n; posA == sa[i].len() {
+			i, posA = i+1, 0
+		}
+		if posB += n; posB == sb[j].len() {
+			j, posB = j+1, 0
+		}
+	}
+
+	return true
+}
+
+// segmentsEqual compares the n entries of a and b starting at positions ia
+// and ib respectively.
+func segmentsEqual(a segment, ia int, b segment, ib int, n int) bool {
+	switch {
+	case a.isConst() && b.isConst():
+		return a.val == b.val
+	case a.isConst():
+		return allEqualTo(b.values[ib:ib+n], a.val)
+	case b.isConst():
+		return allEqualTo(a.values[ia:ia+n], b.val)
+	default:
+		return vector.Equal(a.values[ia:ia+n], b.values[ib:ib+n])
+	}
+}
+
+func allEqualTo(v []field.Element, x field.Element) bool {
+	for i := range v {
+		if v[i] != x {
+			return false
+		}
+	}
+	return true
+}
+
+// Digest returns the SHA-256 digest of v. Equal vectors have the same digest
+// regardless of their representations, see [Equal], and the cost of the
+// function is linear in the [Density] of v rather than in its length. It is
+// meant to identify the content of the columns for caching and
+// deduplication.
+func Digest(v SmartVector) [sha256.Size]byte {
+	var res [sha256.Size]byte
+	copy(res[:], digestWith(sha256.New(), v))
+	return res
+}
+
+// DigestMiMC is as [Digest] but uses the MiMC hash function. The digest is
+// returned as a field element.
+func DigestMiMC(v SmartVector) field.Element {
+	var res field.Element
+	res.SetBytes(digestWith(mimc.NewMiMC(), v))
+	return res
+}
+
+// digestWith hashes the run-length encoding of v: the maximal runs of equal
+// values are written in order as pairs (length, value), each encoded as a
+// field element. The encoding does not depend on the representation of v.
+func digestWith(h hash.Hash, v SmartVector) []byte {
+
+	var (
+		curr  field.Element
+		count int
+	)
+
+	flush := func() {
+		var (
+			n  = field.NewElement(uint64(count)) // #nosec G115 -- the count is positive
+			nb = n.Bytes()
+			xb = curr.Bytes()
+		)
+		h.Write(nb[:])
+		h.Write(xb[:])
+	}
+
+	push := func(x field.Element, n int) {
+		if count > 0 && x == curr {
+			count += n
+			return
+		}
+		if count > 0 {
+			flush()
+		}
+		curr, count = x, n
+	}
+
+	for _, s := range segmentsOf(v) {
+		if s.isConst() {
+			push(s.val, s.n)
+			continue
+		}
+		for _, x := range s.values {
+			push(x, 1)
+		}
+	}
+	flush()
+
+	return h.Sum(nil)
+}
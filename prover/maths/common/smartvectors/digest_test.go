@@ -0,0 +1,74 @@
+package smartvectors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqualAndDigest(t *testing.T) {
+
+	var (
+		five = field.NewElement(5)
+		// all the vectors below represent [3, 5, 5, 5, 5, 5, 1, 2]
+		same = []SmartVector{
+			ForTest(3, 5, 5, 5, 5, 5, 1, 2),
+			NewPaddedCircularWindow(ForTest(1, 2, 3).IntoRegVecSaveAlloc(), five, 6, 8),
+			NewPaddedCircularWindow(ForTest(5, 1, 2, 3).IntoRegVecSaveAlloc(), five, 5, 8),
+			NewRotated(*NewRegular(ForTest(1, 2, 3, 5, 5, 5, 5, 5).IntoRegVecSaveAlloc()), 2),
+		}
+		// each of them differs from the above by a single entry or the length
+		different = []SmartVector{
+			ForTest(3, 5, 5, 5, 5, 5, 1, 1),
+			ForTest(3, 5, 5, 5, 5, 5, 1),
+			NewPaddedCircularWindow(ForTest(1, 2, 3).IntoRegVecSaveAlloc(), field.NewElement(4), 6, 8),
+			NewPaddedCircularWindow(ForTest(1, 2, 3).IntoRegVecSaveAlloc(), five, 5, 8),
+			NewConstant(five, 8),
+		}
+	)
+
+	for i := range same {
+		for j := range same {
+			assert.True(t, Equal(same[i], same[j]), "vectors %v and %v", i, j)
+		}
+		assert.Equal(t, Digest(same[0]), Digest(same[i]), "vector %v", i)
+		assert.Equal(t, DigestMiMC(same[0]), DigestMiMC(same[i]), "vector %v", i)
+
+		for j := range different {
+			assert.False(t, Equal(same[i], different[j]), "vectors %v and %v", i, j)
+			assert.False(t, Equal(different[j], same[i]), "vectors %v and %v", j, i)
+			assert.NotEqual(t, Digest(same[i]), Digest(different[j]), "vectors %v and %v", i, j)
+			assert.NotEqual(t, DigestMiMC(same[i]), DigestMiMC(different[j]), "vectors %v and %v", i, j)
+		}
+	}
+
+	// a constant vector equals its expansion
+	c := NewConstant(five, 8)
+	assert.True(t, Equal(c, NewRegular(c.IntoRegVecSaveAlloc())))
+	assert.Equal(t, Digest(c), Digest(NewRegular(c.IntoRegVecSaveAlloc())))
+	assert.NotEqual(t, Digest(c), Digest(NewConstant(five, 16)))
+}
+
+func TestFuzzEqualAndDigest(t *testing.T) {
+
+	for i := 0; i < fuzzIteration; i++ {
+
+		tcase := newTestBuilder(i).NewTestCaseForLinComb()
+
+		t.Run(fmt.Sprintf("fuzz-digest-%v", i), func(t *testing.T) {
+			for k, v := range tcase.svecs {
+				expanded := NewRegular(v.IntoRegVecSaveAlloc())
+				assert.True(t, Equal(v, expanded), "vector %v: %v", k, v.Pretty())
+				assert.Equal(t, Digest(expanded), Digest(v), "vector %v: %v", k, v.Pretty())
+
+				for l, w := range tcase.svecs {
+					expected := v.Len() == w.Len() && vector.Equal(v.IntoRegVecSaveAlloc(), w.IntoRegVecSaveAlloc())
+					assert.Equal(t, expected, Equal(v, w), "vectors %v and %v", k, l)
+				}
+			}
+		})
+	}
+}
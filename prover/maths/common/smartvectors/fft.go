@@ -53,15 +53,17 @@ func FFT(v SmartVector, decimation fft.Decimation, bitReverse bool, cosetRatio i
 			// In this case, the response is a constant vector
 			return NewConstant(x.window[0], x.Len())
 		}
+
+		// A zero-padded window is mostly made of zero coefficients, the FFT
+		// is pruned to the window.
+		naturalInput := decimation == fft.DIF || bitReverse
+		if x.paddingVal.IsZero() && naturalInput && utils.NextPowerOfTwo(len(x.window)) < x.Len() {
+			return fftPrunedWindow(x, decimation, bitReverse, cosetRatio, cosetID, pool)
+		}
 	}
 
 	// Else : we run the FFT directly
-	var res *Pooled
-	if pool != nil {
-		res = AllocFromPool(pool)
-	} else {
-		res = &Pooled{Regular: make([]field.Element, v.Len())}
-	}
+	res := allocFFTResult(v.Len(), pool)
 
 	v.WriteInSlice(res.Regular)
 
@@ -69,8 +71,13 @@ func FFT(v SmartVector, decimation fft.Decimation, bitReverse bool, cosetRatio i
 	oncoset := false
 
 	if cosetID != 0 || cosetRatio != 0 {
+		// Only the coset table used by the FFT is needed
 		oncoset = true
-		domain = domain.WithCustomCoset(cosetRatio, cosetID)
+		table := fft.TableCoset
+		if decimation == fft.DIT {
+			table = fft.TableCosetReversed
+		}
+		domain = domain.WithCustomCoset(cosetRatio, cosetID, table)
 	}
 
 	if decimation == fft.DIT {
@@ -140,21 +147,21 @@ func FFTInverse(v SmartVector, decimation fft.Decimation, bitReverse bool, coset
 	}
 
 	// Else : we run the FFTInverse directly
-	var res *Pooled
-	if pool != nil {
-		res = AllocFromPool(pool)
-	} else {
-		res = &Pooled{Regular: make([]field.Element, v.Len())}
-	}
+	res := allocFFTResult(v.Len(), pool)
 
 	oncoset := false
 	v.WriteInSlice(res.Regular)
 
 	domain := fft.NewDomain(v.Len())
 	if cosetID != 0 || cosetRatio != 0 {
-		// Optionally equip the domain with a coset
+		// Optionally equip the domain with a coset, only the coset table used
+		// by the FFTInverse is needed.
 		oncoset = true
-		domain = domain.WithCustomCoset(cosetRatio, cosetID)
+		table := fft.TableCosetInv
+		if decimation == fft.DIF {
+			table = fft.TableCosetInvReversed
+		}
+		domain = domain.WithCustomCoset(cosetRatio, cosetID, table)
 	}
 
 	if decimation == fft.DIF {
@@ -172,3 +179,44 @@ func FFTInverse(v SmartVector, decimation fft.Decimation, bitReverse bool, coset
 	}
 	return res
 }
+
+// fftPrunedWindow computes the FFT of a zero-padded window given in natural
+// order by transforming only the window, see [fft.PrunedFFT]. The parameters
+// are the ones of [FFT].
+func fftPrunedWindow(x *PaddedCircularWindow, decimation fft.Decimation, bitReverse bool, cosetRatio int, cosetID int, pool mempool.MemPool) SmartVector {
+
+	var (
+		n      = x.Len()
+		window = x.window
+		res    = allocFFTResult(n, pool)
+	)
+
+	if cosetID != 0 || cosetRatio != 0 {
+		// The coset FFT scales the coefficient i by the entry i of the coset
+		// table, only the ones of the window are non-zero.
+		cosetTable := fft.GetCosetTable(n, cosetRatio, cosetID, fft.TableCoset)
+		window = make([]field.Element, len(x.window))
+		for j := range window {
+			window[j].Mul(&x.window[j], &cosetTable[(x.offset+j)%n])
+		}
+	}
+
+	fft.PrunedFFT(window, x.offset, res.Regular)
+
+	// The output is in natural order, DIF without bitReverse expects it in
+	// bit-reversed order.
+	if decimation == fft.DIF && !bitReverse {
+		fft.BitReverse(res.Regular)
+	}
+
+	return res
+}
+
+// allocFFTResult allocates the result of an FFT of size n, from the pool if
+// one is provided.
+func allocFFTResult(n int, pool mempool.MemPool) *Pooled {
+	if pool != nil {
+		return AllocFromPool(pool)
+	}
+	return &Pooled{Regular: make([]field.Element, n)}
+}
@@ -290,3 +290,38 @@ func TestFFTBackAndForth(t *testing.T) {
 	require.Equal(t, v.Pretty(), vreeval1.Pretty())
 
 }
+
+func TestFFTPrunedWindow(t *testing.T) {
+
+	const n = 1 << 6
+
+	windowSizes := []int{1, 3, 8, 13}
+	offsets := []int{0, 5, n - 2}
+	cosets := [][2]int{{0, 0}, {2, 1}, {4, 3}}
+
+	for _, size := range windowSizes {
+		for _, offset := range offsets {
+			for _, coset := range cosets {
+				for _, decimation := range []fft.Decimation{fft.DIT, fft.DIF} {
+					for _, bitReverse := range []bool{false, true} {
+
+						name := fmt.Sprintf("size=%v-offset=%v-coset=%v-dec=%v-br=%v", size, offset, coset, decimation, bitReverse)
+						t.Run(name, func(t *testing.T) {
+
+							window := make([]field.Element, size)
+							for i := range window {
+								window[i].SetRandom()
+							}
+
+							v := NewPaddedCircularWindow(window, field.Zero(), offset, n)
+							actual := FFT(v, decimation, bitReverse, coset[0], coset[1], nil)
+							expected := FFT(NewRegular(v.IntoRegVecSaveAlloc()), decimation, bitReverse, coset[0], coset[1], nil)
+
+							require.Equal(t, expected.IntoRegVecSaveAlloc(), actual.IntoRegVecSaveAlloc())
+						})
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package smartvectors
+
+import (
+	"math/big"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/poly"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+)
+
+// EvalCoeff evaluates a polynomial in coefficient basis using Horner's
+// method. The constant and the windowed vectors are evaluated without
+// expanding the padding: the padding only costs a geometric sum.
+func EvalCoeff(v SmartVector, x field.Element) field.Element {
+	switch w := v.(type) {
+	case *Constant:
+		res := geometricSum(x, w.length)
+		return *res.Mul(&res, &w.val)
+	case *PaddedCircularWindow:
+		return evalCoeffWindowed(w, x)
+	case *Sparse:
+		return evalCoeffSparse(w, x)
+	}
+	return poly.EvalUnivariate(v.IntoRegVecSaveAlloc(), x)
+}
+
+// BatchEvalCoeff evaluates several polynomials in coefficient basis on the
+// same point. The polynomials are evaluated in parallel.
+func BatchEvalCoeff(vs []SmartVector, x field.Element) []field.Element {
+	res := make([]field.Element, len(vs))
+	parallel.Execute(len(vs), func(start, stop int) {
+		for i := start; i < stop; i++ {
+			res[i] = EvalCoeff(vs[i], x)
+		}
+	})
+	return res
+}
+
+// EvalCoeffMultiPoint evaluates a polynomial in coefficient basis on several
+// points. The points are processed in parallel and the polynomial is expanded
+// at most once.
+func EvalCoeffMultiPoint(v SmartVector, xs []field.Element) []field.Element {
+
+	switch v.(type) {
+	case *Constant, *PaddedCircularWindow, *Regular, *Sparse:
+	default:
+		v = NewRegular(v.IntoRegVecSaveAlloc())
+	}
+
+	res := make([]field.Element, len(xs))
+	parallel.Execute(len(xs), func(start, stop int) {
+		for i := start; i < stop; i++ {
+			res[i] = EvalCoeff(v, xs[i])
+		}
+	})
+	return res
+}
+
+// Horner returns the intermediate values of the evaluation of v in x using
+// Horner's method, in other words the vector h such that
+//
+//	h[i] = v[i] + x * h[i+1] and h[n-1] = v[n-1]
+//
+// In particular, h[0] is the evaluation of v in x.
+func Horner(v SmartVector, x field.Element) []field.Element {
+
+	var (
+		n = v.Len()
+		h = make([]field.Element, n)
+	)
+
+	if c, isConst := v.(*Constant); isConst {
+		h[n-1] = c.val
+		for i := n - 2; i >= 0; i-- {
+			h[i].Mul(&h[i+1], &x).Add(&h[i], &c.val)
+		}
+		return h
+	}
+
+	p := v.IntoRegVecSaveAlloc()
+	h[n-1] = p[n-1]
+	for i := n - 2; i >= 0; i-- {
+		h[i].Mul(&h[i+1], &x).Add(&h[i], &p[i])
+	}
+	return h
+}
+
+// evalCoeffWindowed evaluates a windowed vector in coefficient basis. The
+// vector is decomposed as a constant vector equal to the padding plus the
+// difference between the window and the padding. The window may wrap around
+// the end of the vector, in which case it is processed in two chunks.
+func evalCoeffWindowed(w *PaddedCircularWindow, x field.Element) field.Element {
+
+	res := geometricSum(x, w.totLen)
+	res.Mul(&res, &w.paddingVal)
+
+	var (
+		firstLen = min(len(w.window), w.totLen-w.offset)
+		chunk    = hornerShifted(w.window[:firstLen], w.paddingVal, x)
+		xPow     field.Element
+	)
+
+	xPow.Exp(x, big.NewInt(int64(w.offset)))
+	chunk.Mul(&chunk, &xPow)
+	res.Add(&res, &chunk)
+
+	if firstLen < len(w.window) {
+		// The rest of the window starts at position 0
+		chunk = hornerShifted(w.window[firstLen:], w.paddingVal, x)
+		res.Add(&res, &chunk)
+	}
+
+	return res
+}
+
+// evalCoeffSparse evaluates a sparse vector in coefficient basis. Only the
+// monomials of the stored entries are computed.
+func evalCoeffSparse(s *Sparse, x field.Element) field.Element {
+
+	var (
+		res, xPow, tmp field.Element
+		prev           int
+	)
+
+	xPow.SetOne()
+	for k, i := range s.indices {
+		tmp.Exp(x, big.NewInt(int64(i-prev)))
+		xPow.Mul(&xPow, &tmp)
+		tmp.Mul(&xPow, &s.values[k])
+		res.Add(&res, &tmp)
+		prev = i
+	}
+
+	return res
+}
+
+// hornerShifted evaluates the polynomial whose coefficients are the ones of p
+// minus the shift.
+func hornerShifted(p []field.Element, shift, x field.Element) field.Element {
+	var res, pi field.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		pi.Sub(&p[i], &shift)
+		res.Mul(&res, &x).Add(&res, &pi)
+	}
+	return res
+}
+
+// geometricSum returns 1 + x + ... + x^(n-1)
+func geometricSum(x field.Element, n int) field.Element {
+
+	if x.IsOne() {
+		return field.NewElement(uint64(n))
+	}
+
+	var (
+		res, den field.Element
+		one      = field.One()
+	)
+
+	res.Exp(x, big.NewInt(int64(n)))
+	res.Sub(&res, &one)
+	den.Sub(&x, &one)
+	den.Inverse(&den)
+	return *res.Mul(&res, &den)
+}
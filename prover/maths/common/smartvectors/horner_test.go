@@ -0,0 +1,78 @@
+package smartvectors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/poly"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+)
+
+// evalCoeffNaive evaluates v in coefficient basis after expanding it
+func evalCoeffNaive(v SmartVector, x field.Element) field.Element {
+	res := make([]field.Element, v.Len())
+	v.WriteInSlice(res)
+	return poly.EvalUnivariate(res, x)
+}
+
+func TestEvalCoeff(t *testing.T) {
+
+	var (
+		x   = field.NewElement(7)
+		one = field.One()
+		svs = []SmartVector{
+			NewConstant(field.NewElement(3), 8),
+			ForTest(1, 2, 3, 4),
+			NewRotated(*NewRegular([]field.Element{field.NewElement(1), field.NewElement(2), field.NewElement(3), field.NewElement(4)}), 1),
+			NewPaddedCircularWindow(ForTest(1, 2, 3).IntoRegVecSaveAlloc(), field.NewElement(5), 2, 8),
+			// The window wraps around the end of the vector
+			NewPaddedCircularWindow(ForTest(1, 2, 3).IntoRegVecSaveAlloc(), field.NewElement(5), 6, 8),
+		}
+	)
+
+	for i, v := range svs {
+		for _, x := range []field.Element{x, one, field.Zero()} {
+			expected := evalCoeffNaive(v, x)
+			actual := EvalCoeff(v, x)
+			assert.Equal(t, expected.String(), actual.String(), "vector %v, x=%v", i, x.String())
+
+			h := Horner(v, x)
+			assert.Equal(t, expected.String(), h[0].String(), "vector %v, x=%v", i, x.String())
+		}
+	}
+
+	// The batch variants agree with the evaluations one by one
+	xs := []field.Element{x, one, field.NewElement(42)}
+	batch := BatchEvalCoeff(svs, x)
+	for i, v := range svs {
+		expected := evalCoeffNaive(v, x)
+		assert.Equal(t, expected.String(), batch[i].String(), "vector %v", i)
+
+		multi := EvalCoeffMultiPoint(v, xs)
+		for k := range xs {
+			expected := evalCoeffNaive(v, xs[k])
+			assert.Equal(t, expected.String(), multi[k].String(), "vector %v, point %v", i, k)
+		}
+	}
+}
+
+func TestFuzzEvalCoeff(t *testing.T) {
+
+	for i := 0; i < fuzzIteration; i++ {
+
+		tcase := newTestBuilder(i).NewTestCaseForLinComb()
+
+		t.Run(fmt.Sprintf("fuzz-horner-%v", i), func(t *testing.T) {
+
+			var x field.Element
+			x.SetRandom()
+
+			for k, v := range tcase.svecs {
+				expected := evalCoeffNaive(v, x)
+				actual := EvalCoeff(v, x)
+				assert.Equal(t, expected.String(), actual.String(), "vector %v: %v", k, v.Pretty())
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package smartvectors
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+)
+
+// Lazy is a smart-vector whose entries are computed on demand by a function
+// of their position. It lets the modules define derived columns without
+// allocating them until they are needed in full: the entries accessed with
+// [Lazy.Get] are computed and memoized one by one and the vector is
+// materialized, i.e. all its entries are computed and stored, the first time
+// it is needed in full, e.g. by [Lazy.IntoRegVecSaveAlloc] or by the
+// arithmetic operations. [Lazy.WriteInSlice], and thus the FFT, does not
+// materialize the vector.
+//
+// The function may be called concurrently and must be deterministic. The
+// vector is safe for concurrent use. The zero value is not usable, use
+// [NewLazy].
+type Lazy struct {
+	f      func(int) field.Element
+	length int
+	// mu protects memo, the entries computed before the materialization
+	mu   sync.Mutex
+	memo map[int]field.Element
+	// values holds the entries once the vector is materialized, which is
+	// signaled by materialized.
+	once         sync.Once
+	values       []field.Element
+	materialized atomic.Bool
+}
+
+// NewLazy returns a [Lazy] vector of length n whose entry at position i is
+// f(i).
+func NewLazy(f func(int) field.Element, n int) *Lazy {
+	assertStrictPositiveLen(n)
+	return &Lazy{f: f, length: n, memo: map[int]field.Element{}}
+}
+
+// Len returns the length of the vector
+func (l *Lazy) Len() int { return l.length }
+
+// Get returns the entry at position n. It is computed on the first access and
+// memoized.
+func (l *Lazy) Get(n int) field.Element {
+
+	assertCorrectBound(n, l.length)
+
+	if l.materialized.Load() {
+		return l.values[n]
+	}
+
+	l.mu.Lock()
+	x, found := l.memo[n]
+	l.mu.Unlock()
+
+	if found {
+		return x
+	}
+
+	// The function is evaluated outside of the lock as it may be costly. Two
+	// concurrent accesses may thus evaluate it twice, with the same result.
+	x = l.f(n)
+
+	l.mu.Lock()
+	if l.memo != nil {
+		l.memo[n] = x
+	}
+	l.mu.Unlock()
+
+	return x
+}
+
+// numStored returns the number of entries stored by the vector
+func (l *Lazy) numStored() int {
+	if l.materialized.Load() {
+		return l.length
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.memo)
+}
+
+// IsMaterialized returns true if all the entries of the vector are computed
+// and stored.
+func (l *Lazy) IsMaterialized() bool {
+	return l.materialized.Load()
+}
+
+// Materialize computes and stores all the entries of the vector, in parallel,
+// and returns them as a [Regular]. The computation is done only once; the
+// result must not be mutated.
+func (l *Lazy) Materialize() *Regular {
+
+	l.once.Do(func() {
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		values := make([]field.Element, l.length)
+		parallel.Execute(l.length, func(start, stop int) {
+			for i := start; i < stop; i++ {
+				if x, found := l.memo[i]; found {
+					values[i] = x
+					continue
+				}
+				values[i] = l.f(i)
+			}
+		})
+
+		l.values = values
+		l.memo = nil
+		l.materialized.Store(true)
+	})
+
+	return NewRegular(l.values)
+}
+
+// SubVector returns a subvector of the vector. It mirrors slice[start:stop].
+// The result is a [Lazy] vector sharing the memoized entries of l.
+func (l *Lazy) SubVector(start, stop int) SmartVector {
+	if start > stop {
+		utils.Panic("negative length are not allowed")
+	}
+	if start == stop {
+		utils.Panic("zero length are not allowed")
+	}
+	assertCorrectBound(start, l.length)
+	// The +1 is because we accept if "stop = length"
+	assertCorrectBound(stop, l.length+1)
+
+	if l.materialized.Load() {
+		res := Regular(l.values[start:stop])
+		return &res
+	}
+
+	return NewLazy(func(i int) field.Element { return l.Get(start + i) }, stop-start)
+}
+
+// RotateRight returns a cyclically rotated version of the vector. The result
+// is a [Lazy] vector sharing the memoized entries of l.
+func (l *Lazy) RotateRight(offset int) SmartVector {
+	n := l.length
+	return NewLazy(func(i int) field.Element { return l.Get(utils.PositiveMod(i-offset, n)) }, n)
+}
+
+// WriteInSlice writes the vector in a slice which must have the same length.
+// If the vector is not materialized, the entries that are not memoized are
+// computed in parallel directly into the slice and are not memoized.
+func (l *Lazy) WriteInSlice(buff []field.Element) {
+
+	assertHasLength(len(buff), l.length)
+
+	if l.materialized.Load() {
+		copy(buff, l.values)
+		return
+	}
+
+	l.mu.Lock()
+	memo := make(map[int]field.Element, len(l.memo))
+	for i, x := range l.memo {
+		memo[i] = x
+	}
+	l.mu.Unlock()
+
+	parallel.Execute(l.length, func(start, stop int) {
+		for i := start; i < stop; i++ {
+			if x, found := memo[i]; found {
+				buff[i] = x
+				continue
+			}
+			buff[i] = l.f(i)
+		}
+	})
+}
+
+func (l *Lazy) Pretty() string {
+	if l.materialized.Load() {
+		return fmt.Sprintf("Lazy[%v]", vector.Prettify(l.values))
+	}
+	return fmt.Sprintf("Lazy[length=%v, not materialized]", l.length)
+}
+
+// DeepCopy returns a copy of the vector. If l is not materialized, the copy
+// is a [Lazy] vector computing its entries with the same function.
+func (l *Lazy) DeepCopy() SmartVector {
+	if l.materialized.Load() {
+		return NewRegular(vector.DeepCopy(l.values))
+	}
+	return NewLazy(l.f, l.length)
+}
+
+// IntoRegVecSaveAlloc materializes the vector and returns its entries. The
+// result must not be mutated.
+func (l *Lazy) IntoRegVecSaveAlloc() []field.Element {
+	return l.Materialize().IntoRegVecSaveAlloc()
+}
+
+// materializeLazy returns svecs where the [Lazy] vectors are replaced by
+// their materialization. svecs is not modified.
+func materializeLazy(svecs []SmartVector) []SmartVector {
+
+	var res []SmartVector
+	for i := range svecs {
+		l, ok := svecs[i].(*Lazy)
+		if !ok {
+			continue
+		}
+		if res == nil {
+			res = append([]SmartVector{}, svecs...)
+		}
+		res[i] = l.Materialize()
+	}
+
+	if res == nil {
+		return svecs
+	}
+	return res
+}
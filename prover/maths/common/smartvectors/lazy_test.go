@@ -0,0 +1,145 @@
+package smartvectors
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/fft"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy(t *testing.T) {
+
+	const n = 16
+
+	var (
+		calls atomic.Int64
+		f     = func(i int) field.Element {
+			calls.Add(1)
+			return field.NewElement(uint64(i*i + 1))
+		}
+		expected = make([]field.Element, n)
+	)
+
+	for i := range expected {
+		expected[i] = field.NewElement(uint64(i*i + 1))
+	}
+
+	l := NewLazy(f, n)
+	assert.Equal(t, n, l.Len())
+	assert.Equal(t, 0, Density(l))
+
+	// the entries are memoized
+	assert.Equal(t, expected[3], l.Get(3))
+	assert.Equal(t, expected[3], l.Get(3))
+	assert.Equal(t, int64(1), calls.Load())
+	assert.Equal(t, 1, Density(l))
+
+	// the subvectors and the rotations share the memoized entries
+	assert.Equal(t, expected[2:7], l.SubVector(2, 7).IntoRegVecSaveAlloc())
+	assert.Equal(t, int64(5), calls.Load())
+	for _, offset := range []int{0, 1, 5, -3} {
+		assert.Equal(t,
+			NewRegular(expected).RotateRight(offset).IntoRegVecSaveAlloc(),
+			l.RotateRight(offset).IntoRegVecSaveAlloc(),
+			"offset %v", offset,
+		)
+	}
+	assert.Equal(t, int64(n), calls.Load())
+
+	// writing the vector in a slice does not materialize it
+	buf := make([]field.Element, n)
+	l.WriteInSlice(buf)
+	assert.Equal(t, expected, buf)
+	assert.False(t, l.IsMaterialized())
+	assert.Equal(t, int64(n), calls.Load(), "all the entries are memoized")
+
+	assert.Equal(t, expected, l.Materialize().IntoRegVecSaveAlloc())
+	assert.True(t, l.IsMaterialized())
+	assert.Equal(t, n, Density(l))
+	assert.Equal(t, int64(n), calls.Load())
+
+	require.Panics(t, func() { NewLazy(f, 0) })
+	require.Panics(t, func() { l.Get(n) })
+}
+
+func TestLazyConcurrent(t *testing.T) {
+
+	const n = 1 << 10
+
+	var (
+		l  = NewLazy(func(i int) field.Element { return field.NewElement(uint64(i)) }, n)
+		wg sync.WaitGroup
+	)
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < n; i += 7 {
+				assert.Equal(t, field.NewElement(uint64(i)), l.Get(i))
+			}
+			if g == 0 {
+				l.Materialize()
+			}
+		}(g)
+	}
+
+	wg.Wait()
+	assert.Equal(t, vector.ForTest(0, 1, 2, 3), l.IntoRegVecSaveAlloc()[:4])
+}
+
+func TestLazyArithmetic(t *testing.T) {
+
+	const n = 32
+
+	var (
+		x        = field.NewElement(3)
+		dense    = vector.Rand(n)
+		newLazy  = func() *Lazy { return NewLazy(func(i int) field.Element { return dense[i] }, n) }
+		expected = NewRegular(dense)
+		other    = NewPaddedCircularWindow(vector.Rand(5), field.NewElement(2), 7, n)
+	)
+
+	assert.Equal(t,
+		LinComb([]int{2, -1}, []SmartVector{expected, other}).IntoRegVecSaveAlloc(),
+		LinComb([]int{2, -1}, []SmartVector{newLazy(), other}).IntoRegVecSaveAlloc(),
+	)
+
+	assert.Equal(t,
+		Product([]int{3, 1}, []SmartVector{expected, other}).IntoRegVecSaveAlloc(),
+		Product([]int{3, 1}, []SmartVector{newLazy(), other}).IntoRegVecSaveAlloc(),
+	)
+
+	assert.Equal(t,
+		PolyEval([]SmartVector{expected, other, expected}, x).IntoRegVecSaveAlloc(),
+		PolyEval([]SmartVector{newLazy(), other, newLazy()}, x).IntoRegVecSaveAlloc(),
+	)
+
+	assert.Equal(t,
+		FFT(expected, fft.DIF, true, 0, 0, nil).IntoRegVecSaveAlloc(),
+		FFT(newLazy(), fft.DIF, true, 0, 0, nil).IntoRegVecSaveAlloc(),
+	)
+
+	assert.Equal(t, Sum(expected), Sum(newLazy()))
+	assert.Equal(t, EvalCoeff(expected, x), EvalCoeff(newLazy(), x))
+	assert.Equal(t, BatchInvert(expected).IntoRegVecSaveAlloc(), BatchInvert(newLazy()).IntoRegVecSaveAlloc())
+	assert.True(t, Equal(expected, newLazy()))
+	assert.Equal(t, Digest(expected), Digest(newLazy()))
+
+	// the operands of the product of a sparse vector are only evaluated on its
+	// support
+	var calls atomic.Int64
+	counted := NewLazy(func(i int) field.Element { calls.Add(1); return dense[i] }, n)
+	sparse := NewSparse([]int{4, 9}, vector.ForTest(1, 2), n)
+	assert.Equal(t,
+		Mul(sparse, expected).IntoRegVecSaveAlloc(),
+		Mul(sparse, counted).IntoRegVecSaveAlloc(),
+	)
+	assert.Equal(t, int64(2), calls.Load())
+	assert.False(t, counted.IsMaterialized())
+}
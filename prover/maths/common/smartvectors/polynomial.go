@@ -238,14 +238,6 @@ func batchInterpolateSV(results []field.Element, computed []bool, polys [][]fiel
 	return results
 }
 
-// Evaluate a polynomial in coefficient basis
-func EvalCoeff(v SmartVector, x field.Element) field.Element {
-	// Maybe there is an optim for windowed here
-	res := make([]field.Element, v.Len())
-	v.WriteInSlice(res)
-	return poly.EvalUnivariate(res, x)
-}
-
 func EvalCoeffBivariate(v SmartVector, x field.Element, numCoeffX int, y field.Element) field.Element {
 
 	if v.Len()%numCoeffX != 0 {
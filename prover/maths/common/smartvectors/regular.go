@@ -88,38 +88,55 @@ func processRegularOnly(op operator, svecs []SmartVector, coeffs []int, p ...mem
 	isFirst := true
 	numMatches = 0
 
+	// accumulate applies the operator on a segment of the result starting at
+	// position `start`. For the first operand, we can save by just copying
+	// the result. Importantly, we do not need to assume that regRes is
+	// originally zero.
+	accumulate := func(start int, x []field.Element, coeff int, first bool) {
+		if len(x) == 0 {
+			return
+		}
+		if first {
+			op.vecIntoTerm(resvec.Regular[start:start+len(x)], x, coeff)
+			return
+		}
+		op.vecIntoVec(resvec.Regular[start:start+len(x)], x, coeff)
+	}
+
 	for i := range svecs {
 
 		svec := svecs[i]
-		// In case the current vec is Rotated, we reduce it to a regular form
-		// NB : this could use the pool.
-		if rot, ok := svec.(*Rotated); ok {
-			svec = rotatedAsRegular(rot)
-		}
 
 		if pooled, ok := svec.(*Pooled); ok {
 			svec = &pooled.Regular
 		}
 
-		if reg, ok := svec.(*Regular); ok {
-			numMatches++
-			// For the first one, we can save by just copying the result
-			// Importantly, we do not need to assume that regRes is originally
-			// zero.
-			if isFirst {
-				if hasPool {
-					resvec = AllocFromPool(pool)
-				} else {
-					resvec = &Pooled{Regular: make([]field.Element, length)}
-				}
-
-				isFirst = false
-				op.vecIntoTerm(resvec.Regular, *reg, coeffs[i])
-				continue
-			}
+		var head, tail []field.Element
+
+		switch v := svec.(type) {
+		case *Regular:
+			head = *v
+		case *Rotated:
+			// The rotated vector is processed as two segments of the
+			// underlying vector instead of being copied in a regular.
+			head, tail = rotatedSegments(v)
+		default:
+			continue
+		}
+
+		numMatches++
 
-			op.vecIntoVec(resvec.Regular, *reg, coeffs[i])
+		if isFirst {
+			if hasPool {
+				resvec = AllocFromPool(pool)
+			} else {
+				resvec = &Pooled{Regular: make([]field.Element, length)}
+			}
 		}
+
+		accumulate(0, head, coeffs[i], isFirst)
+		accumulate(len(head), tail, coeffs[i], isFirst)
+		isFirst = false
 	}
 
 	if numMatches == 0 {
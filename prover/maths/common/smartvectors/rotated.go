@@ -126,8 +126,10 @@ func (r *Rotated) DeepCopy() SmartVector {
 }
 
 func (r *Rotated) WriteInSlice(s []field.Element) {
-	res := rotatedAsRegular(r)
-	res.WriteInSlice(s)
+	assertHasLength(len(s), r.Len())
+	head, tail := rotatedSegments(r)
+	copy(s, head)
+	copy(s[len(head):], tail)
 }
 
 func (r *Rotated) Pretty() string {
@@ -140,6 +142,14 @@ func rotatedAsRegular(r *Rotated) *Regular {
 	return r.SubVector(0, r.Len()).(*Regular)
 }
 
+// rotatedSegments returns the two segments of the underlying vector whose
+// concatenation is the rotated vector. The function does not allocate and the
+// tail is empty when the rotation is trivial.
+func rotatedSegments(r *Rotated) (head, tail []field.Element) {
+	offset := utils.PositiveMod(r.offset, r.Len())
+	return r.v.Regular[offset:], r.v.Regular[:offset]
+}
+
 func (r *Rotated) IntoRegVecSaveAlloc() []field.Element {
 	return *rotatedAsRegular(r)
 }
@@ -169,6 +179,11 @@ func SoftRotate(v SmartVector, offset int) SmartVector {
 			v:      casted,
 			offset: offset,
 		}
+	case *Sparse:
+		// The rotation of a sparse vector only moves its few entries
+		return casted.RotateRight(offset)
+	case *Lazy:
+		return casted.RotateRight(offset)
 	default:
 		utils.Panic("unknown type %T", v)
 	}
@@ -93,3 +93,48 @@ func TestRotateRightSimple(t *testing.T) {
 		field.NewFromString("2")}
 	require.Equal(t, m, v_shifted)
 }
+
+func TestRotatedArithmetic(t *testing.T) {
+
+	var (
+		size  = 16
+		a     = vector.Rand(size)
+		b     = vector.Rand(size)
+		c     = vector.Rand(size)
+		x     = field.NewElement(42)
+		dense = func(v smartvectors.SmartVector) smartvectors.SmartVector {
+			return smartvectors.NewRegular(v.IntoRegVecSaveAlloc())
+		}
+	)
+
+	for _, offset := range []int{0, 1, 5, size - 1, size, -1, -7} {
+
+		t.Run(fmt.Sprintf("offset-%v", offset), func(t *testing.T) {
+
+			var (
+				rotA    = smartvectors.NewRotated(vector.DeepCopy(a), offset)
+				rotB    = smartvectors.NewRotated(vector.DeepCopy(b), -offset)
+				reg     = smartvectors.NewRegular(vector.DeepCopy(c))
+				cnst    = smartvectors.NewConstant(x, size)
+				window  = smartvectors.NewPaddedCircularWindow(vector.ForTest(1, 2, 3), x, 4, size)
+				svecs   = []smartvectors.SmartVector{rotA, reg, rotB, cnst, window}
+				densed  = []smartvectors.SmartVector{dense(rotA), reg, dense(rotB), cnst, window}
+				coeffs  = []int{2, -1, 1, 3, -2}
+				expects = func(expected, actual smartvectors.SmartVector) {
+					require.Equal(t, expected.IntoRegVecSaveAlloc(), actual.IntoRegVecSaveAlloc())
+				}
+			)
+
+			expects(smartvectors.LinComb(coeffs, densed), smartvectors.LinComb(coeffs, svecs))
+			expects(smartvectors.Product([]int{2, 1, 3}, densed[:3]), smartvectors.Product([]int{2, 1, 3}, svecs[:3]))
+			expects(smartvectors.Add(dense(rotA), dense(rotB)), smartvectors.Add(rotA, rotB))
+			expects(smartvectors.Mul(dense(rotA), reg), smartvectors.Mul(rotA, reg))
+			expects(smartvectors.ScalarMul(dense(rotA), x), smartvectors.ScalarMul(rotA, x))
+			expects(smartvectors.PolyEval(densed, x), smartvectors.PolyEval(svecs, x))
+
+			// The operands are left untouched
+			expects(densed[0], rotA)
+			expects(densed[2], rotB)
+		})
+	}
+}
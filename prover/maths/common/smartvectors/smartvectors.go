@@ -154,6 +154,10 @@ func Density(v SmartVector) int {
 		return len(w.v.Regular)
 	case *Pooled:
 		return len(w.Regular)
+	case *Sparse:
+		return len(w.indices)
+	case *Lazy:
+		return w.numStored()
 	default:
 		panic(fmt.Sprintf("unexpected type %T", v))
 	}
@@ -172,6 +176,10 @@ func Window(v SmartVector) []field.Element {
 		return *w
 	case *Rotated:
 		return w.IntoRegVecSaveAlloc()
+	case *Sparse:
+		return w.IntoRegVecSaveAlloc()
+	case *Lazy:
+		return w.IntoRegVecSaveAlloc()
 	default:
 		panic(fmt.Sprintf("unexpected type %T", v))
 	}
@@ -0,0 +1,312 @@
+package smartvectors
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/mempool"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Sparse is a smart-vector whose entries are all zero except at a few
+// positions. It is meant for the columns having a handful of scattered
+// non-zero entries, for which neither [Constant] nor [PaddedCircularWindow]
+// save memory. The non-zero entries are stored as a list of (index, value)
+// pairs sorted by increasing index.
+type Sparse struct {
+	indices []int
+	values  []field.Element
+	length  int
+}
+
+// NewSparse returns a [Sparse] of length `length` whose entry at position
+// indices[k] is values[k] and whose other entries are zero. The indices must
+// be strictly increasing and in range, otherwise the function panics. The
+// slices are not copied.
+func NewSparse(indices []int, values []field.Element, length int) *Sparse {
+
+	assertStrictPositiveLen(length)
+
+	if len(indices) != len(values) {
+		utils.Panic("there are %v indices and %v values", len(indices), len(values))
+	}
+
+	for k := range indices {
+		if indices[k] < 0 || indices[k] >= length {
+			utils.Panic("index %v is out of bound, length is %v", indices[k], length)
+		}
+		if k > 0 && indices[k] <= indices[k-1] {
+			utils.Panic("the indices are not strictly increasing: %v then %v", indices[k-1], indices[k])
+		}
+	}
+
+	return &Sparse{indices: indices, values: values, length: length}
+}
+
+// SparseFromSlice returns a [Sparse] holding the non-zero entries of v.
+func SparseFromSlice(v []field.Element) *Sparse {
+
+	assertStrictPositiveLen(len(v))
+
+	res := &Sparse{length: len(v)}
+	for i := range v {
+		if !v[i].IsZero() {
+			res.indices = append(res.indices, i)
+			res.values = append(res.values, v[i])
+		}
+	}
+	return res
+}
+
+// Len returns the length of the vector
+func (s *Sparse) Len() int { return s.length }
+
+// Get returns the entry of the vector at position n
+func (s *Sparse) Get(n int) field.Element {
+	assertCorrectBound(n, s.length)
+	if k, found := s.find(n); found {
+		return s.values[k]
+	}
+	return field.Zero()
+}
+
+// find returns the position k of n in the indices of s and whether it is
+// present. If it is not, k is the position where it would be inserted.
+func (s *Sparse) find(n int) (k int, found bool) {
+	k = sort.SearchInts(s.indices, n)
+	return k, k < len(s.indices) && s.indices[k] == n
+}
+
+// NumNonZero returns the number of explicitly stored entries of the vector.
+// They are usually non-zero but this is not enforced.
+func (s *Sparse) NumNonZero() int { return len(s.indices) }
+
+// SubVector returns a subvector of the sparse vector. It mirrors
+// slice[start:stop]. The result is a [Sparse] sharing the values of s.
+func (s *Sparse) SubVector(start, stop int) SmartVector {
+	if start > stop {
+		utils.Panic("negative length are not allowed")
+	}
+	if start == stop {
+		utils.Panic("zero length are not allowed")
+	}
+	assertCorrectBound(start, s.length)
+	// The +1 is because we accept if "stop = length"
+	assertCorrectBound(stop, s.length+1)
+
+	var (
+		kStart, _ = s.find(start)
+		kStop, _  = s.find(stop)
+		indices   = make([]int, kStop-kStart)
+	)
+
+	for k := range indices {
+		indices[k] = s.indices[kStart+k] - start
+	}
+
+	return &Sparse{indices: indices, values: s.values[kStart:kStop], length: stop - start}
+}
+
+// RotateRight returns a copy of the vector, cyclically rotated to the right
+func (s *Sparse) RotateRight(offset int) SmartVector {
+
+	var (
+		n = s.length
+		// The entries moved past the end of the vector are the ones in
+		// position n-offset and after. They come first in the result.
+		cut, _  = s.find(n - utils.PositiveMod(offset, n))
+		indices = make([]int, 0, len(s.indices))
+		values  = make([]field.Element, 0, len(s.values))
+	)
+
+	for _, k := range [][2]int{{cut, len(s.indices)}, {0, cut}} {
+		for i := k[0]; i < k[1]; i++ {
+			indices = append(indices, utils.PositiveMod(s.indices[i]+offset, n))
+		}
+		values = append(values, s.values[k[0]:k[1]]...)
+	}
+
+	return &Sparse{indices: indices, values: values, length: n}
+}
+
+// WriteInSlice writes the vector in a slice. The slice must have the same
+// length as the vector.
+func (s *Sparse) WriteInSlice(buff []field.Element) {
+	assertHasLength(len(buff), s.length)
+	vector.Fill(buff, field.Zero())
+	for k, i := range s.indices {
+		buff[i] = s.values[k]
+	}
+}
+
+func (s *Sparse) Pretty() string {
+	return fmt.Sprintf("Sparse[length=%v, indices=%v, values=%v]", s.length, s.indices, vector.Prettify(s.values))
+}
+
+func (s *Sparse) DeepCopy() SmartVector {
+	return &Sparse{
+		indices: append([]int{}, s.indices...),
+		values:  vector.DeepCopy(s.values),
+		length:  s.length,
+	}
+}
+
+func (s *Sparse) IntoRegVecSaveAlloc() []field.Element {
+	return IntoRegVec(s)
+}
+
+// processSparse applies the operator when at least one of the operands is
+// [Sparse] and returns false if there is none.
+//
+//   - For a product, the result is zero wherever a sparse operand (with a
+//     non-zero exponent) is zero. It is thus a [Sparse] supported on the
+//     intersection of the supports of the sparse operands and the other
+//     operands are only evaluated there.
+//   - For a linear combination, the non-sparse operands are processed first
+//     and the sparse ones are then added in their entries only. The result
+//     is a [Sparse] if the non-sparse part is zero or empty.
+func processSparse(op operator, coeffs []int, svecs []SmartVector, p ...mempool.MemPool) (SmartVector, bool) {
+
+	var (
+		sparses, others      []SmartVector
+		sparseCfs, othersCfs []int
+		length               = svecs[0].Len()
+	)
+
+	for i := range svecs {
+		if _, ok := svecs[i].(*Sparse); ok {
+			sparses = append(sparses, svecs[i])
+			sparseCfs = append(sparseCfs, coeffs[i])
+			continue
+		}
+		others = append(others, svecs[i])
+		othersCfs = append(othersCfs, coeffs[i])
+	}
+
+	if len(sparses) == 0 {
+		return nil, false
+	}
+
+	switch op.(type) {
+	case productOp:
+
+		// The support of the result is the one of the first sparse operand
+		// with a non-zero exponent, restricted to the support of the others.
+		// A sparse operand with exponent zero is a constant one.
+		var support []int
+		for k := range sparses {
+			if sparseCfs[k] == 0 {
+				continue
+			}
+			s := sparses[k].(*Sparse)
+			if support == nil {
+				support = append([]int{}, s.indices...)
+				continue
+			}
+			filtered := support[:0]
+			for _, i := range support {
+				if _, found := s.find(i); found {
+					filtered = append(filtered, i)
+				}
+			}
+			support = filtered
+		}
+
+		if support == nil {
+			// All the sparse operands are raised to the power zero
+			for k := range sparses {
+				sparses[k] = NewConstant(field.One(), length)
+			}
+			return processOperator(op, append(sparseCfs, othersCfs...), append(sparses, others...), p...), true
+		}
+
+		values := make([]field.Element, len(support))
+		for k, i := range support {
+			values[k] = field.One()
+			for j := range svecs {
+				x := svecs[j].Get(i)
+				op.constIntoConst(&values[k], &x, coeffs[j])
+			}
+		}
+
+		return &Sparse{indices: support, values: values, length: length}, true
+
+	case linCombOp:
+
+		res := &Sparse{length: length}
+		for k := range sparses {
+			res = addSparse(op, res, sparses[k].(*Sparse), sparseCfs[k])
+		}
+
+		if len(others) == 0 {
+			return res, true
+		}
+
+		rest := processOperator(op, othersCfs, others, p...)
+		if c, ok := rest.(*Constant); ok && c.val.IsZero() {
+			return res, true
+		}
+
+		// The result of processOperator is freshly allocated when it is a
+		// pooled vector, it can be updated in place. Otherwise, it is copied.
+		var dense []field.Element
+		pooled, isPooled := rest.(*Pooled)
+		if isPooled {
+			dense = pooled.Regular
+		} else {
+			dense = IntoRegVec(rest)
+		}
+
+		for k, i := range res.indices {
+			op.constTermIntoConst(&dense[i], &res.values[k])
+		}
+
+		if isPooled {
+			return pooled, true
+		}
+		return NewRegular(dense), true
+
+	default:
+		utils.Panic("unexpected operator %T", op)
+	}
+
+	panic("unreachable")
+}
+
+// addSparse returns the sparse vector obtained by applying the linear
+// combination operator over a and (b, coeff). The supports are merged.
+func addSparse(op operator, a, b *Sparse, coeff int) *Sparse {
+
+	var (
+		res  = &Sparse{length: a.length}
+		i, j int
+	)
+
+	res.indices = make([]int, 0, len(a.indices)+len(b.indices))
+	res.values = make([]field.Element, 0, len(a.indices)+len(b.indices))
+
+	for i < len(a.indices) || j < len(b.indices) {
+		switch {
+		case j == len(b.indices) || (i < len(a.indices) && a.indices[i] < b.indices[j]):
+			res.indices = append(res.indices, a.indices[i])
+			res.values = append(res.values, a.values[i])
+			i++
+		case i == len(a.indices) || b.indices[j] < a.indices[i]:
+			var x field.Element
+			op.constIntoTerm(&x, &b.values[j], coeff)
+			res.indices = append(res.indices, b.indices[j])
+			res.values = append(res.values, x)
+			j++
+		default:
+			x := a.values[i]
+			op.constIntoConst(&x, &b.values[j], coeff)
+			res.indices = append(res.indices, a.indices[i])
+			res.values = append(res.values, x)
+			i, j = i+1, j+1
+		}
+	}
+
+	return res
+}
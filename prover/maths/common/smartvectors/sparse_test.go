@@ -0,0 +1,133 @@
+package smartvectors
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparse(t *testing.T) {
+
+	s := NewSparse([]int{1, 2, 6}, vector.ForTest(3, 4, 5), 8)
+	expected := vector.ForTest(0, 3, 4, 0, 0, 0, 5, 0)
+
+	assert.Equal(t, 8, s.Len())
+	assert.Equal(t, 3, Density(s))
+	assert.Equal(t, expected, s.IntoRegVecSaveAlloc())
+	for i := range expected {
+		assert.Equal(t, expected[i], s.Get(i), "position %v", i)
+	}
+
+	for _, offset := range []int{0, 1, 2, 5, 7, -1, -3} {
+		assert.Equal(t,
+			NewRegular(expected).RotateRight(offset).IntoRegVecSaveAlloc(),
+			s.RotateRight(offset).IntoRegVecSaveAlloc(),
+			"offset %v", offset,
+		)
+	}
+
+	for start := 0; start < 8; start++ {
+		for stop := start + 1; stop <= 8; stop++ {
+			assert.Equal(t, expected[start:stop], s.SubVector(start, stop).IntoRegVecSaveAlloc(), "subvector %v:%v", start, stop)
+		}
+	}
+
+	assert.True(t, Equal(s, NewRegular(expected)))
+	assert.Equal(t, Digest(NewRegular(expected)), Digest(s))
+	assert.Equal(t, SparseFromSlice(expected), s)
+
+	require.Panics(t, func() { NewSparse([]int{2, 1}, vector.ForTest(3, 4), 8) }, "the indices are not sorted")
+	require.Panics(t, func() { NewSparse([]int{1, 8}, vector.ForTest(3, 4), 8) }, "the index is out of bound")
+	require.Panics(t, func() { NewSparse([]int{1}, vector.ForTest(3, 4), 8) }, "the lengths do not match")
+}
+
+func TestSparseArithmetic(t *testing.T) {
+
+	const n = 32
+
+	// #nosec G404 --we don't need a cryptographic RNG for testing purpose
+	rng := rand.New(rand.NewSource(0))
+
+	randSparse := func() SmartVector {
+		v := make([]field.Element, n)
+		for k := 0; k < 4; k++ {
+			v[rng.Intn(n)] = field.NewElement(uint64(rng.Intn(10) + 1))
+		}
+		return SparseFromSlice(v)
+	}
+
+	candidates := func() []SmartVector {
+		return []SmartVector{
+			randSparse(),
+			randSparse(),
+			NewConstant(field.NewElement(3), n),
+			NewConstant(field.Zero(), n),
+			NewPaddedCircularWindow(vector.PseudoRand(rng, 5), field.NewElement(2), 30, n),
+			NewRegular(vector.PseudoRand(rng, n)),
+			NewRotated(*NewRegular(vector.PseudoRand(rng, n)), 3),
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+
+		var (
+			all    = candidates()
+			svecs  = []SmartVector{randSparse()}
+			dense  = []SmartVector{}
+			coeffs = []int{rng.Intn(5) - 2}
+		)
+
+		for k := rng.Intn(4); k > 0; k-- {
+			svecs = append(svecs, all[rng.Intn(len(all))])
+			coeffs = append(coeffs, rng.Intn(5)-2)
+		}
+
+		for k := range svecs {
+			dense = append(dense, NewRegular(svecs[k].IntoRegVecSaveAlloc()))
+		}
+
+		t.Run(fmt.Sprintf("case-%v", i), func(t *testing.T) {
+
+			assert.Equal(t,
+				LinComb(coeffs, dense).IntoRegVecSaveAlloc(),
+				LinComb(coeffs, svecs).IntoRegVecSaveAlloc(),
+				"lincomb",
+			)
+
+			exponents := make([]int, len(coeffs))
+			for k := range coeffs {
+				exponents[k] = coeffs[k] + 2
+			}
+
+			assert.Equal(t,
+				Product(exponents, dense).IntoRegVecSaveAlloc(),
+				Product(exponents, svecs).IntoRegVecSaveAlloc(),
+				"product",
+			)
+
+			assert.Equal(t,
+				InnerProduct(dense[0], dense[len(dense)-1]),
+				InnerProduct(svecs[0], svecs[len(svecs)-1]),
+				"inner-product",
+			)
+
+			x := field.NewElement(7)
+			assert.Equal(t, EvalCoeff(dense[0], x), EvalCoeff(svecs[0], x), "eval-coeff")
+			assert.Equal(t, PolyEval(dense, x).IntoRegVecSaveAlloc(), PolyEval(svecs, x).IntoRegVecSaveAlloc(), "poly-eval")
+			assert.Equal(t, Sum(dense[0]), Sum(svecs[0]), "sum")
+		})
+	}
+
+	// The product and the linear combination of sparse vectors remain sparse
+	a, b := randSparse(), randSparse()
+	assert.IsType(t, &Sparse{}, Mul(a, b))
+	assert.IsType(t, &Sparse{}, Add(a, b))
+	assert.IsType(t, &Sparse{}, ScalarMul(a, field.NewElement(5)))
+	assert.IsType(t, &Sparse{}, Mul(a, NewRegular(vector.PseudoRand(rng, n))))
+	assert.IsType(t, &Sparse{}, Add(a, NewConstant(field.Zero(), n)))
+}
@@ -0,0 +1,144 @@
+package smartvectors
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// NumTopValues is the maximal number of values listed in [Statistics.TopValues]
+const NumTopValues = 8
+
+// Statistics collects summary statistics about the values of a smart-vector.
+// They are meant to help deciding which columns would benefit from a sparse
+// or constant representation or from tighter range checks.
+type Statistics struct {
+	// Len is the length of the vector
+	Len int
+	// Density is the size of the concrete representation of the vector, see
+	// [Density].
+	Density int
+	// NumDistinct is the number of distinct values in the vector
+	NumDistinct int
+	// MinByteWidth and MaxByteWidth are the minimal and maximal number of
+	// bytes needed to represent the (canonical) values of the vector. Zero has
+	// a byte-width of zero.
+	MinByteWidth, MaxByteWidth int
+	// ByteWidthHistogram[w] is the number of entries of the vector whose
+	// byte-width is w.
+	ByteWidthHistogram [field.Bytes + 1]int
+	// TopValues lists the most frequent values of the vector by decreasing
+	// number of occurrences. Ties are broken by increasing values. The list
+	// is truncated to [NumTopValues] entries.
+	TopValues []ValueCount
+}
+
+// ValueCount is a value of a smart-vector along with its number of
+// occurrences.
+type ValueCount struct {
+	Value field.Element
+	Count int
+}
+
+// Stats computes the [Statistics] of a smart-vector. The cost of the function
+// is linear in the [Density] of v rather than in its length.
+func Stats(v SmartVector) Statistics {
+
+	var (
+		res    = Statistics{Len: v.Len(), Density: Density(v), MinByteWidth: field.Bytes}
+		counts = map[field.Element]int{}
+	)
+
+	add := func(x field.Element, n int) {
+		if n <= 0 {
+			return
+		}
+		counts[x] += n
+	}
+
+	switch w := v.(type) {
+	case *Constant:
+		add(w.val, w.length)
+	case *PaddedCircularWindow:
+		for _, x := range w.window {
+			add(x, 1)
+		}
+		add(w.paddingVal, w.totLen-len(w.window))
+	case *Regular:
+		for _, x := range *w {
+			add(x, 1)
+		}
+	case *Pooled:
+		for _, x := range w.Regular {
+			add(x, 1)
+		}
+	case *Rotated:
+		// the statistics are invariant by rotation
+		for _, x := range w.v.Regular {
+			add(x, 1)
+		}
+	case *Sparse:
+		for _, x := range w.values {
+			add(x, 1)
+		}
+		add(field.Zero(), w.length-len(w.indices))
+	default:
+		for _, x := range v.IntoRegVecSaveAlloc() {
+			add(x, 1)
+		}
+	}
+
+	res.NumDistinct = len(counts)
+	res.TopValues = make([]ValueCount, 0, len(counts))
+
+	for x, n := range counts {
+		width := byteWidth(x)
+		res.ByteWidthHistogram[width] += n
+		res.MinByteWidth = min(res.MinByteWidth, width)
+		res.MaxByteWidth = max(res.MaxByteWidth, width)
+		res.TopValues = append(res.TopValues, ValueCount{Value: x, Count: n})
+	}
+
+	sort.Slice(res.TopValues, func(i, j int) bool {
+		a, b := res.TopValues[i], res.TopValues[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value.Cmp(&b.Value) < 0
+	})
+
+	if len(res.TopValues) > NumTopValues {
+		res.TopValues = res.TopValues[:NumTopValues]
+	}
+
+	return res
+}
+
+// String returns a one-line summary of the statistics
+func (s Statistics) String() string {
+
+	top := make([]string, len(s.TopValues))
+	for i, vc := range s.TopValues {
+		top[i] = fmt.Sprintf("%v:%v", vc.Value.String(), vc.Count)
+	}
+
+	return fmt.Sprintf(
+		"len=%v density=%v distinct=%v byte-width=[%v, %v] top=[%v]",
+		s.Len, s.Density, s.NumDistinct, s.MinByteWidth, s.MaxByteWidth, strings.Join(top, " "),
+	)
+}
+
+// byteWidth returns the number of bytes needed to represent the canonical
+// value of x.
+func byteWidth(x field.Element) int {
+	limbs := x.Bits()
+	for i := len(limbs) - 1; i >= 0; i-- {
+		if limbs[i] != 0 {
+			return 8*i + (bits.Len64(limbs[i])+7)/8
+		}
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package smartvectors
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+
+	t.Run("constant", func(t *testing.T) {
+		s := Stats(NewConstant(field.NewElement(300), 16))
+		assert.Equal(t, 16, s.Len)
+		assert.Equal(t, 0, s.Density)
+		assert.Equal(t, 1, s.NumDistinct)
+		assert.Equal(t, 2, s.MinByteWidth)
+		assert.Equal(t, 2, s.MaxByteWidth)
+		assert.Equal(t, 16, s.ByteWidthHistogram[2])
+		assert.Equal(t, []ValueCount{{Value: field.NewElement(300), Count: 16}}, s.TopValues)
+	})
+
+	t.Run("padded", func(t *testing.T) {
+		v := RightZeroPadded(vectorOf(1, 2, 2, 1<<20), 16)
+		s := Stats(v)
+		assert.Equal(t, 16, s.Len)
+		assert.Equal(t, 4, s.Density)
+		assert.Equal(t, 4, s.NumDistinct)
+		assert.Equal(t, 0, s.MinByteWidth)
+		assert.Equal(t, 3, s.MaxByteWidth)
+		assert.Equal(t, 12, s.ByteWidthHistogram[0])
+		assert.Equal(t, 3, s.ByteWidthHistogram[1])
+		assert.Equal(t, 1, s.ByteWidthHistogram[3])
+		assert.Equal(t, ValueCount{Value: field.Zero(), Count: 12}, s.TopValues[0])
+		assert.Equal(t, ValueCount{Value: field.NewElement(2), Count: 2}, s.TopValues[1])
+	})
+
+	t.Run("regular-and-rotated", func(t *testing.T) {
+		v := ForTest(5, 5, 5, 7, 0, 1, 2, 3)
+		s := Stats(v)
+		assert.Equal(t, s, Stats(v.RotateRight(3)))
+		assert.Equal(t, 6, s.NumDistinct)
+		assert.Equal(t, ValueCount{Value: field.NewElement(5), Count: 3}, s.TopValues[0])
+		// ties are broken by increasing values
+		assert.Equal(t, ValueCount{Value: field.Zero(), Count: 1}, s.TopValues[1])
+	})
+
+	t.Run("top-values-truncated", func(t *testing.T) {
+		s := Stats(ForTest(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15))
+		assert.Equal(t, 16, s.NumDistinct)
+		assert.Len(t, s.TopValues, NumTopValues)
+	})
+
+	t.Run("full-width", func(t *testing.T) {
+		s := Stats(NewConstant(field.NewFromString("-1"), 4))
+		assert.Equal(t, field.Bytes, s.MaxByteWidth)
+	})
+}
+
+func vectorOf(xs ...uint64) []field.Element {
+	res := make([]field.Element, len(xs))
+	for i := range xs {
+		res[i].SetUint64(xs[i])
+	}
+	return res
+}
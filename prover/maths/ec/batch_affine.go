@@ -0,0 +1,110 @@
+// ec provides helpers to compute over the elliptic curves of the precompiles
+// when building the witness of the zkEVM modules. The curve arithmetic itself
+// is provided by gnark-crypto.
+//
+// Only the ECDSA public key recovery converts points from Jacobian to affine
+// coordinates. The BN254 modules (ecadd, ecmul, ecpair) read affine points
+// from the arithmetization, so they have no use for the batch conversion.
+package ec
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	secp256k1fp "github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+)
+
+// FieldElement is satisfied by the pointers to the field elements of
+// gnark-crypto, e.g. *fp.Element.
+type FieldElement[E any] interface {
+	*E
+	Mul(a, b *E) *E
+	Square(a *E) *E
+	Inverse(a *E) *E
+	SetOne() *E
+	IsZero() bool
+}
+
+// batchInvert writes the inverses of xs in res, which must have the same
+// length as xs, using Montgomery's trick: a single inversion and 3(n-1)
+// multiplications. The zero elements are mapped to zero.
+func batchInvert[E any, P FieldElement[E]](res, xs []E) {
+
+	var acc E
+	P(&acc).SetOne()
+
+	// res[i] holds the product of the non-zero xs[:i]
+	for i := range xs {
+		if P(&xs[i]).IsZero() {
+			res[i] = *new(E)
+			continue
+		}
+		res[i] = acc
+		P(&acc).Mul(&acc, &xs[i])
+	}
+
+	P(&acc).Inverse(&acc)
+
+	// acc holds the inverse of the product of the non-zero xs[:i+1]
+	for i := len(xs) - 1; i >= 0; i-- {
+		if P(&xs[i]).IsZero() {
+			continue
+		}
+		P(&res[i]).Mul(&res[i], &acc)
+		P(&acc).Mul(&acc, &xs[i])
+	}
+}
+
+// jacobianToAffine converts n points from Jacobian to affine coordinates. jac
+// returns the coordinates of the i-th input point and aff the coordinates of
+// the i-th output point. The points are split in one chunk per core and each
+// chunk costs a single inversion. The points at infinity (Z = 0) are mapped to
+// (0, 0) as gnark-crypto does.
+func jacobianToAffine[E any, P FieldElement[E]](
+	n int,
+	jac func(i int) (x, y, z *E),
+	aff func(i int) (x, y *E),
+) {
+	parallel.Execute(n, func(start, stop int) {
+
+		zs := make([]E, stop-start)
+		for i := range zs {
+			_, _, z := jac(start + i)
+			zs[i] = *z
+		}
+
+		zInvs := make([]E, len(zs))
+		batchInvert[E, P](zInvs, zs)
+
+		for i := range zs {
+
+			var (
+				x, y, _ = jac(start + i)
+				ax, ay  = aff(start + i)
+				zInv2   E
+			)
+
+			if P(&zs[i]).IsZero() {
+				*ax, *ay = *new(E), *new(E)
+				continue
+			}
+
+			// (x, y) = (X / Z^2, Y / Z^3)
+			P(&zInv2).Square(&zInvs[i])
+			P(ax).Mul(x, &zInv2)
+			P(ay).Mul(y, &zInv2)
+			P(ay).Mul(ay, &zInvs[i])
+		}
+	})
+}
+
+// BatchJacobianToAffineSecp256k1 converts secp256k1 points from Jacobian to
+// affine coordinates.
+func BatchJacobianToAffineSecp256k1(points []secp256k1.G1Jac) []secp256k1.G1Affine {
+	res := make([]secp256k1.G1Affine, len(points))
+	jacobianToAffine[secp256k1fp.Element](
+		len(points),
+		func(i int) (x, y, z *secp256k1fp.Element) { return &points[i].X, &points[i].Y, &points[i].Z },
+		func(i int) (x, y *secp256k1fp.Element) { return &res[i].X, &res[i].Y },
+	)
+	return res
+}
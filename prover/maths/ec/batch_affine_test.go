@@ -0,0 +1,33 @@
+package ec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/linea-monorepo/prover/maths/ec"
+	"github.com/stretchr/testify/require"
+)
+
+// nbPoints is large enough for every core to get a chunk with several points
+const nbPoints = 1000
+
+func TestBatchJacobianToAffineSecp256k1(t *testing.T) {
+
+	g, _ := secp256k1.Generators()
+	points := make([]secp256k1.G1Jac, nbPoints)
+	for i := range points {
+		if i%100 == 0 {
+			// leave the point at infinity
+			continue
+		}
+		points[i].ScalarMultiplication(&g, big.NewInt(int64(i)))
+	}
+
+	res := ec.BatchJacobianToAffineSecp256k1(points)
+	for i := range points {
+		var expected secp256k1.G1Affine
+		expected.FromJacobian(&points[i])
+		require.Equal(t, expected, res[i], "position %v", i)
+	}
+}
@@ -15,18 +15,20 @@ import (
 Synchronizes the precomputations over multiple threads
 */
 var cosetLocks = sync.Mutex{}
-var cosetLocksLenChecking = sync.Mutex{}
 
 /*
-Indexes a set of precomputed precomCosetTables tables
+Indexes a set of precomputed coset tables
 
-  - First slices k indexes by domainSize N = 2 ^ k
-  - Second level indexes the cosetTables in a custom manner (see `findCosetTablePosition`).
+  - First level indexes the kind of table (see [CosetTables])
+  - Second level indexes by domainSize N = 2 ^ k
+  - Third level indexes the cosetTables in a custom manner (see `findCosetTablePosition`).
 */
-var precomCosetTables [][][]field.Element = make([][][]field.Element, maxOrderInt)
-var precomCosetInvTables [][][]field.Element = make([][][]field.Element, maxOrderInt)
-var precomCosetTablesBitReversed [][][]field.Element = make([][][]field.Element, maxOrderInt)
-var precomCosetInvTablesBitReversed [][][]field.Element = make([][][]field.Element, maxOrderInt)
+var precomCosetTables [numCosetTableKinds][][][]field.Element = [numCosetTableKinds][][][]field.Element{
+	make([][][]field.Element, maxOrderInt),
+	make([][][]field.Element, maxOrderInt),
+	make([][][]field.Element, maxOrderInt),
+	make([][][]field.Element, maxOrderInt),
+}
 
 /*
 Find the position of the coset table
@@ -61,6 +63,18 @@ Find the position of the coset table
 	* numCoset, the ID of the coset in the given ratio
 */
 func GetCoset(N, r, numCoset int) (cos, cosInv, cosBR, cosInvBR []field.Element) {
+	return GetCosetTable(N, r, numCoset, TableCoset),
+		GetCosetTable(N, r, numCoset, TableCosetInv),
+		GetCosetTable(N, r, numCoset, TableCosetReversed),
+		GetCosetTable(N, r, numCoset, TableCosetInvReversed)
+}
+
+/*
+GetCosetTable returns a single coset table of the coset described in the
+doc of [GetCoset]. Only the requested table and the tables it is derived
+from are computed, the result is memoized.
+*/
+func GetCosetTable(N, r, numCoset int, table CosetTables) []field.Element {
 
 	if !utils.IsPowerOfTwo(N) {
 		utils.Panic("N is not a power of two %v", N)
@@ -78,70 +92,56 @@ func GetCoset(N, r, numCoset int) (cos, cosInv, cosBR, cosInvBR []field.Element)
 		utils.Panic("The current field does not have that coset (N %v, r %v, maxOrder %v)", N, r, maxOrderInt)
 	}
 
-	cosetID := cosetID(r, numCoset)
-	order := utils.Log2Floor(N)
-
-	// If necessary, grows the slice of precomputed coset tables
-	cosetLocksLenChecking.Lock()
-	if len(precomCosetTables[order]) <= cosetID {
+	cosetLocks.Lock()
+	defer cosetLocks.Unlock()
 
-		// Append empty slices to the current precomputed coset tables
-		nbToAppend := utils.NextPowerOfTwo(cosetID + 1)
-		nbToAppend -= len(precomCosetTables[order])
-
-		zeroes := make([][]field.Element, nbToAppend)
+	return getCosetTableLocked(N, r, numCoset, table.kind())
+}
 
-		// Extend the table so that it contains the cosetID
-		// Each append creates a deep-copy of the `zeroes` slice
-		precomCosetTables[order] = append(precomCosetTables[order], zeroes...)
-		precomCosetInvTables[order] = append(precomCosetInvTables[order], zeroes...)
-		precomCosetTablesBitReversed[order] = append(precomCosetTablesBitReversed[order], zeroes...)
-		precomCosetInvTablesBitReversed[order] = append(precomCosetInvTablesBitReversed[order], zeroes...)
+// getCosetTableLocked returns the coset table of kind `kind` and computes it
+// if it is not memoized yet. The caller must hold cosetLocks.
+func getCosetTableLocked(N, r, numCoset, kind int) []field.Element {
+
+	var (
+		cosetID = cosetID(r, numCoset)
+		order   = utils.Log2Floor(N)
+		tables  = precomCosetTables[kind]
+	)
+
+	// If necessary, grows the slice of precomputed coset tables so that it
+	// contains the cosetID.
+	if len(tables[order]) <= cosetID {
+		nbToAppend := utils.NextPowerOfTwo(cosetID+1) - len(tables[order])
+		tables[order] = append(tables[order], make([][]field.Element, nbToAppend)...)
 	}
-	cosetLocksLenChecking.Unlock()
 
-	if len(precomCosetTables[order]) <= cosetID {
-		utils.Panic("Required cosetID %v but the precomp list is %v\n", cosetID, len(precomCosetInvTables[order]))
+	if len(tables[order][cosetID]) > 0 {
+		return tables[order][cosetID]
 	}
 
-	// Precompute the coset table if we need it
-	cosetLocksLenChecking.Lock()
-	if len(precomCosetTables[order][cosetID]) == 0 {
-		cosetLocks.Lock()
-		if len(precomCosetTables[order][cosetID]) == 0 {
-			cos, cosInv, cosBR, cosInvBR := computeCosetTable(N, r, numCoset)
-			precomCosetTables[order][cosetID] = cos
-			precomCosetInvTables[order][cosetID] = cosInv
-			precomCosetTablesBitReversed[order][cosetID] = cosBR
-			precomCosetInvTablesBitReversed[order][cosetID] = cosInvBR
-		}
-		cosetLocks.Unlock()
+	var res []field.Element
+
+	switch kind {
+	case kindCoset:
+		var a field.Element
+		a.SetUint64(field.MultiplicativeGen)
+		x := GetOmega(N * r)                  // x = gr
+		x.Exp(x, big.NewInt(int64(numCoset))) // x = gr^numcoset
+		x.Mul(&x, &a)                         // x = a gr^numcoset
+		res = vector.PowerVec(x, N)
+	case kindCosetInv:
+		cos := getCosetTableLocked(N, r, numCoset, kindCoset)
+		res = field.ParBatchInvert(cos, runtime.GOMAXPROCS(0))
+	case kindCosetReversed:
+		res = vector.DeepCopy(getCosetTableLocked(N, r, numCoset, kindCoset))
+		BitReverse(res)
+	case kindCosetInvReversed:
+		res = vector.DeepCopy(getCosetTableLocked(N, r, numCoset, kindCosetInv))
+		BitReverse(res)
+	default:
+		utils.Panic("unknown coset table kind %v", kind)
 	}
-	cosetLocksLenChecking.Unlock()
-
-	return precomCosetTables[order][cosetID],
-		precomCosetInvTables[order][cosetID],
-		precomCosetTablesBitReversed[order][cosetID],
-		precomCosetInvTablesBitReversed[order][cosetID]
-
-}
-
-func computeCosetTable(N, r, numCoset int) (cos, cosInv, cosBR, cosInvBR []field.Element) {
-
-	var a field.Element
-	a.SetUint64(field.MultiplicativeGen)
-	x := GetOmega(N * r)                  // x = gr
-	x.Exp(x, big.NewInt(int64(numCoset))) // x = gr^numcoset
-	x.Mul(&x, &a)                         // x = a gr^numcoset
-
-	cos = vector.PowerVec(x, N)
-	cosInv = field.ParBatchInvert(cos, runtime.GOMAXPROCS(0))
-
-	// Also computes the bit-reversed counter-parts
-	cosBR = vector.DeepCopy(cos)
-	cosInvBR = vector.DeepCopy(cosInv)
-	BitReverse(cosBR)
-	BitReverse(cosInvBR)
 
-	return cos, cosInv, cosBR, cosInvBR
+	tables[order][cosetID] = res
+	return res
 }
@@ -3,7 +3,10 @@ package fft_test
 import (
 	"testing"
 
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
 	"github.com/consensys/linea-monorepo/prover/maths/fft"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/require"
 )
 
 // Aimed at being run only during go-race
@@ -12,3 +15,40 @@ func TestGetCosetConcurrent(t *testing.T) {
 		go func() { _ = fft.NewDomain(1<<12).WithCustomCoset(4, 3) }()
 	}
 }
+
+func TestLazyCosetTables(t *testing.T) {
+
+	const n = 1 << 8
+
+	var (
+		full = fft.NewDomain(n).WithCustomCoset(4, 3)
+		lazy = fft.NewDomain(n).WithCustomCoset(4, 3, fft.TableCoset)
+		a    = make([]field.Element, n)
+	)
+
+	for i := range a {
+		a[i].SetRandom()
+	}
+
+	require.NotNil(t, lazy.CosetTable)
+	require.Nil(t, lazy.CosetTableReversed)
+	require.Nil(t, lazy.CosetTableInv)
+	require.Nil(t, lazy.CosetTableInvReversed)
+
+	for _, dec := range []fft.Decimation{fft.DIT, fft.DIF} {
+
+		expected, actual := vector.DeepCopy(a), vector.DeepCopy(a)
+		full.FFT(expected, dec, true)
+		lazy.FFT(actual, dec, true)
+		require.Equal(t, expected, actual)
+
+		full.FFTInverse(expected, dec, true)
+		lazy.FFTInverse(actual, dec, true)
+		require.Equal(t, expected, actual)
+	}
+
+	// The tables used by the FFTs are computed on access
+	require.Equal(t, full.CosetTableReversed, lazy.CosetTableReversed)
+	require.Equal(t, full.CosetTableInv, lazy.CosetTableInv)
+	require.Equal(t, full.CosetTableInvReversed, lazy.CosetTableInvReversed)
+}
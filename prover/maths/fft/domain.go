@@ -17,6 +17,8 @@
 package fft
 
 import (
+	"sync"
+
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 )
 
@@ -48,4 +50,9 @@ type Domain struct {
 	// CosetTable[i][j] = domain.Generator(i-th)SqrtInv ^ j
 	CosetTableInv         []field.Element
 	CosetTableInvReversed []field.Element // optional, this is computed on demand at the creation of the domain
+
+	// the coset of the domain (see GetCoset) and the lazy computations of the
+	// coset tables that were not precomputed by WithCustomCoset.
+	cosetRatio, cosetID int
+	lazyCosetTables     [numCosetTableKinds]sync.Once
 }
@@ -39,7 +39,7 @@ func ReEvaluateOnLargerDomainCoset(poly []field.Element, newLen int) []field.Ele
 	*/
 	large := vector.ZeroPad(small, newLen)
 	// memoized
-	domainLarge := fft.NewDomain(len(large)).WithCustomCoset(newLen/len(poly), 0)
+	domainLarge := fft.NewDomain(len(large)).WithCustomCoset(newLen/len(poly), 0, fft.TableCoset)
 	domainLarge.FFT(large, fft.DIF, true)
 	fft.BitReverse(large)
 
@@ -1,9 +1,8 @@
 package fft
 
 import (
-	"math/bits"
-
 	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 )
 
 // Decimation is used in the FFT call to select decimation in time or in frequency
@@ -33,10 +32,10 @@ func (domain *Domain) FFT(a []field.Element, decimation Decimation, coset ...boo
 			}
 		}
 		if decimation == DIT {
-			scale(domain.CosetTableReversed)
+			scale(domain.getCosetTable(TableCosetReversed))
 
 		} else {
-			scale(domain.CosetTable)
+			scale(domain.getCosetTable(TableCoset))
 		}
 	}
 
@@ -86,12 +85,12 @@ func (domain *Domain) FFTInverse(a []field.Element, decimation Decimation, coset
 		}
 	}
 	if decimation == DIT {
-		scale(domain.CosetTableInv)
+		scale(domain.getCosetTable(TableCosetInv))
 		return
 	}
 
 	// decimation == DIF
-	scale(domain.CosetTableInvReversed)
+	scale(domain.getCosetTable(TableCosetInvReversed))
 
 }
 
@@ -148,15 +147,7 @@ func ditFFT(a []field.Element, twiddles [][]field.Element, stage int) {
 // BitReverse applies the bit-reversal permutation to a.
 // len(a) must be a power of 2 (as in every single function in this file)
 func BitReverse(a []field.Element) {
-	n := uint64(len(a))
-	nn := uint64(64 - bits.TrailingZeros64(n))
-
-	for i := uint64(0); i < n; i++ {
-		irev := bits.Reverse64(i) >> nn
-		if irev > i {
-			a[i], a[irev] = a[irev], a[i]
-		}
-	}
+	bitutil.ReverseSlice(a)
 }
 
 // kerDIT8 is a kernel that process a FFT of size 8
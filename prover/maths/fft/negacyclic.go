@@ -0,0 +1,144 @@
+package fft
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
+)
+
+// NegacyclicDomain implements the negacyclic number theoretic transform (NTT)
+// over the ring F[X] / (X^n + 1). The forward transform evaluates a polynomial
+// of degree < n on the roots of X^n + 1, i.e. on the odd powers of psi, a
+// primitive 2n-th root of unity. The twisting by the powers of psi that a
+// coset FFT would perform in a separate pass is folded into the twiddles of
+// the butterflies.
+//
+// The evaluations are in bit-reversed order: the forward transform returns
+// P(psi^(2*brv(i)+1)) at position i, where brv is the bit-reversal over
+// log2(n) bits. This is the same order as the one returned by a DIF FFT on the
+// coset psi*<psi^2> so that the evaluations can be mixed with the ones of
+// [Domain.FFT] or of gnark-crypto's FFT with the same shift.
+type NegacyclicDomain struct {
+	Cardinality    uint64
+	CardinalityInv field.Element
+	// Psi is the primitive 2n-th root of unity defining the domain
+	Psi field.Element
+
+	// psiBitReversed[i] = psi^brv(i) and psiInvBitReversed[i] = psi^-brv(i)
+	// with brv the bit-reversal over log2(n) bits.
+	psiBitReversed    []field.Element
+	psiInvBitReversed []field.Element
+	// lastStageInv holds 1/n and psi^-brv(1)/n which are the twiddles of the
+	// last stage of the inverse transform once scaled by 1/n.
+	lastStageInv [2]field.Element
+}
+
+// NewNegacyclicDomain returns a [NegacyclicDomain] of size n. n must be a power
+// of two and 2n must divide the order of the 2-adic subgroup of the field.
+func NewNegacyclicDomain(n int) *NegacyclicDomain {
+
+	if !utils.IsPowerOfTwo(n) {
+		utils.Panic("`n` is not a power of two %v", n)
+	}
+
+	var (
+		logN   = utils.Log2Ceil(n)
+		domain = &NegacyclicDomain{
+			Cardinality:       uint64(n),
+			Psi:               GetOmega(2 * n),
+			psiBitReversed:    make([]field.Element, n),
+			psiInvBitReversed: make([]field.Element, n),
+		}
+		psiInv field.Element
+		pow    = field.One()
+		powInv = field.One()
+	)
+
+	domain.CardinalityInv.SetUint64(uint64(n)).Inverse(&domain.CardinalityInv)
+	psiInv.Inverse(&domain.Psi)
+
+	for i := 0; i < n; i++ {
+		j := bitutil.Reverse(i, logN)
+		domain.psiBitReversed[j] = pow
+		domain.psiInvBitReversed[j] = powInv
+		pow.Mul(&pow, &domain.Psi)
+		powInv.Mul(&powInv, &psiInv)
+	}
+
+	domain.lastStageInv[0] = domain.CardinalityInv
+	if n > 1 {
+		domain.lastStageInv[1].Mul(&domain.psiInvBitReversed[1], &domain.CardinalityInv)
+	}
+
+	return domain
+}
+
+// NTT computes the negacyclic NTT of a in place. The input is the list of the
+// coefficients of the polynomial in natural order and the output its
+// evaluations in bit-reversed order (see [NegacyclicDomain]).
+func (d *NegacyclicDomain) NTT(a []field.Element) {
+
+	n := len(a)
+	if n != int(d.Cardinality) {
+		utils.Panic("expected a vector of size %v, got %v", d.Cardinality, n)
+	}
+
+	// Cooley-Tukey butterflies with the twiddles in bit-reversed order
+	var v field.Element
+	for m, t := 1, n/2; m < n; m, t = 2*m, t/2 {
+		for i := 0; i < m; i++ {
+			var (
+				s  = &d.psiBitReversed[m+i]
+				lo = a[2*i*t : 2*i*t+t]
+				hi = a[2*i*t+t : 2*(i+1)*t]
+			)
+			for j := range lo {
+				v.Mul(&hi[j], s)
+				hi[j].Sub(&lo[j], &v)
+				lo[j].Add(&lo[j], &v)
+			}
+		}
+	}
+}
+
+// NTTInverse computes the inverse of [NegacyclicDomain.NTT] in place. The input
+// is a list of evaluations in bit-reversed order and the output the list of
+// the coefficients in natural order.
+func (d *NegacyclicDomain) NTTInverse(a []field.Element) {
+
+	n := len(a)
+	if n != int(d.Cardinality) {
+		utils.Panic("expected a vector of size %v, got %v", d.Cardinality, n)
+	}
+
+	if n == 1 {
+		a[0].Mul(&a[0], &d.CardinalityInv)
+		return
+	}
+
+	// Gentleman-Sande butterflies with the twiddles in bit-reversed order. The
+	// last stage is done separately to merge the scaling by 1/n.
+	var u field.Element
+	for m, t := n, 1; m > 2; m, t = m/2, 2*t {
+		h := m / 2
+		for i := 0; i < h; i++ {
+			var (
+				s  = &d.psiInvBitReversed[h+i]
+				lo = a[2*i*t : 2*i*t+t]
+				hi = a[2*i*t+t : 2*(i+1)*t]
+			)
+			for j := range lo {
+				u.Set(&lo[j])
+				lo[j].Add(&u, &hi[j])
+				hi[j].Sub(&u, &hi[j]).Mul(&hi[j], s)
+			}
+		}
+	}
+
+	lo, hi := a[:n/2], a[n/2:]
+	for j := range lo {
+		u.Set(&lo[j])
+		lo[j].Add(&u, &hi[j]).Mul(&lo[j], &d.lastStageInv[0])
+		hi[j].Sub(&u, &hi[j]).Mul(&hi[j], &d.lastStageInv[1])
+	}
+}
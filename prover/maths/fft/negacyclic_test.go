@@ -0,0 +1,88 @@
+package fft
+
+import (
+	"fmt"
+	"testing"
+
+	gfft "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegacyclicNTT(t *testing.T) {
+
+	for _, n := range []int{1, 2, 4, 32, 64, 1024} {
+		t.Run(fmt.Sprintf("size-%v", n), func(t *testing.T) {
+
+			var (
+				domain = NewNegacyclicDomain(n)
+				coset  = gfft.NewDomain(uint64(n), gfft.WithShift(domain.Psi))
+				a      = vector.Rand(n)
+				b      = vector.Rand(n)
+			)
+
+			// The evaluations match the ones of a DIF FFT on the coset
+			// psi*<psi^2>.
+			expected := vector.DeepCopy(a)
+			coset.FFT(expected, gfft.DIF, gfft.OnCoset())
+
+			evals := vector.DeepCopy(a)
+			domain.NTT(evals)
+			require.Equal(t, vector.Prettify(expected), vector.Prettify(evals))
+
+			domain.NTTInverse(evals)
+			require.Equal(t, vector.Prettify(a), vector.Prettify(evals))
+
+			// The pointwise product of the evaluations is the product
+			// modulo X^n + 1.
+			ea, eb := vector.DeepCopy(a), vector.DeepCopy(b)
+			domain.NTT(ea)
+			domain.NTT(eb)
+			vector.MulElementWise(ea, ea, eb)
+			domain.NTTInverse(ea)
+			require.Equal(t, vector.Prettify(negacyclicMul(a, b)), vector.Prettify(ea))
+		})
+	}
+}
+
+// negacyclicMul is the schoolbook product of a and b modulo X^n + 1
+func negacyclicMul(a, b []field.Element) []field.Element {
+	n := len(a)
+	res := make([]field.Element, n)
+	for i := range a {
+		for j := range b {
+			var t field.Element
+			t.Mul(&a[i], &b[j])
+			if i+j < n {
+				res[i+j].Add(&res[i+j], &t)
+			} else {
+				res[i+j-n].Sub(&res[i+j-n], &t)
+			}
+		}
+	}
+	return res
+}
+
+func BenchmarkNegacyclicNTT(b *testing.B) {
+
+	var (
+		domain = NewNegacyclicDomain(64)
+		a      = vector.Rand(64)
+	)
+
+	b.Run("ntt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			domain.NTT(a)
+			domain.NTTInverse(a)
+		}
+	})
+
+	coset := gfft.NewDomain(64, gfft.WithShift(domain.Psi))
+	b.Run("coset-fft", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			coset.FFT(a, gfft.DIF, gfft.OnCoset(), gfft.WithNbTasks(1))
+			coset.FFTInverse(a, gfft.DIT, gfft.OnCoset(), gfft.WithNbTasks(1))
+		}
+	})
+}
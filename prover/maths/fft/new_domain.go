@@ -2,6 +2,7 @@ package fft
 
 import (
 	"math/big"
+	"sync"
 
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
@@ -44,23 +45,121 @@ func NewDomain(m int) *Domain {
 	return domain
 }
 
+// CosetTables is a bitmask selecting coset tables of a [Domain]. It is used
+// to choose which tables [Domain.WithCustomCoset] precomputes.
+type CosetTables uint8
+
+const (
+	// TableCoset is the table used by the coset FFT in DIF mode
+	TableCoset CosetTables = 1 << iota
+	// TableCosetInv is the table used by the coset FFTInverse in DIT mode
+	TableCosetInv
+	// TableCosetReversed is the table used by the coset FFT in DIT mode
+	TableCosetReversed
+	// TableCosetInvReversed is the table used by the coset FFTInverse in DIF
+	// mode
+	TableCosetInvReversed
+	// AllCosetTables selects all the coset tables
+	AllCosetTables = TableCoset | TableCosetInv | TableCosetReversed | TableCosetInvReversed
+)
+
+// Positions of the tables in the memoized coset tables and in the [Domain]
+const (
+	kindCoset int = iota
+	kindCosetInv
+	kindCosetReversed
+	kindCosetInvReversed
+	numCosetTableKinds
+)
+
+// kind returns the position of a table which must be a single table.
+func (t CosetTables) kind() int {
+	switch t {
+	case TableCoset:
+		return kindCoset
+	case TableCosetInv:
+		return kindCosetInv
+	case TableCosetReversed:
+		return kindCosetReversed
+	case TableCosetInvReversed:
+		return kindCosetInvReversed
+	}
+	utils.Panic("expected a single coset table, got %b", t)
+	return 0
+}
+
 /*
 Equip the current domain with a coset shifted by the multiplicative generator
 */
-func (dom *Domain) WithCoset() *Domain {
-	return dom.WithCustomCoset(1, 0)
+func (dom *Domain) WithCoset(tables ...CosetTables) *Domain {
+	return dom.WithCustomCoset(1, 0, tables...)
 }
 
 /*
 Equipe the current domain with a custom coset obtained as explained in
-the doc of `GetCoset`
+the doc of `GetCoset`.
+
+By default, all the coset tables are precomputed. Passing `tables` restricts
+the precomputation to the selected tables: the other ones are left nil in the
+domain and computed on first use by [Domain.FFT] or [Domain.FFTInverse]. The
+callers should only request the tables they need as the reversed tables
+double the memory taken by the cosets.
 */
-func (dom *Domain) WithCustomCoset(r, numcoset int) *Domain {
-	n := utils.ToInt(dom.Cardinality)
-	dom.CosetTable,
-		dom.CosetTableInv,
-		dom.CosetTableReversed,
-		dom.CosetTableInvReversed = GetCoset(n, r, numcoset)
+func (dom *Domain) WithCustomCoset(r, numcoset int, tables ...CosetTables) *Domain {
+
+	selected := AllCosetTables
+	if len(tables) > 0 {
+		selected = 0
+		for _, t := range tables {
+			selected |= t
+		}
+	}
+
+	var (
+		n    = utils.ToInt(dom.Cardinality)
+		ptrs = dom.cosetTablePtrs()
+	)
+
+	dom.cosetRatio, dom.cosetID = r, numcoset
+	dom.lazyCosetTables = [numCosetTableKinds]sync.Once{}
+
+	for kind := range ptrs {
+		t := CosetTables(1) << kind
+		if selected&t == 0 {
+			*ptrs[kind] = nil
+			continue
+		}
+
+		*ptrs[kind] = GetCosetTable(n, r, numcoset, t)
+		// The table is already known, so the lazy initialization must not
+		// overwrite it.
+		dom.lazyCosetTables[kind].Do(func() {})
+	}
 
 	return dom
 }
+
+// cosetTablePtrs returns pointers to the coset tables of the domain, indexed
+// by their kind.
+func (dom *Domain) cosetTablePtrs() [numCosetTableKinds]*[]field.Element {
+	return [numCosetTableKinds]*[]field.Element{
+		&dom.CosetTable,
+		&dom.CosetTableInv,
+		&dom.CosetTableReversed,
+		&dom.CosetTableInvReversed,
+	}
+}
+
+// getCosetTable returns a coset table of the domain and computes it if it was
+// not precomputed by [Domain.WithCustomCoset].
+func (dom *Domain) getCosetTable(t CosetTables) []field.Element {
+	kind := t.kind()
+	ptr := dom.cosetTablePtrs()[kind]
+	dom.lazyCosetTables[kind].Do(func() {
+		if dom.cosetRatio == 0 {
+			utils.Panic("the domain is not equipped with a coset")
+		}
+		*ptr = GetCosetTable(utils.ToInt(dom.Cardinality), dom.cosetRatio, dom.cosetID, t)
+	})
+	return *ptr
+}
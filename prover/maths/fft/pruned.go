@@ -0,0 +1,79 @@
+package fft
+
+import (
+	"math/big"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// PrunedFFT evaluates, on the domain of size len(res), the polynomial whose
+// coefficients are all zero except for the ones in [offset, offset+len(window))
+// that are given by window. The input and the output are in natural order.
+//
+// Let m be the smallest power of two not below len(window) and k = n/m. The
+// evaluation point w^(t + k*r) splits as w^t * (w^k)^r, so the evaluations are
+// obtained with k FFTs of size m over the window scaled by the powers of w^t,
+// instead of an FFT of size n over a mostly-zero vector. The window may wrap
+// around the end of the vector as w^n = 1.
+func PrunedFFT(window []field.Element, offset int, res []field.Element) {
+
+	n := len(res)
+
+	if !utils.IsPowerOfTwo(n) {
+		utils.Panic("the size of the domain is not a power of two %v", n)
+	}
+
+	if len(window) == 0 || len(window) > n || offset < 0 || offset >= n {
+		utils.Panic("the window (offset %v, len %v) does not fit in the domain of size %v", offset, len(window), n)
+	}
+
+	var (
+		m      = utils.NextPowerOfTwo(len(window))
+		k      = n / m
+		omega  = GetOmega(n)
+		small  = NewDomain(m)
+		buf    = make([]field.Element, m)
+		omegaT = field.One()
+	)
+
+	for t := 0; t < k; t++ {
+
+		// buf[j] = window[j] * w^(t*j), the tail is zero
+		scale := field.One()
+		for j := range window {
+			buf[j].Mul(&window[j], &scale)
+			scale.Mul(&scale, &omegaT)
+		}
+		for j := len(window); j < m; j++ {
+			buf[j].SetZero()
+		}
+
+		small.FFT(buf, DIF)
+		BitReverse(buf)
+
+		for r := 0; r < m; r++ {
+			res[t+k*r] = buf[r]
+		}
+
+		omegaT.Mul(&omegaT, &omega)
+	}
+
+	if offset == 0 {
+		return
+	}
+
+	// Shifting the window by offset multiplies the evaluation at w^i by
+	// w^(i*offset).
+	var (
+		shift     field.Element
+		shiftBig  big.Int
+		shiftPowI = field.One()
+	)
+	shift.Exp(omega, shiftBig.SetInt64(int64(offset)))
+
+	for i := range res {
+		res[i].Mul(&res[i], &shiftPowI)
+		shiftPowI.Mul(&shiftPowI, &shift)
+	}
+}
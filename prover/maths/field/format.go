@@ -0,0 +1,189 @@
+package field
+
+// This file is NOT autogenerated
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// Base is the base in which the field elements are rendered by [Format]
+type Base uint8
+
+const (
+	// Decimal renders the elements in base 10
+	Decimal Base = 10
+	// Hexadecimal renders the elements in base 16 with a "0x" prefix
+	Hexadecimal Base = 16
+)
+
+// truncationMark replaces the digits removed by [Format.MaxDigits]
+const truncationMark = "..."
+
+// Format configures the rendering of the field elements by [Format.Sprint] and
+// [Table]. The zero value renders the elements in decimal, without
+// truncation, as [Element.String] does for the elements that are not small
+// negative values.
+type Format struct {
+	// Base is the base of the digits, [Decimal] if zero
+	Base Base
+	// Signed renders the elements larger than (p-1)/2 as negative numbers, so
+	// that -1 reads as "-1" and not as a 76-digit number.
+	Signed bool
+	// MaxDigits, if positive, truncates the elements having more digits by
+	// replacing the middle digits with "...". The first and the last digits
+	// are kept as they often suffice to tell two values apart.
+	MaxDigits int
+}
+
+// Sprint returns the string representation of x
+func (f Format) Sprint(x Element) string {
+
+	var (
+		n   big.Int
+		neg bool
+	)
+
+	x.BigInt(&n)
+	if f.Signed && n.Cmp(halfModulus) > 0 {
+		n.Sub(fr.Modulus(), &n)
+		neg = true
+	}
+
+	digits := n.Text(int(f.base()))
+	if f.MaxDigits > 0 && len(digits) > f.MaxDigits {
+		head := (f.MaxDigits + 1) / 2
+		tail := f.MaxDigits - head
+		digits = digits[:head] + truncationMark + digits[len(digits)-tail:]
+	}
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+	if f.base() == Hexadecimal {
+		sb.WriteString("0x")
+	}
+	sb.WriteString(digits)
+	return sb.String()
+}
+
+// Width returns the maximal length of the strings returned by [Format.Sprint]
+func (f Format) Width() int {
+	width := len(fr.Modulus().Text(int(f.base())))
+	if f.MaxDigits > 0 && width > f.MaxDigits {
+		width = f.MaxDigits + len(truncationMark)
+	}
+	if f.Signed {
+		width++
+	}
+	if f.base() == Hexadecimal {
+		width += 2
+	}
+	return width
+}
+
+func (f Format) base() Base {
+	if f.Base == 0 {
+		return Decimal
+	}
+	return f.Base
+}
+
+// halfModulus is (p-1)/2
+var halfModulus = new(big.Int).Rsh(fr.Modulus(), 1)
+
+// Table renders columns of field elements side by side, one row per line with
+// the index of the row in the first column. The elements are right-aligned
+// so that their magnitudes can be compared at a glance.
+type Table struct {
+	// Format of the elements
+	Format Format
+	// Headers names the columns. They are optional, but if provided there must
+	// be one per column.
+	Headers []string
+	// Columns lists the columns to render. They must all have the same length.
+	Columns [][]Element
+	// FirstRow is the index of the first row of the columns, it offsets the
+	// indices printed in the first column when rendering a window of larger
+	// columns.
+	FirstRow int
+	// Marked lists the indices of the rows to highlight, e.g. the row where a
+	// constraint fails. The indices include the offset [Table.FirstRow].
+	Marked []int
+}
+
+// WriteTo writes the table to w. It implements [io.WriterTo]
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+
+	if len(t.Headers) > 0 && len(t.Headers) != len(t.Columns) {
+		return 0, fmt.Errorf("the table has %v headers for %v columns", len(t.Headers), len(t.Columns))
+	}
+
+	numRows := 0
+	if len(t.Columns) > 0 {
+		numRows = len(t.Columns[0])
+	}
+
+	cells := make([][]string, len(t.Columns))
+	widths := make([]int, len(t.Columns))
+	for c := range t.Columns {
+		if len(t.Columns[c]) != numRows {
+			return 0, fmt.Errorf("column %v has %v rows, expected %v", c, len(t.Columns[c]), numRows)
+		}
+		cells[c] = make([]string, numRows)
+		for r := range t.Columns[c] {
+			cells[c][r] = t.Format.Sprint(t.Columns[c][r])
+			widths[c] = max(widths[c], len(cells[c][r]))
+		}
+		if len(t.Headers) > 0 {
+			widths[c] = max(widths[c], len(t.Headers[c]))
+		}
+	}
+
+	marked := make(map[int]bool, len(t.Marked))
+	for _, r := range t.Marked {
+		marked[r] = true
+	}
+
+	rowWidth := len(fmt.Sprint(t.FirstRow + numRows))
+	var sb strings.Builder
+
+	if len(t.Headers) > 0 {
+		rowWidth = max(rowWidth, len("row"))
+		fmt.Fprintf(&sb, "  %*s", rowWidth, "row")
+		for c := range t.Headers {
+			fmt.Fprintf(&sb, "  %*s", widths[c], t.Headers[c])
+		}
+		sb.WriteByte('\n')
+	}
+
+	for r := 0; r < numRows; r++ {
+		mark := " "
+		if marked[t.FirstRow+r] {
+			mark = ">"
+		}
+		fmt.Fprintf(&sb, "%v %*d", mark, rowWidth, t.FirstRow+r)
+		for c := range cells {
+			fmt.Fprintf(&sb, "  %*s", widths[c], cells[c][r])
+		}
+		sb.WriteByte('\n')
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// String returns the rendered table, or the error message if the table is
+// malformed.
+func (t *Table) String() string {
+	var sb strings.Builder
+	if _, err := t.WriteTo(&sb); err != nil {
+		return err.Error()
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package field
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+
+	minusOne := NewFromString("-1")
+	large := NewFromString("123456789012345678901234567890")
+
+	assert.Equal(t, "255", Format{}.Sprint(NewElement(255)))
+	assert.Equal(t, "0xff", Format{Base: Hexadecimal}.Sprint(NewElement(255)))
+	assert.Equal(t, "-1", Format{Signed: true}.Sprint(minusOne))
+	assert.Equal(t, "-0x1", Format{Base: Hexadecimal, Signed: true}.Sprint(minusOne))
+	assert.Equal(t, "12345...67890", Format{MaxDigits: 10}.Sprint(large))
+	assert.Equal(t, "123456789012345678901234567890", Format{MaxDigits: 30}.Sprint(large))
+
+	// the width bounds the length of the elements
+	for _, f := range []Format{{}, {Base: Hexadecimal}, {Signed: true}, {Base: Hexadecimal, Signed: true, MaxDigits: 8}} {
+		for _, x := range []Element{minusOne, large, NewFromString("-123456789012345678901234567890")} {
+			assert.LessOrEqual(t, len(f.Sprint(x)), f.Width(), "%+v %v", f, x.String())
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+
+	table := Table{
+		Format:   Format{Signed: true},
+		Headers:  []string{"A", "LONG_NAME"},
+		Columns:  [][]Element{{NewElement(1), NewElement(200)}, {NewFromString("-1"), NewElement(3)}},
+		FirstRow: 9,
+		Marked:   []int{10},
+	}
+
+	expected := strings.Join([]string{
+		"  row    A  LONG_NAME",
+		"    9    1         -1",
+		">  10  200          3",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, table.String())
+
+	table.Columns[1] = table.Columns[1][:1]
+	var sb strings.Builder
+	_, err := table.WriteTo(&sb)
+	require.Error(t, err, "the columns have different lengths")
+}
@@ -0,0 +1,123 @@
+package field
+
+// This file is NOT autogenerated
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// HexElement wraps an [Element] to encode it as text in hexadecimal, e.g. in
+// the JSON requests and responses. It is rendered as "0x" followed by exactly
+// 2*[Bytes] lower-case digits and parsed with [ParseText].
+type HexElement Element
+
+// DecimalElement wraps an [Element] to encode it as text in decimal. It is
+// parsed with [ParseText].
+type DecimalElement Element
+
+var (
+	// maxHexDigits and maxDecimalDigits are the number of digits of the
+	// modulus in each base, the longest canonical encodings.
+	maxHexDigits     = 2 * Bytes
+	maxDecimalDigits = len(fr.Modulus().Text(10))
+)
+
+// ParseText parses a field element encoded in decimal or in hexadecimal with
+// a "0x" prefix. Contrary to [Element.SetString], the parsing is strict: the
+// sign, the whitespaces, the underscores and the other prefixes are rejected,
+// the number of digits is bounded by the one of the modulus and the value
+// must be canonical, i.e. smaller than the modulus.
+func ParseText(s string) (Element, error) {
+
+	var (
+		digits   = s
+		base     = 10
+		maxLen   = maxDecimalDigits
+		isDigit  = func(c byte) bool { return '0' <= c && c <= '9' }
+		excerpt  = s
+		res      Element
+		n        big.Int
+		hexDigit = func(c byte) bool {
+			return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+		}
+	)
+
+	if len(excerpt) > 2*maxDecimalDigits {
+		excerpt = excerpt[:2*maxDecimalDigits] + "..."
+	}
+
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		digits, base, maxLen, isDigit = s[2:], 16, maxHexDigits, hexDigit
+	}
+
+	if len(digits) == 0 {
+		return res, fmt.Errorf("invalid field element %q: no digits", excerpt)
+	}
+
+	if len(digits) > maxLen {
+		return res, fmt.Errorf("invalid field element %q: %v digits, at most %v are allowed in base %v", excerpt, len(digits), maxLen, base)
+	}
+
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(digits[i]) {
+			return res, fmt.Errorf("invalid field element %q: invalid digit %q in base %v", excerpt, digits[i], base)
+		}
+	}
+
+	n.SetString(digits, base)
+	if n.Cmp(fr.Modulus()) >= 0 {
+		return res, fmt.Errorf("invalid field element %q: not smaller than the modulus", excerpt)
+	}
+
+	res.SetBigInt(&n)
+	return res, nil
+}
+
+// String returns the hexadecimal encoding of the element
+func (h HexElement) String() string {
+	e := Element(h)
+	b := e.Bytes()
+	return fmt.Sprintf("0x%x", b[:])
+}
+
+// MarshalText implements [encoding.TextMarshaler]
+func (h HexElement) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. It accepts the
+// decimal and the hexadecimal encodings, see [ParseText].
+func (h *HexElement) UnmarshalText(text []byte) error {
+	e, err := ParseText(string(text))
+	if err != nil {
+		return err
+	}
+	*h = HexElement(e)
+	return nil
+}
+
+// String returns the decimal encoding of the element
+func (d DecimalElement) String() string {
+	e := Element(d)
+	return e.Text(10)
+}
+
+// MarshalText implements [encoding.TextMarshaler]
+func (d DecimalElement) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. It accepts the
+// decimal and the hexadecimal encodings, see [ParseText].
+func (d *DecimalElement) UnmarshalText(text []byte) error {
+	e, err := ParseText(string(text))
+	if err != nil {
+		return err
+	}
+	*d = DecimalElement(e)
+	return nil
+}
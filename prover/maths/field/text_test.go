@@ -0,0 +1,81 @@
+package field
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseText(t *testing.T) {
+
+	var (
+		modulus      = Modulus()
+		pMinusOne    = NewFromString("-1")
+		pMinusOneDec = new(big.Int).Sub(modulus, big.NewInt(1)).Text(10)
+	)
+
+	valid := map[string]Element{
+		"0":                                   Zero(),
+		"42":                                  NewElement(42),
+		"0x2a":                                NewElement(42),
+		"0X2A":                                NewElement(42),
+		"0x" + strings.Repeat("0", 62) + "2a": NewElement(42),
+		pMinusOneDec:                          pMinusOne,
+		"0x" + modulus.Text(16)[:len(modulus.Text(16))-1] + "0": NewFromString("0x" + modulus.Text(16)[:len(modulus.Text(16))-1] + "0"),
+	}
+
+	for s, expected := range valid {
+		actual, err := ParseText(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, actual, s)
+	}
+
+	invalid := []string{
+		"",
+		"0x",
+		"-1",
+		"+1",
+		" 1",
+		"1 ",
+		"1_000",
+		"0b101",
+		"0x2g",
+		"12a",
+		modulus.Text(10),
+		"0x" + modulus.Text(16),
+		"0x" + strings.Repeat("0", 65),
+		strings.Repeat("0", maxDecimalDigits+1),
+	}
+
+	for _, s := range invalid {
+		_, err := ParseText(s)
+		assert.Error(t, err, "%q", s)
+	}
+}
+
+func TestTextMarshalling(t *testing.T) {
+
+	type fixture struct {
+		H HexElement     `json:"h"`
+		D DecimalElement `json:"d"`
+	}
+
+	x := NewElement(42)
+	b, err := json.Marshal(fixture{H: HexElement(x), D: DecimalElement(x)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"h":"0x`+strings.Repeat("0", 62)+`2a","d":"42"}`, string(b))
+
+	// Both bases are accepted whatever the type
+	var f fixture
+	require.NoError(t, json.Unmarshal([]byte(`{"h":"42","d":"0x2a"}`), &f))
+	assert.Equal(t, x, Element(f.H))
+	assert.Equal(t, x, Element(f.D))
+
+	// The numbers and the non-canonical values are rejected
+	assert.Error(t, json.Unmarshal([]byte(`{"h":42}`), &f))
+	assert.Error(t, json.Unmarshal([]byte(`{"d":"`+Modulus().Text(10)+`"}`), &f))
+}
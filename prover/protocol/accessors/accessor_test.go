@@ -1,13 +1,21 @@
 package accessors_test
 
 import (
+	"bytes"
+	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
 	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,3 +43,161 @@ func TestCoinRounds(t *testing.T) {
 	wizard.Compile(define)
 	require.Equal(t, c.Round, a.Round())
 }
+
+func TestOpenDebugColumn(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		p := b.RegisterCommit("P", 8)
+		q := b.RegisterCommit("Q", 8)
+		b.GlobalConstraint("P_EQ_Q", symbolic.Sub(p, q))
+	}
+
+	prover := func(run *wizard.ProverRuntime) {
+		v := smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7)
+		run.AssignColumn("P", v)
+		run.AssignColumn("Q", v)
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+
+	proof := wizard.Prove(comp, prover, wizard.WithDebugOpenings())
+	require.NoError(t, wizard.Verify(comp, proof))
+	require.Equal(t, []ifaces.ColID{"P", "Q"}, accessors.DebugColumns(proof))
+
+	y, err := accessors.OpenDebugColumn(proof, "Q", 3)
+	require.NoError(t, err)
+	require.Equal(t, field.NewElement(3), y)
+
+	y, err = accessors.OpenDebugColumn(proof, "P", -1)
+	require.NoError(t, err)
+	require.Equal(t, field.NewElement(7), y)
+
+	_, err = accessors.OpenDebugColumn(proof, "P", 8)
+	require.Error(t, err)
+
+	_, err = accessors.OpenDebugColumn(proof, "R", 0)
+	require.Error(t, err)
+
+	// only the requested columns are retained
+	proof = wizard.Prove(comp, prover, wizard.WithDebugOpenings("P"))
+	require.Equal(t, []ifaces.ColID{"P"}, accessors.DebugColumns(proof))
+
+	proof = wizard.Prove(comp, prover)
+	_, err = accessors.OpenDebugColumn(proof, "P", 0)
+	require.ErrorIs(t, err, accessors.ErrNoDebugOpenings)
+	require.ErrorIs(t, accessors.WriteDebugOpenings(&bytes.Buffer{}, proof), accessors.ErrNoDebugOpenings)
+}
+
+func TestWriteReadDebugOpenings(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("P", 4)
+		b.RegisterCommit("Q", 8)
+	}
+
+	prover := func(run *wizard.ProverRuntime) {
+		run.AssignColumn("P", smartvectors.ForTest(4, 5, 6, 7))
+		run.AssignColumn("Q", smartvectors.NewConstant(field.NewElement(9), 8))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	proof := wizard.Prove(comp, prover, wizard.WithDebugOpenings())
+
+	var buf bytes.Buffer
+	require.NoError(t, accessors.WriteDebugOpenings(&buf, proof))
+
+	read, err := accessors.ReadDebugOpenings(&buf)
+	require.NoError(t, err)
+	require.Equal(t, []ifaces.ColID{"P", "Q"}, accessors.DebugColumns(read))
+
+	for _, name := range accessors.DebugColumns(proof) {
+		require.Equal(t, proof.DebugOpenings[name].IntoRegVecSaveAlloc(), read.DebugOpenings[name].IntoRegVecSaveAlloc())
+	}
+
+	y, err := accessors.OpenDebugColumn(read, "P", 2)
+	require.NoError(t, err)
+	require.Equal(t, field.NewElement(6), y)
+}
+
+// countingAccessor is an [ifaces.Accessor] counting its evaluations
+type countingAccessor struct {
+	ifaces.Accessor
+	numEvals, numGnarkEvals *int
+}
+
+func (c countingAccessor) GetVal(run ifaces.Runtime) field.Element {
+	*c.numEvals++
+	return c.Accessor.GetVal(run)
+}
+
+func (c countingAccessor) GetFrontendVariable(api frontend.API, circ ifaces.GnarkRuntime) frontend.Variable {
+	*c.numGnarkEvals++
+	return c.Accessor.GetFrontendVariable(api, circ)
+}
+
+type memoizationTestCircuit struct {
+	C wizard.WizardVerifierCircuit
+}
+
+func (c *memoizationTestCircuit) Define(api frontend.API) error {
+	c.C.Verify(api)
+	return nil
+}
+
+func TestAccessorMemoization(t *testing.T) {
+
+	var numEvals, numGnarkEvals int
+
+	define := func(b *wizard.Builder) {
+		_ = b.RegisterCommit("P", 8)
+		c := b.RegisterRandomCoin("C", coin.Field)
+		a := countingAccessor{
+			Accessor:      accessors.NewFromCoin(c),
+			numEvals:      &numEvals,
+			numGnarkEvals: &numGnarkEvals,
+		}
+
+		// distinct accessors evaluating the same expression
+		var (
+			pow     = accessors.NewExponent(a, 5)
+			samePow = accessors.NewExponent(a, 5)
+			sum     = accessors.NewFromExpression(symbolic.Add(pow, samePow), "SUM")
+		)
+
+		b.InsertVerifier(1,
+			func(run *wizard.VerifierRuntime) error {
+				var expected field.Element
+				x := run.GetRandomCoinField(c.Name)
+				expected.Exp(x, big.NewInt(5))
+				require.Equal(t, expected, pow.GetVal(run))
+				require.Equal(t, expected, samePow.GetVal(run))
+				expected.Double(&expected)
+				require.Equal(t, expected, sum.GetVal(run))
+				return nil
+			},
+			func(api frontend.API, run *wizard.WizardVerifierCircuit) {
+				api.AssertIsEqual(api.Add(pow.GetFrontendVariable(api, run), samePow.GetFrontendVariable(api, run)), sum.GetFrontendVariable(api, run))
+			},
+		)
+	}
+
+	comp := wizard.Compile(define)
+	proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) {
+		run.AssignColumn("P", smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7))
+	})
+
+	// the memoized values do not outlive the verification
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, wizard.Verify(comp, proof))
+		require.Equal(t, i, numEvals)
+	}
+
+	circ := &memoizationTestCircuit{}
+	c, err := wizard.AllocateWizardCircuit(comp)
+	require.NoError(t, err)
+	circ.C = *c
+
+	_, err = frontend.Compile(ecc.BLS12_377.ScalarField(), scs.NewBuilder, circ, frontend.IgnoreUnconstrainedInputs())
+	require.NoError(t, err)
+	require.Equal(t, 1, numGnarkEvals)
+}
@@ -0,0 +1,123 @@
+package accessors
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+// ErrNoDebugOpenings is returned when opening a column of a proof generated
+// without [wizard.WithDebugOpenings].
+var ErrNoDebugOpenings = errors.New("the proof does not retain the columns, run the prover with wizard.WithDebugOpenings")
+
+// OpenDebugColumn returns the value of a column at the given row, as assigned
+// by the prover. Unlike the accessors, it does not require a runtime: the
+// proof only has to be generated with [wizard.WithDebugOpenings], which makes
+// it possible to inspect the witness of a rejected proof without running the
+// prover again. Negative rows are counted from the end of the column.
+func OpenDebugColumn(proof wizard.Proof, name ifaces.ColID, row int) (field.Element, error) {
+
+	if proof.DebugOpenings == nil {
+		return field.Element{}, ErrNoDebugOpenings
+	}
+
+	col, ok := proof.DebugOpenings[name]
+	if !ok {
+		return field.Element{}, fmt.Errorf("column %v is not retained in the proof", name)
+	}
+
+	size := col.Len()
+	if row < 0 {
+		row += size
+	}
+
+	if row < 0 || row >= size {
+		return field.Element{}, fmt.Errorf("row %v is out of bounds for column %v of size %v", row, name, size)
+	}
+
+	return col.Get(row), nil
+}
+
+// DebugColumns returns the sorted names of the columns retained in a proof
+// generated with [wizard.WithDebugOpenings].
+func DebugColumns(proof wizard.Proof) []ifaces.ColID {
+	res := make([]ifaces.ColID, 0, len(proof.DebugOpenings))
+	for name := range proof.DebugOpenings {
+		res = append(res, name)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// WriteDebugOpenings writes the columns retained in a proof generated with
+// [wizard.WithDebugOpenings], so that they can be opened once the prover has
+// exited. The columns are written by name, each one as its name followed by
+// its values, and are read back with [ReadDebugOpenings].
+func WriteDebugOpenings(w io.Writer, proof wizard.Proof) error {
+
+	if proof.DebugOpenings == nil {
+		return ErrNoDebugOpenings
+	}
+
+	names := DebugColumns(proof)
+	if err := binary.Write(w, binary.BigEndian, uint32(len(names))); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := binary.Write(w, binary.BigEndian, uint32(len(name))); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, string(name)); err != nil {
+			return err
+		}
+		values := fr.Vector(proof.DebugOpenings[name].IntoRegVecSaveAlloc())
+		if _, err := values.WriteTo(w); err != nil {
+			return fmt.Errorf("could not write column %v: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadDebugOpenings reads the columns written by [WriteDebugOpenings]. The
+// returned proof only holds the debug openings and can be passed to
+// [OpenDebugColumn] and [DebugColumns].
+func ReadDebugOpenings(r io.Reader) (wizard.Proof, error) {
+
+	var numCols uint32
+	if err := binary.Read(r, binary.BigEndian, &numCols); err != nil {
+		return wizard.Proof{}, err
+	}
+
+	openings := make(map[ifaces.ColID]ifaces.ColAssignment, numCols)
+	for i := uint32(0); i < numCols; i++ {
+
+		var nameLen uint32
+		if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil {
+			return wizard.Proof{}, err
+		}
+
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return wizard.Proof{}, err
+		}
+
+		var values fr.Vector
+		if _, err := values.ReadFrom(r); err != nil {
+			return wizard.Proof{}, fmt.Errorf("could not read column %s: %w", name, err)
+		}
+
+		openings[ifaces.ColID(name)] = smartvectors.NewRegular(values)
+	}
+
+	return wizard.Proof{DebugOpenings: openings}, nil
+}
@@ -90,9 +90,18 @@ func (e *FromExprAccessor) String() string {
 	return e.Name()
 }
 
-// GetVal implements [ifaces.Accessor]
+// GetVal implements [ifaces.Accessor]. If the runtime implements
+// [ifaces.AccessorCache], the value is memoized and reused by all the
+// accessors evaluating the same expression.
 func (e *FromExprAccessor) GetVal(run ifaces.Runtime) field.Element {
 
+	cache, isCached := run.(ifaces.AccessorCache)
+	if isCached {
+		if x, ok := cache.GetMemoizedAccessor(e.Expr.ESHash); ok {
+			return x
+		}
+	}
+
 	metadata := e.Boarded.ListVariableMetadata()
 	inputs := make([]smartvectors.SmartVector, len(metadata))
 
@@ -110,12 +119,26 @@ func (e *FromExprAccessor) GetVal(run ifaces.Runtime) field.Element {
 		}
 	}
 
-	return e.Boarded.Evaluate(inputs).Get(0)
+	res := e.Boarded.Evaluate(inputs).Get(0)
+	if isCached {
+		cache.MemoizeAccessor(e.Expr.ESHash, res)
+	}
+
+	return res
 }
 
-// GetFrontendVariable implements [ifaces.Accessor]
+// GetFrontendVariable implements [ifaces.Accessor]. As [FromExprAccessor.GetVal],
+// the value is memoized if the circuit implements [ifaces.GnarkAccessorCache],
+// so that the constraints of the expression are only emitted once.
 func (e *FromExprAccessor) GetFrontendVariable(api frontend.API, circ ifaces.GnarkRuntime) frontend.Variable {
 
+	cache, isCached := circ.(ifaces.GnarkAccessorCache)
+	if isCached {
+		if x, ok := cache.GetMemoizedAccessor(e.Expr.ESHash); ok {
+			return x
+		}
+	}
+
 	metadata := e.Boarded.ListVariableMetadata()
 	inputs := make([]frontend.Variable, len(metadata))
 
@@ -130,7 +153,12 @@ func (e *FromExprAccessor) GetFrontendVariable(api frontend.API, circ ifaces.Gna
 		}
 	}
 
-	return e.Boarded.GnarkEval(api, inputs)
+	res := e.Boarded.GnarkEval(api, inputs)
+	if isCached {
+		cache.MemoizeAccessor(e.Expr.ESHash, res)
+	}
+
+	return res
 }
 
 // AsVariable implements the [ifaces.Accessor] interface
@@ -17,6 +17,9 @@ type Store struct {
 	indicesByNames collection.Mapping[ifaces.ColID, columnPosition]
 	// stores the columns informations by [round][posInRound]
 	byRounds collection.VecVec[*storedColumnInfo]
+	// aliases maps the former names of the renamed columns to their current
+	// name, see [Store.AddAlias]. It is nil as long as no alias is declared.
+	aliases map[ifaces.ColID]ifaces.ColID
 }
 
 // NewStore constructs an empty Store object
@@ -44,6 +47,15 @@ type storedColumnInfo struct {
 	ID ifaces.ColID
 	// Status of the commitment
 	Status Status
+	// Preserved indicates that the assignment of the column must be kept
+	// by the prover until the end of the proving even if the column is
+	// [Ignored]. See [Store.MarkAsPreserved].
+	Preserved bool
+	// EffectiveSize is the length of the prefix of the column that is
+	// effectively used. The remaining suffix is padding, constant and equal
+	// to the value at position EffectiveSize. It equals Size unless declared
+	// otherwise with [Store.SetEffectiveSize].
+	EffectiveSize int
 }
 
 // AddToRound constructs a [Natural], registers it in the [Store] and returns
@@ -69,7 +81,11 @@ func (s *Store) AddToRound(round int, name ifaces.ColID, size int, status Status
 
 	// Constructing at the beginning does the validation early on
 	nat := newNatural(name, position, s)
-	infos := &storedColumnInfo{Size: size, ID: name, Status: status}
+	infos := &storedColumnInfo{Size: size, ID: name, Status: status, EffectiveSize: size}
+
+	if _, isAlias := s.aliases[name]; isAlias {
+		utils.Panic("can't register %v because it is already an alias of %v", name, s.aliases[name])
+	}
 
 	// Panic if the entry already exist
 	s.indicesByNames.InsertNew(name, position)
@@ -285,7 +301,7 @@ func (s *Store) SetStatus(name ifaces.ColID, status Status) {
 
 // Get the info of a commitment by name, panic if not found
 func (s *Store) info(name ifaces.ColID) *storedColumnInfo {
-	pos := s.indicesByNames.MustGet(name)
+	pos := s.indicesByNames.MustGet(s.Canonical(name))
 	return s.byRounds.MustGet(pos.round)[pos.posInRound]
 }
 
@@ -365,6 +381,7 @@ Panic if not found.
 */
 func (s *Store) GetHandle(name ifaces.ColID) ifaces.Column {
 	// Note that this panics if the entry is not present
+	name = s.Canonical(name)
 	position := s.indicesByNames.MustGet(name)
 	return Natural{
 		ID:       name,
@@ -375,7 +392,7 @@ func (s *Store) GetHandle(name ifaces.ColID) ifaces.Column {
 
 // Panics if the store does not have the name registered
 func (s *Store) MustHaveName(name ifaces.ColID) {
-	if !s.indicesByNames.Exists(name) {
+	if !s.Exists(name) {
 		utils.Panic("don't have %v", name)
 	}
 }
@@ -384,11 +401,11 @@ func (s *Store) MustHaveName(name ifaces.ColID) {
 // the round is the wrong one
 func (s *Store) MustBeInRound(name ifaces.ColID, round int) {
 
-	if !s.indicesByNames.Exists() {
+	if !s.Exists(name) {
 		utils.Panic("commitment %v not registered", name)
 	}
 
-	info := s.indicesByNames.MustGet(name)
+	info := s.indicesByNames.MustGet(s.Canonical(name))
 	if info.round != round {
 		utils.Panic("registered %v at round %v but asserted %v", name, info.round, round)
 	}
@@ -396,7 +413,51 @@ func (s *Store) MustBeInRound(name ifaces.ColID, round int) {
 
 // Returns if the `name` exist in the commitment store
 func (s *Store) Exists(name ifaces.ColID) bool {
-	return s.indicesByNames.Exists(name)
+	return s.indicesByNames.Exists(s.Canonical(name))
+}
+
+// AddAlias declares alias as a former name of the column registered as
+// canonical. The methods of the store accept the alias in place of the
+// canonical name and the handles returned for the alias bear the canonical
+// name. This is used to rename columns without breaking the artefacts (e.g.
+// serialized compiled IOPs or assignments) that still refer to them by their
+// former name. Panics if canonical is not registered or if alias is already
+// the name or the alias of a column.
+func (s *Store) AddAlias(alias, canonical ifaces.ColID) {
+
+	if !s.indicesByNames.Exists(canonical) {
+		utils.Panic("can't alias %v to %v because it is not registered", alias, canonical)
+	}
+
+	if s.Exists(alias) {
+		utils.Panic("can't alias %v to %v because the name is already taken", alias, canonical)
+	}
+
+	if s.aliases == nil {
+		s.aliases = map[ifaces.ColID]ifaces.ColID{}
+	}
+
+	s.aliases[alias] = canonical
+}
+
+// Canonical returns the name under which the column is registered: the
+// canonical name if name is an alias, see [Store.AddAlias], and name
+// otherwise.
+func (s *Store) Canonical(name ifaces.ColID) ifaces.ColID {
+	if canonical, isAlias := s.aliases[name]; isAlias {
+		return canonical
+	}
+	return name
+}
+
+// Aliases returns the aliases declared in the store, mapped to the canonical
+// name of their column.
+func (s *Store) Aliases() map[ifaces.ColID]ifaces.ColID {
+	res := make(map[ifaces.ColID]ifaces.ColID, len(s.aliases))
+	for alias, canonical := range s.aliases {
+		res[alias] = canonical
+	}
+	return res
 }
 
 // Marks a commitment as ignored, this can happen during a
@@ -414,6 +475,49 @@ func (s *Store) IsIgnored(name ifaces.ColID) (ignored bool) {
 	return s.Status(name) == Ignored
 }
 
+// MarkAsPreserved annotates the column so that its assignment is kept by the
+// prover runtime until the end of the proving, even once the column is
+// [Ignored] and would otherwise be freed by the clean-up compilation step. This
+// is useful to inspect the column when debugging. The annotation does not
+// change the status of the column. Panics if the name was not registered.
+func (s *Store) MarkAsPreserved(name ifaces.ColID) {
+	s.info(name).Preserved = true
+}
+
+// IsPreserved returns true if the column was annotated with
+// [Store.MarkAsPreserved].
+func (s *Store) IsPreserved(name ifaces.ColID) bool {
+	return s.info(name).Preserved
+}
+
+// SetEffectiveSize declares that only the first effectiveSize positions of
+// the column are effectively used and that the remaining suffix is padding,
+// i.e. is constant. The declaration is metadata that the prover runtime checks
+// when the column is assigned and that the compilers may use to skip work on
+// the padded suffix. It does not add any constraint. Panics if the name was
+// not registered or if the effective size is not in [0, size].
+func (s *Store) SetEffectiveSize(name ifaces.ColID, effectiveSize int) {
+	info := s.info(name)
+	if effectiveSize < 0 || effectiveSize > info.Size {
+		utils.Panic("effective size %v of %v is not in [0, %v]", effectiveSize, name, info.Size)
+	}
+	info.EffectiveSize = effectiveSize
+}
+
+// EffectiveSize returns the effective size of the column, see
+// [Store.SetEffectiveSize]. It returns the size of the column if no effective
+// size was declared.
+func (s *Store) EffectiveSize(name ifaces.ColID) int {
+	return s.info(name).EffectiveSize
+}
+
+// HasPaddedSuffix returns true if the column was declared with an effective
+// size smaller than its size.
+func (s *Store) HasPaddedSuffix(name ifaces.ColID) bool {
+	info := s.info(name)
+	return info.EffectiveSize < info.Size
+}
+
 // Sanity-checks for the function changing the status of a column
 func assertCorrectStatusTransition(old, new Status) {
 
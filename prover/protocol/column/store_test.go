@@ -26,4 +26,34 @@ func TestStore(t *testing.T) {
 
 	assert.True(t, store.IsIgnored("a"))
 
+	// The preservation is an annotation and does not affect the status
+	assert.False(t, store.IsPreserved("a"))
+	store.MarkAsPreserved("a")
+	assert.True(t, store.IsPreserved("a"))
+	assert.True(t, store.IsIgnored("a"))
+	assert.Panics(t, func() {
+		store.MarkAsPreserved("b")
+	})
+
+}
+
+func TestStoreEffectiveSize(t *testing.T) {
+
+	store := column.NewStore()
+	store.AddToRound(0, "a", 16, column.Committed)
+
+	// By default, the whole column is effectively used
+	assert.Equal(t, 16, store.EffectiveSize("a"))
+	assert.False(t, store.HasPaddedSuffix("a"))
+
+	store.SetEffectiveSize("a", 5)
+	assert.Equal(t, 5, store.EffectiveSize("a"))
+	assert.True(t, store.HasPaddedSuffix("a"))
+
+	store.SetEffectiveSize("a", 0)
+	assert.Equal(t, 0, store.EffectiveSize("a"))
+
+	assert.Panics(t, func() { store.SetEffectiveSize("a", 17) })
+	assert.Panics(t, func() { store.SetEffectiveSize("a", -1) })
+	assert.Panics(t, func() { store.SetEffectiveSize("b", 1) })
 }
@@ -17,6 +17,13 @@ import (
 
 // Arcane is a grouping of all compilers. It compiles
 // any wizard into a single-point polynomial-IOP
+//
+// The columns of the compiled protocol all have size `targetColSize`. Module
+// authors do not need to shard their columns manually when they are larger
+// than `targetColSize`: the splitter transparently segments them and adds the
+// constraints ensuring the consistency between the segments. Columns smaller
+// than `minStickSize` are sent to the verifier directly and the ones in
+// between are stuck together by the sticker.
 func Arcane(minStickSize, targetColSize int, noLog ...bool) func(comp *wizard.CompiledIOP) {
 	withLog_ := false
 	if len(noLog) > 0 {
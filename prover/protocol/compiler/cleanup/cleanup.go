@@ -1,22 +1,136 @@
 package cleanup
 
 import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/sirupsen/logrus"
+)
+
+// Level selects which of the ignored columns have their assignment freed by the
+// prover at the end of the last round.
+type Level int
+
+const (
+	// None does not free anything
+	None Level = iota
+	// Safe frees the ignored columns assigned by the prover and keeps the
+	// precomputed ones, which are cheap to keep as they are shared between
+	// all the proofs and are the ones that are usually inspected when
+	// debugging.
+	Safe
+	// Aggressive frees all the ignored columns
+	Aggressive
 )
 
-// Simple compilation steps which frees ignored items
+// String returns the name of the level, as accepted by [ParseLevel]
+func (l Level) String() string {
+	switch l {
+	case None:
+		return "none"
+	case Safe:
+		return "safe"
+	case Aggressive:
+		return "aggressive"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel returns the level with the given name
+func ParseLevel(s string) (Level, error) {
+	for _, l := range []Level{None, Safe, Aggressive} {
+		if l.String() == s {
+			return l, nil
+		}
+	}
+	return None, fmt.Errorf("unknown clean-up level %q", s)
+}
+
+// Report lists what the clean-up step does for a given level. The lists are
+// ordered by round and then by order of insertion of the columns.
+type Report struct {
+	Level Level
+	// Removed lists the ignored columns whose assignment is freed
+	Removed []ifaces.ColID
+	// Preserved lists the ignored columns kept because they were annotated
+	// as preserved in the column store.
+	Preserved []ifaces.ColID
+	// Kept lists the other ignored columns kept because of the level
+	Kept []ifaces.ColID
+	// NumCellsRemoved is the total size of the columns in Removed
+	NumCellsRemoved int
+}
+
+// Simple compilation steps which frees ignored items. It is equivalent to
+// WithLevel(Aggressive).
 func CleanUp(comp *wizard.CompiledIOP) {
-	// Gets the last round of the comp
-	lastRound := comp.NumRounds() - 1
-	// Get the list of all ignored columns
-	colToRemove := comp.Columns.AllKeysIgnored()
-
-	// The prover removes all the "now unrequired data"
-	comp.SubProvers.AppendToInner(lastRound, func(run *wizard.ProverRuntime) {
-		// Remove all the ignored columns
-		for _, col := range colToRemove {
-			run.Columns.TryDel(col)
+	WithLevel(Aggressive)(comp)
+}
+
+// WithLevel returns a compilation step freeing the ignored columns selected by
+// the level. The columns annotated as preserved are never freed. The preserved
+// columns passed to the function are annotated by the step itself, which
+// allows preserving the columns created by the compilers before the step. The
+// ones that are not registered at this stage are skipped. The step logs a
+// [Report] of the columns it removes.
+func WithLevel(level Level, preserved ...ifaces.ColID) func(comp *wizard.CompiledIOP) {
+
+	return func(comp *wizard.CompiledIOP) {
+
+		for _, col := range preserved {
+			if comp.Columns.Exists(col) {
+				comp.Columns.MarkAsPreserved(col)
+			}
+		}
+
+		report := Analyze(comp, level)
+
+		logrus.Infof(
+			"clean-up (level %v): removing %v columns (%v cells), keeping %v preserved and %v other ignored columns",
+			level, len(report.Removed), report.NumCellsRemoved, len(report.Preserved), len(report.Kept),
+		)
+
+		for _, col := range report.Removed {
+			logrus.Debugf("clean-up (level %v): removing %v", level, col)
+		}
+
+		if len(report.Removed) == 0 {
+			return
+		}
+
+		// Gets the last round of the comp
+		lastRound := comp.NumRounds() - 1
+		colToRemove := report.Removed
+
+		// The prover removes all the "now unrequired data"
+		comp.SubProvers.AppendToInner(lastRound, func(run *wizard.ProverRuntime) {
+			for _, col := range colToRemove {
+				run.DelColumn(col)
+			}
+		})
+	}
+}
+
+// Analyze returns the [Report] of what a clean-up step with the given level
+// would remove if it was applied to comp at this stage of the compilation. It
+// does not modify comp and can be used to compare the levels.
+func Analyze(comp *wizard.CompiledIOP, level Level) Report {
+
+	report := Report{Level: level}
+
+	for _, col := range comp.Columns.AllKeysIgnored() {
+
+		switch {
+		case comp.Columns.IsPreserved(col):
+			report.Preserved = append(report.Preserved, col)
+		case level == None, level == Safe && comp.Precomputed.Exists(col):
+			report.Kept = append(report.Kept, col)
+		default:
+			report.Removed = append(report.Removed, col)
+			report.NumCellsRemoved += comp.Columns.GetSize(col)
 		}
-	})
+	}
 
+	return report
 }
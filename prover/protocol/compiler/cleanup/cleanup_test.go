@@ -0,0 +1,120 @@
+package cleanup_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/cleanup"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanUpLevels(t *testing.T) {
+
+	logrus.SetLevel(logrus.FatalLevel)
+
+	var (
+		all = []ifaces.ColID{"COMMITTED", "PRECOMPUTED", "COMMITTED_PRESERVED", "PRECOMPUTED_PRESERVED"}
+
+		define = func(b *wizard.Builder) {
+			b.RegisterCommit("COMMITTED", 4)
+			b.RegisterPrecomputed("PRECOMPUTED", smartvectors.NewConstant(field.One(), 4))
+			b.RegisterCommit("COMMITTED_PRESERVED", 4)
+			b.RegisterPrecomputed("PRECOMPUTED_PRESERVED", smartvectors.NewConstant(field.One(), 4))
+			b.RegisterCommit("ACTIVE", 4)
+		}
+
+		// ignoreAll emulates a compiler compiling out all the columns but
+		// ACTIVE.
+		ignoreAll = func(comp *wizard.CompiledIOP) {
+			for _, col := range all {
+				comp.Columns.MarkAsIgnored(col)
+			}
+			comp.Columns.MarkAsPreserved("COMMITTED_PRESERVED")
+		}
+
+		prove = func(run *wizard.ProverRuntime) {
+			run.AssignColumn("COMMITTED", smartvectors.NewConstant(field.One(), 4))
+			run.AssignColumn("COMMITTED_PRESERVED", smartvectors.NewConstant(field.One(), 4))
+			run.AssignColumn("ACTIVE", smartvectors.NewConstant(field.One(), 4))
+		}
+	)
+
+	testCases := []struct {
+		level   cleanup.Level
+		removed []ifaces.ColID
+		kept    []ifaces.ColID
+	}{
+		{
+			level: cleanup.None,
+			kept:  []ifaces.ColID{"COMMITTED", "PRECOMPUTED"},
+		},
+		{
+			level:   cleanup.Safe,
+			removed: []ifaces.ColID{"COMMITTED"},
+			kept:    []ifaces.ColID{"PRECOMPUTED"},
+		},
+		{
+			level:   cleanup.Aggressive,
+			removed: []ifaces.ColID{"COMMITTED", "PRECOMPUTED"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+
+			var (
+				report cleanup.Report
+				exists = map[ifaces.ColID]bool{}
+			)
+
+			// inspect runs after the clean-up and records which columns are
+			// still assigned at the end of the proving.
+			inspect := func(comp *wizard.CompiledIOP) {
+				report = cleanup.Analyze(comp, tc.level)
+				comp.SubProvers.AppendToInner(comp.NumRounds()-1, func(run *wizard.ProverRuntime) {
+					for _, col := range all {
+						exists[col] = run.Columns.Exists(col)
+					}
+				})
+			}
+
+			// PRECOMPUTED_PRESERVED is preserved by the clean-up step and the
+			// unregistered column is skipped.
+			cleanUp := cleanup.WithLevel(tc.level, "PRECOMPUTED_PRESERVED", "UNREGISTERED")
+
+			comp := wizard.Compile(define, ignoreAll, cleanUp, inspect, dummy.Compile)
+
+			assert.Equal(t, tc.level, report.Level)
+			assert.Equal(t, tc.removed, report.Removed)
+			assert.Equal(t, tc.kept, report.Kept)
+			assert.Equal(t, []ifaces.ColID{"COMMITTED_PRESERVED", "PRECOMPUTED_PRESERVED"}, report.Preserved)
+			assert.Equal(t, 4*len(tc.removed), report.NumCellsRemoved)
+
+			proof := wizard.Prove(comp, prove)
+			require.NoError(t, wizard.Verify(comp, proof))
+
+			for _, col := range all {
+				assert.Equalf(t, !slices.Contains(tc.removed, col), exists[col], "column %v", col)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+
+	for _, level := range []cleanup.Level{cleanup.None, cleanup.Safe, cleanup.Aggressive} {
+		parsed, err := cleanup.ParseLevel(level.String())
+		require.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+
+	_, err := cleanup.ParseLevel("everything")
+	assert.Error(t, err)
+}
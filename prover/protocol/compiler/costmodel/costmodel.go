@@ -0,0 +1,267 @@
+// Package costmodel provides an analytic cost model for wizard protocols. It
+// estimates, from a [wizard.CompiledIOP] alone, the size of the proof, the
+// amount of work of the verifier and the size of the circuit needed to verify
+// the protocol recursively. The estimates can be recorded at every step of a
+// compilation suite so that the impact of tweaking the suite can be evaluated
+// without running the prover.
+package costmodel
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Params collects the unit costs the model is parametrized with.
+type Params struct {
+	// BytesPerFieldElement is the size of a serialized field element
+	BytesPerFieldElement int
+	// ConstraintsPerFieldOp is the number of constraints needed to perform a
+	// field operation (addition or multiplication) in the recursion circuit.
+	ConstraintsPerFieldOp int
+	// ConstraintsPerHash is the number of constraints needed to perform a hash
+	// permutation (compressing one field element) in the recursion circuit.
+	ConstraintsPerHash int
+}
+
+// DefaultParams corresponds to a native recursion of the wizard verifier in a
+// PLONK circuit using MiMC for the Fiat-Shamir transform. The number of
+// constraints per hash is an approximation of the cost of the MiMC gadget.
+var DefaultParams = Params{
+	BytesPerFieldElement:  field.Bytes,
+	ConstraintsPerFieldOp: 1,
+	ConstraintsPerHash:    330,
+}
+
+// Cost is the estimated cost of a wizard protocol at a given compilation
+// stage. The estimate assumes that the protocol is finalized as is: that is,
+// the columns that are still committed are sent in the clear and the queries
+// that are not yet compiled are checked directly by the verifier, as done by
+// the dummy compiler. The verifier steps registered by the compilers are
+// counted but their cost is not estimated.
+type Cost struct {
+	// Stage is the name of the compilation stage
+	Stage string
+	// NumRounds is the number of rounds of the protocol
+	NumRounds int
+	// ProofElements is the number of field elements sent by the prover: the
+	// cells of the committed and proof columns and the parameters of the
+	// queries.
+	ProofElements int
+	// ProofSizeBytes is the size of the proof in bytes
+	ProofSizeBytes int
+	// VerifierFieldOps is the number of field operations performed by the
+	// verifier to check the queries that are not compiled yet.
+	VerifierFieldOps int
+	// VerifierHashes is the number of hash permutations performed by the
+	// verifier, for the Fiat-Shamir transform and the MiMC queries.
+	VerifierHashes int
+	// NumPendingQueries is the number of queries that are not compiled yet
+	NumPendingQueries int
+	// NumVerifierSteps is the number of verifier steps registered by the
+	// compilers.
+	NumVerifierSteps int
+	// RecursionConstraints is the estimated number of constraints of a
+	// circuit verifying the protocol.
+	RecursionConstraints int
+}
+
+// Analyze estimates the cost of the protocol in its current compilation state.
+func Analyze(comp *wizard.CompiledIOP, params Params, stage string) Cost {
+
+	res := Cost{
+		Stage:            stage,
+		NumRounds:        comp.NumRounds(),
+		NumVerifierSteps: comp.NumVerifierSteps(),
+	}
+
+	for _, name := range comp.Columns.AllKeys() {
+		switch comp.Columns.Status(name) {
+		case column.Committed, column.Proof:
+			res.ProofElements += comp.Columns.GetSize(name)
+		}
+	}
+
+	for _, id := range comp.QueriesParams.AllKeys() {
+		if comp.QueriesParams.IsIgnored(id) {
+			continue
+		}
+		res.NumPendingQueries++
+		res.ProofElements += numParams(comp.QueriesParams.Data(id))
+		res.VerifierFieldOps += queryFieldOps(comp.QueriesParams.Data(id))
+	}
+
+	for _, id := range comp.QueriesNoParams.AllKeys() {
+		if comp.QueriesNoParams.IsIgnored(id) {
+			continue
+		}
+		q := comp.QueriesNoParams.Data(id)
+		res.NumPendingQueries++
+		res.VerifierFieldOps += queryFieldOps(q)
+		if q, ok := q.(query.MiMC); ok {
+			res.VerifierHashes += q.Blocks.Size()
+		}
+	}
+
+	// Every element of the proof is absorbed by the Fiat-Shamir state and
+	// every coin requires at least one squeeze.
+	res.VerifierHashes += res.ProofElements
+	for _, name := range comp.Coins.AllKeys() {
+		res.VerifierHashes += numSqueezes(comp.Coins.Data(name))
+	}
+
+	res.ProofSizeBytes = res.ProofElements * params.BytesPerFieldElement
+	res.RecursionConstraints = res.VerifierFieldOps*params.ConstraintsPerFieldOp +
+		res.VerifierHashes*params.ConstraintsPerHash
+
+	return res
+}
+
+// numParams returns the number of field elements sent by the prover to
+// assign the parameters of a query.
+func numParams(q ifaces.Query) int {
+	switch q := q.(type) {
+	case query.UnivariateEval:
+		return len(q.Pols) + 1
+	case query.InnerProduct:
+		return len(q.Bs)
+	case query.LocalOpening:
+		return 1
+	default:
+		utils.Panic("unexpected query type with parameters %T", q)
+	}
+	return 0
+}
+
+// queryFieldOps estimates the number of field operations performed by a
+// verifier checking the query directly on the assignment of its columns.
+func queryFieldOps(q ifaces.Query) int {
+	switch q := q.(type) {
+	case query.GlobalConstraint:
+		// the expression is evaluated on every row of the domain
+		return q.DomainSize * numNodes(q.Expression)
+	case query.LocalConstraint:
+		return numNodes(q.Expression)
+	case query.UnivariateEval:
+		// evaluation in Lagrange basis: about 3 operations per cell once the
+		// denominators are batch-inverted.
+		return 3 * len(q.Pols) * q.Pols[0].Size()
+	case query.InnerProduct:
+		return 2 * len(q.Bs) * q.A.Size()
+	case query.LocalOpening:
+		return 0
+	case query.Permutation:
+		// grand-product argument: a random linear combination of the columns
+		// and a product per row.
+		return grandProductOps(q.A) + grandProductOps(q.B)
+	case query.FixedPermutation:
+		return grandProductOps([][]ifaces.Column{q.A}) + grandProductOps([][]ifaces.Column{q.B})
+	case query.Inclusion:
+		// log-derivative argument: a random linear combination of the columns,
+		// an inversion (amortized by batching) and an addition per row.
+		res := 4 * (len(q.Included) + 1) * q.Included[0].Size()
+		for _, frag := range q.Including {
+			res += 4 * (len(frag) + 1) * frag[0].Size()
+		}
+		return res
+	case query.Range:
+		return q.Handle.Size()
+	case query.MiMC:
+		// accounted for as hashes
+		return 0
+	default:
+		logrus.Debugf("cost model: no estimate for query of type %T", q)
+		return 0
+	}
+}
+
+// numNodes returns the number of operations needed to evaluate an expression
+// once.
+func numNodes(expr *symbolic.Expression) int {
+	board := expr.Board()
+	return board.CountNodes()
+}
+
+// grandProductOps estimates the number of field operations needed to compute
+// the grand-product of a (possibly fragmented) table.
+func grandProductOps(table [][]ifaces.Column) int {
+	res := 0
+	for _, frag := range table {
+		res += 2 * (len(frag) + 1) * frag[0].Size()
+	}
+	return res
+}
+
+// numSqueezes returns the number of hash permutations needed to sample a coin
+func numSqueezes(info coin.Info) int {
+	switch info.Type {
+	case coin.IntegerVec:
+		// see [fiatshamir.State.RandomManyIntegers]
+		numBits := utils.Log2Ceil(info.UpperBound)
+		perDigest := (field.Bits - 1) / max(numBits, 1)
+		return utils.DivCeil(info.Size, perDigest)
+	default:
+		return 1
+	}
+}
+
+// Report records the cost of a protocol at the successive stages of a
+// compilation suite.
+type Report struct {
+	Params Params
+	Stages []Cost
+}
+
+// NewReport returns an empty report using the provided unit costs
+func NewReport(params Params) *Report {
+	return &Report{Params: params}
+}
+
+// Record returns a compilation step that appends the cost of the protocol to
+// the report. It is meant to be interleaved with the steps of a compilation
+// suite.
+func (r *Report) Record(stage string) func(comp *wizard.CompiledIOP) {
+	return func(comp *wizard.CompiledIOP) {
+		r.Stages = append(r.Stages, Analyze(comp, r.Params, stage))
+	}
+}
+
+// WriteTo writes the report as a table in w
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	sb := &strings.Builder{}
+	tw := tabwriter.NewWriter(sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STAGE\tROUNDS\tPROOF ELEMENTS\tPROOF SIZE (B)\tFIELD OPS\tHASHES\tPENDING QUERIES\tVERIFIER STEPS\tRECURSION CONSTRAINTS")
+	for _, c := range r.Stages {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			c.Stage, c.NumRounds, c.ProofElements, c.ProofSizeBytes, c.VerifierFieldOps,
+			c.VerifierHashes, c.NumPendingQueries, c.NumVerifierSteps, c.RecursionConstraints,
+		)
+	}
+	tw.Flush()
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// Log returns a compilation step that logs the estimated cost of the protocol
+// with the default parameters.
+func Log(stage string) func(comp *wizard.CompiledIOP) {
+	return func(comp *wizard.CompiledIOP) {
+		c := Analyze(comp, DefaultParams, stage)
+		logrus.Infof(
+			"COST MODEL [%v]: rounds=%v proofElements=%v proofSize=%vB fieldOps=%v hashes=%v pendingQueries=%v verifierSteps=%v recursionConstraints=%v",
+			c.Stage, c.NumRounds, c.ProofElements, c.ProofSizeBytes, c.VerifierFieldOps,
+			c.VerifierHashes, c.NumPendingQueries, c.NumVerifierSteps, c.RecursionConstraints,
+		)
+	}
+}
@@ -0,0 +1,73 @@
+package costmodel_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/costmodel"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+
+	const size = 16
+
+	define := func(build *wizard.Builder) {
+		a := build.RegisterCommit("A", size)
+		b := build.RegisterCommit("B", size)
+		build.GlobalConstraint("GLOBAL", symbolic.Sub(symbolic.Mul(a, a), b))
+		build.RegisterRandomCoin("COIN", coin.Field)
+		build.UnivariateEval("UNIVARIATE", a, b)
+	}
+
+	report := costmodel.NewReport(costmodel.DefaultParams)
+	comp := wizard.Compile(define, report.Record("initial"), dummy.Compile, report.Record("dummy"))
+	require.Len(t, report.Stages, 2)
+
+	initial := report.Stages[0]
+	assert.Equal(t, "initial", initial.Stage)
+	assert.Equal(t, 2, initial.NumRounds)
+	// the two columns and the evaluation point with the two evaluations
+	assert.Equal(t, 2*size+3, initial.ProofElements)
+	assert.Equal(t, initial.ProofElements*costmodel.DefaultParams.BytesPerFieldElement, initial.ProofSizeBytes)
+	assert.Equal(t, 2, initial.NumPendingQueries)
+	assert.Positive(t, initial.VerifierFieldOps)
+	assert.Equal(t, initial.ProofElements+1, initial.VerifierHashes)
+	assert.Equal(t,
+		initial.VerifierFieldOps*costmodel.DefaultParams.ConstraintsPerFieldOp+initial.VerifierHashes*costmodel.DefaultParams.ConstraintsPerHash,
+		initial.RecursionConstraints,
+	)
+
+	// the dummy compiler sends the columns in the clear and checks the queries
+	// in a verifier step.
+	compiled := report.Stages[1]
+	assert.Equal(t, 2*size, compiled.ProofElements)
+	assert.Equal(t, 0, compiled.NumPendingQueries)
+	assert.Equal(t, comp.NumVerifierSteps(), compiled.NumVerifierSteps)
+	assert.Positive(t, compiled.NumVerifierSteps)
+
+	sb := &strings.Builder{}
+	_, err := report.WriteTo(sb)
+	require.NoError(t, err)
+	assert.Contains(t, sb.String(), "initial")
+	assert.Contains(t, sb.String(), "dummy")
+}
+
+func TestAnalyzeIntegerVecCoin(t *testing.T) {
+
+	define := func(build *wizard.Builder) {
+		build.RegisterCommit(ifaces.ColID("A"), 4)
+		build.RegisterRandomCoin("COIN", coin.IntegerVec, 1000, 1<<10)
+	}
+
+	c := costmodel.Analyze(wizard.Compile(define), costmodel.DefaultParams, "")
+	// 25 integers of 10 bits fit in one digest, so 1000 integers require 40
+	// digests.
+	assert.Equal(t, 4+40, c.VerifierHashes)
+}
@@ -203,7 +203,6 @@ func (ctx *quotientCtx) Run(run *wizard.ProverRuntime) {
 		coeffs    = map[ifaces.ColID]sv.SmartVector{}
 		stopTimer = profiling.LogTimer("Computing the coeffs %v pols of size %v", len(ctx.AllInvolvedColumns), ctx.DomainSize)
 		lock      = sync.Mutex{}
-		lockRun   = sync.Mutex{}
 		pool      = mempool.CreateFromSyncPool(symbolic.MaxChunkSize).Prewarm(runtime.NumCPU() * ctx.MaxNbExprNode)
 		largePool = mempool.CreateFromSyncPool(ctx.DomainSize).Prewarm(len(ctx.AllInvolvedColumns))
 	)
@@ -227,10 +226,7 @@ func (ctx *quotientCtx) Run(run *wizard.ProverRuntime) {
 				name := pol.GetColID()
 
 				// gets directly a shallow copy in the map of the runtime
-				var witness sv.SmartVector
-				lockRun.Lock()
-				witness, isNatural := run.Columns.TryGet(name)
-				lockRun.Unlock()
+				witness, isNatural := run.TryGetColumn(name)
 
 				// can happen if the column is verifier defined. In that case, no
 				// need to protect with a lock. This will not touch run.Columns.
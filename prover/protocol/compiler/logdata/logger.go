@@ -13,6 +13,7 @@ func Log(msg string) func(comp *wizard.CompiledIOP) {
 		var (
 			numCommitment     = map[column.Status]int{}
 			numCells          = map[column.Status]int{}
+			numEffectiveCells = map[column.Status]int{}
 			encounteredStatus = []column.Status{}
 		)
 
@@ -29,17 +30,20 @@ func Log(msg string) func(comp *wizard.CompiledIOP) {
 			if _, ok := numCommitment[status]; !ok {
 				numCommitment[status] = 0
 				numCells[status] = 0
+				numEffectiveCells[status] = 0
 				encounteredStatus = append(encounteredStatus, status)
 			}
 
 			numCommitment[status]++
 			numCells[status] += size
+			numEffectiveCells[status] += comp.Columns.EffectiveSize(name)
 		}
 
 		for _, status := range encounteredStatus {
 			logrus.Infof(
-				"LOG METADATA : msg \"%v\"- %v - total numcomms %v - numcells %v\n",
-				msg, status.String(), numCommitment[status], numCells[status],
+				"LOG METADATA : msg \"%v\"- %v - total numcomms %v - numcells %v - effective numcells %v (%.1f%%)\n",
+				msg, status.String(), numCommitment[status], numCells[status], numEffectiveCells[status],
+				utilization(numEffectiveCells[status], numCells[status]),
 			)
 		}
 
@@ -48,6 +52,15 @@ func Log(msg string) func(comp *wizard.CompiledIOP) {
 	}
 }
 
+// utilization returns the percentage of the cells that are effectively used,
+// i.e. that are not in the padded suffix of their column.
+func utilization(effectiveCells, cells int) float64 {
+	if cells == 0 {
+		return 100
+	}
+	return 100 * float64(effectiveCells) / float64(cells)
+}
+
 func columnDims(msg string) func(comp *wizard.CompiledIOP) {
 
 	return func(comp *wizard.CompiledIOP) {
@@ -0,0 +1,40 @@
+package logdata
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/sirupsen/logrus"
+)
+
+// LogColumnStats registers, at the end of every round, a prover step logging
+// the [smartvectors.Statistics] of the columns committed during the round.
+// The statistics are only computed and logged when the debug log-level is
+// enabled so the pass can be left in a compilation suite at no cost.
+func LogColumnStats(msg string) func(comp *wizard.CompiledIOP) {
+
+	return func(comp *wizard.CompiledIOP) {
+
+		for round := 0; round < comp.NumRounds(); round++ {
+
+			names := comp.Columns.AllKeysCommittedAt(round)
+			if len(names) == 0 {
+				continue
+			}
+
+			comp.SubProvers.AppendToInner(round, func(run *wizard.ProverRuntime) {
+
+				if !logrus.IsLevelEnabled(logrus.DebugLevel) {
+					return
+				}
+
+				for _, name := range names {
+					witness, ok := run.TryGetColumn(name)
+					if !ok {
+						continue
+					}
+					logrus.Debugf("[%v] COLUMN STATS: %v %v", msg, name, smartvectors.Stats(witness))
+				}
+			})
+		}
+	}
+}
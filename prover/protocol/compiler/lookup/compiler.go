@@ -55,7 +55,8 @@ func CompileLogDerivative(comp *wizard.CompiledIOP) {
 			checkTable      = mainLookupCtx.checkedTables[lookupTableName]
 			round           = mainLookupCtx.rounds[lookupTableName]
 			includedFilters = mainLookupCtx.includedFilters[lookupTableName]
-			tableCtx        = compileLookupTable(comp, round, lookupTable, checkTable, includedFilters)
+			includedWeights = mainLookupCtx.includedWeights[lookupTableName]
+			tableCtx        = compileLookupTable(comp, round, lookupTable, checkTable, includedFilters, includedWeights)
 		)
 
 		// push to zCatalog
@@ -67,6 +68,7 @@ func CompileLogDerivative(comp *wizard.CompiledIOP) {
 				S:       checkTable,
 				T:       lookupTable,
 				SFilter: includedFilters,
+				SWeight: includedWeights,
 			},
 		)
 	}
@@ -136,7 +138,8 @@ func captureLookupTables(comp *wizard.CompiledIOP) mainLookupCtx {
 	ctx := mainLookupCtx{
 		lookupTables:    [][]table{},
 		checkedTables:   map[string][]table{},
-		includedFilters: map[string][]ifaces.Column{},
+		includedFilters: map[string][][]ifaces.Column{},
+		includedWeights: map[string][]ifaces.Column{},
 		rounds:          map[string]int{},
 	}
 
@@ -159,10 +162,10 @@ func captureLookupTables(comp *wizard.CompiledIOP) mainLookupCtx {
 			// corresponds to the including table.
 			checkedTable, lookupTable = getTableCanonicalOrder(lookup)
 			tableName                 = nameTable(lookupTable)
-			// includedFilters stores the query.IncludedFilter parameter. If the
-			// query has no includedFilters on the Included side. Then this is
-			// left as nil.
-			includedFilter ifaces.Column
+			// includedFilter stores the query.IncludedFilter parameter and the
+			// selectors of the query. If the query has no filter on the
+			// Included side. Then this is left as nil.
+			includedFilter []ifaces.Column
 		)
 
 		if lookup.IsFilteredOnIncluding() {
@@ -180,19 +183,21 @@ func captureLookupTables(comp *wizard.CompiledIOP) mainLookupCtx {
 		}
 
 		if lookup.IsFilteredOnIncluded() {
-			includedFilter = lookup.IncludedFilter
+			includedFilter = lookup.IncludedFilterColumns()
 		}
 
 		// In case this is the first iteration where we encounter the lookupTable
 		// we need to add entries in the registering maps.
 		if _, ok := ctx.checkedTables[tableName]; !ok {
-			ctx.includedFilters[tableName] = []ifaces.Column{}
+			ctx.includedFilters[tableName] = [][]ifaces.Column{}
+			ctx.includedWeights[tableName] = []ifaces.Column{}
 			ctx.checkedTables[tableName] = []table{}
 			ctx.lookupTables = append(ctx.lookupTables, lookupTable)
 			ctx.rounds[tableName] = 0
 		}
 
 		ctx.includedFilters[tableName] = append(ctx.includedFilters[tableName], includedFilter)
+		ctx.includedWeights[tableName] = append(ctx.includedWeights[tableName], lookup.IncludedWeight)
 		ctx.checkedTables[tableName] = append(ctx.checkedTables[tableName], checkedTable)
 		ctx.rounds[tableName] = max(ctx.rounds[tableName], comp.QueriesNoParams.Round(lookup.ID))
 
@@ -211,6 +216,9 @@ func captureLookupTables(comp *wizard.CompiledIOP) mainLookupCtx {
 //   - (3) The verifier makes a `Local` query : $(\Sigma_T)[0] = \frac{M_0}{T_0 + \gamma}$
 //   - (4) **(For all k)** The verifier makes a `Global` query : $\left((\Sigma_{S,k})[i] - (\Sigma_{S,k})[i-1]\right)(S_{k,i} + \gamma) = 1$
 //   - (5) The verier makes a `Global` query : $\left((\Sigma_T)[i] - (\Sigma_T)[i-1]\right)(T_i + \gamma) = M_i$
+//
+// For the filtered or weighted S_k, the numerators 1 are replaced by the
+// product of the filters and of the weight of the row.
 
 // here we are looking up set of columns S in a single column T
 func compileLookupTable(
@@ -218,13 +226,15 @@ func compileLookupTable(
 	round int,
 	lookupTable []table,
 	checkedTables []table,
-	includedFilters []ifaces.Column,
+	includedFilters [][]ifaces.Column,
+	includedWeights []ifaces.Column,
 ) (ctx singleTableCtx) {
 
 	ctx = singleTableCtx{
 		TableName: nameTable(lookupTable),
 		S:         make([]*symbolic.Expression, len(checkedTables)),
 		SFilters:  includedFilters,
+		SWeights:  includedWeights,
 		T:         make([]*symbolic.Expression, len(lookupTable)),
 		M:         make([]ifaces.Column, len(lookupTable)),
 	}
@@ -318,8 +328,15 @@ func (stc *singleTableCtx) pushToZCatalog(zCatalog map[[2]int]*zCtx) {
 			sFilter    = symbolic.NewConstant(1)
 		)
 
-		if stc.SFilters[table] != nil {
-			sFilter = symbolic.NewVariable(stc.SFilters[table])
+		if stc.SFilters[table] != nil || stc.SWeights[table] != nil {
+			factors := make([]any, 0, len(stc.SFilters[table])+1)
+			for i := range stc.SFilters[table] {
+				factors = append(factors, stc.SFilters[table][i])
+			}
+			if stc.SWeights[table] != nil {
+				factors = append(factors, stc.SWeights[table])
+			}
+			sFilter = symbolic.Mul(factors...)
 		}
 
 		key := [2]int{round, size}
@@ -476,3 +476,78 @@ func TestConditionalWithDummyCompilerOnly(t *testing.T) {
 	err := wizard.Verify(comp, proof)
 	require.NoError(t, err)
 }
+
+func TestLogDerivativeLookupWithSelectors(t *testing.T) {
+
+	var (
+		sizeT, sizeS = 4, 8
+		runtime      *wizard.ProverRuntime
+	)
+
+	define := func(b *wizard.Builder) {
+		colT := b.RegisterCommit("T", sizeT)
+		colS := b.RegisterCommit("S", sizeS)
+		isActive := b.RegisterCommit("IS_ACTIVE", sizeS)
+		isLookedUp := b.RegisterCommit("IS_LOOKED_UP", sizeS)
+		b.InclusionWithSelectors("LOOKUP", []ifaces.Column{colT}, []ifaces.Column{colS}, isActive, isLookedUp)
+	}
+
+	assign := func(s ...int) func(run *wizard.ProverRuntime) {
+		return func(run *wizard.ProverRuntime) {
+			runtime = run
+			run.AssignColumn("T", smartvectors.ForTest(0, 1, 2, 3))
+			run.AssignColumn("S", smartvectors.ForTest(s...))
+			run.AssignColumn("IS_ACTIVE", smartvectors.ForTest(1, 1, 1, 1, 1, 1, 0, 0))
+			run.AssignColumn("IS_LOOKED_UP", smartvectors.ForTest(1, 0, 1, 1, 0, 1, 1, 0))
+		}
+	}
+
+	comp := wizard.Compile(define, CompileLogDerivative, dummy.Compile)
+
+	// The selected rows are 0, 2, 3 and 5. The others hold values that are
+	// not in the table.
+	proof := wizard.Prove(comp, assign(1, 7, 3, 3, 8, 0, 9, 10))
+	require.NoError(t, wizard.Verify(comp, proof))
+
+	// No filtered copy of the selectors is committed
+	for _, name := range runtime.Columns.ListAllKeys() {
+		assert.NotContains(t, string(name), "IS_ACTIVE,")
+	}
+
+	expectedM := smartvectors.ForTest(1, 1, 0, 2)
+	actualM := runtime.GetColumn("TABLE_T_0_LOGDERIVATIVE_M")
+	assert.Equal(t, expectedM.Pretty(), actualM.Pretty(), "m does not match the expected value")
+
+	// A selected row missing from the table is caught by the prover
+	assert.Panics(t, func() {
+		wizard.Prove(comp, assign(1, 7, 3, 3, 8, 5, 9, 10))
+	})
+}
+
+func TestInclusionWithSelectorsCheck(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		colT := b.RegisterCommit("T", 4)
+		colS := b.RegisterCommit("S", 4)
+		sel1 := b.RegisterCommit("SEL1", 4)
+		sel2 := b.RegisterCommit("SEL2", 4)
+		b.InclusionWithSelectors("LOOKUP", []ifaces.Column{colT}, []ifaces.Column{colS}, sel1, sel2)
+	}
+
+	assign := func(s ...int) func(run *wizard.ProverRuntime) {
+		return func(run *wizard.ProverRuntime) {
+			run.AssignColumn("T", smartvectors.ForTest(0, 1, 2, 3))
+			run.AssignColumn("S", smartvectors.ForTest(s...))
+			run.AssignColumn("SEL1", smartvectors.ForTest(1, 1, 0, 0))
+			run.AssignColumn("SEL2", smartvectors.ForTest(1, 0, 1, 0))
+		}
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+
+	proof := wizard.Prove(comp, assign(2, 5, 6, 7))
+	require.NoError(t, wizard.Verify(comp, proof))
+
+	proof = wizard.Prove(comp, assign(5, 1, 1, 1))
+	assert.Error(t, wizard.Verify(comp, proof))
+}
@@ -28,9 +28,15 @@ type mainLookupCtx struct {
 	checkedTables map[string][]table
 
 	// includedFilters stores all the filters for the checked columns and `nil`
-	// if no filter is applied. As for [checkedTables] they are stored by
-	// lookup table name and in the same order for each key.
-	includedFilters map[string][]ifaces.Column
+	// if no filter is applied. A filter is given as a list of columns whose
+	// product gates the rows of the checked table. As for [checkedTables] they
+	// are stored by lookup table name and in the same order for each key.
+	includedFilters map[string][][]ifaces.Column
+
+	// includedWeights stores the weights of the rows of the checked columns
+	// and `nil` if the query is not weighted. They are stored as
+	// [includedFilters].
+	includedWeights map[string][]ifaces.Column
 
 	// rounds stores the interaction round assigned to each lookupTable. The
 	// round is obtained by taking the max of the declaration rounds of the
@@ -62,9 +68,14 @@ type singleTableCtx struct {
 	// query is a multi-column lookup.
 	S []*symbolic.Expression
 
-	// SFilter stores the filters that are applied on S as column and `nil` if
-	// no filter is applied over the column.
-	SFilters []ifaces.Column
+	// SFilter stores the filters that are applied on S as a list of columns
+	// to multiply and `nil` if no filter is applied over the column.
+	SFilters [][]ifaces.Column
+
+	// SWeights stores the weights of the rows of S and `nil` if the rows are
+	// not weighted. The numerator of the log-derivative sum of S is the
+	// product of the filters and of the weight.
+	SWeights []ifaces.Column
 
 	// T represents the look-up table being currently compiled. The expression
 	// is a variable if the lookup table has only a single column or a random
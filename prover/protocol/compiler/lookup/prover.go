@@ -2,6 +2,7 @@ package lookup
 
 import (
 	"runtime/debug"
+	"strings"
 	"sync"
 
 	sv "github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
@@ -135,8 +136,13 @@ type mAssignmentTask struct {
 	// by a compiled query.
 	S []table
 
-	// SFilter stores the filters that are applied for each table S.
-	SFilter []ifaces.Column
+	// SFilter stores the filters that are applied for each table S, as a list
+	// of columns to multiply.
+	SFilter [][]ifaces.Column
+
+	// SWeight stores the weights of the rows of each table S, nil if the
+	// rows are not weighted. A row counts for its weight in M.
+	SWeight []ifaces.Column
 }
 
 // run executes the task represented by the receiver of the method. Namely, it
@@ -244,10 +250,20 @@ func (a mAssignmentTask) run(run *wizard.ProverRuntime) {
 		var (
 			hasFilter = a.SFilter[i] != nil
 			filter    []field.Element
+			hasWeight = a.SWeight[i] != nil
+			weight    sv.SmartVector
 		)
 
+		if hasWeight {
+			weight = a.SWeight[i].GetColAssignment(run)
+		}
+
 		if hasFilter {
-			filter = a.SFilter[i].GetColAssignment(run).IntoRegVecSaveAlloc()
+			factors := make([]sv.SmartVector, len(a.SFilter[i]))
+			for j := range factors {
+				factors[j] = a.SFilter[i][j].GetColAssignment(run)
+			}
+			filter = sv.Mul(factors...).IntoRegVecSaveAlloc()
 		}
 
 		for k := 0; k < sCollapsed[i].Len(); k++ {
@@ -258,13 +274,21 @@ func (a mAssignmentTask) run(run *wizard.ProverRuntime) {
 
 			if hasFilter && !filter[k].IsOne() {
 				utils.Panic(
-					"the filter column `%v` has a non-binary value at position `%v`: (%v)",
-					a.SFilter[i].GetColID(),
+					"the filter `%v` has a non-binary value at position `%v`: (%v)",
+					filterName(a.SFilter[i]),
 					k,
 					filter[k].String(),
 				)
 			}
 
+			// w stores the number of times the entry is looked up
+			w := one
+			if hasWeight {
+				if w = weight.Get(k); w.IsZero() {
+					continue
+				}
+			}
+
 			var (
 				// v stores the entry of S that we are examining and looking for
 				// in the look up table.
@@ -286,7 +310,7 @@ func (a mAssignmentTask) run(run *wizard.ProverRuntime) {
 			}
 
 			mFrag, posInFragM := posInM[0], posInM[1]
-			m[mFrag][posInFragM].Add(&m[mFrag][posInFragM], &one)
+			m[mFrag][posInFragM].Add(&m[mFrag][posInFragM], &w)
 		}
 
 	}
@@ -333,3 +357,13 @@ func (z zAssignmentTask) run(run *wizard.ProverRuntime) {
 		}
 	})
 }
+
+// filterName returns a printable name for a filter given as a product of
+// columns.
+func filterName(filter []ifaces.Column) string {
+	names := make([]string, len(filter))
+	for i := range filter {
+		names[i] = string(filter[i].GetColID())
+	}
+	return strings.Join(names, " * ")
+}
@@ -0,0 +1,72 @@
+package lookup
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedLogDerivative(t *testing.T) {
+
+	var sizeT, sizeS int = 16, 8
+
+	define := func(b *wizard.Builder) {
+		colT := b.RegisterCommit("T", sizeT)
+		colS := b.RegisterCommit("S", sizeS)
+		weight := b.RegisterCommit("W", sizeS)
+		b.WeightedInclusion("LOOKUP", []ifaces.Column{colT}, []ifaces.Column{colS}, weight)
+
+		// The weight composes with the filters
+		colU := b.RegisterCommit("U", sizeS)
+		filterU := b.RegisterCommit("FILTER_U", sizeS)
+		q := query.NewInclusion("LOOKUP_FILTERED", []ifaces.Column{colU}, [][]ifaces.Column{{colT}}, filterU, nil).
+			WithIncludedWeight(weight)
+		b.QueriesNoParams.AddToRound(0, q.ID, q)
+	}
+
+	prover := func(s, u, filterU smartvectors.SmartVector) wizard.ProverStep {
+		return func(run *wizard.ProverRuntime) {
+			run.AssignColumn("T", smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15))
+			run.AssignColumn("S", s)
+			// Counting-style: the distinct values with their frequency
+			run.AssignColumn("W", smartvectors.ForTest(3, 0, 5, 1, 255, 0, 2, 0))
+			run.AssignColumn("U", u)
+			run.AssignColumn("FILTER_U", filterU)
+		}
+	}
+
+	var (
+		s       = smartvectors.ForTest(1, 20, 4, 15, 7, 30, 1, 40)
+		u       = smartvectors.ForTest(0, 50, 3, 9, 9, 50, 5, 50)
+		filterU = smartvectors.ForTest(1, 1, 1, 1, 1, 1, 1, 1)
+	)
+
+	t.Run("compiled", func(t *testing.T) {
+		comp := wizard.Compile(define, CompileLogDerivative, dummy.Compile)
+		proof := wizard.Prove(comp, prover(s, u, filterU))
+		require.NoError(t, wizard.Verify(comp, proof))
+	})
+
+	t.Run("checked", func(t *testing.T) {
+		comp := wizard.Compile(define, dummy.Compile)
+		proof := wizard.Prove(comp, prover(s, u, filterU))
+		require.NoError(t, wizard.Verify(comp, proof))
+	})
+
+	t.Run("missing-entry", func(t *testing.T) {
+		// 20 is not in the table and now has a non-zero weight
+		s := smartvectors.ForTest(20, 20, 4, 15, 7, 30, 1, 40)
+
+		comp := wizard.Compile(define, dummy.Compile)
+		proof := wizard.Prove(comp, prover(s, u, filterU))
+		require.Error(t, wizard.Verify(comp, proof))
+
+		comp = wizard.Compile(define, CompileLogDerivative, dummy.Compile)
+		require.Panics(t, func() { wizard.Prove(comp, prover(s, u, filterU)) })
+	})
+}
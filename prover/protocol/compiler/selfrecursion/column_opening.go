@@ -256,7 +256,7 @@ func (ctx *SelfRecursionCtx) linearHashAndMerkle() {
 
 		for round := 0; round <= totalNumRounds; round++ {
 			colSisHashName := ctx.VortexCtx.CommitmentName(round)
-			colSisHashSV, found := run.State.TryGet(string(colSisHashName))
+			colSisHashSV, found := run.TryGetState(string(colSisHashName))
 			if !found {
 				// continue with the same committedRound until we meet a non-dry
 				// round or we  reach the total number of committed rounds
@@ -291,7 +291,7 @@ func (ctx *SelfRecursionCtx) linearHashAndMerkle() {
 			}
 
 			// Frees the colSisHash
-			run.State.TryDel(string(colSisHashName))
+			run.DelState(string(colSisHashName))
 
 			// Increment only if the committedRound is non-dry
 			committedRound++
@@ -300,6 +300,12 @@ func assertVortexCompiled(comp *wizard.CompiledIOP) *vortex.Ctx {
 	}
 
 	vortexCtx := ctx.(*vortex.Ctx)
+
+	// The self-recursion checks the Merkle proofs using MiMC
+	if vortexCtx.UseBlake3MerkleTree {
+		utils.Panic("cannot self-recurse a Vortex compilation whose Merkle trees are built with Blake3")
+	}
+
 	// Also "stamp" that the compilation context has been cancelled
 	// this means that the verifier part of vortex will be ignored
 	// (and will be replaced by what is declared in the self-recursion)
@@ -334,3 +334,41 @@ func TestSelfRecursionPrecompMultiLayered(t *testing.T) {
 		require.NoError(subT, err)
 	})
 }
+
+// Test for the number of opened columns derived from the target soundness
+// when the minimal count is not a power of two: it is rounded up so that the
+// self-recursion can compile.
+func TestSelfRecursionDerivedNumOpenedColumns(t *testing.T) {
+
+	logrus.SetLevel(logrus.FatalLevel)
+
+	// With a blow-up factor of 8, each column brings 1.5 bits of security:
+	// a target of 18 bits gives 12 columns, rounded up to 16.
+	report, err := vortex.DeriveNumOpenedColumns(vortex.SoundnessParams{
+		BlowUpFactor: 8,
+		NumCols:      32,
+		NumRows:      32,
+		FieldBits:    field.Bits,
+		TargetBits:   18,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 12, report.MinNumOpenedColumns)
+	require.Equal(t, 16, report.NumOpenedColumns)
+
+	tc := TestCase{Numpoly: 32, NumRound: 2, PolSize: 32, SisInstance: sisInstances[0]}
+	define, prove := generateProtocol(tc)
+
+	comp := wizard.Compile(
+		define,
+		vortex.Compile(
+			8,
+			vortex.WithTargetSoundness(18),
+			vortex.WithSISParams(&tc.SisInstance),
+		),
+		selfrecursion.SelfRecurse,
+		dummy.Compile,
+	)
+
+	proof := wizard.Prove(comp, prove)
+	require.NoError(t, wizard.Verify(comp, proof))
+}
@@ -0,0 +1,113 @@
+package splitter_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArcaneOversizedColumns checks that a module can declare columns larger
+// than the target size of [compiler.Arcane] and constrain them with every
+// kind of query without sharding them manually: the splitter segments them
+// transparently and adds the junction constraints between the segments.
+func TestArcaneOversizedColumns(t *testing.T) {
+
+	logrus.SetLevel(logrus.FatalLevel)
+
+	const (
+		size       = 256
+		targetSize = 32
+	)
+
+	var (
+		counter, table ifaces.Column
+	)
+
+	define := func(b *wizard.Builder) {
+
+		counter = b.RegisterCommit("OVERSIZED_COUNTER", size)
+		table = b.RegisterPrecomputed("OVERSIZED_TABLE", smartvectors.NewRegular(rangeVec(size)))
+
+		b.GlobalConstraint(
+			"OVERSIZED_GLOBAL",
+			ifaces.ColumnAsVariable(counter).
+				Sub(ifaces.ColumnAsVariable(column.Shift(counter, -1))).
+				Sub(symbolic.NewConstant(1)),
+		)
+
+		b.LocalConstraint("OVERSIZED_LOCAL", ifaces.ColumnAsVariable(counter))
+		b.Range("OVERSIZED_RANGE", counter, size)
+		b.Inclusion("OVERSIZED_INCLUSION", []ifaces.Column{table}, []ifaces.Column{counter})
+		b.LocalOpening("OVERSIZED_OPENING", column.Shift(counter, -1))
+	}
+
+	prove := func(run *wizard.ProverRuntime) {
+		run.AssignColumn(counter.GetColID(), smartvectors.NewRegular(rangeVec(size)))
+		run.AssignLocalPoint("OVERSIZED_OPENING", field.NewElement(size-1))
+	}
+
+	comp := wizard.Compile(define, compiler.Arcane(8, targetSize, true), dummy.Compile)
+
+	for _, name := range comp.Columns.AllKeys() {
+		if comp.Columns.IsIgnored(name) {
+			continue
+		}
+		require.LessOrEqual(t, comp.Columns.GetSize(name), targetSize, "column %v was not split", name)
+	}
+
+	proof := wizard.Prove(comp, prove)
+	require.NoError(t, wizard.Verify(comp, proof))
+}
+
+func rangeVec(n int) []field.Element {
+	res := make([]field.Element, n)
+	for i := range res {
+		res[i].SetUint64(uint64(i))
+	}
+	return res
+}
+
+// TestArcaneEffectiveSize checks that the effective size declared for an
+// oversized column carries over to its segments.
+func TestArcaneEffectiveSize(t *testing.T) {
+
+	logrus.SetLevel(logrus.FatalLevel)
+
+	const (
+		size          = 256
+		targetSize    = 32
+		effectiveSize = 40
+	)
+
+	var counter ifaces.Column
+
+	define := func(b *wizard.Builder) {
+		counter = b.RegisterCommit("EFFECTIVE_COUNTER", size)
+		b.Columns.SetEffectiveSize(counter.GetColID(), effectiveSize)
+		b.LocalConstraint("EFFECTIVE_LOCAL", ifaces.ColumnAsVariable(counter))
+		b.Range("EFFECTIVE_RANGE", counter, size)
+	}
+
+	prove := func(run *wizard.ProverRuntime) {
+		run.AssignColumn(counter.GetColID(), smartvectors.RightZeroPadded(rangeVec(effectiveSize), size))
+	}
+
+	comp := wizard.Compile(define, compiler.Arcane(8, targetSize, true), dummy.Compile)
+
+	for i, expected := range []int{32, 8, 0, 0, 0, 0, 0, 0} {
+		name := ifaces.ColIDf("EFFECTIVE_COUNTER_SUBSLICE_%v_OVER_%v", i, size/targetSize)
+		require.Equal(t, expected, comp.Columns.EffectiveSize(name), "segment %v", i)
+	}
+
+	proof := wizard.Prove(comp, prove)
+	require.NoError(t, wizard.Verify(comp, proof))
+}
@@ -139,6 +139,17 @@ func Compile(comp *wizard.CompiledIOP, size int) {
 				for i := 0; i < len(subSlices); i++ {
 					subSlices[i] = comp.InsertColumn(round, nameHandleSlice(h, i, h.Size()/ctx.size), ctx.size, status)
 				}
+
+				// The padded suffix of the column carries over to the
+				// subslices. The ones falling entirely in the suffix get an
+				// effective size of zero and are assigned with constants.
+				if comp.Columns.HasPaddedSuffix(h.GetColID()) {
+					effectiveSize := comp.Columns.EffectiveSize(h.GetColID())
+					for i := 0; i < len(subSlices); i++ {
+						subEffectiveSize := min(max(effectiveSize-i*ctx.size, 0), ctx.size)
+						comp.Columns.SetEffectiveSize(subSlices[i].GetColID(), subEffectiveSize)
+					}
+				}
 			}
 
 			// And register the subslices in the map for easy access later
@@ -210,7 +221,7 @@ func Compile(comp *wizard.CompiledIOP, size int) {
 	*/
 	comp.SubProvers.AppendToInner(numRound-1, func(assi *wizard.ProverRuntime) {
 		for col := range ctx.commitmentMap.InnerMap() {
-			assi.Columns.TryDel(col)
+			assi.DelColumn(col)
 		}
 	})
 
@@ -657,7 +668,7 @@ func (ctx splitterCtx) Prove(round int) wizard.ProverStep {
 			if h.Size() < ctx.size {
 				// Handle the case where the handle is smaller than the size
 				slice := make([]field.Element, ctx.size)
-				witness := run.Columns.MustGet(h.GetColID())
+				witness := run.GetColumn(h.GetColID())
 				for i := 0; i < ctx.size; i += h.Size() {
 					witness.WriteInSlice(slice[i : i+h.Size()])
 				}
@@ -675,7 +686,7 @@ func (ctx splitterCtx) Prove(round int) wizard.ProverStep {
 				continue
 			}
 
-			witness := run.Columns.MustGet(h.GetColID())
+			witness := run.GetColumn(h.GetColID())
 			for i := 0; i < len(subSlices); i++ {
 				run.AssignColumn(subSlices[i].GetColID(), witness.SubVector(i*ctx.size, (i+1)*ctx.size))
 			}
@@ -38,7 +38,7 @@ func Sticker(minSize, maxSize int) func(comp *wizard.CompiledIOP) {
 			for _, compRound := range ctx.CompiledColumns {
 				for _, list := range compRound.BySize {
 					for _, h := range list {
-						run.Columns.TryDel(h.GetColID())
+						run.DelColumn(h.GetColID())
 					}
 				}
 			}
@@ -312,8 +312,8 @@ func (ctx *stickContext) createMapToNew() {
 				for i := range witnesses {
 					// If the column is allocated in the runtime (e.g. not a verifier column)
 					// then we use a shallow copy of it.
-					if run.Columns.Exists(group[i].GetColID()) {
-						witnesses[i] = run.Columns.MustGet(group[i].GetColID())
+					if witness, ok := run.TryGetColumn(group[i].GetColID()); ok {
+						witnesses[i] = witness
 						continue
 					}
 					// Else, we use the witness getting features attached to the column. (Which
@@ -433,7 +433,7 @@ func (ctx *stickContext) compileFixedEvaluation() {
 		// new query @alex, it might be beneficial to run this in parallel
 		// We don't do it because we think this is not necessary.
 		ctx.comp.SubProvers.AppendToInner(round, func(run *wizard.ProverRuntime) {
-			y := run.QueriesParams.MustGet(q.ID).(query.LocalOpeningParams).Y
+			y := run.GetParams(q.ID).(query.LocalOpeningParams).Y
 			run.AssignLocalPoint(newQ.ID, y)
 		})
 
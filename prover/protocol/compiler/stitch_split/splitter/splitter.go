@@ -21,7 +21,7 @@ func Splitter(size int) func(*wizard.CompiledIOP) {
 		comp.SubProvers.AppendToInner(comp.NumRounds()-1, func(run *wizard.ProverRuntime) {
 			for round := range ctx.Splittings {
 				for bigCol := range ctx.Splittings[round].ByBigCol {
-					run.Columns.TryDel(bigCol)
+					run.DelColumn(bigCol)
 				}
 			}
 		})
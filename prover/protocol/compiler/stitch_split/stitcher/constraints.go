@@ -62,7 +62,7 @@ func (ctx stitchingContext) LocalOpening() {
 
 		// Registers the prover's step responsible for assigning the new query
 		ctx.comp.SubProvers.AppendToInner(round, func(run *wizard.ProverRuntime) {
-			y := run.QueriesParams.MustGet(q.ID).(query.LocalOpeningParams).Y
+			y := run.GetParams(q.ID).(query.LocalOpeningParams).Y
 			run.AssignLocalPoint(newQ.ID, y)
 		})
 	}
@@ -38,7 +38,7 @@ func Stitcher(minSize, maxSize int) func(comp *wizard.CompiledIOP) {
 		comp.SubProvers.AppendToInner(comp.NumRounds()-1, func(run *wizard.ProverRuntime) {
 			for round := range ctx.Stitchings {
 				for subCol := range ctx.Stitchings[round].BySubCol {
-					run.Columns.TryDel(subCol)
+					run.DelColumn(subCol)
 				}
 			}
 		})
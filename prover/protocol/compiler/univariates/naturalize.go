@@ -48,6 +48,16 @@ func Naturalize(comp *wizard.CompiledIOP) {
 
 	// The compilation process is applied separately for each query
 	for roundID := 0; roundID < comp.NumRounds(); roundID++ {
+
+		// The prover steps of the queries of the round are independent from
+		// one another, they only need the steps registered so far in the
+		// round to have assigned the original queries. This lets the runtime
+		// run them concurrently.
+		deps := make([]int, comp.SubProvers.LenOf(roundID))
+		for i := range deps {
+			deps[i] = i
+		}
+
 		for _, qName := range comp.QueriesParams.AllKeysAt(roundID) {
 
 			if comp.QueriesParams.IsIgnored(qName) {
@@ -101,7 +111,7 @@ func Naturalize(comp *wizard.CompiledIOP) {
 			/*
 				And assigns them
 			*/
-			comp.SubProvers.AppendToInner(roundID, ctx.prove)
+			comp.RegisterProverActionWithDeps(roundID, &ctx, deps...)
 
 			comp.InsertVerifier(roundID, ctx.Verify, ctx.GnarkVerify)
 		}
@@ -184,9 +194,9 @@ func (ctx *naturalizationCtx) registersTheNewQueries(comp *wizard.CompiledIOP) {
 }
 
 /*
-Generates assignment for the new query
+Generates assignment for the new query, implements [wizard.ProverAction]
 */
-func (ctx *naturalizationCtx) prove(run *wizard.ProverRuntime) {
+func (ctx *naturalizationCtx) Run(run *wizard.ProverRuntime) {
 
 	// At this time, the originalQuery query should be assigned already
 	originalQuery := run.GetUnivariateParams(ctx.q.QueryID)
@@ -0,0 +1,85 @@
+package vortex
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/consensys/linea-monorepo/prover/crypto/vortex"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+)
+
+// registerStateCodecs registers the codecs of the entries of the prover State
+// inserted by the commitment of the round, so that the prover can be
+// checkpointed between the commitment and the opening.
+func (ctx *Ctx) registerStateCodecs(round int) {
+
+	if ctx.isDry(round) {
+		return
+	}
+
+	ctx.comp.RegisterStateCodec(ctx.VortexProverStateName(round), encodedMatrixCodec{})
+	ctx.comp.RegisterStateCodec(ctx.MerkleTreeName(round), merkleTreeCodec{ctx: ctx})
+
+	// The SIS digests are only inserted if the context is self-recursed,
+	// which is decided after the compilation of Vortex.
+	ctx.comp.RegisterStateCodec(string(ctx.CommitmentName(round)), wizard.GobStateCodec[[]field.Element]{})
+}
+
+// encodedMatrixCodec saves the Reed-Solomon encoded matrix of a round as its
+// rows.
+type encodedMatrixCodec struct{}
+
+func (encodedMatrixCodec) EncodeState(w io.Writer, v any) error {
+
+	m, ok := v.(vortex.EncodedMatrix)
+	if !ok {
+		return fmt.Errorf("expected an encoded matrix, got a %T", v)
+	}
+
+	rows := make([][]field.Element, len(m))
+	for i := range m {
+		rows[i] = m[i].IntoRegVecSaveAlloc()
+	}
+	return gob.NewEncoder(w).Encode(rows)
+}
+
+func (encodedMatrixCodec) DecodeState(r io.Reader) (any, error) {
+
+	var rows [][]field.Element
+	if err := gob.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+
+	m := make(vortex.EncodedMatrix, len(rows))
+	for i := range rows {
+		m[i] = smartvectors.NewRegular(rows[i])
+	}
+	return m, nil
+}
+
+// merkleTreeCodec saves the Merkle tree of a round as its leaves, the tree is
+// rebuilt from them with the hash function of the parameters of Vortex.
+type merkleTreeCodec struct {
+	ctx *Ctx
+}
+
+func (merkleTreeCodec) EncodeState(w io.Writer, v any) error {
+	tree, ok := v.(*smt.Tree)
+	if !ok {
+		return fmt.Errorf("expected a Merkle tree, got a %T", v)
+	}
+	return gob.NewEncoder(w).Encode(tree.OccupiedLeaves)
+}
+
+func (c merkleTreeCodec) DecodeState(r io.Reader) (any, error) {
+	var leaves []types.Bytes32
+	if err := gob.NewDecoder(r).Decode(&leaves); err != nil {
+		return nil, err
+	}
+	return c.ctx.VortexParams.RebuildMerkleTree(leaves), nil
+}
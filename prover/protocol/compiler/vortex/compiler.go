@@ -1,8 +1,6 @@
 package vortex
 
 import (
-	"math"
-
 	"github.com/consensys/linea-monorepo/prover/crypto"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
 	"github.com/consensys/linea-monorepo/prover/crypto/ringsis"
@@ -77,6 +75,9 @@ func Compile(blowUpFactor int, options ...VortexOp) func(*wizard.CompiledIOP) {
 		}
 
 		ctx.generateVortexParams()
+		for round := 0; round <= lastRound; round++ {
+			ctx.registerStateCodecs(round)
+		}
 		// Commit to precomputed in Vortex if IsCommitToPrecomputed is true
 		if ctx.IsCommitToPrecomputed() {
 			ctx.commitPrecomputeds()
@@ -101,6 +102,10 @@ type Ctx struct {
 	// Flag indicating that we want to replace SIS by MiMC
 	ReplaceSisByMimc bool
 
+	// Flag indicating that the Merkle trees are built using Blake3 instead
+	// of MiMC. See [WithBlake3MerkleTree].
+	UseBlake3MerkleTree bool
+
 	// The (verifiedly) unique polynomial query
 	Query                        query.UnivariateEval
 	PolynomialsTouchedByTheQuery map[ifaces.ColID]struct{}
@@ -114,8 +119,13 @@ type Ctx struct {
 	MaxCommittedRound  int
 	VortexParams       *vortex.Params
 	SisParams          *ringsis.Params
-	// Optional parameter
+	// Optional parameter, overrides the number of opened columns derived
+	// from the target soundness. See [ForceNumOpenedColumns].
 	numOpenedCol int
+	// targetSoundness and soundnessRegime drive the derivation of the number
+	// of opened columns. See [WithTargetSoundness] and [WithSoundnessRegime].
+	targetSoundness int
+	soundnessRegime SoundnessRegime
 
 	// By rounds commitments : if a round is dried we make an empty sublist.
 	// Inversely, for the `driedByRounds` which track the dried commitments.
@@ -175,6 +185,7 @@ func newCtx(comp *wizard.CompiledIOP, univQ query.UnivariateEval, blowUpFactor i
 		PolynomialsTouchedByTheQuery: map[ifaces.ColID]struct{}{},
 		ShadowCols:                   map[ifaces.ColID]struct{}{},
 		BlowUpFactor:                 blowUpFactor,
+		targetSoundness:              crypto.TargetSecurityLevel,
 		// TODO : allows tuning for multiple instances at once
 		SisParams: &ringsis.StdParams,
 		Items: struct {
@@ -421,9 +432,17 @@ func (ctx *Ctx) generateVortexParams() {
 	if ctx.ReplaceSisByMimc {
 		ctx.VortexParams.RemoveSis(mimc.NewMiMC)
 	}
+
+	if ctx.UseBlake3MerkleTree {
+		ctx.VortexParams.WithMerkleTreeHash(vortex.Blake3)
+	}
+
+	ctx.logSoundness()
 }
 
-// return the number of columns to open
+// NbColsToOpen returns the number of columns to open. Unless forced with
+// [ForceNumOpenedColumns], it is the minimal number of columns reaching the
+// target soundness rounded up to a power of two, see [DeriveNumOpenedColumns].
 func (ctx *Ctx) NbColsToOpen() int {
 
 	// opportunistic sanity-check : params should be set by now
@@ -437,24 +456,41 @@ func (ctx *Ctx) NbColsToOpen() int {
 		return ctx.numOpenedCol
 	}
 
-	if !utils.IsPowerOfTwo(ctx.BlowUpFactor) {
-		utils.Panic("expected a power of two but rho was %v", ctx.BlowUpFactor)
+	report, err := ctx.SoundnessReport()
+	if err != nil {
+		utils.Panic("could not derive the number of opened columns: %v", err)
 	}
 
-	logBlowUpFactor := int(math.Log2(float64(ctx.BlowUpFactor)))
+	return report.NumOpenedColumns
+}
 
-	if 1<<logBlowUpFactor != ctx.BlowUpFactor {
-		utils.Panic("rho %v, logRho %v", ctx.BlowUpFactor, logBlowUpFactor)
-	}
+// SoundnessReport returns the derivation of the number of columns to open for
+// the parameters of the context. Note that it ignores the value forced with
+// [ForceNumOpenedColumns].
+func (ctx *Ctx) SoundnessReport() (SoundnessReport, error) {
+	return DeriveNumOpenedColumns(SoundnessParams{
+		BlowUpFactor: ctx.BlowUpFactor,
+		NumCols:      ctx.NumCols,
+		NumRows:      ctx.CommittedRowsCount,
+		FieldBits:    field.Bits,
+		TargetBits:   ctx.targetSoundness,
+		Regime:       ctx.soundnessRegime,
+	})
+}
 
-	// the 2 factor comes from the factor that we rely on the Guruswami-Sudan
-	// list decoding regime and not the (alleged)-capacity decoding level.
-	nb := 2 * crypto.TargetSecurityLevel / logBlowUpFactor
-	if nb*logBlowUpFactor < crypto.TargetSecurityLevel {
-		nb++
-	}
+// logSoundness logs the derivation of the number of opened columns
+func (ctx *Ctx) logSoundness() {
+
+	report, err := ctx.SoundnessReport()
 
-	return nb
+	switch {
+	case ctx.numOpenedCol > 0:
+		logrus.Warnf("vortex: the number of opened columns is forced to %v; the derivation gives %v (err=%v)", ctx.numOpenedCol, report, err)
+	case err != nil:
+		logrus.Errorf("vortex: could not derive the number of opened columns: %v", err)
+	default:
+		logrus.Infof("vortex: %v", report)
+	}
 }
 
 // registers the vortex opening proof. As an input, we pass the last round
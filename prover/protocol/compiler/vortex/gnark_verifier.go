@@ -25,6 +25,10 @@ func (ctx *Ctx) GnarkVerify(api frontend.API, vr *wizard.WizardVerifierCircuit)
 		return
 	}
 
+	if ctx.UseBlake3MerkleTree {
+		utils.Panic("the Merkle trees are built with Blake3, which cannot be verified in a circuit")
+	}
+
 	// In non-Merkle mode, this is left as empty
 	roots := []frontend.Variable{}
 
@@ -7,14 +7,31 @@ import (
 // Option to be passed to vortex
 type VortexOp func(ctx *Ctx)
 
-// Overrides the number of opened columns (should
-// not be used in production)
+// Overrides the number of opened columns derived from the target soundness.
+// This is an escape hatch for experiments and should not be used in
+// production.
 func ForceNumOpenedColumns(nbCol int) VortexOp {
 	return func(ctx *Ctx) {
 		ctx.numOpenedCol = nbCol
 	}
 }
 
+// Sets the security level, in bits, from which the number of opened columns
+// is derived. The default is [crypto.TargetSecurityLevel].
+func WithTargetSoundness(bits int) VortexOp {
+	return func(ctx *Ctx) {
+		ctx.targetSoundness = bits
+	}
+}
+
+// Sets the decoding regime in which the number of opened columns is derived.
+// The default is [ListDecodingRegime].
+func WithSoundnessRegime(regime SoundnessRegime) VortexOp {
+	return func(ctx *Ctx) {
+		ctx.soundnessRegime = regime
+	}
+}
+
 // Allows passing a SIS instance
 func WithSISParams(params *ringsis.Params) VortexOp {
 	return func(ctx *Ctx) {
@@ -36,3 +53,12 @@ func ReplaceSisByMimc() VortexOp {
 		ctx.SisParams = nil
 	}
 }
+
+// Use Blake3 instead of MiMC to hash the internal nodes of the Merkle trees.
+// This makes committing cheaper but the resulting compilation can neither be
+// self-recursed nor verified in a gnark circuit.
+func WithBlake3MerkleTree() VortexOp {
+	return func(ctx *Ctx) {
+		ctx.UseBlake3MerkleTree = true
+	}
+}
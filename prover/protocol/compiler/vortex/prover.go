@@ -28,12 +28,12 @@ func (ctx *Ctx) AssignColumn(round int) func(*wizard.ProverRuntime) {
 
 		// Call Vortex in Merkle mode
 		committedMatrix, tree, sisDigest := ctx.VortexParams.CommitMerkle(pols)
-		pr.State.InsertNew(ctx.VortexProverStateName(round), committedMatrix)
-		pr.State.InsertNew(ctx.MerkleTreeName(round), tree)
+		pr.InsertState(ctx.VortexProverStateName(round), committedMatrix)
+		pr.InsertState(ctx.MerkleTreeName(round), tree)
 
 		// Only to be read by the self-recursion compiler.
 		if ctx.IsSelfrecursed {
-			pr.State.InsertNew(string(ctx.CommitmentName(round)), sisDigest)
+			pr.InsertState(string(ctx.CommitmentName(round)), sisDigest)
 		}
 
 		// And assign the 1-sized column to contain the root
@@ -96,14 +96,14 @@ func (ctx *Ctx) OpenSelectedColumns(pr *wizard.ProverRuntime) {
 		}
 
 		// Fetch it from the state
-		committedMatrix := pr.State.MustGet(ctx.VortexProverStateName(round)).(vortex.EncodedMatrix)
+		committedMatrix := pr.GetState(ctx.VortexProverStateName(round)).(vortex.EncodedMatrix)
 		// and delete it because it won't be needed anymore and its very heavy
-		pr.State.Del(ctx.VortexProverStateName(round))
+		pr.DelState(ctx.VortexProverStateName(round))
 		// Fetch it from the state
 		committedMatrices = append(committedMatrices, committedMatrix)
 
 		// Also fetches the trees from the prover state
-		tree := pr.State.MustGet(ctx.MerkleTreeName(round)).(*smt.Tree)
+		tree := pr.GetState(ctx.MerkleTreeName(round)).(*smt.Tree)
 		trees = append(trees, tree)
 	}
 
@@ -149,7 +149,7 @@ func (ctx *Ctx) getPols(run *wizard.ProverRuntime, round int) (pols []smartvecto
 	names := ctx.CommitmentsByRounds.MustGet(round)
 	pols = make([]smartvectors.SmartVector, len(names))
 	for i := range names {
-		pols[i] = run.Columns.MustGet(names[i])
+		pols[i] = run.GetColumn(names[i])
 	}
 	return pols
 }
@@ -0,0 +1,145 @@
+package vortex
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// SoundnessRegime indicates the decoding regime in which the proximity test
+// of Vortex is analyzed. It determines the probability that a single opened
+// column fails to catch a cheating prover.
+type SoundnessRegime int
+
+const (
+	// ListDecodingRegime analyzes the proximity test up to the Johnson bound,
+	// the distance being 1 - sqrt(rho). Each opened column brings
+	// log2(1/rho)/2 bits of security. This is the default.
+	ListDecodingRegime SoundnessRegime = iota
+	// UniqueDecodingRegime analyzes the proximity test up to the unique
+	// decoding radius (1 - rho)/2. Each opened column brings
+	// log2(2/(1+rho)) bits of security: it is more conservative and requires
+	// opening more columns.
+	UniqueDecodingRegime
+)
+
+func (r SoundnessRegime) String() string {
+	switch r {
+	case ListDecodingRegime:
+		return "list-decoding"
+	case UniqueDecodingRegime:
+		return "unique-decoding"
+	default:
+		return fmt.Sprintf("SoundnessRegime(%d)", int(r))
+	}
+}
+
+// SoundnessParams collects the parameters of a Vortex instance that drive the
+// soundness of its proximity test.
+type SoundnessParams struct {
+	// BlowUpFactor is the inverse rate of the Reed-Solomon code. It must be a
+	// power of two larger than 1.
+	BlowUpFactor int
+	// NumCols is the number of columns of the committed matrix, before
+	// encoding.
+	NumCols int
+	// NumRows is the number of committed rows, over all the rounds.
+	NumRows int
+	// FieldBits is the size of the field in bits.
+	FieldBits int
+	// TargetBits is the targeted security level in bits.
+	TargetBits int
+	// Regime is the decoding regime of the analysis.
+	Regime SoundnessRegime
+}
+
+// SoundnessReport details the derivation of the number of columns to open by
+// [DeriveNumOpenedColumns].
+type SoundnessReport struct {
+	SoundnessParams
+	// BitsPerColumn is the security brought by each opened column
+	BitsPerColumn float64
+	// MinNumOpenedColumns is the smallest number of opened columns such that
+	// the proximity test reaches the target.
+	MinNumOpenedColumns int
+	// NumOpenedColumns is MinNumOpenedColumns rounded up to the next power of
+	// two, as required by the self-recursion. It is the number of columns
+	// actually opened by Vortex.
+	NumOpenedColumns int
+	// ProximityBits is the security of the proximity test with
+	// NumOpenedColumns opened columns.
+	ProximityBits float64
+	// LinCombBits is the security of the random linear combination of the
+	// rows, i.e. the probability that the combination of rows that are far
+	// from the code is close to the code. It only depends on the field size
+	// and on the dimensions of the matrix.
+	LinCombBits float64
+}
+
+// SecurityBits returns the overall security level of the Vortex instance; the
+// one of its weakest step.
+func (r SoundnessReport) SecurityBits() float64 {
+	return math.Min(r.ProximityBits, r.LinCombBits)
+}
+
+func (r SoundnessReport) String() string {
+	return fmt.Sprintf(
+		"rho=1/%v, %v regime: %.3f bits per opened column, %v opened columns (at least %v) for a target of %v bits (proximity=%.1f bits, linear combination=%.1f bits over a %v-bit field, %v rows x %v columns)",
+		r.BlowUpFactor, r.Regime, r.BitsPerColumn, r.NumOpenedColumns, r.MinNumOpenedColumns, r.TargetBits,
+		r.ProximityBits, r.LinCombBits, r.FieldBits, r.NumRows, r.NumCols,
+	)
+}
+
+// DeriveNumOpenedColumns returns the number of columns Vortex must open to
+// reach the target security level, along with the derivation. The minimal
+// number of columns is rounded up to the next power of two because the
+// self-recursion requires it; the rounding only adds security. It
+// returns an error if the target cannot be reached by opening more columns
+// because the field is too small w.r.t. the dimensions of the matrix.
+//
+// The analysis is the one of the proximity test of Ligero-like schemes: the
+// random linear combination of the rows fails with probability roughly
+// NumRows * n / |F| in the unique decoding regime and NumRows * n^2 / |F| in
+// the list decoding regime, where n is the length of the codewords; and each
+// opened column independently catches a cheating prover with probability
+// delta, the decoding radius.
+func DeriveNumOpenedColumns(p SoundnessParams) (SoundnessReport, error) {
+
+	if !utils.IsPowerOfTwo(p.BlowUpFactor) || p.BlowUpFactor < 2 {
+		return SoundnessReport{}, fmt.Errorf("the blow-up factor must be a power of two larger than 1, got %v", p.BlowUpFactor)
+	}
+
+	if p.TargetBits <= 0 {
+		return SoundnessReport{}, fmt.Errorf("the target security level must be positive, got %v", p.TargetBits)
+	}
+
+	var (
+		res     = SoundnessReport{SoundnessParams: p}
+		rho     = 1 / float64(p.BlowUpFactor)
+		logN    = math.Log2(float64(max(p.NumCols*p.BlowUpFactor, 1)))
+		logRows = math.Log2(float64(max(p.NumRows, 1)))
+	)
+
+	switch p.Regime {
+	case ListDecodingRegime:
+		res.BitsPerColumn = -math.Log2(rho) / 2
+		res.LinCombBits = float64(p.FieldBits) - logRows - 2*logN
+	case UniqueDecodingRegime:
+		res.BitsPerColumn = -math.Log2((1 + rho) / 2)
+		res.LinCombBits = float64(p.FieldBits) - logRows - logN
+	default:
+		return SoundnessReport{}, fmt.Errorf("unknown soundness regime %v", p.Regime)
+	}
+
+	if res.LinCombBits < float64(p.TargetBits) {
+		return res, fmt.Errorf("the field is too small to reach %v bits of security: %v", p.TargetBits, res)
+	}
+
+	// The epsilon absorbs the rounding errors when the ratio is an integer
+	res.MinNumOpenedColumns = int(math.Ceil(float64(p.TargetBits)/res.BitsPerColumn - 1e-9))
+	res.NumOpenedColumns = utils.NextPowerOfTwo(res.MinNumOpenedColumns)
+	res.ProximityBits = float64(res.NumOpenedColumns) * res.BitsPerColumn
+
+	return res, nil
+}
@@ -0,0 +1,56 @@
+package vortex_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveNumOpenedColumns(t *testing.T) {
+
+	testCases := []struct {
+		blowUp      int
+		regime      vortex.SoundnessRegime
+		expectedMin int
+		expected    int
+	}{
+		{blowUp: 2, regime: vortex.ListDecodingRegime, expectedMin: 256, expected: 256},
+		{blowUp: 4, regime: vortex.ListDecodingRegime, expectedMin: 128, expected: 128},
+		{blowUp: 8, regime: vortex.ListDecodingRegime, expectedMin: 86, expected: 128},
+		{blowUp: 16, regime: vortex.ListDecodingRegime, expectedMin: 64, expected: 64},
+		{blowUp: 2, regime: vortex.UniqueDecodingRegime, expectedMin: 309, expected: 512},
+		{blowUp: 8, regime: vortex.UniqueDecodingRegime, expectedMin: 155, expected: 256},
+	}
+
+	for _, tc := range testCases {
+		report, err := vortex.DeriveNumOpenedColumns(vortex.SoundnessParams{
+			BlowUpFactor: tc.blowUp,
+			NumCols:      1 << 19,
+			NumRows:      1 << 12,
+			FieldBits:    field.Bits,
+			TargetBits:   128,
+			Regime:       tc.regime,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedMin, report.MinNumOpenedColumns, "blow-up=%v regime=%v", tc.blowUp, tc.regime)
+		assert.Equal(t, tc.expected, report.NumOpenedColumns, "blow-up=%v regime=%v", tc.blowUp, tc.regime)
+		assert.GreaterOrEqual(t, report.SecurityBits(), 128.0)
+		assert.Less(t, float64(report.MinNumOpenedColumns-1)*report.BitsPerColumn, 128.0, "the count must be minimal")
+	}
+
+	t.Run("field-too-small", func(t *testing.T) {
+		_, err := vortex.DeriveNumOpenedColumns(vortex.SoundnessParams{
+			BlowUpFactor: 2, NumCols: 1 << 19, NumRows: 1 << 12, FieldBits: 64, TargetBits: 128,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid-blow-up", func(t *testing.T) {
+		_, err := vortex.DeriveNumOpenedColumns(vortex.SoundnessParams{BlowUpFactor: 3, TargetBits: 128})
+		assert.Error(t, err)
+	})
+}
@@ -194,6 +194,40 @@ func TestVortexSingleRoundMerkleNoSis(t *testing.T) {
 	require.NoErrorf(t, valid, "the proof did not pass")
 }
 
+func TestVortexSingleRoundMerkleBlake3(t *testing.T) {
+
+	polSize := 1 << 4
+	nPols := 16
+	rows := make([]ifaces.Column, nPols)
+
+	define := func(b *wizard.Builder) {
+		for i := range rows {
+			rows[i] = b.RegisterCommit(ifaces.ColIDf("P_%v", i), polSize)
+		}
+		b.UnivariateEval("EVAL", rows...)
+	}
+
+	prove := func(pr *wizard.ProverRuntime) {
+		ys := make([]field.Element, len(rows))
+		x := field.NewElement(57) // the evaluation point
+
+		// assign the rows with random polynomials and collect the ys
+		for i, row := range rows {
+			p := smartvectors.Rand(polSize)
+			ys[i] = smartvectors.Interpolate(p, x)
+			pr.AssignColumn(row.GetColID(), p)
+		}
+
+		pr.AssignUnivariate("EVAL", x, ys...)
+	}
+
+	compiled := wizard.Compile(define, vortex.Compile(4, vortex.ReplaceSisByMimc(), vortex.WithBlake3MerkleTree()))
+	proof := wizard.Prove(compiled, prove)
+	valid := wizard.Verify(compiled, proof)
+
+	require.NoErrorf(t, valid, "the proof did not pass")
+}
+
 func TestVortexMultiRoundMerkleNoSis(t *testing.T) {
 
 	polSize := 1 << 4
@@ -2,7 +2,6 @@ package functionals
 
 import (
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
-	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
 	"github.com/consensys/linea-monorepo/prover/protocol/coin"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
@@ -67,13 +66,7 @@ func CoeffEval(comp *wizard.CompiledIOP, name string, x coin.Info, pol ifaces.Co
 		p := pol.GetColAssignment(assi)
 
 		// Now needs to evaluate the Horner poly
-		h := make([]field.Element, length)
-		h[length-1] = p.Get(length - 1)
-
-		for i := length - 2; i >= 0; i-- {
-			pi := p.Get(i)
-			h[i].Mul(&h[i+1], &x).Add(&h[i], &pi)
-		}
+		h := smartvectors.Horner(p, x)
 
 		assi.AssignColumn(ifaces.ColIDf("%v_%v", name, EVAL_COEFF_POLY), smartvectors.NewRegular(h))
 		assi.AssignLocalPoint(ifaces.QueryIDf("%v_%v", name, EVAL_COEFF_FIXED_POINT_BEGIN), h[0])
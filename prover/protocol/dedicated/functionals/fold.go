@@ -5,7 +5,6 @@ import (
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
-	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
 	"github.com/consensys/linea-monorepo/prover/protocol/coin"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
@@ -31,11 +30,11 @@ func Fold(comp *wizard.CompiledIOP, h ifaces.Column, x ifaces.Accessor, innerDeg
 		h := h.GetColAssignment(assi) // overshadows the handle
 		x := x.GetVal(assi)           // overshadows the accessor
 
-		foldedVal := make([]field.Element, foldedSize)
-		for i := range foldedVal {
-			subH := h.SubVector(i*innerDegree, (i+1)*innerDegree)
-			foldedVal[i] = smartvectors.EvalCoeff(subH, x)
+		subHs := make([]smartvectors.SmartVector, foldedSize)
+		for i := range subHs {
+			subHs[i] = h.SubVector(i*innerDegree, (i+1)*innerDegree)
 		}
+		foldedVal := smartvectors.BatchEvalCoeff(subHs, x)
 
 		assi.AssignColumn(foldedName, smartvectors.NewRegular(foldedVal))
 	})
@@ -81,9 +81,16 @@ func (ci *CircuitAlignmentInput) NbInstances() int {
 	return ci.NbCircuitInstances
 }
 
-// prepareWitnesses prepares the witnesses for every circuit instance. It is
-// called inside the Once so that we do not prepare the witnesses multiple
-// times. Safe to call multiple times, it is idepotent after first call.
+// prepareWitnesses prepares the witnesses of the circuit instances that are
+// effectively used. It is called inside the Once so that we do not prepare
+// the witnesses multiple times. Safe to call multiple times, it is idepotent
+// after first call.
+//
+// The used instances are the ones holding the inputs actually provided, the
+// last one being completed with the InputFiller. The other instances are
+// deactivated and assigned to zero by the prover, so their witnesses are not
+// prepared either. Note that the circuit is still compiled for
+// NbCircuitInstances instances.
 func (ci *CircuitAlignmentInput) prepareWitnesses(run *wizard.ProverRuntime) {
 	ci.witnessesOnce.Do(func() {
 		if ci.InputFiller == nil {
@@ -100,15 +107,18 @@ func (ci *CircuitAlignmentInput) prepareWitnesses(run *wizard.ProverRuntime) {
 				totalInputs++
 			}
 		}
-		// prepare witness for every circuit instance NB! keep in mind that we only
-		// have public inputs. So the public and private inputs match. Due to
-		// interface definition we have to return both but in practice have only a
-		// single one.
+		// this is the number of instances whose activator is set by the
+		// alignment, the last one is completed using the InputFiller.
+		ci.numEffWitnesses = utils.DivCeil(totalInputs, ci.nbPublicInputs)
+		// prepare witness for every effective circuit instance NB! keep in mind
+		// that we only have public inputs. So the public and private inputs
+		// match. Due to interface definition we have to return both but in
+		// practice have only a single one.
 		ci.witnesses = make([]witness.Witness, ci.NbCircuitInstances)
-		witnessFillers := make([]chan any, ci.NbCircuitInstances)
+		witnessFillers := make([]chan any, ci.numEffWitnesses)
 		var err error
 		wg, ctx := errgroup.WithContext(context.Background())
-		for i := range ci.witnesses {
+		for i := range witnessFillers {
 			ii := i // capture the value. Pre Go 1.22
 			ci.witnesses[i], err = witness.New(ecc.BLS12_377.ScalarField())
 			if err != nil {
@@ -122,7 +132,7 @@ func (ci *CircuitAlignmentInput) prepareWitnesses(run *wizard.ProverRuntime) {
 		}
 		go func() {
 			var filled int
-			for j := 0; j < dataCol.Len(); j++ {
+			for j := 0; j < dataCol.Len() && filled < totalInputs; j++ {
 				mask := maskCol.Get(j)
 				if mask.IsZero() {
 					continue
@@ -139,11 +149,7 @@ func (ci *CircuitAlignmentInput) prepareWitnesses(run *wizard.ProverRuntime) {
 				}
 			}
 
-			if filled > 0 {
-				ci.numEffWitnesses = utils.DivCeil(filled-1, ci.nbPublicInputs)
-			}
-
-			for filled < ci.nbPublicInputs*ci.NbCircuitInstances {
+			for filled < ci.nbPublicInputs*ci.numEffWitnesses {
 				select {
 				case <-ctx.Done():
 					return
@@ -168,9 +174,14 @@ func (ci *CircuitAlignmentInput) prepareWitnesses(run *wizard.ProverRuntime) {
 func (ci *CircuitAlignmentInput) Assign(run *wizard.ProverRuntime, i int) (private, public witness.Witness, err error) {
 	// done inside Once, so can always call without overhead
 	ci.prepareWitnesses(run)
+	if i >= ci.numEffWitnesses {
+		return nil, nil, fmt.Errorf("circuit instance %v is not used, only %v instances are", i, ci.numEffWitnesses)
+	}
 	return ci.witnesses[i], ci.witnesses[i], nil
 }
 
+// NumEffWitnesses returns the number of circuit instances that are used by the
+// assignment. Implements [WitnessAssigner].
 func (ci *CircuitAlignmentInput) NumEffWitnesses(run *wizard.ProverRuntime) int {
 	ci.prepareWitnesses(run)
 	return ci.numEffWitnesses
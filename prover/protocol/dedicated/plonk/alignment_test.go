@@ -1,13 +1,17 @@
 package plonk
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
 )
 
@@ -56,6 +60,62 @@ func TestAlignment(t *testing.T) {
 	t.Log("proof succeeded")
 }
 
+// TestAlignmentPartialInstances checks the number of circuit instances solved
+// when the inputs do not fill all of them, in particular when the number of
+// inputs is not a multiple of the number of public inputs of an instance.
+func TestAlignmentPartialInstances(t *testing.T) {
+
+	const (
+		size                   = 128
+		nbPublicInputsInstance = 6 * nbInputInstances
+	)
+
+	for _, totalInputs := range []int{0, 1, 5, 17, 18, 19, 25, 36} {
+		t.Run(fmt.Sprintf("total-inputs-%v", totalInputs), func(t *testing.T) {
+
+			var toAlign *CircuitAlignmentInput
+			var alignment *Alignment
+			var numEff int
+
+			cmp := wizard.Compile(func(build *wizard.Builder) {
+				toAlign = &CircuitAlignmentInput{
+					Name:               "ALIGNMENT_PARTIAL_TEST",
+					Circuit:            &DummyAlignmentCircuit{Instances: make([]DummyAlignmentCircuitInstance, nbInputInstances)},
+					DataToCircuit:      build.RegisterCommit("DATA", size),
+					DataToCircuitMask:  build.RegisterCommit("DATA_MASK", size),
+					NbCircuitInstances: nbCircuitInstances,
+					InputFiller:        func(circuitInstance, inputIndex int) field.Element { return field.NewElement(uint64(inputIndex + 1)) },
+				}
+				alignment = DefineAlignment(build.CompiledIOP, toAlign)
+			}, dummy.Compile)
+
+			proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+				// the inputs are interleaved with unmasked rows and each
+				// instance expects the sequence 1, 2, ..., 18.
+				data := make([]field.Element, size)
+				mask := make([]field.Element, size)
+				for k := 0; k < totalInputs; k++ {
+					data[2*k] = field.NewElement(uint64(k%nbPublicInputsInstance + 1))
+					mask[2*k] = field.One()
+					data[2*k+1] = field.NewElement(1000)
+				}
+				run.AssignColumn(ifaces.ColID("DATA"), smartvectors.NewRegular(data))
+				run.AssignColumn(ifaces.ColID("DATA_MASK"), smartvectors.NewRegular(mask))
+				alignment.Assign(run)
+				numEff = toAlign.NumEffWitnesses(run)
+			})
+
+			if err := wizard.Verify(cmp, proof); err != nil {
+				t.Fatal("proof failed", err)
+			}
+
+			if expected := utils.DivCeil(totalInputs, nbPublicInputsInstance); numEff != expected {
+				t.Fatalf("%v instances are solved, expected %v", numEff, expected)
+			}
+		})
+	}
+}
+
 // DummyAlignmentCircuit is a dummy circuit for testing alignment. It doesn't do
 // anything except check that the inputs are in order.
 type DummyAlignmentCircuit struct {
@@ -0,0 +1,85 @@
+package plonk_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/selfrecursion"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckpointVortexPlonk resumes a proof of a Plonk circuit with a
+// commitment, compiled with Vortex and self-recursed, from each of its
+// checkpoints. The checkpoints hold the Vortex state of the prover and the
+// ones of the rounds where the Plonk solvers are running are skipped.
+func TestCheckpointVortexPlonk(t *testing.T) {
+
+	var (
+		circuit  = &TestCommitCircuit{}
+		assigner = func() frontend.Circuit { return &TestCommitCircuit{X: 0, Y: 5} }
+		pa       plonk.PlonkInWizardProverAction
+	)
+
+	comp := wizard.Compile(
+		func(build *wizard.Builder) {
+			ctx := plonk.PlonkCheck(build.CompiledIOP, "PLONK", 0, circuit, 2)
+			pa = ctx.GetPlonkProverAction()
+		},
+		compiler.Arcane(1<<4, 1<<10),
+		vortex.Compile(2, vortex.ForceNumOpenedColumns(4)),
+		selfrecursion.SelfRecurse,
+		dummy.Compile,
+	)
+
+	mainCalls := 0
+	prover := func(run *wizard.ProverRuntime) {
+		mainCalls++
+		pa.Run(run, plonk.NewSafeCircuitAssigner(circuit, assigner, assigner))
+	}
+
+	var encoded [][]byte
+	reference := wizard.Prove(comp, prover, wizard.WithCheckpoints(func(cp *wizard.Checkpoint) error {
+		buf := &bytes.Buffer{}
+		if err := cp.Encode(buf); err != nil {
+			return err
+		}
+		encoded = append(encoded, buf.Bytes())
+		return nil
+	}))
+	require.NoError(t, wizard.Verify(comp, reference))
+	require.NotEmpty(t, encoded)
+
+	var (
+		chain      []*wizard.Checkpoint
+		withVortex bool
+	)
+
+	for _, b := range encoded {
+
+		cp, err := wizard.DecodeCheckpoint(comp, bytes.NewReader(b))
+		require.NoError(t, err)
+		require.NotZero(t, cp.Round, "the solvers are running at the end of the first round")
+		chain = append(chain, cp)
+		withVortex = withVortex || len(cp.State) > 0
+
+		resumed := wizard.Prove(comp, prover, wizard.ResumeFrom(chain))
+		require.NoError(t, wizard.Verify(comp, resumed), "round %v", cp.Round)
+
+		for _, name := range reference.Messages.ListAllKeys() {
+			require.Equal(t,
+				reference.Messages.MustGet(name).IntoRegVecSaveAlloc(),
+				resumed.Messages.MustGet(name).IntoRegVecSaveAlloc(),
+				"round %v, message %v", cp.Round, name,
+			)
+		}
+	}
+
+	require.True(t, withVortex, "no checkpoint holds the state of Vortex")
+	require.Equal(t, 1, mainCalls)
+}
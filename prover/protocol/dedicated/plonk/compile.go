@@ -66,6 +66,12 @@ func PlonkCheck(
 
 	if ctx.HasCommitment() {
 		comp.RegisterProverAction(round+1, lroCommitProverAction{compilationCtx: ctx, proverStateLock: &sync.Mutex{}})
+
+		// The channels to the solvers running in the background between the
+		// two rounds cannot be checkpointed.
+		for i := 0; i < ctx.maxNbInstances; i++ {
+			comp.RegisterTransientState(ctx.Sprintf("SOLSYNC_%v", i))
+		}
 	}
 
 	comp.RegisterVerifierAction(round, checkingActivators(ctx.Columns.Activators))
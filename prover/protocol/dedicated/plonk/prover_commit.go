@@ -76,7 +76,7 @@ func (pa initialBBSProverAction) Run(run *wizard.ProverRuntime, wa WitnessAssign
 			// Store the channels in the runtime so that we can
 			// access them in later rounds
 			pa.proverStateLock.Lock()
-			run.State.InsertNew(ctx.Sprintf("SOLSYNC_%v", i), solSync)
+			run.InsertState(ctx.Sprintf("SOLSYNC_%v", i), solSync)
 			pa.proverStateLock.Unlock()
 
 			// Create the witness assignment
@@ -124,8 +124,8 @@ func (pa lroCommitProverAction) Run(run *wizard.ProverRuntime) {
 			// Retrieve the solsync. Not finding it means the instance is not
 			// used.
 			pa.proverStateLock.Lock()
-			solsync_, foundSolSync := run.State.TryGet(ctx.Sprintf("SOLSYNC_%v", i))
-			run.State.TryDel(ctx.Sprintf("SOLSYNC_%v", i))
+			solsync_, foundSolSync := run.TryGetState(ctx.Sprintf("SOLSYNC_%v", i))
+			run.DelState(ctx.Sprintf("SOLSYNC_%v", i))
 			pa.proverStateLock.Unlock()
 
 			if !foundSolSync {
@@ -212,7 +212,10 @@ func InsertProjection(
 	pa.HornerB0 = comp.InsertLocalOpening(round, ifaces.QueryIDf("%v_HORNER_B0", queryName), pa.HornerB)
 
 	comp.RegisterProverAction(round, pa)
-	comp.RegisterVerifierAction(round, projectionVerifierAction{HornerA0: pa.HornerA0, HornerB0: pa.HornerB0, Name: queryName})
+	// The verifier action is named after the query so that the order in
+	// which the projections are checked does not depend on the order in
+	// which the modules insert them.
+	comp.RegisterVerifierActionOrdered(round, string(queryName), projectionVerifierAction{HornerA0: pa.HornerA0, HornerB0: pa.HornerB0, Name: queryName})
 }
 
 // Run implements the [wizard.ProverAction] interface.
@@ -27,3 +27,26 @@ type Accessor interface {
 	// Deprecated: use the new [symbolic] API, this function won't be needed anymore. We keep it since most uses of the symbolic package within this repository uses the old API, but this will be removed in the future.
 	AsVariable() *symbolic.Expression
 }
+
+// AccessorCache is optionally implemented by the [Runtime] memoizing the
+// values of the accessors evaluated against it. The values are keyed by the
+// [symbolic.Expression.ESHash] of the expression they evaluate so that two
+// accessors evaluating the same expression share their value. It is up to
+// the runtime to drop the memoized values when its content changes.
+type AccessorCache interface {
+	// GetMemoizedAccessor returns the memoized value for the key, if any
+	GetMemoizedAccessor(key field.Element) (field.Element, bool)
+	// MemoizeAccessor memoizes a value for the key
+	MemoizeAccessor(key field.Element, val field.Element)
+}
+
+// GnarkAccessorCache is as [AccessorCache] for the [GnarkRuntime]. Reusing a
+// memoized value saves the constraints of its evaluation.
+type GnarkAccessorCache interface {
+	// GetMemoizedAccessor is as [AccessorCache.GetMemoizedAccessor] but in a
+	// gnark circuit
+	GetMemoizedAccessor(key field.Element) (frontend.Variable, bool)
+	// MemoizeAccessor is as [AccessorCache.MemoizeAccessor] but in a gnark
+	// circuit
+	MemoizeAccessor(key field.Element, val frontend.Variable)
+}
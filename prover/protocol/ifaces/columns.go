@@ -10,6 +10,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/symbolic"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/ids"
 )
 
 // ColID is a [Column]'s unique string identifier.
@@ -26,6 +27,15 @@ type ColID string
 // corresponds to an assignment to a column.
 type ColAssignment = smartvectors.SmartVector
 
+// NewColID returns the column ID or an error if it is empty, has leading or
+// trailing spaces or contains non-printable characters, see [ids.Validate].
+func NewColID(s string) (ColID, error) {
+	if err := ids.Validate(s); err != nil {
+		return "", fmt.Errorf("invalid column ID: %w", err)
+	}
+	return ColID(s), nil
+}
+
 // ColIDf is a convenience function to format ColIDs that provides an interface
 // similar to [fmt.Printf]. It allows to more succinctly define the name of
 // a column.
@@ -6,6 +6,7 @@ import (
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover/utils/ids"
 )
 
 // QueryID denotes an unique identifier ID. It uniquely
@@ -14,6 +15,15 @@ import (
 // empty string should not be used as an ID.
 type QueryID string
 
+// NewQueryID returns the query ID or an error if it is empty, has leading or
+// trailing spaces or contains non-printable characters, see [ids.Validate].
+func NewQueryID(s string) (QueryID, error) {
+	if err := ids.Validate(s); err != nil {
+		return "", fmt.Errorf("invalid query ID: %w", err)
+	}
+	return QueryID(s), nil
+}
+
 // QueryIDf formats a [QueryID] from a formatting string and arguments. It is
 // a convenience shorthand for `QueryID(fmt.Sprintf(s, args...))`
 func QueryIDf(s string, args ...interface{}) QueryID {
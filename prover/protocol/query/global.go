@@ -170,24 +170,8 @@ func (cs GlobalConstraint) Check(run ifaces.Runtime) error {
 		resx := res.Get(i)
 		// The proper test
 		if !resx.IsZero() {
-			s := ""
-
-			for j := utils.Max(start, i-15); j < utils.Min(stop, i+15); j++ {
-				debugMap := make(map[string]string)
-				for k, metadataInterface := range metadatas {
-					inpx := evalInputs[k].Get(j)
-					debugMap[string(metadataInterface.String())] = fmt.Sprintf("%v", inpx.String())
-				}
-				if j == i {
-					s += "\n"
-				}
-				s += fmt.Sprintf("%v: %v\n", j, debugMap)
-				if j == i {
-					s += "\n"
-				}
-			}
-
-			return fmt.Errorf("the global constraint check failed at row %v \n\tinput details : %v \n\tres: %v\n\t", i, s, resx.String())
+			return fmt.Errorf("the global constraint %v check failed at row %v, res: %v\n%v",
+				cs.ID, i, resx.String(), failureWindow(metadatas, evalInputs, res, i, start, stop))
 		}
 	}
 
@@ -198,6 +182,47 @@ func (cs GlobalConstraint) Check(run ifaces.Runtime) error {
 	return nil
 }
 
+// FailureDumpFormat is the format of the field elements in the tables dumped
+// when a constraint check fails. It can be changed, e.g. to hexadecimal, to
+// debug constraints over bytes or limbs.
+var FailureDumpFormat = field.Format{Signed: true, MaxDigits: 16}
+
+// failureWindow renders the values of the inputs of a constraint and of its
+// result around the failing row, within [start, stop).
+func failureWindow(metadatas []symbolic.Metadata, inputs []sv.SmartVector, res sv.SmartVector, row, start, stop int) string {
+
+	var (
+		from  = max(start, row-15)
+		to    = min(stop, row+15)
+		table = field.Table{
+			Format:   FailureDumpFormat,
+			Headers:  make([]string, 0, len(metadatas)+1),
+			Columns:  make([][]field.Element, 0, len(metadatas)+1),
+			FirstRow: from,
+			Marked:   []int{row},
+		}
+	)
+
+	for k := range metadatas {
+		table.Headers = append(table.Headers, metadatas[k].String())
+		table.Columns = append(table.Columns, windowOf(inputs[k], from, to))
+	}
+
+	table.Headers = append(table.Headers, "res")
+	table.Columns = append(table.Columns, windowOf(res, from, to))
+
+	return table.String()
+}
+
+// windowOf returns the entries of v in [from, to)
+func windowOf(v sv.SmartVector, from, to int) []field.Element {
+	res := make([]field.Element, to-from)
+	for i := range res {
+		res[i] = v.Get(from + i)
+	}
+	return res
+}
+
 // validatedDomainSize scans the expression of the global constraints and more
 // specifically its inputs and looks for the followings:
 //   - the expression must use at least one [ifaces.Column] as input variable
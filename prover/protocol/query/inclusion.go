@@ -40,6 +40,21 @@ type Inclusion struct {
 	// The slices is indexed per number of fragment, in the non-fragmented case,
 	// consider there is only a single segment.
 	IncludingFilter []ifaces.Column
+	// IncludedSelectors optionally lists (allegedly) binary-assigned columns
+	// gating the rows of the “included" table in addition to IncludedFilter.
+	// A row is subjected to the constraint only if all the selectors and the
+	// filter are equal to one. The product is never committed: the lookup
+	// compiler directly uses it in the log-derivative sums. This avoids
+	// committing a masked copy of the selectors for every conditional lookup.
+	IncludedSelectors []ifaces.Column
+	// IncludedWeight optionally stores the multiplicity with which each row
+	// of the “included" table is looked up: the row i counts for
+	// IncludedWeight[i] rows of the table instead of one. A row of weight
+	// zero is disregarded. This is useful for counting-style arguments, e.g.
+	// when the included table lists the distinct values of a column along
+	// with their number of occurences. The weight is not required to be
+	// binary, contrary to the filters.
+	IncludedWeight ifaces.Column
 }
 
 // NewInclusion constructs an inclusion. Will panic if it is mal-formed
@@ -120,6 +135,68 @@ func NewInclusion(
 	return Inclusion{Included: included, Including: including, ID: id, IncludedFilter: includedFilter, IncludingFilter: includingFilter}
 }
 
+// WithIncludedSelectors returns a copy of the query where the included table
+// is additionally gated by the provided selectors. See
+// [Inclusion.IncludedSelectors]. Will panic if a selector does not have the
+// size of the included table.
+func (r Inclusion) WithIncludedSelectors(selectors ...ifaces.Column) Inclusion {
+
+	for _, sel := range selectors {
+		sel.MustExists()
+		if sel.Size() != r.Included[0].Size() {
+			utils.Panic(
+				"the selector %v (size=%v) does not have the same size as the included table (size=%v)",
+				sel.GetColID(), sel.Size(), r.Included[0].Size(),
+			)
+		}
+	}
+
+	r.IncludedSelectors = append(append([]ifaces.Column{}, r.IncludedSelectors...), selectors...)
+	return r
+}
+
+// WithIncludedWeight returns a copy of the query where the rows of the
+// included table are weighted by the provided column. See
+// [Inclusion.IncludedWeight]. Will panic if the weight does not have the size
+// of the included table or if the query is already weighted.
+func (r Inclusion) WithIncludedWeight(weight ifaces.Column) Inclusion {
+
+	weight.MustExists()
+
+	if r.IsWeighted() {
+		utils.Panic("the inclusion %v is already weighted by %v", r.ID, r.IncludedWeight.GetColID())
+	}
+
+	if weight.Size() != r.Included[0].Size() {
+		utils.Panic(
+			"the weight %v (size=%v) does not have the same size as the included table (size=%v)",
+			weight.GetColID(), weight.Size(), r.Included[0].Size(),
+		)
+	}
+
+	r.IncludedWeight = weight
+	return r
+}
+
+// IsWeighted returns true if the rows of the included table are weighted
+func (r Inclusion) IsWeighted() bool {
+	return r.IncludedWeight != nil
+}
+
+// IncludedFilterColumns returns the columns whose product filters the
+// included table: the IncludedFilter if any, followed by the selectors. It
+// returns nil if the included table is not filtered.
+func (r Inclusion) IncludedFilterColumns() []ifaces.Column {
+	if !r.IsFilteredOnIncluded() {
+		return nil
+	}
+	res := make([]ifaces.Column, 0, len(r.IncludedSelectors)+1)
+	if r.IncludedFilter != nil {
+		res = append(res, r.IncludedFilter)
+	}
+	return append(res, r.IncludedSelectors...)
+}
+
 // Name implements the [ifaces.Query] interface
 func (r Inclusion) Name() ifaces.QueryID {
 	return r.ID
@@ -134,7 +211,7 @@ func (r Inclusion) IsFilteredOnIncluding() bool {
 // IsFilteredOnIncluded returns true if the table is filtered on the including
 // side of the table
 func (r Inclusion) IsFilteredOnIncluded() bool {
-	return r.IncludedFilter != nil
+	return r.IncludedFilter != nil || len(r.IncludedSelectors) > 0
 }
 
 // Check implements the [ifaces.Query] interface
@@ -160,8 +237,13 @@ func (r Inclusion) Check(run ifaces.Runtime) error {
 	var (
 		filterIncluding []smartvectors.SmartVector
 		filterIncluded  smartvectors.SmartVector
+		weight          smartvectors.SmartVector
 	)
 
+	if r.IsWeighted() {
+		weight = r.IncludedWeight.GetColAssignment(run)
+	}
+
 	if r.IsFilteredOnIncluding() {
 		filterIncluding = make([]smartvectors.SmartVector, len(r.IncludingFilter))
 		for frag := range r.IncludingFilter {
@@ -170,7 +252,12 @@ func (r Inclusion) Check(run ifaces.Runtime) error {
 	}
 
 	if r.IsFilteredOnIncluded() {
-		filterIncluded = r.IncludedFilter.GetColAssignment(run)
+		filters := r.IncludedFilterColumns()
+		filterAssignments := make([]smartvectors.SmartVector, len(filters))
+		for i := range filters {
+			filterAssignments[i] = filters[i].GetColAssignment(run)
+		}
+		filterIncluded = smartvectors.Mul(filterAssignments...)
 	}
 
 	/*
@@ -205,6 +292,10 @@ func (r Inclusion) Check(run ifaces.Runtime) error {
 			continue
 		}
 
+		if r.IsWeighted() && weight.Get(row) == field.Zero() {
+			continue
+		}
+
 		rand := rowLinComb(alpha, row, included)
 		if _, ok := inclusionSet[rand]; !ok {
 			notFoundRow := []string{}
@@ -19,6 +19,9 @@ type rawCompiledIOP struct {
 	QueriesNoParams [][]json.RawMessage `json:"queriesNoParams"`
 	Coins           [][]json.RawMessage `json:"coins"`
 	DummyCompiled   bool                `json:"dummyCompiled"`
+	// ColumnAliases maps the former names of the renamed columns to their
+	// current name, see [column.Store.AddAlias].
+	ColumnAliases map[ifaces.ColID]ifaces.ColID `json:"columnAliases,omitempty"`
 }
 
 // SerializeCompiledIOP marshals a [wizard.CompiledIOP] object into JSON. This is
@@ -34,7 +37,7 @@ type rawCompiledIOP struct {
 //		}
 func SerializeCompiledIOP(comp *wizard.CompiledIOP) ([]byte, error) {
 
-	raw := &rawCompiledIOP{}
+	raw := &rawCompiledIOP{ColumnAliases: comp.Columns.Aliases()}
 	numRounds := comp.NumRounds()
 
 	for round := 0; round < numRounds; round++ {
@@ -142,6 +145,10 @@ func DeserializeCompiledIOP(data []byte) (*wizard.CompiledIOP, error) {
 		}
 	}
 
+	for alias, canonical := range raw.ColumnAliases {
+		comp.Columns.AddAlias(alias, canonical)
+	}
+
 	for round := 0; round < numRounds; round++ {
 
 		for _, rawQ := range raw.QueriesNoParams[round] {
@@ -43,3 +43,24 @@ func TestCompiled(t *testing.T) {
 	}
 
 }
+
+func TestCompiledColumnAliases(t *testing.T) {
+
+	comp := newEmptyCompiledIOP()
+	comp.RenameColumns(map[ifaces.ColID]ifaces.ColID{"foo": "foo_renamed"})
+	_ = comp.InsertCommit(0, "foo", 16)
+
+	encoded, err := SerializeCompiledIOP(comp)
+	if err != nil {
+		t.Fatalf("could not encode: %v", err.Error())
+	}
+
+	decoded, err := DeserializeCompiledIOP(encoded)
+	if err != nil {
+		t.Fatalf("could not decode: %v", err.Error())
+	}
+
+	if got := decoded.Columns.GetHandle("foo").GetColID(); got != "foo_renamed" {
+		t.Fatalf("the alias was not restored, got %v", got)
+	}
+}
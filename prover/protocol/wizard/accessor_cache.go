@@ -0,0 +1,99 @@
+package wizard
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+)
+
+var (
+	_ ifaces.AccessorCache      = &VerifierRuntime{}
+	_ ifaces.GnarkAccessorCache = &WizardVerifierCircuit{}
+)
+
+// accessorCache memoizes the values of the accessors evaluated by a verifier
+// runtime, keyed by the ESHash of the expression they evaluate. The values
+// only depend on the coins and on the messages of the prover, so they remain
+// valid as long as these do not change:
+//
+//   - the cache belongs to a single runtime and is never shared across
+//     proofs,
+//   - it is emptied when the coins of the runtime are generated,
+//   - in a circuit, it is emptied when the verification starts as the
+//     variables of an earlier compilation cannot be reused.
+//
+// The code mutating the content of a runtime after the coins are generated
+// must call [VerifierRuntime.InvalidateAccessorCache].
+type accessorCache[T any] struct {
+	mu     sync.Mutex
+	values map[field.Element]T
+}
+
+// newAccessorCache returns an empty [accessorCache]
+func newAccessorCache[T any]() *accessorCache[T] {
+	return &accessorCache[T]{values: map[field.Element]T{}}
+}
+
+// get returns the memoized value for the key, if any
+func (c *accessorCache[T]) get(key field.Element) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[key]
+	return v, ok
+}
+
+// set memoizes a value for the key
+func (c *accessorCache[T]) set(key field.Element, val T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = val
+}
+
+// reset drops all the memoized values
+func (c *accessorCache[T]) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.values)
+}
+
+// GetMemoizedAccessor implements [ifaces.AccessorCache]
+func (run *VerifierRuntime) GetMemoizedAccessor(key field.Element) (field.Element, bool) {
+	if run.accessorCache == nil {
+		return field.Element{}, false
+	}
+	return run.accessorCache.get(key)
+}
+
+// MemoizeAccessor implements [ifaces.AccessorCache]. The runtimes that are not
+// constructed by [Verify] do not memoize anything.
+func (run *VerifierRuntime) MemoizeAccessor(key field.Element, val field.Element) {
+	if run.accessorCache != nil {
+		run.accessorCache.set(key, val)
+	}
+}
+
+// InvalidateAccessorCache drops the values of the accessors memoized by the
+// runtime. It must be called after updating the coins, the columns or the
+// query parameters of the runtime.
+func (run *VerifierRuntime) InvalidateAccessorCache() {
+	if run.accessorCache != nil {
+		run.accessorCache.reset()
+	}
+}
+
+// GetMemoizedAccessor implements [ifaces.GnarkAccessorCache]
+func (c *WizardVerifierCircuit) GetMemoizedAccessor(key field.Element) (frontend.Variable, bool) {
+	if c.accessorCache == nil {
+		return nil, false
+	}
+	return c.accessorCache.get(key)
+}
+
+// MemoizeAccessor implements [ifaces.GnarkAccessorCache]
+func (c *WizardVerifierCircuit) MemoizeAccessor(key field.Element, val frontend.Variable) {
+	if c.accessorCache != nil {
+		c.accessorCache.set(key, val)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/symbolic"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/consensys/linea-monorepo/prover/utils/ids"
 )
 
 /*
@@ -69,6 +70,9 @@ func Compile(define DefineFunc, compilers ...func(*CompiledIOP)) *CompiledIOP {
 		builder.equalizeRounds(numRounds)
 	}
 
+	// Validates and orders the verifier actions with explicit ordering
+	comp.orderVerifierActions()
+
 	return builder.CompiledIOP
 }
 
@@ -76,19 +80,49 @@ func Compile(define DefineFunc, compilers ...func(*CompiledIOP)) *CompiledIOP {
 Creates a new builder for a new IOP
 */
 func newBuilder() Builder {
+
+	comp := &CompiledIOP{
+		Columns:         column.NewStore(),
+		QueriesParams:   NewRegister[ifaces.QueryID, ifaces.Query](),
+		QueriesNoParams: NewRegister[ifaces.QueryID, ifaces.Query](),
+		Coins:           NewRegister[coin.Name, coin.Info](),
+		Precomputed:     collection.NewMapping[ifaces.ColID, ifaces.ColAssignment](),
+	}
+
+	comp.QueriesParams.validateIDs(validateQueryID)
+	comp.QueriesNoParams.validateIDs(validateQueryID)
+
+	// The queries with and without parameters are stored in distinct
+	// registers, a query reusing the ID of one of the other register would
+	// shadow it in the runtime.
+	if detectIDCollisions {
+		detector := ids.NewDetector(wizardPkg)
+		comp.QueriesParams.detectCollisions(detector, "query")
+		comp.QueriesNoParams.detectCollisions(detector, "query")
+		comp.Coins.detectCollisions(detector, "coin")
+	}
+
 	return Builder{
-		CompiledIOP: &CompiledIOP{
-			Columns:         column.NewStore(),
-			QueriesParams:   NewRegister[ifaces.QueryID, ifaces.Query](),
-			QueriesNoParams: NewRegister[ifaces.QueryID, ifaces.Query](),
-			Coins:           NewRegister[coin.Name, coin.Info](),
-			Precomputed:     collection.NewMapping[ifaces.ColID, ifaces.ColAssignment](),
-		},
+		CompiledIOP:    comp,
 		currRound:      0,
 		fsStateIsDirty: true,
 	}
 }
 
+// detectIDCollisions enables the detection of the IDs defined twice. It is
+// only enabled in the debug builds by default, see [ids.Debug].
+var detectIDCollisions = ids.Debug
+
+// validateQueryID checks the format of a query ID, see [ifaces.NewQueryID]
+func validateQueryID(id ifaces.QueryID) error {
+	_, err := ifaces.NewQueryID(string(id))
+	return err
+}
+
+// wizardPkg is the import path of the package, whose frames are skipped when
+// reporting where an ID is defined.
+const wizardPkg = "github.com/consensys/linea-monorepo/prover/protocol/wizard"
+
 /*
 Registers a new column in the protocol
 */
@@ -184,6 +218,24 @@ func (b *Builder) InclusionConditionalOnIncluded(name ifaces.QueryID, including,
 	b.InsertInclusionConditionalOnIncluded(b.currRound, name, including, included, includedFilter)
 }
 
+/*
+An inclusion query that only applies on the rows of the included array where
+all the selectors are equal to 1. The selectors should be columns that contain
+only field elements for 0 and 1.
+*/
+func (b *Builder) InclusionWithSelectors(name ifaces.QueryID, including, included []ifaces.Column, selectors ...ifaces.Column) {
+	b.InsertInclusionWithSelectors(b.currRound, name, including, included, selectors...)
+}
+
+/*
+An inclusion query where each row of the included array is looked up as many
+times as the value of the weight column on this row. The rows of weight zero
+are disregarded.
+*/
+func (b *Builder) WeightedInclusion(name ifaces.QueryID, including, included []ifaces.Column, weight ifaces.Column) {
+	b.InsertWeightedInclusion(b.currRound, name, including, included, weight)
+}
+
 /*
 Creates an permutation query. The query views `a` and `b_` to be lists of
 columns and asserts that `a` and `b_` have the same rows (possibly in
@@ -0,0 +1,475 @@
+package wizard
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Checkpoint is the state of a [ProverRuntime] at the end of a round. It is
+// taken by [Prove] when it runs with [WithCheckpoints] and allows another
+// prover to resume the proof from the next round with [ResumeFrom], e.g. a
+// standby instance taking over a failed one.
+//
+// A checkpoint only holds the changes since the previous one: the entries
+// inserted or replaced in the runtime and the names of the entries deleted
+// since then.
+// The checkpoints of a proof thus form a chain starting from the first one,
+// whose Previous round is -1, and the prover resumes from the whole chain.
+// The values are shared with the runtime, the prover steps are not expected
+// to mutate the assignments in place.
+type Checkpoint struct {
+	// Round is the last round completed by the prover and Previous the round
+	// of the previous checkpoint of the chain, -1 for the first one.
+	Round         int
+	Previous      int
+	Columns       map[ifaces.ColID]ifaces.ColAssignment
+	QueriesParams map[ifaces.QueryID]ifaces.QueryParams
+	Coins         map[coin.Name]interface{}
+	// State holds the entries of the State of the runtime, they are encoded
+	// with the codecs registered with [CompiledIOP.RegisterStateCodec].
+	State map[string]interface{}
+	// Deleted lists the names of the entries deleted since the previous
+	// checkpoint and not inserted again.
+	Deleted checkpointDeletions
+	FS      fiatshamir.Snapshot
+
+	// codecs are the codecs of the entries of the State, they are set when
+	// the checkpoint is taken or decoded.
+	codecs map[string]StateCodec
+}
+
+// checkpointDeletions lists the entries deleted from the runtime between two
+// checkpoints.
+type checkpointDeletions struct {
+	Columns       []ifaces.ColID
+	QueriesParams []ifaces.QueryID
+	Coins         []coin.Name
+	State         []string
+}
+
+// StateCodec saves an entry of the State of the [ProverRuntime] in the
+// checkpoints and restores it. The entries of the State are generally not
+// gob-encodable (Merkle trees holding their hash function, channels, ...),
+// so each of them has a codec registered by the compiler inserting it.
+type StateCodec interface {
+	EncodeState(w io.Writer, v any) error
+	DecodeState(r io.Reader) (any, error)
+}
+
+// RegisterStateCodec registers the codec of the entry of the State of the
+// prover runtime with the given name. The checkpoints cannot be taken while
+// the State holds an entry without codec, see also
+// [CompiledIOP.RegisterTransientState].
+func (c *CompiledIOP) RegisterStateCodec(name string, codec StateCodec) {
+	if codec == nil {
+		utils.Panic("the codec of the state %v is nil", name)
+	}
+	c.registerStateCodec(name, codec)
+}
+
+// RegisterTransientState declares an entry of the State of the prover runtime
+// that cannot be saved in a checkpoint, e.g. the channels to a solver running
+// in the background. No checkpoint is taken while the State holds such an
+// entry: the changes of the rounds are carried over to the next checkpoint.
+func (c *CompiledIOP) RegisterTransientState(name string) {
+	c.registerStateCodec(name, nil)
+}
+
+func (c *CompiledIOP) registerStateCodec(name string, codec StateCodec) {
+	if c.stateCodecs == nil {
+		c.stateCodecs = map[string]StateCodec{}
+	}
+	if _, found := c.stateCodecs[name]; found {
+		utils.Panic("the codec of the state %v is already registered", name)
+	}
+	c.stateCodecs[name] = codec
+}
+
+// WithCheckpoints makes [Prove] pass a [Checkpoint] to sink at the end of
+// every round but the last one. The sink is called in the background, while
+// the prover runs the next round, and at most one call is pending at a time.
+// An error returned by the sink aborts the proof: the next checkpoints would
+// not form a chain with the ones already saved.
+func WithCheckpoints(sink func(*Checkpoint) error) ProveOption {
+	return func(run *ProverRuntime) {
+		run.checkpointSink = sink
+	}
+}
+
+// ResumeFrom makes [Prove] resume the proof from a chain of checkpoints
+// instead of starting from scratch: the prover runs from the round following
+// the one of the last checkpoint and the high-level prover is not called. The
+// checkpoints must have been taken on the same [CompiledIOP].
+func ResumeFrom(chain []*Checkpoint) ProveOption {
+	return func(run *ProverRuntime) {
+		run.resumeFrom = chain
+	}
+}
+
+// checkpointTracker keeps track of the entries of the runtime saved in the
+// previous checkpoints, so that a checkpoint only holds the changes. The
+// values are kept along with the keys: an entry deleted and inserted again
+// under the same key between two checkpoints is a change as well. Thus, a
+// deleted entry is only released once the next checkpoint is taken.
+type checkpointTracker struct {
+	round         int
+	columns       map[ifaces.ColID]ifaces.ColAssignment
+	queriesParams map[ifaces.QueryID]ifaces.QueryParams
+	coins         map[coin.Name]interface{}
+	state         map[string]interface{}
+	// pending receives the result of the sink for the last checkpoint
+	pending chan error
+}
+
+// newCheckpointTracker returns a tracker for which nothing was saved but the
+// precomputed columns, as they are part of the compiled IOP.
+func newCheckpointTracker(c *CompiledIOP) *checkpointTracker {
+	tr := &checkpointTracker{
+		round:         -1,
+		columns:       map[ifaces.ColID]ifaces.ColAssignment{},
+		queriesParams: map[ifaces.QueryID]ifaces.QueryParams{},
+		coins:         map[coin.Name]interface{}{},
+		state:         map[string]interface{}{},
+	}
+	for name, val := range c.Precomputed.InnerMap() {
+		tr.columns[name] = val
+	}
+	return tr
+}
+
+// checkpoint passes the changes of the runtime since the previous checkpoint
+// to the sink, in the background. It panics if the previous checkpoint could
+// not be saved. The rounds already saved, i.e. the one the prover resumed
+// from, are not saved again: the checkpoint would replace the saved one with
+// an empty delta and break the chain.
+func (run *ProverRuntime) checkpoint() {
+
+	if run.checkpointSink == nil || run.currRound <= run.checkpoints.round {
+		return
+	}
+
+	run.waitCheckpoint()
+
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	for name := range run.State.InnerMap() {
+		codec, found := run.Spec.stateCodecs[name]
+		if !found {
+			utils.Panic("cannot checkpoint round %v: the state %v has no codec", run.currRound, name)
+		}
+		if codec == nil {
+			logrus.Debugf("skipping the checkpoint of round %v, the state %v is transient", run.currRound, name)
+			return
+		}
+	}
+
+	var (
+		tr = run.checkpoints
+		cp = &Checkpoint{
+			Round:    run.currRound,
+			Previous: tr.round,
+			FS:       run.FS.Snapshot(),
+			codecs:   run.Spec.stateCodecs,
+		}
+	)
+
+	cp.Columns, cp.Deleted.Columns = trackChanges(tr.columns, run.Columns.InnerMap())
+	cp.QueriesParams, cp.Deleted.QueriesParams = trackChanges(tr.queriesParams, run.QueriesParams.InnerMap())
+	cp.Coins, cp.Deleted.Coins = trackChanges(tr.coins, run.Coins.InnerMap())
+	cp.State, cp.Deleted.State = trackChanges(tr.state, run.State.InnerMap())
+	tr.round = run.currRound
+
+	tr.pending = make(chan error, 1)
+	go func(sink func(*Checkpoint) error, res chan<- error) {
+		res <- sink(cp)
+	}(run.checkpointSink, tr.pending)
+}
+
+// waitCheckpoint waits for the sink to return on the last checkpoint and
+// panics if it failed.
+func (run *ProverRuntime) waitCheckpoint() {
+	tr := run.checkpoints
+	if tr.pending == nil {
+		return
+	}
+	err := <-tr.pending
+	tr.pending = nil
+	if err != nil {
+		utils.Panic("could not save the checkpoint of round %v: %v", tr.round, err)
+	}
+}
+
+// trackChanges returns the entries of m that are not in saved or whose value
+// changed, and the keys of saved that are not in m. It updates saved to m.
+// The deleted keys are sorted for the checkpoints to be deterministic.
+func trackChanges[K ~string, V any](saved map[K]V, m map[K]V) (map[K]V, []K) {
+
+	var (
+		inserted = map[K]V{}
+		deleted  = []K{}
+	)
+
+	for k, v := range m {
+		if old, found := saved[k]; !found || !sameEntry(old, v) {
+			inserted[k] = v
+			saved[k] = v
+		}
+	}
+
+	for k := range saved {
+		if _, found := m[k]; !found {
+			deleted = append(deleted, k)
+			delete(saved, k)
+		}
+	}
+
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i] < deleted[j] })
+	return inserted, deleted
+}
+
+// sameEntry returns true if a and b are the same value of the runtime. The
+// assignments are pointers and are compared as such, the other values, such
+// as the query parameters, are compared deeply. A false negative only costs
+// saving the value again.
+func sameEntry(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return !va.IsValid() && !vb.IsValid()
+	}
+	if va.Comparable() && vb.Comparable() {
+		return va.Equal(vb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// restore sets the runtime in the state of the chain of checkpoints
+func (run *ProverRuntime) restore(chain []*Checkpoint) {
+
+	if len(chain) == 0 {
+		utils.Panic("cannot resume from an empty chain of checkpoints")
+	}
+
+	if err := checkChain(chain); err != nil {
+		utils.Panic("cannot resume from the checkpoints: %v", err)
+	}
+
+	last := chain[len(chain)-1]
+	if last.Round+1 >= run.NumRounds() {
+		utils.Panic("cannot resume from the round %v, the protocol has %v rounds", last.Round, run.NumRounds())
+	}
+
+	tr := run.checkpoints
+	for _, cp := range chain {
+
+		// The entries may replace the ones of the previous checkpoints
+		for name, val := range cp.Columns {
+			run.Columns.Update(name, val)
+		}
+		for name, val := range cp.QueriesParams {
+			run.QueriesParams.Update(name, val)
+		}
+		for name, val := range cp.Coins {
+			run.Coins.Update(name, val)
+		}
+		for name, val := range cp.State {
+			run.State.Update(name, val)
+		}
+
+		for _, name := range cp.Deleted.Columns {
+			run.Columns.TryDel(name)
+		}
+		for _, name := range cp.Deleted.QueriesParams {
+			run.QueriesParams.TryDel(name)
+		}
+		for _, name := range cp.Deleted.Coins {
+			run.Coins.TryDel(name)
+		}
+		for _, name := range cp.Deleted.State {
+			run.State.TryDel(name)
+		}
+	}
+
+	// The next checkpoints continue the chain
+	trackChanges(tr.columns, run.Columns.InnerMap())
+	trackChanges(tr.queriesParams, run.QueriesParams.InnerMap())
+	trackChanges(tr.coins, run.Coins.InnerMap())
+	trackChanges(tr.state, run.State.InnerMap())
+	tr.round = last.Round
+
+	run.FS = fiatshamir.Restore(last.FS)
+	run.currRound = last.Round
+}
+
+// checkChain returns an error if the checkpoints do not form a chain
+func checkChain(chain []*Checkpoint) error {
+	prev := -1
+	for _, cp := range chain {
+		if cp.Previous != prev || cp.Round <= prev {
+			return fmt.Errorf("the checkpoint of round %v follows the one of round %v, expected %v", cp.Round, cp.Previous, prev)
+		}
+		prev = cp.Round
+	}
+	return nil
+}
+
+func init() {
+	// The concrete types stored behind the interfaces of the query parameters
+	// and of the coins.
+	gob.Register(field.Element{})
+	gob.Register([]int{})
+	gob.Register(query.UnivariateEvalParams{})
+	gob.Register(query.LocalOpeningParams{})
+	gob.Register(query.InnerProductParams{})
+}
+
+// encodedColumn is the gob encoding of a column assignment. The constant
+// vectors are kept as such, the other ones are encoded as regular vectors.
+type encodedColumn struct {
+	Len      int
+	Constant *field.Element
+	Values   []field.Element
+}
+
+// encodedCheckpoint is the gob encoding of a [Checkpoint]
+type encodedCheckpoint struct {
+	Round         int
+	Previous      int
+	Columns       map[ifaces.ColID]encodedColumn
+	QueriesParams map[ifaces.QueryID]ifaces.QueryParams
+	Coins         map[coin.Name]interface{}
+	State         map[string][]byte
+	Deleted       checkpointDeletions
+	FS            fiatshamir.Snapshot
+}
+
+// Encode writes the checkpoint with gob. The entries of the State are encoded
+// with their codec; the function fails if one of them has none.
+func (cp *Checkpoint) Encode(w io.Writer) error {
+
+	enc := encodedCheckpoint{
+		Round:         cp.Round,
+		Previous:      cp.Previous,
+		Columns:       make(map[ifaces.ColID]encodedColumn, len(cp.Columns)),
+		QueriesParams: cp.QueriesParams,
+		Coins:         cp.Coins,
+		State:         make(map[string][]byte, len(cp.State)),
+		Deleted:       cp.Deleted,
+		FS:            cp.FS,
+	}
+
+	for name, val := range cp.Columns {
+		col := encodedColumn{Len: val.Len()}
+		if c, ok := val.(*smartvectors.Constant); ok {
+			v := c.Get(0)
+			col.Constant = &v
+		} else {
+			col.Values = val.IntoRegVecSaveAlloc()
+		}
+		enc.Columns[name] = col
+	}
+
+	for name, val := range cp.State {
+		codec := cp.codecs[name]
+		if codec == nil {
+			return fmt.Errorf("could not encode the checkpoint of round %v: the state %v has no codec", cp.Round, name)
+		}
+		buf := &bytes.Buffer{}
+		if err := codec.EncodeState(buf, val); err != nil {
+			return fmt.Errorf("could not encode the state %v of the checkpoint of round %v: %w", name, cp.Round, err)
+		}
+		enc.State[name] = buf.Bytes()
+	}
+
+	if err := gob.NewEncoder(w).Encode(&enc); err != nil {
+		return fmt.Errorf("could not encode the checkpoint of round %v: %w", cp.Round, err)
+	}
+	return nil
+}
+
+// DecodeCheckpoint reads a checkpoint written by [Checkpoint.Encode]. The
+// entries of the State are decoded with the codecs registered in comp.
+func DecodeCheckpoint(comp *CompiledIOP, r io.Reader) (*Checkpoint, error) {
+
+	var enc encodedCheckpoint
+	if err := gob.NewDecoder(r).Decode(&enc); err != nil {
+		return nil, fmt.Errorf("could not decode the checkpoint: %w", err)
+	}
+
+	cp := &Checkpoint{
+		Round:         enc.Round,
+		Previous:      enc.Previous,
+		Columns:       make(map[ifaces.ColID]ifaces.ColAssignment, len(enc.Columns)),
+		QueriesParams: orEmpty(enc.QueriesParams),
+		Coins:         orEmpty(enc.Coins),
+		State:         make(map[string]interface{}, len(enc.State)),
+		Deleted:       enc.Deleted,
+		FS:            enc.FS,
+		codecs:        comp.stateCodecs,
+	}
+
+	for name, col := range enc.Columns {
+		if col.Constant != nil {
+			cp.Columns[name] = smartvectors.NewConstant(*col.Constant, col.Len)
+			continue
+		}
+		if len(col.Values) != col.Len {
+			return nil, fmt.Errorf("column %v: %v values, expected %v", name, len(col.Values), col.Len)
+		}
+		cp.Columns[name] = smartvectors.NewRegular(col.Values)
+	}
+
+	for name, b := range enc.State {
+		codec := comp.stateCodecs[name]
+		if codec == nil {
+			return nil, fmt.Errorf("the state %v of the checkpoint has no codec", name)
+		}
+		val, err := codec.DecodeState(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode the state %v of the checkpoint: %w", name, err)
+		}
+		cp.State[name] = val
+	}
+
+	return cp, nil
+}
+
+// orEmpty returns an empty map for the nil maps, as gob decodes the empty
+// maps as nil.
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return map[K]V{}
+	}
+	return m
+}
+
+// GobStateCodec is a [StateCodec] for the entries of the State that are
+// gob-encodable, T being their concrete type.
+type GobStateCodec[T any] struct{}
+
+func (GobStateCodec[T]) EncodeState(w io.Writer, v any) error {
+	t, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("expected a %T, got a %T", t, v)
+	}
+	return gob.NewEncoder(w).Encode(t)
+}
+
+func (GobStateCodec[T]) DecodeState(r io.Reader) (any, error) {
+	var t T
+	err := gob.NewDecoder(r).Decode(&t)
+	return t, err
+}
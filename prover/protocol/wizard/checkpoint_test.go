@@ -0,0 +1,240 @@
+package wizard_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// checkpointedProtocol has three rounds whose messages depend on the coins of
+// the previous rounds and on the State, so that resuming the proof from a
+// checkpoint only yields the same proof if the whole runtime is restored.
+func checkpointedProtocol() (*wizard.CompiledIOP, wizard.ProverStep, *int) {
+
+	var (
+		mainCalls int
+		p, q, r   ifaces.Column
+		c1, c2    coin.Info
+	)
+
+	define := func(b *wizard.Builder) {
+
+		p = b.CompiledIOP.InsertProof(0, "CKPT_P", SIZE)
+		c1 = b.CompiledIOP.InsertCoin(1, "CKPT_C1", coin.Field)
+		q = b.CompiledIOP.InsertProof(1, "CKPT_Q", SIZE)
+		c2 = b.CompiledIOP.InsertCoin(2, "CKPT_C2", coin.IntegerVec, 4, 8)
+		r = b.CompiledIOP.InsertProof(2, "CKPT_R", SIZE)
+		b.CompiledIOP.RegisterStateCodec("CKPT_STATE", wizard.GobStateCodec[field.Element]{})
+
+		b.RegisterProverAction(1, proverActionFunc(func(run *wizard.ProverRuntime) {
+			c := run.GetRandomCoinField(c1.Name)
+			qv := smartvectors.ScalarMul(p.GetColAssignment(run), c)
+			run.AssignColumn(q.GetColID(), qv)
+			run.State.InsertNew("CKPT_STATE", c)
+		}))
+
+		b.RegisterProverAction(2, proverActionFunc(func(run *wizard.ProverRuntime) {
+			var (
+				ints  = run.GetRandomCoinIntegerVec(c2.Name)
+				state = run.State.MustGet("CKPT_STATE").(field.Element)
+				rv    = make([]field.Element, SIZE)
+			)
+			for i := range rv {
+				rv[i].SetInt64(int64(ints[i%len(ints)]))
+				rv[i].Add(&rv[i], &state)
+			}
+			run.AssignColumn(r.GetColID(), smartvectors.NewRegular(rv))
+		}))
+	}
+
+	prover := func(run *wizard.ProverRuntime) {
+		mainCalls++
+		run.AssignColumn(p.GetColID(), smartvectors.ForTest(1, 2, 3, 4))
+	}
+
+	return wizard.Compile(define), prover, &mainCalls
+}
+
+// proveWithCheckpoints runs the prover and returns its checkpoints, encoded
+// as when they are sent to another process.
+func proveWithCheckpoints(t *testing.T, comp *wizard.CompiledIOP, prover wizard.ProverStep) (wizard.Proof, [][]byte) {
+
+	var encoded [][]byte
+
+	proof := wizard.Prove(comp, prover, wizard.WithCheckpoints(func(cp *wizard.Checkpoint) error {
+		buf := &bytes.Buffer{}
+		if err := cp.Encode(buf); err != nil {
+			return err
+		}
+		encoded = append(encoded, buf.Bytes())
+		return nil
+	}))
+
+	require.NoError(t, wizard.Verify(comp, proof))
+	return proof, encoded
+}
+
+// requireResumes checks that the proof resumed from every prefix of the chain
+// of checkpoints is the reference proof.
+func requireResumes(t *testing.T, comp *wizard.CompiledIOP, prover wizard.ProverStep, reference wizard.Proof, encoded [][]byte) {
+
+	var chain []*wizard.Checkpoint
+
+	for _, b := range encoded {
+
+		cp, err := wizard.DecodeCheckpoint(comp, bytes.NewReader(b))
+		require.NoError(t, err)
+		chain = append(chain, cp)
+
+		resumed := wizard.Prove(comp, prover, wizard.ResumeFrom(chain))
+		require.NoError(t, wizard.Verify(comp, resumed))
+
+		for _, name := range reference.Messages.ListAllKeys() {
+			require.Equal(t,
+				reference.Messages.MustGet(name).IntoRegVecSaveAlloc(),
+				resumed.Messages.MustGet(name).IntoRegVecSaveAlloc(),
+				"round %v, message %v", cp.Round, name,
+			)
+		}
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+
+	comp, prover, mainCalls := checkpointedProtocol()
+
+	reference, encoded := proveWithCheckpoints(t, comp, prover)
+	require.Len(t, encoded, 2)
+	require.Equal(t, 1, *mainCalls)
+
+	// Each checkpoint only holds the changes of its round
+	var chain []*wizard.Checkpoint
+	for i, b := range encoded {
+		cp, err := wizard.DecodeCheckpoint(comp, bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Equal(t, i, cp.Round)
+		require.Equal(t, i-1, cp.Previous)
+		chain = append(chain, cp)
+	}
+	require.Contains(t, chain[0].Columns, ifaces.ColID("CKPT_P"))
+	require.NotContains(t, chain[1].Columns, ifaces.ColID("CKPT_P"))
+	require.Contains(t, chain[1].Columns, ifaces.ColID("CKPT_Q"))
+	require.Contains(t, chain[1].State, "CKPT_STATE")
+
+	// The high-level prover is not re-run and the proof is the same
+	requireResumes(t, comp, prover, reference, encoded)
+	require.Equal(t, 1, *mainCalls)
+
+	// The checkpoints must form a chain
+	require.Panics(t, func() { wizard.Prove(comp, prover, wizard.ResumeFrom(chain[1:])) })
+}
+
+func TestCheckpointFailure(t *testing.T) {
+
+	comp, prover, _ := checkpointedProtocol()
+
+	// The checkpoints that cannot be saved abort the proof
+	require.Panics(t, func() {
+		wizard.Prove(comp, prover, wizard.WithCheckpoints(func(cp *wizard.Checkpoint) error {
+			return errors.New("disk full")
+		}))
+	})
+}
+
+func TestCheckpointNotEncodable(t *testing.T) {
+
+	type opaque struct{ x int }
+
+	cp := &wizard.Checkpoint{State: map[string]interface{}{"OPAQUE": opaque{x: 1}}}
+	require.Error(t, cp.Encode(&bytes.Buffer{}), "the state has no codec")
+}
+
+func TestCheckpointResumeTwice(t *testing.T) {
+
+	comp, prover, _ := checkpointedProtocol()
+	reference, encoded := proveWithCheckpoints(t, comp, prover)
+
+	first, err := wizard.DecodeCheckpoint(comp, bytes.NewReader(encoded[0]))
+	require.NoError(t, err)
+
+	// A standby prover resuming from the first round saves the next rounds
+	// only, so that the chain can be resumed again after a second failover.
+	var resaved []*wizard.Checkpoint
+	resumed := wizard.Prove(comp, prover,
+		wizard.ResumeFrom([]*wizard.Checkpoint{first}),
+		wizard.WithCheckpoints(func(cp *wizard.Checkpoint) error {
+			buf := &bytes.Buffer{}
+			if err := cp.Encode(buf); err != nil {
+				return err
+			}
+			decoded, err := wizard.DecodeCheckpoint(comp, buf)
+			resaved = append(resaved, decoded)
+			return err
+		}),
+	)
+	require.NoError(t, wizard.Verify(comp, resumed))
+
+	require.Len(t, resaved, 1)
+	require.Equal(t, 1, resaved[0].Round)
+	require.Equal(t, 0, resaved[0].Previous)
+	require.Contains(t, resaved[0].Columns, ifaces.ColID("CKPT_Q"))
+
+	chain := []*wizard.Checkpoint{first, resaved[0]}
+	again := wizard.Prove(comp, prover, wizard.ResumeFrom(chain))
+	require.NoError(t, wizard.Verify(comp, again))
+	for _, name := range reference.Messages.ListAllKeys() {
+		require.Equal(t,
+			reference.Messages.MustGet(name).IntoRegVecSaveAlloc(),
+			again.Messages.MustGet(name).IntoRegVecSaveAlloc(),
+			"message %v", name,
+		)
+	}
+}
+
+func TestCheckpointReplacedEntry(t *testing.T) {
+
+	var r ifaces.Column
+
+	define := func(b *wizard.Builder) {
+
+		b.CompiledIOP.InsertProof(0, "CKPT_REPLACED_P", SIZE)
+		b.CompiledIOP.InsertCoin(1, "CKPT_REPLACED_C1", coin.Field)
+		b.CompiledIOP.InsertCoin(2, "CKPT_REPLACED_C2", coin.Field)
+		r = b.CompiledIOP.InsertProof(2, "CKPT_REPLACED_R", SIZE)
+		b.CompiledIOP.RegisterStateCodec("CKPT_REPLACED", wizard.GobStateCodec[field.Element]{})
+
+		// The entry saved at round 0 is replaced under the same key
+		b.RegisterProverAction(1, proverActionFunc(func(run *wizard.ProverRuntime) {
+			run.State.Del("CKPT_REPLACED")
+			run.State.InsertNew("CKPT_REPLACED", field.NewElement(2))
+		}))
+
+		b.RegisterProverAction(2, proverActionFunc(func(run *wizard.ProverRuntime) {
+			v := run.State.MustGet("CKPT_REPLACED").(field.Element)
+			run.AssignColumn(r.GetColID(), smartvectors.NewConstant(v, SIZE))
+		}))
+	}
+
+	prover := func(run *wizard.ProverRuntime) {
+		run.AssignColumn("CKPT_REPLACED_P", smartvectors.ForTest(1, 2, 3, 4))
+		run.State.InsertNew("CKPT_REPLACED", field.NewElement(1))
+	}
+
+	comp := wizard.Compile(define)
+	reference, encoded := proveWithCheckpoints(t, comp, prover)
+	require.Len(t, encoded, 2)
+
+	cp, err := wizard.DecodeCheckpoint(comp, bytes.NewReader(encoded[1]))
+	require.NoError(t, err)
+	require.Equal(t, field.NewElement(2), cp.State["CKPT_REPLACED"])
+	require.Empty(t, cp.Deleted.State)
+
+	requireResumes(t, comp, prover, reference, encoded)
+}
@@ -66,6 +66,12 @@ type CompiledIOP struct {
 	// protocol.
 	SubProvers collection.VecVec[ProverStep]
 
+	// proverStepDeps stores, round by round, the dependencies declared for the
+	// prover steps registered via [CompiledIOP.RegisterProverActionWithDeps].
+	// The inner map is keyed by the position of the step in [SubProvers]. It
+	// is nil as long as no step has declared its dependencies.
+	proverStepDeps map[int]map[int][]int
+
 	// subVerifier stores all the steps that need to be performed by the verifier
 	// explicitly. The role of the verifier function's is to implement all the
 	// manual checks that the verifier has to perform. This is useful when a check
@@ -80,11 +86,33 @@ type CompiledIOP struct {
 	// instantiating a gnark verifier for the sub-protocol.
 	gnarkSubVerifiers collection.VecVec[GnarkVerifierStep]
 
+	// orderedVerifierActions stores, by name, the verifier actions registered
+	// via [CompiledIOP.RegisterVerifierActionOrdered] and verifierActionOrder
+	// the order in which they are run, round by round. The order is computed
+	// by [Compile].
+	orderedVerifierActions map[string]*orderedVerifierAction
+	verifierActionOrder    [][]*orderedVerifierAction
+
 	// Precomputed stores the assignments of all the Precomputed and VerifierKey
 	// polynomials. It is assigned directly when registering a precomputed
 	// column.
 	Precomputed collection.Mapping[ifaces.ColID, ifaces.ColAssignment]
 
+	// precomputedTables indexes the lookup tables registered via
+	// [CompiledIOP.InsertPrecomputedTable] by digest of their content. It is
+	// lazily initialized.
+	precomputedTables map[tableDigest]ifaces.ColID
+
+	// columnRenames maps the names of the columns to rename to their new name,
+	// see [CompiledIOP.RenameColumns]. It is nil as long as no rename is
+	// declared.
+	columnRenames map[ifaces.ColID]ifaces.ColID
+
+	// stateCodecs stores, by name, the codecs of the entries of the State of
+	// the prover runtime, see [CompiledIOP.RegisterStateCodec]. A nil codec
+	// marks a transient entry, see [CompiledIOP.RegisterTransientState].
+	stateCodecs map[string]StateCodec
+
 	// CryptographicCompilerCtx stores the compilation context of the last used
 	// cryptographic compiler. Specifically, it is aimed to store the last
 	// Vortex compilation context (see [github.com/consensys/linea-monorepo/prover/protocol/compiler]) that was used. And
@@ -120,6 +148,10 @@ type CompiledIOP struct {
 	//
 	// For efficiency reasons, the fiatShamirSetup is derived using SHA2.
 	fiatShamirSetup field.Element
+
+	// versionMetadata stores the metadata used to derive [fiatShamirSetup].
+	// It is exported in the [VerifierKey].
+	versionMetadata VersionMetadata
 }
 
 // NumRounds returns the total number of prover interactions with the verifier
@@ -130,6 +162,16 @@ func (c *CompiledIOP) NumRounds() int {
 	return utils.Max(1, c.Coins.NumRounds())
 }
 
+// NumVerifierSteps returns the number of verifier steps registered in the
+// protocol across all rounds.
+func (c *CompiledIOP) NumVerifierSteps() int {
+	res := 0
+	for round := 0; round < c.subVerifiers.Len(); round++ {
+		res += c.subVerifiers.LenOf(round)
+	}
+	return res + len(c.orderedVerifierActions)
+}
+
 // ListCommitments returns a list of all the column that are registered in the
 // protocol. The columns are returned in a deterministic order: round-by-round
 // then by chronological order of declaration.
@@ -179,7 +221,7 @@ func (c *CompiledIOP) InsertColumn(round int, name ifaces.ColID, size int, statu
 	}
 
 	// This performs all the checks
-	return c.Columns.AddToRound(round, name, size, status)
+	return c.addColumn(round, name, size, status)
 }
 
 /*
@@ -376,6 +418,34 @@ func (c *CompiledIOP) InsertInclusionConditionalOnIncluded(round int, name iface
 	c.QueriesNoParams.AddToRound(round, name, query)
 }
 
+// InsertInclusionWithSelectors creates an inclusion query that only applies
+// on the rows of the included table where all the selectors are equal to 1.
+// The selectors must be binary columns of the same size as the included
+// table. Contrary to filtering with a column computed as the product of the
+// selectors, this does not require committing to an additional column: the
+// product is directly accounted for by the lookup compiler.
+func (c *CompiledIOP) InsertInclusionWithSelectors(round int, name ifaces.QueryID, including, included []ifaces.Column, selectors ...ifaces.Column) {
+	c.assertConsistentRound(round)
+	if len(selectors) == 0 {
+		utils.Panic("inclusion %v: no selectors were provided, use InsertInclusion instead", name)
+	}
+	query := query.NewInclusion(name, included, [][]ifaces.Column{including}, nil, nil).
+		WithIncludedSelectors(selectors...)
+	c.QueriesNoParams.AddToRound(round, name, query)
+}
+
+// InsertWeightedInclusion creates an inclusion query where the row i of the
+// included table is looked up weight[i] times, see
+// [query.Inclusion.IncludedWeight]. The compiler lowers it as a
+// log-derivative sum where weight is the numerator of the included side. The
+// weight must have the same size as the included table.
+func (c *CompiledIOP) InsertWeightedInclusion(round int, name ifaces.QueryID, including, included []ifaces.Column, weight ifaces.Column) {
+	c.assertConsistentRound(round)
+	query := query.NewInclusion(name, included, [][]ifaces.Column{including}, nil, nil).
+		WithIncludedWeight(weight)
+	c.QueriesNoParams.AddToRound(round, name, query)
+}
+
 // GenericFragmentedConditionalInclusion constructs a generic inclusion query
 // where the table can possibly be fragmented in several sub-tables. The user
 // set `includedFilter` and/or `includingFilter` to be nil if he does not wish
@@ -423,8 +493,8 @@ func (c *CompiledIOP) InsertPrecomputed(name ifaces.ColID, v smartvectors.SmartV
 		return c.Columns.GetHandle(name)
 	}
 
-	c.Precomputed.InsertNew(name, v)
-	return c.Columns.AddToRound(0, name, v.Len(), column.Precomputed)
+	c.Precomputed.InsertNew(c.renamedColumn(name), v)
+	return c.addColumn(0, name, v.Len(), column.Precomputed)
 }
 
 // InsertProof registers a proof message by specifying its size and providing
@@ -442,7 +512,7 @@ func (c *CompiledIOP) InsertProof(round int, name ifaces.ColID, size int) (msg i
 		utils.Panic("when registering %v, VecType with length zero", name)
 	}
 
-	return c.Columns.AddToRound(round, name, size, column.Proof)
+	return c.addColumn(round, name, size, column.Proof)
 }
 
 // InsertPublicInput registers a public input column, and specifies static information regarding it
@@ -457,7 +527,7 @@ func (c *CompiledIOP) InsertPublicInput(round int, name ifaces.ColID, size int)
 		utils.Panic("when registering %v, VecType with length zero", name)
 	}
 
-	return c.Columns.AddToRound(round, name, size, column.PublicInput)
+	return c.addColumn(round, name, size, column.PublicInput)
 }
 
 // InsertVerifier registers a verifier steps into the current CompiledIOP;
@@ -610,7 +680,7 @@ func (c *CompiledIOP) RegisterVerifyingKey(name ifaces.ColID, witness ifaces.Col
 	if size == 0 {
 		utils.Panic("when registering %v, VecType with length zero", name)
 	}
-	c.Precomputed.InsertNew(name, witness)
+	c.Precomputed.InsertNew(c.renamedColumn(name), witness)
 	return c.InsertColumn(0, name, size, column.VerifyingKey)
 }
 
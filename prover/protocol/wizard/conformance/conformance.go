@@ -0,0 +1,171 @@
+// Package conformance is a reusable test kit for the wizard modules. Given
+// the Define and the Assign functions of a module, it compiles the module and
+// checks that it is satisfied (no panic and a verifying proof) for the
+// assignments every module must support:
+//
+//   - [ZeroActivity]: all the inputs of the module are inactive,
+//   - [MaxCapacity]: the module is used up to its limits,
+//   - [Fuzz]: random assignments derived from a seed.
+//
+// The Assign function of the module is responsible for generating the inputs
+// of each scenario, the kit takes care of the compilation, of seeding the
+// randomness and of the assertions.
+package conformance
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// Scenario is a kind of assignment of the module under test
+type Scenario int
+
+const (
+	// ZeroActivity is an assignment where all the inputs are inactive, e.g.
+	// there is nothing to hash.
+	ZeroActivity Scenario = iota
+	// MaxCapacity is an assignment using the module up to its limits, e.g.
+	// as many permutations as the module can handle.
+	MaxCapacity
+	// Fuzz is a random assignment, generated from the rng passed to the
+	// Assign function of the [Module].
+	Fuzz
+)
+
+// DefaultNumFuzz is the number of fuzz assignments checked by [Kit.Run]
+// unless specified otherwise with [WithNumFuzz].
+const DefaultNumFuzz = 4
+
+// String returns the name of the scenario, as used in the name of the
+// sub-tests.
+func (s Scenario) String() string {
+	switch s {
+	case ZeroActivity:
+		return "zero-activity"
+	case MaxCapacity:
+		return "max-capacity"
+	case Fuzz:
+		return "fuzz"
+	}
+	return fmt.Sprintf("scenario(%d)", int(s))
+}
+
+// Module is the module under test
+type Module struct {
+	// Define declares the inputs of the module and the module itself
+	Define wizard.DefineFunc
+	// Assign assigns the inputs of the module following the scenario and runs
+	// the prover of the module. rng is seeded deterministically, it is meant
+	// for the [Fuzz] scenario but may be used by all of them.
+	Assign func(run *wizard.ProverRuntime, scenario Scenario, rng *rand.Rand)
+	// Reference optionally checks the outputs of the module against a
+	// reference implementation. It is called at the end of the prover, after
+	// Assign, and the returned error fails the test.
+	Reference func(run *wizard.ProverRuntime) error
+}
+
+// Kit is a module compiled once and checked over many assignments
+type Kit struct {
+	comp      *wizard.CompiledIOP
+	module    Module
+	numFuzz   int
+	seed      int64
+	compilers []func(*wizard.CompiledIOP)
+}
+
+// Option customizes a [Kit]
+type Option func(*Kit)
+
+// WithNumFuzz sets the number of fuzz assignments checked by [Kit.Run]
+func WithNumFuzz(n int) Option {
+	return func(k *Kit) {
+		k.numFuzz = n
+	}
+}
+
+// WithSeed sets the seed of the first fuzz assignment, the following ones use
+// the next seeds.
+func WithSeed(seed int64) Option {
+	return func(k *Kit) {
+		k.seed = seed
+	}
+}
+
+// WithCompilers replaces the default compilation, [dummy.Compile], by the
+// given compilation steps.
+func WithCompilers(compilers ...func(*wizard.CompiledIOP)) Option {
+	return func(k *Kit) {
+		k.compilers = compilers
+	}
+}
+
+// New compiles the module. The test fails if the compilation panics.
+func New(t testing.TB, module Module, opts ...Option) *Kit {
+
+	k := &Kit{
+		module:    module,
+		numFuzz:   DefaultNumFuzz,
+		compilers: []func(*wizard.CompiledIOP){dummy.Compile},
+	}
+
+	for _, opt := range opts {
+		opt(k)
+	}
+
+	require.NotPanics(t, func() {
+		k.comp = wizard.Compile(module.Define, k.compilers...)
+	}, "the compilation of the module panicked")
+
+	return k
+}
+
+// Run checks the module for every scenario, each in its own sub-test
+func (k *Kit) Run(t *testing.T) {
+
+	t.Run(ZeroActivity.String(), func(t *testing.T) {
+		k.Check(t, ZeroActivity, k.seed)
+	})
+
+	t.Run(MaxCapacity.String(), func(t *testing.T) {
+		k.Check(t, MaxCapacity, k.seed)
+	})
+
+	for i := 0; i < k.numFuzz; i++ {
+		seed := k.seed + int64(i)
+		t.Run(fmt.Sprintf("%v/seed=%v", Fuzz, seed), func(t *testing.T) {
+			k.Check(t, Fuzz, seed)
+		})
+	}
+}
+
+// Check proves the assignment of the scenario generated from the seed and
+// verifies the proof. It is also meant to be called from the fuzz targets:
+//
+//	f.Fuzz(func(t *testing.T, seed int64) { kit.Check(t, conformance.Fuzz, seed) })
+func (k *Kit) Check(t testing.TB, scenario Scenario, seed int64) {
+
+	var (
+		rng    = rand.New(rand.NewSource(seed))
+		errRef error
+		proof  wizard.Proof
+	)
+
+	prover := func(run *wizard.ProverRuntime) {
+		k.module.Assign(run, scenario, rng)
+		if k.module.Reference != nil {
+			errRef = k.module.Reference(run)
+		}
+	}
+
+	require.NotPanicsf(t, func() {
+		proof = wizard.Prove(k.comp, prover)
+	}, "the prover panicked, scenario=%v seed=%v", scenario, seed)
+
+	require.NoErrorf(t, errRef, "the reference check failed, scenario=%v seed=%v", scenario, seed)
+	require.NoErrorf(t, wizard.Verify(k.comp, proof), "the proof does not verify, scenario=%v seed=%v", scenario, seed)
+}
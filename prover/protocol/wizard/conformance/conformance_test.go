@@ -0,0 +1,75 @@
+package conformance_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard/conformance"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/stretchr/testify/require"
+)
+
+const size = 8
+
+// maskedModule is a toy module whose column A must be zero on the inactive
+// rows.
+func maskedModule(numActive *int) conformance.Module {
+
+	var a, isActive ifaces.Column
+
+	define := func(b *wizard.Builder) {
+		a = b.RegisterCommit("A", size)
+		isActive = b.RegisterCommit("IS_ACTIVE", size)
+		b.GlobalConstraint("IS_ACTIVE_IS_BINARY", symbolic.Mul(isActive, symbolic.Sub(isActive, 1)))
+		b.GlobalConstraint("A_IS_MASKED", symbolic.Mul(a, symbolic.Sub(1, isActive)))
+	}
+
+	assign := func(run *wizard.ProverRuntime, scenario conformance.Scenario, rng *rand.Rand) {
+
+		n := 0
+		switch scenario {
+		case conformance.MaxCapacity:
+			n = size
+		case conformance.Fuzz:
+			n = rng.Intn(size + 1)
+		}
+
+		aWit := make([]field.Element, size)
+		isActiveWit := make([]field.Element, size)
+		for i := 0; i < n; i++ {
+			aWit[i].SetUint64(rng.Uint64())
+			isActiveWit[i].SetOne()
+		}
+
+		run.AssignColumn(a.GetColID(), smartvectors.NewRegular(aWit))
+		run.AssignColumn(isActive.GetColID(), smartvectors.NewRegular(isActiveWit))
+		*numActive = n
+	}
+
+	return conformance.Module{Define: define, Assign: assign}
+}
+
+func TestConformanceKit(t *testing.T) {
+
+	var (
+		numActive int
+		seen      = map[int]bool{}
+		module    = maskedModule(&numActive)
+	)
+
+	module.Reference = func(run *wizard.ProverRuntime) error {
+		seen[numActive] = true
+		return nil
+	}
+
+	kit := conformance.New(t, module, conformance.WithNumFuzz(8), conformance.WithSeed(42))
+	kit.Run(t)
+
+	// The zero-activity and the max-capacity scenarios are both covered
+	require.True(t, seen[0])
+	require.True(t, seen[size])
+}
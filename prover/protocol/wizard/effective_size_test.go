@@ -0,0 +1,47 @@
+package wizard_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignColumnEffectiveSize(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("EFF_A", 8)
+		b.Columns.SetEffectiveSize("EFF_A", 3)
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+
+	t.Run("padded-suffix", func(t *testing.T) {
+		var assigned smartvectors.SmartVector
+		proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) {
+			run.AssignColumn("EFF_A", smartvectors.ForTest(1, 2, 3, 7, 7, 7, 7, 7))
+			assigned = run.GetColumn("EFF_A")
+		})
+		require.NoError(t, wizard.Verify(comp, proof))
+
+		// The assignment is stored without its suffix but keeps its values
+		_, isRegular := assigned.(*smartvectors.Regular)
+		assert.False(t, isRegular)
+		assert.Equal(t, 8, assigned.Len())
+		for i, v := range []uint64{1, 2, 3, 7, 7, 7, 7, 7} {
+			assert.Equal(t, field.NewElement(v), assigned.Get(i))
+		}
+	})
+
+	t.Run("non-constant-suffix", func(t *testing.T) {
+		err := proveWithError(comp, func(run *wizard.ProverRuntime) {
+			run.AssignColumn("EFF_A", smartvectors.ForTest(1, 2, 3, 7, 7, 8, 7, 7))
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "effective size")
+	})
+}
@@ -0,0 +1,10 @@
+package wizard
+
+// DetectIDCollisions enables the detection of the IDs defined twice, which is
+// otherwise only enabled in the debug builds, for the IOPs built until the
+// returned function is called.
+func DetectIDCollisions() (restore func()) {
+	prev := detectIDCollisions
+	detectIDCollisions = true
+	return func() { detectIDCollisions = prev }
+}
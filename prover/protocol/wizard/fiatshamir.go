@@ -35,6 +35,7 @@ func (comp *CompiledIOP) BootstrapFiatShamir(vm VersionMetadata, ser CompiledIOP
 	digest := hasher.Sum(nil)
 	digest[0] = 0 // This is to prevent potential errors due to overflowing the field
 	comp.fiatShamirSetup.SetBytes(digest)
+	comp.versionMetadata = vm
 
 	return comp
 }
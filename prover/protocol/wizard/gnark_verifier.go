@@ -80,6 +80,11 @@ type WizardVerifierCircuit struct {
 	// hashes but also the MiMC Vortex column hashes that we use for the
 	// last round of the self-recursion.
 	HasherFactory *gkrmimc.HasherFactory `gnark:"-"`
+
+	// accessorCache memoizes the values of the accessors evaluated by the
+	// verifier steps so that their constraints are emitted only once. It is
+	// renewed by [WizardVerifierCircuit.Verify].
+	accessorCache *accessorCache[frontend.Variable] `gnark:"-"`
 }
 
 // AllocateWizardCircuit allocates the inner-slices of the verifier struct from a precompiled IOP. It
@@ -152,16 +157,18 @@ func AllocateWizardCircuit(comp *CompiledIOP) (*WizardVerifierCircuit, error) {
 // [frontend.Define] function. Its work mirrors the [Verify] function.
 func (c *WizardVerifierCircuit) Verify(api frontend.API) {
 	c.HasherFactory = gkrmimc.NewHasherFactory(api)
+	c.accessorCache = newAccessorCache[frontend.Variable]()
 	c.FS = fiatshamir.NewGnarkFiatShamir(api, c.HasherFactory)
 	c.FS.Update(c.Spec.fiatShamirSetup)
 	c.generateAllRandomCoins(api)
 
 	logrus.Tracef("Generated the coins")
 
-	for _, roundSteps := range c.Spec.gnarkSubVerifiers.Inner() {
+	for round, roundSteps := range c.Spec.gnarkSubVerifiers.Inner() {
 		for _, step := range roundSteps {
 			step(api, c)
 		}
+		c.runOrderedVerifierActions(api, round)
 	}
 }
 
@@ -0,0 +1,51 @@
+package wizard_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryIDCollision(t *testing.T) {
+
+	defer wizard.DetectIDCollisions()()
+
+	define := func(b *wizard.Builder) {
+		p := b.RegisterCommit("P", SIZE)
+		b.GlobalConstraint("DUPLICATE", symbolic.Mul(p, p))
+		// A query with parameters reusing the ID of a query without
+		// parameters is stored in another register.
+		b.CompiledIOP.InsertUnivariate(0, "DUPLICATE", []ifaces.Column{p})
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		require.True(t, ok, "the compilation should panic with an error")
+		// Both definitions are located in the module, not in the wizard
+		require.Regexp(t, `query "DUPLICATE" is defined twice: at .*ids_test.go:\d+ and at .*ids_test.go:\d+`, err.Error())
+	}()
+
+	wizard.Compile(define)
+}
+
+func TestInvalidIDs(t *testing.T) {
+
+	for _, name := range []string{"", " P", "P\n"} {
+
+		require.Panics(t, func() {
+			wizard.Compile(func(b *wizard.Builder) {
+				b.RegisterCommit(ifaces.ColID(name), SIZE)
+			})
+		}, "column %q", name)
+
+		require.Panics(t, func() {
+			wizard.Compile(func(b *wizard.Builder) {
+				p := b.RegisterCommit("P", SIZE)
+				b.GlobalConstraint(ifaces.QueryID(name), symbolic.Mul(p, p))
+			})
+		}, "query %q", name)
+	}
+}
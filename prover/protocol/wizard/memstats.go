@@ -0,0 +1,224 @@
+package wizard
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"text/tabwriter"
+)
+
+// Names of the [runtime/metrics] read to measure the memory of the prover
+const (
+	metricHeapObjects  = "/memory/classes/heap/objects:bytes"
+	metricHeapLive     = "/gc/heap/live:bytes"
+	metricGCCycles     = "/gc/cycles/total:gc-cycles"
+	metricAllocBytes   = "/gc/heap/allocs:bytes"
+	metricAllocsBySize = "/gc/heap/allocs-by-size:bytes"
+)
+
+// RoundMemStats are the memory measurements of the prover for one round of
+// the protocol. The figures of a round include the prover steps of the round
+// and the update of the Fiat-Shamir state with the messages of the round.
+type RoundMemStats struct {
+	Round int
+	// HeapInUsePeak is the high-water mark of the heap occupied by objects,
+	// live or not yet swept, sampled after every prover step of the round.
+	// The samples are taken at the step boundaries so that two runs of the
+	// same protocol sample the same points.
+	HeapInUsePeak uint64
+	// LiveHeap is the heap still reachable at the end of the round. It is
+	// measured after a forced garbage collection and thus does not depend on
+	// the GC pacing.
+	LiveHeap uint64
+	// GCCycles is the number of garbage collections during the round,
+	// including the one forced to measure LiveHeap.
+	GCCycles uint64
+	// AllocBytes is the cumulated size of the heap allocations of the round
+	AllocBytes uint64
+	// LargeAllocs is the number of allocations of the round larger than the
+	// largest size class of the Go allocator (32 KiB). They are the ones
+	// worth tracking down when the round regresses.
+	LargeAllocs uint64
+}
+
+// MemoryReport collects the per-round memory measurements of [Prove], see
+// [WithMemoryReport].
+type MemoryReport struct {
+	Rounds []RoundMemStats
+}
+
+// WithMemoryReport makes [Prove] measure the memory of every round of the
+// prover and append the measurements to report. This attributes a memory
+// regression to the round, and thus to the compiler, that introduced it.
+//
+// The option forces a garbage collection at the end of every round, which
+// slows down the prover. It is meant for profiling and benchmarking.
+func WithMemoryReport(report *MemoryReport) ProveOption {
+	return func(run *ProverRuntime) {
+		run.memTracker = newMemTracker(report)
+	}
+}
+
+// WriteTo writes the report as a table, one line per round
+func (r *MemoryReport) WriteTo(w io.Writer) (int64, error) {
+
+	var (
+		cw = &countingWriter{w: w}
+		tw = tabwriter.NewWriter(cw, 0, 0, 2, ' ', tabwriter.AlignRight)
+	)
+
+	fmt.Fprintln(tw, "round\theap in use (peak)\tlive heap\tallocated\tlarge allocs\tgc cycles\t")
+	for _, s := range r.Rounds {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t\n",
+			s.Round, formatBytes(s.HeapInUsePeak), formatBytes(s.LiveHeap),
+			formatBytes(s.AllocBytes), s.LargeAllocs, s.GCCycles)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+// memTracker measures the memory of the prover for a [MemoryReport]
+type memTracker struct {
+	report *MemoryReport
+	// lock protects the sampling as the steps of a round may run in
+	// parallel.
+	lock    sync.Mutex
+	samples []metrics.Sample
+	// start holds the cumulative counters at the beginning of the round
+	start memCounters
+	peak  uint64
+}
+
+// memCounters are the cumulative counters of the runtime, read to compute
+// the per-round deltas
+type memCounters struct {
+	gcCycles, allocBytes, largeAllocs uint64
+}
+
+func newMemTracker(report *MemoryReport) *memTracker {
+	t := &memTracker{
+		report: report,
+		samples: []metrics.Sample{
+			{Name: metricHeapObjects},
+			{Name: metricHeapLive},
+			{Name: metricGCCycles},
+			{Name: metricAllocBytes},
+			{Name: metricAllocsBySize},
+		},
+	}
+	t.start = t.read()
+	return t
+}
+
+// read reads the metrics and returns the cumulative counters. It also
+// updates the peak of the heap in use. It must be called with the lock held.
+func (t *memTracker) read() memCounters {
+
+	metrics.Read(t.samples)
+
+	if heap := t.samples[0].Value.Uint64(); heap > t.peak {
+		t.peak = heap
+	}
+
+	res := memCounters{
+		gcCycles:   t.samples[2].Value.Uint64(),
+		allocBytes: t.samples[3].Value.Uint64(),
+	}
+
+	// The large objects fall in the buckets extending past the largest size
+	// class, that is the last one.
+	hist := t.samples[4].Value.Float64Histogram()
+	for i := range hist.Counts {
+		if math.IsInf(hist.Buckets[i+1], 1) {
+			res.largeAllocs += hist.Counts[i]
+		}
+	}
+
+	return res
+}
+
+// sample updates the peak of the heap in use. It is called after every
+// prover step.
+func (t *memTracker) sample() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.read()
+}
+
+// endRound appends the measurements of the round to the report and starts
+// measuring the next round.
+func (t *memTracker) endRound(round int) {
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Sample before collecting, to account for the garbage of the last step
+	t.read()
+	runtime.GC()
+	end := t.read()
+
+	t.report.Rounds = append(t.report.Rounds, RoundMemStats{
+		Round:         round,
+		HeapInUsePeak: t.peak,
+		LiveHeap:      t.samples[1].Value.Uint64(),
+		GCCycles:      end.gcCycles - t.start.gcCycles,
+		AllocBytes:    end.allocBytes - t.start.allocBytes,
+		LargeAllocs:   end.largeAllocs - t.start.largeAllocs,
+	})
+
+	t.start = end
+	t.peak = t.samples[0].Value.Uint64()
+}
+
+// sampleMemory samples the memory if the prover runs with
+// [WithMemoryReport].
+func (run *ProverRuntime) sampleMemory() {
+	if run.memTracker != nil {
+		run.memTracker.sample()
+	}
+}
+
+// endRoundMemory closes the memory measurements of the current round if the
+// prover runs with [WithMemoryReport].
+func (run *ProverRuntime) endRoundMemory() {
+	if run.memTracker != nil {
+		run.memTracker.endRound(run.currRound)
+	}
+}
+
+// formatBytes formats a number of bytes with a binary unit
+func formatBytes(n uint64) string {
+	const unit = 1 << 10
+	if n < unit {
+		return fmt.Sprintf("%v B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// countingWriter counts the bytes written to w, for [io.WriterTo]
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
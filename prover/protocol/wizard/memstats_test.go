@@ -0,0 +1,52 @@
+package wizard_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryReport(t *testing.T) {
+
+	const allocSize = 1 << 26
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("MEM_A", SIZE)
+		b.RegisterRandomCoin("MEM_COIN", coin.Field)
+		b.RegisterProverAction(1, proverActionFunc(func(run *wizard.ProverRuntime) {
+			// Kept alive until the end of the round through the state
+			run.State.InsertNew("MEM_BUF", make([]byte, allocSize))
+		}))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+
+	report := &wizard.MemoryReport{}
+	wizard.Prove(comp, func(run *wizard.ProverRuntime) {
+		run.AssignColumn("MEM_A", smartvectors.ForTest(1, 2, 3, 4))
+	}, wizard.WithMemoryReport(report))
+
+	require.Len(t, report.Rounds, 2)
+
+	r0, r1 := report.Rounds[0], report.Rounds[1]
+	require.Equal(t, 0, r0.Round)
+	require.Equal(t, 1, r1.Round)
+
+	// The allocation of round 1 is attributed to round 1
+	require.GreaterOrEqual(t, r1.AllocBytes, uint64(allocSize))
+	require.GreaterOrEqual(t, r1.LargeAllocs, uint64(1))
+	require.GreaterOrEqual(t, r1.HeapInUsePeak, uint64(allocSize))
+	require.GreaterOrEqual(t, r1.LiveHeap, uint64(allocSize))
+	require.GreaterOrEqual(t, r1.GCCycles, uint64(1))
+	require.Less(t, r0.AllocBytes, uint64(allocSize))
+
+	var sb strings.Builder
+	_, err := report.WriteTo(&sb)
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(sb.String(), "\n"))
+}
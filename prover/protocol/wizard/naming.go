@@ -0,0 +1,266 @@
+package wizard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// NameIssueKind indicates the kind of ambiguity reported by [AuditNames].
+type NameIssueKind int
+
+const (
+	// NearDuplicateName flags names that only differ by their case or by
+	// their separators, e.g. "FOO_BAR" and "foo.bar".
+	NearDuplicateName NameIssueKind = iota
+	// TruncatedName flags a name that is a prefix of another one and where
+	// the cut happens in the middle of a word, e.g. "FOO_ACC" and
+	// "FOO_ACCUMULATOR". This is typically caused by a name that has been
+	// truncated when it was generated.
+	TruncatedName
+)
+
+func (k NameIssueKind) String() string {
+	switch k {
+	case NearDuplicateName:
+		return "near-duplicate"
+	case TruncatedName:
+		return "truncated"
+	default:
+		return fmt.Sprintf("NameIssueKind(%d)", int(k))
+	}
+}
+
+// NameNamespace indicates whether a [NameIssue] relates to columns or to
+// queries. The two namespaces are audited separately.
+type NameNamespace string
+
+const (
+	ColumnNamespace NameNamespace = "column"
+	QueryNamespace  NameNamespace = "query"
+)
+
+// NameIssue is an ambiguity between names of the same namespace reported by
+// [AuditNames].
+type NameIssue struct {
+	Kind      NameIssueKind
+	Namespace NameNamespace
+	// Names lists the conflicting names. For a [TruncatedName] issue, the
+	// first name is the truncated one and the next ones are the names it is a
+	// prefix of.
+	Names []string
+	// Modules lists the module of each name, see [ModuleOf].
+	Modules []string
+	// CrossModule is true if the names do not all belong to the same module.
+	// These are the issues that matter the most as they are the ones that
+	// stricter namespacing would not resolve.
+	CrossModule bool
+}
+
+func (i NameIssue) String() string {
+	scope := "same module"
+	if i.CrossModule {
+		scope = "cross-module"
+	}
+	return fmt.Sprintf("%v %v names (%v): %v", i.Kind, i.Namespace, scope, strings.Join(i.Names, ", "))
+}
+
+// ModuleOf returns the module a column or a query belongs to. By convention,
+// it is the prefix of the name up to the first "." or "_".
+func ModuleOf(name string) string {
+	if i := strings.IndexAny(name, "._"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// AuditNames scans the names of the columns and of the queries of comp and
+// reports the near-duplicate and the truncated names, see [NameIssueKind].
+// The aliases declared with [CompiledIOP.RenameColumns] are not audited. The
+// issues are returned in deterministic order: by namespace, then by kind and
+// then by name.
+func AuditNames(comp *CompiledIOP) []NameIssue {
+
+	var (
+		cols    = comp.Columns.AllKeys()
+		queries = append(comp.QueriesNoParams.AllKeys(), comp.QueriesParams.AllKeys()...)
+		colStr  = make([]string, len(cols))
+		qStr    = make([]string, len(queries))
+	)
+
+	for i := range cols {
+		colStr[i] = string(cols[i])
+	}
+
+	for i := range queries {
+		qStr[i] = string(queries[i])
+	}
+
+	res := auditNamespace(ColumnNamespace, colStr)
+	return append(res, auditNamespace(QueryNamespace, qStr)...)
+}
+
+// auditNamespace returns the issues found among a list of distinct names.
+func auditNamespace(ns NameNamespace, names []string) []NameIssue {
+
+	names = append([]string{}, names...)
+	sort.Strings(names)
+
+	var (
+		res          = []NameIssue{}
+		byNormalized = map[string][]string{}
+		normalized   = []string{}
+	)
+
+	for _, name := range names {
+		norm := normalizeName(name)
+		if _, ok := byNormalized[norm]; !ok {
+			normalized = append(normalized, norm)
+		}
+		byNormalized[norm] = append(byNormalized[norm], name)
+	}
+
+	for _, norm := range normalized {
+		if group := byNormalized[norm]; len(group) > 1 {
+			res = append(res, newNameIssue(NearDuplicateName, ns, group))
+		}
+	}
+
+	// As the names are sorted, the names that a name is a prefix of are all
+	// located right after it.
+	for i, short := range names {
+		group := []string{short}
+		for j := i + 1; j < len(names) && strings.HasPrefix(names[j], short); j++ {
+			if isMidWordCut(short, names[j]) {
+				group = append(group, names[j])
+			}
+		}
+		if len(group) > 1 {
+			res = append(res, newNameIssue(TruncatedName, ns, group))
+		}
+	}
+
+	return res
+}
+
+func newNameIssue(kind NameIssueKind, ns NameNamespace, names []string) NameIssue {
+	issue := NameIssue{
+		Kind:      kind,
+		Namespace: ns,
+		Names:     names,
+		Modules:   make([]string, len(names)),
+	}
+	for i := range names {
+		issue.Modules[i] = ModuleOf(names[i])
+		issue.CrossModule = issue.CrossModule || issue.Modules[i] != issue.Modules[0]
+	}
+	return issue
+}
+
+// normalizeName lowercases the name and strips everything that is not a
+// letter or a digit.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// isMidWordCut returns true if short is a strict prefix of long and if the
+// cut falls between two letters or between a letter and a digit. A cut
+// between two digits is not reported as it is the normal outcome of indexing
+// the columns, e.g. "COL_1" and "COL_10".
+func isMidWordCut(short, long string) bool {
+
+	if len(short) == 0 || len(short) >= len(long) {
+		return false
+	}
+
+	var (
+		before = rune(short[len(short)-1])
+		after  = rune(long[len(short)])
+		alnum  = func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	)
+
+	if !alnum(before) || !alnum(after) {
+		return false
+	}
+
+	return !(unicode.IsDigit(before) && unicode.IsDigit(after))
+}
+
+// RenameColumns declares that the columns registered from now on under one of
+// the keys of renames are to be registered under the corresponding value
+// instead. The former name is kept as an alias of the column (see
+// [column.Store.AddAlias]), so that it can still be used to look up the
+// column or its assignment. This allows migrating the names of the columns
+// of a module, for instance to fix the issues reported by [AuditNames],
+// without touching the code defining the module.
+//
+// The function must be called before the columns are registered, i.e. before
+// the module is defined. It panics if one of the former names is already
+// registered, if it was already renamed or if one of the new names is itself
+// renamed.
+func (c *CompiledIOP) RenameColumns(renames map[ifaces.ColID]ifaces.ColID) {
+
+	if c.columnRenames == nil {
+		c.columnRenames = map[ifaces.ColID]ifaces.ColID{}
+	}
+
+	for old, new := range renames {
+
+		if len(new) == 0 {
+			utils.Panic("can't rename %v to an empty name", old)
+		}
+
+		if c.Columns.Exists(old) {
+			utils.Panic("can't rename %v because it is already registered", old)
+		}
+
+		if prev, ok := c.columnRenames[old]; ok {
+			utils.Panic("can't rename %v to %v because it is already renamed to %v", old, new, prev)
+		}
+
+		c.columnRenames[old] = new
+	}
+
+	for old, new := range c.columnRenames {
+		if _, ok := c.columnRenames[new]; ok {
+			utils.Panic("can't rename %v to %v because %v is itself renamed", old, new, new)
+		}
+	}
+}
+
+// renamedColumn returns the name under which a column declared as name must be
+// registered, see [CompiledIOP.RenameColumns].
+func (c *CompiledIOP) renamedColumn(name ifaces.ColID) ifaces.ColID {
+	if new, ok := c.columnRenames[name]; ok {
+		return new
+	}
+	return name
+}
+
+// addColumn registers the column in the store under its new name, if it is
+// renamed, and declares the former name as an alias.
+func (c *CompiledIOP) addColumn(round int, name ifaces.ColID, size int, status column.Status) ifaces.Column {
+
+	if _, err := ifaces.NewColID(string(name)); err != nil {
+		utils.Panic("%v", err)
+	}
+
+	newName := c.renamedColumn(name)
+	col := c.Columns.AddToRound(round, newName, size, status)
+
+	if newName != name {
+		c.Columns.AddAlias(name, newName)
+	}
+
+	return col
+}
@@ -0,0 +1,83 @@
+package wizard_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditNames(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		var (
+			a = b.RegisterCommit("HUB_STAMP", 8)
+			_ = b.RegisterCommit("hub.stamp", 8)
+			_ = b.RegisterCommit("ROM_ACC", 8)
+			_ = b.RegisterCommit("ROM_ACCUMULATOR", 8)
+			_ = b.RegisterCommit("MMU_ACCUMULATOR", 8)
+			_ = b.RegisterCommit("COL_1", 8)
+			_ = b.RegisterCommit("COL_10", 8)
+			_ = b.RegisterCommit("COL_1_X", 8)
+		)
+		b.GlobalConstraint("HUB_GLOBAL", sym.NewVariable(a))
+		b.GlobalConstraint("ROM_GLOBAL", sym.NewVariable(a))
+		b.GlobalConstraint("rom.global", sym.NewVariable(a))
+	}
+
+	comp := wizard.Compile(define)
+	issues := wizard.AuditNames(comp)
+
+	require.Len(t, issues, 3)
+
+	assert.Equal(t, wizard.NearDuplicateName, issues[0].Kind)
+	assert.Equal(t, wizard.ColumnNamespace, issues[0].Namespace)
+	assert.Equal(t, []string{"HUB_STAMP", "hub.stamp"}, issues[0].Names)
+	assert.True(t, issues[0].CrossModule)
+
+	assert.Equal(t, wizard.TruncatedName, issues[1].Kind)
+	assert.Equal(t, []string{"ROM_ACC", "ROM_ACCUMULATOR"}, issues[1].Names)
+	assert.False(t, issues[1].CrossModule)
+
+	assert.Equal(t, wizard.NearDuplicateName, issues[2].Kind)
+	assert.Equal(t, wizard.QueryNamespace, issues[2].Namespace)
+	assert.Equal(t, []string{"ROM_GLOBAL", "rom.global"}, issues[2].Names)
+}
+
+func TestRenameColumns(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		b.RenameColumns(map[ifaces.ColID]ifaces.ColID{"ROM_ACC": "ROM_ACCUMULATOR"})
+		a := b.RegisterCommit("ROM_ACC", 8)
+		b.GlobalConstraint("ROM_GLOBAL", sym.Sub(a, 1))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+
+	assert.True(t, comp.Columns.Exists("ROM_ACCUMULATOR"))
+	assert.True(t, comp.Columns.Exists("ROM_ACC"))
+	assert.Equal(t, []ifaces.ColID{"ROM_ACCUMULATOR"}, comp.Columns.AllKeys())
+	assert.Equal(t, ifaces.ColID("ROM_ACCUMULATOR"), comp.Columns.GetHandle("ROM_ACC").GetColID())
+	assert.Empty(t, wizard.AuditNames(comp))
+
+	proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) {
+		// The former name is still accepted
+		run.AssignColumn("ROM_ACC", smartvectors.NewConstant(field.One(), 8))
+		assert.Equal(t, run.GetColumn("ROM_ACC"), run.GetColumn("ROM_ACCUMULATOR"))
+	})
+
+	require.NoError(t, wizard.Verify(comp, proof))
+
+	assert.Panics(t, func() {
+		wizard.Compile(func(b *wizard.Builder) {
+			b.RegisterCommit("ROM_ACC", 8)
+			b.RenameColumns(map[ifaces.ColID]ifaces.ColID{"ROM_ACC": "ROM_ACCUMULATOR"})
+		})
+	}, "renaming a registered column")
+}
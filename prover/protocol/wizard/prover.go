@@ -2,6 +2,7 @@ package wizard
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
@@ -12,6 +13,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/query"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/consensys/linea-monorepo/prover/utils/watchdog"
 	"github.com/sirupsen/logrus"
 )
 
@@ -102,6 +104,11 @@ type ProverRuntime struct {
 	// take care of deleting the entry to free memory when he knows that the
 	// field will not be accessed again while proving.
 	//
+	// The prover steps should go through [ProverRuntime.InsertState],
+	// [ProverRuntime.GetState], [ProverRuntime.TryGetState] and
+	// [ProverRuntime.DelState] that hold the lock of the runtime, as the
+	// steps of a round may run concurrently.
+	//
 	// The State is used internally by the [github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex] and the
 	// [github.com/consensys/linea-monorepo/prover/protocol/compiler/selfrecursion] compilers as a communication channel.
 	State collection.Mapping[string, interface{}]
@@ -120,6 +127,51 @@ type ProverRuntime struct {
 
 	// lock is global lock so that the assignment maps are thread safes
 	lock *sync.Mutex
+
+	// lastAssigned is the ID of the last column or query assigned. It is
+	// reported in the [ProverError] raised when a prover step panics.
+	lastAssigned *atomic.Pointer[string]
+
+	// debugOpenings retains the assignments of the columns when the prover
+	// runs with [WithDebugOpenings], nil otherwise. debugFilter restricts
+	// them to a set of columns, nil meaning all the columns.
+	debugOpenings map[ifaces.ColID]ifaces.ColAssignment
+	debugFilter   map[ifaces.ColID]struct{}
+
+	// memTracker measures the memory of every round when the prover runs
+	// with [WithMemoryReport], nil otherwise.
+	memTracker *memTracker
+
+	// checkpointSink receives the changes of the runtime at the end of every
+	// round, see [WithCheckpoints], and checkpoints tracks what was already
+	// passed to it. resumeFrom is the chain of checkpoints the prover resumes
+	// from, see [ResumeFrom].
+	checkpointSink func(*Checkpoint) error
+	checkpoints    *checkpointTracker
+	resumeFrom     []*Checkpoint
+}
+
+// ProveOption changes the behaviour of [Prove]
+type ProveOption func(run *ProverRuntime)
+
+// WithDebugOpenings makes [Prove] retain the assignment of the columns in the
+// [Proof.DebugOpenings] field of the returned proof, so that they can be
+// opened at any row after the fact, for instance when the verifier rejects the
+// proof. See [github.com/consensys/linea-monorepo/prover/protocol/accessors.OpenDebugColumn].
+// If no column is given, all the columns are retained, including the ones
+// that the compilers delete once they are no longer needed. This keeps the
+// whole witness in memory and is meant for debugging only.
+func WithDebugOpenings(cols ...ifaces.ColID) ProveOption {
+	return func(run *ProverRuntime) {
+		run.debugOpenings = map[ifaces.ColID]ifaces.ColAssignment{}
+		if len(cols) == 0 {
+			return
+		}
+		run.debugFilter = make(map[ifaces.ColID]struct{}, len(cols))
+		for _, col := range cols {
+			run.debugFilter[col] = struct{}{}
+		}
+	}
 }
 
 // Prove is the top-level function that runs the Prover on the user's side. It
@@ -142,23 +194,43 @@ type ProverRuntime struct {
 // auto-detection adds little value and adds a lot of convolution especially
 // when the specified protocol is complicated and involves multiple multi-rounds
 // sub-protocols that runs independently.
-func Prove(c *CompiledIOP, highLevelprover ProverStep) Proof {
+//
+// If any of the prover steps panics, the function panics with a [ProverError]
+// describing the step, see [RecoverProverError].
+func Prove(c *CompiledIOP, highLevelprover ProverStep, opts ...ProveOption) Proof {
 	runtime := c.createProver()
-	/*
-		Run the user provided assignment function. We can't expect it
-		to run all the rounds, because the compilation could have added
-		extra-rounds.
-	*/
-	highLevelprover(&runtime)
+	for _, opt := range opts {
+		opt(&runtime)
+	}
+	for key, val := range c.Precomputed.InnerMap() {
+		runtime.retainDebugOpening(key, val)
+	}
+
+	if runtime.resumeFrom != nil {
+		// The rounds up to the one of the checkpoint are already done
+		runtime.restore(runtime.resumeFrom)
+	} else {
+		/*
+			Run the user provided assignment function. We can't expect it
+			to run all the rounds, because the compilation could have added
+			extra-rounds.
+		*/
+		runtime.runStep("main", highLevelprover)
+
+		/*
+			Then, run the compiled prover steps
+		*/
+		runtime.runProverSteps()
+	}
 
-	/*
-		Then, run the compiled prover steps
-	*/
-	runtime.runProverSteps()
 	for runtime.currRound+1 < runtime.NumRounds() {
+		runtime.checkpoint()
 		runtime.goNextRound()
 		runtime.runProverSteps()
 	}
+	// The last round has no Fiat-Shamir update
+	runtime.endRoundMemory()
+	runtime.waitCheckpoint()
 
 	/*
 		Pass all the prover message columns as part of the proof
@@ -176,12 +248,35 @@ func Prove(c *CompiledIOP, highLevelprover ProverStep) Proof {
 		messages.InsertNew(name, messageValue)
 	}
 
+	// The columns inserted without AssignColumn are only retained if they
+	// are still in the runtime.
+	if runtime.debugOpenings != nil {
+		for name, val := range runtime.Columns.InnerMap() {
+			if _, ok := runtime.debugOpenings[name]; !ok {
+				runtime.retainDebugOpening(name, val)
+			}
+		}
+	}
+
 	return Proof{
 		Messages:      messages,
 		QueriesParams: runtime.QueriesParams,
+		DebugOpenings: runtime.debugOpenings,
 	}
 }
 
+// retainDebugOpening keeps the assignment of a column in the debug openings
+// if the prover runs with [WithDebugOpenings].
+func (run *ProverRuntime) retainDebugOpening(name ifaces.ColID, witness ifaces.ColAssignment) {
+	if run.debugOpenings == nil {
+		return
+	}
+	if _, ok := run.debugFilter[name]; run.debugFilter != nil && !ok {
+		return
+	}
+	run.debugOpenings[name] = witness
+}
+
 // NumRounds returns the total number of rounds in the corresponding WizardIOP.
 //
 // Deprecated: this method does not bring anything useful as its already easy
@@ -212,6 +307,8 @@ func (c *CompiledIOP) createProver() ProverRuntime {
 		FS:            fs,
 		currRound:     0,
 		lock:          &sync.Mutex{},
+		lastAssigned:  &atomic.Pointer[string]{},
+		checkpoints:   newCheckpointTracker(c),
 	}
 
 	// Pass the precomputed polynomials
@@ -223,9 +320,8 @@ func (c *CompiledIOP) createProver() ProverRuntime {
 }
 
 // GetColumn implements `ifaces.Runtime`. Returns a column witness, that has been
-// previously stored. It is a deep-copy operation. And thus, it guarantees that
-// the stored witness cannot be accidentally mutated by the caller as a side
-// effect.
+// previously stored. The witness is returned without being copied: the caller
+// must not mutate it as this would also mutate the stored assignment.
 //
 // Something to note however, is that the function will panic if the
 // the provided name does not exists explictly in the [ProverRuntime.Columns]
@@ -255,7 +351,7 @@ func (run ProverRuntime) GetColumn(name ifaces.ColID) ifaces.ColAssignment {
 		expected behaviour.
 	*/
 	run.Spec.Columns.MustHaveName(name)
-	res := run.Columns.MustGet(name)
+	res := run.Columns.MustGet(run.Spec.Columns.Canonical(name))
 	return res
 }
 
@@ -334,6 +430,9 @@ func (run *ProverRuntime) GetRandomCoinIntegerVec(name coin.Name) []int {
 // [CompiledIOP.InsertColumn], [CompiledIOP.InsertCommit] or
 // [CompiledIOP.InsertProof] or even [CompiledIOP.InsertPublicInput].
 //
+// The witness is stored without being copied. Thus, assigning a column with
+// the assignment of another column makes both columns share the same memory.
+//
 // The function will panic if
 //   - an empty column name is provided
 //   - the column is not explictly registered in the CompiledIOP (e.g. if it is
@@ -378,8 +477,52 @@ func (run *ProverRuntime) AssignColumn(name ifaces.ColID, witness ifaces.ColAssi
 		utils.Panic("Witness with non-power of two sizes, should have been caught earlier")
 	}
 
+	// The columns declared with a padded suffix are checked to be constant
+	// past their effective size and stored without their suffix.
+	if run.Spec.Columns.HasPaddedSuffix(name) {
+		witness = compactPaddedSuffix(name, witness, run.Spec.Columns.EffectiveSize(name))
+	}
+
 	// Adds it to the assignments
 	run.Columns.InsertNew(handle.GetColID(), witness)
+	run.retainDebugOpening(handle.GetColID(), witness)
+	run.markAssigned(string(handle.GetColID()))
+}
+
+// compactPaddedSuffix checks that the witness is constant from position
+// effectiveSize onward and, if the witness is a regular vector, returns it as
+// a right-padded vector so that the compilers handling padded vectors (e.g.
+// the splitter and Vortex) can skip the suffix. Panics if the suffix is not
+// constant as this means the effective size declared for the column is
+// wrong.
+func compactPaddedSuffix(name ifaces.ColID, witness ifaces.ColAssignment, effectiveSize int) ifaces.ColAssignment {
+
+	if _, ok := witness.(*smartvectors.Constant); ok {
+		return witness
+	}
+
+	var (
+		n       = witness.Len()
+		padding = witness.Get(effectiveSize)
+	)
+
+	for i := effectiveSize + 1; i < n; i++ {
+		if x := witness.Get(i); !x.Equal(&padding) {
+			utils.Panic(
+				"the assignment of %v is not constant past its effective size %v: position %v is %v but the padding is %v",
+				name, effectiveSize, i, x.String(), padding.String(),
+			)
+		}
+	}
+
+	reg, ok := witness.(*smartvectors.Regular)
+	if !ok {
+		return witness
+	}
+
+	prefix := make([]field.Element, effectiveSize)
+	copy(prefix, (*reg)[:effectiveSize])
+	return smartvectors.RightPadded(prefix, padding, n)
 }
 
 // getRandomCoinGeneric is an internal utility function that we use when
@@ -522,6 +665,9 @@ func (run *ProverRuntime) goNextRound() {
 		logrus.Debugf("Fiat-shamir round %v - %v query params in the transcript", run.currRound, run.FS.TranscriptSize-start)
 	}
 
+	// The round is over, including the Fiat-Shamir updates
+	run.endRoundMemory()
+
 	// Increment the number of rounds
 	run.currRound++
 
@@ -543,12 +689,23 @@ func (run *ProverRuntime) goNextRound() {
 }
 
 // runProverSteps runs all the [ProverStep] specified in the underlying
-// [CompiledIOP] object for the current round.
+// [CompiledIOP] object for the current round. If some of the steps of the
+// round declared their dependencies, the steps are scheduled concurrently
+// (see [CompiledIOP.RegisterProverActionWithDeps]). Otherwise, they are run
+// sequentially in their order of registration.
 func (run *ProverRuntime) runProverSteps() {
 	// Run all the assigners
 	subProverSteps := run.Spec.SubProvers.MustGet(run.currRound)
-	for _, step := range subProverSteps {
-		step(run)
+
+	if deps, ok := run.Spec.proverStepDeps[run.currRound]; ok && len(subProverSteps) > 1 {
+		run.runProverStepsScheduled(subProverSteps, deps)
+		return
+	}
+
+	for i, step := range subProverSteps {
+		run.lastAssigned.Store(nil)
+		run.runStep(stepName(i, step), step)
+		watchdog.Heartbeat("wizard prover round %v", run.currRound)
 	}
 }
 
@@ -599,6 +756,7 @@ func (run *ProverRuntime) AssignInnerProduct(name ifaces.QueryID, ys ...field.El
 
 	param := query.NewInnerProductParams(ys...)
 	run.QueriesParams.InsertNew(name, param)
+	run.markAssigned(string(name))
 	return param
 }
 
@@ -628,6 +786,7 @@ func (run *ProverRuntime) AssignUnivariate(name ifaces.QueryID, x field.Element,
 	// Adds it to the assignments
 	params := query.NewUnivariateEvalParams(x, ys...)
 	run.QueriesParams.InsertNew(name, params)
+	run.markAssigned(string(name))
 }
 
 // GetUnivariateEval get univariate eval metadata. Panic if not found.
@@ -668,6 +827,7 @@ func (run *ProverRuntime) AssignLocalPoint(name ifaces.QueryID, y field.Element)
 	// Adds it to the assignments
 	params := query.NewLocalOpeningParams(y)
 	run.QueriesParams.InsertNew(name, params)
+	run.markAssigned(string(name))
 }
 
 // GetLocalPointEval gets the metadata of a [query.LocalOpening] query. Panic if not found.
@@ -694,5 +854,77 @@ func (run *ProverRuntime) GetLocalPointEvalParams(name ifaces.QueryID) query.Loc
 // GetParams generically extracts the parameters of a query. Will panic if no
 // parameters are found
 func (run *ProverRuntime) GetParams(name ifaces.QueryID) ifaces.QueryParams {
+
+	// Global prover's lock for accessing params
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
 	return run.QueriesParams.MustGet(name)
 }
+
+// DelColumn frees the assignment of a column that will not be accessed
+// anymore while proving. It does nothing if the column is not assigned.
+func (run *ProverRuntime) DelColumn(name ifaces.ColID) {
+
+	// Global prover's lock for accessing the witnesses
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	run.Columns.TryDel(name)
+}
+
+// TryGetColumn returns the assignment of a column explicitly stored in the
+// runtime and whether it was found. Contrary to [ProverRuntime.GetColumn], it
+// does not panic if the column is not assigned or is not a natural column.
+func (run *ProverRuntime) TryGetColumn(name ifaces.ColID) (ifaces.ColAssignment, bool) {
+
+	// Global prover's lock for accessing the witnesses
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	return run.Columns.TryGet(name)
+}
+
+// InsertState stores a new entry in [ProverRuntime.State] and panics if the
+// key is already used.
+func (run *ProverRuntime) InsertState(key string, value any) {
+
+	// Global prover's lock for accessing the state
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	run.State.InsertNew(key, value)
+}
+
+// GetState returns an entry of [ProverRuntime.State] and panics if it is
+// missing.
+func (run *ProverRuntime) GetState(key string) any {
+
+	// Global prover's lock for accessing the state
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	return run.State.MustGet(key)
+}
+
+// TryGetState returns an entry of [ProverRuntime.State] and whether it was
+// found.
+func (run *ProverRuntime) TryGetState(key string) (any, bool) {
+
+	// Global prover's lock for accessing the state
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	return run.State.TryGet(key)
+}
+
+// DelState deletes an entry of [ProverRuntime.State]. It does nothing if the
+// entry is missing.
+func (run *ProverRuntime) DelState(key string) {
+
+	// Global prover's lock for accessing the state
+	run.lock.Lock()
+	defer run.lock.Unlock()
+
+	run.State.TryDel(key)
+}
@@ -0,0 +1,187 @@
+package wizard
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// modulePrefix is trimmed from the package paths reported in [ProverError]
+const modulePrefix = "github.com/consensys/linea-monorepo/prover/"
+
+// ProverError is the value with which [Prove] panics when one of the prover
+// steps panics, be it through [utils.Panic] or through a runtime error such as
+// an index out of range in an assignment function. It records where the
+// prover stood when the panic occurred so that the failure can be traced back
+// without the job context. The callers that want to surface the failure as an
+// error can recover it with [RecoverProverError].
+type ProverError struct {
+	// Round is the round of the protocol the prover was running
+	Round int
+	// Step identifies the prover step that panicked. It is the position of
+	// the step in the round followed by the name of its function or "main"
+	// for the prover step passed to [Prove].
+	Step string
+	// Module is the package, relative to the repository, of the innermost
+	// function that panicked outside of the wizard runtime.
+	Module string
+	// Location is the file and the line of the same function
+	Location string
+	// LastAssigned is the ID of the last column or query assigned by the step
+	// before the panic, if any. When the steps of the round run concurrently,
+	// it may have been assigned by another step.
+	LastAssigned string
+	// Cause is the value passed to panic
+	Cause any
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+// Error implements the error interface
+func (e *ProverError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "wizard prover panicked at round %v, step %v", e.Round, e.Step)
+	if e.Module != "" {
+		fmt.Fprintf(&sb, ", module %v (%v)", e.Module, e.Location)
+	}
+	if e.LastAssigned != "" {
+		fmt.Fprintf(&sb, ", last assigned %v", e.LastAssigned)
+	}
+	fmt.Fprintf(&sb, ": %v", e.Cause)
+	return sb.String()
+}
+
+// Unwrap returns the cause of the panic if it is an error
+func (e *ProverError) Unwrap() error {
+	if err, ok := e.Cause.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// RecoverProverError is meant to be deferred. It recovers the panics raised
+// with a [ProverError] and stores them in err. Any other panic is propagated.
+//
+//	func prove() (proof Proof, err error) {
+//		defer wizard.RecoverProverError(&err)
+//		return Prove(comp, step), nil
+//	}
+func RecoverProverError(err *error) {
+
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	perr, ok := r.(*ProverError)
+	if !ok {
+		panic(r)
+	}
+
+	*err = perr
+}
+
+// IsProverError returns the [ProverError] wrapped in err, if any
+func IsProverError(err error) (*ProverError, bool) {
+	var perr *ProverError
+	ok := errors.As(err, &perr)
+	return perr, ok
+}
+
+// runStep runs a prover step and converts its panics into a [ProverError]
+// carrying the context of the step. The panics that are already a
+// [ProverError], e.g. raised by a nested prover, are propagated unchanged.
+func (run *ProverRuntime) runStep(name string, step ProverStep) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if perr, ok := r.(*ProverError); ok {
+			panic(perr)
+		}
+		panic(run.newProverError(name, r))
+	}()
+	step(run)
+	run.sampleMemory()
+}
+
+// newProverError builds a [ProverError] from the value passed to panic. It is
+// called from the deferred function of [ProverRuntime.runStep], hence the
+// frames of the panicking function are still on the stack.
+func (run *ProverRuntime) newProverError(step string, cause any) *ProverError {
+
+	perr := &ProverError{
+		Round: run.currRound,
+		Step:  step,
+		Cause: cause,
+		Stack: debug.Stack(),
+	}
+
+	if last := run.lastAssigned.Load(); last != nil {
+		perr.LastAssigned = *last
+	}
+
+	var (
+		pcs    = make([]uintptr, 64)
+		n      = runtime.Callers(3, pcs)
+		frames = runtime.CallersFrames(pcs[:n])
+	)
+
+	for {
+		frame, more := frames.Next()
+		if pkg := funcPackage(frame.Function); !isRuntimePackage(pkg) {
+			perr.Module = strings.TrimPrefix(pkg, modulePrefix)
+			perr.Location = fmt.Sprintf("%v:%v", frame.File, frame.Line)
+			break
+		}
+		if !more {
+			break
+		}
+	}
+
+	return perr
+}
+
+// markAssigned records the ID of the last column or query assigned so that it
+// can be reported if the step panics.
+func (run *ProverRuntime) markAssigned(id string) {
+	run.lastAssigned.Store(&id)
+}
+
+// stepName returns the name of a prover step to report in a [ProverError]
+func stepName(pos int, step ProverStep) string {
+	f := runtime.FuncForPC(reflect.ValueOf(step).Pointer())
+	if f == nil {
+		return fmt.Sprintf("#%v", pos)
+	}
+	return fmt.Sprintf("#%v %v", pos, strings.TrimPrefix(f.Name(), modulePrefix))
+}
+
+// funcPackage returns the package path of a fully qualified function name as
+// reported by [runtime.Frame.Function].
+func funcPackage(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:lastSlash+1+dot]
+}
+
+// isRuntimePackage returns true for the packages whose frames are skipped when
+// looking for the function that panicked: the go runtime, the helpers raising
+// the panics and the wizard runtime itself.
+func isRuntimePackage(pkg string) bool {
+	switch pkg {
+	case "runtime", "runtime/debug",
+		modulePrefix + "utils",
+		modulePrefix + "utils/collection",
+		modulePrefix + "protocol/wizard":
+		return true
+	}
+	return false
+}
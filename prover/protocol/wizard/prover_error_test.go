@@ -0,0 +1,106 @@
+package wizard_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proverActionFunc wraps a function into a [wizard.ProverAction]
+type proverActionFunc func(run *wizard.ProverRuntime)
+
+func (f proverActionFunc) Run(run *wizard.ProverRuntime) {
+	f(run)
+}
+
+// proveWithError runs the prover and returns the [wizard.ProverError] it
+// panicked with, if any.
+func proveWithError(comp *wizard.CompiledIOP, step wizard.ProverStep) (err error) {
+	defer wizard.RecoverProverError(&err)
+	wizard.Prove(comp, step)
+	return nil
+}
+
+func TestProverErrorOutOfRange(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("PERR_A", SIZE)
+		b.RegisterProverAction(0, proverActionFunc(func(run *wizard.ProverRuntime) {
+			run.AssignColumn("PERR_A", smartvectors.ForTest(1, 2, 3, 4))
+			var empty []int
+			_ = empty[len(run.Spec.Columns.AllKeys())]
+		}))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	err := proveWithError(comp, func(run *wizard.ProverRuntime) {})
+	require.Error(t, err)
+
+	perr, ok := wizard.IsProverError(err)
+	require.True(t, ok)
+	assert.Equal(t, 0, perr.Round)
+	assert.Contains(t, perr.Step, "#0 ")
+	assert.Equal(t, "protocol/wizard_test", perr.Module)
+	assert.Contains(t, perr.Location, "prover_error_test.go")
+	assert.Equal(t, "PERR_A", perr.LastAssigned)
+	assert.NotEmpty(t, perr.Stack)
+
+	var rtErr runtime.Error
+	assert.True(t, errors.As(err, &rtErr))
+}
+
+func TestProverErrorMainStep(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("PERR_B", SIZE)
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	err := proveWithError(comp, func(run *wizard.ProverRuntime) {
+		utils.Panic("the witness of %v is missing", "PERR_B")
+	})
+
+	perr, ok := wizard.IsProverError(err)
+	require.True(t, ok)
+	assert.Equal(t, "main", perr.Step)
+	assert.Equal(t, "protocol/wizard_test", perr.Module)
+	assert.Empty(t, perr.LastAssigned)
+	assert.Contains(t, perr.Error(), "the witness of PERR_B is missing")
+}
+
+func TestProverErrorScheduled(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("PERR_C", SIZE)
+		b.RegisterCommit("PERR_D", SIZE)
+		c := b.RegisterProverActionWithDeps(0, proverActionFunc(func(run *wizard.ProverRuntime) {
+			run.AssignColumn("PERR_C", smartvectors.ForTest(1, 2, 3, 4))
+		}))
+		b.RegisterProverActionWithDeps(0, proverActionFunc(func(run *wizard.ProverRuntime) {
+			panic("step D failed")
+		}), c)
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	err := proveWithError(comp, func(run *wizard.ProverRuntime) {})
+
+	perr, ok := wizard.IsProverError(err)
+	require.True(t, ok)
+	assert.Contains(t, perr.Step, "#1 ")
+	assert.Equal(t, "step D failed", perr.Cause)
+}
+
+func TestRecoverProverErrorPropagatesOtherPanics(t *testing.T) {
+	assert.PanicsWithValue(t, "not from the prover", func() {
+		var err error
+		defer wizard.RecoverProverError(&err)
+		panic("not from the prover")
+	})
+}
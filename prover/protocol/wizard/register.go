@@ -1,8 +1,11 @@
 package wizard
 
 import (
+	"fmt"
+
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/consensys/linea-monorepo/prover/utils/ids"
 )
 
 /*
@@ -19,6 +22,13 @@ type ByRoundRegister[ID comparable, DATA any] struct {
 	byRoundsIndex collection.Mapping[ID, int]
 	// Marks an entry as ignorable (but does not delete it)
 	ignored collection.Set[ID]
+	// Optionally records the IDs in a namespace possibly shared with other
+	// registers, to report the duplicates along with their definition site.
+	// Only set when the detection is enabled, see [detectIDCollisions].
+	detector  *ids.Detector
+	namespace string
+	// Optionally checks the format of the IDs before inserting them
+	validate func(ID) error
 }
 
 /*
@@ -38,11 +48,33 @@ Insert for a given round. Will panic if an item
 with the same ID has been registered first
 */
 func (r *ByRoundRegister[ID, DATA]) AddToRound(round int, id ID, data DATA) {
+	if r.validate != nil {
+		if err := r.validate(id); err != nil {
+			utils.Panic("%v", err)
+		}
+	}
+	if r.detector != nil {
+		r.detector.MustClaim(r.namespace, fmt.Sprint(id))
+	}
 	r.mapping.InsertNew(id, data)
 	r.byRounds.AppendToInner(round, id)
 	r.byRoundsIndex.InsertNew(id, round)
 }
 
+// validateIDs makes the register check the IDs with validate before inserting
+// them.
+func (r *ByRoundRegister[ID, DATA]) validateIDs(validate func(ID) error) {
+	r.validate = validate
+}
+
+// detectCollisions makes the register claim its IDs in the namespace of the
+// detector. Registers sharing a detector and a namespace thus panic when an ID
+// of one is reused by another.
+func (r *ByRoundRegister[ID, DATA]) detectCollisions(d *ids.Detector, namespace string) {
+	r.detector = d
+	r.namespace = namespace
+}
+
 /*
 Returns the list of all the keys ever. The result is returned in
 Deterministic order.
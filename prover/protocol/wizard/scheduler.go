@@ -0,0 +1,114 @@
+package wizard
+
+import (
+	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/watchdog"
+)
+
+// RegisterProverActionWithDeps registers an action to be accomplished by the
+// prover at a given round, as [CompiledIOP.RegisterProverAction], but
+// additionally declares which of the prover steps previously registered for
+// the same round the action depends on. The dependencies are given as the
+// positions of the steps in [CompiledIOP.SubProvers] for this round and the
+// function returns the position of the newly registered step so that it can
+// be itself referenced as a dependency.
+//
+// When a round contains at least one step registered via this function, the
+// prover runtime schedules the steps of the round concurrently: a step is
+// started as soon as all its dependencies have terminated. Steps that were
+// registered without declaring their dependencies (e.g. via
+// [CompiledIOP.RegisterProverAction] or by appending to
+// [CompiledIOP.SubProvers] directly) are conservatively assumed to depend on
+// every step preceding them in the round. This is meant to be used by
+// compilers producing independent branches within a round, for instance
+// the naturalization of distinct univariate queries.
+//
+// The steps of a scheduled round may run at the same time, thus they must
+// only access the runtime through its methods, which hold the lock of the
+// runtime: e.g. [ProverRuntime.AssignColumn], [ProverRuntime.DelColumn] or
+// [ProverRuntime.InsertState] rather than the maps of the runtime directly.
+//
+// The function panics if one of the dependencies does not refer to an already
+// registered step of the round.
+func (c *CompiledIOP) RegisterProverActionWithDeps(round int, action ProverAction, deps ...int) int {
+
+	pos := c.SubProvers.LenOf(round)
+
+	for _, d := range deps {
+		if d < 0 || d >= pos {
+			utils.Panic("round %v: step %v cannot depend on step %v, only %v steps are registered", round, pos, d, pos)
+		}
+	}
+
+	if c.proverStepDeps == nil {
+		c.proverStepDeps = map[int]map[int][]int{}
+	}
+
+	if c.proverStepDeps[round] == nil {
+		c.proverStepDeps[round] = map[int][]int{}
+	}
+
+	c.SubProvers.AppendToInner(round, action.Run)
+	c.proverStepDeps[round][pos] = append([]int{}, deps...)
+	return pos
+}
+
+// runProverStepsScheduled runs the prover steps of the current round in
+// parallel, following the dependencies declared via
+// [CompiledIOP.RegisterProverActionWithDeps]. The function returns once all
+// the steps have terminated. If some of the steps panic, the first
+// [ProverError] is raised again in the calling goroutine.
+func (run *ProverRuntime) runProverStepsScheduled(steps []ProverStep, declared map[int][]int) {
+
+	var (
+		numSteps  = len(steps)
+		done      = make([]chan struct{}, numSteps)
+		wg        = &sync.WaitGroup{}
+		panicOnce = &sync.Once{}
+		firstErr  any
+	)
+
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	wg.Add(numSteps)
+
+	for i := range steps {
+
+		deps, ok := declared[i]
+		if !ok {
+			// The step did not declare its dependencies, so it is treated as
+			// a barrier and waits for all the previous steps.
+			deps = make([]int, i)
+			for j := range deps {
+				deps[j] = j
+			}
+		}
+
+		go func(i int, deps []int) {
+			defer wg.Done()
+			defer close(done[i])
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { firstErr = r })
+				}
+			}()
+
+			for _, d := range deps {
+				<-done[d]
+			}
+
+			run.runStep(stepName(i, steps[i]), steps[i])
+			watchdog.Heartbeat("wizard prover round %v", run.currRound)
+		}(i, deps)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		panic(firstErr)
+	}
+}
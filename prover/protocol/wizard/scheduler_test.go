@@ -0,0 +1,107 @@
+package wizard_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerRespectsDependencies(t *testing.T) {
+
+	var (
+		counter        atomic.Int32
+		orderA, orderB int32
+		orderC, orderD int32
+		assignAt       = func(name string, order *int32) proverActionFunc {
+			return func(run *wizard.ProverRuntime) {
+				*order = counter.Add(1)
+				run.AssignColumn(ifaces.ColID("SCHED_"+name), smartvectors.ForTest(1, 2, 3, 4))
+			}
+		}
+	)
+
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("SCHED_A", SIZE)
+		b.RegisterCommit("SCHED_B", SIZE)
+		b.RegisterCommit("SCHED_C", SIZE)
+		b.RegisterCommit("SCHED_D", SIZE)
+
+		a := b.RegisterProverActionWithDeps(0, assignAt("A", &orderA))
+		bb := b.RegisterProverActionWithDeps(0, assignAt("B", &orderB))
+		b.RegisterProverActionWithDeps(0, assignAt("C", &orderC), a, bb)
+		// D does not declare its dependencies and must run last
+		b.RegisterProverAction(0, assignAt("D", &orderD))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) {})
+	require.NoError(t, wizard.Verify(comp, proof))
+
+	require.Greater(t, orderC, orderA)
+	require.Greater(t, orderC, orderB)
+	require.Equal(t, int32(4), orderD)
+}
+
+func TestSchedulerRejectsUnknownDependency(t *testing.T) {
+	define := func(b *wizard.Builder) {
+		b.RegisterCommit("SCHED_E", SIZE)
+		b.RegisterProverActionWithDeps(0, proverActionFunc(func(run *wizard.ProverRuntime) {}), 3)
+	}
+
+	require.Panics(t, func() { wizard.Compile(define) })
+}
+
+func TestSchedulerConcurrentWrites(t *testing.T) {
+
+	const numSteps = 16
+
+	// started counts the steps that started, they wait for each other so
+	// that they run concurrently.
+	var started atomic.Int32
+
+	define := func(b *wizard.Builder) {
+		for i := 0; i < numSteps; i++ {
+			name := ifaces.ColID(fmt.Sprintf("SCHED_W_%v", i))
+			b.RegisterCommit(name, SIZE)
+			b.RegisterProverActionWithDeps(0, proverActionFunc(func(run *wizard.ProverRuntime) {
+				started.Add(1)
+				deadline := time.Now().Add(10 * time.Second)
+				for started.Load() < numSteps {
+					if time.Now().After(deadline) {
+						panic("the steps were not run concurrently")
+					}
+					time.Sleep(time.Millisecond)
+				}
+				run.InsertState(string(name), i)
+				run.AssignColumn(name, smartvectors.ForTest(1, 2, 3, i))
+				// reads the entries written by the other steps meanwhile
+				for j := 0; j < numSteps; j++ {
+					run.TryGetState(fmt.Sprintf("SCHED_W_%v", j))
+					run.TryGetColumn(ifaces.ColID(fmt.Sprintf("SCHED_W_%v", j)))
+				}
+			}))
+		}
+
+		// Runs once all the other steps are done
+		b.RegisterProverAction(0, proverActionFunc(func(run *wizard.ProverRuntime) {
+			for i := 0; i < numSteps; i++ {
+				name := fmt.Sprintf("SCHED_W_%v", i)
+				require.Equal(t, i, run.GetState(name))
+				require.Equal(t, field.NewElement(uint64(i)), run.GetColumnAt(ifaces.ColID(name), 3))
+				run.DelState(name)
+			}
+		}))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) {})
+	require.NoError(t, wizard.Verify(comp, proof))
+}
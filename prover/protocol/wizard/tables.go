@@ -0,0 +1,68 @@
+package wizard
+
+import (
+	"crypto/sha256"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// tableDigest uniquely identifies the content of a precomputed table
+type tableDigest [sha256.Size]byte
+
+// InsertPrecomputedTable registers a precomputed column meant to be used as
+// a lookup table. Contrary to [CompiledIOP.InsertPrecomputed], the table is
+// identified by its content rather than by its name: if a table with the same
+// size and values was already registered through this function, possibly
+// under a different name, the existing column is returned and nothing is
+// added to the protocol. This allows the modules to share their static
+// tables (range-checks, base conversions, etc...) instead of committing to
+// the same vector several times.
+//
+// The returned column should be treated as shared: it must not be the target
+// of constraints other than lookups and its name is the one of the first
+// registration. The function panics if the name is already used by a column
+// with a different content.
+//
+// The registry is not serialized with the [CompiledIOP]. The tables
+// registered before a serialization round-trip are not shared with the ones
+// registered afterward.
+func (c *CompiledIOP) InsertPrecomputedTable(name ifaces.ColID, v smartvectors.SmartVector) ifaces.Column {
+
+	digest := digestTable(v)
+
+	if c.precomputedTables == nil {
+		c.precomputedTables = map[tableDigest]ifaces.ColID{}
+	}
+
+	if existing, ok := c.precomputedTables[digest]; ok {
+		return c.Columns.GetHandle(existing)
+	}
+
+	if c.Columns.Exists(name) {
+		if !c.Precomputed.Exists(name) || digestTable(c.Precomputed.MustGet(name)) != digest {
+			utils.Panic("the name %v is already used by a column that is not the same table", name)
+		}
+		c.precomputedTables[digest] = name
+		return c.Columns.GetHandle(name)
+	}
+
+	c.precomputedTables[digest] = name
+	return c.InsertPrecomputed(name, v)
+}
+
+// ListPrecomputedTables returns the names of the columns registered via
+// [CompiledIOP.InsertPrecomputedTable], in no particular order.
+func (c *CompiledIOP) ListPrecomputedTables() []ifaces.ColID {
+	res := make([]ifaces.ColID, 0, len(c.precomputedTables))
+	for _, name := range c.precomputedTables {
+		res = append(res, name)
+	}
+	return res
+}
+
+// digestTable hashes the content of a vector, see [smartvectors.Digest]
+func digestTable(v smartvectors.SmartVector) tableDigest {
+	return tableDigest(smartvectors.Digest(v))
+}
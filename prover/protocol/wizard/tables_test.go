@@ -0,0 +1,54 @@
+package wizard_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertPrecomputedTable(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+
+		var (
+			comp   = b.CompiledIOP
+			range8 = smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7)
+			other  = smartvectors.ForTest(1, 1, 2, 3, 4, 5, 6, 7)
+			a      = comp.InsertPrecomputedTable("MODULE_A_RANGE", range8)
+			b2     = comp.InsertPrecomputedTable("MODULE_B_RANGE", smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7))
+			c      = comp.InsertPrecomputedTable("MODULE_C_TABLE", other)
+		)
+
+		// identical content is shared under the first name
+		require.Equal(t, ifaces.ColID("MODULE_A_RANGE"), b2.GetColID())
+		require.Equal(t, a.GetColID(), b2.GetColID())
+		require.False(t, comp.Columns.Exists("MODULE_B_RANGE"))
+
+		// a constant vector is the same table as the regular one
+		zeroes := comp.InsertPrecomputedTable("ZEROES", smartvectors.NewConstant(field.Zero(), 8))
+		require.Equal(t, zeroes.GetColID(),
+			comp.InsertPrecomputedTable("ZEROES_2", smartvectors.ForTest(0, 0, 0, 0, 0, 0, 0, 0)).GetColID())
+
+		// the same values with a different length are another table
+		require.NotEqual(t, zeroes.GetColID(),
+			comp.InsertPrecomputedTable("ZEROES_4", smartvectors.NewConstant(field.Zero(), 4)).GetColID())
+
+		require.Equal(t, ifaces.ColID("MODULE_C_TABLE"), c.GetColID())
+		require.Len(t, comp.ListPrecomputedTables(), 4)
+
+		// a column registered with InsertPrecomputed can be reused if it has
+		// the same content, but not otherwise.
+		p := comp.InsertPrecomputed("PLAIN", smartvectors.ForTest(7, 6, 5, 4, 3, 2, 1, 0))
+		require.Equal(t, p.GetColID(),
+			comp.InsertPrecomputedTable("PLAIN", smartvectors.ForTest(7, 6, 5, 4, 3, 2, 1, 0)).GetColID())
+		require.Panics(t, func() {
+			comp.InsertPrecomputedTable("PLAIN", smartvectors.ForTest(0, 6, 5, 4, 3, 2, 1, 0))
+		})
+	}
+
+	wizard.Compile(define)
+}
@@ -32,6 +32,11 @@ type Proof struct {
 	// QueriesParams stores all the query parameters (i.e) the messages of the
 	// oracle to the verifier.
 	QueriesParams collection.Mapping[ifaces.QueryID, ifaces.QueryParams]
+
+	// DebugOpenings stores the assignment of the columns when the prover ran
+	// with [WithDebugOpenings] and is nil otherwise. It is not read by the
+	// verifier.
+	DebugOpenings map[ifaces.ColID]ifaces.ColAssignment
 }
 
 // VerifierStep specifies a single step of verifier for a single subprotocol.
@@ -69,6 +74,10 @@ type VerifierRuntime struct {
 	// the verifer end up having different state or the same message being
 	// included a second time. Use it externally at your own risks.
 	FS *fiatshamir.State
+
+	// accessorCache memoizes the values of the accessors evaluated by the
+	// verifier steps, see [VerifierRuntime.InvalidateAccessorCache].
+	accessorCache *accessorCache[field.Element]
 }
 
 // Verify verifies a wizard proof. The caller specifies a [CompiledIOP] that
@@ -91,12 +100,13 @@ func Verify(c *CompiledIOP, proof Proof) error {
 		any
 	*/
 	errs := []error{}
-	for _, roundSteps := range runtime.Spec.subVerifiers.Inner() {
+	for round, roundSteps := range runtime.Spec.subVerifiers.Inner() {
 		for _, step := range roundSteps {
 			if err := step(&runtime); err != nil {
 				errs = append(errs, err)
 			}
 		}
+		errs = append(errs, runtime.runOrderedVerifierActions(round)...)
 	}
 
 	if len(errs) > 0 {
@@ -122,6 +132,7 @@ func (c *CompiledIOP) createVerifier(proof Proof) VerifierRuntime {
 		Columns:       proof.Messages,
 		QueriesParams: proof.QueriesParams,
 		FS:            fiatshamir.NewMiMCFiatShamir(),
+		accessorCache: newAccessorCache[field.Element](),
 	}
 
 	runtime.FS.Update(c.fiatShamirSetup)
@@ -145,6 +156,9 @@ func (c *CompiledIOP) createVerifier(proof Proof) VerifierRuntime {
 // it avoid implementing a "round-after-round" coin population logic.
 func (run *VerifierRuntime) generateAllRandomCoins() {
 
+	// The values memoized so far were computed with other coins
+	run.InvalidateAccessorCache()
+
 	for currRound := 0; currRound < run.Spec.NumRounds(); currRound++ {
 		if currRound > 0 {
 			/*
@@ -0,0 +1,178 @@
+package wizard
+
+import (
+	"sort"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/dag"
+)
+
+// orderedVerifierAction is a [VerifierAction] registered via
+// [CompiledIOP.RegisterVerifierActionOrdered] along with the name identifying
+// it and the names of the actions it must run after.
+type orderedVerifierAction struct {
+	Name   string
+	Round  int
+	After  []string
+	Action VerifierAction
+}
+
+// RegisterVerifierActionOrdered registers an action to be accomplished by the
+// verifier of the protocol at a given round, as
+// [CompiledIOP.RegisterVerifierAction], but with an explicit ordering. The
+// action is identified by a name which must be unique in the protocol and
+// declares the names of the actions it must run after. The actions of a
+// round that are registered this way run after the other verifier steps of
+// the round, in an order that depends only on their names and on their
+// declared dependencies: the actions are topologically sorted and the ties
+// are broken by name. Thus, the order in which the modules of the protocol
+// register their actions does not affect the verifier.
+//
+// The dependencies may refer to actions registered later on, or in an
+// earlier round, but not in a later round. They are validated by [Compile]
+// which panics if a dependency is unknown, refers to a later round or if
+// the dependencies form a cycle. The function panics if the name is empty or
+// already used.
+func (c *CompiledIOP) RegisterVerifierActionOrdered(round int, name string, action VerifierAction, after ...string) {
+
+	c.assertConsistentRound(round)
+
+	if len(name) == 0 {
+		utils.Panic("round %v: a verifier action cannot have an empty name", round)
+	}
+
+	if c.orderedVerifierActions == nil {
+		c.orderedVerifierActions = map[string]*orderedVerifierAction{}
+	}
+
+	if prev, ok := c.orderedVerifierActions[name]; ok {
+		utils.Panic("verifier action %v registered at round %v is already registered at round %v", name, round, prev.Round)
+	}
+
+	c.orderedVerifierActions[name] = &orderedVerifierAction{
+		Name:   name,
+		Round:  round,
+		After:  append([]string{}, after...),
+		Action: action,
+	}
+
+	// The order has to be recomputed by [Compile]
+	c.verifierActionOrder = nil
+}
+
+// orderVerifierActions validates the dependencies of the actions registered
+// via [CompiledIOP.RegisterVerifierActionOrdered] and computes the order in
+// which they are run, round by round. It panics if the dependencies are not
+// valid. It is called by [Compile].
+func (c *CompiledIOP) orderVerifierActions() {
+
+	if len(c.orderedVerifierActions) == 0 {
+		return
+	}
+
+	var (
+		numRounds = c.NumRounds()
+		order     = make([][]*orderedVerifierAction, numRounds)
+		byRound   = make([][]*orderedVerifierAction, numRounds)
+	)
+
+	for _, a := range c.orderedVerifierActions {
+		if a.Round >= numRounds {
+			utils.Panic("verifier action %v is registered at round %v but the protocol has %v rounds", a.Name, a.Round, numRounds)
+		}
+
+		for _, dep := range a.After {
+			d, ok := c.orderedVerifierActions[dep]
+			if !ok {
+				utils.Panic("verifier action %v must run after %v which is not registered", a.Name, dep)
+			}
+			if d.Round > a.Round {
+				utils.Panic("verifier action %v of round %v must run after %v which is registered at the later round %v", a.Name, a.Round, dep, d.Round)
+			}
+		}
+
+		byRound[a.Round] = append(byRound[a.Round], a)
+	}
+
+	for round, actions := range byRound {
+
+		// The actions are inserted by name so that the topological sort
+		// breaks the ties by name.
+		sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+
+		g := dag.New[string]()
+		for _, a := range actions {
+			g.AddNode(a.Name)
+		}
+
+		for _, a := range actions {
+			for _, dep := range a.After {
+				// The dependencies on earlier rounds are always satisfied
+				if c.orderedVerifierActions[dep].Round < round {
+					continue
+				}
+				g.AddEdge(dep, a.Name)
+			}
+		}
+
+		names, err := g.TopologicalSort()
+		if err != nil {
+			// The error reports a single cycle, the strongly connected
+			// components give all the actions involved in one.
+			cyclic := [][]string{}
+			for _, comp := range g.StronglyConnectedComponents() {
+				if len(comp) > 1 {
+					cyclic = append(cyclic, comp)
+				}
+			}
+			utils.Panic("round %v: the dependencies of the verifier actions are not valid: %v, the actions %v are on a cycle", round, err, cyclic)
+		}
+
+		for _, name := range names {
+			order[round] = append(order[round], c.orderedVerifierActions[name])
+		}
+	}
+
+	c.verifierActionOrder = order
+}
+
+// orderedVerifierActionsOf returns the actions registered via
+// [CompiledIOP.RegisterVerifierActionOrdered] for the given round in the
+// order in which they must be run.
+func (c *CompiledIOP) orderedVerifierActionsOf(round int) []*orderedVerifierAction {
+
+	if len(c.orderedVerifierActions) == 0 {
+		return nil
+	}
+
+	if c.verifierActionOrder == nil {
+		utils.Panic("the verifier actions are not ordered, were some registered after the compilation?")
+	}
+
+	if round >= len(c.verifierActionOrder) {
+		return nil
+	}
+
+	return c.verifierActionOrder[round]
+}
+
+// runOrderedVerifierActions runs the ordered verifier actions of a round
+// and returns the errors they returned.
+func (run *VerifierRuntime) runOrderedVerifierActions(round int) []error {
+	errs := []error{}
+	for _, a := range run.Spec.orderedVerifierActionsOf(round) {
+		if err := a.Action.Run(run); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// runOrderedVerifierActions is as [VerifierRuntime.runOrderedVerifierActions]
+// in a gnark circuit.
+func (c *WizardVerifierCircuit) runOrderedVerifierActions(api frontend.API, round int) {
+	for _, a := range c.Spec.orderedVerifierActionsOf(round) {
+		a.Action.RunGnark(api, c)
+	}
+}
@@ -0,0 +1,108 @@
+package wizard_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAction is a [wizard.VerifierAction] appending its name to a log
+type recordingAction struct {
+	name string
+	log  *[]string
+}
+
+func (a recordingAction) Run(_ *wizard.VerifierRuntime) error {
+	*a.log = append(*a.log, a.name)
+	return nil
+}
+
+func (a recordingAction) RunGnark(_ frontend.API, _ *wizard.WizardVerifierCircuit) {}
+
+func TestVerifierActionsOrder(t *testing.T) {
+
+	// runWith registers the actions in the given order and returns the order
+	// in which the verifier ran them.
+	runWith := func(registrationOrder []string) []string {
+
+		var (
+			log  = []string{}
+			deps = map[string][]string{
+				"A": {"D"},
+				"B": {},
+				"C": {"B"},
+				"D": {},
+			}
+		)
+
+		define := func(b *wizard.Builder) {
+			b.RegisterCommit("ACTIONS_P", SIZE)
+			b.InsertVerifier(0, func(_ *wizard.VerifierRuntime) error {
+				log = append(log, "anonymous")
+				return nil
+			}, nil)
+			for _, name := range registrationOrder {
+				b.RegisterVerifierActionOrdered(0, name, recordingAction{name: name, log: &log}, deps[name]...)
+			}
+		}
+
+		comp := wizard.Compile(define, dummy.Compile)
+		proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) {
+			run.AssignColumn("ACTIONS_P", smartvectors.ForTest(1, 2, 3, 4))
+		})
+		require.NoError(t, wizard.Verify(comp, proof))
+		return log
+	}
+
+	expected := []string{"anonymous", "B", "C", "D", "A"}
+	require.Equal(t, expected, runWith([]string{"A", "B", "C", "D"}))
+	require.Equal(t, expected, runWith([]string{"D", "C", "B", "A"}))
+	require.Equal(t, expected, runWith([]string{"C", "A", "D", "B"}))
+}
+
+func TestVerifierActionsValidation(t *testing.T) {
+
+	var (
+		noop   = recordingAction{log: &[]string{}}
+		column = func(b *wizard.Builder) {
+			b.RegisterCommit(ifaces.ColID("ACTIONS_Q"), SIZE)
+		}
+	)
+
+	t.Run("unknown-dependency", func(t *testing.T) {
+		require.Panics(t, func() {
+			wizard.Compile(func(b *wizard.Builder) {
+				column(b)
+				b.RegisterVerifierActionOrdered(0, "A", noop, "B")
+			})
+		})
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		require.PanicsWithValue(t, "round 0: the dependencies of the verifier actions are not valid: dag: cycle detected: A -> C -> A, the actions [[A C] [D E]] are on a cycle", func() {
+			wizard.Compile(func(b *wizard.Builder) {
+				column(b)
+				b.RegisterVerifierActionOrdered(0, "C", noop, "A")
+				b.RegisterVerifierActionOrdered(0, "B", noop)
+				b.RegisterVerifierActionOrdered(0, "A", noop, "B", "C")
+				b.RegisterVerifierActionOrdered(0, "D", noop, "E")
+				b.RegisterVerifierActionOrdered(0, "E", noop, "D")
+			})
+		})
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		require.Panics(t, func() {
+			wizard.Compile(func(b *wizard.Builder) {
+				column(b)
+				b.RegisterVerifierActionOrdered(0, "A", noop)
+				b.RegisterVerifierActionOrdered(0, "A", noop)
+			})
+		})
+	})
+}
@@ -0,0 +1,279 @@
+package wizard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// VerifierKeyVersion is the version of the encoding of the [VerifierKey]. It
+// must be incremented whenever the encoding or the meaning of a field changes.
+const VerifierKeyVersion = 1
+
+// VerifierKey is a compact and data-only description of the verifier side of
+// a [CompiledIOP]: the messages it receives at every round, the random coins
+// it sends and how they are derived via Fiat-Shamir, as well as the
+// verifying key columns (for instance, the commitments to the precomputed
+// columns after a Vortex compilation).
+//
+// Contrary to the [CompiledIOP], it can be exported once at compilation time,
+// serialized, versioned and loaded by another process. This process can then
+// check the shape of a proof ([VerifierKey.CheckProof]), recompute the
+// verifier's challenges ([VerifierKey.SampleCoins]) or ensure that a
+// [CompiledIOP] obtained by other means is the one that was exported
+// ([VerifyWithKey]) without running the compilation.
+//
+// The verifier checks themselves are Go functions and are not part of the
+// key.
+type VerifierKey struct {
+	// Version is the [VerifierKeyVersion] of the encoding
+	Version int `cbor:"version"`
+	// Metadata is the metadata passed to [CompiledIOP.BootstrapFiatShamir]
+	Metadata VersionMetadata `cbor:"metadata"`
+	// FiatShamirSetup is the initial value of the Fiat-Shamir state
+	FiatShamirSetup field.Element `cbor:"fsSetup"`
+	// DummyCompiled indicates that the transcript is not updated with the
+	// prover messages, see [CompiledIOP.DummyCompiled].
+	DummyCompiled bool `cbor:"dummyCompiled"`
+	// Rounds lists what is exchanged at every round, in the order in which the
+	// messages enter the Fiat-Shamir transcript.
+	Rounds []VerifierKeyRound `cbor:"rounds"`
+	// VerifyingKey lists the [column.VerifyingKey] columns with their values
+	VerifyingKey []VerifierKeyColumn `cbor:"verifyingKey"`
+}
+
+// VerifierKeyRound lists the messages of the prover and the coins of the
+// verifier for one round of the protocol.
+type VerifierKeyRound struct {
+	ProofColumns       []VerifierKeyColumn `cbor:"proofColumns"`
+	PublicInputColumns []VerifierKeyColumn `cbor:"publicInputColumns"`
+	QueriesParams      []ifaces.QueryID    `cbor:"queriesParams"`
+	Coins              []coin.Info         `cbor:"coins"`
+}
+
+// VerifierKeyColumn describes a column visible to the verifier. The values are
+// only provided for the verifying key columns.
+type VerifierKeyColumn struct {
+	Name   ifaces.ColID    `cbor:"name"`
+	Size   int             `cbor:"size"`
+	Values []field.Element `cbor:"values,omitempty"`
+}
+
+// ExportVerifierKey returns the [VerifierKey] of the compiled IOP. It should
+// be called once the compilation is complete and after
+// [CompiledIOP.BootstrapFiatShamir].
+func (c *CompiledIOP) ExportVerifierKey() *VerifierKey {
+
+	vk := &VerifierKey{
+		Version:         VerifierKeyVersion,
+		Metadata:        c.versionMetadata,
+		FiatShamirSetup: c.fiatShamirSetup,
+		DummyCompiled:   c.DummyCompiled,
+		Rounds:          make([]VerifierKeyRound, c.NumRounds()),
+	}
+
+	for round := range vk.Rounds {
+
+		r := &vk.Rounds[round]
+
+		for _, name := range c.Columns.AllKeysProofAt(round) {
+			r.ProofColumns = append(r.ProofColumns, VerifierKeyColumn{Name: name, Size: c.Columns.GetSize(name)})
+		}
+
+		for _, name := range c.Columns.AllKeysPublicInputAt(round) {
+			r.PublicInputColumns = append(r.PublicInputColumns, VerifierKeyColumn{Name: name, Size: c.Columns.GetSize(name)})
+		}
+
+		r.QueriesParams = append(r.QueriesParams, c.QueriesParams.AllKeysAt(round)...)
+
+		for _, name := range c.Coins.AllKeysAt(round) {
+			r.Coins = append(r.Coins, c.Coins.Data(name))
+		}
+	}
+
+	for _, name := range c.Columns.AllVerifyingKey() {
+		vk.VerifyingKey = append(vk.VerifyingKey, VerifierKeyColumn{
+			Name:   name,
+			Size:   c.Columns.GetSize(name),
+			Values: c.Precomputed.MustGet(name).IntoRegVecSaveAlloc(),
+		})
+	}
+
+	return vk
+}
+
+// WriteTo writes the verifier key in w using a deterministic CBOR encoding.
+// It implements [io.WriterTo].
+func (vk *VerifierKey) WriteTo(w io.Writer) (int64, error) {
+
+	em, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := em.Marshal(vk)
+	if err != nil {
+		return 0, fmt.Errorf("could not encode the verifier key: %w", err)
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// ReadVerifierKey reads a verifier key written by [VerifierKey.WriteTo]. It
+// returns an error if the key was written with another [VerifierKeyVersion].
+func ReadVerifierKey(r io.Reader) (*VerifierKey, error) {
+
+	vk := &VerifierKey{}
+	if err := cbor.NewDecoder(r).Decode(vk); err != nil {
+		return nil, fmt.Errorf("could not decode the verifier key: %w", err)
+	}
+
+	if vk.Version != VerifierKeyVersion {
+		return nil, fmt.Errorf("unsupported verifier key version %v, expected %v", vk.Version, VerifierKeyVersion)
+	}
+
+	return vk, nil
+}
+
+// Digest returns the SHA256 hash of the encoding of the verifier key. Two
+// compiled IOPs with the same digest have the same verifier messages,
+// transcript and verifying key.
+func (vk *VerifierKey) Digest() [sha256.Size]byte {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		// The key only holds plain data, the encoding cannot fail.
+		panic(err)
+	}
+	return sha256.Sum256(buf.Bytes())
+}
+
+// CheckProof checks that the proof holds the messages expected by the
+// verifier: exactly the proof and public input columns, with the right sizes,
+// and the parameters of all the parametrizable queries.
+func (vk *VerifierKey) CheckProof(proof Proof) error {
+
+	var (
+		errs      []error
+		nbColumns int
+	)
+
+	for _, r := range vk.Rounds {
+
+		for _, cols := range [][]VerifierKeyColumn{r.ProofColumns, r.PublicInputColumns} {
+			for _, col := range cols {
+				nbColumns++
+				v, ok := proof.Messages.TryGet(col.Name)
+				if !ok {
+					errs = append(errs, fmt.Errorf("missing column %v", col.Name))
+					continue
+				}
+				if v.Len() != col.Size {
+					errs = append(errs, fmt.Errorf("column %v has size %v, expected %v", col.Name, v.Len(), col.Size))
+				}
+			}
+		}
+
+		for _, q := range r.QueriesParams {
+			if !proof.QueriesParams.Exists(q) {
+				errs = append(errs, fmt.Errorf("missing parameters for query %v", q))
+			}
+		}
+	}
+
+	if n := len(proof.Messages.InnerMap()); n != nbColumns {
+		errs = append(errs, fmt.Errorf("the proof has %v columns, expected %v", n, nbColumns))
+	}
+
+	return errors.Join(errs...)
+}
+
+// SampleCoins replays the Fiat-Shamir transcript of the verifier on the proof
+// and returns the random coins of the protocol. The result is the same as
+// what [Verify] generates for the compiled IOP of the key. The proof is
+// expected to have passed [VerifierKey.CheckProof].
+func (vk *VerifierKey) SampleCoins(proof Proof) (collection.Mapping[coin.Name, any], error) {
+
+	var (
+		fs    = fiatshamir.NewMiMCFiatShamir()
+		coins = collection.NewMapping[coin.Name, any]()
+	)
+
+	fs.Update(vk.FiatShamirSetup)
+
+	for round := range vk.Rounds {
+
+		if round > 0 && !vk.DummyCompiled {
+
+			prev := &vk.Rounds[round-1]
+
+			for _, cols := range [][]VerifierKeyColumn{prev.ProofColumns, prev.PublicInputColumns} {
+				for _, col := range cols {
+					v, err := vk.columnValue(proof, col.Name)
+					if err != nil {
+						return coins, err
+					}
+					fs.UpdateSV(v)
+				}
+			}
+
+			for _, q := range prev.QueriesParams {
+				if !proof.QueriesParams.Exists(q) {
+					return coins, fmt.Errorf("missing parameters for query %v", q)
+				}
+				proof.QueriesParams.MustGet(q).UpdateFS(fs)
+			}
+		}
+
+		for i := range vk.Rounds[round].Coins {
+			info := &vk.Rounds[round].Coins[i]
+			coins.InsertNew(info.Name, info.Sample(fs))
+		}
+	}
+
+	return coins, nil
+}
+
+// columnValue returns the assignment of a column visible to the verifier
+func (vk *VerifierKey) columnValue(proof Proof, name ifaces.ColID) (smartvectors.SmartVector, error) {
+
+	if proof.Messages.Exists(name) {
+		return proof.Messages.MustGet(name), nil
+	}
+
+	for _, col := range vk.VerifyingKey {
+		if col.Name == name {
+			return smartvectors.NewRegular(col.Values), nil
+		}
+	}
+
+	return nil, fmt.Errorf("missing column %v", name)
+}
+
+// VerifyWithKey verifies the proof after checking that the compiled IOP
+// matches the verifier key and that the proof has the expected shape. This
+// allows a verifier process to rely on a trusted, independently distributed,
+// verifier key while obtaining the compiled IOP by other means, for instance
+// by deserialization.
+func VerifyWithKey(vk *VerifierKey, c *CompiledIOP, proof Proof) error {
+
+	if vk.Digest() != c.ExportVerifierKey().Digest() {
+		return errors.New("the compiled IOP does not match the verifier key")
+	}
+
+	if err := vk.CheckProof(proof); err != nil {
+		return fmt.Errorf("the proof does not match the verifier key: %w", err)
+	}
+
+	return Verify(c, proof)
+}
@@ -0,0 +1,102 @@
+package wizard_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifierKeyProtocol returns a three-rounds protocol, its prover and a
+// pointer to the coins seen by the prover.
+func verifierKeyProtocol(t *testing.T, title string) (*wizard.CompiledIOP, wizard.ProverStep, *[2]field.Element) {
+
+	var (
+		coins  [2]field.Element
+		define = func(b *wizard.Builder) {
+			b.RegisterCommit("P", SIZE)
+			b.RegisterRandomCoin("ALPHA", coin.Field)
+			q := b.RegisterCommit("Q", SIZE)
+			b.RegisterRandomCoin("BETA", coin.Field)
+			b.UnivariateEval("U", q)
+		}
+		prover = func(run *wizard.ProverRuntime) {
+			run.AssignColumn("P", smartvectors.ForTest(1, 2, 3, 4))
+			coins[0] = run.GetRandomCoinField("ALPHA")
+			q := smartvectors.NewConstant(coins[0], SIZE)
+			run.AssignColumn("Q", q)
+			coins[1] = run.GetRandomCoinField("BETA")
+			run.AssignUnivariate("U", coins[1], coins[0])
+		}
+	)
+
+	comp := wizard.Compile(define, dummy.Compile)
+	comp.BootstrapFiatShamir(wizard.VersionMetadata{Title: title, Version: "1"}, func(*wizard.CompiledIOP) ([]byte, error) {
+		return []byte(title), nil
+	})
+
+	return comp, prover, &coins
+}
+
+func TestVerifierKey(t *testing.T) {
+
+	comp, prover, coins := verifierKeyProtocol(t, "test")
+	vk := comp.ExportVerifierKey()
+
+	require.Len(t, vk.Rounds, 3)
+	assert.Equal(t, "test", vk.Metadata.Title)
+	assert.Equal(t, []wizard.VerifierKeyColumn{{Name: "P", Size: SIZE}}, vk.Rounds[0].ProofColumns)
+	assert.Equal(t, []ifaces.QueryID{"U"}, vk.Rounds[2].QueriesParams)
+
+	var buf bytes.Buffer
+	_, err := vk.WriteTo(&buf)
+	require.NoError(t, err)
+	decoded, err := wizard.ReadVerifierKey(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, vk.Digest(), decoded.Digest())
+
+	proof := wizard.Prove(comp, prover)
+	require.NoError(t, decoded.CheckProof(proof))
+	require.NoError(t, wizard.VerifyWithKey(decoded, comp, proof))
+
+	sampled, err := decoded.SampleCoins(proof)
+	require.NoError(t, err)
+	assert.Equal(t, coins[0], sampled.MustGet("ALPHA"))
+	assert.Equal(t, coins[1], sampled.MustGet("BETA"))
+
+	t.Run("other-protocol", func(t *testing.T) {
+		other, _, _ := verifierKeyProtocol(t, "other")
+		assert.NotEqual(t, vk.Digest(), other.ExportVerifierKey().Digest())
+		assert.Error(t, wizard.VerifyWithKey(vk, other, proof))
+	})
+
+	t.Run("wrong-version", func(t *testing.T) {
+		old := *vk
+		old.Version = wizard.VerifierKeyVersion + 1
+		var buf bytes.Buffer
+		_, err := old.WriteTo(&buf)
+		require.NoError(t, err)
+		_, err = wizard.ReadVerifierKey(&buf)
+		assert.ErrorContains(t, err, "unsupported verifier key version")
+	})
+
+	t.Run("wrong-proof-shape", func(t *testing.T) {
+		tampered := wizard.Prove(comp, prover)
+		tampered.Messages.Update("P", smartvectors.ForTest(1, 2))
+		tampered.Messages.InsertNew("EXTRA", smartvectors.ForTest(1, 2, 3, 4))
+		tampered.Messages.Del("Q")
+
+		err := vk.CheckProof(tampered)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "column P has size 2, expected 4")
+		assert.ErrorContains(t, err, "missing column Q")
+		assert.Error(t, wizard.VerifyWithKey(vk, comp, tampered))
+	})
+}
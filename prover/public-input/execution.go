@@ -13,6 +13,9 @@ type Execution struct {
 	FinalBlockTimestamp    uint64
 	FinalRollingHash       [32]byte
 	FinalRollingHashNumber uint64
+	// TracesLimitsDigest is the digest of the traces limits the execution was
+	// proven with, nil unless the traces limits are bound.
+	TracesLimitsDigest []byte
 }
 
 type ExecutionSerializable struct {
@@ -22,6 +25,7 @@ type ExecutionSerializable struct {
 	FinalBlockTimestamp    uint64   `json:"finalBlockTimestamp"`
 	FinalRollingHash       string   `json:"finalRollingHash"`
 	FinalRollingHashNumber uint64   `json:"finalRollingHashNumber"`
+	TracesLimitsDigest     string   `json:"tracesLimitsDigest,omitempty"`
 }
 
 func (e ExecutionSerializable) Decode() (decoded Execution, err error) {
@@ -57,6 +61,12 @@ func (e ExecutionSerializable) Decode() (decoded Execution, err error) {
 		return
 	}
 
-	fillWithHex(decoded.FinalRollingHash[:], e.FinalRollingHash)
+	if fillWithHex(decoded.FinalRollingHash[:], e.FinalRollingHash); err != nil {
+		return
+	}
+
+	if e.TracesLimitsDigest != "" {
+		decoded.TracesLimitsDigest, err = utils.HexDecodeString(e.TracesLimitsDigest)
+	}
 	return
 }
@@ -3,6 +3,7 @@ package symbolic
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/linea-monorepo/prover/maths/common/mempool"
@@ -153,7 +154,15 @@ func (b *ExpressionBoard) evaluateSingleThread(inputs []sv.SmartVector, p ...mem
 		for pil := range nodeAssignment[level] {
 
 			node := &nodeAssignment[level][pil]
+
+			if b.profiler == nil {
+				nodeAssignment.eval(node, pool)
+				continue
+			}
+
+			start := time.Now()
 			nodeAssignment.eval(node, pool)
+			b.profiler.record(level, pil, time.Since(start))
 		}
 	}
 
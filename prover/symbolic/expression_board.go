@@ -21,6 +21,9 @@ type ExpressionBoard struct {
 	// of the ID indicates the level and the LSB bits indicates the position
 	// in the level.
 	ESHashesToPos map[field.Element]nodeID
+	// profiler is an optional profiler recording the time spent evaluating
+	// each node. It is nil unless set with [ExpressionBoard.SetProfiler].
+	profiler *EvalProfiler
 }
 
 // emptyBoard initializes a board with no Node in it.
@@ -0,0 +1,88 @@
+package symbolic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pretty returns a human-readable infix representation of the expression. It
+// is meant for debugging and inspection purposes only: the output format is
+// not stable and should not be parsed.
+//
+// Linear combinations are rendered as sums, products as products of powers
+// and polynomial evaluations as `PolyEval(x; c0, c1, ...)`. Variables are
+// rendered using the `String()` method of their metadata.
+func (e *Expression) Pretty() string {
+	sb := &strings.Builder{}
+	e.writePretty(sb)
+	return sb.String()
+}
+
+// writePretty writes the pretty representation of the expression in sb.
+func (e *Expression) writePretty(sb *strings.Builder) {
+
+	switch op := e.Operator.(type) {
+
+	case Constant:
+		sb.WriteString(op.Val.String())
+
+	case Variable:
+		sb.WriteString(op.Metadata.String())
+
+	case LinComb:
+		sb.WriteString("(")
+		for i, c := range op.Coeffs {
+			switch {
+			case i == 0 && c == -1:
+				sb.WriteString("-")
+			case i == 0 && c == 1:
+			case i == 0:
+				fmt.Fprintf(sb, "%v*", c)
+			case c == 1:
+				sb.WriteString(" + ")
+			case c == -1:
+				sb.WriteString(" - ")
+			case c < 0:
+				fmt.Fprintf(sb, " - %v*", -c)
+			default:
+				fmt.Fprintf(sb, " + %v*", c)
+			}
+			e.Children[i].writePretty(sb)
+		}
+		sb.WriteString(")")
+
+	case Product:
+		for i, exp := range op.Exponents {
+			if i > 0 {
+				sb.WriteString(" * ")
+			}
+			e.Children[i].writePretty(sb)
+			if exp != 1 {
+				fmt.Fprintf(sb, "^%v", exp)
+			}
+		}
+
+	case PolyEval:
+		sb.WriteString("PolyEval(")
+		e.Children[0].writePretty(sb)
+		sb.WriteString(";")
+		for i, c := range e.Children[1:] {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(" ")
+			c.writePretty(sb)
+		}
+		sb.WriteString(")")
+
+	default:
+		fmt.Fprintf(sb, "%T(", op)
+		for i, c := range e.Children {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			c.writePretty(sb)
+		}
+		sb.WriteString(")")
+	}
+}
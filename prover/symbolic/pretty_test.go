@@ -0,0 +1,28 @@
+package symbolic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPretty(t *testing.T) {
+
+	a, b, x := NewDummyVar("a"), NewDummyVar("b"), NewDummyVar("x")
+
+	testCases := []struct {
+		Expr     *Expression
+		Expected string
+	}{
+		{Expr: a, Expected: "a"},
+		{Expr: NewConstant(3), Expected: "3"},
+		{Expr: a.Sub(b), Expected: "(a - b)"},
+		{Expr: a.Mul(b).Mul(b), Expected: "a * b^2"},
+		{Expr: a.Sub(b).Mul(a), Expected: "(a - b) * a"},
+		{Expr: NewPolyEval(x, []*Expression{a, b}), Expected: "PolyEval(x; a, b)"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.Expected, tc.Expr.Pretty())
+	}
+}
@@ -0,0 +1,231 @@
+package symbolic
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+// EvalProfiler records the time spent evaluating each node of an
+// [ExpressionBoard]. It is meant to help optimizing the formulation of the
+// constraints: once attached to a board with [ExpressionBoard.SetProfiler],
+// every call to [ExpressionBoard.Evaluate] adds the time spent in each node
+// to the profiler, and [EvalProfiler.WritePprof] exports the result as a
+// pprof profile whose flamegraph shows the sub-expressions that are the most
+// expensive to evaluate.
+//
+// The profiler is safe to use from the concurrent chunk evaluations of
+// [ExpressionBoard.Evaluate]. Timing every node has a cost, so it should not
+// be attached in production.
+type EvalProfiler struct {
+	// ModuleOf maps the variables of the board to the name of the module they
+	// come from. The frames of the profile are tagged with the modules of the
+	// variables their sub-expression depend on. The default returns the part
+	// of the variable name preceding the first ".".
+	ModuleOf func(m Metadata) string
+	board    *ExpressionBoard
+	// nanos[level][posInLevel] is the total time spent evaluating the node
+	// and numEval[level][posInLevel] the number of times it was evaluated.
+	nanos   [][]int64
+	numEval [][]int64
+}
+
+// NewEvalProfiler returns a profiler for the nodes of the board. The
+// profiler still has to be attached to the board using
+// [ExpressionBoard.SetProfiler].
+func NewEvalProfiler(board *ExpressionBoard) *EvalProfiler {
+	p := &EvalProfiler{
+		ModuleOf: defaultModuleOf,
+		board:    board,
+		nanos:    make([][]int64, len(board.Nodes)),
+		numEval:  make([][]int64, len(board.Nodes)),
+	}
+
+	for lvl := range board.Nodes {
+		p.nanos[lvl] = make([]int64, len(board.Nodes[lvl]))
+		p.numEval[lvl] = make([]int64, len(board.Nodes[lvl]))
+	}
+
+	return p
+}
+
+// SetProfiler attaches a profiler to the board. The profiler must have been
+// created for this board. Passing nil detaches the current profiler.
+func (b *ExpressionBoard) SetProfiler(p *EvalProfiler) {
+	if p != nil && len(p.nanos) != len(b.Nodes) {
+		panic("the profiler was created for another board")
+	}
+	b.profiler = p
+}
+
+// record adds the duration of an evaluation of the node at position
+// [level][pil] of the board.
+func (p *EvalProfiler) record(level, pil int, d time.Duration) {
+	atomic.AddInt64(&p.nanos[level][pil], int64(d))
+	atomic.AddInt64(&p.numEval[level][pil], 1)
+}
+
+// Total returns the total time spent evaluating the nodes of the board.
+func (p *EvalProfiler) Total() time.Duration {
+	total := int64(0)
+	for lvl := range p.nanos {
+		for pil := range p.nanos[lvl] {
+			total += atomic.LoadInt64(&p.nanos[lvl][pil])
+		}
+	}
+	return time.Duration(total)
+}
+
+// WritePprof writes the recorded timings as a gzipped pprof profile that can
+// be opened with `go tool pprof -http=: <file>`. Each node is a frame whose
+// self-time is the time spent evaluating it. As the board is a DAG, a node
+// shared by several expressions is only reported under its first parent.
+func (p *EvalProfiler) WritePprof(w io.Writer) error {
+
+	var (
+		prof = &profile.Profile{
+			SampleType: []*profile.ValueType{
+				{Type: "evaluations", Unit: "count"},
+				{Type: "cpu", Unit: "nanoseconds"},
+			},
+			PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+			Period:     1,
+		}
+		modules   = p.modulesOfNodes()
+		locations = make([][]*profile.Location, len(p.board.Nodes))
+		stacks    = make([][][]*profile.Location, len(p.board.Nodes))
+	)
+
+	for lvl := range p.board.Nodes {
+		locations[lvl] = make([]*profile.Location, len(p.board.Nodes[lvl]))
+		stacks[lvl] = make([][]*profile.Location, len(p.board.Nodes[lvl]))
+	}
+
+	// The stacks are computed from the root down to the leaves, so that the
+	// stack of the first parent of a node is always known when reaching it.
+	for lvl := len(p.board.Nodes) - 1; lvl >= 0; lvl-- {
+		for pil := range p.board.Nodes[lvl] {
+
+			var (
+				node = &p.board.Nodes[lvl][pil]
+				id   = uint64(len(prof.Location) + 1)
+				fn   = &profile.Function{
+					ID:   id,
+					Name: fmt.Sprintf("%v[%v,%v] %v", operatorName(node.Operator), lvl, pil, modules[lvl][pil]),
+				}
+				loc = &profile.Location{
+					ID:   id,
+					Line: []profile.Line{{Function: fn}},
+				}
+			)
+
+			prof.Function = append(prof.Function, fn)
+			prof.Location = append(prof.Location, loc)
+			locations[lvl][pil] = loc
+
+			// pprof stacks are ordered from the leaf to the root
+			stack := []*profile.Location{loc}
+			if len(node.Parents) > 0 {
+				parent := node.Parents[0]
+				stack = append(stack, stacks[parent.level()][parent.posInLevel()]...)
+			}
+			stacks[lvl][pil] = stack
+
+			numEval := atomic.LoadInt64(&p.numEval[lvl][pil])
+			if numEval == 0 {
+				continue
+			}
+
+			prof.Sample = append(prof.Sample, &profile.Sample{
+				Location: stack,
+				Value:    []int64{numEval, atomic.LoadInt64(&p.nanos[lvl][pil])},
+			})
+		}
+	}
+
+	if err := prof.CheckValid(); err != nil {
+		return fmt.Errorf("invalid evaluation profile: %w", err)
+	}
+
+	return prof.Write(w)
+}
+
+// modulesOfNodes returns, for each node of the board, the list of the modules
+// of the variables it depends on, formatted as "[mod1,mod2]".
+func (p *EvalProfiler) modulesOfNodes() [][]string {
+
+	var (
+		nodes   = p.board.Nodes
+		sets    = make([][]map[string]struct{}, len(nodes))
+		res     = make([][]string, len(nodes))
+		maxShow = 4
+	)
+
+	for lvl := range nodes {
+		sets[lvl] = make([]map[string]struct{}, len(nodes[lvl]))
+		res[lvl] = make([]string, len(nodes[lvl]))
+
+		for pil := range nodes[lvl] {
+
+			set := map[string]struct{}{}
+			if v, ok := nodes[lvl][pil].Operator.(Variable); ok {
+				set[p.ModuleOf(v.Metadata)] = struct{}{}
+			}
+
+			for _, c := range nodes[lvl][pil].Children {
+				for m := range sets[c.level()][c.posInLevel()] {
+					set[m] = struct{}{}
+				}
+			}
+
+			sets[lvl][pil] = set
+
+			mods := make([]string, 0, len(set))
+			for m := range set {
+				mods = append(mods, m)
+			}
+			sort.Strings(mods)
+
+			if len(mods) > maxShow {
+				mods = append(mods[:maxShow], fmt.Sprintf("+%v", len(mods)-maxShow))
+			}
+
+			res[lvl][pil] = "[" + strings.Join(mods, ",") + "]"
+		}
+	}
+
+	return res
+}
+
+// defaultModuleOf returns the prefix of the name of the variable preceding
+// the first "."
+func defaultModuleOf(m Metadata) string {
+	name := m.String()
+	if i := strings.Index(name, "."); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// operatorName returns a short name for the type of the operator
+func operatorName(op Operator) string {
+	switch op.(type) {
+	case Constant:
+		return "Constant"
+	case Variable:
+		return "Variable"
+	case LinComb:
+		return "LinComb"
+	case Product:
+		return "Product"
+	case PolyEval:
+		return "PolyEval"
+	default:
+		return fmt.Sprintf("%T", op)
+	}
+}
@@ -0,0 +1,59 @@
+package symbolic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sv "github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalProfiler(t *testing.T) {
+
+	var (
+		x    = NewDummyVar("A.x")
+		y    = NewDummyVar("A.y")
+		z    = NewDummyVar("B.z")
+		expr = x.Mul(y).Add(z.Mul(z).Mul(x))
+		b    = expr.Board()
+		prof = NewEvalProfiler(&b)
+		size = 4 * MaxChunkSize
+	)
+
+	b.SetProfiler(prof)
+
+	inputs := []sv.SmartVector{}
+	for range b.ListVariableMetadata() {
+		inputs = append(inputs, sv.NewRegular(vector.Repeat(field.NewElement(2), size)))
+	}
+
+	// 2*2 + 2*2*2 = 12
+	res := b.Evaluate(inputs)
+	last := res.Get(size - 1)
+	require.Equal(t, "12", last.String())
+	require.Greater(t, prof.Total().Nanoseconds(), int64(0))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, prof.WritePprof(buf))
+
+	parsed, err := profile.Parse(buf)
+	require.NoError(t, err)
+	require.NotEmpty(t, parsed.Sample)
+
+	// All the samples must be rooted in the root of the board which depends
+	// on the two modules and each chunk must have been recorded.
+	for _, s := range parsed.Sample {
+		root := s.Location[len(s.Location)-1].Line[0].Function.Name
+		require.True(t, strings.HasSuffix(root, "[A,B]"), "root frame is %v", root)
+		require.Equal(t, int64(size/MaxChunkSize), s.Value[0])
+	}
+
+	b.SetProfiler(nil)
+	before := prof.Total()
+	b.Evaluate(inputs)
+	require.Equal(t, before, prof.Total())
+}
@@ -0,0 +1,148 @@
+// Package bitutil gathers the bit-level helpers shared by the FFTs, the hash
+// modules and the circuits: the bit-reversal permutation, the decomposition of
+// integers in digits and the conversions between bytes and field limbs.
+package bitutil
+
+import (
+	"math/bits"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Reverse reverses the logN lower bits of i. The higher bits of i are
+// ignored. Reverse(i, 0) is zero.
+func Reverse(i, logN int) int {
+	if logN == 0 {
+		return 0
+	}
+	if logN < 0 || logN > 63 {
+		utils.Panic("cannot reverse %v bits", logN)
+	}
+	return utils.ToInt(bits.Reverse64(uint64(i)) >> (64 - logN))
+}
+
+// ReverseSlice applies the bit-reversal permutation to a in place: the entry
+// at position i is swapped with the one at position Reverse(i, log2(len(a))).
+// It does nothing on an empty slice and panics if the length of a is not a
+// power of two.
+func ReverseSlice[T any](a []T) {
+
+	n := uint64(len(a))
+	if n == 0 {
+		return
+	}
+	if !utils.IsPowerOfTwo(len(a)) {
+		utils.Panic("the length of the slice should be a power of two, got %v", n)
+	}
+
+	// bits.Reverse64 reverses its input as a 64-bit integer, the shift
+	// corrects it to reverse the log2(n) lower bits.
+	shift := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> shift
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
+
+// Decompose returns the nb digits of x in the given base, the least
+// significant first. It panics if x does not fit on nb digits.
+func Decompose(x, base uint64, nb int) []uint64 {
+
+	if base < 2 {
+		utils.Panic("invalid base %v", base)
+	}
+
+	res := make([]uint64, nb)
+	for i := range res {
+		res[i] = x % base
+		x /= base
+	}
+
+	if x != 0 {
+		utils.Panic("the input does not fit on %v digits in base %v", nb, base)
+	}
+
+	return res
+}
+
+// ToBits returns the nb lower bits of x, the least significant first. It
+// panics if x does not fit on nb bits.
+func ToBits(x uint64, nb int) []uint64 {
+	return Decompose(x, 2, nb)
+}
+
+// PopCount returns the number of bits set in the canonical representations
+// of the limbs.
+func PopCount(limbs []field.Element) int {
+	res := 0
+	for i := range limbs {
+		// The words of a field element are in Montgomery form, so the
+		// canonical representation is recovered first.
+		for _, w := range limbs[i].Bits() {
+			res += bits.OnesCount64(w)
+		}
+	}
+	return res
+}
+
+// Parity returns the parity of the number of bits set in the canonical
+// representations of the limbs, i.e. 1 if [PopCount] is odd and 0 otherwise.
+func Parity(limbs []field.Element) int {
+	return PopCount(limbs) & 1
+}
+
+// LimbsToBytes returns the concatenation of the limbSize lower bytes of the
+// big-endian representations of the limbs. It panics if a limb does not fit
+// on limbSize bytes.
+func LimbsToBytes(limbs []field.Element, limbSize int) []byte {
+
+	if limbSize <= 0 || limbSize > field.Bytes {
+		utils.Panic("invalid limb size %v", limbSize)
+	}
+
+	res := make([]byte, 0, len(limbs)*limbSize)
+	for i := range limbs {
+		b := limbs[i].Bytes()
+		for _, x := range b[:field.Bytes-limbSize] {
+			if x != 0 {
+				utils.Panic("limb #%v does not fit on %v bytes", i, limbSize)
+			}
+		}
+		res = append(res, b[field.Bytes-limbSize:]...)
+	}
+	return res
+}
+
+// BytesToLimbs splits b in limbs of limbSize bytes, each interpreted in
+// big-endian. It is the converse of [LimbsToBytes]. It panics if the length of
+// b is not a multiple of limbSize or if a limb of [field.Bytes] bytes is not
+// the canonical encoding of a field element.
+func BytesToLimbs(b []byte, limbSize int) []field.Element {
+
+	if limbSize <= 0 || limbSize > field.Bytes {
+		utils.Panic("invalid limb size %v", limbSize)
+	}
+
+	if len(b)%limbSize != 0 {
+		utils.Panic("the length of the input %v is not a multiple of the limb size %v", len(b), limbSize)
+	}
+
+	res := make([]field.Element, len(b)/limbSize)
+	for i := range res {
+		limb := b[i*limbSize : (i+1)*limbSize]
+		if limbSize < field.Bytes {
+			res[i].SetBytes(limb)
+			continue
+		}
+		// A full-size limb would otherwise be silently reduced modulo the
+		// field and not round-trip through [LimbsToBytes].
+		if err := res[i].SetBytesCanonical(limb); err != nil {
+			utils.Panic("limb #%v is not a field element: %v", i, err)
+		}
+	}
+	return res
+}
@@ -0,0 +1,112 @@
+package bitutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverse(t *testing.T) {
+	assert.Equal(t, 0, Reverse(5, 0))
+	assert.Equal(t, 0b100, Reverse(0b001, 3))
+	assert.Equal(t, 0b011, Reverse(0b110, 3))
+	// the bits above logN are ignored
+	assert.Equal(t, 0b100, Reverse(0b1001, 3))
+	assert.Equal(t, 1<<62, Reverse(1, 63))
+}
+
+func TestReverseSlice(t *testing.T) {
+
+	for logN := 0; logN < 8; logN++ {
+
+		a := make([]int, 1<<logN)
+		for i := range a {
+			a[i] = i
+		}
+
+		ReverseSlice(a)
+		for i := range a {
+			require.Equal(t, Reverse(i, logN), a[i], "logN=%v i=%v", logN, i)
+		}
+
+		// the permutation is an involution
+		ReverseSlice(a)
+		for i := range a {
+			require.Equal(t, i, a[i])
+		}
+	}
+
+	assert.Panics(t, func() { ReverseSlice(make([]int, 6)) })
+	assert.NotPanics(t, func() { ReverseSlice([]int{}) })
+}
+
+func TestDecompose(t *testing.T) {
+	assert.Equal(t, []uint64{3, 2, 1, 0}, Decompose(0x123, 16, 4))
+	assert.Equal(t, []uint64{1, 0, 1, 1}, ToBits(0b1101, 4))
+	assert.Equal(t, []uint64{0, 0}, ToBits(0, 2))
+	assert.Panics(t, func() { ToBits(0b1101, 3) })
+	assert.Panics(t, func() { Decompose(1, 1, 3) })
+}
+
+func TestPopCountAndParity(t *testing.T) {
+
+	limbs := []field.Element{
+		field.NewElement(0b1011),
+		field.NewElement(0),
+		field.NewElement(1 << 63),
+	}
+
+	assert.Equal(t, 4, PopCount(limbs))
+	assert.Equal(t, 0, Parity(limbs))
+	assert.Equal(t, 1, Parity(limbs[:1]))
+
+	// The bits are counted on the canonical representation, not on the
+	// Montgomery form of the elements.
+	var (
+		minusOne        field.Element
+		modulusMinusOne = new(big.Int).Sub(field.Modulus(), big.NewInt(1))
+		expected        = 0
+	)
+	minusOne.SetInt64(-1)
+	for i := 0; i < modulusMinusOne.BitLen(); i++ {
+		expected += int(modulusMinusOne.Bit(i))
+	}
+	assert.Equal(t, expected, PopCount([]field.Element{minusOne}))
+}
+
+func TestLimbsAndBytes(t *testing.T) {
+
+	b := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	limbs := BytesToLimbs(b, 2)
+
+	require.Len(t, limbs, 3)
+	assert.Equal(t, field.NewElement(0x0102), limbs[0])
+	assert.Equal(t, field.NewElement(0x0506), limbs[2])
+	assert.Equal(t, b, LimbsToBytes(limbs, 2))
+
+	// 0x0102 does not fit on a single byte
+	assert.Panics(t, func() { LimbsToBytes(limbs, 1) })
+	assert.Panics(t, func() { BytesToLimbs(b, 4) })
+}
+
+func TestLimbsAndBytesFullSize(t *testing.T) {
+
+	var minusOne field.Element
+	minusOne.SetInt64(-1)
+
+	limbs := []field.Element{field.NewElement(1), minusOne, field.NewElement(0)}
+	b := LimbsToBytes(limbs, field.Bytes)
+
+	require.Len(t, b, 3*field.Bytes)
+	assert.Equal(t, limbs, BytesToLimbs(b, field.Bytes))
+	assert.Equal(t, b, LimbsToBytes(BytesToLimbs(b, field.Bytes), field.Bytes))
+
+	// A full-size limb encoding a value larger than the modulus is rejected
+	// rather than reduced.
+	modulus := field.Modulus().FillBytes(make([]byte, field.Bytes))
+	assert.Panics(t, func() { BytesToLimbs(modulus, field.Bytes) })
+	assert.Panics(t, func() { BytesToLimbs(b, field.Bytes+1) })
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CanonicalJSON returns the canonical JSON encoding of v. The value is first
+// encoded with [json.Marshal], so the json tags are honored, and the result
+// is rewritten so that:
+//   - the keys of the objects are sorted,
+//   - there is no whitespace,
+//   - the integers are written in decimal without exponent and the other
+//     numbers in the shortest form parsing back to the same float64,
+//   - the strings are not HTML-escaped.
+//
+// The encoding thus only depends on the content of v and not on the order of
+// the fields of its structs. It is meant for the checksums which must not
+// change when a struct is refactored, see [ChecksumJSON].
+func CanonicalJSON(v any) ([]byte, error) {
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode the value: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.UseNumber()
+
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("could not decode the encoded value: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, decoded); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ChecksumJSON returns the digest of the canonical JSON encoding of v, see
+// [CanonicalJSON] and [Digest].
+func ChecksumJSON(v any) (string, error) {
+	encoded, err := CanonicalJSON(v)
+	if err != nil {
+		return "", err
+	}
+	return Digest(bytes.NewReader(encoded))
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		n, err := canonicalNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(n)
+	case string:
+		writeCanonicalString(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, v[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected decoded JSON value of type %T", v)
+	}
+	return nil
+}
+
+// canonicalNumber formats the integers in decimal and the other numbers in
+// the shortest form parsing back to the same float64.
+func canonicalNumber(n json.Number) (string, error) {
+	s := n.String()
+
+	if !strings.ContainsAny(s, ".eE") {
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return "", fmt.Errorf("invalid JSON integer %q", s)
+		}
+		return i.String(), nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid JSON number %q: %w", s, err)
+	}
+	if f == 0 {
+		// also normalizes -0
+		return "0", nil
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	// encoding a string cannot fail
+	_ = enc.Encode(s)
+	// the encoder terminates the value with a newline
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,77 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/require"
+)
+
+type canonicalA struct {
+	Name   string         `json:"name"`
+	Limits map[string]int `json:"limits"`
+	Ratio  float64        `json:"ratio"`
+	Tags   []string       `json:"tags,omitempty"`
+}
+
+// canonicalB has the same fields as canonicalA in a different order
+type canonicalB struct {
+	Tags   []string       `json:"tags,omitempty"`
+	Ratio  float64        `json:"ratio"`
+	Limits map[string]int `json:"limits"`
+	Name   string         `json:"name"`
+}
+
+func TestCanonicalJSON(t *testing.T) {
+
+	a := canonicalA{
+		Name:   "<linea>",
+		Limits: map[string]int{"BIN": 1 << 18, "ADD": 1 << 20},
+		Ratio:  1e21,
+		Tags:   []string{"b", "a"},
+	}
+	b := canonicalB{Tags: a.Tags, Ratio: a.Ratio, Limits: a.Limits, Name: a.Name}
+
+	encoded, err := utils.CanonicalJSON(a)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"limits":{"ADD":1048576,"BIN":262144},"name":"<linea>","ratio":1e+21,"tags":["b","a"]}`,
+		string(encoded),
+	)
+
+	encodedB, err := utils.CanonicalJSON(b)
+	require.NoError(t, err)
+	require.Equal(t, string(encoded), string(encodedB))
+
+	// The digest is locked: changing it invalidates the checksums of the
+	// existing setups.
+	digest, err := utils.ChecksumJSON(a)
+	require.NoError(t, err)
+	require.Equal(t, "0x7b8ba83280988c05e5e6ac8226b29808b0ab75a4c61a8e050b752fcdda0b10f2", digest)
+
+	digestB, err := utils.ChecksumJSON(b)
+	require.NoError(t, err)
+	require.Equal(t, digest, digestB)
+}
+
+func TestCanonicalJSONNumbers(t *testing.T) {
+
+	encoded, err := utils.CanonicalJSON(map[string]any{
+		"x":   1.50,
+		"y":   -0.0,
+		"z":   1e-7,
+		"w":   100.0,
+		"big": 18446744073709551616.0,
+		"u":   uint64(18446744073709551615),
+	})
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"big":18446744073709552000,"u":18446744073709551615,"w":100,"x":1.5,"y":0,"z":1e-07}`,
+		string(encoded),
+	)
+}
+
+func TestCanonicalJSONError(t *testing.T) {
+	_, err := utils.CanonicalJSON(func() {})
+	require.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrOverflow is returned (wrapped) by the checked arithmetic functions when
+// the result of the operation cannot be represented in the operands' type.
+var ErrOverflow = errors.New("integer overflow")
+
+// AddChecked returns a + b or an error wrapping [ErrOverflow] if the sum
+// overflows the type T.
+func AddChecked[T constraints.Integer](a, b T) (T, error) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return 0, fmt.Errorf("%w: %v + %v", ErrOverflow, a, b)
+	}
+	return c, nil
+}
+
+// MulChecked returns a * b or an error wrapping [ErrOverflow] if the product
+// overflows the type T.
+func MulChecked[T constraints.Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+
+	c := a * b
+
+	// The sign check catches the MinInt * -1 case that the division check
+	// misses since MinInt / -1 silently wraps around to MinInt.
+	if c/b != a || (c < 0) != ((a < 0) != (b < 0)) {
+		return 0, fmt.Errorf("%w: %v * %v", ErrOverflow, a, b)
+	}
+	return c, nil
+}
+
+// DivCeilChecked returns the ceiled value of a / b. Unlike [DivCeil], it
+// returns an error if b is zero or if the division overflows (which can only
+// happen when dividing the smallest signed value by -1).
+func DivCeilChecked[T constraints.Integer](a, b T) (T, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("division by zero: %v / 0", a)
+	}
+
+	if b < 0 && a < 0 && -a < 0 {
+		// a is the smallest value of a signed type and -a wraps around
+		return 0, fmt.Errorf("%w: %v / %v", ErrOverflow, a, b)
+	}
+
+	q := a / b
+	if a%b != 0 && (a < 0) == (b < 0) {
+		q++
+	}
+	return q, nil
+}
+
+// MulDivCeilChecked returns the ceiled value of (a * b) / c and returns an
+// error if the product a * b overflows or if c is zero. This is a common
+// pattern when sizing modules from trace limits.
+func MulDivCeilChecked[T constraints.Integer](a, b, c T) (T, error) {
+	ab, err := MulChecked(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return DivCeilChecked(ab, c)
+}
@@ -0,0 +1,81 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddChecked(t *testing.T) {
+
+	res, err := utils.AddChecked(3, 4)
+	require.NoError(t, err)
+	require.Equal(t, 7, res)
+
+	res, err = utils.AddChecked(-3, 4)
+	require.NoError(t, err)
+	require.Equal(t, 1, res)
+
+	_, err = utils.AddChecked(math.MaxInt, 1)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+
+	_, err = utils.AddChecked(math.MinInt, -1)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+
+	_, err = utils.AddChecked[uint8](200, 100)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+}
+
+func TestMulChecked(t *testing.T) {
+
+	res, err := utils.MulChecked(1<<20, 1<<20)
+	require.NoError(t, err)
+	require.Equal(t, 1<<40, res)
+
+	res, err = utils.MulChecked(-5, 7)
+	require.NoError(t, err)
+	require.Equal(t, -35, res)
+
+	res, err = utils.MulChecked(0, math.MaxInt)
+	require.NoError(t, err)
+	require.Equal(t, 0, res)
+
+	_, err = utils.MulChecked(1<<32, 1<<32)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+
+	_, err = utils.MulChecked(math.MinInt, -1)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+
+	_, err = utils.MulChecked[int32](1<<16, 1<<16)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+}
+
+func TestDivCeilChecked(t *testing.T) {
+
+	for a := 0; a < 50; a++ {
+		for b := 1; b < 12; b++ {
+			res, err := utils.DivCeilChecked(a, b)
+			require.NoError(t, err)
+			require.Equal(t, utils.DivCeil(a, b), res, "a=%v b=%v", a, b)
+		}
+	}
+
+	res, err := utils.DivCeilChecked(-7, 2)
+	require.NoError(t, err)
+	require.Equal(t, -3, res)
+
+	res, err = utils.DivCeilChecked(-7, -2)
+	require.NoError(t, err)
+	require.Equal(t, 4, res)
+
+	_, err = utils.DivCeilChecked(3, 0)
+	require.Error(t, err)
+
+	_, err = utils.DivCeilChecked(math.MinInt, -1)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+
+	_, err = utils.MulDivCeilChecked(math.MaxInt/2, 3, 4)
+	require.ErrorIs(t, err, utils.ErrOverflow)
+}
@@ -0,0 +1,260 @@
+// Package dag provides a generic directed graph and the ordering algorithms
+// needed by the compiler passes: a deterministic topological sort with cycle
+// diagnostics and the detection of strongly connected components.
+package dag
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Graph is a directed graph whose nodes are identified by comparable values,
+// typically column or query IDs. The nodes are indexed by order of insertion
+// and all the algorithms of the package break ties using this order. This
+// makes their results deterministic: for the same sequence of insertions, the
+// same ordering is returned.
+//
+// An edge `from -> to` means that `from` must come before `to`, e.g. `to`
+// depends on `from`.
+type Graph[N comparable] struct {
+	nodes []N
+	index map[N]int
+	// succ and pred store the adjacency lists in both directions, by node
+	// index. The lists are in order of insertion of the edges.
+	succ, pred [][]int
+	edges      map[[2]int]struct{}
+}
+
+// New returns an empty graph
+func New[N comparable]() *Graph[N] {
+	return &Graph[N]{
+		index: map[N]int{},
+		edges: map[[2]int]struct{}{},
+	}
+}
+
+// AddNode adds a node to the graph. The function is a no-op if the node is
+// already in the graph.
+func (g *Graph[N]) AddNode(n N) {
+	g.addNode(n)
+}
+
+// addNode adds a node if needed and returns its index
+func (g *Graph[N]) addNode(n N) int {
+	if i, ok := g.index[n]; ok {
+		return i
+	}
+	i := len(g.nodes)
+	g.index[n] = i
+	g.nodes = append(g.nodes, n)
+	g.succ = append(g.succ, nil)
+	g.pred = append(g.pred, nil)
+	return i
+}
+
+// AddEdge adds an edge stating that `from` must come before `to`. The nodes
+// are added to the graph if they are not already present. Adding the same
+// edge twice has no effect.
+func (g *Graph[N]) AddEdge(from, to N) {
+	f, t := g.addNode(from), g.addNode(to)
+	if _, ok := g.edges[[2]int{f, t}]; ok {
+		return
+	}
+	g.edges[[2]int{f, t}] = struct{}{}
+	g.succ[f] = append(g.succ[f], t)
+	g.pred[t] = append(g.pred[t], f)
+}
+
+// HasNode returns true if the node is in the graph
+func (g *Graph[N]) HasNode(n N) bool {
+	_, ok := g.index[n]
+	return ok
+}
+
+// Len returns the number of nodes of the graph
+func (g *Graph[N]) Len() int {
+	return len(g.nodes)
+}
+
+// Nodes returns the nodes of the graph in order of insertion
+func (g *Graph[N]) Nodes() []N {
+	return append([]N{}, g.nodes...)
+}
+
+// Successors returns the nodes that must come after `n` and are directly
+// connected to it, in order of insertion of the edges. The function panics if
+// the node is not in the graph.
+func (g *Graph[N]) Successors(n N) []N {
+	return g.toNodes(g.succ[g.mustIndex(n)])
+}
+
+// Predecessors returns the nodes that must come before `n` and are directly
+// connected to it, in order of insertion of the edges. The function panics if
+// the node is not in the graph.
+func (g *Graph[N]) Predecessors(n N) []N {
+	return g.toNodes(g.pred[g.mustIndex(n)])
+}
+
+func (g *Graph[N]) mustIndex(n N) int {
+	i, ok := g.index[n]
+	if !ok {
+		utils.Panic("node %v is not in the graph", n)
+	}
+	return i
+}
+
+func (g *Graph[N]) toNodes(indices []int) []N {
+	res := make([]N, len(indices))
+	for k, i := range indices {
+		res[k] = g.nodes[i]
+	}
+	return res
+}
+
+// CycleError is returned by [Graph.TopologicalSort] when the graph is not
+// acyclic. It reports one of the cycles of the graph.
+type CycleError[N comparable] struct {
+	// Cycle lists the nodes of the cycle in the order of the edges. The
+	// first node is the one inserted first in the graph and the edge from
+	// the last node back to the first one is implicit.
+	Cycle []N
+}
+
+func (e *CycleError[N]) Error() string {
+	parts := make([]string, 0, len(e.Cycle)+1)
+	for _, n := range e.Cycle {
+		parts = append(parts, fmt.Sprintf("%v", n))
+	}
+	parts = append(parts, fmt.Sprintf("%v", e.Cycle[0]))
+	return "dag: cycle detected: " + strings.Join(parts, " -> ")
+}
+
+// TopologicalSort returns the nodes of the graph ordered such that every node
+// comes after all its predecessors. Among the nodes that are ready at the same
+// time, the one inserted first comes first. In particular, the order of
+// insertion is returned if it is already a valid ordering.
+//
+// If the graph contains a cycle, the function returns a [*CycleError]
+// listing the nodes of one of the cycles.
+func (g *Graph[N]) TopologicalSort() ([]N, error) {
+
+	var (
+		n        = len(g.nodes)
+		inDegree = make([]int, n)
+		ready    = &minHeap{}
+		res      = make([]N, 0, n)
+	)
+
+	for i := range g.nodes {
+		inDegree[i] = len(g.pred[i])
+		if inDegree[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		res = append(res, g.nodes[i])
+		for _, s := range g.succ[i] {
+			inDegree[s]--
+			if inDegree[s] == 0 {
+				heap.Push(ready, s)
+			}
+		}
+	}
+
+	if len(res) < n {
+		return nil, &CycleError[N]{Cycle: g.findCycle(inDegree)}
+	}
+
+	return res, nil
+}
+
+// MustTopologicalSort is as [Graph.TopologicalSort] but panics if the graph
+// has a cycle.
+func (g *Graph[N]) MustTopologicalSort() []N {
+	res, err := g.TopologicalSort()
+	if err != nil {
+		utils.Panic("%v", err)
+	}
+	return res
+}
+
+// findCycle returns a cycle among the nodes that could not be sorted: those
+// with a positive remaining in-degree. Each of them has at least one
+// predecessor in the same situation so walking back the predecessors from any
+// of them eventually loops.
+func (g *Graph[N]) findCycle(inDegree []int) []N {
+
+	var (
+		start = -1
+		// posInPath[i] is the position of i in the path + 1, zero if the node
+		// is not on the path.
+		posInPath = make([]int, len(g.nodes))
+		path      = []int{}
+	)
+
+	for i := range inDegree {
+		if inDegree[i] > 0 {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		utils.Panic("no cycle to report")
+	}
+
+	curr := start
+	for posInPath[curr] == 0 {
+		path = append(path, curr)
+		posInPath[curr] = len(path)
+
+		next := -1
+		for _, p := range g.pred[curr] {
+			if inDegree[p] > 0 {
+				next = p
+				break
+			}
+		}
+		curr = next
+	}
+
+	// The path was walked backward, so the cycle is reversed and rotated to
+	// start from its smallest node.
+	cycle := path[posInPath[curr]-1:]
+	for l, r := 0, len(cycle)-1; l < r; l, r = l+1, r-1 {
+		cycle[l], cycle[r] = cycle[r], cycle[l]
+	}
+
+	minPos := 0
+	for k := range cycle {
+		if cycle[k] < cycle[minPos] {
+			minPos = k
+		}
+	}
+
+	rotated := make([]int, 0, len(cycle))
+	rotated = append(rotated, cycle[minPos:]...)
+	rotated = append(rotated, cycle[:minPos]...)
+	return g.toNodes(rotated)
+}
+
+// minHeap is a min-heap of node indices used to break ties by order of
+// insertion.
+type minHeap []int
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h minHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x any)        { *h = append(*h, x.(int)) }
+
+func (h *minHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,108 @@
+package dag_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils/dag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologicalSort(t *testing.T) {
+
+	g := dag.New[string]()
+	g.AddNode("d")
+	g.AddEdge("c", "a")
+	g.AddEdge("b", "a")
+	g.AddEdge("a", "e")
+	g.AddEdge("c", "a") // duplicate edges are ignored
+
+	res, err := g.TopologicalSort()
+	require.NoError(t, err)
+	// "d" and "c" are ready first and "d" was inserted first
+	assert.Equal(t, []string{"d", "c", "b", "a", "e"}, res)
+	assert.Equal(t, []string{"c", "b"}, g.Predecessors("a"))
+}
+
+func TestTopologicalSortKeepsValidInsertionOrder(t *testing.T) {
+
+	g := dag.New[int]()
+	for i := 0; i < 100; i++ {
+		g.AddNode(i)
+	}
+
+	for i := 0; i < 100; i++ {
+		for j := i + 1; j < 100; j += 1 + i%7 {
+			g.AddEdge(i, j)
+		}
+	}
+
+	res := g.MustTopologicalSort()
+	for i := range res {
+		assert.Equal(t, i, res[i])
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+
+	g := dag.New[string]()
+	g.AddEdge("x", "a")
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "a")
+	g.AddEdge("c", "y")
+
+	_, err := g.TopologicalSort()
+	require.Error(t, err)
+
+	var cycleErr *dag.CycleError[string]
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"a", "b", "c"}, cycleErr.Cycle)
+	assert.Equal(t, "dag: cycle detected: a -> b -> c -> a", err.Error())
+
+	assert.Panics(t, func() { g.MustTopologicalSort() })
+}
+
+func TestTopologicalSortSelfLoop(t *testing.T) {
+
+	g := dag.New[string]()
+	g.AddEdge("a", "a")
+
+	_, err := g.TopologicalSort()
+	assert.EqualError(t, err, "dag: cycle detected: a -> a")
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+
+	g := dag.New[int]()
+	g.AddNode(7)
+	g.AddEdge(5, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 1)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 6)
+	g.AddEdge(6, 4)
+	g.AddEdge(8, 8)
+
+	assert.Equal(t,
+		[][]int{{7}, {5}, {1, 2, 3}, {4, 6}, {8}},
+		g.StronglyConnectedComponents(),
+	)
+}
+
+func TestStronglyConnectedComponentsDeep(t *testing.T) {
+
+	// A long chain closed into a single cycle checks that the implementation
+	// does not rely on recursion.
+	const n = 1 << 16
+
+	g := dag.New[int]()
+	for i := 0; i < n; i++ {
+		g.AddEdge(i, (i+1)%n)
+	}
+
+	comps := g.StronglyConnectedComponents()
+	require.Len(t, comps, 1)
+	assert.Len(t, comps[0], n)
+}
@@ -0,0 +1,138 @@
+package dag
+
+import "slices"
+
+// StronglyConnectedComponents returns the strongly connected components of
+// the graph: the maximal sets of nodes that can all reach each other. A node
+// that is not on any cycle forms a component on its own.
+//
+// The components are returned in a topological order of the condensed graph:
+// if there is an edge from a node of component A to a node of component B,
+// then A comes before B. Ties are broken by the order of insertion of the
+// first node of each component and the nodes of a component are listed by
+// order of insertion.
+func (g *Graph[N]) StronglyConnectedComponents() [][]N {
+
+	var (
+		comps     = g.tarjan()
+		compOf    = make([]int, len(g.nodes))
+		condensed = New[int]()
+	)
+
+	for c, comp := range comps {
+		for _, i := range comp {
+			compOf[i] = c
+		}
+	}
+
+	// The components are inserted by order of their first node so that the
+	// topological sort below breaks ties accordingly.
+	order := make([]int, len(comps))
+	for c := range order {
+		order[c] = c
+	}
+
+	slices.SortFunc(order, func(a, b int) int { return comps[a][0] - comps[b][0] })
+
+	for _, c := range order {
+		condensed.AddNode(c)
+	}
+
+	for f := range g.nodes {
+		for _, t := range g.succ[f] {
+			if compOf[f] != compOf[t] {
+				condensed.AddEdge(compOf[f], compOf[t])
+			}
+		}
+	}
+
+	res := make([][]N, 0, len(comps))
+	for _, c := range condensed.MustTopologicalSort() {
+		res = append(res, g.toNodes(comps[c]))
+	}
+
+	return res
+}
+
+// tarjan runs Tarjan's algorithm and returns the components as lists of node
+// indices sorted in increasing order. The algorithm is implemented with an
+// explicit stack so that deep graphs do not exhaust the goroutine stack.
+func (g *Graph[N]) tarjan() [][]int {
+
+	type frame struct {
+		node, nextSucc int
+	}
+
+	var (
+		n       = len(g.nodes)
+		index   = make([]int, n) // visiting index + 1, zero if unvisited
+		lowLink = make([]int, n)
+		onStack = make([]bool, n)
+		stack   = []int{}
+		counter = 0
+		res     = [][]int{}
+	)
+
+	for root := 0; root < n; root++ {
+
+		if index[root] != 0 {
+			continue
+		}
+
+		callStack := []frame{{node: root}}
+		counter++
+		index[root], lowLink[root] = counter, counter
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(callStack) > 0 {
+
+			top := &callStack[len(callStack)-1]
+			v := top.node
+
+			if top.nextSucc < len(g.succ[v]) {
+				w := g.succ[v][top.nextSucc]
+				top.nextSucc++
+
+				switch {
+				case index[w] == 0:
+					counter++
+					index[w], lowLink[w] = counter, counter
+					stack = append(stack, w)
+					onStack[w] = true
+					callStack = append(callStack, frame{node: w})
+				case onStack[w]:
+					lowLink[v] = min(lowLink[v], index[w])
+				}
+				continue
+			}
+
+			// All the successors of v are processed
+			callStack = callStack[:len(callStack)-1]
+			if len(callStack) > 0 {
+				parent := callStack[len(callStack)-1].node
+				lowLink[parent] = min(lowLink[parent], lowLink[v])
+			}
+
+			if lowLink[v] != index[v] {
+				continue
+			}
+
+			comp := []int{}
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+
+			slices.Sort(comp)
+			res = append(res, comp)
+		}
+	}
+
+	return res
+}
@@ -3,10 +3,9 @@ package utils
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 	"math/big"
-	"strings"
 
-	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -58,23 +57,56 @@ func ApplyModulusBn254(b string) string {
 	return hexutil.Encode(fbytes[:])
 }
 
-// Apply the modulus
-func ApplyModulusBls12377(b string) string {
-	var f bls12377fr.Element
-	f.SetString(b)
-	fbytes := f.Bytes()
-	return hexutil.Encode(fbytes[:])
+// HexDecodeString decodes an hex string. The "0x" prefix is optional and the
+// digits may be upper or lower-case so that the strings produced by the other
+// components of the stack are accepted as they are. The returned error quotes
+// the start of the string and wraps the error of [hex.DecodeString].
+func HexDecodeString(s string) ([]byte, error) {
+	digits := trimHexPrefix(s)
+	res, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string %v: %w", hexExcerpt(s), err)
+	}
+	return res, nil
 }
 
-func HexDecodeString(s string) ([]byte, error) {
-	s = strings.TrimPrefix(s, "0x")
-	return hex.DecodeString(s)
+// HexDecodeFixed decodes an hex string as [HexDecodeString] does and returns
+// an error if it does not encode exactly n bytes.
+func HexDecodeFixed(s string, n int) ([]byte, error) {
+	res, err := HexDecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != n {
+		return nil, fmt.Errorf("invalid hex string %v: expected %v bytes but got %v", hexExcerpt(s), n, len(res))
+	}
+	return res, nil
 }
 
+// HexEncodeToString encodes b in the canonical form: "0x" prefixed with
+// lower-case digits.
 func HexEncodeToString(b []byte) string {
 	return "0x" + hex.EncodeToString(b)
 }
 
+// trimHexPrefix removes the "0x" or "0X" prefix of s if any
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
+
+// hexExcerpt quotes the start of an hex string to report it in an error
+// without dumping the whole of a proof or of a blob in the logs.
+func hexExcerpt(s string) string {
+	const maxLen = 24
+	if len(s) > maxLen {
+		return fmt.Sprintf("%q (%v chars)", s[:maxLen]+"...", len(s))
+	}
+	return fmt.Sprintf("%q", s)
+}
+
 // Compute the keccak of a stream of bytes. Returns the hex string.
 func KeccakHash(stream []byte) []byte {
 	h := sha3.NewLegacyKeccak256()
@@ -73,3 +73,36 @@ func TestHexEncodeToString(t *testing.T) {
 		})
 	}
 }
+
+func TestHexDecodeFixed(t *testing.T) {
+	tests := []struct {
+		desc, input string
+		n           int
+		want        []byte
+		wantErr     bool
+	}{
+		{"Lower-case", "0xabcdef", 3, []byte{0xab, 0xcd, 0xef}, false},
+		{"Upper-case", "0XABCDEF", 3, []byte{0xab, 0xcd, 0xef}, false},
+		{"Mixed-case without prefix", "aBcDeF", 3, []byte{0xab, 0xcd, 0xef}, false},
+		{"Too short", "0xabcd", 3, nil, true},
+		{"Too long", "0xabcdef01", 3, nil, true},
+		{"Not hex", "0xabcdeg", 3, nil, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := utils.HexDecodeFixed(tt.input, tt.n)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("test case %d: expected error but got nil", i)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("test case %d: unexpected error: %s", i, err)
+			} else if !bytes.Equal(got, tt.want) {
+				t.Errorf("test case %d:\nwant: %q\ngot: %q", i, tt.want, got)
+			}
+		})
+	}
+}
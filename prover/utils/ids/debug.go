@@ -0,0 +1,7 @@
+//go:build debug
+
+package ids
+
+// Debug is true when the binary is built with the debug tag. The detection
+// of the identifier collisions is only enabled in that case.
+const Debug = true
@@ -0,0 +1,129 @@
+// Package ids checks the identifiers of the circuits, the columns and the
+// queries. [Validate] checks their format and a [Detector] reports the ones
+// defined twice along with the location of their first definition. Duplicate
+// identifiers are typically copy-pastes: depending on where they are
+// registered, they either panic far from where the copy happened or silently
+// shadow one another.
+//
+// The detectors are only active in the builds with the debug tag, see
+// [Debug], as recording the definition sites is expensive for the large
+// protocols.
+package ids
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Validate returns an error if the identifier is empty, has leading or
+// trailing spaces or contains a non-printable character. The inner spaces are
+// allowed as the compilers derive some identifiers from formatted lists.
+func Validate(id string) error {
+	if len(id) == 0 {
+		return fmt.Errorf("empty identifier")
+	}
+	if strings.TrimSpace(id) != id {
+		return fmt.Errorf("identifier %q has leading or trailing spaces", id)
+	}
+	for _, r := range id {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("identifier %q contains the invalid character %q", id, r)
+		}
+	}
+	return nil
+}
+
+// Global is the process-wide detector, for the identifiers that are unique
+// in the whole process.
+var Global = NewDetector()
+
+// Detector records the identifiers claimed in each namespace along with the
+// location of the code claiming them. It is safe for concurrent use.
+type Detector struct {
+	mu     sync.Mutex
+	claims map[string]map[string]string
+	// skipPkgs lists the packages whose frames are not reported as the
+	// definition site
+	skipPkgs []string
+}
+
+// NewDetector returns an empty detector. The frames of the skipPkgs packages
+// (given by their import path) are skipped when locating the definition
+// site, e.g. the package registering the identifiers on behalf of its
+// callers.
+func NewDetector(skipPkgs ...string) *Detector {
+	return &Detector{claims: map[string]map[string]string{}, skipPkgs: skipPkgs}
+}
+
+// Claim records the identifier in the namespace. It returns an error if the
+// identifier is invalid or was already claimed in the namespace, giving the
+// location of the first claim.
+func (d *Detector) Claim(namespace, id string) error {
+
+	if err := Validate(id); err != nil {
+		return fmt.Errorf("%v: %w", namespace, err)
+	}
+
+	site := d.callSite()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	claims, ok := d.claims[namespace]
+	if !ok {
+		claims = map[string]string{}
+		d.claims[namespace] = claims
+	}
+
+	if first, ok := claims[id]; ok {
+		return fmt.Errorf("%v %q is defined twice: at %v and at %v", namespace, id, first, site)
+	}
+
+	claims[id] = site
+	return nil
+}
+
+// MustClaim is as [Detector.Claim] but panics on error
+func (d *Detector) MustClaim(namespace, id string) {
+	if err := d.Claim(namespace, id); err != nil {
+		panic(err)
+	}
+}
+
+// callSite returns the location of the first caller outside of this package
+// and of the skipped packages.
+func (d *Detector) callSite() string {
+
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !d.skipped(frame.Function) {
+			return fmt.Sprintf("%v:%v", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// skipped returns true if the function belongs to this package or to one of
+// the skipped packages
+func (d *Detector) skipped(function string) bool {
+	if strings.HasPrefix(function, thisPkg+".") {
+		return true
+	}
+	for _, pkg := range d.skipPkgs {
+		if strings.HasPrefix(function, pkg+".") {
+			return true
+		}
+	}
+	return false
+}
+
+const thisPkg = "github.com/consensys/linea-monorepo/prover/utils/ids"
@@ -0,0 +1,36 @@
+package ids_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+
+	for _, id := range []string{"A", "KECCAK_INPUT_0", "CYCLIC_COUNTER_12_42", "sub-circuit.counter", "TABLE_(A, B)"} {
+		require.NoError(t, ids.Validate(id), id)
+	}
+
+	for _, id := range []string{"", " A", "A\n", "A\tB", "A\x00"} {
+		require.Error(t, ids.Validate(id), "%q", id)
+	}
+}
+
+func TestDetector(t *testing.T) {
+
+	d := ids.NewDetector()
+
+	require.NoError(t, d.Claim("query", "A"))
+	require.NoError(t, d.Claim("coin", "A"), "the namespaces are independent")
+	require.NoError(t, d.Claim("query", "B"))
+	require.Error(t, d.Claim("query", ""))
+
+	err := d.Claim("query", "A")
+	require.Error(t, err)
+	// Both definition sites are reported
+	require.Regexp(t, `at .*ids_test.go:\d+ and at .*ids_test.go:\d+`, err.Error())
+
+	require.Panics(t, func() { d.MustClaim("coin", "A") })
+}
@@ -0,0 +1,241 @@
+// Package numa places the workers and the large buffers of the prover on the
+// NUMA nodes of the machine. On dual-socket machines, the phases that are bound
+// by the memory bandwidth (FFTs, SIS hashing) suffer from the traffic between
+// the nodes. The package offers two mitigations, selected via [Configure]:
+//
+//   - [PolicyLocal] pins the workers of the [parallel] package on the CPUs of
+//     a node (round-robin on the worker ID) so that the memory they allocate
+//     and touch first is local to their node.
+//   - [PolicyInterleave] does not pin the workers.
+//
+// With both policies, the large buffers that are shared by all the workers
+// (e.g. the Reed-Solomon encoded matrix of Vortex) are interleaved page by page
+// across all the nodes via [Interleave], so that no node serves all the
+// traffic.
+//
+// The placement relies on sched_setaffinity and mbind and is only implemented
+// on Linux. On other platforms, or when the machine has a single node,
+// [Configure] falls back to [PolicyNone] and all the functions of the package
+// are no-ops.
+//
+// [parallel]: github.com/consensys/linea-monorepo/prover/utils/parallel
+package numa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Policy selects how the workers and the large buffers are placed
+type Policy string
+
+const (
+	// PolicyNone leaves the placement to the OS. This is the default.
+	PolicyNone Policy = "none"
+	// PolicyLocal pins the workers on the nodes and interleaves the shared
+	// buffers.
+	PolicyLocal Policy = "local"
+	// PolicyInterleave interleaves the shared buffers without pinning the
+	// workers.
+	PolicyInterleave Policy = "interleave"
+)
+
+// MinInterleaveBytes is the size under which [Interleave] ignores the buffers.
+// The smaller buffers are not worth a system call.
+const MinInterleaveBytes = 2 << 20
+
+// Node is a NUMA node of the machine
+type Node struct {
+	ID   int
+	CPUs []int
+}
+
+// Topology lists the NUMA nodes of the machine having at least one CPU
+type Topology struct {
+	Nodes []Node
+}
+
+// state is the configuration set by [Configure]
+type state struct {
+	policy   Policy
+	topology *Topology
+}
+
+var current atomic.Pointer[state]
+
+// Configure detects the topology of the machine and enables the policy for
+// the rest of the process. It returns an error if the policy is unknown. If
+// the topology cannot be detected or has a single node, the function logs a
+// warning and falls back to [PolicyNone].
+func Configure(policy Policy) error {
+
+	switch policy {
+	case "", PolicyNone:
+		current.Store(nil)
+		return nil
+	case PolicyLocal, PolicyInterleave:
+	default:
+		return fmt.Errorf("unknown NUMA policy %q", policy)
+	}
+
+	topology, err := Detect()
+	if err != nil {
+		logrus.Warnf("NUMA: could not detect the topology, falling back to the policy %q: %v", PolicyNone, err)
+		current.Store(nil)
+		return nil
+	}
+
+	if len(topology.Nodes) < 2 {
+		logrus.Infof("NUMA: the machine has a single node, falling back to the policy %q", PolicyNone)
+		current.Store(nil)
+		return nil
+	}
+
+	logrus.Infof("NUMA: using the policy %q on %v nodes", policy, len(topology.Nodes))
+	current.Store(&state{policy: policy, topology: topology})
+	return nil
+}
+
+// CurrentPolicy returns the policy in use
+func CurrentPolicy() Policy {
+	if s := current.Load(); s != nil {
+		return s.policy
+	}
+	return PolicyNone
+}
+
+// PinWorker pins the calling goroutine on the CPUs of the node assigned to the
+// worker and returns a function undoing it. The caller is expected to defer
+// the returned function before returning. It is a no-op unless the policy is
+// [PolicyLocal].
+func PinWorker(workerID int) (release func()) {
+
+	s := current.Load()
+	if s == nil || s.policy != PolicyLocal {
+		return func() {}
+	}
+
+	node := s.topology.Nodes[workerID%len(s.topology.Nodes)]
+	release, err := pinThread(node.CPUs)
+	if err != nil {
+		logrus.Debugf("NUMA: could not pin worker %v on node %v: %v", workerID, node.ID, err)
+		return func() {}
+	}
+
+	return release
+}
+
+// Interleave requests the pages of buf to be spread across all the nodes. The
+// pages that are already allocated are moved. It is a no-op under
+// [PolicyNone] and for buffers smaller than [MinInterleaveBytes]. As only the
+// pages fully included in the buffer are affected, the memory of the buffer is
+// never shared with another object.
+func Interleave[T any](buf []T) {
+
+	s := current.Load()
+	if s == nil || len(buf) == 0 {
+		return
+	}
+
+	size := uintptr(len(buf)) * unsafe.Sizeof(buf[0])
+	if size < MinInterleaveBytes {
+		return
+	}
+
+	nodes := make([]int, len(s.topology.Nodes))
+	for i := range nodes {
+		nodes[i] = s.topology.Nodes[i].ID
+	}
+
+	if err := interleave(unsafe.Pointer(&buf[0]), size, nodes); err != nil {
+		logrus.Debugf("NUMA: could not interleave a buffer of %v bytes: %v", size, err)
+	}
+	runtime.KeepAlive(buf)
+}
+
+// parseList parses a list of integers in the format used by the Linux kernel
+// in sysfs, e.g. "0-3,8,10-11".
+func parseList(s string) ([]int, error) {
+
+	var res []int
+
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return res, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+
+		lo, hi, isRange := strings.Cut(part, "-")
+
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid list %q: %w", s, err)
+		}
+
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("invalid list %q: %w", s, err)
+			}
+		}
+
+		if first < 0 || last < first {
+			return nil, fmt.Errorf("invalid list %q: bad range %q", s, part)
+		}
+
+		for i := first; i <= last; i++ {
+			res = append(res, i)
+		}
+	}
+
+	return res, nil
+}
+
+// detectFrom reads the topology from a sysfs node directory, usually
+// /sys/devices/system/node.
+func detectFrom(dir string) (*Topology, error) {
+
+	online, err := os.ReadFile(filepath.Join(dir, "online"))
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := parseList(string(online))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Topology{}
+	for _, id := range ids {
+
+		cpuList, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("node%d", id), "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+
+		cpus, err := parseList(string(cpuList))
+		if err != nil {
+			return nil, err
+		}
+
+		// Memory-only nodes cannot run workers
+		if len(cpus) > 0 {
+			res.Nodes = append(res.Nodes, Node{ID: id, CPUs: cpus})
+		}
+	}
+
+	if len(res.Nodes) == 0 {
+		return nil, fmt.Errorf("no node with CPUs in %v", dir)
+	}
+
+	return res, nil
+}
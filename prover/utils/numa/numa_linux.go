@@ -0,0 +1,94 @@
+//go:build linux
+
+package numa
+
+import (
+	"os"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// Constants of the mbind system call, see linux/mempolicy.h
+	mpolInterleave = 3
+	mpolMfMove     = 1 << 1
+)
+
+// Detect returns the NUMA topology of the machine
+func Detect() (*Topology, error) {
+	return detectFrom("/sys/devices/system/node")
+}
+
+// pinThread locks the calling goroutine on its thread and restricts the
+// thread to the given CPUs. The returned function restores the previous
+// affinity before unlocking the thread, so that the thread can be reused by
+// other goroutines.
+func pinThread(cpus []int) (release func(), err error) {
+
+	runtime.LockOSThread()
+
+	var prev, set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &prev); err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	return func() {
+		// There is not much to do if this fails, the thread keeps a
+		// restricted but valid affinity.
+		_ = unix.SchedSetaffinity(0, &prev)
+		runtime.UnlockOSThread()
+	}, nil
+}
+
+// interleave calls mbind on the pages fully included in [addr, addr+size)
+func interleave(addr unsafe.Pointer, size uintptr, nodes []int) error {
+
+	var (
+		pageSize = uintptr(os.Getpagesize())
+		start    = (uintptr(addr) + pageSize - 1) &^ (pageSize - 1)
+		end      = (uintptr(addr) + size) &^ (pageSize - 1)
+		maxNode  = 0
+	)
+
+	if end <= start {
+		return nil
+	}
+
+	for _, n := range nodes {
+		maxNode = max(maxNode, n)
+	}
+
+	mask := make([]uint64, maxNode/64+1)
+	for _, n := range nodes {
+		mask[n/64] |= 1 << (n % 64)
+	}
+
+	// The kernel expects the number of bits of the mask plus one
+	_, _, errno := unix.Syscall6(
+		unix.SYS_MBIND,
+		start,
+		end-start,
+		mpolInterleave,
+		uintptr(unsafe.Pointer(&mask[0])),
+		uintptr(len(mask)*64+1),
+		mpolMfMove,
+	)
+
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+//go:build linux
+
+package numa
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestPinThread(t *testing.T) {
+
+	var before unix.CPUSet
+	require.NoError(t, unix.SchedGetaffinity(0, &before))
+
+	cpu := -1
+	for i := 0; i < 1024 && cpu < 0; i++ {
+		if before.IsSet(i) {
+			cpu = i
+		}
+	}
+	require.GreaterOrEqual(t, cpu, 0)
+
+	// The test goroutine is locked to observe the affinity of the same thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	release, err := pinThread([]int{cpu})
+	require.NoError(t, err)
+
+	var pinned unix.CPUSet
+	require.NoError(t, unix.SchedGetaffinity(0, &pinned))
+	assert.Equal(t, 1, pinned.Count())
+	assert.True(t, pinned.IsSet(cpu))
+
+	release()
+
+	var after unix.CPUSet
+	require.NoError(t, unix.SchedGetaffinity(0, &after))
+	assert.Equal(t, before, after)
+}
+
+func TestInterleave(t *testing.T) {
+
+	buf := make([]byte, 2*MinInterleaveBytes)
+
+	// mbind is not allowed in all the sandboxes
+	if err := interleave(unsafe.Pointer(&buf[0]), uintptr(len(buf)), []int{0}); err != nil {
+		t.Skipf("mbind is not available: %v", err)
+	}
+
+	// A buffer smaller than a page is a no-op
+	assert.NoError(t, interleave(unsafe.Pointer(&buf[1]), 16, []int{0}))
+}
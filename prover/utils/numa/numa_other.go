@@ -0,0 +1,23 @@
+//go:build !linux
+
+package numa
+
+import (
+	"errors"
+	"unsafe"
+)
+
+var errUnsupported = errors.New("NUMA placement is only supported on Linux")
+
+// Detect returns the NUMA topology of the machine
+func Detect() (*Topology, error) {
+	return nil, errUnsupported
+}
+
+func pinThread([]int) (func(), error) {
+	return nil, errUnsupported
+}
+
+func interleave(unsafe.Pointer, uintptr, []int) error {
+	return errUnsupported
+}
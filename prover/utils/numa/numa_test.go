@@ -0,0 +1,83 @@
+package numa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseList(t *testing.T) {
+
+	for s, expected := range map[string][]int{
+		"":              nil,
+		"0\n":           {0},
+		"0-3":           {0, 1, 2, 3},
+		"0-1,8,10-11\n": {0, 1, 8, 10, 11},
+	} {
+		res, err := parseList(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, res, s)
+	}
+
+	for _, s := range []string{"a", "0-", "3-1", "-1", "0,,1"} {
+		_, err := parseList(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDetectFrom(t *testing.T) {
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	write("online", "0-2\n")
+	write("node0/cpulist", "0-3,8-11\n")
+	write("node1/cpulist", "4-7,12-15\n")
+	// A memory-only node, e.g. CXL memory
+	write("node2/cpulist", "\n")
+
+	topology, err := detectFrom(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &Topology{Nodes: []Node{
+		{ID: 0, CPUs: []int{0, 1, 2, 3, 8, 9, 10, 11}},
+		{ID: 1, CPUs: []int{4, 5, 6, 7, 12, 13, 14, 15}},
+	}}, topology)
+
+	write("online", "0-3\n")
+	_, err = detectFrom(dir)
+	assert.Error(t, err, "node3 is missing")
+
+	_, err = detectFrom(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestPolicies(t *testing.T) {
+
+	defer current.Store(nil)
+
+	assert.Error(t, Configure("remote"))
+
+	require.NoError(t, Configure(PolicyNone))
+	assert.Equal(t, PolicyNone, CurrentPolicy())
+
+	// The no-ops must not fail
+	PinWorker(3)()
+	Interleave(make([]byte, 2*MinInterleaveBytes))
+
+	// Whatever the machine, the policy falls back to none or is enabled on
+	// all the nodes.
+	require.NoError(t, Configure(PolicyLocal))
+	if CurrentPolicy() == PolicyNone {
+		t.Skip("the machine has a single NUMA node")
+	}
+
+	assert.Equal(t, PolicyLocal, CurrentPolicy())
+	PinWorker(3)()
+	Interleave(make([]byte, 2*MinInterleaveBytes))
+}
@@ -4,6 +4,7 @@ import (
 	"runtime"
 	"sync"
 
+	"github.com/consensys/linea-monorepo/prover/utils/numa"
 	"github.com/sirupsen/logrus"
 )
 
@@ -37,6 +38,7 @@ func ExecuteChunky(nbIterations int, work func(start, stop int), numcpus ...int)
 	// Each goroutine consumes the jobChan to
 	for p := 0; p < numcpu; p++ {
 		go func() {
+			defer numa.PinWorker(p)()
 			for i := range jobChan {
 				work(i, i+1)
 				wg.Done()
@@ -3,6 +3,8 @@ package parallel
 import (
 	"runtime"
 	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/utils/numa"
 )
 
 // ExecuteJobStealing parallelizes a workload specified by a function consuming
@@ -32,6 +34,7 @@ func ExecuteFromChan(nbIterations int, work func(wg *sync.WaitGroup, indexChan c
 	// Each goroutine consumes the jobChan to
 	for p := 0; p < numcpu; p++ {
 		go func() {
+			defer numa.PinWorker(p)()
 			work(wg, jobChan)
 		}()
 	}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/numa"
 )
 
 // Execute process in parallel the work function
@@ -45,6 +46,8 @@ func Execute(nbIterations int, work func(int, int), maxCpus ...int) {
 		}
 
 		go func() {
+			defer numa.PinWorker(i)()
+
 			// In case the subtask panics, we recover so that we can repanic in
 			// the main goroutine. Simplifying the process of tracing back the
 			// error and allowing to test the panics.
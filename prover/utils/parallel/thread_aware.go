@@ -3,6 +3,8 @@ package parallel
 import (
 	"runtime"
 	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/utils/numa"
 )
 
 type ThreadInit func(threadID int)
@@ -30,6 +32,7 @@ func ExecuteThreadAware(nbIterations int, init ThreadInit, worker Worker, numcpu
 	for p := 0; p < numcpu; p++ {
 		threadID := p
 		go func() {
+			defer numa.PinWorker(threadID)()
 			init(threadID)
 			for taskID := range jobChan {
 				worker(taskID, threadID)
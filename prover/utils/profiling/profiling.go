@@ -3,7 +3,9 @@ package profiling
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/consensys/linea-monorepo/prover/utils"
@@ -67,3 +69,24 @@ func ProfileTrace(name string, profiled, traced bool, fn func()) {
 
 	fn()
 }
+
+// WriteReport writes a report in the profiling directory of name, next to the
+// profiles written by [ProfileTrace].
+func WriteReport(name, file string, report io.WriterTo) error {
+
+	dir := fmt.Sprintf("profiling/%v", name)
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(dir, file))
+	if err != nil {
+		return err
+	}
+
+	if _, err := report.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -113,3 +113,15 @@ func (a *Account) UnmarshalJSON(data []byte) error {
 	}
 	return nil
 }
+
+// LeafExtraFields returns the fields that the versioned leaf layouts of the
+// accumulator append to the leaf of the account: the keccak code hash, on two
+// words so that they fit in the field.
+func (a Account) LeafExtraFields() []Bytes32 {
+	var buf bytes.Buffer
+	a.KeccakCodeHash.WriteTo(&buf)
+	return []Bytes32{
+		AsBytes32(buf.Bytes()[:32]),
+		AsBytes32(buf.Bytes()[32:]),
+	}
+}
@@ -0,0 +1,259 @@
+// Package watchdog implements a watchdog aborting the prover when one of its
+// phases stops making progress. The long-running parts of the prover (the
+// wizard runtime, the gnark provers) report their progress by calling
+// [Heartbeat]. If no heartbeat is received for longer than the configured
+// timeout, the watchdog writes a diagnostics bundle containing the stacks of
+// all the goroutines and the state of the current phase and terminates the
+// process with [ExitCode] instead of letting it hang forever.
+//
+// The heartbeats are no-ops until a watchdog is installed with [Install] so
+// the packages reporting their progress do not need to know whether a
+// watchdog is running.
+package watchdog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExitCode is the exit code of the process when the watchdog detects a
+// stalled phase. It matches the CodeStalled code of the controller.
+const ExitCode = 16
+
+// historySize is the number of past phases reported in the diagnostics bundle
+const historySize = 32
+
+// Config collects the parameters of a [Watchdog]
+type Config struct {
+	// StallTimeout is the maximal duration between two heartbeats
+	StallTimeout time.Duration
+	// LongPhaseTimeout is the maximal duration of the phases started with
+	// [BeginLongPhase] which cannot report progress while they run. If zero,
+	// StallTimeout is used.
+	LongPhaseTimeout time.Duration
+	// DiagnosticsDir is the directory in which the diagnostics bundles are
+	// written.
+	DiagnosticsDir string
+	// Job is a free-form description of the job being processed, it is
+	// included in the diagnostics bundle.
+	Job string
+}
+
+// Watchdog monitors the heartbeats of the prover. It should be constructed
+// with [New].
+type Watchdog struct {
+	cfg Config
+	// onStall is called with the path of the diagnostics bundle when a stall
+	// is detected. By default, it terminates the process.
+	onStall func(bundle string)
+
+	mu        sync.Mutex
+	phase     phaseState
+	history   []phaseState
+	startedAt time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// phaseState is the state of a phase as reported in the diagnostics bundle
+type phaseState struct {
+	Name          string        `json:"name"`
+	StartedAt     time.Time     `json:"startedAt"`
+	LastHeartbeat time.Time     `json:"lastHeartbeat"`
+	NumHeartbeats int           `json:"numHeartbeats"`
+	Timeout       time.Duration `json:"timeout"`
+}
+
+// current is the watchdog receiving the heartbeats, if any
+var current atomic.Pointer[Watchdog]
+
+// New returns a new watchdog. It does not start monitoring until [Watchdog.Start]
+// is called.
+func New(cfg Config) *Watchdog {
+
+	if cfg.StallTimeout <= 0 {
+		panic("the stall timeout of the watchdog must be positive")
+	}
+
+	if cfg.LongPhaseTimeout <= 0 {
+		cfg.LongPhaseTimeout = cfg.StallTimeout
+	}
+
+	return &Watchdog{
+		cfg: cfg,
+		onStall: func(bundle string) {
+			logrus.Errorf("watchdog: aborting the prover, diagnostics written in %v", bundle)
+			os.Exit(ExitCode)
+		},
+		stop: make(chan struct{}),
+	}
+}
+
+// Install sets w as the watchdog receiving the heartbeats. Passing nil
+// uninstalls the current watchdog.
+func Install(w *Watchdog) {
+	current.Store(w)
+}
+
+// Heartbeat reports progress in the phase named by the format string and the
+// arguments. It is a no-op if no watchdog is installed.
+func Heartbeat(format string, args ...any) {
+	if w := current.Load(); w != nil {
+		w.beat(fmt.Sprintf(format, args...), w.cfg.StallTimeout)
+	}
+}
+
+// BeginLongPhase is as [Heartbeat] but announces a phase that cannot report
+// its progress while it runs (e.g. a gnark proof generation). The phase is
+// allowed to run for the LongPhaseTimeout of the watchdog until the next
+// heartbeat.
+func BeginLongPhase(format string, args ...any) {
+	if w := current.Load(); w != nil {
+		w.beat(fmt.Sprintf(format, args...), w.cfg.LongPhaseTimeout)
+	}
+}
+
+// beat records a heartbeat for the given phase
+func (w *Watchdog) beat(phase string, timeout time.Duration) {
+
+	now := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if phase != w.phase.Name {
+		if w.phase.Name != "" {
+			w.history = append(w.history, w.phase)
+			if len(w.history) > historySize {
+				w.history = w.history[len(w.history)-historySize:]
+			}
+		}
+		w.phase = phaseState{Name: phase, StartedAt: now}
+	}
+
+	w.phase.LastHeartbeat = now
+	w.phase.NumHeartbeats++
+	w.phase.Timeout = timeout
+}
+
+// Start installs the watchdog and starts monitoring the heartbeats in the
+// background. Start counts as a first heartbeat.
+func (w *Watchdog) Start() {
+
+	w.startedAt = time.Now()
+	w.beat("start", w.cfg.StallTimeout)
+	Install(w)
+
+	go func() {
+
+		ticker := time.NewTicker(w.checkInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case now := <-ticker.C:
+				if w.isStalled(now) {
+					w.handleStall(now)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the monitoring and uninstalls the watchdog
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() {
+		current.CompareAndSwap(w, nil)
+		close(w.stop)
+	})
+}
+
+// checkInterval returns the period at which the heartbeats are checked
+func (w *Watchdog) checkInterval() time.Duration {
+	return max(w.cfg.StallTimeout/10, time.Millisecond)
+}
+
+// isStalled returns true if the current phase has not received a heartbeat
+// for longer than its timeout.
+func (w *Watchdog) isStalled(now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return now.Sub(w.phase.LastHeartbeat) > w.phase.Timeout
+}
+
+// handleStall writes the diagnostics bundle and calls onStall
+func (w *Watchdog) handleStall(now time.Time) {
+
+	w.mu.Lock()
+	phase := w.phase
+	w.mu.Unlock()
+
+	logrus.Errorf("watchdog: phase %q made no progress for %v (timeout %v)",
+		phase.Name, now.Sub(phase.LastHeartbeat).Round(time.Second), phase.Timeout)
+
+	bundle, err := w.writeDiagnostics(now)
+	if err != nil {
+		logrus.Errorf("watchdog: could not write the diagnostics bundle: %v", err)
+	}
+
+	w.onStall(bundle)
+}
+
+// writeDiagnostics writes the diagnostics bundle in a new directory of the
+// diagnostics directory and returns its path.
+func (w *Watchdog) writeDiagnostics(now time.Time) (string, error) {
+
+	dir := filepath.Join(w.cfg.DiagnosticsDir, fmt.Sprintf("stall-%v", now.Format("20060102-150405")))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("could not create %v: %w", dir, err)
+	}
+
+	stacks, err := os.Create(filepath.Join(dir, "goroutines.txt"))
+	if err != nil {
+		return dir, fmt.Errorf("could not create the goroutines dump: %w", err)
+	}
+	defer stacks.Close()
+
+	if err := pprof.Lookup("goroutine").WriteTo(stacks, 2); err != nil {
+		return dir, fmt.Errorf("could not dump the goroutines: %w", err)
+	}
+
+	w.mu.Lock()
+	state := struct {
+		Job          string       `json:"job"`
+		StartedAt    time.Time    `json:"startedAt"`
+		DetectedAt   time.Time    `json:"detectedAt"`
+		CurrentPhase phaseState   `json:"currentPhase"`
+		PastPhases   []phaseState `json:"pastPhases"`
+	}{
+		Job:          w.cfg.Job,
+		StartedAt:    w.startedAt,
+		DetectedAt:   now,
+		CurrentPhase: w.phase,
+		PastPhases:   append([]phaseState{}, w.history...),
+	}
+	w.mu.Unlock()
+
+	stateBytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return dir, fmt.Errorf("could not serialize the phase state: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "phase.json"), stateBytes, 0o644); err != nil {
+		return dir, fmt.Errorf("could not write the phase state: %w", err)
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,107 @@
+package watchdog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWatchdog returns a watchdog reporting stalls on the returned channel
+// instead of terminating the process.
+func newTestWatchdog(t *testing.T, cfg Config) (*Watchdog, chan string) {
+	cfg.DiagnosticsDir = t.TempDir()
+	w := New(cfg)
+	stalls := make(chan string, 1)
+	w.onStall = func(bundle string) { stalls <- bundle }
+	t.Cleanup(w.Stop)
+	return w, stalls
+}
+
+func TestWatchdogDetectsStall(t *testing.T) {
+
+	w, stalls := newTestWatchdog(t, Config{StallTimeout: 50 * time.Millisecond, Job: "test-job"})
+	w.Start()
+
+	// As long as the heartbeats are regular, no stall should be reported
+	for i := 0; i < 10; i++ {
+		Heartbeat("round %v", i)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-stalls:
+		t.Fatal("unexpected stall")
+	default:
+	}
+
+	var bundle string
+	select {
+	case bundle = <-stalls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stall was not detected")
+	}
+
+	goroutines, err := os.ReadFile(filepath.Join(bundle, "goroutines.txt"))
+	require.NoError(t, err)
+	require.Contains(t, string(goroutines), "TestWatchdogDetectsStall")
+
+	stateBytes, err := os.ReadFile(filepath.Join(bundle, "phase.json"))
+	require.NoError(t, err)
+
+	var state struct {
+		Job          string
+		CurrentPhase phaseState
+		PastPhases   []phaseState
+	}
+	require.NoError(t, json.Unmarshal(stateBytes, &state))
+	require.Equal(t, "test-job", state.Job)
+	require.Equal(t, "round 9", state.CurrentPhase.Name)
+	require.Len(t, state.PastPhases, 10)
+}
+
+func TestWatchdogLongPhase(t *testing.T) {
+
+	w, stalls := newTestWatchdog(t, Config{StallTimeout: 20 * time.Millisecond, LongPhaseTimeout: time.Hour})
+	w.Start()
+
+	BeginLongPhase("gnark proving")
+	time.Sleep(100 * time.Millisecond)
+
+	select {
+	case <-stalls:
+		t.Fatal("unexpected stall during a long phase")
+	default:
+	}
+
+	// A regular heartbeat restores the stall timeout
+	Heartbeat("after gnark proving")
+
+	select {
+	case <-stalls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stall was not detected")
+	}
+}
+
+func TestWatchdogStop(t *testing.T) {
+
+	w, stalls := newTestWatchdog(t, Config{StallTimeout: 20 * time.Millisecond})
+	w.Start()
+	w.Stop()
+
+	require.Nil(t, current.Load())
+
+	// heartbeats are no-ops once the watchdog is uninstalled
+	Heartbeat("ignored")
+	time.Sleep(100 * time.Millisecond)
+
+	select {
+	case <-stalls:
+		t.Fatal("unexpected stall after stopping the watchdog")
+	default:
+	}
+}
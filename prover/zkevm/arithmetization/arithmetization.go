@@ -42,8 +42,8 @@ func NewArithmetization(builder *wizard.Builder, settings Settings) *Arithmetiza
 
 // Assign the arithmetization related columns. Namely, it will open the file
 // specified in the witness object, call corset and assign the prover runtime
-// columns.
-func (a *Arithmetization) Assign(run *wizard.ProverRuntime, traceFile string) {
+// columns. It returns the usage of the modules of the arithmetization.
+func (a *Arithmetization) Assign(run *wizard.ProverRuntime, traceFile string) []ModuleUsage {
 
 	traceF := files.MustRead(traceFile)
 	trace, errT := ReadLtTraces(traceF, a.Schema)
@@ -51,5 +51,5 @@ func (a *Arithmetization) Assign(run *wizard.ProverRuntime, traceFile string) {
 		fmt.Printf("error loading the trace fpath=%q err=%v", traceFile, errT.Error())
 	}
 
-	AssignFromLtTraces(run, a.Schema, trace, a.Settings.Limits)
+	return AssignFromLtTraces(run, a.Schema, trace, a.Settings.Limits)
 }
@@ -12,38 +12,35 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/sirupsen/logrus"
 )
 
-// ReadExpandedTraces parses the provided trace file, expands it and returns the
-// corset object holding the expanded traces.
-func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces trace.Trace, limits *config.TracesLimits) {
+// AssignFromLtTraces assigns the columns of the arithmetization from the
+// expanded traces and returns the usage of the modules, including the modexp
+// calls counted by [ModexpUsage]. The process exits with
+// [TraceOverflowExitCode] if a module overflows its limit and panics if the
+// traces invoke a precompile that is disabled in the limits.
+func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces trace.Trace, limits *config.TracesLimits) []ModuleUsage {
 
 	// This loops checks the module assignment to see if we have created a 77
 	// error.
 	var (
-		modules      = expTraces.Modules().Collect()
-		moduleLimits = mapModuleLimits(limits)
-		err77        error
-		numCols      = expTraces.Width()
+		usage   = append(Usage(expTraces, limits), ModexpUsage(schema, expTraces, limits)...)
+		err77   error
+		numCols = expTraces.Width()
 	)
 
-	for _, module := range modules {
+	for _, u := range usage {
 
-		var (
-			name   = module.Name()
-			limit  = moduleLimits[module.Name()]
-			height = module.Height()
-			ratio  = float64(height) / float64(limit)
-			level  = logrus.InfoLevel
-		)
+		level := logrus.InfoLevel
 
-		if uint(limit) < height {
+		if u.Overflows() {
 			level = logrus.ErrorLevel
-			err77 = errors.Join(err77, fmt.Errorf("limit overflow: module '%s' overflows its limit height=%v limit=%v ratio=%v", name, height, limit, ratio))
+			err77 = errors.Join(err77, fmt.Errorf("limit overflow: module '%s' overflows its limit height=%v limit=%v ratio=%v", u.Module, u.Rows, u.Limit, u.Ratio()))
 		}
 
-		logrus.StandardLogger().Logf(level, "module utilization module=%v height=%v limit=%v ratio=%v", name, height, limit, ratio)
+		logrus.StandardLogger().Logf(level, "module utilization module=%v height=%v limit=%v ratio=%v", u.Module, u.Rows, u.Limit, u.Ratio())
 	}
 
 	if err77 != nil {
@@ -51,6 +48,10 @@ func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces
 		os.Exit(TraceOverflowExitCode)
 	}
 
+	if err := CheckDisabledPrecompiles(schema, expTraces, limits); err != nil {
+		utils.Panic("rejecting the traces: %v", err)
+	}
+
 	for id := uint(0); id < numCols; id++ {
 
 		var (
@@ -72,4 +73,6 @@ func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces
 
 		run.AssignColumn(ifaces.ColID(name), smartvectors.LeftPadded(plain, padding, wCol.Size()))
 	}
+
+	return usage
 }
@@ -42,6 +42,7 @@ func Define(comp *wizard.CompiledIOP, schema *air.Schema, limits *config.TracesL
 
 	scanner.scanColumns()
 	scanner.scanConstraints()
+	defineDisabledPrecompiles(comp, limits)
 }
 
 // scanColumns scans the column declaration of the corset [air.Schema] into the
@@ -278,6 +279,12 @@ func mapModuleLimits(limit *config.TracesLimits) map[string]int {
 
 	for i := 0; i < numField; i++ {
 
+		// Skips the fields that are not module limits, e.g. the list of
+		// disabled precompiles.
+		if limitType.Field(i).Type.Kind() != reflect.Int {
+			continue
+		}
+
 		var (
 			corsetTag = limitType.Field(i).Tag.Get("corset")
 			limit     = limitVal.Field(i).Interface().(int)
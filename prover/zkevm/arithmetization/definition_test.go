@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/consensys/go-corset/pkg/air"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/coin"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
@@ -15,6 +16,48 @@ import (
 
 func TestDefine(t *testing.T) {
 
+	comp, schema, limits := defineInputs(t)
+	Define(comp, schema, limits)
+}
+
+func TestDefineDisabledPrecompiles(t *testing.T) {
+
+	comp, schema, limits := defineInputs(t)
+	limits.DisabledPrecompiles = []string{
+		config.PrecompileModexp,
+		config.PrecompileEcmul,
+		config.PrecompileEcpair,
+		config.PrecompileSha2,
+	}
+
+	Define(comp, schema, limits)
+
+	for _, name := range limits.DisabledPrecompiles {
+		require.NotEmpty(t, precompileSelectors[name], "no selector for %v", name)
+		for _, col := range precompileSelectors[name] {
+			q := ifaces.QueryIDf("DISABLED_PRECOMPILE_%v_%v", name, col)
+			require.True(t, comp.QueriesNoParams.Exists(q), "missing query %v", q)
+		}
+	}
+}
+
+func TestDefineZeroLimitPrecompile(t *testing.T) {
+
+	comp, schema, limits := defineInputs(t)
+	limits.PrecompileSha2Blocks = 0
+
+	Define(comp, schema, limits)
+
+	for _, col := range precompileSelectors[config.PrecompileSha2] {
+		q := ifaces.QueryIDf("DISABLED_PRECOMPILE_%v_%v", config.PrecompileSha2, col)
+		require.True(t, comp.QueriesNoParams.Exists(q), "missing query %v", q)
+	}
+}
+
+// defineInputs returns an empty compiled IOP, the schema of the zkEVM and
+// limits setting every module to 1 << 10 rows.
+func defineInputs(t *testing.T) (*wizard.CompiledIOP, *air.Schema, *config.TracesLimits) {
+
 	var (
 		comp = &wizard.CompiledIOP{
 			Columns:         column.NewStore(),
@@ -29,9 +72,11 @@ func TestDefine(t *testing.T) {
 	)
 
 	for i := 0; i < limitRefl.NumField(); i++ {
-		limitRefl.Field(i).SetInt(1 << 10)
+		if limitRefl.Field(i).Kind() == reflect.Int {
+			limitRefl.Field(i).SetInt(1 << 10)
+		}
 	}
 
 	require.NoError(t, errBin)
-	Define(comp, schema, limits)
+	return comp, schema, limits
 }
@@ -0,0 +1,75 @@
+package arithmetization
+
+import (
+	"slices"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+)
+
+// The names under which the modexp calls are reported in the usage of the
+// modules. They are named after the limits bounding them.
+const (
+	ModexpSmallCallsUsage = "PRECOMPILE_MODEXP_EFFECTIVE_CALLS"
+	ModexpLargeCallsUsage = "PRECOMPILE_MODEXP_LARGE_EFFECTIVE_CALLS"
+)
+
+// ModexpUsage counts the modexp calls of the expanded traces whose operands
+// fit on 256 bits and the larger ones, which are proven by distinct circuits,
+// and reports them against their limits. The counts are not visible in the
+// height of the BLK_MDXP module and the prover would otherwise fail on the
+// traces exceeding the capacity of the circuits. Nothing is reported if the
+// modexp precompile is disabled.
+func ModexpUsage(schema *air.Schema, expTraces trace.Trace, limits *config.TracesLimits) []ModuleUsage {
+
+	if !limits.PrecompileEnabled(config.PrecompileModexp) {
+		return nil
+	}
+
+	var (
+		selectors = precompileSelectors[config.PrecompileModexp]
+		limbsID   = ifaces.ColID("blake2fmodexpdata.LIMB")
+		isModexp  []field.Element
+		limbs     []field.Element
+	)
+
+	for id := uint(0); id < expTraces.Width(); id++ {
+
+		var (
+			col  = expTraces.Column(id)
+			name = ifaces.ColID(wizardName(getModuleName(schema, col), col.Name()))
+			data = col.Data()
+		)
+
+		switch {
+		case name == limbsID:
+			limbs = make([]field.Element, data.Len())
+			for i := range limbs {
+				limbs[i] = data.Get(uint(i))
+			}
+		case slices.Contains(selectors, name):
+			if isModexp == nil {
+				isModexp = make([]field.Element, data.Len())
+			}
+			for i := range isModexp {
+				x := data.Get(uint(i))
+				isModexp[i].Add(&isModexp[i], &x)
+			}
+		}
+	}
+
+	if isModexp == nil {
+		isModexp = make([]field.Element, len(limbs))
+	}
+
+	nbSmall, nbLarge := modexp.CountInstances(isModexp, limbs)
+
+	return []ModuleUsage{
+		{Module: ModexpSmallCallsUsage, Rows: nbSmall, Limit: limits.PrecompileModexpEffectiveCalls},
+		{Module: ModexpLargeCallsUsage, Rows: nbLarge, Limit: limits.ModexpLargeCalls()},
+	}
+}
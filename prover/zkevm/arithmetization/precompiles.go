@@ -0,0 +1,96 @@
+package arithmetization
+
+import (
+	"fmt"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+// precompileSelectors lists, for every precompile that can be disabled, the
+// columns of the arithmetization flagging the rows of its calls. A precompile
+// is invoked in the traces if and only if one of them is non-zero.
+var precompileSelectors = map[string][]ifaces.ColID{
+	config.PrecompileModexp: {
+		"blake2fmodexpdata.IS_MODEXP_BASE",
+		"blake2fmodexpdata.IS_MODEXP_EXPONENT",
+		"blake2fmodexpdata.IS_MODEXP_MODULUS",
+		"blake2fmodexpdata.IS_MODEXP_RESULT",
+	},
+	config.PrecompileEcmul: {
+		"ecdata.IS_ECMUL_DATA",
+		"ecdata.IS_ECMUL_RESULT",
+	},
+	config.PrecompileEcpair: {
+		"ecdata.IS_ECPAIRING_DATA",
+		"ecdata.IS_ECPAIRING_RESULT",
+	},
+	config.PrecompileSha2: {
+		"shakiradata.IS_SHA2_DATA",
+		"shakiradata.SELECTOR_SHA2_RES_HI",
+	},
+}
+
+// disabledSelectors maps the selector columns of the disabled precompiles to
+// the name of their precompile.
+func disabledSelectors(limits *config.TracesLimits) map[ifaces.ColID]string {
+	res := map[ifaces.ColID]string{}
+	for _, name := range limits.EffectiveDisabledPrecompiles() {
+		for _, col := range precompileSelectors[name] {
+			res[col] = name
+		}
+	}
+	return res
+}
+
+// defineDisabledPrecompiles constrains the selector columns of the disabled
+// precompiles to be zero. Without it, the calls to a precompile whose module
+// is not in the zkEVM would go unproven.
+func defineDisabledPrecompiles(comp *wizard.CompiledIOP, limits *config.TracesLimits) {
+	for _, name := range limits.EffectiveDisabledPrecompiles() {
+		for _, colID := range precompileSelectors[name] {
+			comp.InsertGlobal(
+				0,
+				ifaces.QueryIDf("DISABLED_PRECOMPILE_%v_%v", name, colID),
+				ifaces.ColumnAsVariable(comp.Columns.GetHandle(colID)),
+			)
+		}
+	}
+}
+
+// CheckDisabledPrecompiles returns an error if the expanded traces invoke one
+// of the precompiles disabled in the limits. It lets the prover reject the
+// traces upfront instead of failing on the constraints of
+// [defineDisabledPrecompiles].
+func CheckDisabledPrecompiles(schema *air.Schema, expTraces trace.Trace, limits *config.TracesLimits) error {
+
+	selectors := disabledSelectors(limits)
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	for id := uint(0); id < expTraces.Width(); id++ {
+
+		var (
+			col          = expTraces.Column(id)
+			name         = ifaces.ColID(wizardName(getModuleName(schema, col), col.Name()))
+			precomp, off = selectors[name]
+		)
+
+		if !off {
+			continue
+		}
+
+		data := col.Data()
+		for i := uint(0); i < data.Len(); i++ {
+			if x := data.Get(i); !x.IsZero() {
+				return fmt.Errorf("the traces invoke the precompile %v which is disabled in the prover config, %v is non-zero at row %v", precomp, name, i)
+			}
+		}
+	}
+
+	return nil
+}
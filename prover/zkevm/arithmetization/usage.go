@@ -0,0 +1,49 @@
+package arithmetization
+
+import (
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/config"
+)
+
+// ModuleUsage reports the number of rows used by a module of the
+// arithmetization against the limit configured for the module. The usage of
+// all the modules is attached to the execution proofs so that the headroom on
+// the limits can be monitored and the limits tuned.
+type ModuleUsage struct {
+	Module string `json:"module"`
+	Rows   int    `json:"rows"`
+	Limit  int    `json:"limit"`
+}
+
+// Ratio returns the fraction of the limit used by the module
+func (u ModuleUsage) Ratio() float64 {
+	return float64(u.Rows) / float64(u.Limit)
+}
+
+// Overflows returns true if the module uses more rows than its limit
+func (u ModuleUsage) Overflows() bool {
+	return u.Rows > u.Limit
+}
+
+// Usage returns the usage of every module of the expanded traces, in the order
+// in which the modules appear in the traces. The modules that have no limit
+// in the configuration are reported with a limit of zero.
+func Usage(expTraces trace.Trace, limits *config.TracesLimits) []ModuleUsage {
+
+	var (
+		modules      = expTraces.Modules().Collect()
+		moduleLimits = mapModuleLimits(limits)
+		res          = make([]ModuleUsage, 0, len(modules))
+	)
+
+	for _, module := range modules {
+		res = append(res, ModuleUsage{
+			Module: module.Name(),
+			// #nosec G115 -- the height of a module fits in an int
+			Rows:  int(module.Height()),
+			Limit: moduleLimits[module.Name()],
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,59 @@
+package arithmetization
+
+import (
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+
+	sch, errBin := ReadZkevmBin()
+	require.NoError(t, errBin)
+
+	expTraces, errs := schema.NewTraceBuilder(sch).Build(nil)
+	require.NotNil(t, expTraces, "could not build the traces: %v", errs)
+
+	limits := &config.TracesLimits{Add: 1 << 10, Bin: 1 << 11}
+	usage := Usage(expTraces, limits)
+
+	require.Len(t, usage, len(expTraces.Modules().Collect()))
+
+	found := map[string]ModuleUsage{}
+	for _, u := range usage {
+		found[u.Module] = u
+	}
+
+	require.Equal(t, 1<<10, found["add"].Limit)
+	require.Equal(t, 1<<11, found["bin"].Limit)
+}
+
+func TestModuleUsage(t *testing.T) {
+
+	u := ModuleUsage{Module: "add", Rows: 3, Limit: 4}
+	require.Equal(t, 0.75, u.Ratio())
+	require.False(t, u.Overflows())
+
+	u.Rows = 5
+	require.True(t, u.Overflows())
+}
+
+func TestModexpUsage(t *testing.T) {
+
+	sch, errBin := ReadZkevmBin()
+	require.NoError(t, errBin)
+
+	expTraces, errs := schema.NewTraceBuilder(sch).Build(nil)
+	require.NotNil(t, expTraces, "could not build the traces: %v", errs)
+
+	limits := &config.TracesLimits{PrecompileModexpEffectiveCalls: 4}
+	require.Equal(t, []ModuleUsage{
+		{Module: ModexpSmallCallsUsage, Rows: 0, Limit: 4},
+		{Module: ModexpLargeCallsUsage, Rows: 0, Limit: 1},
+	}, ModexpUsage(sch, expTraces, limits))
+
+	limits.DisabledPrecompiles = []string{config.PrecompileModexp}
+	require.Empty(t, ModexpUsage(sch, expTraces, limits))
+}
@@ -11,6 +11,7 @@ import (
 
 var (
 	checkerZkEvm     *ZkEvm
+	checkerZkEvmErr  error
 	onceCheckerZkEvm = sync.Once{}
 
 	checkerCompilationSuite = compilationSuite{
@@ -25,7 +26,7 @@ var (
 // that the provided prover inputs are correct. It typically is used to audit
 // the traces of the arithmetization. Currently, it does not include the keccaks
 // nor does it include the state-management checks.
-func CheckerZkEvm(tl *config.TracesLimits) *ZkEvm {
+func CheckerZkEvm(tl *config.TracesLimits) (*ZkEvm, error) {
 	onceCheckerZkEvm.Do(func() {
 		settings := Settings{
 			Arithmetization: arithmetization.Settings{
@@ -37,7 +38,7 @@ func CheckerZkEvm(tl *config.TracesLimits) *ZkEvm {
 				Version: "beta-v1",
 			},
 		}
-		checkerZkEvm = NewZkEVM(settings)
+		checkerZkEvm, checkerZkEvmErr = NewZkEVM(settings)
 	})
-	return checkerZkEvm
+	return checkerZkEvm, checkerZkEvmErr
 }
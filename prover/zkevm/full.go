@@ -1,6 +1,7 @@
 package zkevm
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/consensys/linea-monorepo/prover/config"
@@ -8,90 +9,118 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/cleanup"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/logdata"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/mimc"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/selfrecursion"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager/accumulator"
 )
 
 var (
 	fullZkEvm              *ZkEvm
+	fullZkEvmErr           error
 	fullZkEvmCheckOnly     *ZkEvm
+	fullZkEvmCheckOnlyErr  error
 	onceFullZkEvm          = sync.Once{}
 	onceFullZkEvmCheckOnly = sync.Once{}
 
-	// This is the SIS instance, that has been found to minimize the overhead of
-	// recursion. It is changed w.r.t to the estimated because the estimated one
-	// allows for 10 bits limbs instead of just 8. But since the current state
-	// of the self-recursion currently relies on the number of limbs to be a
-	// power of two, we go with this one although it overshoots our security
-	// level target.
-	sisInstance = ringsis.Params{LogTwoBound: 16, LogTwoDegree: 6}
+	dummyCompilationSuite = compilationSuite{
+		logdata.LogColumnStats("initial-wizard"),
+		dummy.CompileAtProverLvl,
+	}
 
-	dummyCompilationSuite = compilationSuite{dummy.CompileAtProverLvl}
+	// FullMetadata identifies the compilation suite of the full prover. Its
+	// version must be bumped whenever the suite or the layout of the zkEVM
+	// changes so that the proofs of the previous suite are not mistaken for
+	// the ones of the new one. "beta-v3" sizes the keccak module for the
+	// public keys hashed by the ecdsa module on top of the hashes of the
+	// arithmetization, see [github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa.EcdsaZkEvm.NumKeccakF].
+	FullMetadata = wizard.VersionMetadata{
+		Title:   "linea/evm-execution/full",
+		Version: "beta-v3",
+	}
+)
 
-	// This is the compilation suite in use for the full prover
-	fullCompilationSuite = compilationSuite{
+// fullCompilationSuite returns the compilation suite in use for the full
+// prover. The SIS instance comes from the config and defaults to
+// [ringsis.StdParams], that has been found to minimize the overhead of
+// recursion. It is changed w.r.t to the estimated because the estimated one
+// allows for 10 bits limbs instead of just 8. But since the current state of
+// the self-recursion currently relies on the number of limbs to be a power of
+// two, we go with this one although it overshoots our security level target.
+//
+// The number of columns opened by each Vortex instance is derived from
+// [crypto.TargetSecurityLevel] and from the blow-up factor, see
+// [vortex.DeriveNumOpenedColumns]. The derivation is logged at compilation.
+// It gives 256 columns for the instances with a blow-up factor of 2, as
+// before, but 128 columns (86 rounded up to a power of two) instead of 64 for
+// the instances with a blow-up factor of 8. The opening proofs of the last
+// two instances and the self-recursion and outer circuits verifying them are
+// thus about twice as large, which changes the verifying key of the outer
+// circuit: the setup had to be regenerated along with the "beta-v2" version.
+//
+// The clean-up steps free the columns selected by the clean-up settings, see
+// [WithCleanUp]. They only run on the prover side and do not change the
+// setup.
+func fullCompilationSuite(sis *ringsis.Params, cu *cleanUpSettings) compilationSuite {
+
+	cleanUp := cleanup.WithLevel(cu.level, cu.preserved...)
+
+	return compilationSuite{
 		// logdata.Log("initial-wizard"),
+		logdata.LogColumnStats("initial-wizard"),
 		mimc.CompileMiMC,
 		compiler.Arcane(1<<10, 1<<19, false),
 		vortex.Compile(
 			2,
-			vortex.ForceNumOpenedColumns(256),
-			vortex.WithSISParams(&sisInstance),
+			vortex.WithSISParams(sis),
 		),
 		// logdata.Log("post-vortex-1"),
 
 		// First round of self-recursion
 		selfrecursion.SelfRecurse,
 		// logdata.Log("post-selfrecursion-1"),
-		cleanup.CleanUp,
+		cleanUp,
 		mimc.CompileMiMC,
 		compiler.Arcane(1<<10, 1<<18, false),
 		vortex.Compile(
 			2,
-			vortex.ForceNumOpenedColumns(256),
-			vortex.WithSISParams(&sisInstance),
+			vortex.WithSISParams(sis),
 		),
 		// logdata.Log("post-vortex-2"),
 
 		// Second round of self-recursion
 		selfrecursion.SelfRecurse,
 		// logdata.Log("post-selfrecursion-2"),
-		cleanup.CleanUp,
+		cleanUp,
 		mimc.CompileMiMC,
 		compiler.Arcane(1<<10, 1<<16, false),
 		vortex.Compile(
 			8,
-			vortex.ForceNumOpenedColumns(64),
-			vortex.WithSISParams(&sisInstance),
+			vortex.WithSISParams(sis),
 		),
 
 		// Fourth round of self-recursion
 		// logdata.Log("post-vortex-3"),
 		selfrecursion.SelfRecurse,
 		// logdata.Log("post-selfrecursion-3"),
-		cleanup.CleanUp,
+		cleanUp,
 		mimc.CompileMiMC,
 		compiler.Arcane(1<<10, 1<<13, false),
 		vortex.Compile(
 			8,
-			vortex.ForceNumOpenedColumns(64),
 			vortex.ReplaceSisByMimc(),
 		),
 		// logdata.Log("post-vortex-4"),
 	}
-)
+}
 
 // FullZkEvm compiles the full prover zkEVM. It memoizes the results and
 // returns it for all the subsequent calls. That is, it should not be called
@@ -99,28 +128,76 @@ var (
 // instance compiled with the parameters it received the first time. This
 // behavior is motivated by the fact that the compilation process takes time
 // and we don't want to spend the compilation time twice, plus in practice we
-// won't need to call it with different configuration parameters.
-func FullZkEvm(tl *config.TracesLimits) *ZkEvm {
+// won't need to call it with different configuration parameters. The error,
+// returned if the traces limits are invalid, is memoized as well. The same
+// goes for the options.
+func FullZkEvm(tl *config.TracesLimits, sis ringsis.Params, opts ...FullOption) (*ZkEvm, error) {
 
 	onceFullZkEvm.Do(func() {
+		cu := &cleanUpSettings{level: cleanup.Aggressive}
+		for _, opt := range opts {
+			opt(cu)
+		}
 		// Initialize the Full zkEVM arithmetization
-		fullZkEvm = fullZKEVMWithSuite(tl, fullCompilationSuite)
+		fullZkEvm, fullZkEvmErr = fullZKEVMWithSuite(tl, fullCompilationSuite(&sis, cu))
 	})
 
-	return fullZkEvm
+	return fullZkEvm, fullZkEvmErr
+}
+
+// FullOption changes the compilation of the full zkEVM, see [FullZkEvm]
+type FullOption func(*cleanUpSettings)
+
+// cleanUpSettings collects the settings of the clean-up steps of the full
+// compilation suite.
+type cleanUpSettings struct {
+	level     cleanup.Level
+	preserved []ifaces.ColID
 }
 
-func FullZkEVMCheckOnly(tl *config.TracesLimits) *ZkEvm {
+// WithCleanUp sets the level of the clean-up steps of the full compilation
+// suite, [cleanup.Aggressive] by default, and lists columns that they never
+// free. See [cleanup.WithLevel].
+func WithCleanUp(level cleanup.Level, preserved ...ifaces.ColID) FullOption {
+	return func(cu *cleanUpSettings) {
+		cu.level = level
+		cu.preserved = preserved
+	}
+}
+
+// DebugOptions returns the options of [FullZkEvm] selected by the debug
+// section of the config: the clean-up level and the preservation of the
+// columns retained by the debug openings, so that they are still assigned
+// when the proof retains them.
+func DebugOptions(cfg *config.Config) ([]FullOption, error) {
+
+	level := cleanup.Aggressive
+	if cfg.Debug.CleanUpLevel != "" {
+		var err error
+		if level, err = cleanup.ParseLevel(cfg.Debug.CleanUpLevel); err != nil {
+			return nil, fmt.Errorf("debug.clean_up_level: %w", err)
+		}
+	}
+
+	preserved := make([]ifaces.ColID, len(cfg.Debug.DebugOpeningsColumns))
+	for i, col := range cfg.Debug.DebugOpeningsColumns {
+		preserved[i] = ifaces.ColID(col)
+	}
+
+	return []FullOption{WithCleanUp(level, preserved...)}, nil
+}
+
+func FullZkEVMCheckOnly(tl *config.TracesLimits) (*ZkEvm, error) {
 
 	onceFullZkEvmCheckOnly.Do(func() {
 		// Initialize the Full zkEVM arithmetization
-		fullZkEvmCheckOnly = fullZKEVMWithSuite(tl, dummyCompilationSuite)
+		fullZkEvmCheckOnly, fullZkEvmCheckOnlyErr = fullZKEVMWithSuite(tl, dummyCompilationSuite)
 	})
 
-	return fullZkEvmCheckOnly
+	return fullZkEvmCheckOnly, fullZkEvmCheckOnlyErr
 }
 
-func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite) *ZkEvm {
+func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite) (*ZkEvm, error) {
 
 	// @Alex: only set mandatory parameters here. aka, the one that are not
 	// actually feature-gated.
@@ -129,6 +206,9 @@ func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite) *ZkEvm
 		Arithmetization: arithmetization.Settings{
 			Limits: tl,
 		},
+		PublicInput: publicInput.Settings{
+			MaxBlockGasLimit: tl.BlockGasLimit,
+		},
 		Statemanager: statemanager.Settings{
 			AccSettings: accumulator.Settings{
 				MaxNumProofs:    tl.ShomeiMerkleProofs,
@@ -137,47 +217,33 @@ func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite) *ZkEvm
 			},
 			MiMCCodeHashSize: tl.Rom,
 		},
-		Metadata: wizard.VersionMetadata{
-			Title:   "linea/evm-execution/full",
-			Version: "beta-v1",
-		},
+		Metadata: FullMetadata,
 		Keccak: keccak.Settings{
 			MaxNumKeccakf: tl.BlockKeccak,
 		},
-		Ecdsa: ecdsa.Settings{
-			MaxNbEcRecover:     tl.PrecompileEcrecoverEffectiveCalls,
-			MaxNbTx:            tl.BlockTransactions,
-			NbInputInstance:    4,
-			NbCircuitInstances: utils.DivCeil(tl.PrecompileEcrecoverEffectiveCalls+tl.BlockTransactions, 4),
-		},
-		Modexp: modexp.Settings{
-			MaxNbInstance256:  tl.PrecompileModexpEffectiveCalls,
-			MaxNbInstance4096: 1,
-		},
-		Ecadd: ecarith.Limits{
-			// 14 was found the right number to have just under 2^19 constraints
-			// per circuit.
-			NbInputInstances:   utils.DivCeil(tl.PrecompileEcaddEffectiveCalls, 28),
-			NbCircuitInstances: 28,
-		},
-		Ecmul: ecarith.Limits{
-			NbCircuitInstances: utils.DivCeil(tl.PrecompileEcmulEffectiveCalls, 6),
-			NbInputInstances:   6,
-		},
-		Ecpair: ecpair.Limits{
-			NbMillerLoopInputInstances:   1,
-			NbMillerLoopCircuits:         tl.PrecompileEcpairingMillerLoops,
-			NbFinalExpInputInstances:     1,
-			NbFinalExpCircuits:           tl.PrecompileEcpairingEffectiveCalls,
-			NbG2MembershipInputInstances: 6,
-			NbG2MembershipCircuits:       utils.DivCeil(tl.PrecompileEcpairingG2MembershipCalls, 6),
-		},
-		Sha2: sha2.Settings{
-			MaxNumSha2F: tl.PrecompileSha2Blocks,
-		},
+		PrecompileLimits: tl,
 	}
 
 	// Initialize the Full zkEVM arithmetization
-	fullZkEvm = NewZkEVM(settings)
-	return fullZkEvm
+	return NewZkEVM(settings)
+}
+
+// limitsDivCeil computes the ceiled division of a trace limit by the number of
+// instances per circuit. It returns an explicit error if the operation is
+// invalid instead of silently returning a wrapped-around value.
+func limitsDivCeil(name string, a, b int) (int, error) {
+	res, err := utils.DivCeilChecked(a, b)
+	if err != nil {
+		return 0, fmt.Errorf("could not compute the number of %v from the traces limits: %w", name, err)
+	}
+	return res, nil
+}
+
+// limitsAdd sums two trace limits and returns an error if the sum overflows.
+func limitsAdd(name string, a, b int) (int, error) {
+	res, err := utils.AddChecked(a, b)
+	if err != nil {
+		return 0, fmt.Errorf("could not compute the number of %v from the traces limits: %w", name, err)
+	}
+	return res, nil
 }
@@ -0,0 +1,39 @@
+package zkevm
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/cleanup"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugOptions(t *testing.T) {
+
+	apply := func(cfg *config.Config) *cleanUpSettings {
+		opts, err := DebugOptions(cfg)
+		require.NoError(t, err)
+		cu := &cleanUpSettings{}
+		for _, opt := range opts {
+			opt(cu)
+		}
+		return cu
+	}
+
+	cfg := &config.Config{}
+	cu := apply(cfg)
+	assert.Equal(t, cleanup.Aggressive, cu.level)
+	assert.Empty(t, cu.preserved)
+
+	cfg.Debug.CleanUpLevel = "safe"
+	cfg.Debug.DebugOpeningsColumns = []string{"A", "B"}
+	cu = apply(cfg)
+	assert.Equal(t, cleanup.Safe, cu.level)
+	assert.Equal(t, []ifaces.ColID{"A", "B"}, cu.preserved)
+
+	cfg.Debug.CleanUpLevel = "everything"
+	_, err := DebugOptions(cfg)
+	assert.Error(t, err)
+}
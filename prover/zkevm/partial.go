@@ -18,6 +18,7 @@ const (
 
 var (
 	partialZkEvm     *ZkEvm
+	partialZkEvmErr  error
 	oncePartialZkEvm = sync.Once{}
 
 	partialCompilationSuite = compilationSuite{
@@ -34,7 +35,7 @@ var (
 // ignore the configuration options and directly return the previously compiled
 // object. It therefore means that it should not be called twice with different
 // config options.
-func PartialZkEvm(tl *config.TracesLimits) *ZkEvm {
+func PartialZkEvm(tl *config.TracesLimits) (*ZkEvm, error) {
 
 	// This is hidden behind a once, because the compilation time can take a
 	// significant amount of time and we want it to be only triggered when we
@@ -55,8 +56,8 @@ func PartialZkEvm(tl *config.TracesLimits) *ZkEvm {
 				Version: "beta-v1",
 			},
 		}
-		partialZkEvm = NewZkEVM(settings)
+		partialZkEvm, partialZkEvmErr = NewZkEVM(settings)
 	})
 
-	return partialZkEvm
+	return partialZkEvm, partialZkEvmErr
 }
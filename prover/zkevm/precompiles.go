@@ -0,0 +1,289 @@
+package zkevm
+
+import (
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ec_bls"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+)
+
+// PrecompileModule is a module of the zkEVM proving the calls to one or
+// several precompiles. Adding a precompile to the zkEVM amounts to
+// implementing this interface and registering the module with
+// [RegisterPrecompile].
+type PrecompileModule interface {
+	// Name identifies the module
+	Name() string
+	// Enabled returns false if the module is not to be defined for the
+	// limits, typically because its precompile is disabled
+	// explicitly or by a zero limit.
+	Enabled(limits *config.TracesLimits) bool
+	// SetLimits derives the settings of the module from the traces limits.
+	// It is always called before Define, with zero limits if
+	// [Settings.PrecompileLimits] is not set. It returns an error if the
+	// limits do not yield valid settings.
+	SetLimits(limits *config.TracesLimits) error
+	// Define declares the columns and the constraints of the module
+	Define(comp *wizard.CompiledIOP)
+	// Assign assigns the module. It is called once the arithmetization is
+	// assigned.
+	Assign(run *wizard.ProverRuntime, witness *Witness)
+}
+
+// keccakProvider is implemented by the precompile modules whose hashes are
+// proven by the keccak module. They are defined and assigned before the
+// keccak module.
+type keccakProvider interface {
+	PrecompileModule
+	keccak.Provider
+}
+
+// precompileRegistry lists the constructors of the precompile modules in
+// their order of definition. The order shapes the compiled IOP, so the
+// modules are appended at the end.
+var precompileRegistry []func() PrecompileModule
+
+// RegisterPrecompile registers a precompile module in the zkEVM. The modules
+// are defined and assigned in the order of registration. Every zkEVM
+// instantiates its own modules with newModule.
+func RegisterPrecompile(newModule func() PrecompileModule) {
+	precompileRegistry = append(precompileRegistry, newModule)
+}
+
+func init() {
+	// The order is the one in which the modules have always been defined
+	RegisterPrecompile(func() PrecompileModule { return &ecdsaPrecompile{} })
+	RegisterPrecompile(func() PrecompileModule { return &modexpPrecompile{} })
+	// There is no ecadd module, its proof is deactivated pending the
+	// resolution of: https://github.com/Consensys/linea-tracer/issues/954
+	RegisterPrecompile(func() PrecompileModule { return &ecmulPrecompile{} })
+	RegisterPrecompile(func() PrecompileModule { return &ecpairPrecompile{} })
+	RegisterPrecompile(func() PrecompileModule { return &ecBlsPrecompile{} })
+	RegisterPrecompile(func() PrecompileModule { return &sha2Precompile{} })
+}
+
+// ecdsaPrecompile verifies the ecrecover calls and the signatures of the
+// transactions
+type ecdsaPrecompile struct {
+	settings ecdsa.Settings
+	module   *ecdsa.EcdsaZkEvm
+}
+
+func (p *ecdsaPrecompile) Name() string { return "ecdsa" }
+
+func (p *ecdsaPrecompile) Enabled(*config.TracesLimits) bool { return true }
+
+func (p *ecdsaPrecompile) SetLimits(tl *config.TracesLimits) error {
+	nbInputs, err := limitsAdd("ecdsa inputs", tl.PrecompileEcrecoverEffectiveCalls, tl.BlockTransactions)
+	if err != nil {
+		return err
+	}
+	nbCircuits, err := limitsDivCeil("ecdsa circuits", nbInputs, 4)
+	if err != nil {
+		return err
+	}
+	p.settings = ecdsa.Settings{
+		MaxNbEcRecover:     tl.PrecompileEcrecoverEffectiveCalls,
+		MaxNbTx:            tl.BlockTransactions,
+		NbInputInstance:    4,
+		NbCircuitInstances: nbCircuits,
+	}
+	return nil
+}
+
+func (p *ecdsaPrecompile) Define(comp *wizard.CompiledIOP) {
+	p.module = ecdsa.NewEcdsaZkEvm(comp, &p.settings)
+}
+
+func (p *ecdsaPrecompile) Assign(run *wizard.ProverRuntime, witness *Witness) {
+	p.module.Assign(run, witness.TxSignatureGetter, len(witness.TxSignatures))
+}
+
+func (p *ecdsaPrecompile) KeccakProviders() []generic.GenericByteModule {
+	return p.module.KeccakProviders()
+}
+
+func (p *ecdsaPrecompile) NumKeccakF() int {
+	return p.module.NumKeccakF()
+}
+
+// modexpPrecompile proves the calls to the modexp precompile
+type modexpPrecompile struct {
+	settings modexp.Settings
+	module   *modexp.Module
+}
+
+func (p *modexpPrecompile) Name() string { return "modexp" }
+
+func (p *modexpPrecompile) Enabled(tl *config.TracesLimits) bool {
+	return tl.PrecompileEnabled(config.PrecompileModexp)
+}
+
+func (p *modexpPrecompile) SetLimits(tl *config.TracesLimits) error {
+	p.settings = modexp.Settings{
+		MaxNbInstance256:  tl.PrecompileModexpEffectiveCalls,
+		MaxNbInstance4096: tl.ModexpLargeCalls(),
+	}
+	return nil
+}
+
+func (p *modexpPrecompile) Define(comp *wizard.CompiledIOP) {
+	p.module = modexp.NewModuleZkEvm(comp, p.settings)
+}
+
+func (p *modexpPrecompile) Assign(run *wizard.ProverRuntime, _ *Witness) {
+	p.module.Assign(run)
+}
+
+// ecmulPrecompile proves the calls to the ecmul precompile
+type ecmulPrecompile struct {
+	limits ecarith.Limits
+	module *ecarith.EcMul
+}
+
+func (p *ecmulPrecompile) Name() string { return "ecmul" }
+
+func (p *ecmulPrecompile) Enabled(tl *config.TracesLimits) bool {
+	return tl.PrecompileEnabled(config.PrecompileEcmul)
+}
+
+func (p *ecmulPrecompile) SetLimits(tl *config.TracesLimits) error {
+	nbCircuits, err := limitsDivCeil("ecmul circuits", tl.PrecompileEcmulEffectiveCalls, 6)
+	if err != nil {
+		return err
+	}
+	p.limits = ecarith.Limits{
+		NbCircuitInstances: nbCircuits,
+		NbInputInstances:   6,
+	}
+	return nil
+}
+
+func (p *ecmulPrecompile) Define(comp *wizard.CompiledIOP) {
+	p.module = ecarith.NewEcMulZkEvm(comp, &p.limits)
+}
+
+func (p *ecmulPrecompile) Assign(run *wizard.ProverRuntime, _ *Witness) {
+	p.module.Assign(run)
+}
+
+// ecpairPrecompile proves the calls to the ecpairing precompile
+type ecpairPrecompile struct {
+	limits ecpair.Limits
+	module *ecpair.ECPair
+}
+
+func (p *ecpairPrecompile) Name() string { return "ecpair" }
+
+func (p *ecpairPrecompile) Enabled(tl *config.TracesLimits) bool {
+	return tl.PrecompileEnabled(config.PrecompileEcpair)
+}
+
+func (p *ecpairPrecompile) SetLimits(tl *config.TracesLimits) error {
+	nbG2MembershipCircuits, err := limitsDivCeil("ecpair g2 membership circuits", tl.PrecompileEcpairingG2MembershipCalls, 6)
+	if err != nil {
+		return err
+	}
+	nbCurveCheckCircuits, err := limitsDivCeil("ecpair curve check circuits", tl.PrecompileEcpairingMalformedPairs, 6)
+	if err != nil {
+		return err
+	}
+	p.limits = ecpair.Limits{
+		NbMillerLoopInputInstances:   1,
+		NbMillerLoopCircuits:         tl.PrecompileEcpairingMillerLoops,
+		NbFinalExpInputInstances:     1,
+		NbFinalExpCircuits:           tl.PrecompileEcpairingEffectiveCalls,
+		NbG2MembershipInputInstances: 6,
+		NbG2MembershipCircuits:       nbG2MembershipCircuits,
+		NbCurveCheckInputInstances:   6,
+		NbCurveCheckCircuits:         nbCurveCheckCircuits,
+	}
+	return nil
+}
+
+func (p *ecpairPrecompile) Define(comp *wizard.CompiledIOP) {
+	p.module = ecpair.NewECPairZkEvm(comp, &p.limits)
+}
+
+func (p *ecpairPrecompile) Assign(run *wizard.ProverRuntime, _ *Witness) {
+	p.module.Assign(run)
+}
+
+// ecBlsPrecompile proves the calls to the BLS12-381 precompiles of EIP-2537.
+// The module is always defined and disables itself unless the limits of the
+// BLS precompiles are set.
+type ecBlsPrecompile struct {
+	settings ec_bls.Settings
+	module   *ec_bls.EcBls
+}
+
+func (p *ecBlsPrecompile) Name() string { return "ec_bls" }
+
+func (p *ecBlsPrecompile) Enabled(*config.TracesLimits) bool { return true }
+
+func (p *ecBlsPrecompile) SetLimits(tl *config.TracesLimits) error {
+	nbG1AddCircuits, err := limitsDivCeil("bls g1 add circuits", tl.PrecompileBlsG1AddEffectiveCalls, 32)
+	if err != nil {
+		return err
+	}
+	p.settings = ec_bls.Settings{
+		Enabled: tl.Eip2537Enabled(),
+		// An addition takes ~14K constraints, a multiplication ~550K and
+		// a pairing check ~6M constraints. To be revisited once the
+		// precompiles are activated.
+		G1Add: ec_bls.Limits{
+			NbInputInstances:   32,
+			NbCircuitInstances: nbG1AddCircuits,
+		},
+		G1Mul: ec_bls.Limits{
+			NbInputInstances:   1,
+			NbCircuitInstances: tl.PrecompileBlsG1MulEffectiveCalls,
+		},
+		PairingCheck: ec_bls.Limits{
+			NbInputInstances:   1,
+			NbCircuitInstances: tl.PrecompileBlsPairingCheckCalls,
+		},
+	}
+	return nil
+}
+
+func (p *ecBlsPrecompile) Define(comp *wizard.CompiledIOP) {
+	p.module = ec_bls.NewEcBlsZkEvm(comp, &p.settings)
+}
+
+func (p *ecBlsPrecompile) Assign(run *wizard.ProverRuntime, _ *Witness) {
+	p.module.Assign(run)
+}
+
+// sha2Precompile proves the calls to the sha2 precompile
+type sha2Precompile struct {
+	settings sha2.Settings
+	module   *sha2.Sha2SingleProvider
+}
+
+func (p *sha2Precompile) Name() string { return "sha2" }
+
+func (p *sha2Precompile) Enabled(tl *config.TracesLimits) bool {
+	return tl.PrecompileEnabled(config.PrecompileSha2)
+}
+
+func (p *sha2Precompile) SetLimits(tl *config.TracesLimits) error {
+	p.settings = sha2.Settings{
+		MaxNumSha2F: tl.PrecompileSha2Blocks,
+	}
+	return nil
+}
+
+func (p *sha2Precompile) Define(comp *wizard.CompiledIOP) {
+	p.module = sha2.NewSha2ZkEvm(comp, p.settings)
+}
+
+func (p *sha2Precompile) Assign(run *wizard.ProverRuntime, _ *Witness) {
+	p.module.Run(run)
+}
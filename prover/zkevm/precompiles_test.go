@@ -0,0 +1,45 @@
+package zkevm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrecompilesInvalidLimits(t *testing.T) {
+
+	tl := &config.TracesLimits{
+		PrecompileEcrecoverEffectiveCalls: math.MaxInt,
+		BlockTransactions:                 1,
+	}
+
+	_, err := newPrecompiles(&Settings{
+		Arithmetization:  arithmetization.Settings{Limits: tl},
+		PrecompileLimits: tl,
+	})
+
+	require.ErrorIs(t, err, utils.ErrOverflow)
+	require.ErrorContains(t, err, "ecdsa")
+}
+
+func TestNewPrecompilesNoLimits(t *testing.T) {
+
+	precompiles, err := newPrecompiles(&Settings{
+		Arithmetization: arithmetization.Settings{Limits: &config.TracesLimits{}},
+	})
+	require.NoError(t, err)
+
+	// The settings are derived from zero limits rather than left to zero
+	for _, m := range precompiles {
+		if p, ok := m.(*ecdsaPrecompile); ok {
+			require.Equal(t, 4, p.settings.NbInputInstance)
+			require.Equal(t, 0, p.settings.NbCircuitInstances)
+			return
+		}
+	}
+	t.Fatal("the ecdsa module is not instantiated")
+}
@@ -59,33 +59,38 @@ func HashOf(comp *wizard.CompiledIOP, inputCols []ifaces.Column) (ifaces.Column,
 	return ctx.IntermediateHashes[len(inputCols)-1], ctx
 }
 
-// Run implements the [wizard.ProverAction] interface
+// Run implements the [wizard.ProverAction] interface. The rows are hashed by
+// chunks in parallel and the intermediate hashes are assembled with a
+// [smartvectors.Builder]: the hashes of the padding rows of the inputs are
+// all equal, so they are assigned as padded vectors.
 func (ctx *hashingCtx) Run(run *wizard.ProverRuntime) {
 
 	var (
 		numRow = ctx.InputCols[0].Size()
 		numCol = len(ctx.InputCols)
 		inputs = make([][]field.Element, numCol)
-		interm = make([][]field.Element, numCol)
+		interm = make([]*smartvectors.Builder, numCol)
 	)
 
 	for i := range interm {
 		inputs[i] = ctx.InputCols[i].GetColAssignment(run).IntoRegVecSaveAlloc()
-		interm[i] = make([]field.Element, numRow)
+		interm[i] = smartvectors.NewBuilder(numRow)
 	}
 
 	parallel.Execute(numRow, func(start, stop int) {
 		prevState := make([]field.Element, stop-start)
 		for i := range interm {
-			mimcVecCompression(prevState, inputs[i][start:stop], interm[i][start:stop])
-			prevState = interm[i][start:stop]
+			newState := make([]field.Element, stop-start)
+			mimcVecCompression(prevState, inputs[i][start:stop], newState)
+			interm[i].Write(start, newState)
+			prevState = newState
 		}
 	})
 
 	for i := range interm {
 		run.AssignColumn(
 			ctx.IntermediateHashes[i].GetColID(),
-			smartvectors.NewRegular(interm[i]),
+			interm[i].Build(),
 		)
 	}
 }
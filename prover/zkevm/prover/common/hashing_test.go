@@ -9,6 +9,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHashing(t *testing.T) {
@@ -19,13 +20,21 @@ func TestHashing(t *testing.T) {
 			smartvectors.NewConstant(field.One(), 4),
 			smartvectors.NewConstant(field.NewElement(2), 4),
 		},
+		{
+			smartvectors.ForTest(1, 2, 0, 0),
+			smartvectors.ForTest(3, 4, 0, 0),
+			smartvectors.ForTest(5, 6, 0, 0),
+		},
 	}
 
 	for i, tc := range testCase {
 
 		t.Run(fmt.Sprintf("testcase-%v", i), func(t *testing.T) {
 
-			var pa wizard.ProverAction
+			var (
+				pa   wizard.ProverAction
+				hash ifaces.Column
+			)
 
 			define := func(b *wizard.Builder) {
 				cols := []ifaces.Column{
@@ -34,7 +43,7 @@ func TestHashing(t *testing.T) {
 					b.RegisterCommit("A3", 4),
 				}
 
-				_, pa = HashOf(b.CompiledIOP, cols)
+				hash, pa = HashOf(b.CompiledIOP, cols)
 			}
 
 			prove := func(run *wizard.ProverRuntime) {
@@ -42,6 +51,9 @@ func TestHashing(t *testing.T) {
 				run.AssignColumn("A2", tc[1])
 				run.AssignColumn("A3", tc[2])
 				pa.Run(run)
+
+				// The hashes of the padding rows are not stored densely
+				require.NotEqual(t, "*smartvectors.Regular", fmt.Sprintf("%T", hash.GetColAssignment(run)))
 			}
 
 			comp := wizard.Compile(define, dummy.Compile)
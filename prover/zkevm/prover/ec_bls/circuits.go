@@ -0,0 +1,242 @@
+package ec_bls
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+const (
+	// nbLimbsFp is the number of 128 bits limbs used by the precompiles to
+	// encode an element of the base field. The first one is a zero padding.
+	nbLimbsFp     = 4
+	nbLimbsG1     = 2 * nbLimbsFp
+	nbLimbsG2     = 4 * nbLimbsFp
+	nbLimbsScalar = 2
+
+	// nbPairsPerPairingCheck is the number of pairs of a pairing check call
+	// supported by the circuit.
+	nbPairsPerPairingCheck = 2
+
+	nbRowsPerG1Add        = 3 * nbLimbsG1
+	nbRowsPerG1Mul        = 2*nbLimbsG1 + nbLimbsScalar
+	nbRowsPerPairingCheck = nbPairsPerPairingCheck * (nbLimbsG1 + nbLimbsG2)
+)
+
+// MultiG1AddCircuit is a circuit that can handle multiple BLS12_G1ADD
+// instes. The length of the slice Instances should corresponds to the one
+// defined in the Limits struct.
+type MultiG1AddCircuit struct {
+	Instances []G1AddInstance
+}
+
+type G1AddInstance struct {
+	// The inputs and the result of the addition. The result is provided by
+	// the caller, we have to ensure that it is correct.
+	P, Q, R [nbLimbsG1]frontend.Variable `gnark:",public"`
+}
+
+// NewG1AddCircuit creates a new circuit for verifying the BLS12_G1ADD
+// precompile based on the defined number of inputs.
+func NewG1AddCircuit(limits *Limits) *MultiG1AddCircuit {
+	return &MultiG1AddCircuit{
+		Instances: make([]G1AddInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiG1AddCircuit) Define(api frontend.API) error {
+
+	f, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+
+	curve, err := sw_emulated.New[sw_bls12381.BaseField, sw_bls12381.ScalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return fmt.Errorf("curve: %w", err)
+	}
+
+	for i := range c.Instances {
+		var (
+			P = g1FromLimbs(api, f, c.Instances[i].P[:])
+			Q = g1FromLimbs(api, f, c.Instances[i].Q[:])
+			R = g1FromLimbs(api, f, c.Instances[i].R[:])
+		)
+		// The precompile does not require the inputs to be in the subgroup,
+		// only on the curve.
+		curve.AssertIsOnCurve(P)
+		curve.AssertIsOnCurve(Q)
+		curve.AssertIsEqual(R, curve.AddUnified(P, Q))
+	}
+
+	return nil
+}
+
+// MultiG1MulCircuit is a circuit that can handle multiple G1 multiplications.
+// The length of the slice Instances should corresponds to the one defined in
+// the Limits struct.
+type MultiG1MulCircuit struct {
+	Instances []G1MulInstance
+}
+
+type G1MulInstance struct {
+	// The point and the scalar to multiply it with. The scalar is not
+	// necessarily reduced.
+	P [nbLimbsG1]frontend.Variable     `gnark:",public"`
+	N [nbLimbsScalar]frontend.Variable `gnark:",public"`
+	// The result of the multiplication. Is provided by the caller, we have to
+	// ensure that the result is correct.
+	R [nbLimbsG1]frontend.Variable `gnark:",public"`
+}
+
+// NewG1MulCircuit creates a new circuit for verifying the G1 multiplications
+// based on the defined number of inputs.
+func NewG1MulCircuit(limits *Limits) *MultiG1MulCircuit {
+	return &MultiG1MulCircuit{
+		Instances: make([]G1MulInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiG1MulCircuit) Define(api frontend.API) error {
+
+	f, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+
+	s, err := emulated.NewField[sw_bls12381.ScalarField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+
+	curve, err := sw_emulated.New[sw_bls12381.BaseField, sw_bls12381.ScalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return fmt.Errorf("curve: %w", err)
+	}
+
+	for i := range c.Instances {
+
+		var (
+			P    = g1FromLimbs(api, f, c.Instances[i].P[:])
+			R    = g1FromLimbs(api, f, c.Instances[i].R[:])
+			inst = &c.Instances[i]
+			hi   = make([]frontend.Variable, 4)
+			lo   = make([]frontend.Variable, 4)
+		)
+
+		// The scalar spans 256 bits and may exceed the modulus of the scalar
+		// field, which has 255 bits. It cannot be packed directly into an
+		// emulated element as its most significant limb would overflow, so it
+		// is recomposed as hi * 2^128 + lo.
+		hi[0], hi[1] = bitslice.Partition(api, inst.N[0], 64, bitslice.WithNbDigits(128))
+		lo[0], lo[1] = bitslice.Partition(api, inst.N[1], 64, bitslice.WithNbDigits(128))
+		hi[2], hi[3], lo[2], lo[3] = 0, 0, 0, 0
+		N := s.Add(s.NewElement(lo), s.MulConst(s.NewElement(hi), new(big.Int).Lsh(big.NewInt(1), 128)))
+
+		curve.AssertIsOnCurve(P)
+		curve.AssertIsEqual(R, curve.ScalarMul(P, N, algopts.WithCompleteArithmetic()))
+	}
+
+	return nil
+}
+
+// MultiPairingCheckCircuit is a circuit that can handle multiple successful
+// BLS12_PAIRING_CHECK instes of [nbPairsPerPairingCheck] pairs. The length
+// of the slice Instances should corresponds to the one defined in the Limits
+// struct.
+type MultiPairingCheckCircuit struct {
+	Instances []PairingCheckInstance
+}
+
+type PairingCheckInstance struct {
+	Pairs [nbPairsPerPairingCheck]PairingCheckPair
+}
+
+type PairingCheckPair struct {
+	P [nbLimbsG1]frontend.Variable `gnark:",public"`
+	Q [nbLimbsG2]frontend.Variable `gnark:",public"`
+}
+
+// NewPairingCheckCircuit creates a new circuit for verifying the
+// BLS12_PAIRING_CHECK precompile based on the defined number of inputs.
+func NewPairingCheckCircuit(limits *Limits) *MultiPairingCheckCircuit {
+	return &MultiPairingCheckCircuit{
+		Instances: make([]PairingCheckInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiPairingCheckCircuit) Define(api frontend.API) error {
+
+	f, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("pairing: %w", err)
+	}
+
+	for i := range c.Instances {
+
+		var (
+			Ps = make([]*sw_bls12381.G1Affine, nbPairsPerPairingCheck)
+			Qs = make([]*sw_bls12381.G2Affine, nbPairsPerPairingCheck)
+		)
+
+		for j, pair := range c.Instances[i].Pairs {
+			Ps[j] = g1FromLimbs(api, f, pair.P[:])
+			Qs[j] = g2FromLimbs(api, f, pair.Q[:])
+			pairing.AssertIsOnG1(Ps[j])
+			pairing.AssertIsOnG2(Qs[j])
+		}
+
+		if err := pairing.PairingCheck(Ps, Qs); err != nil {
+			return fmt.Errorf("pairing check: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// fpFromLimbs converts the [nbLimbsFp] limbs of a base field element into an
+// emulated element. The limbs are already range checked to be in 128 bits
+// range by the arithmetization, the first one must be zero.
+func fpFromLimbs(api frontend.API, f *emulated.Field[sw_bls12381.BaseField], limbs []frontend.Variable) *emulated.Element[sw_bls12381.BaseField] {
+
+	api.AssertIsEqual(limbs[0], 0)
+
+	// gnark circuit works with 64 bits values, we need to split the 128 bits
+	// values into high and low parts. The limbs of the emulated element are
+	// ordered from the least significant.
+	res := make([]frontend.Variable, 6)
+	for i := 0; i < 3; i++ {
+		res[2*i], res[2*i+1] = bitslice.Partition(api, limbs[nbLimbsFp-1-i], 64, bitslice.WithNbDigits(128))
+	}
+
+	return f.NewElement(res)
+}
+
+// g1FromLimbs converts the [nbLimbsG1] limbs of a G1 point
+func g1FromLimbs(api frontend.API, f *emulated.Field[sw_bls12381.BaseField], limbs []frontend.Variable) *sw_bls12381.G1Affine {
+	return &sw_bls12381.G1Affine{
+		X: *fpFromLimbs(api, f, limbs[:nbLimbsFp]),
+		Y: *fpFromLimbs(api, f, limbs[nbLimbsFp:]),
+	}
+}
+
+// g2FromLimbs converts the [nbLimbsG2] limbs of a G2 point
+func g2FromLimbs(api frontend.API, f *emulated.Field[sw_bls12381.BaseField], limbs []frontend.Variable) *sw_bls12381.G2Affine {
+	var res sw_bls12381.G2Affine
+	res.P.X.A0 = *fpFromLimbs(api, f, limbs[0:nbLimbsFp])
+	res.P.X.A1 = *fpFromLimbs(api, f, limbs[nbLimbsFp:2*nbLimbsFp])
+	res.P.Y.A0 = *fpFromLimbs(api, f, limbs[2*nbLimbsFp:3*nbLimbsFp])
+	res.P.Y.A1 = *fpFromLimbs(api, f, limbs[3*nbLimbsFp:])
+	return &res
+}
@@ -0,0 +1,127 @@
+package ec_bls
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// randomG1 returns a random point of the G1 subgroup
+func randomG1(t *testing.T) bls12381.G1Affine {
+	var (
+		_, _, g1, _ = bls12381.Generators()
+		k, err      = rand.Int(rand.Reader, fr.Modulus())
+		res         bls12381.G1Affine
+	)
+	require.NoError(t, err)
+	res.ScalarMultiplication(&g1, k)
+	return res
+}
+
+// assignLimbs assigns the limbs to the circuit variables
+func assignLimbs(dst []frontend.Variable, limbs []field.Element) {
+	for i := range limbs {
+		dst[i] = limbs[i].BigInt(new(big.Int))
+	}
+}
+
+func TestG1AddCircuit(t *testing.T) {
+
+	var (
+		p, q, r, inf bls12381.G1Affine
+		limits       = &Limits{NbInputInstances: 3}
+		circuit      = NewG1AddCircuit(limits)
+		assignment   = NewG1AddCircuit(limits)
+		cases        = [][3]*bls12381.G1Affine{
+			{&p, &q, &r},
+			{&p, &inf, &p},
+			{&inf, &inf, &inf},
+		}
+	)
+
+	p, q = randomG1(t), randomG1(t)
+	r.Add(&p, &q)
+
+	for i, c := range cases {
+		for j, dst := range []*[nbLimbsG1]frontend.Variable{&assignment.Instances[i].P, &assignment.Instances[i].Q, &assignment.Instances[i].R} {
+			limbs := g1Limbs(c[j])
+			assignLimbs(dst[:], limbs[:])
+		}
+	}
+
+	assert.NoError(t, test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField()))
+
+	// An incorrect result is rejected
+	limbs := g1Limbs(&q)
+	assignLimbs(assignment.Instances[0].R[:], limbs[:])
+	assert.Error(t, test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField()))
+}
+
+func TestG1MulCircuit(t *testing.T) {
+
+	var (
+		p, r, inf  bls12381.G1Affine
+		limits     = &Limits{NbInputInstances: 2}
+		circuit    = NewG1MulCircuit(limits)
+		assignment = NewG1MulCircuit(limits)
+		// The scalars of the precompile are not reduced
+		n = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(12345))
+	)
+
+	p = randomG1(t)
+	r.ScalarMultiplication(&p, n)
+
+	for i, c := range []struct{ p, r *bls12381.G1Affine }{{&p, &r}, {&inf, &inf}} {
+		pl, rl, nl := g1Limbs(c.p), g1Limbs(c.r), scalarLimbs(n)
+		assignLimbs(assignment.Instances[i].P[:], pl[:])
+		assignLimbs(assignment.Instances[i].R[:], rl[:])
+		assignLimbs(assignment.Instances[i].N[:], nl[:])
+	}
+
+	assert.NoError(t, test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField()))
+
+	// An incorrect result is rejected
+	limbs := g1Limbs(&p)
+	assignLimbs(assignment.Instances[0].R[:], limbs[:])
+	assert.Error(t, test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField()))
+}
+
+func TestPairingCheckCircuit(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("the pairing check circuit is large")
+	}
+
+	var (
+		limits     = &Limits{NbInputInstances: 1}
+		circuit    = NewPairingCheckCircuit(limits)
+		assignment = NewPairingCheckCircuit(limits)
+		filler     = pairingCheckFiller()
+		inputs     = make([]field.Element, nbRowsPerPairingCheck)
+	)
+
+	// The filler must be a valid instance of the circuit
+	for i := range inputs {
+		inputs[i] = filler(0, i)
+	}
+
+	for j := range assignment.Instances[0].Pairs {
+		var (
+			pair   = &assignment.Instances[0].Pairs[j]
+			offset = j * (nbLimbsG1 + nbLimbsG2)
+		)
+		assignLimbs(pair.P[:], inputs[offset:offset+nbLimbsG1])
+		assignLimbs(pair.Q[:], inputs[offset+nbLimbsG1:offset+nbLimbsG1+nbLimbsG2])
+	}
+
+	assert.NoError(t, test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField()))
+}
@@ -0,0 +1,25 @@
+// Package ec_bls provides the integration of the BLS12-381 precompiles of
+// EIP-2537 (G1 addition, G1 multiplication and pairing check) in the zkEVM.
+//
+// The precompiles are not activated on Linea yet and the arithmetization does
+// not expose their data. The module is a scaffold: the gnark circuits verifying
+// the calls with emulated arithmetic and their alignment with the data are
+// defined, but the module is only included in the zkEVM when enabled in the
+// [Settings].
+//
+// The data of a call is laid out as by the precompile: every base field element
+// takes 64 bytes, that is 4 limbs of 128 bits, the most significant first and
+// the first one being a zero padding. A G2 point is encoded as X.c0, X.c1,
+// Y.c0, Y.c1 and a scalar takes 2 limbs. The point at infinity is encoded with
+// zeroes. The multi-scalar multiplications are expected to be split into
+// multiplications and additions by the arithmetization.
+//
+// The following is left for when the precompiles are activated:
+//
+//   - the check that the coordinates are canonical, i.e. smaller than the
+//     modulus;
+//   - the subgroup check of the G1 multiplication inputs;
+//   - the pairing checks with a negative result and the ones involving the
+//     point at infinity, the circuit only handles successful checks of
+//     [nbPairsPerPairingCheck] pairs.
+package ec_bls
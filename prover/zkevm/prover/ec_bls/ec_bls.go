@@ -0,0 +1,119 @@
+package ec_bls
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+const (
+	ROUND_NR               = 0
+	NAME_BLS_G1_ADD        = "BLS_G1_ADD_INTEGRATION"
+	NAME_BLS_G1_MUL        = "BLS_G1_MUL_INTEGRATION"
+	NAME_BLS_PAIRING_CHECK = "BLS_PAIRING_CHECK_INTEGRATION"
+)
+
+// EcBls integrates the verification of the EIP-2537 precompile calls inside
+// gnark circuits. The alignment of an operation is nil if it has no circuit
+// instance.
+type EcBls struct {
+	*BlsDataSource
+	AlignedG1Add        *plonk.Alignment
+	AlignedG1Mul        *plonk.Alignment
+	AlignedPairingCheck *plonk.Alignment
+}
+
+// BlsDataSource is a struct that holds the columns that are used to fetch the
+// data of the calls from the arithmetization. Every circuit selector flags
+// the limbs of the inputs and of the results of one operation.
+type BlsDataSource struct {
+	CsG1Add        ifaces.Column
+	CsG1Mul        ifaces.Column
+	CsPairingCheck ifaces.Column
+	Limb           ifaces.Column
+}
+
+// NewEcBlsZkEvm declares the EIP-2537 module in the zkEVM. It returns nil if
+// the module is not enabled in the settings, in which case nothing is
+// declared. Enabling it requires the arithmetization to provide the BLS_DATA
+// module.
+func NewEcBlsZkEvm(comp *wizard.CompiledIOP, settings *Settings) *EcBls {
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	return newEcBls(
+		comp,
+		settings,
+		&BlsDataSource{
+			CsG1Add:        comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_G1_ADD"),
+			CsG1Mul:        comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_G1_MUL"),
+			CsPairingCheck: comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_PAIRING_CHECK"),
+			Limb:           comp.Columns.GetHandle("blsdata.LIMB"),
+		},
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newEcBls creates a new EIP-2537 integration
+func newEcBls(comp *wizard.CompiledIOP, settings *Settings, src *BlsDataSource, plonkOptions []plonk.Option) *EcBls {
+
+	res := &EcBls{BlsDataSource: src}
+
+	if settings.G1Add.NbCircuitInstances > 0 {
+		res.AlignedG1Add = plonk.DefineAlignment(comp, &plonk.CircuitAlignmentInput{
+			Name:               NAME_BLS_G1_ADD + "_ALIGNMENT",
+			Round:              ROUND_NR,
+			DataToCircuitMask:  src.CsG1Add,
+			DataToCircuit:      src.Limb,
+			Circuit:            NewG1AddCircuit(&settings.G1Add),
+			NbCircuitInstances: settings.G1Add.NbCircuitInstances,
+			PlonkOptions:       plonkOptions,
+			InputFiller:        nil, // not necessary: 0 + 0 = 0 with complete arithmetic
+		})
+	}
+
+	if settings.G1Mul.NbCircuitInstances > 0 {
+		res.AlignedG1Mul = plonk.DefineAlignment(comp, &plonk.CircuitAlignmentInput{
+			Name:               NAME_BLS_G1_MUL + "_ALIGNMENT",
+			Round:              ROUND_NR,
+			DataToCircuitMask:  src.CsG1Mul,
+			DataToCircuit:      src.Limb,
+			Circuit:            NewG1MulCircuit(&settings.G1Mul),
+			NbCircuitInstances: settings.G1Mul.NbCircuitInstances,
+			PlonkOptions:       plonkOptions,
+			InputFiller:        nil, // not necessary: 0 * 0 = 0 with complete arithmetic
+		})
+	}
+
+	if settings.PairingCheck.NbCircuitInstances > 0 {
+		res.AlignedPairingCheck = plonk.DefineAlignment(comp, &plonk.CircuitAlignmentInput{
+			Name:               NAME_BLS_PAIRING_CHECK + "_ALIGNMENT",
+			Round:              ROUND_NR,
+			DataToCircuitMask:  src.CsPairingCheck,
+			DataToCircuit:      src.Limb,
+			Circuit:            NewPairingCheckCircuit(&settings.PairingCheck),
+			NbCircuitInstances: settings.PairingCheck.NbCircuitInstances,
+			PlonkOptions:       plonkOptions,
+			InputFiller:        pairingCheckFiller(),
+		})
+	}
+
+	return res
+}
+
+// Assign assigns the data from the trace to the gnark inputs. It is a no-op
+// if the module is disabled.
+func (e *EcBls) Assign(run *wizard.ProverRuntime) {
+
+	if e == nil {
+		return
+	}
+
+	for _, a := range []*plonk.Alignment{e.AlignedG1Add, e.AlignedG1Mul, e.AlignedPairingCheck} {
+		if a != nil {
+			a.Assign(run)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ec_bls
+
+import (
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcBlsDisabled(t *testing.T) {
+
+	var ecBls *EcBls
+
+	cmp := wizard.Compile(
+		func(b *wizard.Builder) {
+			// The columns of the arithmetization are not required
+			ecBls = NewEcBlsZkEvm(b.CompiledIOP, &Settings{
+				G1Add: Limits{NbInputInstances: 1, NbCircuitInstances: 1},
+			})
+			b.RegisterCommit("A", 4)
+		},
+		dummy.Compile,
+	)
+
+	assert.Nil(t, ecBls)
+
+	proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+		run.AssignColumn("A", smartvectors.NewConstant(field.Zero(), 4))
+		ecBls.Assign(run)
+	})
+
+	assert.NoError(t, wizard.Verify(cmp, proof))
+}
+
+func TestEcBlsG1AddIntegration(t *testing.T) {
+
+	var (
+		p, q, r  bls12381.G1Affine
+		size     = 64
+		settings = &Settings{
+			Enabled: true,
+			G1Add:   Limits{NbInputInstances: 2, NbCircuitInstances: 1},
+		}
+		ecBls *EcBls
+	)
+
+	p, q = randomG1(t), randomG1(t)
+	r.Add(&p, &q)
+
+	// A single call, the second instance of the circuit is filled with zeroes
+	var limbs []field.Element
+	for _, x := range []*bls12381.G1Affine{&p, &q, &r} {
+		l := g1Limbs(x)
+		limbs = append(limbs, l[:]...)
+	}
+
+	cmp := wizard.Compile(
+		func(b *wizard.Builder) {
+			src := &BlsDataSource{
+				CsG1Add:        b.RegisterCommit("CS_G1_ADD", size),
+				CsG1Mul:        b.RegisterCommit("CS_G1_MUL", size),
+				CsPairingCheck: b.RegisterCommit("CS_PAIRING_CHECK", size),
+				Limb:           b.RegisterCommit("LIMB", size),
+			}
+			ecBls = newEcBls(b.CompiledIOP, settings, src, []plonk.Option{plonk.WithRangecheck(16, 6, true)})
+		},
+		dummy.Compile,
+	)
+
+	assert.NotNil(t, ecBls.AlignedG1Add)
+	assert.Nil(t, ecBls.AlignedG1Mul)
+	assert.Nil(t, ecBls.AlignedPairingCheck)
+
+	proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+		cs := make([]field.Element, len(limbs))
+		for i := range cs {
+			cs[i].SetOne()
+		}
+		run.AssignColumn("CS_G1_ADD", smartvectors.RightZeroPadded(cs, size))
+		run.AssignColumn("CS_G1_MUL", smartvectors.NewConstant(field.Zero(), size))
+		run.AssignColumn("CS_PAIRING_CHECK", smartvectors.NewConstant(field.Zero(), size))
+		run.AssignColumn("LIMB", smartvectors.RightZeroPadded(limbs, size))
+		ecBls.Assign(run)
+	})
+
+	assert.NoError(t, wizard.Verify(cmp, proof))
+}
@@ -0,0 +1,72 @@
+package ec_bls
+
+import (
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// fpLimbs returns the limbs of a base field element as encoded by the
+// precompiles.
+func fpLimbs(x *fp.Element) (res [nbLimbsFp]field.Element) {
+	b := x.Bytes()
+	for i := 1; i < nbLimbsFp; i++ {
+		res[i].SetBytes(b[16*(i-1) : 16*i])
+	}
+	return res
+}
+
+// g1Limbs returns the limbs of a G1 point as encoded by the precompiles
+func g1Limbs(p *bls12381.G1Affine) (res [nbLimbsG1]field.Element) {
+	x, y := fpLimbs(&p.X), fpLimbs(&p.Y)
+	copy(res[:nbLimbsFp], x[:])
+	copy(res[nbLimbsFp:], y[:])
+	return res
+}
+
+// g2Limbs returns the limbs of a G2 point as encoded by the precompiles
+func g2Limbs(q *bls12381.G2Affine) (res [nbLimbsG2]field.Element) {
+	for i, c := range []*fp.Element{&q.X.A0, &q.X.A1, &q.Y.A0, &q.Y.A1} {
+		limbs := fpLimbs(c)
+		copy(res[i*nbLimbsFp:], limbs[:])
+	}
+	return res
+}
+
+// scalarLimbs returns the limbs of a 256 bits scalar as encoded by the
+// precompiles.
+func scalarLimbs(n *big.Int) (res [nbLimbsScalar]field.Element) {
+	var b [32]byte
+	n.FillBytes(b[:])
+	res[0].SetBytes(b[:16])
+	res[1].SetBytes(b[16:])
+	return res
+}
+
+// pairingCheckFiller returns an input filler for the pairing check alignment.
+// Contrary to the other operations, an instance made of zeroes is not a valid
+// input of the circuit. The unused instances are filled with the successful
+// check e(G1, G2) * e(-G1, G2) = 1 where G1 and G2 are the generators.
+func pairingCheckFiller() func(circuitInstance, inputIndex int) field.Element {
+
+	var (
+		_, _, g1, g2 = bls12381.Generators()
+		negG1        bls12381.G1Affine
+		instance     []field.Element
+	)
+
+	negG1.Neg(&g1)
+	g2l := g2Limbs(&g2)
+
+	for _, p := range []*bls12381.G1Affine{&g1, &negG1} {
+		pl := g1Limbs(p)
+		instance = append(instance, pl[:]...)
+		instance = append(instance, g2l[:]...)
+	}
+
+	return func(_, inputIndex int) field.Element {
+		return instance[inputIndex%nbRowsPerPairingCheck]
+	}
+}
@@ -0,0 +1,20 @@
+package ec_bls
+
+// Settings specifies the parameters of the EIP-2537 module
+type Settings struct {
+	// Enabled indicates whether the module is included in the zkEVM. When it
+	// is not, the other fields are ignored and no column is declared.
+	Enabled bool
+	// Limits for each of the supported operations
+	G1Add, G1Mul, PairingCheck Limits
+}
+
+// Limits defines the upper limits on the size of the circuit and the number of
+// gnark circuits for one operation. The total number of allowed calls is the
+// product of the fields.
+type Limits struct {
+	// how many calls can we verify in a single circuit
+	NbInputInstances int
+	// how many circuit instances can we have
+	NbCircuitInstances int
+}
@@ -9,9 +9,11 @@ import (
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
 	"github.com/consensys/gnark/std/math/bitslice"
 	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -70,11 +72,78 @@ func newEcMul(comp *wizard.CompiledIOP, limits *Limits, src *EcDataMulSource, pl
 	return res
 }
 
-// Assign assigns the data from the trace to the gnark inputs.
+// Assign assigns the data from the trace to the gnark inputs. It is a no-op
+// if the module is disabled.
 func (em *EcMul) Assign(run *wizard.ProverRuntime) {
+
+	if em == nil {
+		return
+	}
+
+	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+		stats := sharedBaseStatsOf(
+			em.CsEcMul.GetColAssignment(run).IntoRegVecSaveAlloc(),
+			em.IsData.GetColAssignment(run).IntoRegVecSaveAlloc(),
+			em.Index.GetColAssignment(run).IntoRegVecSaveAlloc(),
+			em.Limb.GetColAssignment(run).IntoRegVecSaveAlloc(),
+		)
+		logrus.Debugf(
+			"EC_MUL: %v calls, %v distinct base points, %v calls share their base point with another call, largest group = %v",
+			stats.NbCalls, stats.NbDistinctBases, stats.NbCallsWithSharedBase, stats.LargestGroup,
+		)
+	}
+
 	em.AlignedGnarkData.Assign(run)
 }
 
+// sharedBaseStats summarizes how often the EC_MUL calls of a trace reuse the
+// same base point. The circuit verifies every call independently. Batching the
+// calls sharing a base point via a random linear combination is only sound
+// with ~128 bits random coefficients, at which point the multi-scalar
+// multiplication costs as much as the GLV scalar multiplications it replaces.
+// The statistics are logged so that the opportunity can be re-evaluated on
+// real traffic.
+type sharedBaseStats struct {
+	NbCalls, NbDistinctBases, NbCallsWithSharedBase, LargestGroup int
+}
+
+// sharedBaseStatsOf computes the [sharedBaseStats] of the EC_MUL calls found
+// in the provided columns of the EC_DATA module. A call starts on the row where
+// the circuit selector and IS_DATA are set and INDEX is zero. The base point
+// is given by the first 4 limbs of the call (hi and lo limbs of X and Y).
+func sharedBaseStatsOf(csEcMul, isData, index, limbs []field.Element) sharedBaseStats {
+
+	var (
+		res    = sharedBaseStats{}
+		groups = map[[4]field.Element]int{}
+	)
+
+	for i := range csEcMul {
+
+		if !csEcMul[i].IsOne() || !isData[i].IsOne() || !index[i].IsZero() {
+			continue
+		}
+
+		if i+4 > len(limbs) {
+			break
+		}
+
+		base := [4]field.Element{limbs[i], limbs[i+1], limbs[i+2], limbs[i+3]}
+		groups[base]++
+		res.NbCalls++
+	}
+
+	res.NbDistinctBases = len(groups)
+	for _, size := range groups {
+		if size > 1 {
+			res.NbCallsWithSharedBase += size
+		}
+		res.LargestGroup = max(res.LargestGroup, size)
+	}
+
+	return res
+}
+
 // EcDataMulSource is a struct that holds the columns that are used to
 // fetch data from the EC_DATA module from the arithmetization.
 type EcDataMulSource struct {
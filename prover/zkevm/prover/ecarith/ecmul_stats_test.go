@@ -0,0 +1,52 @@
+package ecarith
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedBaseStats(t *testing.T) {
+
+	var (
+		csEcMul, isData, index, limbs []field.Element
+		pushCall                      = func(px, py, n uint64) {
+			call := []uint64{0, px, 0, py, 0, n, 1, 2, 3, 4}
+			for k := range call {
+				csEcMul = append(csEcMul, field.One())
+				isData = append(isData, field.NewElement(boolToUint(k < 6)))
+				index = append(index, field.NewElement(uint64(k%6)))
+				limbs = append(limbs, field.NewElement(call[k]))
+			}
+		}
+		pushPadding = func() {
+			csEcMul = append(csEcMul, field.Zero())
+			isData = append(isData, field.Zero())
+			index = append(index, field.Zero())
+			limbs = append(limbs, field.Zero())
+		}
+	)
+
+	pushCall(1, 2, 5)
+	pushCall(1, 2, 7)
+	pushPadding()
+	pushCall(3, 4, 5)
+	pushCall(1, 2, 9)
+	pushPadding()
+
+	stats := sharedBaseStatsOf(csEcMul, isData, index, limbs)
+	assert.Equal(t, sharedBaseStats{
+		NbCalls:               4,
+		NbDistinctBases:       2,
+		NbCallsWithSharedBase: 3,
+		LargestGroup:          3,
+	}, stats)
+}
+
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
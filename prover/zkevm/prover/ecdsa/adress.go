@@ -7,7 +7,6 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
-	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/byte32cmp"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
@@ -15,6 +14,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
 	commoncs "github.com/consensys/linea-monorepo/prover/zkevm/prover/common/common_constraints"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak/adapter"
 )
 
 // Address submodule is responsible for the columns holding the address of the sender,
@@ -47,9 +47,8 @@ type Addresses struct {
 	// used as the hassID for hashing by keccak.
 	hashNum ifaces.Column
 
-	// columns for decomposition and trimming the HashHi to AddressHi
-	limbColumnsUntrimmed        byte32cmp.LimbColumns
-	computeLimbColumnsUntrimmed wizard.ProverAction
+	// gadget trimming the HashHi to AddressHi
+	trimming *adapter.Adapter
 
 	// providers for keccak, Providers contain the inputs and outputs of keccak hash.
 	provider generic.GenericByteModule
@@ -64,7 +63,6 @@ func newAddress(comp *wizard.CompiledIOP, size int, ecRec *EcRecover, ac *antich
 	ecRecSize := ecRec.EcRecoverIsRes.Size()
 	// declare the native columns
 	addr := &Addresses{
-		addressHi:          createCol("ADDRESS_HI"),
 		addressLo:          createCol("ADDRESS_LO"),
 		isAddress:          createCol("IS_ADDRESS"),
 		addressHiUntrimmed: createCol("ADRESSHI_UNTRIMMED"),
@@ -148,21 +146,11 @@ func (addr *Addresses) csIsAddressHiEcRec(comp *wizard.CompiledIOP, ecRec *EcRec
 			addr.isAddressHiEcRec))
 }
 
-// The constraints for trimming the HashHi to AddressHi
+// The constraints for trimming the HashHi to AddressHi, it declares AddressHi.
 func (addr *Addresses) csAddressTrimming(comp *wizard.CompiledIOP) {
-
-	bitPerLimbs := 16
-	addr.limbColumnsUntrimmed, addr.computeLimbColumnsUntrimmed = byte32cmp.Decompose(comp, addr.addressHiUntrimmed, 8, bitPerLimbs)
-	// addr.LimbColumnsTrimmed, addr.computeLimbColumnsTrimmed = byte32cmp.Decompose(comp, addr.AddressHi, 2, 16)
-
-	// recompose two first limbs to get AddressHi
-	// since decomposition is in little-endian, but address is in big-endian, we get the first limbs.
-	a := addr.limbColumnsUntrimmed.Limbs[0]
-	b := addr.limbColumnsUntrimmed.Limbs[1]
-	pow2 := sym.NewConstant(1 << bitPerLimbs)
-	expr := sym.Add(a, sym.Mul(pow2, b))
-
-	comp.InsertGlobal(0, ifaces.QueryIDf("Address_Trimming"), sym.Sub(expr, addr.addressHi))
+	digest := adapter.Digest{Hi: addr.addressHiUntrimmed, Lo: addr.addressLo}
+	addr.trimming = adapter.Truncate(comp, NAME_ADDRESSES, digest, halfDigest+trimmingSize)
+	addr.addressHi = addr.trimming.Output.Hi
 }
 
 // It builds a provider from  public key extracted from Gnark-Data (as hash input) and addresses (as output).
@@ -240,34 +228,30 @@ func (addr *Addresses) assignMainColumns(
 	n := nbRowsPerEcRec
 
 	var (
-		hashHi, hashLo, isHash, trimmedHi []field.Element
+		hashHi, hashLo, isHash []field.Element
 	)
 
 	permTrace := keccak.GenerateTrace(pkModule.Data.ScanStreams(run))
-	var v, w, u field.Element
+	var v, w field.Element
 	for _, digest := range permTrace.HashOutPut {
 
 		hi := digest[:halfDigest]
 		lo := digest[halfDigest:]
-		trimmed := hi[halfDigest-trimmingSize:]
 
 		v.SetBytes(hi[:])
 		w.SetBytes(lo[:])
-		u.SetBytes(trimmed[:])
 
 		if len(hashHi) == split {
 			n = nbRowsPerTxSign
 		}
 		repeatLO := vector.Repeat(w, n)
 		repeatHi := vector.Repeat(v, n)
-		repeatTrimmedHi := vector.Repeat(u, n)
 		repeatIsTxHash := vector.Repeat(field.Zero(), n-1)
 
 		hashHi = append(hashHi, repeatHi...)
 		hashLo = append(hashLo, repeatLO...)
 		isHash = append(isHash, field.One())
 		isHash = append(isHash, repeatIsTxHash...)
-		trimmedHi = append(trimmedHi, repeatTrimmedHi...)
 	}
 
 	isFromEcRec := isHash[:split]
@@ -277,7 +261,6 @@ func (addr *Addresses) assignMainColumns(
 	run.AssignColumn(addr.addressHiUntrimmed.GetColID(), smartvectors.RightZeroPadded(hashHi, size))
 	run.AssignColumn(addr.addressLo.GetColID(), smartvectors.RightZeroPadded(hashLo, size))
 	run.AssignColumn(addr.isAddress.GetColID(), smartvectors.RightZeroPadded(isHash, size))
-	run.AssignColumn(addr.addressHi.GetColID(), smartvectors.RightZeroPadded(trimmedHi, size))
 	run.AssignColumn(addr.isAddressFromEcRec.GetColID(), smartvectors.RightZeroPadded(isFromEcRec, size))
 	run.AssignColumn(addr.isAddressFromTxnData.GetColID(), smartvectors.RightZeroPadded(isFromTxnData, size))
 
@@ -286,8 +269,8 @@ func (addr *Addresses) assignMainColumns(
 // It assigns the helper columns
 func (addr *Addresses) assignHelperColumns(run *wizard.ProverRuntime, ecRec *EcRecover) {
 
-	// assign LimbColumns from decomposition via proverAction
-	addr.computeLimbColumnsUntrimmed.Run(run)
+	// assign AddressHi from the trimming of HashHi
+	addr.trimming.Run(run)
 
 	// assign isAddressHiEcRec
 	isRes := ecRec.EcRecoverIsRes.GetColAssignment(run).IntoRegVecSaveAlloc()
@@ -72,10 +72,18 @@ type antichamber struct {
 }
 
 type Settings struct {
-	MaxNbEcRecover     int
-	MaxNbTx            int
-	NbInputInstance    int
+	MaxNbEcRecover  int
+	MaxNbTx         int
+	NbInputInstance int
+	// NbCircuitInstances is the number of gnark circuit instances the
+	// antichamber is compiled for. It is fixed by the setup: at proving time,
+	// only the instances holding the signatures of the trace are solved, the
+	// other ones are deactivated and assigned to zero.
 	NbCircuitInstances int
+	// UseGLVForRecovery switches the witness generation to GLV-accelerated
+	// scalar multiplications when recovering the public keys. It has no
+	// impact on the constraints.
+	UseGLVForRecovery bool
 }
 
 func (l *Settings) sizeAntichamber() int {
@@ -112,6 +120,7 @@ func newAntichamber(comp *wizard.CompiledIOP, inputs *antichamberInput) *anticha
 	res.txSignature = newTxSignatures(comp, txSignInputs)
 	res.EcRecover = newEcRecover(comp, inputs.settings, inputs.ecSource)
 	res.UnalignedGnarkData = newUnalignedGnarkData(comp, size, res.unalignedGnarkDataSource())
+	res.UnalignedGnarkData.useGLV = settings.UseGLVForRecovery
 	res.Addresses = newAddress(comp, size, res.EcRecover, res, inputs.txSource)
 	toAlign := &plonk.CircuitAlignmentInput{
 		Name:               NAME_GNARK_DATA,
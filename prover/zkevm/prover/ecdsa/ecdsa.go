@@ -32,10 +32,22 @@ func (e *EcdsaZkEvm) Assign(run *wizard.ProverRuntime, txSig TxSignatureGetter,
 	e.ant.assign(run, txSig, nbTx)
 }
 
-func (e *EcdsaZkEvm) GetProviders() []generic.GenericByteModule {
+// KeccakProviders returns the hashes of the public keys into the addresses and
+// the hashes of the transactions, which are proven by the keccak module. It
+// implements [keccak.Provider].
+func (e *EcdsaZkEvm) KeccakProviders() []generic.GenericByteModule {
 	return e.ant.Providers
 }
 
+// NumKeccakF implements [keccak.Provider]. The public keys hashed into the
+// addresses are not known to the arithmetization, their hashes are thus
+// accounted for here: a public key is 64 bytes long and requires a single
+// keccakf permutation.
+func (e *EcdsaZkEvm) NumKeccakF() int {
+	s := e.ant.Inputs.settings
+	return s.MaxNbEcRecover + s.MaxNbTx
+}
+
 func getEcdataArithmetization(comp *wizard.CompiledIOP) *ecDataSource {
 	return &ecDataSource{
 		CsEcrecover: comp.Columns.GetHandle("ecdata.CIRCUIT_SELECTOR_ECRECOVER"),
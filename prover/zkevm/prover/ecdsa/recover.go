@@ -0,0 +1,164 @@
+package ecdsa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/linea-monorepo/prover/maths/ec"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+)
+
+// pkRecoveryInput collects the inputs needed to recover the public key of a
+// signature: the prehashed message, the signature (r, s) and the recovery
+// identifier v (0 or 1, i.e. without the +27 offset).
+type pkRecoveryInput struct {
+	msg  [32]byte
+	r, s *big.Int
+	v    uint
+}
+
+// batchRecoverPublicKeys recovers the public keys corresponding to all the
+// provided signatures. It returns the same results as calling
+// [ecdsa.PublicKey.RecoverFrom] on every input but is faster when many
+// signatures have to be processed:
+//
+//   - the modular inverses of the r values are computed with a single batch
+//     inversion instead of one inversion per signature;
+//   - the scalar multiplications are distributed over all the available cores;
+//   - the conversion of the results to affine coordinates is batched and
+//     distributed over the cores.
+//
+// When useGLV is set, [u1]G + [u2]R is computed as two separate GLV-accelerated
+// scalar multiplications instead of a joint Straus-Shamir multiplication.
+//
+// The function returns an error indicating the position of the first invalid
+// input if any.
+func batchRecoverPublicKeys(inputs []pkRecoveryInput, useGLV bool) ([]secp256k1.G1Affine, error) {
+
+	var (
+		n    = len(inputs)
+		rs   = make([]fr.Element, n)
+		errs = make([]error, n)
+		res  = make([]secp256k1.G1Jac, n)
+		frN  = fr.Modulus()
+	)
+
+	for i := range inputs {
+
+		if err := checkScalarRange(inputs[i].r); err != nil {
+			return nil, fmt.Errorf("signature %v: r: %w", i, err)
+		}
+
+		if err := checkScalarRange(inputs[i].s); err != nil {
+			return nil, fmt.Errorf("signature %v: s: %w", i, err)
+		}
+
+		rs[i].SetBigInt(inputs[i].r)
+	}
+
+	rInvs := fr.BatchInvert(rs)
+
+	parallel.Execute(n, func(start, stop int) {
+		for i := start; i < stop; i++ {
+
+			var (
+				in          = &inputs[i]
+				z           = ecdsa.HashToInt(in.msg[:])
+				zFr, sFr    fr.Element
+				u1Fr, u2Fr  fr.Element
+				u1, u2      big.Int
+				rPoint, err = recoverRPoint(in.v, in.r)
+			)
+
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+
+			zFr.SetBigInt(new(big.Int).Mod(z, frN))
+			sFr.SetBigInt(in.s)
+
+			// u1 = -z / r and u2 = s / r
+			u1Fr.Mul(&zFr, &rInvs[i]).Neg(&u1Fr)
+			u2Fr.Mul(&sFr, &rInvs[i])
+			u1Fr.BigInt(&u1)
+			u2Fr.BigInt(&u2)
+
+			if !useGLV {
+				res[i].JointScalarMultiplicationBase(rPoint, &u1, &u2)
+				continue
+			}
+
+			var (
+				rJac secp256k1.G1Jac
+				tmp  secp256k1.G1Jac
+			)
+
+			rJac.FromAffine(rPoint)
+			res[i].ScalarMultiplicationBase(&u1)
+			tmp.ScalarMultiplication(&rJac, &u2)
+			res[i].AddAssign(&tmp)
+		}
+	})
+
+	for i := range errs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("signature %v: %w", i, errs[i])
+		}
+	}
+
+	return ec.BatchJacobianToAffineSecp256k1(res), nil
+}
+
+// checkScalarRange returns an error if x is not in the range [1, n) where n
+// is the order of the secp256k1 group.
+func checkScalarRange(x *big.Int) error {
+	if x.Sign() <= 0 {
+		return errors.New("must be positive")
+	}
+	if x.Cmp(fr.Modulus()) >= 0 {
+		return errors.New("must be smaller than the group order")
+	}
+	return nil
+}
+
+// recoverRPoint recovers the point R of the signature from its x coordinate
+// r and the recovery identifier v. Bit 1 of v indicates whether the x
+// coordinate of R is r or r + n and bit 0 of v indicates the parity of its y
+// coordinate.
+func recoverRPoint(v uint, r *big.Int) (*secp256k1.G1Affine, error) {
+
+	var (
+		xBig       = new(big.Int).Set(r)
+		x, y, y2   fp.Element
+		_, b       = secp256k1.CurveCoefficients()
+		yBig       big.Int
+		wantParity = v & 1
+	)
+
+	if v&2 != 0 {
+		xBig.Add(xBig, fr.Modulus())
+		if xBig.Cmp(fp.Modulus()) >= 0 {
+			return nil, errors.New("r + n overflows the base field")
+		}
+	}
+
+	x.SetBigInt(xBig)
+
+	// y^2 = x^3 + b, the a coefficient of secp256k1 is zero
+	y2.Square(&x).Mul(&y2, &x).Add(&y2, &b)
+	if y.Sqrt(&y2) == nil {
+		return nil, ecdsa.ErrNoSqrtR
+	}
+
+	if y.BigInt(&yBig).Bit(0) != wantParity {
+		y.Neg(&y)
+	}
+
+	return &secp256k1.G1Affine{X: x, Y: y}, nil
+}
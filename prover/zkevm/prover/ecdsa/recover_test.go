@@ -0,0 +1,52 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchRecoverPublicKeys(t *testing.T) {
+
+	const nbSigs = 64
+
+	var (
+		inputs   = make([]pkRecoveryInput, 0, nbSigs)
+		expected = make([]ecdsa.PublicKey, 0, nbSigs)
+	)
+
+	for i := 0; i < nbSigs; i++ {
+
+		sk, err := ecdsa.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		var msg [32]byte
+		copy(msg[:], fmt.Sprintf("message-%v", i))
+
+		v, r, s, err := sk.SignForRecover(msg[:], nil)
+		require.NoError(t, err)
+
+		var pk ecdsa.PublicKey
+		require.NoError(t, pk.RecoverFrom(msg[:], v, r, s))
+
+		inputs = append(inputs, pkRecoveryInput{msg: msg, r: r, s: s, v: v})
+		expected = append(expected, pk)
+	}
+
+	for _, useGLV := range []bool{false, true} {
+		pks, err := batchRecoverPublicKeys(inputs, useGLV)
+		require.NoError(t, err)
+		for i := range pks {
+			require.True(t, pks[i].Equal(&expected[i].A), "glv=%v sig=%v", useGLV, i)
+		}
+	}
+}
+
+func TestBatchRecoverPublicKeysInvalid(t *testing.T) {
+	_, err := batchRecoverPublicKeys([]pkRecoveryInput{{r: big.NewInt(0), s: big.NewInt(1)}}, false)
+	require.Error(t, err)
+}
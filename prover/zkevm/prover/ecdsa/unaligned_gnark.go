@@ -3,7 +3,6 @@ package ecdsa
 import (
 	"math/big"
 
-	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
@@ -39,6 +38,11 @@ type UnalignedGnarkData struct {
 	isNotPublicKeyAndPushing ifaces.Column
 
 	size int
+
+	// useGLV indicates whether the public key recovery should use the GLV
+	// scalar multiplication rather than the joint Straus-Shamir one. This
+	// only affects the witness generation.
+	useGLV bool
 }
 
 type unalignedGnarkDataSource struct {
@@ -115,8 +119,17 @@ func (d *UnalignedGnarkData) assignUnalignedGnarkData(run *wizard.ProverRuntime,
 		panic("unexpected source length")
 	}
 
-	var resIsPublicKey, resGnarkIndex, resGnarkPkIndex, resGnarkData []field.Element
-	txCount := 0
+	var (
+		resIsPublicKey, resGnarkIndex, resGnarkPkIndex, resGnarkData []field.Element
+		txCount                                                      = 0
+		// allRows and allPrependZeroCount store the rows pushed to gnark and
+		// the number of zeroes to prepend to them for every signature. The
+		// rows 0..3 (the public key) are only filled once all the public
+		// keys have been recovered.
+		allRows             [][]field.Element
+		allPrependZeroCount []uint
+		recoveryInputs      []pkRecoveryInput
+	)
 
 	for i := 0; i < d.size; {
 
@@ -199,19 +212,38 @@ func (d *UnalignedGnarkData) assignUnalignedGnarkData(run *wizard.ProverRuntime,
 			// we have run out of inputs.
 			break
 		}
-		// compute the expected public key
-		var pk ecdsa.PublicKey
+
 		if !v.IsUint64() {
 			utils.Panic("v is not a uint64")
 		}
-		err = pk.RecoverFrom(prehashedMsg[:], uint(v.Uint64()-27), r, s)
-		if err != nil {
-			utils.Panic("error recovering public: err=%v v=%v r=%v s=%v", err.Error(), v.Uint64()-27, r.String(), s.String())
-		}
-		pkx := pk.A.X.Bytes()
+
+		recoveryInputs = append(recoveryInputs, pkRecoveryInput{
+			msg: prehashedMsg,
+			r:   r,
+			s:   s,
+			v:   uint(v.Uint64() - 27),
+		})
+		allRows = append(allRows, rows)
+		allPrependZeroCount = append(allPrependZeroCount, prependZeroCount)
+	}
+
+	// compute the expected public keys. This is the expensive part of the
+	// assignment so it is done for all the signatures at once.
+	pks, err := batchRecoverPublicKeys(recoveryInputs, d.useGLV)
+	if err != nil {
+		utils.Panic("error recovering public key: %v", err)
+	}
+
+	for k, rows := range allRows {
+
+		var (
+			prependZeroCount = allPrependZeroCount[k]
+			pkx              = pks[k].X.Bytes()
+			pky              = pks[k].Y.Bytes()
+		)
+
 		rows[0].SetBytes(pkx[:16])
 		rows[1].SetBytes(pkx[16:])
-		pky := pk.A.Y.Bytes()
 		rows[2].SetBytes(pky[:16])
 		rows[3].SetBytes(pky[16:])
 
@@ -2,7 +2,9 @@ package ecpair
 
 import (
 	"fmt"
+	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/emulated/fields_bn254"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
@@ -309,3 +311,114 @@ func (c *MillerLoopFinalExpInstance) Check(api frontend.API, fp *emulated.Field[
 
 	return evmprecompiles.ECPairMillerLoopAndFinalExpCheck(api, &prev, &P, &Q, c.Expected[1])
 }
+
+// modulusHi and modulusLo are the 128 bits limbs of the modulus of the base
+// field of BN254.
+var (
+	modulusHi, _ = new(big.Int).SetString("30644e72e131a029b85045b68181585d", 16)
+	modulusLo, _ = new(big.Int).SetString("97816a916871ca8d3c208c16d87cfd47", 16)
+)
+
+// MultiCurveCheckCircuit is a circuit that checks the validity of multiple
+// pairs of inputs of the failing calls. Use [newMultiCurveCheckCircuit] to
+// create a new instance with bounded number of allowed checks.
+type MultiCurveCheckCircuit struct {
+	Instances []CurveCheckInstance `gnark:",public"`
+}
+
+func newMultiCurveCheckCircuit(nbInstances int) *MultiCurveCheckCircuit {
+	return &MultiCurveCheckCircuit{
+		Instances: make([]CurveCheckInstance, nbInstances),
+	}
+}
+
+func (c *MultiCurveCheckCircuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField[sw_bn254.BaseField](api)
+	if err != nil {
+		return fmt.Errorf("new field emulation: %w", err)
+	}
+	pairing, err := sw_bn254.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+	for i := range c.Instances {
+		if err := c.Instances[i].Check(api, fp, pairing); err != nil {
+			return fmt.Errorf("instance %d check: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// CurveCheckInstance is a single pair of a failing call. IsValid tells
+// whether the coordinates are reduced, P is on the curve and Q is in G2. The
+// points at infinity are encoded as (0, 0) and are valid.
+type CurveCheckInstance struct {
+	P       G1ElementWizard
+	Q       G2ElementWizard
+	IsValid frontend.Variable
+}
+
+func (c *CurveCheckInstance) Check(api frontend.API, fp *emulated.Field[sw_bn254.BaseField], pairing *sw_bn254.Pairing) error {
+	var (
+		P    = c.P.ToG1Element(api, fp)
+		Q    = c.Q.ToG2Element(api, fp)
+		ext2 = fields_bn254.NewExt2(api)
+	)
+
+	isReduced := frontend.Variable(1)
+	for i := 0; i < nbG1Limbs; i += 2 {
+		isReduced = api.And(isReduced, isReducedCoordinateInCircuit(api, c.P.P[i], c.P.P[i+1]))
+	}
+	for i := 0; i < nbG2Limbs; i += 2 {
+		isReduced = api.And(isReduced, isReducedCoordinateInCircuit(api, c.Q.Q[i], c.Q.Q[i+1]))
+	}
+
+	// P is on the curve y² = x³ + 3 or is (0, 0)
+	var (
+		isZeroP   = api.And(fp.IsZero(&P.X), fp.IsZero(&P.Y))
+		rhsP      = fp.Add(fp.Mul(fp.Mul(&P.X, &P.X), &P.X), fp.NewElement(3))
+		isOnCurve = api.Or(fp.IsZero(fp.Sub(fp.Mul(&P.Y, &P.Y), rhsP)), isZeroP)
+	)
+
+	// Q is on the twist y² = x³ + b' with b' = 3/(9+u) or is (0, 0)
+	var (
+		isZeroQ = api.And(
+			api.And(fp.IsZero(&Q.P.X.A0), fp.IsZero(&Q.P.X.A1)),
+			api.And(fp.IsZero(&Q.P.Y.A0), fp.IsZero(&Q.P.Y.A1)),
+		)
+		bTwist = fields_bn254.E2{
+			A0: emulated.ValueOf[sw_bn254.BaseField]("19485874751759354771024239261021720505790618469301721065564631296452457478373"),
+			A1: emulated.ValueOf[sw_bn254.BaseField]("266929791119991161246907387137283842545076965332900288569378510910307636690"),
+		}
+		rhsQ      = ext2.Add(ext2.Mul(ext2.Square(&Q.P.X), &Q.P.X), &bTwist)
+		diffQ     = ext2.Sub(ext2.Square(&Q.P.Y), rhsQ)
+		isOnTwist = api.Or(api.And(fp.IsZero(&diffQ.A0), fp.IsZero(&diffQ.A1)), isZeroQ)
+	)
+
+	// The subgroup check is only sound for the points on the twist and does
+	// not handle (0, 0), the other points are replaced by the generator.
+	var (
+		_, _, _, g2Gen = bn254.Generators()
+		gen            = sw_bn254.NewG2Affine(g2Gen)
+		useQ           = api.And(isOnTwist, api.Sub(1, isZeroQ))
+		toCheck        sw_bn254.G2Affine
+	)
+	toCheck.P.X = *ext2.Select(useQ, &Q.P.X, &gen.P.X)
+	toCheck.P.Y = *ext2.Select(useQ, &Q.P.Y, &gen.P.Y)
+	isInG2 := api.And(isOnTwist, api.Or(isZeroQ, pairing.IsOnG2(&toCheck)))
+
+	api.AssertIsEqual(c.IsValid, api.And(isReduced, api.And(isOnCurve, isInG2)))
+	return nil
+}
+
+// isReducedCoordinateInCircuit returns 1 if the coordinate given by its 128 bits limbs
+// is smaller than the modulus of the base field and 0 otherwise.
+func isReducedCoordinateInCircuit(api frontend.API, hi, lo frontend.Variable) frontend.Variable {
+	isLess := func(a, b frontend.Variable) frontend.Variable {
+		return api.IsZero(api.Add(api.Cmp(a, b), 1))
+	}
+	return api.Or(
+		isLess(hi, modulusHi),
+		api.And(api.IsZero(api.Sub(hi, modulusHi)), isLess(lo, modulusLo)),
+	)
+}
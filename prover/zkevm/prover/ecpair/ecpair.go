@@ -4,6 +4,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
 )
 
 func createColFn(comp *wizard.CompiledIOP, rootName string, size int) func(name string) ifaces.Column {
@@ -17,9 +18,11 @@ const (
 	nameECPair              = "ECPAIR"
 	namePairingData         = "ECPAIR_UNALIGNED_PAIRING_DATA"
 	nameG2Data              = "ECPAIR_UNALIGNED_G2_DATA"
+	nameCurveCheckData      = "ECPAIR_UNALIGNED_CURVE_CHECK_DATA"
 	nameAlignmentG2Subgroup = "ECPAIR_ALIGNMENT_G2"
 	nameAlignmentMillerLoop = "ECPAIR_ALIGNMENT_ML"
 	nameAlignmentFinalExp   = "ECPAIR_ALIGNMENT_FINALEXP"
+	nameAlignmentCurveCheck = "ECPAIR_ALIGNMENT_CURVE_CHECK"
 )
 
 const (
@@ -35,12 +38,15 @@ const (
 // - AlignedG2MembershipData: the aligned columns for the G2 check circuit
 // - AlignedMillerLoopCircuit: the aligned columns for the MillerLoop circuit
 // - AlignedFinalExpCircuit: the aligned columns for the FinalExp circuit
+// - UnalignedCurveCheckData: the unaligned columns for the inputs of the
+// failing calls, see [ECPair.WithMalformedInputs]
+// - AlignedCurveCheckCircuit: the aligned columns for the curve check circuit
 //
 // Use [newECPair] to create a new instance of ECPair with the limits and source columns.
 //
 // By default, the gnark circuit is not attached to the module. Use methods
-// [WithPairingCircuit] and [WithG2MembershipCircuit] for attaching the circuit
-// and enforcing the actual checks at prover runtime.
+// [WithPairingCircuit], [WithG2MembershipCircuit] and [WithCurveCheckCircuit]
+// for attaching the circuit and enforcing the actual checks at prover runtime.
 type ECPair struct {
 	*Limits
 
@@ -55,6 +61,9 @@ type ECPair struct {
 	AlignedG2MembershipData  *plonk.Alignment
 	AlignedMillerLoopCircuit *plonk.Alignment
 	AlignedFinalExpCircuit   *plonk.Alignment
+
+	UnalignedCurveCheckData  *UnalignedCurveCheckData
+	AlignedCurveCheckCircuit *plonk.Alignment
 }
 
 func NewECPairZkEvm(comp *wizard.CompiledIOP, limits *Limits) *ECPair {
@@ -74,7 +83,9 @@ func NewECPairZkEvm(comp *wizard.CompiledIOP, limits *Limits) *ECPair {
 			CsG2Membership:    comp.Columns.GetHandle("ecdata.CIRCUIT_SELECTOR_G2_MEMBERSHIP"),
 		},
 	).WithG2MembershipCircuit(comp).
-		WithPairingCircuit(comp, plonk.WithRangecheck(16, 6, true))
+		WithPairingCircuit(comp, plonk.WithRangecheck(16, 6, true)).
+		WithMalformedInputs(comp).
+		WithCurveCheckCircuit(comp, plonk.WithRangecheck(16, 6, true))
 }
 
 func newECPair(comp *wizard.CompiledIOP, limits *Limits, ecSource *ECPairSource) *ECPair {
@@ -167,6 +178,44 @@ func (ec *ECPair) WithG2MembershipCircuit(comp *wizard.CompiledIOP, options ...p
 	return ec
 }
 
+// WithMalformedInputs extends the module to prove the calls failing because of
+// malformed inputs, as required by EIP-197: a coordinate larger than the
+// modulus, a point which is not on the curve or a G2 point which is not in
+// the subgroup. Every pair of the failing calls is sent to the curve check
+// circuit telling whether the pair is valid, and the module constrains at
+// least one of the pairs of every failing call to be invalid.
+//
+// The calls failing because of their input length are handled by the
+// arithmetization and do not reach the ECDATA module.
+func (ec *ECPair) WithMalformedInputs(comp *wizard.CompiledIOP) *ECPair {
+	ec.UnalignedCurveCheckData = newUnalignedCurveCheckData(comp, ec.Limits, ec.ECPairSource)
+	ec.csMalformedInputs(comp)
+	return ec
+}
+
+// WithCurveCheckCircuit attaches the gnark circuit to the ECPair module for
+// enforcing the validity checks of the pairs of the failing calls. It must be
+// called after [ECPair.WithMalformedInputs].
+func (ec *ECPair) WithCurveCheckCircuit(comp *wizard.CompiledIOP, options ...plonk.Option) *ECPair {
+	if ec.UnalignedCurveCheckData == nil {
+		utils.Panic("ECPair: the curve check circuit requires the malformed inputs to be enabled")
+	}
+
+	alignInputCurveCheck := &plonk.CircuitAlignmentInput{
+		Round:              roundNr,
+		Name:               nameAlignmentCurveCheck,
+		DataToCircuit:      ec.UnalignedCurveCheckData.Limb,
+		DataToCircuitMask:  ec.UnalignedCurveCheckData.IsActive,
+		Circuit:            newMultiCurveCheckCircuit(ec.NbCurveCheckInputInstances),
+		InputFiller:        inputFillerCurveCheck,
+		PlonkOptions:       options,
+		NbCircuitInstances: ec.NbCurveCheckCircuits,
+	}
+	ec.AlignedCurveCheckCircuit = plonk.DefineAlignment(comp, alignInputCurveCheck)
+
+	return ec
+}
+
 // ECPairSource represents the source columns from the arithmetization of the
 // ECPAIR precompile. We assume that the data in the columns is already
 // well-formed.
@@ -269,3 +318,65 @@ func newUnalignedPairingData(comp *wizard.CompiledIOP, limits *Limits) *Unaligne
 		Index:                        createCol("INDEX"),
 	}
 }
+
+// UnalignedCurveCheckData represents the unaligned columns for the inputs of
+// the failing calls.
+//
+// Every pair of a failing call is pulled from the source (4 G1 limbs and 8 G2
+// limbs) and followed by a computed row holding whether the pair is valid.
+// The accumulator tells whether all the pairs of the call seen so far are
+// valid and must be zero at the last pair of the call.
+//
+// Use [newUnalignedCurveCheckData] to create a new instance of
+// UnalignedCurveCheckData.
+type UnalignedCurveCheckData struct {
+	// SrcIsFailingPairingData flags the data rows of the failing calls in the
+	// source columns. It has the size of the source.
+	SrcIsFailingPairingData ifaces.Column
+
+	IsActive   ifaces.Column
+	IsPulling  ifaces.Column
+	IsComputed ifaces.Column
+
+	Limb       ifaces.Column
+	InstanceID ifaces.Column
+	PairID     ifaces.Column
+	TotalPairs ifaces.Column
+	// Index is the position of the row in the pair, from 0 to 12. The row
+	// with index 12 is the computed one.
+	Index ifaces.Column
+	// AccIsValid is the product of the validity bits of the pairs of the call
+	// up to the current row.
+	AccIsValid ifaces.Column
+
+	// IsSameInstance, IsLastPair and IsLastIndex are constrained through
+	// [dedicated.IsZero] and assigned by the corresponding prover actions.
+	IsSameInstance    ifaces.Column
+	IsLastPair        ifaces.Column
+	IsLastIndex       ifaces.Column
+	CptIsSameInstance wizard.ProverAction
+	CptIsLastPair     wizard.ProverAction
+	CptIsLastIndex    wizard.ProverAction
+}
+
+func newUnalignedCurveCheckData(comp *wizard.CompiledIOP, limits *Limits, src *ECPairSource) *UnalignedCurveCheckData {
+	size := limits.sizeCurveCheckData()
+	createCol := createColFn(comp, nameCurveCheckData, size)
+
+	return &UnalignedCurveCheckData{
+		SrcIsFailingPairingData: comp.InsertCommit(
+			roundNr,
+			ifaces.ColIDf("%s_SRC_IS_FAILING_PAIRING_DATA", nameCurveCheckData),
+			src.Limb.Size(),
+		),
+		IsActive:   createCol("IS_ACTIVE"),
+		IsPulling:  createCol("IS_PULLING"),
+		IsComputed: createCol("IS_COMPUTED"),
+		Limb:       createCol("LIMB"),
+		InstanceID: createCol("INSTANCE_ID"),
+		PairID:     createCol("PAIR_ID"),
+		TotalPairs: createCol("TOTAL_PAIRS"),
+		Index:      createCol("INDEX"),
+		AccIsValid: createCol("ACC_IS_VALID"),
+	}
+}
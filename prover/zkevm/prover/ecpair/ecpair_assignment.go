@@ -8,13 +8,20 @@ import (
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
 )
 
-// Assign assigns the data to the circuit
+// Assign assigns the data to the circuit. It is a no-op if the module is
+// disabled.
 func (ec *ECPair) Assign(run *wizard.ProverRuntime) {
 
+	if ec == nil {
+		return
+	}
+
 	// assign data to the pairing check part
 	ec.assignPairingData(run)
 	// assign data to the membership check part
 	ec.assignMembershipData(run)
+	// assign data to the curve check part
+	ec.assignCurveCheckData(run)
 	// assign the column telling wether the previous and the current row have
 	// the same id.
 	ec.CptPrevEqualCurrID.Run(run)
@@ -51,6 +58,10 @@ func (ec *ECPair) Assign(run *wizard.ProverRuntime) {
 	if ec.AlignedFinalExpCircuit != nil {
 		ec.AlignedFinalExpCircuit.Assign(run)
 	}
+	// assign the public inputs for gnark curve check circuit
+	if ec.AlignedCurveCheckCircuit != nil {
+		ec.AlignedCurveCheckCircuit.Assign(run)
+	}
 }
 
 func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) {
@@ -323,3 +334,115 @@ func (ec *ECPair) assignMembershipData(run *wizard.ProverRuntime) {
 	dstIsPulling.PadAndAssign(run, field.Zero())
 	dstIsComputed.PadAndAssign(run, field.Zero())
 }
+
+// assignCurveCheckData assigns the pairs of the failing calls. It is a no-op
+// if the malformed inputs are not enabled, see [ECPair.WithMalformedInputs].
+func (ec *ECPair) assignCurveCheckData(run *wizard.ProverRuntime) {
+	cc := ec.UnalignedCurveCheckData
+	if cc == nil {
+		return
+	}
+
+	var (
+		srcIsData     = ec.ECPairSource.IsEcPairingData.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcSuccessBit = ec.ECPairSource.SuccessBit.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcLimbs      = ec.ECPairSource.Limb.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcID         = ec.ECPairSource.ID.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcPairID     = ec.ECPairSource.AccPairings.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcTotalPairs = ec.ECPairSource.TotalPairings.GetColAssignment(run).IntoRegVecSaveAlloc()
+	)
+	if len(srcIsData) != len(srcLimbs) || len(srcIsData) != len(srcSuccessBit) {
+		utils.Panic("ECPair: input length mismatch")
+	}
+
+	var (
+		srcIsFailing  = common.NewVectorBuilder(cc.SrcIsFailingPairingData)
+		dstIsActive   = common.NewVectorBuilder(cc.IsActive)
+		dstIsPulling  = common.NewVectorBuilder(cc.IsPulling)
+		dstIsComputed = common.NewVectorBuilder(cc.IsComputed)
+		dstLimb       = common.NewVectorBuilder(cc.Limb)
+		dstInstanceID = common.NewVectorBuilder(cc.InstanceID)
+		dstPairID     = common.NewVectorBuilder(cc.PairID)
+		dstTotalPairs = common.NewVectorBuilder(cc.TotalPairs)
+		dstIndex      = common.NewVectorBuilder(cc.Index)
+		dstAccIsValid = common.NewVectorBuilder(cc.AccIsValid)
+	)
+
+	isFailing := func(i int) bool {
+		return srcIsData[i].IsOne() && srcSuccessBit[i].IsZero()
+	}
+
+	for i := range srcIsData {
+		srcIsFailing.PushBoolean(isFailing(i))
+	}
+
+	var (
+		accIsValid = true
+		prevID     field.Element
+		hasPrev    bool
+	)
+
+	for currPos := 0; currPos < len(srcLimbs); {
+		if !isFailing(currPos) {
+			currPos++
+			continue
+		}
+
+		// the data rows of a failing call are its pairs, each of them made
+		// of the G1 limbs followed by the G2 limbs.
+		var (
+			inG1 [nbG1Limbs]field.Element
+			inG2 [nbG2Limbs]field.Element
+			id   = srcID[currPos]
+		)
+		copy(inG1[:], srcLimbs[currPos:currPos+nbG1Limbs])
+		copy(inG2[:], srcLimbs[currPos+nbG1Limbs:currPos+nbG1Limbs+nbG2Limbs])
+
+		if !hasPrev || id != prevID {
+			accIsValid = true
+		}
+		prevID, hasPrev = id, true
+
+		for j := 0; j < nbG1Limbs+nbG2Limbs; j++ {
+			dstIsActive.PushOne()
+			dstIsPulling.PushOne()
+			dstIsComputed.PushZero()
+			dstLimb.PushField(srcLimbs[currPos+j])
+			dstInstanceID.PushField(id)
+			dstPairID.PushField(srcPairID[currPos+j])
+			dstTotalPairs.PushField(srcTotalPairs[currPos+j])
+			dstIndex.PushInt(j)
+			dstAccIsValid.PushBoolean(accIsValid)
+		}
+
+		isValid := isPairValid(inG1, inG2)
+		accIsValid = accIsValid && isValid
+
+		dstIsActive.PushOne()
+		dstIsPulling.PushZero()
+		dstIsComputed.PushOne()
+		dstLimb.PushBoolean(isValid)
+		dstInstanceID.PushField(id)
+		dstPairID.PushField(srcPairID[currPos+nbG1Limbs+nbG2Limbs-1])
+		dstTotalPairs.PushField(srcTotalPairs[currPos+nbG1Limbs+nbG2Limbs-1])
+		dstIndex.PushInt(nbG1Limbs + nbG2Limbs)
+		dstAccIsValid.PushBoolean(accIsValid)
+
+		currPos += nbG1Limbs + nbG2Limbs
+	}
+
+	srcIsFailing.PadAndAssign(run, field.Zero())
+	dstIsActive.PadAndAssign(run, field.Zero())
+	dstIsPulling.PadAndAssign(run, field.Zero())
+	dstIsComputed.PadAndAssign(run, field.Zero())
+	dstLimb.PadAndAssign(run, field.Zero())
+	dstInstanceID.PadAndAssign(run, field.Zero())
+	dstPairID.PadAndAssign(run, field.Zero())
+	dstTotalPairs.PadAndAssign(run, field.Zero())
+	dstIndex.PadAndAssign(run, field.Zero())
+	dstAccIsValid.PadAndAssign(run, field.Zero())
+
+	cc.CptIsLastIndex.Run(run)
+	cc.CptIsSameInstance.Run(run)
+	cc.CptIsLastPair.Run(run)
+}
@@ -290,3 +290,139 @@ func (ec *ECPair) csExclusivePairingCircuitMasks(comp *wizard.CompiledIOP) {
 		ec.UnalignedPairingData.ToFinalExpCircuitMask,
 	})
 }
+
+func (ec *ECPair) csMalformedInputs(comp *wizard.CompiledIOP) {
+	var (
+		src = ec.ECPairSource
+		cc  = ec.UnalignedCurveCheckData
+	)
+
+	// the data rows of the failing calls are the ones with a zero success bit
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_SRC_IS_FAILING_PAIRING_DATA", nameCurveCheckData),
+		sym.Sub(
+			cc.SrcIsFailingPairingData,
+			sym.Mul(src.IsEcPairingData, sym.Sub(1, src.SuccessBit)),
+		),
+	)
+
+	common.MustBeActivationColumns(comp, cc.IsActive)
+	common.MustBeMutuallyExclusiveBinaryFlags(comp, cc.IsActive, []ifaces.Column{
+		cc.IsPulling,
+		cc.IsComputed,
+	})
+	common.MustZeroWhenInactive(comp, cc.IsActive,
+		cc.Limb,
+		cc.InstanceID,
+		cc.PairID,
+		cc.TotalPairs,
+		cc.Index,
+		cc.AccIsValid,
+	)
+
+	// all the pairs of the failing calls are pulled from the source
+	projection.InsertProjection(
+		comp, ifaces.QueryIDf("%v_PROJECTION", nameCurveCheckData),
+		[]ifaces.Column{src.Limb, src.ID, src.AccPairings, src.TotalPairings},
+		[]ifaces.Column{cc.Limb, cc.InstanceID, cc.PairID, cc.TotalPairs},
+		cc.SrcIsFailingPairingData, cc.IsPulling,
+	)
+
+	// the rows come by groups of 13: the 12 limbs of the pair followed by the
+	// computed validity bit. INDEX starts at zero and is reset after every
+	// computed row.
+	comp.InsertLocal(
+		roundNr,
+		ifaces.QueryIDf("%v_INDEX_START", nameCurveCheckData),
+		ifaces.ColumnAsVariable(cc.Index),
+	)
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_INDEX_INCREMENT", nameCurveCheckData),
+		sym.Mul(
+			cc.IsActive,
+			sym.Sub(
+				cc.Index,
+				sym.Mul(
+					sym.Sub(1, column.Shift(cc.IsComputed, -1)),
+					sym.Add(column.Shift(cc.Index, -1), 1),
+				),
+			),
+		),
+	)
+
+	// the last active row is a computed one, otherwise the prover could drop
+	// the computed row of the last pair and skip the check of its call. The
+	// shift does not wrap around as the last row is always padding.
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_ENDS_WITH_COMPUTED", nameCurveCheckData),
+		sym.Mul(
+			cc.IsActive,
+			sym.Sub(1, column.Shift(cc.IsActive, 1)),
+			sym.Sub(1, cc.IsComputed),
+		),
+	)
+
+	cc.IsLastIndex, cc.CptIsLastIndex = dedicated.IsZero(comp, sym.Sub(cc.Index, nbG1Limbs+nbG2Limbs))
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_COMPUTED_AT_LAST_INDEX", nameCurveCheckData),
+		sym.Mul(cc.IsActive, sym.Sub(cc.IsComputed, cc.IsLastIndex)),
+	)
+
+	// the computed rows belong to the pair of the rows before them
+	for _, col := range []ifaces.Column{cc.InstanceID, cc.PairID, cc.TotalPairs} {
+		comp.InsertGlobal(
+			roundNr,
+			ifaces.QueryIDf("%v_CONSTANT_WHEN_IS_COMPUTED", col.GetColID()),
+			sym.Mul(cc.IsComputed, sym.Sub(col, column.Shift(col, -1))),
+		)
+	}
+
+	// The accumulator is reset to 1 at the start of every call, is constant
+	// over the pulled rows and is multiplied by the validity bit on the
+	// computed rows. The shift wraps around on the first row, which is then
+	// detected as the start of a call thanks to the padding.
+	cc.IsSameInstance, cc.CptIsSameInstance = dedicated.IsZero(
+		comp,
+		sym.Sub(cc.InstanceID, column.Shift(cc.InstanceID, -1)),
+	)
+
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_ACC_INIT", nameCurveCheckData),
+		sym.Mul(
+			cc.IsPulling,
+			sym.Sub(1, cc.IsSameInstance),
+			sym.Sub(cc.AccIsValid, 1),
+		),
+	)
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_ACC_CONSTANT_WHEN_PULLING", nameCurveCheckData),
+		sym.Mul(
+			cc.IsPulling,
+			cc.IsSameInstance,
+			sym.Sub(cc.AccIsValid, column.Shift(cc.AccIsValid, -1)),
+		),
+	)
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_ACC_UPDATE", nameCurveCheckData),
+		sym.Mul(
+			cc.IsComputed,
+			sym.Sub(cc.AccIsValid, sym.Mul(column.Shift(cc.AccIsValid, -1), cc.Limb)),
+		),
+	)
+
+	// IF IS_COMPUTED AND PAIR_ID == TOTAL_PAIRS => ACC_IS_VALID = 0, that is,
+	// one of the pairs of the failing call is invalid.
+	cc.IsLastPair, cc.CptIsLastPair = dedicated.IsZero(comp, sym.Sub(cc.PairID, cc.TotalPairs))
+	comp.InsertGlobal(
+		roundNr,
+		ifaces.QueryIDf("%v_FAILING_CALL_HAS_INVALID_PAIR", nameCurveCheckData),
+		sym.Mul(cc.IsComputed, cc.IsLastPair, cc.AccIsValid),
+	)
+}
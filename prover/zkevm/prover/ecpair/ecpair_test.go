@@ -1,17 +1,27 @@
 package ecpair
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
 )
 
 type pairingDataTestCase struct {
 	InputFName, ModuleFName                      string
 	NbMillerLoops, NbFinalExps, NbSubgroupChecks int
+	// NbCurveChecks enables the malformed inputs when set
+	NbCurveChecks int
+	// Tamper, when set, modifies the honest assignment of the module. The
+	// verification is then expected to fail.
+	Tamper func(run *wizard.ProverRuntime, mod *ECPair)
 }
 
 var pairingDataTestCases = []pairingDataTestCase{
@@ -62,7 +72,7 @@ var pairingDataTestCases = []pairingDataTestCase{
 	},
 }
 
-func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2MembershipCircuit bool, checkPairingModule, checkSubgroupModule bool) {
+func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2MembershipCircuit, withCurveCheckCircuit bool, checkPairingModule, checkSubgroupModule bool) {
 	t.Run(tc.InputFName, func(t *testing.T) {
 
 		var (
@@ -93,6 +103,10 @@ func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2
 		if tc.NbSubgroupChecks > 0 {
 			limits.NbG2MembershipCircuits = 1
 		}
+		if tc.NbCurveChecks > 0 {
+			limits.NbCurveCheckInputInstances = tc.NbCurveChecks
+			limits.NbCurveCheckCircuits = 1
+		}
 
 		cmp := wizard.Compile(func(build *wizard.Builder) {
 			inp = &ECPairSource{
@@ -115,6 +129,12 @@ func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2
 			if withG2MembershipCircuit {
 				mod.WithG2MembershipCircuit(build.CompiledIOP, plonk.WithRangecheck(16, 6, false))
 			}
+			if tc.NbCurveChecks > 0 {
+				mod.WithMalformedInputs(build.CompiledIOP)
+			}
+			if withCurveCheckCircuit {
+				mod.WithCurveCheckCircuit(build.CompiledIOP, plonk.WithRangecheck(16, 6, false))
+			}
 		}, dummy.Compile)
 
 		proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
@@ -133,6 +153,11 @@ func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2
 
 			mod.Assign(run)
 
+			if tc.Tamper != nil {
+				tc.Tamper(run, mod)
+				return
+			}
+
 			if checkPairingModule {
 				modCt.CheckAssignment(run,
 					"ECPAIR_IS_ACTIVE",
@@ -166,7 +191,14 @@ func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2
 			}
 		})
 
-		if err := wizard.Verify(cmp, proof); err != nil {
+		err := wizard.Verify(cmp, proof)
+		if tc.Tamper != nil {
+			if err == nil {
+				t.Fatal("the verification passed with a tampered assignment")
+			}
+			return
+		}
+		if err != nil {
 			t.Fatal("proof failed", err)
 		}
 
@@ -176,7 +208,7 @@ func testModule(t *testing.T, tc pairingDataTestCase, withPairingCircuit, withG2
 
 func TestPairingData(t *testing.T) {
 	for _, tc := range pairingDataTestCases {
-		testModule(t, tc, false, false, true, false)
+		testModule(t, tc, false, false, false, true, false)
 	}
 }
 
@@ -202,6 +234,84 @@ var membershipTestCases = []pairingDataTestCase{
 
 func TestMembership(t *testing.T) {
 	for _, tc := range membershipTestCases {
-		testModule(t, tc, false, false, false, true)
+		testModule(t, tc, false, false, false, false, true)
+	}
+}
+
+var malformedInputsTestCases = []pairingDataTestCase{
+	{
+		// a call with a G1 point out of the curve, a call with a G2 point out
+		// of the subgroup and a call with a non-reduced coordinate.
+		InputFName:    "testdata/ecpair_malformed_input.csv",
+		NbMillerLoops: 1,
+		NbFinalExps:   1,
+		NbCurveChecks: 5,
+	},
+	{
+		// test case with bigger limits than inputs. Tests that input fillers work correctly.
+		InputFName:    "testdata/ecpair_malformed_input.csv",
+		NbMillerLoops: 1,
+		NbFinalExps:   1,
+		NbCurveChecks: 8,
+	},
+	{
+		// empty input to test edge case and fillers
+		InputFName:    "testdata/ecpair_empty.csv",
+		NbMillerLoops: 1,
+		NbFinalExps:   1,
+		NbCurveChecks: 2,
+	},
+}
+
+func TestMalformedInputs(t *testing.T) {
+	for _, tc := range malformedInputsTestCases {
+		testModule(t, tc, false, false, false, false, false)
+	}
+}
+
+func TestMalformedInputsDroppedComputedRow(t *testing.T) {
+	tc := malformedInputsTestCases[0]
+	tc.Tamper = dropLastCurveCheck
+	testModule(t, tc, false, false, false, false, false)
+}
+
+// dropLastCurveCheck erases the computed row of the last pair from the honest
+// assignment, so that the validity of the last failing call is never checked,
+// and reassigns the columns derived from the erased ones.
+func dropLastCurveCheck(run *wizard.ProverRuntime, mod *ECPair) {
+
+	var (
+		cc         = mod.UnalignedCurveCheckData
+		isComputed = cc.IsComputed.GetColAssignment(run).IntoRegVecSaveAlloc()
+		last       = -1
+	)
+
+	for i := range isComputed {
+		if isComputed[i].IsOne() {
+			last = i
+		}
+	}
+	if last < 0 {
+		utils.Panic("no computed row to drop")
+	}
+
+	for _, col := range []ifaces.Column{
+		cc.IsActive, cc.IsComputed, cc.Limb, cc.InstanceID, cc.PairID,
+		cc.TotalPairs, cc.Index, cc.AccIsValid,
+	} {
+		v := append([]field.Element{}, col.GetColAssignment(run).IntoRegVecSaveAlloc()...)
+		v[last].SetZero()
+		run.Columns.Update(col.GetColID(), smartvectors.NewRegular(v))
+	}
+
+	// The columns of [dedicated.IsZero] are named IS_ZERO_<id>_RES and
+	// IS_ZERO_<id>_INVERSE_OR_ZERO, they are deleted and recomputed.
+	for _, col := range []ifaces.Column{cc.IsLastIndex, cc.IsSameInstance, cc.IsLastPair} {
+		id := string(col.GetColID())
+		run.Columns.Del(col.GetColID())
+		run.Columns.Del(ifaces.ColID(strings.TrimSuffix(id, "_RES") + "_INVERSE_OR_ZERO"))
 	}
+	cc.CptIsLastIndex.Run(run)
+	cc.CptIsSameInstance.Run(run)
+	cc.CptIsLastPair.Run(run)
 }
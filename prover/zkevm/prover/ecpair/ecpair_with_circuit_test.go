@@ -8,12 +8,18 @@ import (
 
 func TestPairingDataCircuit(t *testing.T) {
 	for _, tc := range pairingDataTestCases {
-		testModule(t, tc, true, false, true, false)
+		testModule(t, tc, true, false, false, true, false)
 	}
 }
 
 func TestMembershipCircuit(t *testing.T) {
 	for _, tc := range membershipTestCases {
-		testModule(t, tc, false, true, false, true)
+		testModule(t, tc, false, true, false, false, true)
+	}
+}
+
+func TestCurveCheckCircuit(t *testing.T) {
+	for _, tc := range malformedInputsTestCases {
+		testModule(t, tc, false, false, true, false, false)
 	}
 }
@@ -21,6 +21,12 @@ type Limits struct {
 	NbG2MembershipInputInstances int
 	// Number of G2 subgroup membership circuits
 	NbG2MembershipCircuits int
+
+	// Number of inputs per curve check circuits. An input is a pair of a
+	// failing call.
+	NbCurveCheckInputInstances int
+	// Number of curve check circuits
+	NbCurveCheckCircuits int
 }
 
 func (l *Limits) nbMillerLoops() int {
@@ -35,6 +41,10 @@ func (l *Limits) nbG2MembershipChecks() int {
 	return l.NbG2MembershipInputInstances * l.NbG2MembershipCircuits
 }
 
+func (l *Limits) nbCurveChecks() int {
+	return l.NbCurveCheckInputInstances * l.NbCurveCheckCircuits
+}
+
 func (l *Limits) sizeMillerLoopPart() int {
 	return l.nbMillerLoops() * (nbG1Limbs + nbG2Limbs + 2*nbGtLimbs)
 }
@@ -50,3 +60,10 @@ func (l *Limits) sizeG2MembershipPart() int {
 func (l *Limits) sizeECPair() int {
 	return utils.NextPowerOfTwo(l.sizeMillerLoopPart() + l.sizeFinalExpPart() + l.sizeG2MembershipPart())
 }
+
+// sizeCurveCheckData returns the size of the columns of the
+// [UnalignedCurveCheckData]. One row is left for padding so that the first
+// row of the data is always detected as the start of a call.
+func (l *Limits) sizeCurveCheckData() int {
+	return utils.NextPowerOfTwo(l.nbCurveChecks()*(nbG1Limbs+nbG2Limbs+1) + 1)
+}
@@ -1,6 +1,8 @@
 package ecpair
 
 import (
+	"math/big"
+
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
@@ -221,3 +223,43 @@ func inputFillerG2Membership(circuitInstance, inputIndex int) field.Element {
 	}
 	return res
 }
+
+func inputFillerCurveCheck(circuitInstance, inputIndex int) field.Element {
+	// p = 0 and q = 0 are valid inputs of the precompile
+	if inputIndex%(nbG1Limbs+nbG2Limbs+1) == nbG1Limbs+nbG2Limbs {
+		return field.One()
+	}
+	return field.Zero()
+}
+
+// isPairValid returns true if the coordinates are reduced, the G1 point is on
+// the curve and the G2 point is in the subgroup. The point at infinity is
+// encoded as (0, 0) and is valid.
+func isPairValid(inG1 [nbG1Limbs]field.Element, inG2 [nbG2Limbs]field.Element) bool {
+	for i := 0; i < nbG1Limbs; i += 2 {
+		if !isReducedCoordinate(inG1[i], inG1[i+1]) {
+			return false
+		}
+	}
+	for i := 0; i < nbG2Limbs; i += 2 {
+		if !isReducedCoordinate(inG2[i], inG2[i+1]) {
+			return false
+		}
+	}
+
+	var (
+		p = convG1WizardToGnark(inG1)
+		q = convG2WizardToGnark(inG2)
+	)
+	return p.IsOnCurve() && q.IsOnCurve() && q.IsInSubGroup()
+}
+
+// isReducedCoordinate returns true if the coordinate given by its 128 bits
+// limbs is smaller than the modulus of the base field.
+func isReducedCoordinate(hi, lo field.Element) bool {
+	var x, l big.Int
+	hi.BigInt(&x)
+	lo.BigInt(&l)
+	x.Lsh(&x, 128).Add(&x, &l)
+	return x.Cmp(fp.Modulus()) < 0
+}
@@ -79,6 +79,11 @@ func (gdm *GenDataModule) ScanStreams(run *wizard.ProverRuntime) [][]byte {
 		buffer.Write(currLimb)
 	}
 
-	streams = append(streams, buffer.Bytes())
+	// Flush the last stream, if any. The hash numbers start at 1, so a zero
+	// one means that the module has no active row. An empty buffer is not
+	// enough to tell, the last stream may be empty.
+	if !currHashNum.IsZero() {
+		streams = append(streams, buffer.Bytes())
+	}
 	return streams
 }
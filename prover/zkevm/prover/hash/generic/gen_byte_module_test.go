@@ -42,6 +42,7 @@ func TestScanByteStream(t *testing.T) {
 		recoveredStreams = gdm.ScanStreams(run)
 	})
 
+	assert.Len(t, recoveredStreams, len(streams))
 	for i := range streams {
 		assert.Equalf(t,
 			utils.HexEncodeToString(streams[i]),
@@ -49,6 +50,15 @@ func TestScanByteStream(t *testing.T) {
 			"position %v", i,
 		)
 	}
+
+	// A module without any active row has no stream
+	_ = wizard.Prove(comp, func(run *wizard.ProverRuntime) {
+
+		assignGdbFromStream(run, gdm, nil)
+		recoveredStreams = gdm.ScanStreams(run)
+	})
+
+	assert.Empty(t, recoveredStreams)
 }
 
 func assignGdbFromStream(run *wizard.ProverRuntime, gdm *GenDataModule, stream [][]byte) {
@@ -189,3 +189,114 @@ func GenerateAndAssignGenInfoModule(
 	isHashHiCol.PadAndAssign(run)
 	isHashLoCol.PadAndAssign(run)
 }
+
+// GenerateAndAssignGenDataModuleFromStreams assigns gdm so that it encodes the
+// given streams, in order. Every stream is split into limbs of random sizes
+// (1 to 16 bytes) and rows with TO_HASH = 0 are randomly interleaved within
+// the streams. The streams must be non-empty. The function returns the number
+// of rows used, which must not exceed the size of the module.
+func GenerateAndAssignGenDataModuleFromStreams(run *wizard.ProverRuntime, gdm *generic.GenDataModule,
+	streams [][]byte, rng *rand.Rand) int {
+
+	var (
+		limbCol    = common.NewVectorBuilder(gdm.Limb)
+		nByteCol   = common.NewVectorBuilder(gdm.NBytes)
+		hashNumCol = common.NewVectorBuilder(gdm.HashNum)
+		indexCol   = common.NewVectorBuilder(gdm.Index)
+		toHashCol  = common.NewVectorBuilder(gdm.ToHash)
+		// alignedLimbs splits the stream into 16-byte limbs (but the last one)
+		// instead of random sizes.
+		alignedLimbs = rng.Intn(4) == 0
+	)
+
+	for hashNum, stream := range streams {
+
+		if len(stream) == 0 {
+			panic("the streams must be non-empty")
+		}
+
+		for index := 0; len(stream) > 0; index++ {
+
+			nBytes := min(16, len(stream))
+			if !alignedLimbs {
+				nBytes = min(1+rng.Intn(16), len(stream))
+			}
+
+			limbCol.PushField(leftAlignedLimb(stream[:nBytes]))
+			nByteCol.PushInt(nBytes)
+			hashNumCol.PushInt(hashNum + 1)
+			indexCol.PushInt(index)
+			toHashCol.PushInt(1)
+			stream = stream[nBytes:]
+
+			// Interleaves a row that is not part of the stream
+			if rng.Intn(8) == 0 {
+				nBytes := 1 + rng.Intn(16)
+				limbCol.PushField(randLimbs(rng, nBytes))
+				nByteCol.PushInt(nBytes)
+				hashNumCol.PushInt(hashNum + 1)
+				indexCol.PushInt(index)
+				toHashCol.PushInt(0)
+			}
+		}
+	}
+
+	numRows := limbCol.Height()
+
+	limbCol.PadAndAssign(run)
+	nByteCol.PadAndAssign(run)
+	hashNumCol.PadAndAssign(run)
+	indexCol.PadAndAssign(run)
+	toHashCol.PadAndAssign(run)
+
+	return numRows
+}
+
+// AssignGenInfoModuleFromDigests assigns gim with the given digests, in order.
+// The high and the low parts of a digest are on the same row and rows without
+// any digest are randomly interleaved. The function returns the number of rows
+// used, which must not exceed the size of the module.
+func AssignGenInfoModuleFromDigests(run *wizard.ProverRuntime, gim *generic.GenInfoModule,
+	digests [][32]byte, rng *rand.Rand) int {
+
+	var (
+		hashHi   = common.NewVectorBuilder(gim.HashHi)
+		hashLo   = common.NewVectorBuilder(gim.HashLo)
+		isHashHi = common.NewVectorBuilder(gim.IsHashHi)
+		isHashLo = common.NewVectorBuilder(gim.IsHashLo)
+	)
+
+	for _, digest := range digests {
+
+		if rng.Intn(8) == 0 {
+			hashHi.PushInt(0)
+			hashLo.PushInt(0)
+			isHashHi.PushInt(0)
+			isHashLo.PushInt(0)
+		}
+
+		hashHi.PushHi(digest)
+		hashLo.PushLo(digest)
+		isHashHi.PushInt(1)
+		isHashLo.PushInt(1)
+	}
+
+	numRows := hashHi.Height()
+
+	hashHi.PadAndAssign(run)
+	hashLo.PadAndAssign(run)
+	isHashHi.PadAndAssign(run)
+	isHashLo.PadAndAssign(run)
+
+	return numRows
+}
+
+// leftAlignedLimb returns the limb encoding b (at most 16 bytes) as expected
+// by [generic.GenDataModule.ScanStreams].
+func leftAlignedLimb(b []byte) field.Element {
+	var buf [16]byte
+	copy(buf[:], b)
+	var res field.Element
+	res.SetBytes(buf[:])
+	return res
+}
@@ -12,8 +12,9 @@ import (
 // is properly done. The returned lookup table stores all the value in the
 // range 1..size and is padded with ones to the next power of two.
 //
-// The function also caches the tables so it can be safely called multiple time
-// , it won't recreate the same lookup table several times.
+// The table is registered via [wizard.CompiledIOP.InsertPrecomputedTable], so
+// the function can be safely called multiple times: it won't recreate the
+// same lookup table several times.
 func getLookupForSize(comp *wizard.CompiledIOP, size int) ifaces.Column {
 
 	var (
@@ -21,15 +22,11 @@ func getLookupForSize(comp *wizard.CompiledIOP, size int) ifaces.Column {
 		name = ifaces.ColIDf("LOOKUP_TABLE_RANGE_1_%v", size)
 	)
 
-	if comp.Columns.Exists(name) {
-		return comp.Columns.GetHandle(name)
-	}
-
 	for i := range res {
 		res[i].SetInt64(int64(i) + 1)
 	}
 
-	return comp.InsertPrecomputed(
+	return comp.InsertPrecomputedTable(
 		name,
 		smartvectors.RightPadded(res, field.One(), utils.NextPowerOfTwo(size)),
 	)
@@ -0,0 +1,234 @@
+// The adapter package provides the gadgets re-slicing the keccak digests
+// exposed by the providers, e.g. to truncate them into addresses or to reverse
+// their byte order. The consumer modules use them instead of re-deriving the
+// limbs of the digests with their own constraints.
+package adapter
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/byte32cmp"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+const (
+	// halfDigestSize is the number of bytes held by each column of a [Digest]
+	halfDigestSize = 16
+	// digestSize is the number of bytes of a keccak digest
+	digestSize = 2 * halfDigestSize
+)
+
+// Digest is a keccak digest as exposed in the [generic.GenInfoModule] of the
+// providers. Hi holds the 16 leading bytes of the digest and Lo the 16
+// trailing ones, both in big-endian order.
+type Digest struct {
+	Hi, Lo ifaces.Column
+}
+
+// Adapter is a gadget re-slicing a [Digest]. Each column of the output holds
+// a sequence of bytes of the input, in big-endian order, see [Truncate] and
+// [ReverseBytes]. The output columns are constrained by decomposing the input
+// columns in bytes, which are range-checked; the input columns are only
+// decomposed if they are needed. An output column that is identical to an
+// input column is the input column itself.
+type Adapter struct {
+	Input  Digest
+	Output Digest
+
+	// hiBytes and loBytes list the positions in the input digest of the bytes
+	// of Output.Hi and Output.Lo.
+	hiBytes, loBytes []int
+	// decompositions holds the byte decompositions of Input.Hi and Input.Lo,
+	// nil if they are not needed.
+	decompositions [2]*byte32cmp.LimbColumns
+	// computeDecompositions assigns decompositions
+	computeDecompositions []wizard.ProverAction
+}
+
+// Truncate returns an [Adapter] whose output holds the nbBytes trailing bytes
+// of the digest: Output.Lo holds the min(nbBytes, 16) trailing bytes and
+// Output.Hi the remaining ones, or is nil if there are none. This is the
+// layout of the addresses in the arithmetization: an address is the 20-byte
+// truncation of a digest with its 4 leading bytes in the Hi column.
+func Truncate(comp *wizard.CompiledIOP, name string, d Digest, nbBytes int) *Adapter {
+
+	if nbBytes <= 0 || nbBytes > digestSize {
+		utils.Panic("cannot truncate a digest to %v bytes", nbBytes)
+	}
+
+	var (
+		loBytes = utils.RangeSlice[int](min(nbBytes, halfDigestSize), digestSize-min(nbBytes, halfDigestSize))
+		hiBytes []int
+	)
+
+	if nbBytes > halfDigestSize {
+		hiBytes = utils.RangeSlice[int](nbBytes-halfDigestSize, digestSize-nbBytes)
+	}
+
+	return newAdapter(comp, name, d, hiBytes, loBytes)
+}
+
+// ReverseBytes returns an [Adapter] whose output is the digest with its bytes
+// in reverse order, i.e. the digest read as a little-endian number.
+func ReverseBytes(comp *wizard.CompiledIOP, name string, d Digest) *Adapter {
+
+	var (
+		hiBytes = make([]int, halfDigestSize)
+		loBytes = make([]int, halfDigestSize)
+	)
+
+	for i := 0; i < halfDigestSize; i++ {
+		hiBytes[i] = digestSize - 1 - i
+		loBytes[i] = halfDigestSize - 1 - i
+	}
+
+	return newAdapter(comp, name, d, hiBytes, loBytes)
+}
+
+// newAdapter declares the output columns of the adapter and the constraints
+// binding them to the bytes of the input at the given positions.
+func newAdapter(comp *wizard.CompiledIOP, name string, d Digest, hiBytes, loBytes []int) *Adapter {
+
+	a := &Adapter{
+		Input:   d,
+		hiBytes: hiBytes,
+		loBytes: loBytes,
+	}
+
+	a.Output.Hi = a.defineOutput(comp, name+"_HI", hiBytes)
+	a.Output.Lo = a.defineOutput(comp, name+"_LO", loBytes)
+	return a
+}
+
+// defineOutput returns the output column holding the bytes at the given
+// positions and constrains it. It returns nil if there are no bytes.
+func (a *Adapter) defineOutput(comp *wizard.CompiledIOP, name string, positions []int) ifaces.Column {
+
+	if len(positions) == 0 {
+		return nil
+	}
+
+	if col, ok := a.asInputColumn(positions); ok {
+		return col
+	}
+
+	var (
+		round = max(a.Input.Hi.Round(), a.Input.Lo.Round())
+		size  = ifaces.AssertSameLength(a.Input.Hi, a.Input.Lo)
+		res   = comp.InsertCommit(round, ifaces.ColIDf("KECCAK_ADAPTER_%v", name), size)
+		acc   = sym.NewVariable(a.inputByte(comp, positions[0]))
+	)
+
+	// the bytes are recombined in big-endian order
+	for _, pos := range positions[1:] {
+		acc = sym.Add(sym.Mul(acc, 256), a.inputByte(comp, pos))
+	}
+
+	comp.InsertGlobal(round, ifaces.QueryIDf("KECCAK_ADAPTER_%v", name), sym.Sub(res, acc))
+	return res
+}
+
+// asInputColumn returns the input column holding exactly the bytes at the
+// given positions, if any.
+func (a *Adapter) asInputColumn(positions []int) (ifaces.Column, bool) {
+
+	if len(positions) != halfDigestSize {
+		return nil, false
+	}
+
+	for half, col := range []ifaces.Column{a.Input.Hi, a.Input.Lo} {
+		isCol := true
+		for i, pos := range positions {
+			if pos != half*halfDigestSize+i {
+				isCol = false
+				break
+			}
+		}
+		if isCol {
+			return col, true
+		}
+	}
+
+	return nil, false
+}
+
+// inputByte returns the column holding the byte of the input at position pos,
+// decomposing the corresponding input column if it has not been yet.
+func (a *Adapter) inputByte(comp *wizard.CompiledIOP, pos int) ifaces.Column {
+
+	half := pos / halfDigestSize
+
+	if a.decompositions[half] == nil {
+		col := a.Input.Hi
+		if half == 1 {
+			col = a.Input.Lo
+		}
+		limbs, pa := byte32cmp.Decompose(comp, col, halfDigestSize, 8)
+		a.decompositions[half] = &limbs
+		a.computeDecompositions = append(a.computeDecompositions, pa)
+	}
+
+	// the limbs are in little-endian order
+	return a.decompositions[half].Limbs[halfDigestSize-1-pos%halfDigestSize]
+}
+
+// Run assigns the columns of the adapter. The input columns must have been
+// assigned beforehand.
+func (a *Adapter) Run(run *wizard.ProverRuntime) {
+
+	for _, pa := range a.computeDecompositions {
+		pa.Run(run)
+	}
+
+	var (
+		hi = a.Input.Hi.GetColAssignment(run).IntoRegVecSaveAlloc()
+		lo = a.Input.Lo.GetColAssignment(run).IntoRegVecSaveAlloc()
+	)
+
+	for _, out := range []struct {
+		col       ifaces.Column
+		positions []int
+	}{
+		{a.Output.Hi, a.hiBytes},
+		{a.Output.Lo, a.loBytes},
+	} {
+
+		if out.col == nil || out.col == a.Input.Hi || out.col == a.Input.Lo {
+			continue
+		}
+
+		res := make([]field.Element, len(hi))
+		for row := range res {
+			digest := digestBytes(hi[row], lo[row])
+			buf := make([]byte, len(out.positions))
+			for i, pos := range out.positions {
+				buf[i] = digest[pos]
+			}
+			res[row].SetBytes(buf)
+		}
+
+		run.AssignColumn(out.col.GetColID(), smartvectors.NewRegular(res))
+	}
+}
+
+// digestBytes returns the bytes of a digest from the values of its Hi and Lo
+// columns. It panics if one of them does not fit on 16 bytes.
+func digestBytes(hi, lo field.Element) [digestSize]byte {
+
+	var res [digestSize]byte
+
+	for i, half := range []field.Element{hi, lo} {
+		b := half.Bytes()
+		for _, x := range b[:len(b)-halfDigestSize] {
+			if x != 0 {
+				utils.Panic("the half digest %v does not fit on %v bytes", half.String(), halfDigestSize)
+			}
+		}
+		copy(res[i*halfDigestSize:], b[len(b)-halfDigestSize:])
+	}
+
+	return res
+}
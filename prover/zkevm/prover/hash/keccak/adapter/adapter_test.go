@@ -0,0 +1,120 @@
+package adapter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/keccak"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapter(t *testing.T) {
+
+	const size = 4
+
+	// the digests of the first integers
+	digests := make([][digestSize]byte, size)
+	for i := range digests {
+		digests[i] = keccak.Hash([]byte{byte(i)})
+	}
+
+	testCases := []struct {
+		name string
+		// define returns the adapter under test
+		define func(comp *wizard.CompiledIOP, d Digest) *Adapter
+		// expected returns the expected output bytes for a digest, split in
+		// Hi and Lo. A nil Hi means that the output has no Hi column.
+		expected func(digest [digestSize]byte) (hi, lo []byte)
+		// isLoInput is set if the output Lo column is the input Lo column
+		isLoInput bool
+	}{
+		{
+			name: "address",
+			define: func(comp *wizard.CompiledIOP, d Digest) *Adapter {
+				return Truncate(comp, "ADDRESS", d, 20)
+			},
+			expected: func(digest [digestSize]byte) (hi, lo []byte) {
+				return digest[12:16], digest[16:]
+			},
+			isLoInput: true,
+		},
+		{
+			name: "short-truncation",
+			define: func(comp *wizard.CompiledIOP, d Digest) *Adapter {
+				return Truncate(comp, "SHORT", d, 5)
+			},
+			expected: func(digest [digestSize]byte) (hi, lo []byte) {
+				return nil, digest[27:]
+			},
+		},
+		{
+			name: "little-endian",
+			define: func(comp *wizard.CompiledIOP, d Digest) *Adapter {
+				return ReverseBytes(comp, "LITTLE_ENDIAN", d)
+			},
+			expected: func(digest [digestSize]byte) (hi, lo []byte) {
+				reversed := digest
+				slices.Reverse(reversed[:])
+				return reversed[:16], reversed[16:]
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			var (
+				a                  *Adapter
+				outputHi, outputLo []field.Element
+			)
+
+			define := func(b *wizard.Builder) {
+				d := Digest{
+					Hi: b.RegisterCommit("HASH_HI", size),
+					Lo: b.RegisterCommit("HASH_LO", size),
+				}
+				a = tc.define(b.CompiledIOP, d)
+			}
+
+			prove := func(run *wizard.ProverRuntime) {
+				hi := make([]field.Element, size)
+				lo := make([]field.Element, size)
+				for i := range digests {
+					hi[i].SetBytes(digests[i][:halfDigestSize])
+					lo[i].SetBytes(digests[i][halfDigestSize:])
+				}
+				run.AssignColumn("HASH_HI", smartvectors.NewRegular(hi))
+				run.AssignColumn("HASH_LO", smartvectors.NewRegular(lo))
+				a.Run(run)
+
+				if a.Output.Hi != nil {
+					outputHi = a.Output.Hi.GetColAssignment(run).IntoRegVecSaveAlloc()
+				}
+				outputLo = a.Output.Lo.GetColAssignment(run).IntoRegVecSaveAlloc()
+			}
+
+			comp := wizard.Compile(define, dummy.Compile)
+			proof := wizard.Prove(comp, prove)
+			require.NoError(t, wizard.Verify(comp, proof))
+
+			// checks the values of the output columns
+			assert.Equal(t, tc.isLoInput, a.Output.Lo.GetColID() == ifaces.ColID("HASH_LO"))
+
+			for i := range digests {
+				expectedHi, expectedLo := tc.expected(digests[i])
+				if expectedHi == nil {
+					assert.Nil(t, a.Output.Hi)
+				} else {
+					assert.Equal(t, new(field.Element).SetBytes(expectedHi).String(), outputHi[i].String())
+				}
+				assert.Equal(t, new(field.Element).SetBytes(expectedLo).String(), outputLo[i].String())
+			}
+		})
+	}
+}
@@ -30,14 +30,14 @@ func NewLookupTables(comp *wizard.CompiledIOP) lookUpTables {
 
 	// table for base conversion (used for converting blocks to what keccakf expect)
 	colUint16, colBaseA, colBaseB := baseConversionKeccakBaseX()
-	res.colUint16 = comp.InsertPrecomputed(ifaces.ColIDf("LOOKUP_Uint16"), colUint16)
-	res.colBaseA = comp.InsertPrecomputed(ifaces.ColIDf("LOOKUP_BaseA"), colBaseA)
-	res.colBaseB = comp.InsertPrecomputed(ifaces.ColIDf("LOOKUP_BaseB"), colBaseB)
+	res.colUint16 = comp.InsertPrecomputedTable(ifaces.ColIDf("LOOKUP_Uint16"), colUint16)
+	res.colBaseA = comp.InsertPrecomputedTable(ifaces.ColIDf("LOOKUP_BaseA"), colBaseA)
+	res.colBaseB = comp.InsertPrecomputedTable(ifaces.ColIDf("LOOKUP_BaseB"), colBaseB)
 
 	// table for base conversion (from BaseBDirty to uint4)
 	colUint4, colBaseBDirty := baseConversionKeccakBaseBDirtyToUint4()
-	res.ColUint4 = comp.InsertPrecomputed(ifaces.ColIDf("LOOKUP_Uint4"), colUint4)
-	res.ColBaseBDirty = comp.InsertPrecomputed(ifaces.ColIDf("LOOKUP_BaseBDirty"), colBaseBDirty)
+	res.ColUint4 = comp.InsertPrecomputedTable(ifaces.ColIDf("LOOKUP_Uint4"), colUint4)
+	res.ColBaseBDirty = comp.InsertPrecomputedTable(ifaces.ColIDf("LOOKUP_BaseBDirty"), colBaseBDirty)
 	return res
 
 }
@@ -66,12 +66,11 @@ func baseConversionKeccakBaseBDirtyToUint4() (
 		u = append(u, x)
 		v = append(v, field.NewElement(uint4))
 	}
+	// The tables are zero-padded, (0, 0) is a valid entry. This makes the
+	// baseBDirty column identical to the one of the keccakf module so that
+	// the two modules share it.
 	n := utils.NextPowerOfTwo(keccakf.BaseBPow4)
-	for i := keccakf.BaseBPow4; i < n; i++ {
-		u = append(u, u[len(u)-1])
-		v = append(v, v[len(v)-1])
-	}
-	return smartvectors.NewRegular(v), smartvectors.NewRegular(u)
+	return smartvectors.RightZeroPadded(v, n), smartvectors.RightZeroPadded(u, n)
 }
 
 func BaseBToUint4(x field.Element, base int) (res uint64) {
@@ -0,0 +1,315 @@
+package keccak
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/keccak"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard/conformance"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic/testdata"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// fuzzMaxNumKeccakF bounds the number of permutations of a fuzzing case
+	fuzzMaxNumKeccakF = 16
+	// fuzzSizeData is the size of the data module of a provider. It is large
+	// enough to hold fuzzMaxNumKeccakF blocks split in 1-byte limbs.
+	fuzzSizeData = 8192
+	// fuzzSizeInfo is the size of the info module of a provider
+	fuzzSizeInfo = 64
+	// fuzzNumProviders is the number of providers of the zkEVM harness
+	fuzzNumProviders = 3
+)
+
+// paddingEdgeLengths lists the lengths of the streams on the boundaries of the
+// padding of keccak: a single padding byte, a full padding block and the limbs
+// boundaries.
+var paddingEdgeLengths = []int{
+	1, 15, 16, 17,
+	keccak.Rate - 1, keccak.Rate, keccak.Rate + 1,
+	2*keccak.Rate - 1, 2 * keccak.Rate, 2*keccak.Rate + 1,
+}
+
+// keccakHarness is a keccak module checked with the conformance kit. The
+// streams of the witness are generated by the Assign function of the module
+// following the scenario, unless they are fixed by the test.
+type keccakHarness struct {
+	kit          *conformance.Kit
+	numProviders int
+	// fixed, if not nil, replaces the streams generated for the scenario
+	fixed   [][][]byte
+	streams [][][]byte // per provider
+}
+
+// newSingleProviderHarness compiles a [KeccakSingleProvider]. The output of
+// the module is checked against the reference.
+func newSingleProviderHarness(t testing.TB) *keccakHarness {
+
+	var (
+		h   = &keccakHarness{numProviders: 1}
+		mod *KeccakSingleProvider
+		gdm generic.GenDataModule
+		gim generic.GenInfoModule
+	)
+
+	define := func(b *wizard.Builder) {
+		gdm = testdata.CreateGenDataModule(b.CompiledIOP, "FUZZ", fuzzSizeData)
+		gim = testdata.CreateGenInfoModule(b.CompiledIOP, "FUZZ", fuzzSizeInfo)
+		mod = NewKeccakSingleProvider(b.CompiledIOP, KeccakSingleProviderInput{
+			MaxNumKeccakF: fuzzMaxNumKeccakF,
+			Provider:      generic.GenericByteModule{Data: gdm, Info: gim},
+		})
+	}
+
+	assign := func(run *wizard.ProverRuntime, scenario conformance.Scenario, rng *rand.Rand) {
+		streams := h.generate(scenario, rng)[0]
+		testdata.GenerateAndAssignGenDataModuleFromStreams(run, &gdm, streams, rng)
+		testdata.AssignGenInfoModuleFromDigests(run, &gim, referenceDigests(streams), rng)
+		mod.Run(run)
+	}
+
+	reference := func(run *wizard.ProverRuntime) error {
+
+		var (
+			streams  = h.streams[0]
+			hashHi   = mod.HashHi.GetColAssignment(run).IntoRegVecSaveAlloc()
+			hashLo   = mod.HashLo.GetColAssignment(run).IntoRegVecSaveAlloc()
+			isActive = mod.IsActive.GetColAssignment(run).IntoRegVecSaveAlloc()
+			digests  = referenceDigests(streams)
+			found    = 0
+		)
+
+		for row := range isActive {
+			if isActive[row].IsZero() {
+				continue
+			}
+			if found < len(digests) {
+				hi, lo := splitDigest(digests[found])
+				if hashHi[row] != hi || hashLo[row] != lo {
+					return fmt.Errorf("hash #%v (stream of %v bytes): wrong digest, lengths=%v", found, len(streams[found]), streamLengths(h.streams))
+				}
+			}
+			found++
+		}
+
+		if found != len(digests) {
+			return fmt.Errorf("the module returned %v hashes, expected %v, lengths=%v", found, len(digests), streamLengths(h.streams))
+		}
+		return nil
+	}
+
+	h.kit = conformance.New(t, conformance.Module{Define: define, Assign: assign, Reference: reference})
+	return h
+}
+
+// newZkEVMHarness compiles a [KeccakZkEVM] over fuzzNumProviders providers.
+// The digests expected by the providers are the reference ones, hence the
+// proof verifies only if the module agrees with the reference.
+func newZkEVMHarness(t testing.TB) *keccakHarness {
+
+	var (
+		h    = &keccakHarness{numProviders: fuzzNumProviders}
+		mod  *KeccakZkEVM
+		gdms = make([]generic.GenDataModule, fuzzNumProviders)
+		gims = make([]generic.GenInfoModule, fuzzNumProviders)
+	)
+
+	define := func(b *wizard.Builder) {
+		providers := make([]generic.GenericByteModule, fuzzNumProviders)
+		for i := range providers {
+			name := fmt.Sprintf("FUZZ_%v", i)
+			gdms[i] = testdata.CreateGenDataModule(b.CompiledIOP, name, fuzzSizeData)
+			gims[i] = testdata.CreateGenInfoModule(b.CompiledIOP, name, fuzzSizeInfo)
+			providers[i] = generic.GenericByteModule{Data: gdms[i], Info: gims[i]}
+		}
+		mod = newKeccakZkEvm(b.CompiledIOP, Settings{MaxNumKeccakf: fuzzMaxNumKeccakF}, providers)
+	}
+
+	assign := func(run *wizard.ProverRuntime, scenario conformance.Scenario, rng *rand.Rand) {
+		streams := h.generate(scenario, rng)
+		for i := range gdms {
+			testdata.GenerateAndAssignGenDataModuleFromStreams(run, &gdms[i], streams[i], rng)
+			testdata.AssignGenInfoModuleFromDigests(run, &gims[i], referenceDigests(streams[i]), rng)
+		}
+		mod.Run(run)
+	}
+
+	h.kit = conformance.New(t, conformance.Module{Define: define, Assign: assign})
+	return h
+}
+
+// generate sets and returns the streams of the witness for the scenario
+func (h *keccakHarness) generate(scenario conformance.Scenario, rng *rand.Rand) [][][]byte {
+
+	switch {
+	case h.fixed != nil:
+		h.streams = h.fixed
+	case scenario == conformance.ZeroActivity:
+		h.streams = make([][][]byte, h.numProviders)
+	case scenario == conformance.MaxCapacity:
+		h.streams = maxCapacityStreams(rng, h.numProviders)
+	default:
+		h.streams = randStreams(rng, h.numProviders)
+	}
+
+	return h.streams
+}
+
+// check proves and verifies the harness for the given streams
+func (h *keccakHarness) check(t *testing.T, streams [][][]byte) {
+	h.fixed = streams
+	defer func() { h.fixed = nil }()
+	h.kit.Check(t, conformance.Fuzz, 0)
+}
+
+// maxCapacityStreams generates streams of a single block until the number of
+// permutations is exhausted, and distributes them among numProviders
+// providers.
+func maxCapacityStreams(rng *rand.Rand, numProviders int) [][][]byte {
+
+	res := make([][][]byte, numProviders)
+
+	for i := 0; i < fuzzMaxNumKeccakF; i++ {
+		stream := make([]byte, 1+rng.Intn(keccak.Rate-1))
+		rng.Read(stream)
+		provider := rng.Intn(numProviders)
+		res[provider] = append(res[provider], stream)
+	}
+
+	return res
+}
+
+// randStreams generates random streams and distributes them among numProviders
+// providers. The lengths of the streams are either on the edges of the
+// padding or random, up to three blocks. The streams are generated until the
+// number of permutations is exhausted.
+func randStreams(rng *rand.Rand, numProviders int) [][][]byte {
+
+	var (
+		res          = make([][][]byte, numProviders)
+		numKeccakF   = 0
+		numHashesMax = fuzzSizeInfo / 2
+		numHashes    = 0
+	)
+
+	for numHashes < numHashesMax {
+
+		length := 1 + rng.Intn(3*keccak.Rate)
+		if rng.Intn(2) == 0 {
+			length = paddingEdgeLengths[rng.Intn(len(paddingEdgeLengths))]
+		}
+
+		numKeccakF += length/keccak.Rate + 1
+		if numKeccakF > fuzzMaxNumKeccakF {
+			break
+		}
+
+		stream := make([]byte, length)
+		rng.Read(stream)
+
+		provider := rng.Intn(numProviders)
+		res[provider] = append(res[provider], stream)
+		numHashes++
+	}
+
+	return res
+}
+
+// referenceDigests hashes the streams with the keccak implementation of
+// go-ethereum.
+func referenceDigests(streams [][]byte) [][32]byte {
+	res := make([][32]byte, len(streams))
+	for i := range streams {
+		copy(res[i][:], crypto.Keccak256(streams[i]))
+	}
+	return res
+}
+
+func splitDigest(d [32]byte) (hi, lo field.Element) {
+	hi.SetBytes(d[:16])
+	lo.SetBytes(d[16:])
+	return hi, lo
+}
+
+func streamLengths(streams [][][]byte) [][]int {
+	res := make([][]int, len(streams))
+	for i := range streams {
+		for j := range streams[i] {
+			res[i] = append(res[i], len(streams[i][j]))
+		}
+	}
+	return res
+}
+
+// TestKeccakConformance runs the conformance kit over both modules
+func TestKeccakConformance(t *testing.T) {
+	t.Run("single-provider", newSingleProviderHarness(t).kit.Run)
+	t.Run("zkevm", newZkEVMHarness(t).kit.Run)
+}
+
+// TestKeccakPaddingEdges hashes one stream of every edge length, in a single
+// witness, so that every padding boundary is covered by the default test run.
+func TestKeccakPaddingEdges(t *testing.T) {
+
+	var (
+		h       = newSingleProviderHarness(t)
+		rng     = rand.New(rand.NewSource(0))
+		streams [][]byte
+	)
+
+	// The edge lengths do not fit in a single witness, the longest ones are
+	// proven separately.
+	var numKeccakF int
+	for _, length := range paddingEdgeLengths {
+		stream := make([]byte, length)
+		rng.Read(stream)
+
+		if numKeccakF+length/keccak.Rate+1 > fuzzMaxNumKeccakF {
+			h.check(t, [][][]byte{streams})
+			streams, numKeccakF = nil, 0
+		}
+
+		streams = append(streams, stream)
+		numKeccakF += length/keccak.Rate + 1
+	}
+
+	h.check(t, [][][]byte{streams})
+}
+
+// FuzzKeccakSingleProvider checks the module against the reference over
+// random witnesses. Only the seed corpus runs with the regular tests, large
+// campaigns are run with:
+//
+//	go test -run XXX -fuzz FuzzKeccakSingleProvider -fuzztime 1000x
+func FuzzKeccakSingleProvider(f *testing.F) {
+
+	h := newSingleProviderHarness(f)
+
+	for seed := int64(0); seed < 4; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		h.kit.Check(t, conformance.Fuzz, seed)
+	})
+}
+
+// FuzzKeccakZkEVM is as [FuzzKeccakSingleProvider] but with the streams
+// randomly distributed among several providers.
+func FuzzKeccakZkEVM(f *testing.F) {
+
+	h := newZkEVMHarness(f)
+
+	for seed := int64(0); seed < 4; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		h.kit.Check(t, conformance.Fuzz, seed)
+	})
+}
@@ -2,10 +2,10 @@ package keccak
 
 import (
 	"github.com/consensys/linea-monorepo/prover/crypto/keccak"
-	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak/base_conversion"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak/keccakf"
@@ -145,6 +145,7 @@ func AssignLaneInfo(run *wizard.ProverRuntime, l *LaneInfo, in [][]byte) {
 				block[len(in[i])-j*136] = 1 // dst
 				block[135] |= 0x80          // end marker
 			}
+			blockLanes := bitutil.BytesToLimbs(block[:], 8)
 			for k := 0; k < 17; k++ {
 				if k == 0 && j == 0 {
 					isFirstLaneOfNewHash.PushInt(1)
@@ -152,8 +153,7 @@ func AssignLaneInfo(run *wizard.ProverRuntime, l *LaneInfo, in [][]byte) {
 					isFirstLaneOfNewHash.PushInt(0)
 				}
 				isLaneActive.PushInt(1)
-				f := *new(field.Element).SetBytes(block[k*8 : k*8+8])
-				lanes.PushField(f)
+				lanes.PushField(blockLanes[k])
 			}
 		}
 
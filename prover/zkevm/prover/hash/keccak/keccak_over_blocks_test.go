@@ -83,3 +83,14 @@ func TestCustomizedKeccak(t *testing.T) {
 	proof := wizard.Prove(comp, prover)
 	assert.NoErrorf(t, wizard.Verify(comp, proof), "invalid proof")
 }
+
+func TestKeccakOverBlocksSharesTables(t *testing.T) {
+
+	definer, _ := MakeTestCaseCustomizedKeccak(t, nil)
+	comp := wizard.Compile(definer)
+
+	// The base conversion and the keccakf modules both look up in the
+	// baseB-dirty table. It must be committed only once.
+	assert.True(t, comp.Columns.Exists("LOOKUP_BaseBDirty"))
+	assert.False(t, comp.Columns.Exists("KECCAKF_BASE2_DIRTY_"))
+}
@@ -21,11 +21,22 @@ type KeccakZkEVM struct {
 	pa_keccak  wizard.ProverAction
 }
 
-func NewKeccakZkEVM(comp *wizard.CompiledIOP, settings Settings, providersFromEcdsa []generic.GenericByteModule) *KeccakZkEVM {
+// NewKeccakZkEVM declares the keccak module proving the hashes of the
+// arithmetization along with the ones of the providers. The capacity of the
+// module is the one of the settings, increased by the number of keccakf
+// permutations required by the providers.
+func NewKeccakZkEVM(comp *wizard.CompiledIOP, settings Settings, providers []Provider) *KeccakZkEVM {
+
+	var modules []generic.GenericByteModule
+	for _, p := range providers {
+		modules = append(modules, p.KeccakProviders()...)
+		settings.MaxNumKeccakf += p.NumKeccakF()
+	}
+
 	return newKeccakZkEvm(
 		comp,
 		settings, append(
-			providersFromEcdsa,
+			modules,
 			getShakiraArithmetization(comp),
 			getRlpAddArithmetization(comp),
 		),
@@ -298,7 +298,10 @@ func (io *InputOutput) assignHashOutPut(run *wizard.ProverRuntime, isBlockActive
 		}
 
 	}
-	isHashOutput.PushInt(1)
+	// The last active row ends the last hash, if the module is used at all
+	if isBlockActiveWit[0].IsOne() {
+		isHashOutput.PushInt(1)
+	}
 	isHashOutput.PadAndAssign(run)
 
 	// populate HashOutputSlicesBaseB
@@ -45,10 +45,10 @@ func newLookUpTables(comp *wizard.CompiledIOP, maxNumKeccakf int) lookUpTables {
 	baseBDirty, baseAClean := valBaseXToBaseY(BaseB, BaseA, 1)
 
 	// tables for bit representation conversions
-	l.BaseAClean = comp.InsertPrecomputed(deriveName("BASE1_CLEAN"), baseAClean)
-	l.BaseBClean = comp.InsertPrecomputed(deriveName("BASE2_CLEAN"), baseBClean)
-	l.BaseADirty = comp.InsertPrecomputed(deriveName("BASE1_DIRTY"), baseADirty)
-	l.BaseBDirty = comp.InsertPrecomputed(deriveName("BASE2_DIRTY"), baseBDirty)
+	l.BaseAClean = comp.InsertPrecomputedTable(deriveName("BASE1_CLEAN"), baseAClean)
+	l.BaseBClean = comp.InsertPrecomputedTable(deriveName("BASE2_CLEAN"), baseBClean)
+	l.BaseADirty = comp.InsertPrecomputedTable(deriveName("BASE1_DIRTY"), baseADirty)
+	l.BaseBDirty = comp.InsertPrecomputedTable(deriveName("BASE2_DIRTY"), baseBDirty)
 
 	// tables for the RC columns
 	l.RC = comp.InsertPrecomputed(deriveName("RC"), valRCBase2(maxNumKeccakf))
@@ -186,9 +186,7 @@ func (c *piChiIota) assign(
 	for x := 0; x < 5; x++ {
 		for y := 0; y < 5; y++ {
 			for k := 0; k < numSlice; k++ {
-				aPiOut[x][y] = aRho[x][y].GetColAssignment(run).
-					SubVector(0, effNumRows).
-					IntoRegVecSaveAlloc()
+				aPiOut[x][y] = activeRows(run, aRho[x][y], effNumRows)
 			}
 		}
 	}
@@ -196,14 +194,10 @@ func (c *piChiIota) assign(
 	// Fetch the assignment for the block of data
 	blockBaseBVal := [numLanesInBlock][]field.Element{}
 	for m := 0; m < numLanesInBlock; m++ {
-		blockBaseBVal[m] = blockBaseB[m].GetColAssignment(run).
-			SubVector(0, effNumRows).
-			IntoRegVecSaveAlloc()
+		blockBaseBVal[m] = activeRows(run, blockBaseB[m], effNumRows)
 	}
 
-	isBlockBaseBVal := isBlockBaseB.GetColAssignment(run).
-		SubVector(0, effNumRows).
-		IntoRegVecSaveAlloc()
+	isBlockBaseBVal := activeRows(run, isBlockBaseB, effNumRows)
 
 	// Then permute the columns to apply the effects of the Pi permutation.
 	aPiOut = pi(aPiOut)
@@ -314,3 +308,12 @@ func (c *piChiIota) assign(
 	}
 
 }
+
+// activeRows returns the first n rows of the assignment of col. There may be
+// no active row at all if the module is not used.
+func activeRows(run *wizard.ProverRuntime, col ifaces.Column, n int) []field.Element {
+	if n == 0 {
+		return []field.Element{}
+	}
+	return col.GetColAssignment(run).SubVector(0, n).IntoRegVecSaveAlloc()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/symbolic"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 )
 
 // Converts a U64 to a given base, the base should be given in field element
@@ -52,24 +53,11 @@ func BaseRecompose(r []field.Element, base *field.Element) (res field.Element) {
 // It composes chunks and returns slices
 func DecomposeSmall(r uint64, base int, nb int) (res []field.Element) {
 	// It will essentially be used for chunk to slice decomposition
-	res = make([]field.Element, 0, nb)
-	base64 := uint64(base)
-	curr := r
-	for curr > 0 {
-		limb := field.NewElement(curr % base64)
-		res = append(res, limb)
-		curr /= base64
-	}
-
-	if len(res) > nb {
-		utils.Panic("expected %v limbs, but got %v", nb, len(res))
+	digits := bitutil.Decompose(r, uint64(base), nb)
+	res = make([]field.Element, nb)
+	for i := range digits {
+		res[i].SetUint64(digits[i])
 	}
-
-	// Complete with zeroes
-	for len(res) < nb {
-		res = append(res, field.Zero())
-	}
-
 	return res
 }
 
@@ -0,0 +1,20 @@
+package keccak
+
+import (
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+)
+
+// Provider is implemented by the zkEVM modules, other than the
+// arithmetization, whose hashes are proven by the keccak module. The hashes
+// of a provider are stitched with the ones of the other providers and the
+// stitching is tied to the results of the keccak module by a projection.
+// Thus, the results a provider relies on cannot diverge from the ones proven
+// by the keccak module.
+type Provider interface {
+	// KeccakProviders returns the inputs and the results of the hashes.
+	KeccakProviders() []generic.GenericByteModule
+	// NumKeccakF returns the maximal number of keccakf permutations the
+	// hashes may require. It is added to the capacity of the keccak module,
+	// which is set for the hashes of the arithmetization by [Settings].
+	NumKeccakF() int
+}
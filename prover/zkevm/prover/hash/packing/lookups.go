@@ -21,8 +21,8 @@ func NewLookupTables(comp *wizard.CompiledIOP) lookUpTables {
 	res := lookUpTables{}
 	// table for powers of numbers (used for decomposition of clean limbs)
 	colNum, colPower2 := numToPower2(MAXNBYTE)
-	res.colNumber = comp.InsertPrecomputed(ifaces.ColIDf("LookUp_Num"), colNum)
-	res.colPowers = comp.InsertPrecomputed(ifaces.ColIDf("LookUp_Powers"), colPower2)
+	res.colNumber = comp.InsertPrecomputedTable(ifaces.ColIDf("LookUp_Num"), colNum)
+	res.colPowers = comp.InsertPrecomputedTable(ifaces.ColIDf("LookUp_Powers"), colPower2)
 
 	return res
 }
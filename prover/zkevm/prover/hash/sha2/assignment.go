@@ -8,6 +8,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/bitutil"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
 )
 
@@ -190,21 +191,11 @@ func sha2Compress(oldState [2]field.Element, block [16]field.Element) (newState
 		blockBytes    = [64]byte{}
 	)
 
-	for i := range oldState {
-		osI := oldState[i].Bytes()
-		copy(oldStateBytes[16*i:], osI[16:])
-	}
-
-	for i := range block {
-		bI := block[i].Bytes()
-		copy(blockBytes[4*i:], bI[32-4:])
-	}
+	copy(oldStateBytes[:], bitutil.LimbsToBytes(oldState[:], 16))
+	copy(blockBytes[:], bitutil.LimbsToBytes(block[:], 4))
 
 	newStateBytes := sha2.Compress(oldStateBytes, blockBytes)
-
-	for i := range newState {
-		newState[i].SetBytes(newStateBytes[16*i : 16*i+16])
-	}
+	copy(newState[:], bitutil.BytesToLimbs(newStateBytes[:], 16))
 
 	return newState
 }
@@ -140,9 +140,14 @@ func newSha2SingleProvider(comp *wizard.CompiledIOP, inp Sha2SingleProviderInput
 	return m
 }
 
-// It implements [wizard.ProverAction] for sha2.
+// It implements [wizard.ProverAction] for sha2. It is a no-op if the module
+// is disabled.
 func (m *Sha2SingleProvider) Run(run *wizard.ProverRuntime) {
 
+	if m == nil {
+		return
+	}
+
 	// assign ImportAndPad module
 	m.pa_importPad.Run(run)
 	// assign packing module
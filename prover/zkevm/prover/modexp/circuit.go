@@ -2,7 +2,6 @@ package modexp
 
 import (
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/evmprecompiles"
 	"github.com/consensys/gnark/std/math/bitslice"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
@@ -93,7 +92,27 @@ func (m *modexpCircuit) Define(api frontend.API) error {
 	return nil
 }
 
-// defined4096 implements the circuit logic for the case where
+// checkModexpInstance implements the circuit logic checking a single MODEXP
+// claim following the semantic of EIP-198:
+//
+//   - if the modulus is zero, the result is zero.
+//   - otherwise, the result is the canonical residue of base^exponent modulo
+//     the modulus. In particular, base^0 = 1 except for modulus = 1 where the
+//     result is zero.
+//
+// The modulus may be even. The reduction used by [emulated.Field.ModMul] does
+// not rely on the modulus being invertible modulo a power of two (as a
+// Montgomery reduction would) so there is no need to decompose the modulus
+// into its odd part and its power-of-two part and to recombine the results
+// via the CRT.
+//
+// The results of [emulated.Field.ModExp] are however not necessarily fully
+// reduced: for exponents 0 and 1 the base or 1 is returned as is and the
+// intermediate products are only reduced up to the width of the limbs. That
+// is why, the circuit does not compare the claimed result with it directly but
+// instead checks that (1) the claimed result is congruent to it and (2) that
+// the claimed result is strictly smaller than the modulus. The two conditions
+// uniquely determine the result.
 func checkModexpInstance[P emulated.FieldParams](api frontend.API, m *modexpCircuitInstance) {
 
 	var (
@@ -126,8 +145,41 @@ func checkModexpInstance[P emulated.FieldParams](api frontend.API, m *modexpCirc
 		exponent       = emApi.NewElement(exponentLimbs)
 		modulus        = emApi.NewElement(modulusLimbs)
 		resultExpected = emApi.NewElement(resultLimbs)
-		resultActual   = evmprecompiles.Expmod(api, base, exponent, modulus)
+		// When the modulus is zero, we run the computation with 1 as a dummy
+		// modulus. Since the result must be smaller than the modulus, this
+		// forces the claimed result to be zero.
+		isZeroMod    = emApi.IsZero(modulus)
+		safeModulus  = emApi.Select(isZeroMod, emApi.One(), modulus)
+		resultActual = emApi.ModExp(base, exponent, safeModulus)
 	)
 
-	emApi.AssertIsEqual(resultExpected, resultActual)
+	emApi.ModAssertIsEqual(resultExpected, resultActual, safeModulus)
+	assertBitsStrictlyLess(api, emApi.ToBits(resultExpected), emApi.ToBits(safeModulus))
+}
+
+// assertBitsStrictlyLess asserts that the integer represented by the bits `a`
+// is strictly smaller than the one represented by the bits `b`. The bits are
+// given in little-endian order and both slices must have the same length.
+func assertBitsStrictlyLess(api frontend.API, a, b []frontend.Variable) {
+
+	if len(a) != len(b) {
+		utils.Panic("the operands have different bit-lengths: %v != %v", len(a), len(b))
+	}
+
+	var (
+		// isLess and isEqual indicate whether the prefixes of `a` and `b`
+		// (starting from the most significant bit) are respectively smaller
+		// or equal.
+		isLess  frontend.Variable = 0
+		isEqual frontend.Variable = 1
+	)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		// a[i] < b[i] iff a[i] = 0 and b[i] = 1
+		bitIsLess := api.Mul(api.Sub(1, a[i]), b[i])
+		isLess = api.Add(isLess, api.Mul(isEqual, bitIsLess))
+		isEqual = api.Mul(isEqual, api.Sub(1, api.Xor(a[i], b[i])))
+	}
+
+	api.AssertIsEqual(isLess, 1)
 }
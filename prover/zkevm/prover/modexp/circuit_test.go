@@ -0,0 +1,124 @@
+package modexp
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+// modexpPrecompile is the go-ethereum implementation of the MODEXP precompile
+// used as a reference for the differential tests.
+var modexpPrecompile = vm.PrecompiledContractsCancun[common.BytesToAddress([]byte{5})]
+
+// gethModexp returns base^exp mod modulus as computed by go-ethereum with all
+// the operands encoded over `numBytes` bytes.
+func gethModexp(t *testing.T, base, exp, modulus *big.Int, numBytes int) *big.Int {
+
+	input := make([]byte, 0, 96+3*numBytes)
+	for range 3 {
+		input = append(input, common.LeftPadBytes(big.NewInt(int64(numBytes)).Bytes(), 32)...)
+	}
+
+	for _, x := range []*big.Int{base, exp, modulus} {
+		input = append(input, common.LeftPadBytes(x.Bytes(), numBytes)...)
+	}
+
+	res, err := modexpPrecompile.Run(input)
+	require.NoError(t, err)
+	return new(big.Int).SetBytes(res)
+}
+
+// assignInstance returns an assignment of a 256 bits modexp circuit with a
+// single instance.
+func assignInstance(base, exp, modulus, result *big.Int) *modexpCircuit {
+
+	var (
+		res  = allocateCircuit(1, smallModexpSize)
+		mask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), limbSizeBits), big.NewInt(1))
+	)
+
+	split := func(dst []frontend.Variable, x *big.Int) {
+		for i := range dst {
+			shift := uint(limbSizeBits * (len(dst) - i - 1))
+			dst[i] = new(big.Int).And(new(big.Int).Rsh(x, shift), mask)
+		}
+	}
+
+	split(res.Instances[0].Base, base)
+	split(res.Instances[0].Exponent, exp)
+	split(res.Instances[0].Modulus, modulus)
+	split(res.Instances[0].Result, result)
+	return res
+}
+
+func TestModexpCircuitEIP198(t *testing.T) {
+
+	var (
+		rng       = rand.New(rand.NewSource(982374))
+		bound     = new(big.Int).Lsh(big.NewInt(1), smallModexpSize)
+		randBig   = func() *big.Int { return new(big.Int).Rand(rng, bound) }
+		makeEven  = func(x *big.Int) *big.Int { return new(big.Int).SetBit(x, 0, 0) }
+		bigInt    = big.NewInt
+		pow2      = func(n uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), n) }
+		maxUint   = new(big.Int).Sub(bound, big.NewInt(1))
+		testCases = []struct {
+			name               string
+			base, exp, modulus *big.Int
+		}{
+			{name: "random", base: randBig(), exp: randBig(), modulus: randBig()},
+			{name: "random-even-modulus", base: randBig(), exp: randBig(), modulus: makeEven(randBig())},
+			{name: "power-of-two-modulus", base: randBig(), exp: randBig(), modulus: pow2(130)},
+			{name: "modulus-two", base: randBig(), exp: randBig(), modulus: bigInt(2)},
+			{name: "even-modulus-even-base", base: makeEven(randBig()), exp: randBig(), modulus: bigInt(12)},
+			{name: "zero-modulus", base: randBig(), exp: randBig(), modulus: bigInt(0)},
+			{name: "modulus-one", base: randBig(), exp: randBig(), modulus: bigInt(1)},
+			{name: "zero-exponent", base: randBig(), exp: bigInt(0), modulus: randBig()},
+			{name: "zero-exponent-modulus-one", base: randBig(), exp: bigInt(0), modulus: bigInt(1)},
+			{name: "zero-exponent-zero-base", base: bigInt(0), exp: bigInt(0), modulus: makeEven(randBig())},
+			{name: "zero-everything", base: bigInt(0), exp: bigInt(0), modulus: bigInt(0)},
+			{name: "exponent-one-large-base", base: maxUint, exp: bigInt(1), modulus: makeEven(randBig())},
+			{name: "zero-base", base: bigInt(0), exp: randBig(), modulus: randBig()},
+			{name: "max-operands", base: maxUint, exp: maxUint, modulus: maxUint},
+		}
+	)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			var (
+				result  = gethModexp(t, tc.base, tc.exp, tc.modulus, smallModexpSize/8)
+				circuit = allocateCircuit(1, smallModexpSize)
+				field   = ecc.BLS12_377.ScalarField()
+			)
+
+			// Sanity-check of the reference against the standard library. The
+			// standard library does not reduce when the modulus is zero.
+			if tc.modulus.Sign() != 0 {
+				require.Equal(t, new(big.Int).Exp(tc.base, tc.exp, tc.modulus).String(), result.String())
+			}
+
+			err := test.IsSolved(circuit, assignInstance(tc.base, tc.exp, tc.modulus, result), field)
+			require.NoError(t, err)
+
+			// A result that is congruent but not reduced must be rejected
+			if tc.modulus.Sign() != 0 {
+				notReduced := new(big.Int).Add(result, tc.modulus)
+				if notReduced.Cmp(bound) < 0 {
+					err = test.IsSolved(circuit, assignInstance(tc.base, tc.exp, tc.modulus, notReduced), field)
+					require.Error(t, err)
+				}
+			}
+
+			wrong := new(big.Int).Xor(result, big.NewInt(1))
+			err = test.IsSolved(circuit, assignInstance(tc.base, tc.exp, tc.modulus, wrong), field)
+			require.Error(t, err)
+		})
+	}
+}
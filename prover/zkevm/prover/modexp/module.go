@@ -4,6 +4,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
@@ -52,6 +53,15 @@ type Module struct {
 	// positions of limbs corresponding to public inputs of (respectely) the
 	// small and the large circuit.
 	ToSmallCirc ifaces.Column
+	// IsZeroLimb is a binary column indicating that the limb on the same row
+	// is zero. It is assigned by isZeroLimbCtx.
+	IsZeroLimb    ifaces.Column
+	isZeroLimbCtx wizard.ProverAction
+	// HighLimbsAreZero accumulates, over the rows of a modexp instance, the
+	// fact that all its limbs except the last two of each operand are zero.
+	// On the last row of an instance, it indicates whether all the operands
+	// of the instance fit on 256 bits.
+	HighLimbsAreZero ifaces.Column
 	// connection logic of the modexp circuit specialized for the small and
 	// large instances respectively
 	GnarkCircuitConnector256Bits, GnarkCircuitConnector4096Bits *plonk.Alignment
@@ -87,13 +97,23 @@ func newModule(comp *wizard.CompiledIOP, input Input) *Module {
 			IsLarge:                comp.InsertCommit(0, "MODEXP_IS_LARGE", size),
 			LsbIndicator:           comp.InsertPrecomputed("MODEXP_LSB_INDICATOR", lsbIndicatorValue(size)),
 			ToSmallCirc:            comp.InsertCommit(0, "MODEXP_TO_SMALL_CIRC", size),
+			HighLimbsAreZero:       comp.InsertCommit(0, "MODEXP_HIGH_LIMBS_ARE_ZERO", size),
 		}
 	)
 
+	// The rows past the capacity of the module only exist because the size is
+	// rounded up to a power of two and are always padding.
+	for _, col := range []ifaces.Column{mod.IsActive, mod.Limbs, mod.IsSmall, mod.IsLarge, mod.ToSmallCirc, mod.HighLimbsAreZero} {
+		comp.Columns.SetEffectiveSize(col.GetColID(), maxNbInstance*modexpNumRowsPerInstance)
+	}
+
+	mod.IsZeroLimb, mod.isZeroLimbCtx = dedicated.IsZero(comp, mod.Limbs)
+
 	mod.Input.setIsModexp(comp)
 
 	mod.csIsActive(comp)
 	mod.csIsSmallAndLarge(comp)
+	mod.csIsLargeImpliesLargeOperands(comp)
 	mod.csToCirc(comp)
 
 	projection.InsertProjection(
@@ -223,8 +243,7 @@ func (mod *Module) csIsSmallAndLarge(comp *wizard.CompiledIOP) {
 	// limbs 2..32 of the operands of the corresponding modexp must be zero
 	// (otherwise, they would represent numbers larger than 256 bits).
 	//
-	// The converse constraint does not exists in the 4096 case because it would
-	// not be wrong to supply
+	// The converse constraint is [MODEXP_IS_LARGE_IMPLIES_LARGE_OPERANDS].
 	//
 
 	comp.InsertGlobal(
@@ -238,6 +257,49 @@ func (mod *Module) csIsSmallAndLarge(comp *wizard.CompiledIOP) {
 	)
 }
 
+// csIsLargeImpliesLargeOperands ensures that only the instances having an
+// operand larger than 256 bits are routed to the 4096-bits circuit. Together
+// with MODEXP_IS_SMALL_IMPLIES_SMALL_OPERANDS, this makes the routing of the
+// instances fully determined by their operands so that the prover cannot
+// exhaust the few 4096-bits instances with small modexps.
+func (mod *Module) csIsLargeImpliesLargeOperands(comp *wizard.CompiledIOP) {
+
+	var (
+		isFirstRow = variables.NewPeriodicSample(modexpNumRowsPerInstance, 0)
+		isLastRow  = variables.NewPeriodicSample(modexpNumRowsPerInstance, modexpNumRowsPerInstance-1)
+		// isZeroHighLimb is 1 if the limb is zero or is one of the last two
+		// limbs of an operand, which are allowed to be non-zero in small
+		// instances.
+		isZeroHighLimb = sym.Add(
+			mod.LsbIndicator,
+			sym.Mul(sym.Sub(1, mod.LsbIndicator), mod.IsZeroLimb),
+		)
+	)
+
+	// HighLimbsAreZero is the running product of isZeroHighLimb, restarted
+	// at the first row of every instance.
+	comp.InsertGlobal(
+		0,
+		"MODEXP_HIGH_LIMBS_ARE_ZERO_WELL_FORMED",
+		sym.Sub(
+			mod.HighLimbsAreZero,
+			sym.Mul(
+				isZeroHighLimb,
+				sym.Add(
+					isFirstRow,
+					sym.Mul(sym.Sub(1, isFirstRow), column.Shift(mod.HighLimbsAreZero, -1)),
+				),
+			),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"MODEXP_IS_LARGE_IMPLIES_LARGE_OPERANDS",
+		sym.Mul(isLastRow, mod.IsLarge, mod.HighLimbsAreZero),
+	)
+}
+
 // csToCirc ensures the well-construction of ant.ToSmallCirc
 func (mod *Module) csToCirc(comp *wizard.CompiledIOP) {
 
@@ -10,30 +10,48 @@ import (
 // antichamberAssignment is a builder structure used to incrementally compute
 // the assignment of the column of the [Module] module.
 type antichamberAssignment struct {
-	isActive    *common.VectorBuilder
-	isSmall     *common.VectorBuilder
-	isLarge     *common.VectorBuilder
-	limbs       *common.VectorBuilder
-	toSmallCirc *common.VectorBuilder
+	isActive         *common.VectorBuilder
+	isSmall          *common.VectorBuilder
+	isLarge          *common.VectorBuilder
+	limbs            *common.VectorBuilder
+	toSmallCirc      *common.VectorBuilder
+	highLimbsAreZero *common.VectorBuilder
 }
 
-// Assign assigns the anti-chamber module
+// Assign assigns the anti-chamber module. It is a no-op if the module is
+// disabled.
 func (mod *Module) Assign(run *wizard.ProverRuntime) {
 
+	if mod == nil {
+		return
+	}
+
 	mod.Input.assignIsModexp(run)
 
 	var (
 		isModexp = mod.Input.isModExp.GetColAssignment(run).IntoRegVecSaveAlloc()
 		limbs    = mod.Input.Limbs.GetColAssignment(run).IntoRegVecSaveAlloc()
 		builder  = antichamberAssignment{
-			isActive:    common.NewVectorBuilder(mod.IsActive),
-			isSmall:     common.NewVectorBuilder(mod.IsSmall),
-			isLarge:     common.NewVectorBuilder(mod.IsLarge),
-			limbs:       common.NewVectorBuilder(mod.Limbs),
-			toSmallCirc: common.NewVectorBuilder(mod.ToSmallCirc),
+			isActive:         common.NewVectorBuilder(mod.IsActive),
+			isSmall:          common.NewVectorBuilder(mod.IsSmall),
+			isLarge:          common.NewVectorBuilder(mod.IsLarge),
+			limbs:            common.NewVectorBuilder(mod.Limbs),
+			toSmallCirc:      common.NewVectorBuilder(mod.ToSmallCirc),
+			highLimbsAreZero: common.NewVectorBuilder(mod.HighLimbsAreZero),
 		}
 	)
 
+	// The instances are counted upfront so that the traces exceeding the
+	// capacity of the circuits are rejected with an explicit error instead of
+	// failing in the middle of the assignment of the circuits.
+	nbSmall, nbLarge := CountInstances(isModexp, limbs)
+	if nbSmall > mod.MaxNb256BitsInstances {
+		utils.Panic("the traces contain %v modexp calls with 256-bits operands but the prover supports only %v of them (PRECOMPILE_MODEXP_EFFECTIVE_CALLS)", nbSmall, mod.MaxNb256BitsInstances)
+	}
+	if nbLarge > mod.MaxNb4096BitsInstances {
+		utils.Panic("the traces contain %v modexp calls with operands larger than 256 bits but the prover supports only %v of them (PRECOMPILE_MODEXP_LARGE_EFFECTIVE_CALLS)", nbLarge, mod.MaxNb4096BitsInstances)
+	}
+
 	for currPosition := 0; currPosition < len(limbs); {
 
 		if isModexp[currPosition].IsZero() {
@@ -47,16 +65,10 @@ func (mod *Module) Assign(run *wizard.ProverRuntime) {
 			utils.Panic("A new modexp is starting but there is not enough rows (currPosition=%v len(ecdata.Limb)=%v)", currPosition, len(limbs))
 		}
 
-		isLarge := false
-
-		// An instance is considered large if any of the operand has more than
-		// 2 16-bytes limbs.
-		for k := 0; k < modexpNumRowsPerInstance; k++ {
-			if k%32 < 30 && !limbs[currPosition+k].IsZero() {
-				isLarge = true
-				break
-			}
-		}
+		var (
+			isLarge          = isLargeInstance(limbs[currPosition : currPosition+modexpNumRowsPerInstance])
+			highLimbsAreZero = true
+		)
 
 		for k := 0; k < modexpNumRowsPerInstance; k++ {
 
@@ -70,6 +82,11 @@ func (mod *Module) Assign(run *wizard.ProverRuntime) {
 			} else {
 				builder.toSmallCirc.PushZero()
 			}
+
+			if k%32 < 30 && !limbs[currPosition+k].IsZero() {
+				highLimbsAreZero = false
+			}
+			builder.highLimbsAreZero.PushBoolean(highLimbsAreZero)
 		}
 
 		currPosition += modexpNumRowsPerInstance
@@ -80,6 +97,8 @@ func (mod *Module) Assign(run *wizard.ProverRuntime) {
 	builder.isLarge.PadAndAssign(run, field.Zero())
 	builder.limbs.PadAndAssign(run, field.Zero())
 	builder.toSmallCirc.PadAndAssign(run, field.Zero())
+	builder.highLimbsAreZero.PadAndAssign(run, field.One())
+	mod.isZeroLimbCtx.Run(run)
 
 	// It is possible to not declare the circuit (for testing purpose) in that
 	// case we skip the corresponding assignment part.
@@ -88,3 +107,41 @@ func (mod *Module) Assign(run *wizard.ProverRuntime) {
 		mod.GnarkCircuitConnector4096Bits.Assign(run)
 	}
 }
+
+// isLargeInstance returns true if the modexp instance whose limbs are given
+// must be routed to the 4096-bits circuit. An instance is considered large if
+// any of the operand has more than 2 16-bytes limbs.
+func isLargeInstance(limbs []field.Element) bool {
+	for k := range limbs {
+		if k%32 < 30 && !limbs[k].IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// CountInstances returns the number of modexp instances whose operands fit on
+// 256 bits and the number of the larger ones, given the IS_MODEXP indicator
+// and the LIMB column of the BLK_MDXP module. It is used to reject the traces
+// exceeding the capacity of the circuits before starting the proof.
+func CountInstances(isModexp, limbs []field.Element) (nbSmall, nbLarge int) {
+
+	for currPosition := 0; currPosition < len(limbs); {
+
+		if isModexp[currPosition].IsZero() {
+			currPosition++
+			continue
+		}
+
+		end := min(currPosition+modexpNumRowsPerInstance, len(limbs))
+		if isLargeInstance(limbs[currPosition:end]) {
+			nbLarge++
+		} else {
+			nbSmall++
+		}
+
+		currPosition = end
+	}
+
+	return nbSmall, nbLarge
+}
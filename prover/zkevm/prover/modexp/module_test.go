@@ -3,9 +3,14 @@ package modexp
 import (
 	"testing"
 
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestModExpAntichamber(t *testing.T) {
@@ -75,3 +80,68 @@ func TestModExpAntichamber(t *testing.T) {
 		})
 	}
 }
+
+func TestModExpEffectiveSize(t *testing.T) {
+
+	var mod *Module
+
+	cmp := wizard.Compile(func(build *wizard.Builder) {
+		inp := Input{
+			IsModExpBase:     build.RegisterCommit("IS_MODEXP_BASE", 512),
+			IsModExpExponent: build.RegisterCommit("IS_MODEXP_EXPONENT", 512),
+			IsModExpModulus:  build.RegisterCommit("IS_MODEXP_MODULUS", 512),
+			IsModExpResult:   build.RegisterCommit("IS_MODEXP_RESULT", 512),
+			Limbs:            build.RegisterCommit("LIMBS", 512),
+			Settings:         Settings{MaxNbInstance256: 1, MaxNbInstance4096: 2},
+		}
+		mod = newModule(build.CompiledIOP, inp)
+	}, dummy.Compile)
+
+	// 3 instances of 128 rows are rounded up to 512 rows
+	for _, col := range []ifaces.Column{mod.IsActive, mod.Limbs, mod.IsSmall, mod.IsLarge, mod.ToSmallCirc, mod.HighLimbsAreZero} {
+		require.Equal(t, 512, col.Size())
+		require.Equal(t, 3*modexpNumRowsPerInstance, cmp.Columns.EffectiveSize(col.GetColID()))
+	}
+
+	// The padding of an empty assignment is accepted by the runtime
+	proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+		for _, name := range []ifaces.ColID{"IS_MODEXP_BASE", "IS_MODEXP_EXPONENT", "IS_MODEXP_MODULUS", "IS_MODEXP_RESULT", "LIMBS"} {
+			run.AssignColumn(name, smartvectors.NewConstant(field.Zero(), 512))
+		}
+		mod.Assign(run)
+	})
+
+	require.NoError(t, wizard.Verify(cmp, proof))
+}
+
+func TestCountInstances(t *testing.T) {
+
+	testCases := []struct {
+		InputFName       string
+		NbSmall, NbLarge int
+	}{
+		{InputFName: "testdata/single_256_bits_input.csv", NbSmall: 1},
+		{InputFName: "testdata/single_4096_bits_input.csv", NbLarge: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.InputFName, func(t *testing.T) {
+
+			var (
+				ct       = csvtraces.MustOpenCsvFile(tc.InputFName)
+				isModexp = make([]field.Element, ct.Len())
+			)
+
+			for _, name := range []string{"IS_MODEXP_BASE", "IS_MODEXP_EXPONENT", "IS_MODEXP_MODULUS", "IS_MODEXP_RESULT"} {
+				col := ct.Get(name)
+				for i := range isModexp {
+					isModexp[i].Add(&isModexp[i], &col[i])
+				}
+			}
+
+			nbSmall, nbLarge := CountInstances(isModexp, ct.Get("LIMBS"))
+			assert.Equal(t, tc.NbSmall, nbSmall)
+			assert.Equal(t, tc.NbLarge, nbLarge)
+		})
+	}
+}
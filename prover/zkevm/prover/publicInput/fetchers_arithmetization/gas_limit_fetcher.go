@@ -0,0 +1,145 @@
+package fetchers_arithmetization
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/bigrange"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	arith "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/arith_struct"
+	util "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/utilities"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GasLimitFetcher is a struct used to fetch the gas limits of the blocks from the arithmetization's BlockDataCols
+type GasLimitFetcher struct {
+	// RelBlock is the relative block number, ranging from 1 to the total number of blocks
+	RelBlock ifaces.Column
+	// Data contains all the gas limits in the conflation, ordered by block
+	Data ifaces.Column
+	// filter on the GasLimitFetcher.Data column
+	FilterFetched ifaces.Column
+	// filter on the arithmetization's BlockDataCols
+	SelectorGasLimit ifaces.Column
+	// prover action to compute SelectorGasLimit
+	ComputeSelectorGasLimit wizard.ProverAction
+	// MinGasLimit and MaxGasLimit are the bounds enforced on the gas limit of
+	// every block. A MaxGasLimit of zero means that the gas limits are only
+	// required to fit on 64 bits.
+	MinGasLimit, MaxGasLimit uint64
+}
+
+// NewGasLimitFetcher returns a new GasLimitFetcher with initialized columns that are not constrained.
+// The gas limits are bounded by [params.MinGasLimit] and maxGasLimit.
+func NewGasLimitFetcher(comp *wizard.CompiledIOP, name string, bdc *arith.BlockDataCols, maxGasLimit uint64) GasLimitFetcher {
+	size := bdc.Ct.Size()
+	res := GasLimitFetcher{
+		RelBlock:      util.CreateCol(name, "REL_BLOCK", size, comp),
+		Data:          util.CreateCol(name, "DATA", size, comp),
+		FilterFetched: util.CreateCol(name, "FILTER_FETCHED", size, comp),
+		MinGasLimit:   params.MinGasLimit,
+		MaxGasLimit:   maxGasLimit,
+	}
+	return res
+}
+
+// DefineGasLimitFetcher specifies the constraints of the GasLimitFetcher with respect to the BlockDataCols
+func DefineGasLimitFetcher(comp *wizard.CompiledIOP, fetcher *GasLimitFetcher, name string, bdc *arith.BlockDataCols) {
+	gasLimitField := util.GetGasLimitField()
+	// constrain the fetcher.SelectorGasLimit column, which will be the filter for the arithmetization's BlockDataCols
+	fetcher.SelectorGasLimit, fetcher.ComputeSelectorGasLimit = dedicated.IsZero(
+		comp,
+		sym.Sub(
+			bdc.Inst,
+			gasLimitField, // check that the Inst field indicates a gas limit row
+		),
+	)
+
+	// require that the filter on fetched data is a binary column
+	util.MustBeBinary(comp, fetcher.FilterFetched)
+
+	// require that the filter on fetched gas limits only contains 1s followed by 0s
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("%s_FILTER_ON_FETCHED_CONSTRAINT_NO_0_TO_1", name),
+		sym.Sub(
+			fetcher.FilterFetched,
+			sym.Mul(
+				column.Shift(fetcher.FilterFetched, -1),
+				fetcher.FilterFetched),
+		),
+	)
+
+	// a projection query to check that the gas limit data is fetched correctly
+	projection.InsertProjection(comp,
+		ifaces.QueryIDf("%s_GAS_LIMIT_PROJECTION", name),
+		[]ifaces.Column{fetcher.RelBlock, fetcher.Data},
+		[]ifaces.Column{bdc.RelBlock, bdc.DataLo},
+		fetcher.FilterFetched,
+		fetcher.SelectorGasLimit, // filter lights up on the arithmetization's BlockDataCols rows that contain gas limit data
+	)
+
+	// the gas limits must be at least MinGasLimit
+	bigrange.BigRange(
+		comp,
+		sym.Mul(
+			fetcher.FilterFetched,
+			sym.Sub(fetcher.Data, fetcher.MinGasLimit),
+		),
+		4, 16,
+		fmt.Sprintf("%s_GAS_LIMIT_LOWER_BOUND", name),
+	)
+
+	// and at most MaxGasLimit, when it is set
+	if fetcher.MaxGasLimit > 0 {
+		bigrange.BigRange(
+			comp,
+			sym.Mul(
+				fetcher.FilterFetched,
+				sym.Sub(fetcher.MaxGasLimit, fetcher.Data),
+			),
+			4, 16,
+			fmt.Sprintf("%s_GAS_LIMIT_UPPER_BOUND", name),
+		)
+	}
+}
+
+// AssignGasLimitFetcher assigns the data in the GasLimitFetcher using data fetched from the BlockDataCols
+func AssignGasLimitFetcher(run *wizard.ProverRuntime, fetcher GasLimitFetcher, bdc *arith.BlockDataCols) {
+
+	// get the hardcoded gas limit flag
+	gasLimitField := util.GetGasLimitField()
+
+	// initialize empty fetched data and filter on the fetched data
+	size := bdc.Ct.Size()
+	relBlock := make([]field.Element, size)
+	data := make([]field.Element, size)
+	filterFetched := make([]field.Element, size)
+
+	// counter is used to populate filter.Data and will increment every time we find a new gas limit
+	counter := 0
+
+	for i := 0; i < size; i++ {
+		// inst is the flag that specifies the row type
+		inst := bdc.Inst.GetColAssignmentAt(run, i)
+		if inst.Equal(&gasLimitField) {
+			filterFetched[counter].SetOne()
+			relBlock[counter] = bdc.RelBlock.GetColAssignmentAt(run, i)
+			data[counter] = bdc.DataLo.GetColAssignmentAt(run, i)
+			counter++
+		}
+	}
+
+	// assign the fetcher columns
+	run.AssignColumn(fetcher.RelBlock.GetColID(), smartvectors.NewRegular(relBlock))
+	run.AssignColumn(fetcher.Data.GetColID(), smartvectors.NewRegular(data))
+	run.AssignColumn(fetcher.FilterFetched.GetColID(), smartvectors.NewRegular(filterFetched))
+	// assign the SelectorGasLimit using the ComputeSelectorGasLimit prover action
+	fetcher.ComputeSelectorGasLimit.Run(run)
+}
@@ -0,0 +1,66 @@
+package fetchers_arithmetization
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	arith "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/arith_struct"
+	util "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/utilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGasLimitFetcher tests the fetching and the bounds of the gas limits
+func TestGasLimitFetcher(t *testing.T) {
+
+	testCases := []struct {
+		Name        string
+		MaxGasLimit uint64
+		ShouldFail  bool
+	}{
+		{Name: "unbounded", MaxGasLimit: 0},
+		{Name: "within-bound", MaxGasLimit: 30_000_000},
+		{Name: "above-bound", MaxGasLimit: 29_999_999, ShouldFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// initialize sample block data from a mock test data CSV file
+			ctBlockData := util.InitializeCsv("../testdata/blockdata_mock.csv", t)
+			var (
+				bdc     *arith.BlockDataCols
+				fetcher GasLimitFetcher
+			)
+
+			cmp := wizard.Compile(func(b *wizard.Builder) {
+				// register sample arithmetization columns
+				bdc, _, _ = arith.DefineTestingArithModules(b, ctBlockData, nil, nil)
+				// create and constrain a new gas limit fetcher
+				fetcher = NewGasLimitFetcher(b.CompiledIOP, "GAS_LIMIT_FETCHER_FROM_ARITH", bdc, tc.MaxGasLimit)
+				DefineGasLimitFetcher(b.CompiledIOP, &fetcher, "GAS_LIMIT_FETCHER_FROM_ARITH", bdc)
+			}, dummy.Compile)
+
+			prove := func() wizard.Proof {
+				return wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+					// assign the CSV columns
+					arith.AssignTestingArithModules(run, ctBlockData, nil, nil)
+					// assign the gas limit fetcher
+					AssignGasLimitFetcher(run, fetcher, bdc)
+					// the mock test data has a gas limit of 30M in the first two blocks
+					assert.Equal(t, field.NewElement(30_000_000), fetcher.Data.GetColAssignmentAt(run, 1))
+					assert.Equal(t, field.Zero(), fetcher.FilterFetched.GetColAssignmentAt(run, 2))
+				})
+			}
+
+			if tc.ShouldFail {
+				require.Panics(t, func() { prove() })
+				return
+			}
+
+			require.NoError(t, wizard.Verify(cmp, prove()))
+		})
+	}
+}
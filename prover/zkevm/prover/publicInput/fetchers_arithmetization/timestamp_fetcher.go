@@ -1,13 +1,17 @@
 package fetchers_arithmetization
 
 import (
+	"fmt"
+
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/bigrange"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/variables"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	sym "github.com/consensys/linea-monorepo/prover/symbolic"
 	arith "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/arith_struct"
@@ -193,6 +197,31 @@ func DefineTimestampFetcher(comp *wizard.CompiledIOP, fetcher *TimestampFetcher,
 	// constrain the First/Last Block ID counters
 	ConstrainFirstAndLastBlockID(comp, fetcher, name, bdc)
 
+	// constrain the timestamps to be strictly increasing across the conflation
+	ConstrainTimestampsIncreasing(comp, fetcher, name)
+
+}
+
+// ConstrainTimestampsIncreasing requires the timestamps in fetcher.Data to be
+// strictly increasing, as expected by the L1 contracts. Every timestamp but the
+// first one is checked to be larger than the previous one by showing that the
+// difference minus one fits on 64 bits, which is where the timestamps live.
+func ConstrainTimestampsIncreasing(comp *wizard.CompiledIOP, fetcher *TimestampFetcher, name string) {
+	isFirstRow := variables.NewPeriodicSample(fetcher.Data.Size(), 0)
+	bigrange.BigRange(
+		comp,
+		sym.Mul(
+			fetcher.FilterFetched,
+			sym.Sub(1, isFirstRow),
+			sym.Sub(
+				fetcher.Data,
+				column.Shift(fetcher.Data, -1),
+				1,
+			),
+		),
+		4, 16,
+		fmt.Sprintf("%s_TIMESTAMPS_STRICTLY_INCREASING", name),
+	)
 }
 
 // AssignTimestampFetcher assigns the data in the TimestampFetcher using data fetched from the BlockDataCols
@@ -1,14 +1,17 @@
 package fetchers_arithmetization
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
 	arith "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/arith_struct"
 	util "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/utilities"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestTimestampFetcher tests the fetching of the timestamp data
@@ -43,3 +46,38 @@ func TestTimestampFetcher(t *testing.T) {
 	}
 	t.Log("proof succeeded")
 }
+
+// TestTimestampFetcherNotIncreasing tests that the timestamps of consecutive
+// blocks cannot be equal or decreasing
+func TestTimestampFetcherNotIncreasing(t *testing.T) {
+
+	for _, secondTimestamp := range []string{"0xb", "0xa"} {
+
+		ctBlockData, err := csvtraces.NewCsvTrace(strings.NewReader(
+			"REL_BLOCK,INST,CT,DATA_HI,DATA_LO,FIRST_BLOCK_NUMBER\n" +
+				"1,0x42,1,0,0xb,1500\n" +
+				"2,0x42,1,0," + secondTimestamp + ",1500\n",
+		))
+		require.NoError(t, err)
+
+		var (
+			bdc     *arith.BlockDataCols
+			fetcher TimestampFetcher
+		)
+
+		cmp := wizard.Compile(func(b *wizard.Builder) {
+			bdc, _, _ = arith.DefineTestingArithModules(b, ctBlockData, nil, nil)
+			fetcher = NewTimestampFetcher(b.CompiledIOP, "TIMESTAMP_FETCHER_FROM_ARITH", bdc)
+			DefineTimestampFetcher(b.CompiledIOP, &fetcher, "TIMESTAMP_FETCHER_FROM_ARITH", bdc)
+		}, dummy.Compile)
+
+		require.Panics(t, func() {
+			proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+				arith.AssignTestingArithModules(run, ctBlockData, nil, nil)
+				AssignTimestampFetcher(run, fetcher, bdc)
+			})
+			// not reached, the prover fails on the range check
+			_ = wizard.Verify(cmp, proof)
+		}, "timestamp %v after 0xb", secondTimestamp)
+	}
+}
@@ -23,6 +23,7 @@ type PublicInput struct {
 	Inputs             InputModules
 	Aux                AuxiliaryModules
 	TimestampFetcher   fetch.TimestampFetcher
+	GasLimitFetcher    fetch.GasLimitFetcher
 	RootHashFetcher    fetch.RootHashFetcher
 	RollingHashFetcher logs.RollingSelector
 	LogHasher          logs.LogHasher
@@ -48,6 +49,10 @@ type AuxiliaryModules struct {
 // Settings contains options for proving and verifying that the public inputs are computed properly.
 type Settings struct {
 	Name string
+	// MaxBlockGasLimit is the largest gas limit that a block of the
+	// conflation can have. Zero means that the gas limits are only bounded
+	// below, by the minimal gas limit of Ethereum.
+	MaxBlockGasLimit uint64
 }
 
 // InputModules groups several arithmetization modules needed to compute the public input.
@@ -135,6 +140,10 @@ func newPublicInput(
 	timestampFetcher := fetch.NewTimestampFetcher(comp, "PUBLIC_INPUT_TIMESTAMP_FETCHER", inp.BlockData)
 	fetch.DefineTimestampFetcher(comp, &timestampFetcher, "PUBLIC_INPUT_TIMESTAMP_FETCHER", inp.BlockData)
 
+	// Gas limits
+	gasLimitFetcher := fetch.NewGasLimitFetcher(comp, "PUBLIC_INPUT_GAS_LIMIT_FETCHER", inp.BlockData, settings.MaxBlockGasLimit)
+	fetch.DefineGasLimitFetcher(comp, &gasLimitFetcher, "PUBLIC_INPUT_GAS_LIMIT_FETCHER", inp.BlockData)
+
 	// Logs: Fetchers, Selectors and Hasher
 	fetchedL2L1 := logs.NewExtractedData(comp, inp.LogCols.Ct.Size(), "PUBLIC_INPUT_L2L1LOGS")
 	fetchedRollingMsg := logs.NewExtractedData(comp, inp.LogCols.Ct.Size(), "PUBLIC_INPUT_ROLLING_MSG")
@@ -206,6 +215,7 @@ func newPublicInput(
 
 	publicInput := PublicInput{
 		TimestampFetcher:   timestampFetcher,
+		GasLimitFetcher:    gasLimitFetcher,
 		RootHashFetcher:    rootHashFetcher,
 		RollingHashFetcher: rollingSelector,
 		LogHasher:          logHasherL2l1,
@@ -244,6 +254,8 @@ func (pub *PublicInput) Assign(run *wizard.ProverRuntime, l2BridgeAddress common
 
 	// assign the timestamp module
 	fetch.AssignTimestampFetcher(run, pub.TimestampFetcher, inp.BlockData)
+	// assign the gas limit module
+	fetch.AssignGasLimitFetcher(run, pub.GasLimitFetcher, inp.BlockData)
 	// assign the log modules
 	aux.logSelectors.Assign(run, l2BridgeAddress)
 	logs.AssignExtractedData(run, inp.LogCols, aux.logSelectors, aux.fetchedL2L1, logs.L2L1)
@@ -39,6 +39,11 @@ func GetTimestampField() field.Element {
 	return timestampField
 }
 
+// GetGasLimitField returns a field element that contains the hardcoded INST value for a gas limit
+func GetGasLimitField() field.Element {
+	return field.NewElement(uint64(vm.GASLIMIT))
+}
+
 // InitializeCsv is used to initialize a CsvTrace based on a path
 func InitializeCsv(csvPath string, t *testing.T) *csvtraces.CsvTrace {
 	f, err := os.Open(csvPath)
@@ -176,30 +176,7 @@ func (am *Module) Assign(
 	)
 
 	for _, trace := range traces {
-		switch t := trace.Underlying.(type) {
-		case statemanager.UpdateTraceST:
-			pushUpdateRows(builder, t)
-		case statemanager.UpdateTraceWS:
-			pushUpdateRows(builder, t)
-		case statemanager.InsertionTraceST:
-			pushInsertionRows(builder, t)
-		case statemanager.InsertionTraceWS:
-			pushInsertionRows(builder, t)
-		case statemanager.DeletionTraceST:
-			pushDeletionRows(builder, t)
-		case statemanager.DeletionTraceWS:
-			pushDeletionRows(builder, t)
-		case statemanager.ReadZeroTraceST:
-			pushReadZeroRows(builder, t)
-		case statemanager.ReadZeroTraceWS:
-			pushReadZeroRows(builder, t)
-		case statemanager.ReadNonZeroTraceST:
-			pushReadNonZeroRows(builder, t)
-		case statemanager.ReadNonZeroTraceWS:
-			pushReadNonZeroRows(builder, t)
-		default:
-			utils.Panic("Unexpected type : %T", t)
-		}
+		builder.pushTrace(trace)
 	}
 
 	// Sanity check on the size
@@ -295,6 +272,34 @@ func (am *Module) assignTopRootCols(
 	run.AssignColumn(cols.TopRoot.GetColID(), smartvectors.RightPadded(builder.topRoot, topRootPad, paddedSize))
 }
 
+// pushTrace appends the rows corresponding to a state-manager trace
+func (a *assignmentBuilder) pushTrace(trace statemanager.DecodedTrace) {
+	switch t := trace.Underlying.(type) {
+	case statemanager.UpdateTraceST:
+		pushUpdateRows(a, t)
+	case statemanager.UpdateTraceWS:
+		pushUpdateRows(a, t)
+	case statemanager.InsertionTraceST:
+		pushInsertionRows(a, t)
+	case statemanager.InsertionTraceWS:
+		pushInsertionRows(a, t)
+	case statemanager.DeletionTraceST:
+		pushDeletionRows(a, t)
+	case statemanager.DeletionTraceWS:
+		pushDeletionRows(a, t)
+	case statemanager.ReadZeroTraceST:
+		pushReadZeroRows(a, t)
+	case statemanager.ReadZeroTraceWS:
+		pushReadZeroRows(a, t)
+	case statemanager.ReadNonZeroTraceST:
+		pushReadNonZeroRows(a, t)
+	case statemanager.ReadNonZeroTraceWS:
+		pushReadNonZeroRows(a, t)
+	default:
+		utils.Panic("Unexpected type : %T", t)
+	}
+}
+
 // This is a low level function used by all the operations (INSERT, UPDATE, DELETE, READ-ZERO, and READ-NONZERO)
 // to update each row of various columns
 func (a *assignmentBuilder) pushRow(
@@ -0,0 +1,121 @@
+package accumulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shomeiFixturesDir contains the state transitions recorded from shomei, the
+// state manager running in production. Every JSON file of the directory is
+// replayed by [TestDifferentialShomei].
+const shomeiFixturesDir = "../../../../backend/execution/statemanager/testdata"
+
+// TestDifferentialShomei replays the recorded shomei state transitions through
+// the witness builder of the accumulator module and checks that the roots it
+// assigns are the ones computed by shomei at every step.
+func TestDifferentialShomei(t *testing.T) {
+
+	fnames, err := filepath.Glob(filepath.Join(shomeiFixturesDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, fnames, "no shomei fixtures found")
+
+	for _, fname := range fnames {
+		t.Run(filepath.Base(fname), func(t *testing.T) {
+			parsed := readShomeiFixture(t, fname)
+			assert.NoError(t, replayShomeiTraces(
+				parsed.Result.ZkParentStateRootHash,
+				parsed.Result.ZkStateMerkleProof,
+			))
+		})
+	}
+}
+
+// TestDifferentialShomeiDivergence checks that the replay reports a root that
+// does not match the recorded one.
+func TestDifferentialShomeiDivergence(t *testing.T) {
+
+	parsed := readShomeiFixture(t, filepath.Join(shomeiFixturesDir, "insert-2-accounts.json"))
+	parent := parsed.Result.ZkParentStateRootHash
+	parent[31] ^= 1
+
+	err := replayShomeiTraces(parent, parsed.Result.ZkStateMerkleProof)
+	assert.ErrorContains(t, err, "top root")
+}
+
+func readShomeiFixture(t *testing.T, fname string) statemanager.ShomeiOutput {
+	f := files.MustRead(fname)
+	defer f.Close()
+
+	var parsed statemanager.ShomeiOutput
+	require.NoErrorf(t, json.NewDecoder(f).Decode(&parsed), "failed to decode the JSON file (%v)", fname)
+	return parsed
+}
+
+// replayShomeiTraces pushes the traces of every block into a fresh assignment
+// builder and returns an error describing the first step at which the roots
+// of the builder diverge from the ones of shomei:
+//
+//   - every row must open its leaf against its root, i.e. the leaf hashing and
+//     the Merkle semantics of the prover agree with the ones of shomei;
+//   - the top root of a trie before a trace must be its top root after the
+//     previous trace on the same trie, across the blocks;
+//   - the first top root of the world state must be the parent state root
+//     hash of the fixture.
+func replayShomeiTraces(parent types.Bytes32, blocks [][]statemanager.DecodedTrace) error {
+
+	var parentFr field.Element
+	if err := parentFr.SetBytesCanonical(parent[:]); err != nil {
+		return fmt.Errorf("parent state root hash: %w", err)
+	}
+
+	// topRoots stores the last top root seen for each trie, indexed by the
+	// location of the trie.
+	topRoots := map[string]field.Element{statemanager.WS_LOCATION: parentFr}
+
+	for blockID, traces := range blocks {
+
+		// A trace takes at most 6 rows, see [pushInsertionRows]
+		builder := newAssignmentBuilder(Settings{MaxNumProofs: 6 * len(traces), MerkleTreeDepth: 40})
+
+		for traceID, trace := range traces {
+
+			start := len(builder.roots)
+			builder.pushTrace(trace)
+			stop := len(builder.roots)
+
+			for row := start; row < stop; row++ {
+				var (
+					leaf     = types.Bytes32(builder.leaves[row].Bytes())
+					root     = types.Bytes32(builder.roots[row].Bytes())
+					computed = computeRoot(leaf, builder.proofs[row])
+				)
+				if computed != root {
+					return fmt.Errorf(
+						"block %v, trace %v (%T, location %v), row %v: the leaf opens to root %v but shomei gives %v",
+						blockID, traceID, trace.Underlying, trace.Location, row-start, computed.Hex(), root.Hex(),
+					)
+				}
+			}
+
+			if prev, ok := topRoots[trace.Location]; ok && prev != builder.topRoot[start] {
+				return fmt.Errorf(
+					"block %v, trace %v (%T, location %v): the top root before the trace is %v but the previous step left %v",
+					blockID, traceID, trace.Underlying, trace.Location, builder.topRoot[start].Text(16), prev.Text(16),
+				)
+			}
+
+			topRoots[trace.Location] = builder.topRoot[stop-1]
+		}
+	}
+
+	return nil
+}
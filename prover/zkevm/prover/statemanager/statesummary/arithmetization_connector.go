@@ -15,8 +15,9 @@ import (
 // checking consistency between the StateSummary and the rest of the
 // arithmetization.
 type arithmetizationLink struct {
-	Acp, Scp    HubColumnSet
-	scpSelector scpSelector
+	Acp, Scp               HubColumnSet
+	scpSelector            scpSelector
+	storageReadConsistency *storageReadConsistency
 }
 
 // ConnectToHub generates all the constraints attesting that the state-summary
@@ -24,9 +25,10 @@ type arithmetizationLink struct {
 func (ss *Module) ConnectToHub(comp *wizard.CompiledIOP, acp, scp HubColumnSet) {
 
 	al := &arithmetizationLink{
-		Acp:         acp,
-		Scp:         scp,
-		scpSelector: newScpSelector(comp, scp),
+		Acp:                    acp,
+		Scp:                    scp,
+		scpSelector:            newScpSelector(comp, scp),
+		storageReadConsistency: storageReadConsistencyDefine(comp, scp),
 	}
 
 	storageIntegrationDefineInitial(comp, *ss, scp, al.scpSelector)
@@ -64,6 +66,8 @@ func (ss *Module) assignArithmetizationLink(run *wizard.ProverRuntime) {
 		ss.arithmetizationLink.scpSelector.ComputeSelectorMaxDeplBlock,
 	})
 
+	storageReadConsistencyAssign(run, ss.arithmetizationLink.Scp, ss.arithmetizationLink.storageReadConsistency)
+
 }
 
 /*
@@ -73,6 +73,90 @@ func TestIntegrationConnector(t *testing.T) {
 
 }
 
+// TestIntegrationConnectorStorageReadConsistency checks that the connector
+// rejects an SCP where an access to a slot does not see the value left by the
+// previous access to the same slot, in the same block.
+func TestIntegrationConnectorStorageReadConsistency(t *testing.T) {
+
+	initialBlockNo := 0
+	tContext := common.InitializeContext(initialBlockNo)
+	nbTampered := 0
+
+	for i, tCase := range tContext.TestCases {
+
+		var (
+			initState    = tContext.State
+			shomeiState  = mock.InitShomeiState(initState)
+			stateLogs    = tCase.StateLogsGens(initState)
+			shomeiTraces = mock.StateLogsToShomeiTraces(shomeiState, stateLogs)
+			stitcher     mock.Stitcher
+		)
+
+		stitcher.Initialize(initialBlockNo, initState)
+		for index := range stateLogs {
+			for _, frame := range stateLogs[index] {
+				stitcher.AddFrame(frame)
+			}
+		}
+		acpVectors := stitcher.Finalize(mock.GENERATE_ACP_SAMPLE)
+		scpVectors := stitcher.Finalize(mock.GENERATE_SCP_SAMPLE)
+
+		// Look for a repeated access to a slot within a block and change the
+		// value it sees. The next value is left untouched so that the final
+		// value of the slot remains the same.
+		tampered := false
+		for row := 1; row < len(scpVectors.ValueLOCurr) && !tampered; row++ {
+			if scpVectors.PeekAtStorage[row].IsOne() &&
+				scpVectors.PeekAtStorage[row-1].IsOne() &&
+				scpVectors.FirstKOCBlock[row].IsZero() &&
+				scpVectors.DeploymentNumber[row].Equal(&scpVectors.DeploymentNumber[row-1]) {
+				one := field.One()
+				scpVectors.ValueLOCurr[row].Add(&scpVectors.ValueLOCurr[row], &one)
+				tampered = true
+			}
+		}
+
+		if !tampered {
+			continue
+		}
+		nbTampered++
+
+		t.Run(fmt.Sprintf("test-case-%v", i), func(t *testing.T) {
+
+			t.Logf("Test case explainer: %v", tCase.Explainer)
+
+			var (
+				ss       Module
+				acp, scp HubColumnSet
+			)
+
+			define := func(b *wizard.Builder) {
+				acp = defineStateManagerColumns(b.CompiledIOP, mock.GENERATE_ACP_SAMPLE, acpVectors.Size())
+				scp = defineStateManagerColumns(b.CompiledIOP, mock.GENERATE_SCP_SAMPLE, scpVectors.Size())
+				ss = NewModule(b.CompiledIOP, 1<<6)
+				ss.ConnectToHub(b.CompiledIOP, acp, scp)
+			}
+
+			prove := func(run *wizard.ProverRuntime) {
+				acp.assignForTest(run, acpVectors)
+				scp.assignForTest(run, scpVectors)
+				ss.Assign(run, shomeiTraces)
+			}
+
+			comp := wizard.Compile(define, dummy.Compile)
+			proof := wizard.Prove(comp, prove)
+
+			if err := wizard.Verify(comp, proof); err == nil {
+				t.Fatalf("verification passed with an inconsistent storage read")
+			}
+		})
+	}
+
+	if nbTampered == 0 {
+		t.Fatalf("no test case has repeated accesses to a storage slot within a block")
+	}
+}
+
 /*
 defineStateManagerColumns is a function used for testing which assigns data from the arithmetization mock
 in order to create a test sample of HUB columns, either ACP or SCP
@@ -0,0 +1,130 @@
+package statesummary
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+)
+
+/*
+storageReadConsistency contains the columns used to check that, within a block,
+the consecutive accesses to the same storage slot of the SCP are consistent with
+each other: the value seen by an access is the value left by the previous one.
+As the SCP is sorted by the hub's counters within a slot, this binds repeated
+reads of a slot to identical values unless a write occurred in between. The
+check is restricted to the accesses sharing the same deployment number as the
+storage is wiped when the account is redeployed.
+*/
+type storageReadConsistency struct {
+	// SameDeployment is 1 when the deployment number of the row is the same as
+	// the one of the previous row.
+	SameDeployment        ifaces.Column
+	ComputeSameDeployment wizard.ProverAction
+	// Filter is 1 when the row and the previous one are accesses to the same
+	// slot, in the same block and for the same deployment.
+	Filter ifaces.Column
+}
+
+/*
+storageReadConsistencyDefine defines the constraints enforcing that the current
+value of a storage access of the SCP equals the next value of the previous
+access to the same slot, in the same block and for the same deployment number.
+It returns nil if the SCP has a single row, as there is nothing to compare.
+*/
+func storageReadConsistencyDefine(comp *wizard.CompiledIOP, smc HubColumnSet) *storageReadConsistency {
+
+	if smc.AddressHI.Size() < 2 {
+		return nil
+	}
+
+	sameDeployment, computeSameDeployment := dedicated.IsZero(
+		comp,
+		sym.Sub(smc.DeploymentNumber, column.Shift(smc.DeploymentNumber, -1)),
+	)
+
+	res := &storageReadConsistency{
+		SameDeployment:        sameDeployment,
+		ComputeSameDeployment: computeSameDeployment,
+		Filter: comp.InsertCommit(0,
+			"FILTER_CONNECTOR_SUMMARY_ARITHMETIZATION_STORAGE_READ_CONSISTENCY",
+			smc.AddressHI.Size(),
+		),
+	}
+
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("CONSTRAINT_FILTER_CONNECTOR_SUMMARY_ARITHMETIZATION_STORAGE_READ_CONSISTENCY"),
+		sym.Sub(
+			res.Filter,
+			sym.Mul(
+				smc.PeekAtStorage,
+				column.Shift(smc.PeekAtStorage, -1),
+				sym.Sub(1, smc.FirstKOCBlock),
+				res.SameDeployment,
+			),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("CONSTRAINT_CONNECTOR_SUMMARY_ARITHMETIZATION_STORAGE_READ_CONSISTENCY_HI"),
+		sym.Mul(
+			res.Filter,
+			sym.Sub(smc.ValueHICurr, column.Shift(smc.ValueHINext, -1)),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("CONSTRAINT_CONNECTOR_SUMMARY_ARITHMETIZATION_STORAGE_READ_CONSISTENCY_LO"),
+		sym.Mul(
+			res.Filter,
+			sym.Sub(smc.ValueLOCurr, column.Shift(smc.ValueLONext, -1)),
+		),
+	)
+
+	return res
+}
+
+/*
+storageReadConsistencyAssign assigns the columns of the read consistency
+constraints, if any.
+*/
+func storageReadConsistencyAssign(run *wizard.ProverRuntime, smc HubColumnSet, rc *storageReadConsistency) {
+
+	if rc == nil {
+		return
+	}
+
+	rc.ComputeSameDeployment.Run(run)
+
+	var (
+		size           = smc.AddressHI.Size()
+		peekAtStorage  = smc.PeekAtStorage.GetColAssignment(run)
+		firstKOCBlock  = smc.FirstKOCBlock.GetColAssignment(run)
+		sameDeployment = rc.SameDeployment.GetColAssignment(run)
+		filter         = make([]field.Element, size)
+	)
+
+	// The first row has no previous access, the constraint defining the
+	// filter is cancelled there.
+	for index := 1; index < size; index++ {
+
+		var (
+			peek     = peekAtStorage.Get(index)
+			prevPeek = peekAtStorage.Get(index - 1)
+			first    = firstKOCBlock.Get(index)
+			sameDepl = sameDeployment.Get(index)
+		)
+
+		if peek.IsOne() && prevPeek.IsOne() && first.IsZero() && sameDepl.IsOne() {
+			filter[index].SetOne()
+		}
+	}
+
+	run.AssignColumn(rc.Filter.GetColID(), smartvectors.NewRegular(filter))
+}
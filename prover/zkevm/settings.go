@@ -1,14 +1,10 @@
 package zkevm
 
 import (
+	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 )
@@ -19,15 +15,15 @@ type compilationSuite = []func(*wizard.CompiledIOP)
 
 // List the options set to initialize the zkEVM
 type Settings struct {
-	Keccak           keccak.Settings
-	Statemanager     statemanager.Settings
-	Arithmetization  arithmetization.Settings
-	Ecdsa            ecdsa.Settings
-	Modexp           modexp.Settings
-	Ecadd, Ecmul     ecarith.Limits
-	Ecpair           ecpair.Limits
-	Sha2             sha2.Settings
-	PublicInput      publicInput.Settings
+	Keccak          keccak.Settings
+	Statemanager    statemanager.Settings
+	Arithmetization arithmetization.Settings
+	PublicInput     publicInput.Settings
+	// PrecompileLimits are the limits from which the registered precompile
+	// modules derive their settings, see [PrecompileModule.SetLimits]. If
+	// nil, the settings are derived from zero limits, i.e. the modules are
+	// defined without any circuit instance.
+	PrecompileLimits *config.TracesLimits
 	CompilationSuite compilationSuite
 	Metadata         wizard.VersionMetadata
 }
@@ -1,16 +1,13 @@
 package zkevm
 
 import (
+	"fmt"
+
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/serialization"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
-	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 )
@@ -27,31 +24,18 @@ type ZkEvm struct {
 	// PublicInput gives access to the public inputs of the wizard-IOP and is
 	// used to access them to define the outer-circuit.
 	PublicInput *publicInput.PublicInput
-	// ecdsa is the module responsible for verifying the ecdsa tx signatures and
-	// ecrecover
-	ecdsa *ecdsa.EcdsaZkEvm
-
-	// modexp is the module responsible for proving the calls to the modexp
-	// precompile
-	modexp *modexp.Module
-	// deactivated pending the resolution of: https://github.com/Consensys/linea-tracer/issues/954
-	//
-	// ecadd is the module responsible for proving the calls to the ecadd
-	// precompile
-	// ecadd *ecarith.EcAdd
-	// ecmul is the module responsible for proving the calls to the ecmul
-	// precompile
-	ecmul *ecarith.EcMul
-	// ecpair is the module responsible for the proving the calls the ecpairing
-	// precompile
-	ecpair *ecpair.ECPair
-	// sha2 is the module responsible for doing the computation of the sha2
-	// precompile.
-	sha2 *sha2.Sha2SingleProvider
+	// precompiles are the precompile modules of the zkEVM in the order of
+	// registration. The modules disabled in the limits are left out, the
+	// arithmetization constrains their calls away.
+	precompiles []PrecompileModule
 
 	// Contains the actual wizard-IOP compiled object. This object is called to
 	// generate the inner-proof.
 	WizardIOP *wizard.CompiledIOP
+
+	// moduleUsage is the usage of the modules of the arithmetization as
+	// recorded by the last run of the prover.
+	moduleUsage []arithmetization.ModuleUsage
 }
 
 // NewZkEVM instantiates a new ZkEvm instance. The function returns a fully
@@ -60,26 +44,58 @@ type ZkEvm struct {
 //
 // The function can take a bit of time to complete. It will populate the zkEVM
 // struct and needs to be called before running the prover of the inner-proof.
+// It returns an error if the settings of the precompile modules cannot be
+// derived from [Settings.PrecompileLimits].
 func NewZkEVM(
 	settings Settings, // Settings for the zkEVM
-) *ZkEvm {
+) (*ZkEvm, error) {
+
+	precompiles, err := newPrecompiles(&settings)
+	if err != nil {
+		return nil, err
+	}
 
 	var (
 		res    *ZkEvm
 		define = func(b *wizard.Builder) {
-			res = newZkEVM(b, &settings)
+			res = newZkEVM(b, &settings, precompiles)
 		}
 		wizardIOP = wizard.Compile(define, settings.CompilationSuite...).BootstrapFiatShamir(settings.Metadata, serialization.SerializeCompiledIOP)
 	)
 
 	res.WizardIOP = wizardIOP
-	return res
+	return res, nil
+}
+
+// newPrecompiles instantiates the registered precompile modules enabled in
+// the limits of the arithmetization and derives their settings from the
+// precompile limits. Without precompile limits, the settings are derived
+// from zero limits so that they are validated all the same.
+func newPrecompiles(s *Settings) ([]PrecompileModule, error) {
+
+	limits := s.PrecompileLimits
+	if limits == nil {
+		limits = &config.TracesLimits{}
+	}
+
+	var res []PrecompileModule
+	for _, newModule := range precompileRegistry {
+		m := newModule()
+		if !m.Enabled(s.Arithmetization.Limits) {
+			continue
+		}
+		if err := m.SetLimits(limits); err != nil {
+			return nil, fmt.Errorf("could not set the limits of the %v module: %w", m.Name(), err)
+		}
+		res = append(res, m)
+	}
+	return res, nil
 }
 
 // Prove assigns and runs the inner-prover of the zkEVM and then, it returns the
-// inner-proof
-func (z *ZkEvm) ProveInner(input *Witness) wizard.Proof {
-	return wizard.Prove(z.WizardIOP, z.prove(input))
+// inner-proof. The options are passed to [wizard.Prove].
+func (z *ZkEvm) ProveInner(input *Witness, opts ...wizard.ProveOption) wizard.Proof {
+	return wizard.Prove(z.WizardIOP, z.prove(input), opts...)
 }
 
 // Verify verifies the inner-proof of the zkEVM
@@ -91,38 +107,41 @@ func (z *ZkEvm) VerifyInner(proof wizard.Proof) error {
 // unexported and should not be exported. The user should instead use the
 // "NewZkEvm" function. This function is meant to be passed as a closure to the
 // wizard.Compile function. Thus, this is an internal.
-func newZkEVM(b *wizard.Builder, s *Settings) *ZkEvm {
+func newZkEVM(b *wizard.Builder, s *Settings, precompiles []PrecompileModule) *ZkEvm {
 
 	var (
-		comp         = b.CompiledIOP
-		arith        = arithmetization.NewArithmetization(b, s.Arithmetization)
-		ecdsa        = ecdsa.NewEcdsaZkEvm(comp, &s.Ecdsa)
-		stateManager = statemanager.NewStateManagerNoHub(comp, s.Statemanager)
-		keccak       = keccak.NewKeccakZkEVM(comp, s.Keccak, ecdsa.GetProviders())
-		modexp       = modexp.NewModuleZkEvm(comp, s.Modexp)
-		// deactivated pending the resolution of: https://github.com/Consensys/linea-tracer/issues/954
-		//
-		// ecadd        = ecarith.NewEcAddZkEvm(comp, &s.Ecadd)
-		ecmul       = ecarith.NewEcMulZkEvm(comp, &s.Ecmul)
-		ecpair      = ecpair.NewECPairZkEvm(comp, &s.Ecpair)
-		sha2        = sha2.NewSha2ZkEvm(comp, s.Sha2)
-		publicInput = publicInput.NewPublicInputZkEVM(comp, &s.PublicInput, &stateManager.StateSummary)
+		comp  = b.CompiledIOP
+		arith = arithmetization.NewArithmetization(b, s.Arithmetization)
+		res   = &ZkEvm{
+			arithmetization: arith,
+			precompiles:     precompiles,
+		}
 	)
 
-	return &ZkEvm{
-		arithmetization: arith,
-		ecdsa:           ecdsa,
-		stateManager:    stateManager,
-		keccak:          keccak,
-		modexp:          modexp,
-		// deactivated pending the resolution of: https://github.com/Consensys/linea-tracer/issues/954
-		//
-		// ecadd:           ecadd,
-		ecmul:       ecmul,
-		ecpair:      ecpair,
-		sha2:        sha2,
-		PublicInput: &publicInput,
+	// The modules whose hashes are proven by the keccak module are declared
+	// before it and the other ones after. The declaration order of the
+	// modules is kept as is since it shapes the compiled IOP.
+	var keccakProviders []keccak.Provider
+	for _, m := range res.precompiles {
+		if p, ok := m.(keccakProvider); ok {
+			m.Define(comp)
+			keccakProviders = append(keccakProviders, p)
+		}
 	}
+
+	res.stateManager = statemanager.NewStateManagerNoHub(comp, s.Statemanager)
+	res.keccak = keccak.NewKeccakZkEVM(comp, s.Keccak, keccakProviders)
+
+	for _, m := range res.precompiles {
+		if _, ok := m.(keccakProvider); !ok {
+			m.Define(comp)
+		}
+	}
+
+	publicInput := publicInput.NewPublicInputZkEVM(comp, &s.PublicInput, &res.stateManager.StateSummary)
+	res.PublicInput = &publicInput
+
+	return res
 }
 
 // Returns a prover function for the zkEVM module. The resulting function is
@@ -133,23 +152,35 @@ func (z *ZkEvm) prove(input *Witness) (prover wizard.ProverStep) {
 		// Assigns the arithmetization module. From Corset. Must be done first
 		// because the following modules use the content of these columns to
 		// assign themselves.
-		z.arithmetization.Assign(run, input.ExecTracesFPath)
+		z.moduleUsage = z.arithmetization.Assign(run, input.ExecTracesFPath)
+
+		for _, m := range z.precompiles {
+			if _, ok := m.(keccakProvider); ok {
+				m.Assign(run, input)
+			}
+		}
 
 		// Assign the state-manager module
-		z.ecdsa.Assign(run, input.TxSignatureGetter, len(input.TxSignatures))
 		z.stateManager.Assign(run, input.SMTraces)
 		z.keccak.Run(run)
-		z.modexp.Assign(run)
-		// deactivated pending the resolution of: https://github.com/Consensys/linea-tracer/issues/954
-		//
-		// z.ecadd.Assign(run)
-		z.ecmul.Assign(run)
-		z.ecpair.Assign(run)
-		z.sha2.Run(run)
+
+		for _, m := range z.precompiles {
+			if _, ok := m.(keccakProvider); !ok {
+				m.Assign(run, input)
+			}
+		}
+
 		z.PublicInput.Assign(run, input.L2BridgeAddress)
 	}
 }
 
+// ModuleUsage returns the number of rows used by every module of the
+// arithmetization against its limit, as recorded by the last call to
+// [ZkEvm.ProveInner]. It returns nil if the prover has not been run.
+func (z *ZkEvm) ModuleUsage() []arithmetization.ModuleUsage {
+	return z.moduleUsage
+}
+
 // Limits returns the configuration limits used to instantiate the current
 // zk-EVM.
 func (z *ZkEvm) Limits() *config.TracesLimits {